@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var conntrackCmd = &cobra.Command{
+	Use:   "conntrack",
+	Short: "Inspect and flush tracked connections",
+	Long:  `Commands to inspect and flush the connections tracked by the userspace firewall.`,
+}
+
+var conntrackListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List tracked connections",
+	Example: `
+  netbird conntrack list
+  netbird conntrack list --ip 100.64.0.10
+  netbird conntrack list -p tcp --port 443`,
+	Args: cobra.NoArgs,
+	RunE: listConntrack,
+}
+
+var conntrackFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Flush tracked connections",
+	Long: `Removes tracked connections matching the filter, all connections are removed if no filter is given.
+Packets of flushed connections have to pass the firewall rules again, which is useful after a policy change.`,
+	Example: `
+  netbird conntrack flush
+  netbird conntrack flush --ip 100.64.0.10 --port 22`,
+	Args: cobra.NoArgs,
+	RunE: flushConntrack,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{conntrackListCmd, conntrackFlushCmd} {
+		c.Flags().String("ip", "", "Only match connections with this source or destination IP")
+		c.Flags().Uint16("port", 0, "Only match connections with this source or destination port")
+		c.Flags().StringP("protocol", "p", "", "Only match connections of this protocol (tcp/udp/icmp)")
+	}
+}
+
+func getConntrackFilter(cmd *cobra.Command) (*proto.ConntrackFilter, error) {
+	ip, _ := cmd.Flags().GetString("ip")
+	port, err := cmd.Flags().GetUint16("port")
+	if err != nil {
+		return nil, fmt.Errorf("invalid port: %v", err)
+	}
+
+	protocol, _ := cmd.Flags().GetString("protocol")
+	protocol = strings.ToLower(protocol)
+	if protocol != "" && protocol != "tcp" && protocol != "udp" && protocol != "icmp" {
+		return nil, fmt.Errorf("invalid protocol: use tcp/udp/icmp")
+	}
+
+	return &proto.ConntrackFilter{
+		Ip:       ip,
+		Port:     uint32(port),
+		Protocol: protocol,
+	}, nil
+}
+
+func listConntrack(cmd *cobra.Command, _ []string) error {
+	filter, err := getConntrackFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListConntrack(cmd.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to list conntrack entries: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetEntries()) == 0 {
+		cmd.Println("No tracked connections.")
+		return nil
+	}
+
+	printConntrackEntries(cmd, resp.GetEntries())
+	return nil
+}
+
+func flushConntrack(cmd *cobra.Command, _ []string) error {
+	filter, err := getConntrackFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.FlushConntrack(cmd.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to flush conntrack entries: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Flushed %d tracked connections\n", resp.GetFlushed())
+	return nil
+}
+
+func printConntrackEntries(cmd *cobra.Command, entries []*proto.ConntrackEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].GetProtocol() != entries[j].GetProtocol() {
+			return entries[i].GetProtocol() < entries[j].GetProtocol()
+		}
+		return entries[i].GetLastSeen().AsTime().After(entries[j].GetLastSeen().AsTime())
+	})
+
+	cmd.Printf("Tracked connections: %d\n\n", len(entries))
+
+	for _, e := range entries {
+		var conn string
+		if e.GetProtocol() == "ICMP" {
+			conn = fmt.Sprintf("%s -> %s (type %d code %d id %d)", e.GetSourceIp(), e.GetDestinationIp(), e.GetIcmpType(), e.GetIcmpCode(), e.GetIcmpId())
+		} else {
+			conn = fmt.Sprintf("%s:%d -> %s:%d", e.GetSourceIp(), e.GetSourcePort(), e.GetDestinationIp(), e.GetDestinationPort())
+		}
+
+		state := e.GetState()
+		if state == "" {
+			state = "-"
+		}
+
+		ruleID := e.GetRuleId()
+		if ruleID == "" {
+			ruleID = "-"
+		}
+
+		cmd.Printf("%s %s %s\n", e.GetProtocol(), e.GetDirection(), conn)
+		cmd.Printf("  State: %s, Rule: %s\n", state, ruleID)
+		cmd.Printf("  Last seen: %s ago, Timeout: %s, Expires in: %s\n",
+			time.Since(e.GetLastSeen().AsTime()).Round(time.Second),
+			e.GetTimeout().AsDuration(),
+			e.GetExpiresIn().AsDuration().Round(time.Second),
+		)
+		cmd.Printf("  Tx: %d packets / %d bytes, Rx: %d packets / %d bytes\n", e.GetPacketsTx(), e.GetBytesTx(), e.GetPacketsRx(), e.GetBytesRx())
+	}
+}
@@ -85,9 +85,10 @@ func debugBundle(cmd *cobra.Command, _ []string) error {
 
 	client := proto.NewDaemonServiceClient(conn)
 	resp, err := client.DebugBundle(cmd.Context(), &proto.DebugBundleRequest{
-		Anonymize:  anonymizeFlag,
-		Status:     getStatusOutput(cmd, anonymizeFlag),
-		SystemInfo: debugSystemInfoFlag,
+		Anonymize:   anonymizeFlag,
+		Status:      getStatusOutput(cmd, anonymizeFlag),
+		SystemInfo:  debugSystemInfoFlag,
+		Diagnostics: getDiagnosticsOutput(cmd, client, debugDiagnosePeers, anonymizeFlag),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to bundle debug: %v", status.Convert(err).Message())
@@ -210,9 +211,10 @@ func runForDuration(cmd *cobra.Command, args []string) error {
 	statusOutput = fmt.Sprintf("%s\n%s\n%s", statusOutput, headerPreDown, getStatusOutput(cmd, anonymizeFlag))
 
 	resp, err := client.DebugBundle(cmd.Context(), &proto.DebugBundleRequest{
-		Anonymize:  anonymizeFlag,
-		Status:     statusOutput,
-		SystemInfo: debugSystemInfoFlag,
+		Anonymize:   anonymizeFlag,
+		Status:      statusOutput,
+		SystemInfo:  debugSystemInfoFlag,
+		Diagnostics: getDiagnosticsOutput(cmd, client, debugDiagnosePeers, anonymizeFlag),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to bundle debug: %v", status.Convert(err).Message())
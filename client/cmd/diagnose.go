@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	nbdiagnose "github.com/netbirdio/netbird/client/diagnose"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var diagnoseJSONFlag bool
+
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose <peer>",
+	Short: "Diagnose the connection to a peer",
+	Long: `Shows why the connection to a peer is relayed or not established: the local and remote ICE candidates,
+the candidate pair check results, the local NAT type, the reachability of the STUN, TURN and relay servers
+and the recent reconnect attempts. The peer can be given by its public key, NetBird IP, FQDN or hostname.`,
+	Example: `
+  netbird diagnose peer-a
+  netbird diagnose 100.64.0.10 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: diagnoseFunc,
+}
+
+func init() {
+	diagnoseCmd.Flags().BoolVar(&diagnoseJSONFlag, "json", false, "display the diagnostics in json format")
+}
+
+func diagnoseFunc(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	cmd.Println("Diagnosing the connection, this takes a few seconds...")
+	resp, err := client.DiagnosePeer(cmd.Context(), &proto.DiagnosePeerRequest{Peer: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to diagnose peer: %v", status.Convert(err).Message())
+	}
+
+	output := nbdiagnose.ConvertToOutput(resp.GetDiagnostics(), anonymizeFlag)
+	if !diagnoseJSONFlag {
+		cmd.Print(nbdiagnose.ParseToText(output))
+		return nil
+	}
+
+	jsonOutput, err := nbdiagnose.ParseToJSON(output)
+	if err != nil {
+		return err
+	}
+	cmd.Println(jsonOutput)
+	return nil
+}
+
+// getDiagnosticsOutput returns the text reports of the given peers for the debug bundle,
+// failures are added to the report instead of aborting the bundle
+func getDiagnosticsOutput(cmd *cobra.Command, client proto.DaemonServiceClient, peers []string, anon bool) string {
+	var reports []string
+	for _, peer := range peers {
+		resp, err := client.DiagnosePeer(cmd.Context(), &proto.DiagnosePeerRequest{Peer: peer})
+		if err != nil {
+			reports = append(reports, fmt.Sprintf("Peer: %s\n  failed to diagnose peer: %s\n", peer, status.Convert(err).Message()))
+			continue
+		}
+		reports = append(reports, nbdiagnose.ParseToText(nbdiagnose.ConvertToOutput(resp.GetDiagnostics(), anon)))
+	}
+	return strings.Join(reports, "\n")
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage client profiles",
+	Long: `Commands to manage client profiles. Each profile has its own config, WireGuard key and state file,
+which allows switching between management servers without logging in again.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List client profiles",
+	Args:    cobra.NoArgs,
+	RunE:    listProfiles,
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a client profile",
+	Example: `
+  netbird profile add customer
+  netbird profile use customer
+  netbird up --management-url https://netbird.customer.example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: addProfile,
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch to a client profile",
+	Long: `Disconnects the active profile and activates the given one. The profile connects right away
+if it is logged in, otherwise run netbird up to log in.`,
+	Args: cobra.ExactArgs(1),
+	RunE: useProfile,
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a client profile with its config and state",
+	Args:    cobra.ExactArgs(1),
+	RunE:    removeProfile,
+}
+
+func listProfiles(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListProfiles(cmd.Context(), &proto.ListProfilesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list profiles: %v", status.Convert(err).Message())
+	}
+
+	for _, p := range resp.GetProfiles() {
+		marker := " "
+		if p.GetActive() {
+			marker = "*"
+		}
+
+		managementURL := p.GetManagementUrl()
+		if managementURL == "" {
+			managementURL = "not logged in"
+		}
+
+		cmd.Printf("%s %s (%s)\n", marker, p.GetName(), managementURL)
+	}
+
+	return nil
+}
+
+func addProfile(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.AddProfile(cmd.Context(), &proto.AddProfileRequest{Name: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to add profile: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Profile %s added, run netbird profile use %s to switch to it\n", resp.GetProfile().GetName(), resp.GetProfile().GetName())
+	return nil
+}
+
+func useProfile(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.SwitchProfile(cmd.Context(), &proto.SwitchProfileRequest{Name: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to switch profile: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Switched to profile %s\n", args[0])
+	if resp.GetStatus() == string(internal.StatusNeedsLogin) {
+		cmd.Println("The profile isn't logged in, run netbird up to log in")
+	}
+	return nil
+}
+
+func removeProfile(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	if _, err = client.RemoveProfile(cmd.Context(), &proto.RemoveProfileRequest{Name: args[0]}); err != nil {
+		return fmt.Errorf("failed to remove profile: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Profile %s removed\n", args[0])
+	return nil
+}
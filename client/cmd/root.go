@@ -38,7 +38,9 @@ const (
 	extraIFaceBlackListFlag = "extra-iface-blacklist"
 	dnsRouteIntervalFlag    = "dns-router-interval"
 	systemInfoFlag          = "system-info"
+	diagnoseFlag            = "diagnose"
 	blockLANAccessFlag      = "block-lan-access"
+	offlineStartFlag        = "offline-start"
 )
 
 var (
@@ -73,8 +75,10 @@ var (
 	extraIFaceBlackList     []string
 	anonymizeFlag           bool
 	debugSystemInfoFlag     bool
+	debugDiagnosePeers      []string
 	dnsRouteInterval        time.Duration
 	blockLANAccess          bool
+	offlineStart            bool
 
 	rootCmd = &cobra.Command{
 		Use:          "netbird",
@@ -147,6 +151,9 @@ func init() {
 	rootCmd.AddCommand(networksCMD)
 	rootCmd.AddCommand(forwardingRulesCmd)
 	rootCmd.AddCommand(debugCmd)
+	rootCmd.AddCommand(conntrackCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(diagnoseCmd)
 
 	serviceCmd.AddCommand(runCmd, startCmd, stopCmd, restartCmd) // service control commands are subcommands of service
 	serviceCmd.AddCommand(installCmd, uninstallCmd)              // service installer commands are subcommands of service
@@ -156,6 +163,10 @@ func init() {
 
 	forwardingRulesCmd.AddCommand(forwardingRulesListCmd)
 
+	conntrackCmd.AddCommand(conntrackListCmd, conntrackFlushCmd)
+
+	profileCmd.AddCommand(profileListCmd, profileAddCmd, profileUseCmd, profileRemoveCmd)
+
 	debugCmd.AddCommand(debugBundleCmd)
 	debugCmd.AddCommand(logCmd)
 	logCmd.AddCommand(logLevelCmd)
@@ -181,6 +192,7 @@ func init() {
 	upCmd.PersistentFlags().BoolVar(&autoConnectDisabled, disableAutoConnectFlag, false, "Disables auto-connect feature. If enabled, then the client won't connect automatically when the service starts.")
 
 	debugCmd.PersistentFlags().BoolVarP(&debugSystemInfoFlag, systemInfoFlag, "S", true, "Adds system information to the debug bundle")
+	debugCmd.PersistentFlags().StringSliceVar(&debugDiagnosePeers, diagnoseFlag, nil, "Adds the connection diagnostics of the given peers to the debug bundle, e.g., --diagnose peer-a,100.64.0.10")
 }
 
 // SetupCloseHandler handles SIGTERM signal and exits with success
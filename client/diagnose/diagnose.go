@@ -0,0 +1,427 @@
+package diagnose
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/netbirdio/netbird/client/anonymize"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	iceStateFailed    = "Failed"
+	pairStateFailed   = "failed"
+	candidateSrflx    = "srflx"
+	timeLayout        = "2006-01-02 15:04:05"
+	natEndpointDepend = "endpoint-dependent mapping (symmetric)"
+	natUDPBlocked     = "UDP blocked"
+)
+
+type CandidateOutput struct {
+	Type           string `json:"type"`
+	Network        string `json:"network"`
+	Address        string `json:"address"`
+	Port           int32  `json:"port"`
+	RelatedAddress string `json:"relatedAddress,omitempty"`
+	IgnoredReason  string `json:"ignoredReason,omitempty"`
+}
+
+type CandidatePairOutput struct {
+	Local     CandidateOutput `json:"local"`
+	Remote    CandidateOutput `json:"remote"`
+	State     string          `json:"state"`
+	Nominated bool            `json:"nominated"`
+}
+
+type ICEOutput struct {
+	State            string                `json:"state"`
+	LastAttempt      time.Time             `json:"lastAttempt"`
+	LastError        string                `json:"lastError"`
+	LocalCandidates  []CandidateOutput     `json:"localCandidates"`
+	RemoteCandidates []CandidateOutput     `json:"remoteCandidates"`
+	CandidatePairs   []CandidatePairOutput `json:"candidatePairs"`
+}
+
+type STUNMappingOutput struct {
+	Server        string `json:"server"`
+	MappedAddress string `json:"mappedAddress"`
+	Error         string `json:"error"`
+}
+
+type NATOutput struct {
+	Type         string              `json:"type"`
+	LocalAddress string              `json:"localAddress"`
+	Mappings     []STUNMappingOutput `json:"mappings"`
+	Error        string              `json:"error"`
+}
+
+type RelayOutput struct {
+	URI       string `json:"uri"`
+	Available bool   `json:"available"`
+	Error     string `json:"error"`
+}
+
+type ReconnectEventOutput struct {
+	Time      time.Time `json:"time"`
+	Reason    string    `json:"reason"`
+	OfferSent bool      `json:"offerSent"`
+}
+
+// Output is the diagnose report of the connection to a remote peer
+type Output struct {
+	FQDN                   string                 `json:"fqdn"`
+	IP                     string                 `json:"netbirdIp"`
+	PubKey                 string                 `json:"publicKey"`
+	Status                 string                 `json:"status"`
+	ConnType               string                 `json:"connectionType"`
+	ICEStatus              string                 `json:"iceStatus"`
+	RelayStatus            string                 `json:"relayStatus"`
+	RelayAddress           string                 `json:"relayAddress"`
+	RelaySupportedLocally  bool                   `json:"relaySupportedLocally"`
+	RelaySupportedByRemote bool                   `json:"relaySupportedByRemote"`
+	ICE                    ICEOutput              `json:"ice"`
+	NAT                    NATOutput              `json:"nat"`
+	Relays                 []RelayOutput          `json:"relays"`
+	ReconnectHistory       []ReconnectEventOutput `json:"reconnectHistory"`
+	Findings               []string               `json:"findings"`
+}
+
+// ConvertToOutput converts the daemon response to the report, anonymizing the addresses if anon is set
+func ConvertToOutput(diag *proto.PeerDiagnostics, anon bool) Output {
+	connType := "-"
+	if diag.GetConnStatus() == "Connected" {
+		connType = "P2P"
+		if diag.GetRelayed() {
+			connType = "Relayed"
+		}
+	}
+
+	output := Output{
+		FQDN:                   diag.GetFqdn(),
+		IP:                     diag.GetIP(),
+		PubKey:                 diag.GetPubKey(),
+		Status:                 diag.GetConnStatus(),
+		ConnType:               connType,
+		ICEStatus:              diag.GetIceStatus(),
+		RelayStatus:            diag.GetRelayStatus(),
+		RelayAddress:           diag.GetRelayAddress(),
+		RelaySupportedLocally:  diag.GetRelaySupportedLocally(),
+		RelaySupportedByRemote: diag.GetRelaySupportedByRemote(),
+		ICE: ICEOutput{
+			State:            diag.GetIce().GetState(),
+			LastError:        diag.GetIce().GetLastError(),
+			LocalCandidates:  []CandidateOutput{},
+			RemoteCandidates: []CandidateOutput{},
+			CandidatePairs:   []CandidatePairOutput{},
+		},
+		NAT: NATOutput{
+			Type:         diag.GetNat().GetType(),
+			LocalAddress: diag.GetNat().GetLocalAddress(),
+			Mappings:     []STUNMappingOutput{},
+			Error:        diag.GetNat().GetError(),
+		},
+		Relays:           []RelayOutput{},
+		ReconnectHistory: []ReconnectEventOutput{},
+	}
+
+	if diag.GetIce().GetLastAttempt() != nil {
+		output.ICE.LastAttempt = diag.GetIce().GetLastAttempt().AsTime().Local()
+	}
+	for _, c := range diag.GetIce().GetLocalCandidates() {
+		output.ICE.LocalCandidates = append(output.ICE.LocalCandidates, toCandidateOutput(c))
+	}
+	for _, c := range diag.GetIce().GetRemoteCandidates() {
+		output.ICE.RemoteCandidates = append(output.ICE.RemoteCandidates, toCandidateOutput(c))
+	}
+	for _, pair := range diag.GetIce().GetCandidatePairs() {
+		output.ICE.CandidatePairs = append(output.ICE.CandidatePairs, CandidatePairOutput{
+			Local:     toCandidateOutput(pair.GetLocal()),
+			Remote:    toCandidateOutput(pair.GetRemote()),
+			State:     pair.GetState(),
+			Nominated: pair.GetNominated(),
+		})
+	}
+
+	for _, mapping := range diag.GetNat().GetMappings() {
+		output.NAT.Mappings = append(output.NAT.Mappings, STUNMappingOutput{
+			Server:        mapping.GetServer(),
+			MappedAddress: mapping.GetMappedAddress(),
+			Error:         mapping.GetError(),
+		})
+	}
+
+	for _, relay := range diag.GetRelays() {
+		output.Relays = append(output.Relays, RelayOutput{
+			URI:       relay.GetURI(),
+			Available: relay.GetAvailable(),
+			Error:     relay.GetError(),
+		})
+	}
+
+	for _, event := range diag.GetReconnectHistory() {
+		output.ReconnectHistory = append(output.ReconnectHistory, ReconnectEventOutput{
+			Time:      event.GetTime().AsTime().Local(),
+			Reason:    event.GetReason(),
+			OfferSent: event.GetOfferSent(),
+		})
+	}
+
+	output.Findings = findings(output)
+
+	if anon {
+		anonymizer := anonymize.NewAnonymizer(anonymize.DefaultAddresses())
+		anonymizeOutput(anonymizer, &output)
+	}
+
+	return output
+}
+
+// ParseToJSON returns the report in json format
+func ParseToJSON(output Output) (string, error) {
+	jsonBytes, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("json marshal failed")
+	}
+	return string(jsonBytes), nil
+}
+
+// ParseToText returns the human-readable report
+func ParseToText(output Output) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Peer: %s\n", output.FQDN)
+	fmt.Fprintf(&b, "  NetBird IP: %s\n", output.IP)
+	fmt.Fprintf(&b, "  Public key: %s\n", output.PubKey)
+	fmt.Fprintf(&b, "  Status: %s\n", output.Status)
+	fmt.Fprintf(&b, "  Connection type: %s\n", output.ConnType)
+	fmt.Fprintf(&b, "  ICE connection: %s\n", output.ICEStatus)
+	fmt.Fprintf(&b, "  Relay connection: %s\n", output.RelayStatus)
+	fmt.Fprintf(&b, "  Relay server address: %s\n", valueOrDash(output.RelayAddress))
+	fmt.Fprintf(&b, "  Relay supported (local/remote): %t/%t\n", output.RelaySupportedLocally, output.RelaySupportedByRemote)
+
+	b.WriteString("\nICE:\n")
+	fmt.Fprintf(&b, "  State: %s\n", valueOrDash(output.ICE.State))
+	fmt.Fprintf(&b, "  Last attempt: %s\n", formatTime(output.ICE.LastAttempt))
+	fmt.Fprintf(&b, "  Last error: %s\n", valueOrDash(output.ICE.LastError))
+
+	b.WriteString("  Local candidates:\n")
+	writeCandidates(&b, output.ICE.LocalCandidates)
+	b.WriteString("  Remote candidates:\n")
+	writeCandidates(&b, output.ICE.RemoteCandidates)
+
+	b.WriteString("  Candidate pairs:\n")
+	if len(output.ICE.CandidatePairs) == 0 {
+		b.WriteString("    -\n")
+	}
+	for _, pair := range output.ICE.CandidatePairs {
+		nominated := ""
+		if pair.Nominated {
+			nominated = ", nominated"
+		}
+		fmt.Fprintf(&b, "    %s <-> %s: %s%s\n", formatCandidate(pair.Local), formatCandidate(pair.Remote), pair.State, nominated)
+	}
+
+	b.WriteString("\nNAT:\n")
+	if output.NAT.Error != "" {
+		fmt.Fprintf(&b, "  Error: %s\n", output.NAT.Error)
+	} else {
+		fmt.Fprintf(&b, "  Type: %s\n", output.NAT.Type)
+		fmt.Fprintf(&b, "  Local address: %s\n", output.NAT.LocalAddress)
+		for _, mapping := range output.NAT.Mappings {
+			if mapping.Error != "" {
+				fmt.Fprintf(&b, "  [%s] no answer, reason: %s\n", mapping.Server, mapping.Error)
+				continue
+			}
+			fmt.Fprintf(&b, "  [%s] mapped to %s\n", mapping.Server, mapping.MappedAddress)
+		}
+	}
+
+	b.WriteString("\nRelays:\n")
+	if len(output.Relays) == 0 {
+		b.WriteString("  -\n")
+	}
+	for _, relay := range output.Relays {
+		if relay.Available {
+			fmt.Fprintf(&b, "  [%s] is Available\n", relay.URI)
+			continue
+		}
+		fmt.Fprintf(&b, "  [%s] is Unavailable, reason: %s\n", relay.URI, relay.Error)
+	}
+
+	b.WriteString("\nReconnect history:\n")
+	if len(output.ReconnectHistory) == 0 {
+		b.WriteString("  -\n")
+	}
+	for _, event := range output.ReconnectHistory {
+		action := ""
+		if event.OfferSent {
+			action = ", sent offer"
+		}
+		fmt.Fprintf(&b, "  %s %s%s\n", formatTime(event.Time), event.Reason, action)
+	}
+
+	b.WriteString("\nFindings:\n")
+	if len(output.Findings) == 0 {
+		b.WriteString("  -\n")
+	}
+	for _, finding := range output.Findings {
+		fmt.Fprintf(&b, "  - %s\n", finding)
+	}
+
+	return b.String()
+}
+
+// findings points out the likely reasons of a connection not being established peer-to-peer
+func findings(output Output) []string {
+	var result []string
+
+	switch output.NAT.Type {
+	case natUDPBlocked:
+		result = append(result, "none of the STUN servers answered, outgoing UDP seems to be blocked and only relayed connections are possible")
+	case natEndpointDepend:
+		result = append(result, "the local NAT maps each destination to a different port, P2P connections only work if the remote peer isn't behind a NAT of the same type")
+	}
+
+	if output.ICE.LastAttempt.IsZero() {
+		result = append(result, "no ICE connection attempt was made yet, the remote peer might be offline or the signal server unreachable")
+		return result
+	}
+
+	hasSrflx := false
+	for _, c := range output.ICE.LocalCandidates {
+		if c.Type == candidateSrflx {
+			hasSrflx = true
+			break
+		}
+	}
+	if !hasSrflx && output.NAT.Type != natUDPBlocked {
+		result = append(result, "no server reflexive candidate was gathered, the STUN servers might be unreachable from the ICE agent")
+	}
+
+	usable := 0
+	for _, c := range output.ICE.RemoteCandidates {
+		if c.IgnoredReason == "" {
+			usable++
+		}
+	}
+	if usable == 0 {
+		result = append(result, "no usable candidates were received from the remote peer, check its signal connection and version")
+	}
+
+	if len(output.ICE.CandidatePairs) > 0 {
+		failed := 0
+		for _, pair := range output.ICE.CandidatePairs {
+			if pair.State == pairStateFailed {
+				failed++
+			}
+		}
+		if failed == len(output.ICE.CandidatePairs) {
+			result = append(result, "all candidate pairs failed the connectivity checks, a firewall might drop UDP between the peers")
+		}
+	}
+
+	if output.ICE.State == iceStateFailed && output.ConnType == "Relayed" {
+		result = append(result, "the connection falls back to the relay because the ICE connection failed")
+	}
+
+	return result
+}
+
+func writeCandidates(b *strings.Builder, candidates []CandidateOutput) {
+	if len(candidates) == 0 {
+		b.WriteString("    -\n")
+		return
+	}
+	for _, c := range candidates {
+		line := fmt.Sprintf("    %s %s", c.Network, formatCandidate(c))
+		if c.RelatedAddress != "" {
+			line += fmt.Sprintf(" (related %s)", c.RelatedAddress)
+		}
+		if c.IgnoredReason != "" {
+			line += fmt.Sprintf(", ignored: %s", c.IgnoredReason)
+		}
+		b.WriteString(line + "\n")
+	}
+}
+
+func formatCandidate(c CandidateOutput) string {
+	if c.Address == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s %s", c.Type, net.JoinHostPort(c.Address, fmt.Sprint(c.Port)))
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(timeLayout)
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func toCandidateOutput(c *proto.ICECandidate) CandidateOutput {
+	return CandidateOutput{
+		Type:           c.GetType(),
+		Network:        c.GetNetwork(),
+		Address:        c.GetAddress(),
+		Port:           c.GetPort(),
+		RelatedAddress: c.GetRelatedAddress(),
+		IgnoredReason:  c.GetIgnoredReason(),
+	}
+}
+
+func anonymizeOutput(a *anonymize.Anonymizer, output *Output) {
+	output.FQDN = a.AnonymizeDomain(output.FQDN)
+	output.RelayAddress = a.AnonymizeURI(output.RelayAddress)
+	output.ICE.LastError = a.AnonymizeString(output.ICE.LastError)
+
+	anonymizeCandidates(a, output.ICE.LocalCandidates)
+	anonymizeCandidates(a, output.ICE.RemoteCandidates)
+	for i := range output.ICE.CandidatePairs {
+		anonymizeCandidate(a, &output.ICE.CandidatePairs[i].Local)
+		anonymizeCandidate(a, &output.ICE.CandidatePairs[i].Remote)
+	}
+
+	output.NAT.LocalAddress = anonymizeHostPort(a, output.NAT.LocalAddress)
+	output.NAT.Error = a.AnonymizeString(output.NAT.Error)
+	for i, mapping := range output.NAT.Mappings {
+		mapping.Server = anonymizeHostPort(a, mapping.Server)
+		mapping.MappedAddress = anonymizeHostPort(a, mapping.MappedAddress)
+		mapping.Error = a.AnonymizeString(mapping.Error)
+		output.NAT.Mappings[i] = mapping
+	}
+
+	for i, relay := range output.Relays {
+		relay.URI = a.AnonymizeURI(relay.URI)
+		relay.Error = a.AnonymizeString(relay.Error)
+		output.Relays[i] = relay
+	}
+}
+
+func anonymizeCandidates(a *anonymize.Anonymizer, candidates []CandidateOutput) {
+	for i := range candidates {
+		anonymizeCandidate(a, &candidates[i])
+	}
+}
+
+func anonymizeCandidate(a *anonymize.Anonymizer, c *CandidateOutput) {
+	c.Address = a.AnonymizeIPString(c.Address)
+	c.RelatedAddress = anonymizeHostPort(a, c.RelatedAddress)
+}
+
+func anonymizeHostPort(a *anonymize.Anonymizer, hostPort string) string {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return a.AnonymizeString(hostPort)
+	}
+	return net.JoinHostPort(a.AnonymizeIPString(host), port)
+}
@@ -0,0 +1,129 @@
+package diagnose
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var lastAttempt = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func relayedDiagnostics() *proto.PeerDiagnostics {
+	return &proto.PeerDiagnostics{
+		PubKey:                 "Pubkey1",
+		Fqdn:                   "peer-1.awesome-domain.com",
+		IP:                     "100.64.0.10",
+		ConnStatus:             "Connected",
+		Relayed:                true,
+		IceStatus:              "Disconnected",
+		RelayStatus:            "Connected",
+		RelayAddress:           "rels://relay.awesome-domain.com:443",
+		RelaySupportedLocally:  true,
+		RelaySupportedByRemote: true,
+		Ice: &proto.ICEDiagnostics{
+			State:       "Failed",
+			LastAttempt: timestamppb.New(lastAttempt),
+			LocalCandidates: []*proto.ICECandidate{
+				{Type: "host", Network: "udp4", Address: "192.168.1.10", Port: 51820},
+			},
+			RemoteCandidates: []*proto.ICECandidate{
+				{Type: "host", Network: "udp4", Address: "10.0.0.5", Port: 51820, IgnoredReason: "address is in a routed network"},
+				{Type: "srflx", Network: "udp4", Address: "93.184.216.7", Port: 40000, RelatedAddress: "10.0.0.5:51820"},
+			},
+			CandidatePairs: []*proto.ICECandidatePair{
+				{
+					Local:  &proto.ICECandidate{Type: "host", Network: "udp4", Address: "192.168.1.10", Port: 51820},
+					Remote: &proto.ICECandidate{Type: "srflx", Network: "udp4", Address: "93.184.216.7", Port: 40000},
+					State:  "failed",
+				},
+			},
+		},
+		Nat: &proto.NATDiagnostics{
+			Type:         "endpoint-dependent mapping (symmetric)",
+			LocalAddress: "0.0.0.0:54321",
+			Mappings: []*proto.STUNMapping{
+				{Server: "93.184.216.10:3478", MappedAddress: "93.184.216.2:1000"},
+				{Server: "93.184.216.11:3478", MappedAddress: "93.184.216.2:1001"},
+			},
+		},
+		Relays: []*proto.RelayState{
+			{URI: "stun:stun.awesome-domain.com:3478", Available: true},
+			{URI: "turn:turn.awesome-domain.com:3478", Error: "context deadline exceeded"},
+		},
+		ReconnectHistory: []*proto.ReconnectEvent{
+			{Time: timestamppb.New(lastAttempt), Reason: "ICE connection disconnected", OfferSent: true},
+		},
+	}
+}
+
+func TestConvertToOutput(t *testing.T) {
+	output := ConvertToOutput(relayedDiagnostics(), false)
+
+	assert.Equal(t, "Relayed", output.ConnType)
+	assert.Equal(t, lastAttempt.Local(), output.ICE.LastAttempt)
+	assert.Len(t, output.ICE.RemoteCandidates, 2)
+	assert.Len(t, output.NAT.Mappings, 2)
+	assert.False(t, output.Relays[1].Available)
+	assert.Equal(t, []string{
+		"the local NAT maps each destination to a different port, P2P connections only work if the remote peer isn't behind a NAT of the same type",
+		"no server reflexive candidate was gathered, the STUN servers might be unreachable from the ICE agent",
+		"all candidate pairs failed the connectivity checks, a firewall might drop UDP between the peers",
+		"the connection falls back to the relay because the ICE connection failed",
+	}, output.Findings)
+}
+
+func TestFindingsWithoutAttempt(t *testing.T) {
+	output := ConvertToOutput(&proto.PeerDiagnostics{
+		ConnStatus: "Connecting",
+		Nat:        &proto.NATDiagnostics{Type: "UDP blocked"},
+	}, false)
+
+	assert.Equal(t, "-", output.ConnType)
+	assert.Equal(t, []string{
+		"none of the STUN servers answered, outgoing UDP seems to be blocked and only relayed connections are possible",
+		"no ICE connection attempt was made yet, the remote peer might be offline or the signal server unreachable",
+	}, output.Findings)
+}
+
+func TestConvertToOutputAnonymized(t *testing.T) {
+	output := ConvertToOutput(relayedDiagnostics(), true)
+
+	text := ParseToText(output)
+	for _, original := range []string{"awesome-domain.com", "93.184.216.7", "93.184.216.10", "93.184.216.2"} {
+		assert.NotContains(t, text, original)
+	}
+	assert.Contains(t, text, "100.64.0.10", "NetBird IPs are kept")
+}
+
+func TestParseToText(t *testing.T) {
+	text := ParseToText(ConvertToOutput(relayedDiagnostics(), false))
+	attempt := lastAttempt.Local().Format(timeLayout)
+
+	for _, expected := range []string{
+		"Peer: peer-1.awesome-domain.com\n",
+		"  Connection type: Relayed\n",
+		"  Last attempt: " + attempt + "\n",
+		"    udp4 host 10.0.0.5:51820, ignored: address is in a routed network\n",
+		"    udp4 srflx 93.184.216.7:40000 (related 10.0.0.5:51820)\n",
+		"    host 192.168.1.10:51820 <-> srflx 93.184.216.7:40000: failed\n",
+		"  [93.184.216.11:3478] mapped to 93.184.216.2:1001\n",
+		"  [turn:turn.awesome-domain.com:3478] is Unavailable, reason: context deadline exceeded\n",
+		"  " + attempt + " ICE connection disconnected, sent offer\n",
+	} {
+		assert.Contains(t, text, expected)
+	}
+	assert.True(t, strings.HasPrefix(text, "Peer: "))
+}
+
+func TestParseToJSON(t *testing.T) {
+	json, err := ParseToJSON(ConvertToOutput(relayedDiagnostics(), false))
+	require.NoError(t, err)
+	assert.Contains(t, json, `"connectionType": "Relayed"`)
+	assert.Contains(t, json, `"ignoredReason": "address is in a routed network"`)
+}
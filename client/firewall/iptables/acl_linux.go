@@ -20,6 +20,9 @@ const (
 
 	// rules chains contains the effective ACL rules
 	chainNameInputRules = "NETBIRD-ACL-INPUT"
+
+	ipsetV6Suffix  = "-v6"
+	protocolICMPv6 = "ipv6-icmp"
 )
 
 type aclEntries map[string][][]string
@@ -35,6 +38,8 @@ type aclManager struct {
 	entries         aclEntries
 	optionalEntries map[string][]entry
 	ipsetStore      *ipsetStore
+	// v6 is set for the ip6tables instance, which only filters the inbound overlay traffic
+	v6 bool
 
 	stateManager *statemanager.Manager
 }
@@ -43,6 +48,7 @@ func newAclManager(iptablesClient *iptables.IPTables, wgIface iFaceMapper) (*acl
 	m := &aclManager{
 		iptablesClient:  iptablesClient,
 		wgIface:         wgIface,
+		v6:              iptablesClient.Proto() == iptables.ProtocolIPv6,
 		entries:         make(map[string][][]string),
 		optionalEntries: make(map[string][]entry),
 		ipsetStore:      newIpsetStore(),
@@ -86,14 +92,24 @@ func (m *aclManager) AddPeerFiltering(
 	chain := chainNameInputRules
 
 	ipsetName = transformIPsetName(ipsetName, sPort, dPort)
+	if ipsetName != "" && m.v6 {
+		// ipset names are shared between the address families
+		ipsetName += ipsetV6Suffix
+	}
+	if m.v6 && protocol == firewall.ProtocolICMP {
+		protocol = protocolICMPv6
+	}
 	specs := filterRuleSpecs(ip, string(protocol), sPort, dPort, action, ipsetName)
 
-	mangleSpecs := slices.Clone(specs)
-	mangleSpecs = append(mangleSpecs,
-		"-i", m.wgIface.Name(),
-		"-m", "addrtype", "--dst-type", "LOCAL",
-		"-j", "MARK", "--set-xmark", fmt.Sprintf("%#x", nbnet.PreroutingFwmarkRedirected),
-	)
+	var mangleSpecs []string
+	if !m.v6 {
+		mangleSpecs = slices.Clone(specs)
+		mangleSpecs = append(mangleSpecs,
+			"-i", m.wgIface.Name(),
+			"-m", "addrtype", "--dst-type", "LOCAL",
+			"-j", "MARK", "--set-xmark", fmt.Sprintf("%#x", nbnet.PreroutingFwmarkRedirected),
+		)
+	}
 
 	specs = append(specs, "-j", actionToStr(action))
 	if ipsetName != "" {
@@ -116,7 +132,7 @@ func (m *aclManager) AddPeerFiltering(
 		if err := ipset.Flush(ipsetName); err != nil {
 			log.Errorf("flush ipset %s before use it: %s", ipsetName, err)
 		}
-		if err := ipset.Create(ipsetName); err != nil {
+		if err := ipset.Create(ipsetName, m.ipsetOptions()...); err != nil {
 			return nil, fmt.Errorf("failed to create ipset: %w", err)
 		}
 		if err := ipset.Add(ipsetName, ip.String()); err != nil {
@@ -139,9 +155,11 @@ func (m *aclManager) AddPeerFiltering(
 		return nil, err
 	}
 
-	if err := m.iptablesClient.Append(tableMangle, chainRTPRE, mangleSpecs...); err != nil {
-		log.Errorf("failed to add mangle rule: %v", err)
-		mangleSpecs = nil
+	if mangleSpecs != nil {
+		if err := m.iptablesClient.Append(tableMangle, chainRTPRE, mangleSpecs...); err != nil {
+			log.Errorf("failed to add mangle rule: %v", err)
+			mangleSpecs = nil
+		}
 	}
 
 	rule := &Rule{
@@ -311,6 +329,12 @@ func (m *aclManager) seedInitialEntries() {
 	m.appendToEntries("INPUT", []string{"-i", m.wgIface.Name(), "-j", chainNameInputRules})
 	m.appendToEntries("INPUT", append([]string{"-i", m.wgIface.Name()}, established...))
 
+	// the routing chains only exist for IPv4, inbound IPv6 overlay traffic is never forwarded
+	if m.v6 {
+		m.appendToEntries("FORWARD", []string{"-i", m.wgIface.Name(), "-j", "DROP"})
+		return
+	}
+
 	// Inbound is handled by our ACLs, the rest is dropped.
 	// For outbound we respect the FORWARD policy. However, we need to allow established/related traffic for inbound rules.
 	m.appendToEntries("FORWARD", []string{"-i", m.wgIface.Name(), "-j", "DROP"})
@@ -320,6 +344,9 @@ func (m *aclManager) seedInitialEntries() {
 }
 
 func (m *aclManager) seedInitialOptionalEntries() {
+	if m.v6 {
+		return
+	}
 	m.optionalEntries["FORWARD"] = []entry{
 		{
 			spec:     []string{"-m", "mark", "--mark", fmt.Sprintf("%#x", nbnet.PreroutingFwmarkRedirected), "-j", "ACCEPT"},
@@ -350,8 +377,13 @@ func (m *aclManager) updateState() {
 	currentState.Lock()
 	defer currentState.Unlock()
 
-	currentState.ACLEntries = m.entries
-	currentState.ACLIPsetStore = m.ipsetStore
+	if m.v6 {
+		currentState.ACLEntries6 = m.entries
+		currentState.ACLIPsetStore6 = m.ipsetStore
+	} else {
+		currentState.ACLEntries = m.entries
+		currentState.ACLIPsetStore = m.ipsetStore
+	}
 
 	if err := m.stateManager.UpdateState(currentState); err != nil {
 		log.Errorf("failed to update state: %v", err)
@@ -361,8 +393,8 @@ func (m *aclManager) updateState() {
 // filterRuleSpecs returns the specs of a filtering rule
 func filterRuleSpecs(ip net.IP, protocol string, sPort, dPort *firewall.Port, action firewall.Action, ipsetName string) (specs []string) {
 	matchByIP := true
-	// don't use IP matching if IP is ip 0.0.0.0
-	if ip.String() == "0.0.0.0" {
+	// don't use IP matching if IP is ip 0.0.0.0 or ::
+	if ip.IsUnspecified() {
 		matchByIP = false
 	}
 
@@ -381,6 +413,13 @@ func filterRuleSpecs(ip net.IP, protocol string, sPort, dPort *firewall.Port, ac
 	return specs
 }
 
+func (m *aclManager) ipsetOptions() []ipset.Option {
+	if m.v6 {
+		return []ipset.Option{ipset.OptIPv6()}
+	}
+	return nil
+}
+
 func actionToStr(action firewall.Action) string {
 	if action == firewall.ActionAccept {
 		return "ACCEPT"
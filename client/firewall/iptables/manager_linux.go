@@ -26,6 +26,9 @@ type Manager struct {
 	ipv4Client *iptables.IPTables
 	aclMgr     *aclManager
 	router     *router
+
+	// aclMgr6 filters the IPv6 overlay traffic, it is nil if ip6tables is not available
+	aclMgr6 *aclManager
 }
 
 // iFaceMapper defines subset methods of interface required for manager
@@ -57,6 +60,12 @@ func Create(wgIface iFaceMapper) (*Manager, error) {
 		return nil, fmt.Errorf("create acl manager: %w", err)
 	}
 
+	if ip6tablesClient, err := iptables.NewWithProtocol(iptables.ProtocolIPv6); err != nil {
+		log.Warnf("ip6tables is not available, IPv6 overlay traffic won't be filtered: %v", err)
+	} else if m.aclMgr6, err = newAclManager(ip6tablesClient, wgIface); err != nil {
+		log.Warnf("failed to create IPv6 acl manager: %v", err)
+	}
+
 	return m, nil
 }
 
@@ -82,6 +91,13 @@ func (m *Manager) Init(stateManager *statemanager.Manager) error {
 		return fmt.Errorf("acl manager init: %w", err)
 	}
 
+	if m.aclMgr6 != nil {
+		if err := m.aclMgr6.init(stateManager); err != nil {
+			log.Warnf("failed to init IPv6 acl manager, IPv6 overlay traffic is disabled: %v", err)
+			m.aclMgr6 = nil
+		}
+	}
+
 	// persist early to ensure cleanup of chains
 	go func() {
 		if err := stateManager.PersistState(context.Background()); err != nil {
@@ -107,7 +123,24 @@ func (m *Manager) AddPeerFiltering(
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	return m.aclMgr.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
+	if ip.To4() == nil {
+		if m.aclMgr6 == nil {
+			return nil, fmt.Errorf("unsupported IP version: %s", ip.String())
+		}
+		return m.aclMgr6.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
+	}
+
+	rules, err := m.aclMgr.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
+	if err != nil || m.aclMgr6 == nil || !ip.IsUnspecified() {
+		return rules, err
+	}
+
+	// rules without a peer address apply to both address families
+	rules6, err := m.aclMgr6.AddPeerFiltering(id, net.IPv6zero, proto, sPort, dPort, action, ipsetName)
+	if err != nil {
+		return rules, fmt.Errorf("add IPv6 rule: %w", err)
+	}
+	return append(rules, rules6...), nil
 }
 
 func (m *Manager) AddRouteFiltering(
@@ -134,9 +167,18 @@ func (m *Manager) DeletePeerRule(rule firewall.Rule) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if r, ok := rule.(*Rule); ok && m.aclMgr6 != nil && isIPv6(r.ip) {
+		return m.aclMgr6.DeletePeerRule(rule)
+	}
+
 	return m.aclMgr.DeletePeerRule(rule)
 }
 
+// SupportsIPv6 returns true if the IPv6 overlay traffic is filtered
+func (m *Manager) SupportsIPv6() bool {
+	return m.aclMgr6 != nil
+}
+
 func (m *Manager) DeleteRouteRule(rule firewall.Rule) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -176,6 +218,11 @@ func (m *Manager) Close(stateManager *statemanager.Manager) error {
 	if err := m.aclMgr.Reset(); err != nil {
 		merr = multierror.Append(merr, fmt.Errorf("reset acl manager: %w", err))
 	}
+	if m.aclMgr6 != nil {
+		if err := m.aclMgr6.Reset(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("reset IPv6 acl manager: %w", err))
+		}
+	}
 	if err := m.router.Reset(); err != nil {
 		merr = multierror.Append(merr, fmt.Errorf("reset router: %w", err))
 	}
@@ -243,6 +290,11 @@ func (m *Manager) DeleteDNATRule(rule firewall.Rule) error {
 	return m.router.DeleteDNATRule(rule)
 }
 
+func isIPv6(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	return err == nil && addr.Is6() && !addr.Is4In6()
+}
+
 func getConntrackEstablished() []string {
 	return []string{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"}
 }
@@ -35,6 +35,9 @@ type ShutdownState struct {
 
 	ACLEntries    aclEntries  `json:"acl_entries,omitempty"`
 	ACLIPsetStore *ipsetStore `json:"acl_ipset_store,omitempty"`
+
+	ACLEntries6    aclEntries  `json:"acl_entries_v6,omitempty"`
+	ACLIPsetStore6 *ipsetStore `json:"acl_ipset_store_v6,omitempty"`
 }
 
 func (s *ShutdownState) Name() string {
@@ -60,6 +63,14 @@ func (s *ShutdownState) Cleanup() error {
 	if s.ACLIPsetStore != nil {
 		ipt.aclMgr.ipsetStore = s.ACLIPsetStore
 	}
+	if ipt.aclMgr6 != nil {
+		if s.ACLEntries6 != nil {
+			ipt.aclMgr6.entries = s.ACLEntries6
+		}
+		if s.ACLIPsetStore6 != nil {
+			ipt.aclMgr6.ipsetStore = s.ACLIPsetStore6
+		}
+	}
 
 	if err := ipt.Close(nil); err != nil {
 		return fmt.Errorf("reset iptables manager: %w", err)
@@ -80,6 +80,10 @@ type Manager interface {
 	// IsServerRouteSupported returns true if the firewall supports server side routing operations
 	IsServerRouteSupported() bool
 
+	// SupportsIPv6 returns true if the firewall filters the IPv6 overlay traffic.
+	// Peer rules with IPv6 addresses must not be added otherwise.
+	SupportsIPv6() bool
+
 	AddRouteFiltering(
 		id []byte,
 		sources []netip.Prefix,
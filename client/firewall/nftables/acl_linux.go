@@ -43,6 +43,7 @@ type AclManager struct {
 	sConn              *nftables.Conn
 	wgIface            iFaceMapper
 	routingFwChainName string
+	family             nftables.TableFamily
 
 	workTable       *nftables.Table
 	chainInputRules *nftables.Chain
@@ -68,6 +69,7 @@ func newAclManager(table *nftables.Table, wgIface iFaceMapper, routingFwChainNam
 		wgIface:            wgIface,
 		workTable:          table,
 		routingFwChainName: routingFwChainName,
+		family:             table.Family,
 
 		ipsetStore: newIpsetStore(),
 		rules:      make(map[string]*Rule),
@@ -146,7 +148,7 @@ func (m *AclManager) DeletePeerRule(rule firewall.Rule) error {
 	}
 
 	if _, ok := ips[r.ip.String()]; ok {
-		err := m.sConn.SetDeleteElements(r.nftSet, []nftables.SetElement{{Key: r.ip.To4()}})
+		err := m.sConn.SetDeleteElements(r.nftSet, []nftables.SetElement{{Key: m.rawIP(r.ip)}})
 		if err != nil {
 			log.Errorf("delete elements for set %q: %v", r.nftSet.Name, err)
 		}
@@ -194,6 +196,29 @@ func (m *AclManager) DeletePeerRule(rule firewall.Rule) error {
 // createDefaultAllowRules creates default allow rules for the input and output chains
 func (m *AclManager) createDefaultAllowRules() error {
 	expIn := []expr.Any{
+		&expr.Verdict{
+			Kind: expr.VerdictAccept,
+		},
+	}
+	if m.family == nftables.TableFamilyIPv4 {
+		expIn = m.defaultAllowExprsV4()
+	}
+
+	_ = m.rConn.InsertRule(&nftables.Rule{
+		Table:    m.workTable,
+		Chain:    m.chainInputRules,
+		Position: 0,
+		Exprs:    expIn,
+	})
+
+	if err := m.rConn.Flush(); err != nil {
+		return fmt.Errorf(flushError, err)
+	}
+	return nil
+}
+
+func (m *AclManager) defaultAllowExprsV4() []expr.Any {
+	return []expr.Any{
 		&expr.Payload{
 			DestRegister: 1,
 			Base:         expr.PayloadBaseNetworkHeader,
@@ -217,18 +242,6 @@ func (m *AclManager) createDefaultAllowRules() error {
 			Kind: expr.VerdictAccept,
 		},
 	}
-
-	_ = m.rConn.InsertRule(&nftables.Rule{
-		Table:    m.workTable,
-		Chain:    m.chainInputRules,
-		Position: 0,
-		Exprs:    expIn,
-	})
-
-	if err := m.rConn.Flush(); err != nil {
-		return fmt.Errorf(flushError, err)
-	}
-	return nil
 }
 
 // Flush rule/chain/set operations from the buffer
@@ -271,17 +284,28 @@ func (m *AclManager) addIOFiltering(
 	var expressions []expr.Any
 
 	if proto != firewall.ProtocolALL {
-		expressions = append(expressions, &expr.Payload{
-			DestRegister: 1,
-			Base:         expr.PayloadBaseNetworkHeader,
-			Offset:       uint32(9),
-			Len:          uint32(1),
-		})
+		if m.family == nftables.TableFamilyIPv6 {
+			// the IPv6 header has no fixed protocol field, extension headers may follow
+			expressions = append(expressions, &expr.Meta{
+				Key:      expr.MetaKeyL4PROTO,
+				Register: 1,
+			})
+		} else {
+			expressions = append(expressions, &expr.Payload{
+				DestRegister: 1,
+				Base:         expr.PayloadBaseNetworkHeader,
+				Offset:       uint32(9),
+				Len:          uint32(1),
+			})
+		}
 
 		protoData, err := protoToInt(proto)
 		if err != nil {
 			return nil, fmt.Errorf("convert protocol to number: %v", err)
 		}
+		if m.family == nftables.TableFamilyIPv6 && proto == firewall.ProtocolICMP {
+			protoData = unix.IPPROTO_ICMPV6
+		}
 
 		expressions = append(expressions, &expr.Cmp{
 			Register: 1,
@@ -290,19 +314,22 @@ func (m *AclManager) addIOFiltering(
 		})
 	}
 
-	rawIP := ip.To4()
-	// check if rawIP contains zeroed IPv4 0.0.0.0 value
+	rawIP := m.rawIP(ip)
+	// check if rawIP contains zeroed 0.0.0.0 or :: value
 	// in that case not add IP match expression into the rule definition
 	if !bytes.HasPrefix(anyIP, rawIP) {
 		// source address position
 		addrOffset := uint32(12)
+		if m.family == nftables.TableFamilyIPv6 {
+			addrOffset = 8
+		}
 
 		expressions = append(expressions,
 			&expr.Payload{
 				DestRegister: 1,
 				Base:         expr.PayloadBaseNetworkHeader,
 				Offset:       addrOffset,
-				Len:          4,
+				Len:          uint32(len(rawIP)),
 			},
 		)
 		// add individual IP for match if no ipset defined
@@ -442,7 +469,10 @@ func (m *AclManager) createDefaultChains() (err error) {
 
 	// netbird-acl-forward-filter
 	chainFwFilter := m.createFilterChainWithHook(chainNameForwardFilter, nftables.ChainHookForward)
-	m.addJumpRulesToRtForward(chainFwFilter) // to netbird-rt-fwd
+	// the routing chains only exist in the IPv4 table
+	if m.routingFwChainName != "" {
+		m.addJumpRulesToRtForward(chainFwFilter) // to netbird-rt-fwd
+	}
 	m.addDropExpressions(chainFwFilter, expr.MetaKeyIIFNAME)
 
 	err = m.rConn.Flush()
@@ -589,7 +619,7 @@ func (m *AclManager) addJumpRule(chain *nftables.Chain, to string, ifaceKey expr
 
 func (m *AclManager) addIpToSet(ipsetName string, ip net.IP) (*nftables.Set, error) {
 	ipset, err := m.rConn.GetSetByName(m.workTable, ipsetName)
-	rawIP := ip.To4()
+	rawIP := m.rawIP(ip)
 	if err != nil {
 		if ipset, err = m.createSet(m.workTable, ipsetName); err != nil {
 			return nil, fmt.Errorf("get set name: %v", err)
@@ -623,6 +653,9 @@ func (m *AclManager) createSet(table *nftables.Table, name string) (*nftables.Se
 		Dynamic: true,
 		KeyType: nftables.TypeIPAddr,
 	}
+	if m.family == nftables.TableFamilyIPv6 {
+		ipset.KeyType = nftables.TypeIP6Addr
+	}
 
 	if err := m.rConn.AddSet(ipset, nil); err != nil {
 		return nil, fmt.Errorf("create set: %v", err)
@@ -686,6 +719,14 @@ func (m *AclManager) refreshRuleHandles(chain *nftables.Chain, mangle bool) erro
 	return nil
 }
 
+// rawIP returns the address in the byte representation of the table family
+func (m *AclManager) rawIP(ip net.IP) []byte {
+	if m.family == nftables.TableFamilyIPv6 {
+		return ip.To16()
+	}
+	return ip.To4()
+}
+
 func generatePeerRuleId(ip net.IP, sPort *firewall.Port, dPort *firewall.Port, action firewall.Action, ipset *nftables.Set) string {
 	rulesetID := ":"
 	if sPort != nil {
@@ -41,6 +41,9 @@ type Manager struct {
 
 	router     *router
 	aclManager *AclManager
+
+	// aclManager6 filters the IPv6 overlay traffic in the ip6 netbird table, it is nil if that table can't be created
+	aclManager6 *AclManager
 }
 
 // Create nftables firewall manager
@@ -63,12 +66,17 @@ func Create(wgIface iFaceMapper) (*Manager, error) {
 		return nil, fmt.Errorf("create acl manager: %w", err)
 	}
 
+	workTable6 := &nftables.Table{Name: tableNameNetbird, Family: nftables.TableFamilyIPv6}
+	if m.aclManager6, err = newAclManager(workTable6, wgIface, ""); err != nil {
+		log.Warnf("failed to create IPv6 acl manager: %v", err)
+	}
+
 	return m, nil
 }
 
 // Init nftables firewall manager
 func (m *Manager) Init(stateManager *statemanager.Manager) error {
-	workTable, err := m.createWorkTable()
+	workTable, err := m.createWorkTable(nftables.TableFamilyIPv4)
 	if err != nil {
 		return fmt.Errorf("create work table: %w", err)
 	}
@@ -82,6 +90,13 @@ func (m *Manager) Init(stateManager *statemanager.Manager) error {
 		return fmt.Errorf("acl manager init: %w", err)
 	}
 
+	if m.aclManager6 != nil {
+		if err := m.initIPv6(); err != nil {
+			log.Warnf("failed to init IPv6 acl manager, IPv6 overlay traffic is disabled: %v", err)
+			m.aclManager6 = nil
+		}
+	}
+
 	stateManager.RegisterState(&ShutdownState{})
 
 	// We only need to record minimal interface state for potential recreation.
@@ -108,6 +123,14 @@ func (m *Manager) Init(stateManager *statemanager.Manager) error {
 	return nil
 }
 
+func (m *Manager) initIPv6() error {
+	workTable, err := m.createWorkTable(nftables.TableFamilyIPv6)
+	if err != nil {
+		return fmt.Errorf("create work table: %w", err)
+	}
+	return m.aclManager6.init(workTable)
+}
+
 // AddPeerFiltering rule to the firewall
 //
 // If comment argument is empty firewall manager should set
@@ -126,10 +149,23 @@ func (m *Manager) AddPeerFiltering(
 
 	rawIP := ip.To4()
 	if rawIP == nil {
-		return nil, fmt.Errorf("unsupported IP version: %s", ip.String())
+		if m.aclManager6 == nil || ip.To16() == nil {
+			return nil, fmt.Errorf("unsupported IP version: %s", ip.String())
+		}
+		return m.aclManager6.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
 	}
 
-	return m.aclManager.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
+	rules, err := m.aclManager.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
+	if err != nil || m.aclManager6 == nil || !ip.IsUnspecified() {
+		return rules, err
+	}
+
+	// rules without a peer address apply to both address families
+	rules6, err := m.aclManager6.AddPeerFiltering(id, net.IPv6zero, proto, sPort, dPort, action, ipsetName)
+	if err != nil {
+		return rules, fmt.Errorf("add IPv6 rule: %w", err)
+	}
+	return append(rules, rules6...), nil
 }
 
 func (m *Manager) AddRouteFiltering(
@@ -156,9 +192,18 @@ func (m *Manager) DeletePeerRule(rule firewall.Rule) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if r, ok := rule.(*Rule); ok && m.aclManager6 != nil && r.nftRule != nil && r.nftRule.Table.Family == nftables.TableFamilyIPv6 {
+		return m.aclManager6.DeletePeerRule(rule)
+	}
+
 	return m.aclManager.DeletePeerRule(rule)
 }
 
+// SupportsIPv6 returns true if the IPv6 overlay traffic is filtered
+func (m *Manager) SupportsIPv6() bool {
+	return m.aclManager6 != nil
+}
+
 // DeleteRouteRule deletes a routing rule
 func (m *Manager) DeleteRouteRule(rule firewall.Rule) error {
 	m.mutex.Lock()
@@ -199,7 +244,25 @@ func (m *Manager) AllowNetbird() error {
 		return fmt.Errorf("failed to create default allow rules: %v", err)
 	}
 
-	chains, err := m.rConn.ListChainsOfTableFamily(nftables.TableFamilyIPv4)
+	if err := m.allowNetbirdInFamily(nftables.TableFamilyIPv4); err != nil {
+		return err
+	}
+
+	if m.aclManager6 != nil {
+		if err := m.aclManager6.createDefaultAllowRules(); err != nil {
+			return fmt.Errorf("failed to create default IPv6 allow rules: %v", err)
+		}
+		if err := m.allowNetbirdInFamily(nftables.TableFamilyIPv6); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allowNetbirdInFamily accepts the netbird interface traffic in the INPUT chain of the filter table of the family
+func (m *Manager) allowNetbirdInFamily(family nftables.TableFamily) error {
+	chains, err := m.rConn.ListChainsOfTableFamily(family)
 	if err != nil {
 		return fmt.Errorf("list of chains: %w", err)
 	}
@@ -340,7 +403,13 @@ func (m *Manager) Flush() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	return m.aclManager.Flush()
+	if err := m.aclManager.Flush(); err != nil {
+		return err
+	}
+	if m.aclManager6 != nil {
+		return m.aclManager6.Flush()
+	}
+	return nil
 }
 
 // AddDNATRule adds a DNAT rule
@@ -359,8 +428,8 @@ func (m *Manager) DeleteDNATRule(rule firewall.Rule) error {
 	return m.router.DeleteDNATRule(rule)
 }
 
-func (m *Manager) createWorkTable() (*nftables.Table, error) {
-	tables, err := m.rConn.ListTablesOfFamily(nftables.TableFamilyIPv4)
+func (m *Manager) createWorkTable(family nftables.TableFamily) (*nftables.Table, error) {
+	tables, err := m.rConn.ListTablesOfFamily(family)
 	if err != nil {
 		return nil, fmt.Errorf("list of tables: %w", err)
 	}
@@ -371,7 +440,7 @@ func (m *Manager) createWorkTable() (*nftables.Table, error) {
 		}
 	}
 
-	table := m.rConn.AddTable(&nftables.Table{Name: tableNameNetbird, Family: nftables.TableFamilyIPv4})
+	table := m.rConn.AddTable(&nftables.Table{Name: tableNameNetbird, Family: family})
 	err = m.rConn.Flush()
 	return table, err
 }
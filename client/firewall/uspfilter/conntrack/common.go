@@ -17,6 +17,7 @@ type BaseConnTrack struct {
 	Direction nftypes.Direction
 	SourceIP  netip.Addr
 	DestIP    netip.Addr
+	RuleID    []byte
 	lastSeen  atomic.Int64
 	PacketsTx atomic.Uint64
 	PacketsRx atomic.Uint64
@@ -48,6 +48,7 @@ type ICMPTracker struct {
 	tickerCancel  context.CancelFunc
 	mutex         sync.RWMutex
 	flowLogger    nftypes.FlowLogger
+	limiter       *Limiter
 }
 
 // NewICMPTracker creates a new ICMP connection tracker
@@ -100,16 +101,23 @@ func (t *ICMPTracker) TrackOutbound(srcIP netip.Addr, dstIP netip.Addr, id uint1
 	}
 }
 
-// TrackInbound records an inbound ICMP Echo Request
-func (t *ICMPTracker) TrackInbound(srcIP netip.Addr, dstIP netip.Addr, id uint16, typecode layers.ICMPv4TypeCode, ruleId []byte, size int) {
-	t.track(srcIP, dstIP, id, typecode, nftypes.Ingress, ruleId, size)
+// TrackInbound records an inbound ICMP Echo Request.
+// It returns false if the request is new and exceeds the limits of the tracker, the packet should be dropped then.
+func (t *ICMPTracker) TrackInbound(srcIP netip.Addr, dstIP netip.Addr, id uint16, typecode layers.ICMPv4TypeCode, ruleId []byte, size int) bool {
+	return t.track(srcIP, dstIP, id, typecode, nftypes.Ingress, ruleId, size)
+}
+
+// SetLimiter sets the limiter that is consulted for new inbound echo requests.
+// It must be called before the tracker processes packets.
+func (t *ICMPTracker) SetLimiter(limiter *Limiter) {
+	t.limiter = limiter
 }
 
 // track is the common implementation for tracking both inbound and outbound ICMP connections
-func (t *ICMPTracker) track(srcIP netip.Addr, dstIP netip.Addr, id uint16, typecode layers.ICMPv4TypeCode, direction nftypes.Direction, ruleId []byte, size int) {
+func (t *ICMPTracker) track(srcIP netip.Addr, dstIP netip.Addr, id uint16, typecode layers.ICMPv4TypeCode, direction nftypes.Direction, ruleId []byte, size int) bool {
 	key, exists := t.updateIfExists(srcIP, dstIP, id, direction, size)
 	if exists {
-		return
+		return true
 	}
 
 	typ, code := typecode.Type(), typecode.Code()
@@ -118,7 +126,7 @@ func (t *ICMPTracker) track(srcIP netip.Addr, dstIP netip.Addr, id uint16, typec
 	if typ != uint8(layers.ICMPv4TypeEchoRequest) {
 		t.logger.Trace("New %s ICMP connection %s type %d code %d", direction, key, typ, code)
 		t.sendStartEvent(direction, srcIP, dstIP, typ, code, ruleId, size)
-		return
+		return true
 	}
 
 	conn := &ICMPConnTrack{
@@ -127,6 +135,7 @@ func (t *ICMPTracker) track(srcIP netip.Addr, dstIP netip.Addr, id uint16, typec
 			Direction: direction,
 			SourceIP:  srcIP,
 			DestIP:    dstIP,
+			RuleID:    ruleId,
 		},
 		ICMPType: typ,
 		ICMPCode: code,
@@ -135,11 +144,27 @@ func (t *ICMPTracker) track(srcIP netip.Addr, dstIP netip.Addr, id uint16, typec
 	conn.UpdateCounters(direction, size)
 
 	t.mutex.Lock()
+	// another packet of the same flow may have created the connection since the lookup
+	if _, exists := t.connections[key]; exists {
+		t.mutex.Unlock()
+		t.updateIfExists(srcIP, dstIP, id, direction, size)
+		return true
+	}
+	// the entry is reserved under the lock, so a flow never holds more than one
+	if direction == nftypes.Ingress {
+		if reason, ok := t.limiter.admit(srcIP); !ok {
+			t.mutex.Unlock()
+			t.logger.Trace("Dropping new ICMP connection %s (%s)", key, reason)
+			t.sendDropEvent(srcIP, dstIP, typ, code, ruleId, reason, size)
+			return false
+		}
+	}
 	t.connections[key] = conn
 	t.mutex.Unlock()
 
 	t.logger.Trace("New %s ICMP connection %s type %d code %d", direction, key, typ, code)
 	t.sendEvent(nftypes.TypeStart, conn, ruleId)
+	return true
 }
 
 // IsValidInbound checks if an inbound ICMP Echo Reply matches a tracked request
@@ -187,7 +212,7 @@ func (t *ICMPTracker) cleanup() {
 
 	for key, conn := range t.connections {
 		if conn.timeoutExceeded(t.timeout) {
-			delete(t.connections, key)
+			t.remove(key, conn)
 
 			t.logger.Debug("Removed ICMP connection %s (timeout) [in: %d Pkts/%d B out: %d Pkts/%d B]",
 				key, conn.PacketsRx.Load(), conn.BytesRx.Load(), conn.PacketsTx.Load(), conn.BytesTx.Load())
@@ -196,6 +221,13 @@ func (t *ICMPTracker) cleanup() {
 	}
 }
 
+func (t *ICMPTracker) remove(key ICMPConnKey, conn *ICMPConnTrack) {
+	delete(t.connections, key)
+	if conn.Direction == nftypes.Ingress {
+		t.limiter.release(conn.SourceIP)
+	}
+}
+
 // Close stops the cleanup routine and releases resources
 func (t *ICMPTracker) Close() {
 	t.tickerCancel()
@@ -244,3 +276,20 @@ func (t *ICMPTracker) sendStartEvent(direction nftypes.Direction, srcIP netip.Ad
 	}
 	t.flowLogger.StoreEvent(fields)
 }
+
+func (t *ICMPTracker) sendDropEvent(srcIP netip.Addr, dstIP netip.Addr, typ uint8, code uint8, ruleID []byte, reason nftypes.DropReason, size int) {
+	t.flowLogger.StoreEvent(nftypes.EventFields{
+		FlowID:     uuid.New(),
+		Type:       nftypes.TypeDrop,
+		RuleID:     ruleID,
+		Direction:  nftypes.Ingress,
+		Protocol:   nftypes.ICMP,
+		SourceIP:   srcIP,
+		DestIP:     dstIP,
+		ICMPType:   typ,
+		ICMPCode:   code,
+		RxPackets:  1,
+		RxBytes:    uint64(size),
+		DropReason: reason,
+	})
+}
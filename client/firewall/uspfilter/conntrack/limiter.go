@@ -0,0 +1,143 @@
+package conntrack
+
+import (
+	"net/netip"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+const (
+	// DefaultMaxEntries is the default cap of inbound connections tracked across all sources
+	DefaultMaxEntries = 65536
+	// DefaultMaxEntriesPerSource is the default cap of inbound connections tracked per source IP
+	DefaultMaxEntriesPerSource = 4096
+)
+
+// Limits configures the caps for inbound connections. Zero values disable the respective limit.
+type Limits struct {
+	// MaxEntries caps the number of tracked inbound connections across all sources
+	MaxEntries int
+	// MaxEntriesPerSource caps the number of tracked inbound connections per source IP
+	MaxEntriesPerSource int
+	// NewConnRate is the number of new connections per second a single source may open
+	NewConnRate rate.Limit
+	// NewConnBurst is the number of new connections a single source may open at once
+	NewConnBurst int
+}
+
+type sourceState struct {
+	entries int
+	bucket  *rate.Limiter
+}
+
+// Limiter enforces Limits on connections initiated by remote sources.
+// It is shared by the TCP, UDP and ICMP trackers, a nil Limiter admits everything.
+type Limiter struct {
+	limits  Limits
+	mu      sync.Mutex
+	entries int
+	sources map[netip.Addr]*sourceState
+}
+
+// NewLimiter creates a limiter enforcing the given limits
+func NewLimiter(limits Limits) *Limiter {
+	if limits.NewConnRate > 0 && limits.NewConnBurst <= 0 {
+		limits.NewConnBurst = max(1, int(limits.NewConnRate))
+	}
+
+	return &Limiter{
+		limits:  limits,
+		sources: make(map[netip.Addr]*sourceState),
+	}
+}
+
+// AllowNew reports whether the source may open another connection according to its new-connection rate.
+// It doesn't reserve an entry, it is meant for connections that are not tracked by the conntrack tables.
+func (l *Limiter) AllowNew(src netip.Addr) bool {
+	if l == nil || l.limits.NewConnRate <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := l.source(src)
+	allowed := state.bucket.Allow()
+	l.prune(src, state)
+	return allowed
+}
+
+// admit reserves an entry for a new connection initiated by src
+func (l *Limiter) admit(src netip.Addr) (nftypes.DropReason, bool) {
+	if l == nil {
+		return nftypes.DropReasonUnknown, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limits.MaxEntries > 0 && l.entries >= l.limits.MaxEntries {
+		return nftypes.DropReasonConntrackLimit, false
+	}
+
+	state := l.source(src)
+	defer l.prune(src, state)
+
+	if l.limits.MaxEntriesPerSource > 0 && state.entries >= l.limits.MaxEntriesPerSource {
+		return nftypes.DropReasonConntrackLimit, false
+	}
+
+	if state.bucket != nil && !state.bucket.Allow() {
+		return nftypes.DropReasonRateLimit, false
+	}
+
+	state.entries++
+	l.entries++
+
+	return nftypes.DropReasonUnknown, true
+}
+
+// release frees the entry of a connection initiated by src
+func (l *Limiter) release(src netip.Addr) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.sources[src]
+	if !ok || state.entries == 0 {
+		return
+	}
+
+	state.entries--
+	l.entries--
+	l.prune(src, state)
+}
+
+func (l *Limiter) source(src netip.Addr) *sourceState {
+	state, ok := l.sources[src]
+	if !ok {
+		state = &sourceState{}
+		if l.limits.NewConnRate > 0 {
+			state.bucket = rate.NewLimiter(l.limits.NewConnRate, l.limits.NewConnBurst)
+		}
+		l.sources[src] = state
+	}
+	return state
+}
+
+// prune drops the state of sources that hold no entries and have a full bucket, they are indistinguishable from new sources
+func (l *Limiter) prune(src netip.Addr, state *sourceState) {
+	if state.entries > 0 {
+		return
+	}
+	if state.bucket != nil && state.bucket.Tokens() < float64(state.bucket.Burst()) {
+		return
+	}
+	delete(l.sources, src)
+}
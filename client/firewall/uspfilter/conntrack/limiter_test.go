@@ -0,0 +1,160 @@
+package conntrack
+
+import (
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func TestLimiter_PerSourceCap(t *testing.T) {
+	limiter := NewLimiter(Limits{MaxEntriesPerSource: 2})
+
+	tracker := NewTCPTracker(DefaultTCPTimeout, logger, flowLogger)
+	defer tracker.Close()
+	tracker.SetLimiter(limiter)
+
+	peerA := netip.MustParseAddr("100.64.0.10")
+	peerB := netip.MustParseAddr("100.64.0.20")
+	local := netip.MustParseAddr("100.64.0.1")
+
+	assert.True(t, tracker.TrackInbound(peerA, local, 40000, 22, TCPSyn, nil, 60))
+	assert.True(t, tracker.TrackInbound(peerA, local, 40001, 22, TCPSyn, nil, 60))
+	assert.False(t, tracker.TrackInbound(peerA, local, 40002, 22, TCPSyn, nil, 60), "third connection from the same source should exceed the cap")
+
+	// packets of already tracked connections are not affected
+	assert.True(t, tracker.TrackInbound(peerA, local, 40000, 22, TCPAck, nil, 60))
+
+	// other sources have their own budget
+	assert.True(t, tracker.TrackInbound(peerB, local, 40000, 22, TCPSyn, nil, 60))
+
+	// outbound connections are not limited
+	for port := uint16(50000); port < 50005; port++ {
+		tracker.TrackOutbound(local, peerA, port, 80, TCPSyn, 60)
+	}
+	assert.Len(t, tracker.List(Filter{IP: peerA}), 7)
+
+	// removing entries frees the budget
+	assert.Equal(t, 1, tracker.Flush(Filter{IP: peerA, Port: 40001}))
+	assert.True(t, tracker.TrackInbound(peerA, local, 40002, 22, TCPSyn, nil, 60))
+}
+
+func TestLimiter_GlobalCapSharedAcrossTrackers(t *testing.T) {
+	limiter := NewLimiter(Limits{MaxEntries: 2})
+
+	tcpTracker := NewTCPTracker(DefaultTCPTimeout, logger, flowLogger)
+	defer tcpTracker.Close()
+	tcpTracker.SetLimiter(limiter)
+
+	udpTracker := NewUDPTracker(DefaultUDPTimeout, logger, flowLogger)
+	defer udpTracker.Close()
+	udpTracker.SetLimiter(limiter)
+
+	local := netip.MustParseAddr("100.64.0.1")
+
+	assert.True(t, tcpTracker.TrackInbound(netip.MustParseAddr("100.64.0.10"), local, 40000, 22, TCPSyn, nil, 60))
+	assert.True(t, udpTracker.TrackInbound(netip.MustParseAddr("100.64.0.11"), local, 40000, 53, nil, 60))
+	assert.False(t, udpTracker.TrackInbound(netip.MustParseAddr("100.64.0.12"), local, 40000, 53, nil, 60))
+
+	assert.Equal(t, 1, udpTracker.Flush(Filter{}))
+	assert.True(t, udpTracker.TrackInbound(netip.MustParseAddr("100.64.0.12"), local, 40000, 53, nil, 60))
+}
+
+func TestLimiter_NewConnRate(t *testing.T) {
+	limiter := NewLimiter(Limits{NewConnRate: 0.001, NewConnBurst: 3})
+	src := netip.MustParseAddr("100.64.0.10")
+
+	for i := 0; i < 3; i++ {
+		_, ok := limiter.admit(src)
+		require.True(t, ok)
+	}
+
+	reason, ok := limiter.admit(src)
+	assert.False(t, ok)
+	assert.Equal(t, nftypes.DropReasonRateLimit, reason)
+	assert.False(t, limiter.AllowNew(src))
+
+	assert.True(t, limiter.AllowNew(netip.MustParseAddr("100.64.0.20")))
+}
+
+func TestLimiter_Nil(t *testing.T) {
+	var limiter *Limiter
+	src := netip.MustParseAddr("100.64.0.10")
+
+	_, ok := limiter.admit(src)
+	assert.True(t, ok)
+	assert.True(t, limiter.AllowNew(src))
+	limiter.release(src)
+}
+
+func TestLimiter_ReleasePrunesSources(t *testing.T) {
+	limiter := NewLimiter(Limits{MaxEntriesPerSource: 10})
+	src := netip.MustParseAddr("100.64.0.10")
+
+	_, ok := limiter.admit(src)
+	require.True(t, ok)
+	assert.Len(t, limiter.sources, 1)
+
+	limiter.release(src)
+	assert.Empty(t, limiter.sources)
+	assert.Zero(t, limiter.entries)
+}
+
+func TestLimiter_ConcurrentPacketsOfNewFlow(t *testing.T) {
+	limiter := NewLimiter(Limits{MaxEntriesPerSource: 100})
+
+	tcpTracker := NewTCPTracker(DefaultTCPTimeout, logger, flowLogger)
+	defer tcpTracker.Close()
+	tcpTracker.SetLimiter(limiter)
+
+	udpTracker := NewUDPTracker(DefaultUDPTimeout, logger, flowLogger)
+	defer udpTracker.Close()
+	udpTracker.SetLimiter(limiter)
+
+	icmpTracker := NewICMPTracker(DefaultICMPTimeout, logger, flowLogger)
+	defer icmpTracker.Close()
+	icmpTracker.SetLimiter(limiter)
+
+	local := netip.MustParseAddr("100.64.0.1")
+	peer := netip.MustParseAddr("100.64.0.10")
+	echoRequest := layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0)
+
+	// raceNewFlow holds the limiter while the packets of a new flow pile up, so all of them race to create the connection
+	raceNewFlow := func(track func() bool) {
+		var wg sync.WaitGroup
+		limiter.mu.Lock()
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.True(t, track())
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		limiter.mu.Unlock()
+		wg.Wait()
+	}
+
+	raceNewFlow(func() bool { return tcpTracker.TrackInbound(peer, local, 40000, 22, TCPSyn, nil, 60) })
+	raceNewFlow(func() bool { return udpTracker.TrackInbound(peer, local, 40000, 53, nil, 60) })
+	raceNewFlow(func() bool { return icmpTracker.TrackInbound(peer, local, 1, echoRequest, nil, 60) })
+
+	limiter.mu.Lock()
+	assert.Equal(t, 3, limiter.entries, "every flow should hold a single entry")
+	limiter.mu.Unlock()
+
+	assert.Equal(t, 1, tcpTracker.Flush(Filter{}))
+	assert.Equal(t, 1, udpTracker.Flush(Filter{}))
+	assert.Equal(t, 1, icmpTracker.Flush(Filter{}))
+
+	limiter.mu.Lock()
+	assert.Zero(t, limiter.entries, "flushing the flows should free all entries")
+	assert.Empty(t, limiter.sources, "sources without entries should be dropped")
+	limiter.mu.Unlock()
+}
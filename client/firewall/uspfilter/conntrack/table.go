@@ -0,0 +1,206 @@
+package conntrack
+
+import (
+	"net/netip"
+	"time"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// Filter selects connection tracking entries. Zero values match everything.
+type Filter struct {
+	Protocol nftypes.Protocol
+	// IP matches either the source or the destination address
+	IP netip.Addr
+	// Port matches either the source or the destination port
+	Port uint16
+}
+
+func (f Filter) matches(protocol nftypes.Protocol, srcIP, dstIP netip.Addr, srcPort, dstPort uint16) bool {
+	if f.Protocol != nftypes.ProtocolUnknown && f.Protocol != protocol {
+		return false
+	}
+
+	if f.IP.IsValid() {
+		ip := f.IP.Unmap()
+		if srcIP.Unmap() != ip && dstIP.Unmap() != ip {
+			return false
+		}
+	}
+
+	if f.Port != 0 && srcPort != f.Port && dstPort != f.Port {
+		return false
+	}
+
+	return true
+}
+
+// Entry is a point-in-time snapshot of a tracked connection
+type Entry struct {
+	Protocol   nftypes.Protocol
+	Direction  nftypes.Direction
+	SourceIP   netip.Addr
+	DestIP     netip.Addr
+	SourcePort uint16
+	DestPort   uint16
+	ICMPType   uint8
+	ICMPCode   uint8
+	ICMPID     uint16
+	State      string
+	RuleID     []byte
+	LastSeen   time.Time
+	// Timeout is the idle timeout that applies to the connection in its current state
+	Timeout   time.Duration
+	PacketsTx uint64
+	PacketsRx uint64
+	BytesTx   uint64
+	BytesRx   uint64
+}
+
+// ExpiresIn returns the time left until the connection is considered stale
+func (e Entry) ExpiresIn() time.Duration {
+	remaining := e.Timeout - time.Since(e.LastSeen)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func newEntry(protocol nftypes.Protocol, b *BaseConnTrack, srcPort, dstPort uint16, state string, timeout time.Duration) Entry {
+	return Entry{
+		Protocol:   protocol,
+		Direction:  b.Direction,
+		SourceIP:   b.SourceIP,
+		DestIP:     b.DestIP,
+		SourcePort: srcPort,
+		DestPort:   dstPort,
+		State:      state,
+		RuleID:     b.RuleID,
+		LastSeen:   b.GetLastSeen(),
+		Timeout:    timeout,
+		PacketsTx:  b.PacketsTx.Load(),
+		PacketsRx:  b.PacketsRx.Load(),
+		BytesTx:    b.BytesTx.Load(),
+		BytesRx:    b.BytesRx.Load(),
+	}
+}
+
+// List returns a snapshot of all TCP connections matching the filter
+func (t *TCPTracker) List(filter Filter) []Entry {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var entries []Entry
+	for key, conn := range t.connections {
+		if !filter.matches(nftypes.TCP, key.SrcIP, key.DstIP, key.SrcPort, key.DstPort) {
+			continue
+		}
+
+		conn.RLock()
+		state := conn.State
+		conn.RUnlock()
+
+		entries = append(entries, newEntry(nftypes.TCP, &conn.BaseConnTrack, conn.SourcePort, conn.DestPort, state.String(), t.connTimeout(conn, state)))
+	}
+	return entries
+}
+
+// Flush removes all TCP connections matching the filter and returns the number of removed entries
+func (t *TCPTracker) Flush(filter Filter) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var flushed int
+	for key, conn := range t.connections {
+		if !filter.matches(nftypes.TCP, key.SrcIP, key.DstIP, key.SrcPort, key.DstPort) {
+			continue
+		}
+		t.remove(key, conn)
+		flushed++
+
+		conn.RLock()
+		state := conn.State
+		conn.RUnlock()
+
+		t.logger.Trace("Flushed TCP connection %s", key)
+
+		// event already handled by state change
+		if !conn.IsTombstone() && state != TCPStateTimeWait {
+			t.sendEvent(nftypes.TypeEnd, conn, nil)
+		}
+	}
+	return flushed
+}
+
+// List returns a snapshot of all UDP connections matching the filter
+func (t *UDPTracker) List(filter Filter) []Entry {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var entries []Entry
+	for key, conn := range t.connections {
+		if !filter.matches(nftypes.UDP, key.SrcIP, key.DstIP, key.SrcPort, key.DstPort) {
+			continue
+		}
+		entries = append(entries, newEntry(nftypes.UDP, &conn.BaseConnTrack, conn.SourcePort, conn.DestPort, "", t.timeout))
+	}
+	return entries
+}
+
+// Flush removes all UDP connections matching the filter and returns the number of removed entries
+func (t *UDPTracker) Flush(filter Filter) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var flushed int
+	for key, conn := range t.connections {
+		if !filter.matches(nftypes.UDP, key.SrcIP, key.DstIP, key.SrcPort, key.DstPort) {
+			continue
+		}
+		t.remove(key, conn)
+		flushed++
+
+		t.logger.Trace("Flushed UDP connection %s", key)
+		t.sendEvent(nftypes.TypeEnd, conn, nil)
+	}
+	return flushed
+}
+
+// List returns a snapshot of all ICMP connections matching the filter.
+// ICMP connections have no ports, a port filter never matches them.
+func (t *ICMPTracker) List(filter Filter) []Entry {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var entries []Entry
+	for key, conn := range t.connections {
+		if !filter.matches(nftypes.ICMP, key.SrcIP, key.DstIP, 0, 0) {
+			continue
+		}
+		entry := newEntry(nftypes.ICMP, &conn.BaseConnTrack, 0, 0, "", t.timeout)
+		entry.ICMPType = conn.ICMPType
+		entry.ICMPCode = conn.ICMPCode
+		entry.ICMPID = key.ID
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Flush removes all ICMP connections matching the filter and returns the number of removed entries
+func (t *ICMPTracker) Flush(filter Filter) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var flushed int
+	for key, conn := range t.connections {
+		if !filter.matches(nftypes.ICMP, key.SrcIP, key.DstIP, 0, 0) {
+			continue
+		}
+		t.remove(key, conn)
+		flushed++
+
+		t.logger.Trace("Flushed ICMP connection %s", key)
+		t.sendEvent(nftypes.TypeEnd, conn, nil)
+	}
+	return flushed
+}
@@ -0,0 +1,91 @@
+package conntrack
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func TestTCPTracker_ListAndFlush(t *testing.T) {
+	tracker := NewTCPTracker(DefaultTCPTimeout, logger, flowLogger)
+	defer tracker.Close()
+
+	peerA := netip.MustParseAddr("100.64.0.10")
+	peerB := netip.MustParseAddr("100.64.0.20")
+	local := netip.MustParseAddr("100.64.0.1")
+	ruleID := []byte("rule-1")
+
+	tracker.TrackInbound(peerA, local, 40000, 22, TCPSyn, ruleID, 60)
+	tracker.TrackInbound(peerB, local, 40001, 443, TCPSyn, nil, 60)
+	tracker.TrackOutbound(local, peerB, 50000, 80, TCPSyn, 60)
+
+	entries := tracker.List(Filter{})
+	require.Len(t, entries, 3)
+
+	entries = tracker.List(Filter{IP: peerA})
+	require.Len(t, entries, 1)
+	assert.Equal(t, nftypes.TCP, entries[0].Protocol)
+	assert.Equal(t, nftypes.Ingress, entries[0].Direction)
+	assert.Equal(t, uint16(22), entries[0].DestPort)
+	assert.Equal(t, TCPStateSynSent.String(), entries[0].State)
+	assert.Equal(t, ruleID, entries[0].RuleID)
+	assert.Equal(t, TCPHandshakeTimeout, entries[0].Timeout)
+	assert.Equal(t, uint64(1), entries[0].PacketsRx)
+	assert.Equal(t, uint64(60), entries[0].BytesRx)
+
+	assert.Len(t, tracker.List(Filter{Port: 443}), 1)
+	assert.Len(t, tracker.List(Filter{IP: peerB}), 2)
+	assert.Empty(t, tracker.List(Filter{Protocol: nftypes.UDP}))
+
+	assert.Equal(t, 2, tracker.Flush(Filter{IP: peerB}))
+	entries = tracker.List(Filter{})
+	require.Len(t, entries, 1)
+	assert.Equal(t, peerA, entries[0].SourceIP)
+
+	assert.Equal(t, 1, tracker.Flush(Filter{}))
+	assert.Empty(t, tracker.List(Filter{}))
+}
+
+func TestUDPTracker_ListAndFlush(t *testing.T) {
+	tracker := NewUDPTracker(DefaultUDPTimeout, logger, flowLogger)
+	defer tracker.Close()
+
+	local := netip.MustParseAddr("100.64.0.1")
+	peer := netip.MustParseAddr("100.64.0.10")
+
+	tracker.TrackOutbound(local, peer, 50000, 53, 100)
+	tracker.TrackOutbound(local, peer, 50001, 123, 100)
+
+	entries := tracker.List(Filter{Port: 53})
+	require.Len(t, entries, 1)
+	assert.Equal(t, nftypes.UDP, entries[0].Protocol)
+	assert.Equal(t, DefaultUDPTimeout, entries[0].Timeout)
+	assert.Equal(t, uint64(100), entries[0].BytesTx)
+	assert.Greater(t, entries[0].ExpiresIn(), DefaultUDPTimeout/2)
+
+	assert.Equal(t, 1, tracker.Flush(Filter{Port: 123}))
+	assert.Len(t, tracker.List(Filter{}), 1)
+	assert.Equal(t, 0, tracker.Flush(Filter{IP: netip.MustParseAddr("100.64.0.99")}))
+}
+
+func TestICMPTracker_ListIgnoresPortFilter(t *testing.T) {
+	tracker := NewICMPTracker(DefaultICMPTimeout, logger, flowLogger)
+	defer tracker.Close()
+
+	local := netip.MustParseAddr("100.64.0.1")
+	peer := netip.MustParseAddr("100.64.0.10")
+
+	tracker.TrackOutbound(local, peer, 7, 0x0800, 64)
+
+	entries := tracker.List(Filter{IP: peer})
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint16(7), entries[0].ICMPID)
+	assert.Equal(t, uint8(8), entries[0].ICMPType)
+
+	assert.Empty(t, tracker.List(Filter{Port: 53}))
+	assert.Equal(t, 1, tracker.Flush(Filter{Protocol: nftypes.ICMP}))
+}
@@ -126,6 +126,7 @@ type TCPTracker struct {
 	tickerCancel  context.CancelFunc
 	timeout       time.Duration
 	flowLogger    nftypes.FlowLogger
+	limiter       *Limiter
 }
 
 // NewTCPTracker creates a new TCP connection tracker
@@ -182,16 +183,23 @@ func (t *TCPTracker) TrackOutbound(srcIP netip.Addr, dstIP netip.Addr, srcPort u
 	}
 }
 
-// TrackInbound processes an inbound TCP packet and updates connection state
-func (t *TCPTracker) TrackInbound(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, flags uint8, ruleID []byte, size int) {
-	t.track(srcIP, dstIP, srcPort, dstPort, flags, nftypes.Ingress, ruleID, size)
+// TrackInbound processes an inbound TCP packet and updates connection state.
+// It returns false if the connection is new and exceeds the limits of the tracker, the packet should be dropped then.
+func (t *TCPTracker) TrackInbound(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, flags uint8, ruleID []byte, size int) bool {
+	return t.track(srcIP, dstIP, srcPort, dstPort, flags, nftypes.Ingress, ruleID, size)
+}
+
+// SetLimiter sets the limiter that is consulted for new inbound connections.
+// It must be called before the tracker processes packets.
+func (t *TCPTracker) SetLimiter(limiter *Limiter) {
+	t.limiter = limiter
 }
 
 // track is the common implementation for tracking both inbound and outbound connections
-func (t *TCPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, flags uint8, direction nftypes.Direction, ruleID []byte, size int) {
+func (t *TCPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, flags uint8, direction nftypes.Direction, ruleID []byte, size int) bool {
 	key, exists := t.updateIfExists(srcIP, dstIP, srcPort, dstPort, flags, direction, size)
 	if exists {
-		return
+		return true
 	}
 
 	conn := &TCPConnTrack{
@@ -200,6 +208,7 @@ func (t *TCPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, d
 			Direction: direction,
 			SourceIP:  srcIP,
 			DestIP:    dstIP,
+			RuleID:    ruleID,
 		},
 		SourcePort: srcPort,
 		DestPort:   dstPort,
@@ -213,10 +222,26 @@ func (t *TCPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, d
 	conn.UpdateCounters(direction, size)
 
 	t.mutex.Lock()
+	// another packet of the same flow may have created the connection since the lookup
+	if _, exists := t.connections[key]; exists {
+		t.mutex.Unlock()
+		t.updateIfExists(srcIP, dstIP, srcPort, dstPort, flags, direction, size)
+		return true
+	}
+	// the entry is reserved under the lock, so a flow never holds more than one
+	if direction == nftypes.Ingress {
+		if reason, ok := t.limiter.admit(srcIP); !ok {
+			t.mutex.Unlock()
+			t.logger.Trace("Dropping new TCP connection %s (%s)", key, reason)
+			t.sendDropEvent(key, ruleID, reason, size)
+			return false
+		}
+	}
 	t.connections[key] = conn
 	t.mutex.Unlock()
 
 	t.sendEvent(nftypes.TypeStart, conn, ruleID)
+	return true
 }
 
 // IsValidInbound checks if an inbound TCP packet matches a tracked connection
@@ -425,23 +450,12 @@ func (t *TCPTracker) cleanup() {
 	for key, conn := range t.connections {
 		if conn.IsTombstone() {
 			// Clean up tombstoned connections without sending an event
-			delete(t.connections, key)
+			t.remove(key, conn)
 			continue
 		}
 
-		var timeout time.Duration
-		switch {
-		case conn.State == TCPStateTimeWait:
-			timeout = TimeWaitTimeout
-		case conn.IsEstablished():
-			timeout = t.timeout
-		default:
-			timeout = TCPHandshakeTimeout
-		}
-
-		if conn.timeoutExceeded(timeout) {
-			// Return IPs to pool
-			delete(t.connections, key)
+		if conn.timeoutExceeded(t.connTimeout(conn, conn.State)) {
+			t.remove(key, conn)
 
 			t.logger.Trace("Cleaned up timed-out TCP connection %s", key)
 
@@ -453,6 +467,25 @@ func (t *TCPTracker) cleanup() {
 	}
 }
 
+func (t *TCPTracker) remove(key ConnKey, conn *TCPConnTrack) {
+	delete(t.connections, key)
+	if conn.Direction == nftypes.Ingress {
+		t.limiter.release(conn.SourceIP)
+	}
+}
+
+// connTimeout returns the idle timeout for a connection in the given state
+func (t *TCPTracker) connTimeout(conn *TCPConnTrack, state TCPState) time.Duration {
+	switch {
+	case state == TCPStateTimeWait:
+		return TimeWaitTimeout
+	case conn.IsEstablished():
+		return t.timeout
+	default:
+		return TCPHandshakeTimeout
+	}
+}
+
 // Close stops the cleanup routine and releases resources
 func (t *TCPTracker) Close() {
 	t.tickerCancel()
@@ -494,3 +527,20 @@ func (t *TCPTracker) sendEvent(typ nftypes.Type, conn *TCPConnTrack, ruleID []by
 		TxBytes:    conn.BytesTx.Load(),
 	})
 }
+
+func (t *TCPTracker) sendDropEvent(key ConnKey, ruleID []byte, reason nftypes.DropReason, size int) {
+	t.flowLogger.StoreEvent(nftypes.EventFields{
+		FlowID:     uuid.New(),
+		Type:       nftypes.TypeDrop,
+		RuleID:     ruleID,
+		Direction:  nftypes.Ingress,
+		Protocol:   nftypes.TCP,
+		SourceIP:   key.SrcIP,
+		DestIP:     key.DstIP,
+		SourcePort: key.SrcPort,
+		DestPort:   key.DstPort,
+		RxPackets:  1,
+		RxBytes:    uint64(size),
+		DropReason: reason,
+	})
+}
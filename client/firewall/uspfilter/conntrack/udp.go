@@ -35,6 +35,7 @@ type UDPTracker struct {
 	tickerCancel  context.CancelFunc
 	mutex         sync.RWMutex
 	flowLogger    nftypes.FlowLogger
+	limiter       *Limiter
 }
 
 // NewUDPTracker creates a new UDP connection tracker
@@ -66,9 +67,16 @@ func (t *UDPTracker) TrackOutbound(srcIP netip.Addr, dstIP netip.Addr, srcPort u
 	}
 }
 
-// TrackInbound records an inbound UDP connection
-func (t *UDPTracker) TrackInbound(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, ruleID []byte, size int) {
-	t.track(srcIP, dstIP, srcPort, dstPort, nftypes.Ingress, ruleID, size)
+// TrackInbound records an inbound UDP connection.
+// It returns false if the connection is new and exceeds the limits of the tracker, the packet should be dropped then.
+func (t *UDPTracker) TrackInbound(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, ruleID []byte, size int) bool {
+	return t.track(srcIP, dstIP, srcPort, dstPort, nftypes.Ingress, ruleID, size)
+}
+
+// SetLimiter sets the limiter that is consulted for new inbound connections.
+// It must be called before the tracker processes packets.
+func (t *UDPTracker) SetLimiter(limiter *Limiter) {
+	t.limiter = limiter
 }
 
 func (t *UDPTracker) updateIfExists(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, direction nftypes.Direction, size int) (ConnKey, bool) {
@@ -93,10 +101,10 @@ func (t *UDPTracker) updateIfExists(srcIP netip.Addr, dstIP netip.Addr, srcPort
 }
 
 // track is the common implementation for tracking both inbound and outbound connections
-func (t *UDPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, direction nftypes.Direction, ruleID []byte, size int) {
+func (t *UDPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, dstPort uint16, direction nftypes.Direction, ruleID []byte, size int) bool {
 	key, exists := t.updateIfExists(srcIP, dstIP, srcPort, dstPort, direction, size)
 	if exists {
-		return
+		return true
 	}
 
 	conn := &UDPConnTrack{
@@ -105,6 +113,7 @@ func (t *UDPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, d
 			Direction: direction,
 			SourceIP:  srcIP,
 			DestIP:    dstIP,
+			RuleID:    ruleID,
 		},
 		SourcePort: srcPort,
 		DestPort:   dstPort,
@@ -113,11 +122,27 @@ func (t *UDPTracker) track(srcIP netip.Addr, dstIP netip.Addr, srcPort uint16, d
 	conn.UpdateCounters(direction, size)
 
 	t.mutex.Lock()
+	// another packet of the same flow may have created the connection since the lookup
+	if _, exists := t.connections[key]; exists {
+		t.mutex.Unlock()
+		t.updateIfExists(srcIP, dstIP, srcPort, dstPort, direction, size)
+		return true
+	}
+	// the entry is reserved under the lock, so a flow never holds more than one
+	if direction == nftypes.Ingress {
+		if reason, ok := t.limiter.admit(srcIP); !ok {
+			t.mutex.Unlock()
+			t.logger.Trace("Dropping new UDP connection %s (%s)", key, reason)
+			t.sendDropEvent(key, ruleID, reason, size)
+			return false
+		}
+	}
 	t.connections[key] = conn
 	t.mutex.Unlock()
 
 	t.logger.Trace("New %s UDP connection: %s", direction, key)
 	t.sendEvent(nftypes.TypeStart, conn, ruleID)
+	return true
 }
 
 // IsValidInbound checks if an inbound packet matches a tracked connection
@@ -163,7 +188,7 @@ func (t *UDPTracker) cleanup() {
 
 	for key, conn := range t.connections {
 		if conn.timeoutExceeded(t.timeout) {
-			delete(t.connections, key)
+			t.remove(key, conn)
 
 			t.logger.Trace("Removed UDP connection %s (timeout) [in: %d Pkts/%d B out: %d Pkts/%d B]",
 				key, conn.PacketsRx.Load(), conn.BytesRx.Load(), conn.PacketsTx.Load(), conn.BytesTx.Load())
@@ -172,6 +197,13 @@ func (t *UDPTracker) cleanup() {
 	}
 }
 
+func (t *UDPTracker) remove(key ConnKey, conn *UDPConnTrack) {
+	delete(t.connections, key)
+	if conn.Direction == nftypes.Ingress {
+		t.limiter.release(conn.SourceIP)
+	}
+}
+
 // Close stops the cleanup routine and releases resources
 func (t *UDPTracker) Close() {
 	t.tickerCancel()
@@ -218,3 +250,20 @@ func (t *UDPTracker) sendEvent(typ nftypes.Type, conn *UDPConnTrack, ruleID []by
 		TxBytes:    conn.BytesTx.Load(),
 	})
 }
+
+func (t *UDPTracker) sendDropEvent(key ConnKey, ruleID []byte, reason nftypes.DropReason, size int) {
+	t.flowLogger.StoreEvent(nftypes.EventFields{
+		FlowID:     uuid.New(),
+		Type:       nftypes.TypeDrop,
+		RuleID:     ruleID,
+		Direction:  nftypes.Ingress,
+		Protocol:   nftypes.UDP,
+		SourceIP:   key.SrcIP,
+		DestIP:     key.DstIP,
+		SourcePort: key.SrcPort,
+		DestPort:   key.DstPort,
+		RxPackets:  1,
+		RxBytes:    uint64(size),
+		DropReason: reason,
+	})
+}
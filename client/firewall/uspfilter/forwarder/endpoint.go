@@ -17,6 +17,8 @@ type endpoint struct {
 	dispatcher stack.NetworkDispatcher
 	device     *wgdevice.Device
 	mtu        uint32
+	// synProxy translates the connections established with SYN cookies, nil if cookies are disabled
+	synProxy *synProxy
 }
 
 func (e *endpoint) Attach(dispatcher stack.NetworkDispatcher) {
@@ -46,17 +48,17 @@ func (e *endpoint) LinkAddress() tcpip.LinkAddress {
 func (e *endpoint) WritePackets(pkts stack.PacketBufferList) (int, tcpip.Error) {
 	var written int
 	for _, pkt := range pkts.AsSlice() {
-		netHeader := header.IPv4(pkt.NetworkHeader().View().AsSlice())
-
 		data := stack.PayloadSince(pkt.NetworkHeader())
 		if data == nil {
 			continue
 		}
 
-		// Send the packet through WireGuard
-		address := netHeader.DestinationAddress()
-		err := e.device.CreateOutboundPacket(data.AsSlice(), address.AsSlice())
-		if err != nil {
+		if !e.synProxy.handleOutbound(data.AsSlice()) {
+			written++
+			continue
+		}
+
+		if err := e.writePacket(data.AsSlice()); err != nil {
 			e.logger.Error("CreateOutboundPacket: %v", err)
 			continue
 		}
@@ -66,6 +68,12 @@ func (e *endpoint) WritePackets(pkts stack.PacketBufferList) (int, tcpip.Error)
 	return written, nil
 }
 
+// writePacket sends the IPv4 packet through WireGuard
+func (e *endpoint) writePacket(packet []byte) error {
+	address := header.IPv4(packet).DestinationAddress()
+	return e.device.CreateOutboundPacket(packet, address.AsSlice())
+}
+
 func (e *endpoint) Wait() {
 	// not required
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"runtime"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gvisor.dev/gvisor/pkg/buffer"
@@ -26,6 +27,8 @@ const (
 	defaultMaxInFlight   = 1024
 	iosReceiveWindow     = 16384
 	iosMaxInFlight       = 256
+
+	synProxyCleanupInterval = 30 * time.Second
 )
 
 type Forwarder struct {
@@ -34,6 +37,8 @@ type Forwarder struct {
 	stack        *stack.Stack
 	endpoint     *endpoint
 	udpForwarder *udpForwarder
+	halfOpen     *halfOpenLimiter
+	synProxy     *synProxy
 	ctx          context.Context
 	cancel       context.CancelFunc
 	ip           net.IP
@@ -101,6 +106,18 @@ func New(iface common.IFaceMapper, logger *nblog.Logger, flowLogger nftypes.Flow
 		},
 	})
 
+	receiveWindow := defaultReceiveWindow
+	maxInFlight := defaultMaxInFlight
+	if runtime.GOOS == "ios" {
+		receiveWindow = iosReceiveWindow
+		maxInFlight = iosMaxInFlight
+	}
+
+	synProxy, err := newSynProxy(logger, uint32(mtu), receiveWindow)
+	if err != nil {
+		return nil, fmt.Errorf("create SYN proxy: %w", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	f := &Forwarder{
 		logger:       logger,
@@ -108,17 +125,24 @@ func New(iface common.IFaceMapper, logger *nblog.Logger, flowLogger nftypes.Flow
 		stack:        s,
 		endpoint:     endpoint,
 		udpForwarder: newUDPForwarder(mtu, logger, flowLogger),
-		ctx:          ctx,
-		cancel:       cancel,
-		netstack:     netstack,
-		ip:           iface.Address().IP,
+		// answer with cookies before the netstack forwarder runs out of in-flight slots
+		halfOpen: newHalfOpenLimiter(maxInFlight / 2),
+		synProxy: synProxy,
+		ctx:      ctx,
+		cancel:   cancel,
+		netstack: netstack,
+		ip:       iface.Address().IP,
 	}
 
-	receiveWindow := defaultReceiveWindow
-	maxInFlight := defaultMaxInFlight
-	if runtime.GOOS == "ios" {
-		receiveWindow = iosReceiveWindow
-		maxInFlight = iosMaxInFlight
+	if synProxy != nil {
+		synProxy.overloaded = f.halfOpen.exceeded
+		synProxy.hasEndpoint = func(key flowKey) bool {
+			return s.FindTransportEndpoint(ipv4.ProtocolNumber, tcp.ProtocolNumber, key.endpointID(), nicID) != nil
+		}
+		synProxy.sendOut = endpoint.writePacket
+		synProxy.deliverIn = f.deliver
+		endpoint.synProxy = synProxy
+		go f.cleanupSynProxy()
 	}
 
 	tcpForwarder := tcp.NewForwarder(s, receiveWindow, maxInFlight, f.handleTCP)
@@ -138,6 +162,16 @@ func (f *Forwarder) InjectIncomingPacket(payload []byte) error {
 		return fmt.Errorf("packet too small: %d bytes", len(payload))
 	}
 
+	if !f.synProxy.handleInbound(payload) {
+		return nil
+	}
+
+	f.deliver(payload)
+	return nil
+}
+
+// deliver passes the packet to the netstack
+func (f *Forwarder) deliver(payload []byte) {
 	pkt := stack.NewPacketBuffer(stack.PacketBufferOptions{
 		Payload: buffer.MakeWithData(payload),
 	})
@@ -146,7 +180,20 @@ func (f *Forwarder) InjectIncomingPacket(payload []byte) error {
 	if f.endpoint.dispatcher != nil {
 		f.endpoint.dispatcher.DeliverNetworkPacket(ipv4.ProtocolNumber, pkt)
 	}
-	return nil
+}
+
+func (f *Forwarder) cleanupSynProxy() {
+	ticker := time.NewTicker(synProxyCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case now := <-ticker.C:
+			f.synProxy.cleanup(now)
+		}
+	}
 }
 
 // Stop gracefully shuts down the forwarder
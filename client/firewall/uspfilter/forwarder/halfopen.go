@@ -0,0 +1,84 @@
+package forwarder
+
+import (
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EnvMaxHalfOpenPerSource caps the TCP handshakes a single source may have in progress, 0 disables the cap.
+	EnvMaxHalfOpenPerSource = "NB_FORWARDER_MAX_HALF_OPEN_PER_SOURCE"
+
+	defaultMaxHalfOpenPerSource = 128
+)
+
+// halfOpenLimiter caps the in-progress handshakes per source.
+// The netstack forwarder dials the destination before it answers the SYN, so without a cap a single peer could occupy
+// all in-flight slots with spoofed or unanswered SYNs. SYNs above the per-source cap, or above the total cap of
+// in-progress handshakes, are answered with SYN cookies when they are enabled.
+type halfOpenLimiter struct {
+	mu       sync.Mutex
+	max      int
+	maxTotal int
+	total    int
+	inFlight map[netip.Addr]int
+}
+
+func newHalfOpenLimiter(maxTotal int) *halfOpenLimiter {
+	limit := defaultMaxHalfOpenPerSource
+	if val := os.Getenv(EnvMaxHalfOpenPerSource); val != "" {
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed < 0 {
+			log.Warnf("failed to parse %s: invalid value %q", EnvMaxHalfOpenPerSource, val)
+		} else {
+			limit = parsed
+		}
+	}
+
+	return &halfOpenLimiter{
+		max:      limit,
+		maxTotal: maxTotal,
+		inFlight: make(map[netip.Addr]int),
+	}
+}
+
+// exceeded reports whether another handshake of the source would exceed its cap or the cap across all sources
+func (l *halfOpenLimiter) exceeded(src netip.Addr) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.max > 0 && l.inFlight[src] >= l.max || l.maxTotal > 0 && l.total >= l.maxTotal
+}
+
+// acquire reserves a handshake slot for the source, it returns false if the source exceeds its cap
+func (l *halfOpenLimiter) acquire(src netip.Addr) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && l.inFlight[src] >= l.max {
+		return false
+	}
+	l.inFlight[src]++
+	l.total++
+	return true
+}
+
+// release frees a handshake slot of the source
+func (l *halfOpenLimiter) release(src netip.Addr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.inFlight[src]; !ok {
+		return
+	}
+	l.total--
+	if l.inFlight[src] <= 1 {
+		delete(l.inFlight, src)
+		return
+	}
+	l.inFlight[src]--
+}
@@ -0,0 +1,49 @@
+package forwarder
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHalfOpenLimiter(t *testing.T) {
+	t.Setenv(EnvMaxHalfOpenPerSource, "2")
+	limiter := newHalfOpenLimiter(3)
+
+	peerA := netip.MustParseAddr("100.64.0.10")
+	peerB := netip.MustParseAddr("100.64.0.20")
+
+	require.True(t, limiter.acquire(peerA))
+	assert.False(t, limiter.exceeded(peerA))
+	require.True(t, limiter.acquire(peerA))
+	assert.True(t, limiter.exceeded(peerA), "source at its cap should be answered with cookies")
+	assert.False(t, limiter.acquire(peerA), "source above its cap should be refused")
+
+	assert.False(t, limiter.exceeded(peerB))
+	require.True(t, limiter.acquire(peerB))
+	assert.True(t, limiter.exceeded(peerB), "sources should be answered with cookies at the total cap")
+
+	limiter.release(peerA)
+	assert.False(t, limiter.exceeded(peerA))
+	assert.False(t, limiter.exceeded(peerB))
+
+	limiter.release(peerA)
+	limiter.release(peerB)
+	// releasing a source without handshakes is a no-op
+	limiter.release(peerB)
+	assert.Empty(t, limiter.inFlight)
+	assert.Zero(t, limiter.total)
+}
+
+func TestHalfOpenLimiter_Disabled(t *testing.T) {
+	t.Setenv(EnvMaxHalfOpenPerSource, "0")
+	limiter := newHalfOpenLimiter(0)
+
+	peer := netip.MustParseAddr("100.64.0.10")
+	for i := 0; i < 1000; i++ {
+		require.True(t, limiter.acquire(peer))
+	}
+	assert.False(t, limiter.exceeded(peer))
+}
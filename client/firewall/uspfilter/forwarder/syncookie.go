@@ -0,0 +1,477 @@
+package forwarder
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gvisor.dev/gvisor/pkg/tcpip"
+	"gvisor.dev/gvisor/pkg/tcpip/checksum"
+	"gvisor.dev/gvisor/pkg/tcpip/header"
+	"gvisor.dev/gvisor/pkg/tcpip/stack"
+
+	nblog "github.com/netbirdio/netbird/client/firewall/uspfilter/log"
+)
+
+const (
+	// EnvDisableSynCookies disables answering SYNs above the half-open cap with SYN cookies, they are dropped instead.
+	EnvDisableSynCookies = "NB_FORWARDER_DISABLE_SYN_COOKIES"
+
+	// cookieCounterPeriod is the time slot encoded in a cookie, cookies of the current and previous slots are accepted
+	cookieCounterPeriod = 64 * time.Second
+	maxCookieAge        = 2
+
+	// proxiedHandshakeTimeout is the time the netstack has to answer the SYN of a validated cookie
+	proxiedHandshakeTimeout = 30 * time.Second
+	// proxiedClosedTimeout keeps the translation of closed connections for retransmitted FINs
+	proxiedClosedTimeout = 2 * time.Minute
+	// proxiedIdleTimeout drops the translation of connections without any traffic
+	proxiedIdleTimeout = 3 * time.Hour
+)
+
+// cookieMSS are the MSS values a cookie can encode, the client's MSS is rounded down to one of them
+var cookieMSS = [8]uint16{536, 1200, 1220, 1280, 1360, 1400, 1440, 1460}
+
+// flowKey identifies a proxied connection by the client and the forwarded destination
+type flowKey struct {
+	src netip.AddrPort
+	dst netip.AddrPort
+}
+
+func flowKeyFromID(id stack.TransportEndpointID) flowKey {
+	return flowKey{
+		src: netip.AddrPortFrom(netip.AddrFrom4(id.RemoteAddress.As4()), id.RemotePort),
+		dst: netip.AddrPortFrom(netip.AddrFrom4(id.LocalAddress.As4()), id.LocalPort),
+	}
+}
+
+func (k flowKey) endpointID() stack.TransportEndpointID {
+	return stack.TransportEndpointID{
+		LocalPort:     k.dst.Port(),
+		LocalAddress:  tcpip.AddrFrom4(k.dst.Addr().As4()),
+		RemotePort:    k.src.Port(),
+		RemoteAddress: tcpip.AddrFrom4(k.src.Addr().As4()),
+	}
+}
+
+// proxiedFlow is a connection whose handshake was completed with a SYN cookie.
+// The netstack picked its own initial sequence number, so the sequence numbers are translated for the whole connection.
+type proxiedFlow struct {
+	clientISN uint32
+	cookie    uint32
+	// delta is the netstack's initial sequence number minus the cookie
+	delta       uint32
+	established bool
+	// pending is the ACK that carried the cookie, it completes the netstack's handshake
+	pending  []byte
+	finIn    bool
+	finOut   bool
+	lastSeen time.Time
+	closedAt time.Time
+}
+
+// synProxy answers SYNs with SYN cookies when the forwarder is overloaded.
+// The netstack forwarder dials the destination before it answers a SYN and holds an in-flight slot meanwhile,
+// with cookies a handshake only reaches the netstack once the client proved it owns its address.
+type synProxy struct {
+	logger *nblog.Logger
+	secret []byte
+	mss    uint16
+	window uint16
+
+	// overloaded reports whether a SYN of the source should be answered with a cookie
+	overloaded func(src netip.Addr) bool
+	// hasEndpoint reports whether the netstack already handles the connection
+	hasEndpoint func(key flowKey) bool
+	// sendOut writes a packet to the peer
+	sendOut func(packet []byte) error
+	// deliverIn injects a packet into the netstack
+	deliverIn func(packet []byte)
+
+	// lastCookie is the time the latest cookie was sent, ACKs are only checked for cookies shortly after
+	lastCookie atomic.Int64
+
+	mu    sync.Mutex
+	flows map[flowKey]*proxiedFlow
+}
+
+func newSynProxy(logger *nblog.Logger, mtu uint32, window int) (*synProxy, error) {
+	if val := os.Getenv(EnvDisableSynCookies); val != "" {
+		if disabled, err := strconv.ParseBool(val); err == nil && disabled {
+			logger.Info("forwarder: SYN cookies disabled")
+			return nil, nil //nolint:nilnil
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate cookie secret: %w", err)
+	}
+
+	return &synProxy{
+		logger: logger,
+		secret: secret,
+		mss:    uint16(mtu - header.IPv4MinimumSize - header.TCPMinimumSize),
+		window: uint16(min(window, 0xffff)),
+		flows:  make(map[flowKey]*proxiedFlow),
+	}, nil
+}
+
+// handleInbound processes a packet of a peer before the netstack sees it.
+// It returns false if the packet was consumed and must not be delivered to the netstack.
+func (p *synProxy) handleInbound(packet []byte) bool {
+	if p == nil {
+		return true
+	}
+
+	ip, tcp, ok := parseTCP(packet)
+	if !ok {
+		return true
+	}
+
+	key := flowKey{
+		src: netip.AddrPortFrom(netip.AddrFrom4(ip.SourceAddress().As4()), tcp.SourcePort()),
+		dst: netip.AddrPortFrom(netip.AddrFrom4(ip.DestinationAddress().As4()), tcp.DestinationPort()),
+	}
+	flags := tcp.Flags()
+
+	p.mu.Lock()
+	flow, exists := p.flows[key]
+	if exists {
+		defer p.mu.Unlock()
+		return p.translateInbound(key, flow, tcp, flags)
+	}
+	p.mu.Unlock()
+
+	switch {
+	case flags&(header.TCPFlagSyn|header.TCPFlagAck|header.TCPFlagRst) == header.TCPFlagSyn:
+		if !p.overloaded(key.src.Addr()) {
+			return true
+		}
+		p.answerSyn(key, ip, tcp)
+		return false
+
+	case flags&(header.TCPFlagSyn|header.TCPFlagAck|header.TCPFlagRst) == header.TCPFlagAck:
+		if !p.cookiesActive() || p.hasEndpoint(key) {
+			return true
+		}
+		return !p.acceptCookie(key, packet, tcp)
+	}
+
+	return true
+}
+
+// handleOutbound processes a packet of the netstack before it is sent to the peer.
+// It returns false if the packet was consumed and must not be sent.
+func (p *synProxy) handleOutbound(packet []byte) bool {
+	if p == nil {
+		return true
+	}
+
+	ip, tcp, ok := parseTCP(packet)
+	if !ok {
+		return true
+	}
+
+	key := flowKey{
+		src: netip.AddrPortFrom(netip.AddrFrom4(ip.DestinationAddress().As4()), tcp.DestinationPort()),
+		dst: netip.AddrPortFrom(netip.AddrFrom4(ip.SourceAddress().As4()), tcp.SourcePort()),
+	}
+	flags := tcp.Flags()
+
+	p.mu.Lock()
+	flow, exists := p.flows[key]
+	if !exists {
+		p.mu.Unlock()
+		return true
+	}
+
+	if flow.established {
+		defer p.mu.Unlock()
+
+		setSequenceNumber(tcp, tcp.SequenceNumber()-flow.delta)
+		p.track(key, flow, flags, &flow.finOut)
+		return true
+	}
+
+	switch {
+	case flags.Contains(header.TCPFlagSyn | header.TCPFlagAck):
+		// complete the netstack's handshake with the client's ACK
+		flow.delta = tcp.SequenceNumber() - flow.cookie
+		flow.established = true
+		flow.lastSeen = time.Now()
+		pending := flow.pending
+		flow.pending = nil
+		_, pendingTCP, _ := parseTCP(pending)
+		setAckNumber(pendingTCP, pendingTCP.AckNumber()+flow.delta)
+		p.mu.Unlock()
+
+		p.logger.Trace("forwarder: netstack accepted SYN cookie connection %s -> %s", key.src, key.dst)
+		// the netstack is writing the SYN-ACK, deliver outside of its write path
+		go p.deliverIn(pending)
+		return false
+
+	case flags.Contains(header.TCPFlagRst):
+		// the destination refused the connection, the client expects the sequence number of the cookie
+		delete(p.flows, key)
+		p.mu.Unlock()
+
+		setSequenceNumber(tcp, flow.cookie+1)
+		return true
+	}
+
+	p.mu.Unlock()
+	return false
+}
+
+// isProxied reports whether the connection was established with a SYN cookie
+func (p *synProxy) isProxied(key flowKey) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, exists := p.flows[key]
+	return exists
+}
+
+// cleanup drops the translation of stale connections
+func (p *synProxy) cleanup(now time.Time) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, flow := range p.flows {
+		switch {
+		case !flow.established && now.Sub(flow.lastSeen) > proxiedHandshakeTimeout,
+			!flow.closedAt.IsZero() && now.Sub(flow.closedAt) > proxiedClosedTimeout,
+			now.Sub(flow.lastSeen) > proxiedIdleTimeout:
+			delete(p.flows, key)
+		}
+	}
+}
+
+// translateInbound maps the acknowledgements of the client to the netstack's sequence numbers, p.mu must be held
+func (p *synProxy) translateInbound(key flowKey, flow *proxiedFlow, tcp header.TCP, flags header.TCPFlags) bool {
+	if !flow.established {
+		// the client retransmits once the netstack accepted the connection
+		if flags.Contains(header.TCPFlagRst) {
+			delete(p.flows, key)
+		}
+		return false
+	}
+
+	if flags.Contains(header.TCPFlagAck) {
+		setAckNumber(tcp, tcp.AckNumber()+flow.delta)
+	}
+	p.track(key, flow, flags, &flow.finIn)
+	return true
+}
+
+// track updates the state of an established flow with a packet of either direction, p.mu must be held
+func (p *synProxy) track(key flowKey, flow *proxiedFlow, flags header.TCPFlags, fin *bool) {
+	now := time.Now()
+	flow.lastSeen = now
+
+	if flags.Contains(header.TCPFlagRst) {
+		delete(p.flows, key)
+		return
+	}
+	if flags.Contains(header.TCPFlagFin) {
+		*fin = true
+	}
+	if flow.finIn && flow.finOut && flow.closedAt.IsZero() {
+		flow.closedAt = now
+	}
+}
+
+// answerSyn sends a SYN-ACK carrying a cookie without keeping any state
+func (p *synProxy) answerSyn(key flowKey, ip header.IPv4, tcp header.TCP) {
+	now := time.Now()
+	p.lastCookie.Store(now.UnixNano())
+
+	clientMSS := header.ParseSynOptions(tcp.Options(), false).MSS
+	mssIdx := uint8(0)
+	for i, mss := range cookieMSS {
+		if mss <= clientMSS && mss <= p.mss {
+			mssIdx = uint8(i)
+		}
+	}
+
+	clientISN := tcp.SequenceNumber()
+	cookie := p.cookie(key, clientISN, cookieCounter(now), mssIdx)
+
+	synAck := buildTCP(ip.DestinationAddress(), ip.SourceAddress(), &header.TCPFields{
+		SrcPort:    key.dst.Port(),
+		DstPort:    key.src.Port(),
+		SeqNum:     cookie,
+		AckNum:     clientISN + 1,
+		Flags:      header.TCPFlagSyn | header.TCPFlagAck,
+		WindowSize: p.window,
+	}, p.mss)
+
+	p.logger.Trace("forwarder: answering SYN of %s -> %s with a cookie", key.src, key.dst)
+	if err := p.sendOut(synAck); err != nil {
+		p.logger.Error("forwarder: failed to send SYN cookie: %v", err)
+	}
+}
+
+// acceptCookie checks whether the ACK completes a cookie handshake and opens the connection in the netstack.
+// It returns true if the ACK was consumed.
+func (p *synProxy) acceptCookie(key flowKey, packet []byte, tcp header.TCP) bool {
+	clientISN := tcp.SequenceNumber() - 1
+	cookie := tcp.AckNumber() - 1
+
+	mssIdx, ok := p.checkCookie(key, clientISN, cookie, time.Now())
+	if !ok {
+		return false
+	}
+
+	p.mu.Lock()
+	if _, exists := p.flows[key]; exists {
+		p.mu.Unlock()
+		return false
+	}
+	p.flows[key] = &proxiedFlow{
+		clientISN: clientISN,
+		cookie:    cookie,
+		pending:   append([]byte(nil), packet...),
+		lastSeen:  time.Now(),
+	}
+	p.mu.Unlock()
+
+	syn := buildTCP(
+		tcpip.AddrFrom4(key.src.Addr().As4()),
+		tcpip.AddrFrom4(key.dst.Addr().As4()),
+		&header.TCPFields{
+			SrcPort:    key.src.Port(),
+			DstPort:    key.dst.Port(),
+			SeqNum:     clientISN,
+			Flags:      header.TCPFlagSyn,
+			WindowSize: tcp.WindowSize(),
+		},
+		cookieMSS[mssIdx],
+	)
+
+	p.logger.Trace("forwarder: valid SYN cookie for %s -> %s", key.src, key.dst)
+	p.deliverIn(syn)
+	return true
+}
+
+func (p *synProxy) cookiesActive() bool {
+	return time.Since(time.Unix(0, p.lastCookie.Load())) < maxCookieAge*cookieCounterPeriod
+}
+
+// cookie encodes the time slot and the MSS index in the top bits and authenticates them with the connection in the rest
+func (p *synProxy) cookie(key flowKey, clientISN, counter uint32, mssIdx uint8) uint32 {
+	var buf [4 + 2 + 4 + 2 + 4 + 4 + 1]byte
+	src, dst := key.src.Addr().As4(), key.dst.Addr().As4()
+	copy(buf[0:], src[:])
+	binary.BigEndian.PutUint16(buf[4:], key.src.Port())
+	copy(buf[6:], dst[:])
+	binary.BigEndian.PutUint16(buf[10:], key.dst.Port())
+	binary.BigEndian.PutUint32(buf[12:], clientISN)
+	binary.BigEndian.PutUint32(buf[16:], counter)
+	buf[20] = mssIdx
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	return (counter&0x1f)<<27 | uint32(mssIdx&0x7)<<24 | binary.BigEndian.Uint32(sum)&0xffffff
+}
+
+// checkCookie returns the MSS index of the cookie if it is valid for the connection
+func (p *synProxy) checkCookie(key flowKey, clientISN, cookie uint32, now time.Time) (uint8, bool) {
+	current := cookieCounter(now)
+	mssIdx := uint8(cookie>>24) & 0x7
+
+	for age := uint32(0); age < maxCookieAge; age++ {
+		counter := current - age
+		if counter&0x1f != cookie>>27 {
+			continue
+		}
+		if p.cookie(key, clientISN, counter, mssIdx) == cookie {
+			return mssIdx, true
+		}
+	}
+
+	return 0, false
+}
+
+func cookieCounter(now time.Time) uint32 {
+	return uint32(now.Unix() / int64(cookieCounterPeriod/time.Second))
+}
+
+// parseTCP returns the headers of an unfragmented IPv4 TCP packet
+func parseTCP(packet []byte) (header.IPv4, header.TCP, bool) {
+	ip := header.IPv4(packet)
+	if !ip.IsValid(len(packet)) || ip.TransportProtocol() != header.TCPProtocolNumber || ip.More() || ip.FragmentOffset() != 0 {
+		return nil, nil, false
+	}
+
+	tcp := header.TCP(packet[ip.HeaderLength():ip.TotalLength()])
+	if len(tcp) < header.TCPMinimumSize || int(tcp.DataOffset()) < header.TCPMinimumSize || int(tcp.DataOffset()) > len(tcp) {
+		return nil, nil, false
+	}
+
+	return ip, tcp, true
+}
+
+// buildTCP creates an IPv4 TCP packet without payload announcing the MSS
+func buildTCP(src, dst tcpip.Address, fields *header.TCPFields, mss uint16) []byte {
+	const tcpLen = header.TCPMinimumSize + header.TCPOptionMSSLength
+	packet := make([]byte, header.IPv4MinimumSize+tcpLen)
+
+	ip := header.IPv4(packet)
+	ip.Encode(&header.IPv4Fields{
+		TotalLength: uint16(len(packet)),
+		TTL:         64,
+		Protocol:    uint8(header.TCPProtocolNumber),
+		SrcAddr:     src,
+		DstAddr:     dst,
+	})
+	ip.SetChecksum(^ip.CalculateChecksum())
+
+	tcp := header.TCP(packet[header.IPv4MinimumSize:])
+	fields.DataOffset = tcpLen
+	tcp.Encode(fields)
+	header.EncodeMSSOption(uint32(mss), tcp[header.TCPMinimumSize:])
+
+	xsum := header.PseudoHeaderChecksum(header.TCPProtocolNumber, src, dst, tcpLen)
+	tcp.SetChecksum(^checksum.Checksum(tcp, xsum))
+
+	return packet
+}
+
+func setSequenceNumber(tcp header.TCP, seq uint32) {
+	tcp.SetChecksum(adjustChecksum(tcp.Checksum(), tcp.SequenceNumber(), seq))
+	tcp.SetSequenceNumber(seq)
+}
+
+func setAckNumber(tcp header.TCP, ack uint32) {
+	tcp.SetChecksum(adjustChecksum(tcp.Checksum(), tcp.AckNumber(), ack))
+	tcp.SetAckNumber(ack)
+}
+
+// adjustChecksum incrementally updates a checksum for a changed 32-bit field (RFC 1624)
+func adjustChecksum(xsum uint16, old, updated uint32) uint16 {
+	sum := checksum.Combine(^xsum, ^uint16(old>>16))
+	sum = checksum.Combine(sum, ^uint16(old))
+	sum = checksum.Combine(sum, uint16(updated>>16))
+	sum = checksum.Combine(sum, uint16(updated))
+	return ^sum
+}
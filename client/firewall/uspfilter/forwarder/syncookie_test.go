@@ -0,0 +1,340 @@
+package forwarder
+
+import (
+	"context"
+	"io"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gvisor.dev/gvisor/pkg/buffer"
+	"gvisor.dev/gvisor/pkg/tcpip"
+	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
+	"gvisor.dev/gvisor/pkg/tcpip/checksum"
+	"gvisor.dev/gvisor/pkg/tcpip/header"
+	"gvisor.dev/gvisor/pkg/tcpip/link/channel"
+	"gvisor.dev/gvisor/pkg/tcpip/network/ipv4"
+	"gvisor.dev/gvisor/pkg/tcpip/stack"
+	"gvisor.dev/gvisor/pkg/tcpip/transport/tcp"
+	"gvisor.dev/gvisor/pkg/waiter"
+
+	nblog "github.com/netbirdio/netbird/client/firewall/uspfilter/log"
+)
+
+const refusedPort = 444
+
+func newTestSynProxy(t *testing.T) *synProxy {
+	t.Helper()
+
+	p, err := newSynProxy(nblog.NewFromLogrus(logrus.StandardLogger()), 1500, 65535)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	return p
+}
+
+func TestSynProxy_Cookie(t *testing.T) {
+	p := newTestSynProxy(t)
+	key := flowKey{
+		src: netip.MustParseAddrPort("100.64.0.10:40000"),
+		dst: netip.MustParseAddrPort("10.0.0.1:443"),
+	}
+	now := time.Now()
+	cookie := p.cookie(key, 1000, cookieCounter(now), 6)
+
+	mssIdx, ok := p.checkCookie(key, 1000, cookie, now)
+	require.True(t, ok)
+	assert.Equal(t, uint8(6), mssIdx)
+
+	_, ok = p.checkCookie(key, 1000, cookie, now.Add(cookieCounterPeriod))
+	assert.True(t, ok, "cookie of the previous time slot should be accepted")
+
+	_, ok = p.checkCookie(key, 1000, cookie, now.Add(maxCookieAge*cookieCounterPeriod))
+	assert.False(t, ok, "expired cookie should be rejected")
+
+	_, ok = p.checkCookie(key, 1001, cookie, now)
+	assert.False(t, ok, "cookie of another initial sequence number should be rejected")
+
+	other := key
+	other.src = netip.MustParseAddrPort("100.64.0.10:40001")
+	_, ok = p.checkCookie(other, 1000, cookie, now)
+	assert.False(t, ok, "cookie of another connection should be rejected")
+
+	_, ok = newTestSynProxy(t).checkCookie(key, 1000, cookie, now)
+	assert.False(t, ok, "cookie of another secret should be rejected")
+}
+
+func TestSynProxy_TranslationKeepsChecksum(t *testing.T) {
+	packet := testPacket(
+		netip.MustParseAddrPort("100.64.0.10:40000"),
+		netip.MustParseAddrPort("10.0.0.1:443"),
+		1000, 2000, header.TCPFlagAck|header.TCPFlagPsh, []byte("payload"),
+	)
+	_, tcpHdr, ok := parseTCP(packet)
+	require.True(t, ok)
+
+	setSequenceNumber(tcpHdr, 0xfffffff0)
+	setAckNumber(tcpHdr, 12345)
+
+	assert.Equal(t, uint32(0xfffffff0), tcpHdr.SequenceNumber())
+	assert.Equal(t, uint32(12345), tcpHdr.AckNumber())
+	assert.True(t, validTCPChecksum(packet))
+}
+
+func TestSynProxy_PassesThroughWhenNotOverloaded(t *testing.T) {
+	p := newTestSynProxy(t)
+	p.overloaded = func(netip.Addr) bool { return false }
+	p.hasEndpoint = func(flowKey) bool { return false }
+
+	client := netip.MustParseAddrPort("100.64.0.10:40000")
+	server := netip.MustParseAddrPort("10.0.0.1:443")
+
+	assert.True(t, p.handleInbound(testPacket(client, server, 1000, 0, header.TCPFlagSyn, nil)))
+	assert.True(t, p.handleInbound(testPacket(client, server, 1001, 2000, header.TCPFlagAck, nil)), "ACKs aren't checked before a cookie was sent")
+}
+
+func TestSynProxy_Handshake(t *testing.T) {
+	p, accepted, toClient := setupSynProxyStack(t)
+
+	client := netip.MustParseAddrPort("100.64.0.10:40000")
+	server := netip.MustParseAddrPort("10.0.0.1:443")
+	const clientISN = 1000
+
+	require.False(t, p.handleInbound(testPacket(client, server, clientISN, 0, header.TCPFlagSyn, nil)), "SYN should be answered by the proxy")
+
+	synAck := receivePacket(t, toClient)
+	_, synAckTCP, ok := parseTCP(synAck)
+	require.True(t, ok)
+	require.Equal(t, header.TCPFlagSyn|header.TCPFlagAck, synAckTCP.Flags())
+	require.Equal(t, uint32(clientISN+1), synAckTCP.AckNumber())
+	require.True(t, validTCPChecksum(synAck))
+	assert.Empty(t, p.flows, "no state should be kept before the client completed the handshake")
+	cookie := synAckTCP.SequenceNumber()
+
+	assert.True(t, p.handleInbound(testPacket(client, server, clientISN+1, cookie+2, header.TCPFlagAck, nil)), "invalid cookie should be passed to the netstack")
+	assert.Empty(t, p.flows)
+
+	// the client completes the handshake and sends data right away
+	require.False(t, p.handleInbound(testPacket(client, server, clientISN+1, cookie+1, header.TCPFlagAck|header.TCPFlagPsh, []byte("hello"))),
+		"ACK with a valid cookie should be held until the netstack accepted the connection")
+
+	var conn *gonet.TCPConn
+	select {
+	case conn = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("netstack didn't accept the connection")
+	}
+	defer conn.Close()
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 5)
+	_, err := io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+
+	_, err = conn.Write([]byte("world"))
+	require.NoError(t, err)
+
+	for {
+		packet := receivePacket(t, toClient)
+		_, tcpHdr, ok := parseTCP(packet)
+		require.True(t, ok)
+		require.True(t, validTCPChecksum(packet))
+		require.Equal(t, clientISN+1+uint32(len("hello")), tcpHdr.AckNumber())
+		if len(tcpHdr.Payload()) == 0 {
+			continue
+		}
+
+		assert.Equal(t, cookie+1, tcpHdr.SequenceNumber(), "sequence numbers should continue from the cookie")
+		assert.Equal(t, "world", string(tcpHdr.Payload()))
+		break
+	}
+
+	// the acknowledgements of the client are translated to the netstack's sequence numbers
+	clientAck := testPacket(client, server, clientISN+6, cookie+6, header.TCPFlagAck, nil)
+	require.True(t, p.handleInbound(clientAck))
+	_, tcpHdr, _ := parseTCP(clientAck)
+	assert.Equal(t, cookie+6+p.flows[flowKey{src: client, dst: server}].delta, tcpHdr.AckNumber())
+	assert.True(t, validTCPChecksum(clientAck))
+
+	require.True(t, p.handleInbound(testPacket(client, server, clientISN+6, cookie+6, header.TCPFlagRst, nil)))
+	assert.Empty(t, p.flows, "reset should drop the translation")
+}
+
+func TestSynProxy_RefusedConnection(t *testing.T) {
+	p, _, toClient := setupSynProxyStack(t)
+
+	client := netip.MustParseAddrPort("100.64.0.10:40000")
+	server := netip.MustParseAddrPort("10.0.0.1:444")
+	const clientISN = 1000
+
+	require.False(t, p.handleInbound(testPacket(client, server, clientISN, 0, header.TCPFlagSyn, nil)))
+	_, synAckTCP, _ := parseTCP(receivePacket(t, toClient))
+	cookie := synAckTCP.SequenceNumber()
+
+	require.False(t, p.handleInbound(testPacket(client, server, clientISN+1, cookie+1, header.TCPFlagAck, nil)))
+
+	rst := receivePacket(t, toClient)
+	_, rstTCP, ok := parseTCP(rst)
+	require.True(t, ok)
+	assert.True(t, rstTCP.Flags().Contains(header.TCPFlagRst))
+	assert.Equal(t, cookie+1, rstTCP.SequenceNumber(), "reset should match the sequence number the client expects")
+	assert.True(t, validTCPChecksum(rst))
+	assert.Empty(t, p.flows)
+}
+
+func TestSynProxy_Cleanup(t *testing.T) {
+	p := newTestSynProxy(t)
+	now := time.Now()
+
+	pending := flowKey{src: netip.MustParseAddrPort("100.64.0.10:40000"), dst: netip.MustParseAddrPort("10.0.0.1:443")}
+	closed := flowKey{src: netip.MustParseAddrPort("100.64.0.10:40001"), dst: netip.MustParseAddrPort("10.0.0.1:443")}
+	active := flowKey{src: netip.MustParseAddrPort("100.64.0.10:40002"), dst: netip.MustParseAddrPort("10.0.0.1:443")}
+
+	p.flows[pending] = &proxiedFlow{lastSeen: now}
+	p.flows[closed] = &proxiedFlow{established: true, lastSeen: now, closedAt: now}
+	p.flows[active] = &proxiedFlow{established: true, lastSeen: now}
+
+	p.cleanup(now.Add(proxiedHandshakeTimeout + time.Second))
+	assert.NotContains(t, p.flows, pending)
+	assert.Contains(t, p.flows, closed)
+
+	p.cleanup(now.Add(proxiedClosedTimeout + time.Second))
+	assert.NotContains(t, p.flows, closed)
+	assert.Contains(t, p.flows, active)
+
+	p.cleanup(now.Add(proxiedIdleTimeout + time.Second))
+	assert.Empty(t, p.flows)
+}
+
+// setupSynProxyStack wires a proxy to a netstack with a TCP forwarder accepting every connection except to the refused port
+func setupSynProxyStack(t *testing.T) (*synProxy, <-chan *gonet.TCPConn, <-chan []byte) {
+	t.Helper()
+
+	s := stack.New(stack.Options{
+		NetworkProtocols:   []stack.NetworkProtocolFactory{ipv4.NewProtocol},
+		TransportProtocols: []stack.TransportProtocolFactory{tcp.NewProtocol},
+	})
+	link := channel.New(256, 1500, "")
+	const nicID = tcpip.NICID(1)
+	require.Nil(t, s.CreateNIC(nicID, link))
+	require.Nil(t, s.SetPromiscuousMode(nicID, true))
+	require.Nil(t, s.SetSpoofing(nicID, true))
+	s.SetRouteTable([]tcpip.Route{{Destination: header.IPv4EmptySubnet, NIC: nicID}})
+
+	accepted := make(chan *gonet.TCPConn, 1)
+	forwarder := tcp.NewForwarder(s, 0, 10, func(r *tcp.ForwarderRequest) {
+		if r.ID().LocalPort == refusedPort {
+			r.Complete(true)
+			return
+		}
+
+		var wq waiter.Queue
+		ep, err := r.CreateEndpoint(&wq)
+		if err != nil {
+			r.Complete(true)
+			return
+		}
+		r.Complete(false)
+		accepted <- gonet.NewTCPConn(&wq, ep)
+	})
+	s.SetTransportProtocolHandler(tcp.ProtocolNumber, forwarder.HandlePacket)
+
+	p := newTestSynProxy(t)
+	toClient := make(chan []byte, 64)
+	p.overloaded = func(netip.Addr) bool { return true }
+	p.hasEndpoint = func(key flowKey) bool {
+		return s.FindTransportEndpoint(ipv4.ProtocolNumber, tcp.ProtocolNumber, key.endpointID(), nicID) != nil
+	}
+	p.sendOut = func(packet []byte) error {
+		toClient <- packet
+		return nil
+	}
+	p.deliverIn = func(packet []byte) {
+		pkt := stack.NewPacketBuffer(stack.PacketBufferOptions{Payload: buffer.MakeWithData(packet)})
+		defer pkt.DecRef()
+		link.InjectInbound(ipv4.ProtocolNumber, pkt)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for {
+			pkt := link.ReadContext(ctx)
+			if pkt == nil {
+				return
+			}
+			view := stack.PayloadSince(pkt.NetworkHeader())
+			packet := append([]byte(nil), view.AsSlice()...)
+			view.Release()
+			pkt.DecRef()
+
+			if p.handleOutbound(packet) {
+				toClient <- packet
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		s.Close()
+		s.Wait()
+	})
+
+	return p, accepted, toClient
+}
+
+func testPacket(src, dst netip.AddrPort, seq, ack uint32, flags header.TCPFlags, payload []byte) []byte {
+	tcpLen := header.TCPMinimumSize + len(payload)
+	packet := make([]byte, header.IPv4MinimumSize+tcpLen)
+	srcAddr, dstAddr := tcpip.AddrFrom4(src.Addr().As4()), tcpip.AddrFrom4(dst.Addr().As4())
+
+	ip := header.IPv4(packet)
+	ip.Encode(&header.IPv4Fields{
+		TotalLength: uint16(len(packet)),
+		TTL:         64,
+		Protocol:    uint8(header.TCPProtocolNumber),
+		SrcAddr:     srcAddr,
+		DstAddr:     dstAddr,
+	})
+	ip.SetChecksum(^ip.CalculateChecksum())
+
+	tcpHdr := header.TCP(packet[header.IPv4MinimumSize:])
+	tcpHdr.Encode(&header.TCPFields{
+		SrcPort:    src.Port(),
+		DstPort:    dst.Port(),
+		SeqNum:     seq,
+		AckNum:     ack,
+		DataOffset: header.TCPMinimumSize,
+		Flags:      flags,
+		WindowSize: 65535,
+	})
+	copy(tcpHdr[header.TCPMinimumSize:], payload)
+
+	xsum := header.PseudoHeaderChecksum(header.TCPProtocolNumber, srcAddr, dstAddr, uint16(tcpLen))
+	tcpHdr.SetChecksum(^checksum.Checksum(tcpHdr, xsum))
+
+	return packet
+}
+
+func validTCPChecksum(packet []byte) bool {
+	ip, tcpHdr, ok := parseTCP(packet)
+	if !ok {
+		return false
+	}
+	xsum := header.PseudoHeaderChecksum(header.TCPProtocolNumber, ip.SourceAddress(), ip.DestinationAddress(), uint16(len(tcpHdr)))
+	return checksum.Checksum(tcpHdr, xsum) == 0xffff
+}
+
+func receivePacket(t *testing.T, packets <-chan []byte) []byte {
+	t.Helper()
+
+	select {
+	case packet := <-packets:
+		return packet
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for a packet to the client")
+		return nil
+	}
+}
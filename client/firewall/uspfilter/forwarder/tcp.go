@@ -21,6 +21,20 @@ import (
 func (f *Forwarder) handleTCP(r *tcp.ForwarderRequest) {
 	id := r.ID()
 
+	// TODO: handle ipv6
+	src := netip.AddrFrom4(id.RemoteAddress.As4())
+	// the clients of connections established with a SYN cookie already completed the handshake
+	if !f.synProxy.isProxied(flowKeyFromID(id)) {
+		if !f.halfOpen.acquire(src) {
+			// drop silently, the peer will retransmit the SYN
+			r.Complete(false)
+			f.logger.Trace("forwarder: dropping SYN for %v (too many half-open connections)", epID(id))
+			f.sendTCPDropEvent(id, nftypes.DropReasonSynFlood)
+			return
+		}
+		defer f.halfOpen.release(src)
+	}
+
 	flowID := uuid.New()
 
 	f.sendTCPEvent(nftypes.TypeStart, flowID, id, nil)
@@ -130,3 +144,18 @@ func (f *Forwarder) sendTCPEvent(typ nftypes.Type, flowID uuid.UUID, id stack.Tr
 
 	f.flowLogger.StoreEvent(fields)
 }
+
+func (f *Forwarder) sendTCPDropEvent(id stack.TransportEndpointID, reason nftypes.DropReason) {
+	f.flowLogger.StoreEvent(nftypes.EventFields{
+		FlowID:     uuid.New(),
+		Type:       nftypes.TypeDrop,
+		Direction:  nftypes.Ingress,
+		Protocol:   nftypes.TCP,
+		SourceIP:   netip.AddrFrom4(id.RemoteAddress.As4()),
+		DestIP:     netip.AddrFrom4(id.LocalAddress.As4()),
+		SourcePort: id.RemotePort,
+		DestPort:   id.LocalPort,
+		RxPackets:  1,
+		DropReason: reason,
+	})
+}
@@ -16,6 +16,8 @@ type localIPManager struct {
 
 	// fixed-size high array for upper byte of a IPv4 address
 	ipv4Bitmap [256]*ipv4LowBitmap
+	// IPv6 addresses are sparse, a set is sufficient
+	ipv6Set map[netip.Addr]struct{}
 }
 
 // ipv4LowBitmap is a map for the low 16 bits of a IPv4 address
@@ -84,7 +86,7 @@ func (m *localIPManager) processIP(ip net.IP, bitmap *[256]*ipv4LowBitmap, ipv4S
 	return nil
 }
 
-func (m *localIPManager) processInterface(iface net.Interface, bitmap *[256]*ipv4LowBitmap, ipv4Set map[string]struct{}, ipv4Addresses *[]string) {
+func (m *localIPManager) processInterface(iface net.Interface, bitmap *[256]*ipv4LowBitmap, ipv4Set map[string]struct{}, ipv4Addresses *[]string, ipv6Set map[netip.Addr]struct{}) {
 	addrs, err := iface.Addrs()
 	if err != nil {
 		log.Debugf("get addresses for interface %s failed: %v", iface.Name, err)
@@ -102,6 +104,11 @@ func (m *localIPManager) processInterface(iface net.Interface, bitmap *[256]*ipv
 			continue
 		}
 
+		if ip.To4() == nil {
+			addIPv6(ip, ipv6Set)
+			continue
+		}
+
 		if err := m.processIP(ip, bitmap, ipv4Set, ipv4Addresses); err != nil {
 			log.Debugf("process IP failed: %v", err)
 		}
@@ -125,10 +132,17 @@ func (m *localIPManager) UpdateLocalIPs(iface common.IFaceMapper) (err error) {
 		newIPv4Bitmap[127].bitmap[i] = 0xFFFFFFFF
 	}
 
+	newIPv6Set := map[netip.Addr]struct{}{
+		netip.IPv6Loopback(): {},
+	}
+
 	if iface != nil {
 		if err := m.processIP(iface.Address().IP, &newIPv4Bitmap, ipv4Set, &ipv4Addresses); err != nil {
 			return err
 		}
+		if addr := iface.Address(); addr.HasIPv6() {
+			addIPv6(addr.IPv6, newIPv6Set)
+		}
 	}
 
 	interfaces, err := net.Interfaces()
@@ -136,25 +150,39 @@ func (m *localIPManager) UpdateLocalIPs(iface common.IFaceMapper) (err error) {
 		log.Warnf("failed to get interfaces: %v", err)
 	} else {
 		for _, intf := range interfaces {
-			m.processInterface(intf, &newIPv4Bitmap, ipv4Set, &ipv4Addresses)
+			m.processInterface(intf, &newIPv4Bitmap, ipv4Set, &ipv4Addresses, newIPv6Set)
 		}
 	}
 
 	m.mu.Lock()
 	m.ipv4Bitmap = newIPv4Bitmap
+	m.ipv6Set = newIPv6Set
 	m.mu.Unlock()
 
 	log.Debugf("Local IPv4 addresses: %v", ipv4Addresses)
+	log.Debugf("Local IPv6 address count: %d", len(newIPv6Set))
 	return nil
 }
 
-func (m *localIPManager) IsLocalIP(ip netip.Addr) bool {
-	if !ip.Is4() {
-		return false
+func addIPv6(ip net.IP, ipv6Set map[netip.Addr]struct{}) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok || !addr.Is6() {
+		return
 	}
+	ipv6Set[addr] = struct{}{}
+}
 
+func (m *localIPManager) IsLocalIP(ip netip.Addr) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	if ip.Is6() {
+		_, ok := m.ipv6Set[ip]
+		return ok
+	}
+	if !ip.Is4() {
+		return false
+	}
+
 	return m.checkBitmapBit(ip.AsSlice())
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/google/gopacket/layers"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/common"
@@ -42,9 +43,22 @@ const (
 	// EnvEnableNetstackLocalForwarding enables forwarding of local traffic to the native stack when running netstack
 	// Leaving this on by default introduces a security risk as sockets on listening on localhost only will be accessible
 	EnvEnableNetstackLocalForwarding = "NB_ENABLE_NETSTACK_LOCAL_FORWARDING"
+
+	// EnvConntrackMaxEntries caps the number of tracked inbound connections across all peers, 0 disables the cap.
+	EnvConntrackMaxEntries = "NB_CONNTRACK_MAX_ENTRIES"
+
+	// EnvConntrackMaxEntriesPerSource caps the number of tracked inbound connections per peer, 0 disables the cap.
+	EnvConntrackMaxEntriesPerSource = "NB_CONNTRACK_MAX_ENTRIES_PER_SOURCE"
+
+	// EnvConntrackNewConnRate limits the new connections per second a single peer may open, 0 disables the limit.
+	// It applies to local and routed traffic.
+	EnvConntrackNewConnRate = "NB_CONNTRACK_NEW_CONN_RATE"
 )
 
-var errNatNotSupported = errors.New("nat not supported with userspace firewall")
+var (
+	errNatNotSupported   = errors.New("nat not supported with userspace firewall")
+	errConntrackDisabled = errors.New("conntrack is disabled")
+)
 
 // RuleSet is a set of rules grouped by a string key
 type RuleSet map[string]PeerRule
@@ -96,6 +110,7 @@ type Manager struct {
 	udpTracker  *conntrack.UDPTracker
 	icmpTracker *conntrack.ICMPTracker
 	tcpTracker  *conntrack.TCPTracker
+	connLimiter *conntrack.Limiter
 	forwarder   atomic.Pointer[forwarder.Forwarder]
 	logger      *nblog.Logger
 	flowLogger  nftypes.FlowLogger
@@ -112,6 +127,15 @@ type decoder struct {
 	icmp6   layers.ICMPv6
 	decoded []gopacket.LayerType
 	parser  *gopacket.DecodingLayerParser
+	parser6 *gopacket.DecodingLayerParser
+}
+
+// decode decodes the packet with the parser matching the IP version
+func (d *decoder) decode(packetData []byte) error {
+	if len(packetData) > 0 && packetData[0]>>4 == 6 && d.parser6 != nil {
+		return d.parser6.DecodeLayers(packetData, &d.decoded)
+	}
+	return d.parser.DecodeLayers(packetData, &d.decoded)
 }
 
 // Create userspace firewall manager constructor
@@ -151,6 +175,40 @@ func parseCreateEnv() (bool, bool) {
 	return disableConntrack, enableLocalForwarding
 }
 
+func parseConntrackLimits() conntrack.Limits {
+	limits := conntrack.Limits{
+		MaxEntries:          conntrack.DefaultMaxEntries,
+		MaxEntriesPerSource: conntrack.DefaultMaxEntriesPerSource,
+	}
+
+	for env, target := range map[string]*int{
+		EnvConntrackMaxEntries:          &limits.MaxEntries,
+		EnvConntrackMaxEntriesPerSource: &limits.MaxEntriesPerSource,
+	} {
+		val := os.Getenv(env)
+		if val == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed < 0 {
+			log.Warnf("failed to parse %s: invalid value %q", env, val)
+			continue
+		}
+		*target = parsed
+	}
+
+	if val := os.Getenv(EnvConntrackNewConnRate); val != "" {
+		connRate, err := strconv.ParseFloat(val, 64)
+		if err != nil || connRate < 0 {
+			log.Warnf("failed to parse %s: invalid value %q", EnvConntrackNewConnRate, val)
+		} else {
+			limits.NewConnRate = rate.Limit(connRate)
+		}
+	}
+
+	return limits
+}
+
 func create(iface common.IFaceMapper, nativeFirewall firewall.Manager, disableServerRoutes bool, flowLogger nftypes.FlowLogger) (*Manager, error) {
 	disableConntrack, enableLocalForwarding := parseCreateEnv()
 
@@ -165,6 +223,11 @@ func create(iface common.IFaceMapper, nativeFirewall firewall.Manager, disableSe
 					&d.eth, &d.ip4, &d.ip6, &d.icmp4, &d.icmp6, &d.tcp, &d.udp,
 				)
 				d.parser.IgnoreUnsupported = true
+				d.parser6 = gopacket.NewDecodingLayerParser(
+					layers.LayerTypeIPv6,
+					&d.eth, &d.ip4, &d.ip6, &d.icmp4, &d.icmp6, &d.tcp, &d.udp,
+				)
+				d.parser6.IgnoreUnsupported = true
 				return d
 			},
 		},
@@ -192,6 +255,15 @@ func create(iface common.IFaceMapper, nativeFirewall firewall.Manager, disableSe
 		m.udpTracker = conntrack.NewUDPTracker(conntrack.DefaultUDPTimeout, m.logger, flowLogger)
 		m.icmpTracker = conntrack.NewICMPTracker(conntrack.DefaultICMPTimeout, m.logger, flowLogger)
 		m.tcpTracker = conntrack.NewTCPTracker(conntrack.DefaultTCPTimeout, m.logger, flowLogger)
+
+		limits := parseConntrackLimits()
+		log.Debugf("conntrack limits: max entries %d, max entries per source %d, new connection rate %v/s",
+			limits.MaxEntries, limits.MaxEntriesPerSource, limits.NewConnRate)
+
+		m.connLimiter = conntrack.NewLimiter(limits)
+		m.udpTracker.SetLimiter(m.connLimiter)
+		m.icmpTracker.SetLimiter(m.connLimiter)
+		m.tcpTracker.SetLimiter(m.connLimiter)
 	}
 
 	// netstack needs the forwarder for local traffic
@@ -330,6 +402,11 @@ func (m *Manager) IsServerRouteSupported() bool {
 	return true
 }
 
+// SupportsIPv6 returns true as the userspace filter decodes both address families
+func (m *Manager) SupportsIPv6() bool {
+	return true
+}
+
 func (m *Manager) AddNatRule(pair firewall.RouterPair) error {
 	if m.nativeRouter.Load() && m.nativeFirewall != nil {
 		return m.nativeFirewall.AddNatRule(pair)
@@ -528,7 +605,7 @@ func (m *Manager) processOutgoingHooks(packetData []byte, size int) bool {
 	d := m.decoders.Get().(*decoder)
 	defer m.decoders.Put(d)
 
-	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+	if err := d.decode(packetData); err != nil {
 		return false
 	}
 
@@ -604,17 +681,19 @@ func (m *Manager) trackOutbound(d *decoder, srcIP, dstIP netip.Addr, size int) {
 	}
 }
 
-func (m *Manager) trackInbound(d *decoder, srcIP, dstIP netip.Addr, ruleID []byte, size int) {
+// trackInbound tracks inbound packets, it returns false if the packet opens a new connection that exceeds the conntrack limits
+func (m *Manager) trackInbound(d *decoder, srcIP, dstIP netip.Addr, ruleID []byte, size int) bool {
 	transport := d.decoded[1]
 	switch transport {
 	case layers.LayerTypeUDP:
-		m.udpTracker.TrackInbound(srcIP, dstIP, uint16(d.udp.SrcPort), uint16(d.udp.DstPort), ruleID, size)
+		return m.udpTracker.TrackInbound(srcIP, dstIP, uint16(d.udp.SrcPort), uint16(d.udp.DstPort), ruleID, size)
 	case layers.LayerTypeTCP:
 		flags := getTCPFlags(&d.tcp)
-		m.tcpTracker.TrackInbound(srcIP, dstIP, uint16(d.tcp.SrcPort), uint16(d.tcp.DstPort), flags, ruleID, size)
+		return m.tcpTracker.TrackInbound(srcIP, dstIP, uint16(d.tcp.SrcPort), uint16(d.tcp.DstPort), flags, ruleID, size)
 	case layers.LayerTypeICMPv4:
-		m.icmpTracker.TrackInbound(srcIP, dstIP, d.icmp4.Id, d.icmp4.TypeCode, ruleID, size)
+		return m.icmpTracker.TrackInbound(srcIP, dstIP, d.icmp4.Id, d.icmp4.TypeCode, ruleID, size)
 	}
+	return true
 }
 
 // udpHooksDrop checks if any UDP hooks should drop the packet
@@ -703,8 +782,9 @@ func (m *Manager) handleLocalTraffic(d *decoder, srcIP, dstIP netip.Addr, packet
 			SourcePort: srcPort,
 			DestPort:   dstPort,
 			// TODO: icmp type/code
-			RxPackets: 1,
-			RxBytes:   uint64(size),
+			RxPackets:  1,
+			RxBytes:    uint64(size),
+			DropReason: nftypes.DropReasonPolicy,
 		})
 		return true
 	}
@@ -715,7 +795,9 @@ func (m *Manager) handleLocalTraffic(d *decoder, srcIP, dstIP netip.Addr, packet
 	}
 
 	// track inbound packets to get the correct direction and session id for flows
-	m.trackInbound(d, srcIP, dstIP, ruleID, size)
+	if m.stateful && !m.trackInbound(d, srcIP, dstIP, ruleID, size) {
+		return true
+	}
 
 	// pass to either native or virtual stack (to be picked up by listeners)
 	return false
@@ -770,6 +852,25 @@ func (m *Manager) handleRoutedTraffic(d *decoder, srcIP, dstIP netip.Addr, packe
 			SourcePort: srcPort,
 			DestPort:   dstPort,
 			// TODO: icmp type/code
+			DropReason: nftypes.DropReasonPolicy,
+		})
+		return true
+	}
+
+	if d.decoded[1] == layers.LayerTypeTCP && d.tcp.SYN && !d.tcp.ACK && !m.connLimiter.AllowNew(srcIP) {
+		m.logger.Trace("Dropping routed packet (new connection rate exceeded): src=%s:%d dst=%s:%d",
+			srcIP, srcPort, dstIP, dstPort)
+
+		m.flowLogger.StoreEvent(nftypes.EventFields{
+			FlowID:     uuid.New(),
+			Type:       nftypes.TypeDrop,
+			Direction:  nftypes.Ingress,
+			Protocol:   pnum,
+			SourceIP:   srcIP,
+			DestIP:     dstIP,
+			SourcePort: srcPort,
+			DestPort:   dstPort,
+			DropReason: nftypes.DropReasonRateLimit,
 		})
 		return true
 	}
@@ -813,7 +914,7 @@ func getPortsFromPacket(d *decoder) (srcPort, dstPort uint16) {
 }
 
 func (m *Manager) isValidPacket(d *decoder, packetData []byte) bool {
-	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+	if err := d.decode(packetData); err != nil {
 		m.logger.Trace("couldn't decode packet, err: %s", err)
 		return false
 	}
@@ -855,7 +956,7 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP netip.Addr,
 			size,
 		)
 
-		// TODO: ICMPv6
+		// TODO: ICMPv6 echo tracking
 	}
 
 	return false
@@ -863,13 +964,20 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP netip.Addr,
 
 // isSpecialICMP returns true if the packet is a special ICMP packet that should be allowed
 func (m *Manager) isSpecialICMP(d *decoder) bool {
-	if d.decoded[1] != layers.LayerTypeICMPv4 {
+	switch d.decoded[1] {
+	case layers.LayerTypeICMPv4:
+		icmpType := d.icmp4.TypeCode.Type()
+		return icmpType == layers.ICMPv4TypeDestinationUnreachable ||
+			icmpType == layers.ICMPv4TypeTimeExceeded
+	case layers.LayerTypeICMPv6:
+		// path MTU discovery depends on packet too big messages
+		icmpType := d.icmp6.TypeCode.Type()
+		return icmpType == layers.ICMPv6TypeDestinationUnreachable ||
+			icmpType == layers.ICMPv6TypeTimeExceeded ||
+			icmpType == layers.ICMPv6TypePacketTooBig
+	default:
 		return false
 	}
-
-	icmpType := d.icmp4.TypeCode.Type()
-	return icmpType == layers.ICMPv4TypeDestinationUnreachable ||
-		icmpType == layers.ICMPv4TypeTimeExceeded
 }
 
 func (m *Manager) peerACLsBlock(srcIP netip.Addr, packetData []byte, rules map[netip.Addr]RuleSet, d *decoder) ([]byte, bool) {
@@ -923,7 +1031,7 @@ func validateRule(ip netip.Addr, packetData []byte, rules map[string]PeerRule, d
 			return rule.mgmtId, rule.drop, true
 		}
 
-		if payloadLayer != rule.protoLayer {
+		if !protoLayerMatches(rule, payloadLayer) {
 			continue
 		}
 
@@ -949,6 +1057,22 @@ func validateRule(ip netip.Addr, packetData []byte, rules map[string]PeerRule, d
 	return nil, false, false
 }
 
+// protoLayerMatches checks the transport layer of the packet against the rule.
+// ICMP rules without a peer address apply to ICMPv4 and ICMPv6.
+func protoLayerMatches(rule PeerRule, payloadLayer gopacket.LayerType) bool {
+	if payloadLayer == rule.protoLayer {
+		return true
+	}
+	if rule.matchByIP {
+		return false
+	}
+	return isICMPLayer(rule.protoLayer) && isICMPLayer(payloadLayer)
+}
+
+func isICMPLayer(layer gopacket.LayerType) bool {
+	return layer == layers.LayerTypeICMPv4 || layer == layers.LayerTypeICMPv6
+}
+
 // routeACLsPass returns true if the packet is allowed by the route ACLs
 func (m *Manager) routeACLsPass(srcIP, dstIP netip.Addr, proto firewall.Protocol, srcPort, dstPort uint16) ([]byte, bool) {
 	m.mutex.RLock()
@@ -1092,3 +1216,28 @@ func (m *Manager) DisableRouting() error {
 
 	return nil
 }
+
+// ConntrackEntries returns a snapshot of all tracked connections matching the filter
+func (m *Manager) ConntrackEntries(filter conntrack.Filter) ([]conntrack.Entry, error) {
+	if !m.stateful {
+		return nil, errConntrackDisabled
+	}
+
+	var entries []conntrack.Entry
+	entries = append(entries, m.tcpTracker.List(filter)...)
+	entries = append(entries, m.udpTracker.List(filter)...)
+	entries = append(entries, m.icmpTracker.List(filter)...)
+	return entries, nil
+}
+
+// FlushConntrack removes all tracked connections matching the filter and returns the number of removed entries.
+// Subsequent packets of flushed connections have to pass the ACLs again.
+func (m *Manager) FlushConntrack(filter conntrack.Filter) (int, error) {
+	if !m.stateful {
+		return 0, errConntrackDisabled
+	}
+
+	flushed := m.tcpTracker.Flush(filter) + m.udpTracker.Flush(filter) + m.icmpTracker.Flush(filter)
+	log.Infof("flushed %d conntrack entries", flushed)
+	return flushed, nil
+}
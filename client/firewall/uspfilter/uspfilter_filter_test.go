@@ -1015,3 +1015,136 @@ func TestRouteACLOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestPeerACLFilteringIPv6(t *testing.T) {
+	localIP := net.ParseIP("100.10.0.100")
+	wgNet := &net.IPNet{
+		IP:   net.ParseIP("100.10.0.0"),
+		Mask: net.CIDRMask(16, 32),
+	}
+	localIPv6, wgNetV6, err := net.ParseCIDR("fd00:1234::100/64")
+	require.NoError(t, err)
+
+	ifaceMock := &IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+		AddressFunc: func() wgaddr.Address {
+			return wgaddr.Address{
+				IP:      localIP,
+				Network: wgNet,
+				IPv6:    localIPv6,
+				IPv6Net: wgNetV6,
+			}
+		},
+	}
+
+	manager, err := Create(ifaceMock, false, flowLogger)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, manager.Close(nil))
+	})
+	require.NoError(t, manager.UpdateLocalIPs())
+	require.True(t, manager.SupportsIPv6())
+
+	_, err = manager.AddPeerFiltering(nil, net.ParseIP("fd00:1234::1"), fw.ProtocolTCP, nil, &fw.Port{Values: []uint16{22}}, fw.ActionAccept, "")
+	require.NoError(t, err)
+	_, err = manager.AddPeerFiltering(nil, net.ParseIP("0.0.0.0"), fw.ProtocolICMP, nil, nil, fw.ActionAccept, "")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		srcIP           string
+		proto           fw.Protocol
+		dstPort         uint16
+		icmpType        uint8
+		shouldBeBlocked bool
+	}{
+		{
+			name:            "Allow TCP from IPv6 peer",
+			srcIP:           "fd00:1234::1",
+			proto:           fw.ProtocolTCP,
+			dstPort:         22,
+			shouldBeBlocked: false,
+		},
+		{
+			name:            "Block TCP from IPv6 peer to other port",
+			srcIP:           "fd00:1234::1",
+			proto:           fw.ProtocolTCP,
+			dstPort:         80,
+			shouldBeBlocked: true,
+		},
+		{
+			name:            "Block TCP from other IPv6 peer",
+			srcIP:           "fd00:1234::2",
+			proto:           fw.ProtocolTCP,
+			dstPort:         22,
+			shouldBeBlocked: true,
+		},
+		{
+			name:            "Allow ICMPv6 echo by wildcard ICMP rule",
+			srcIP:           "fd00:1234::2",
+			proto:           fw.ProtocolICMP,
+			icmpType:        layers.ICMPv6TypeEchoRequest,
+			shouldBeBlocked: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			packet := createTestPacketV6(t, tc.srcIP, localIPv6.String(), tc.proto, 12345, tc.dstPort, tc.icmpType)
+			require.Equal(t, tc.shouldBeBlocked, manager.dropFilter(packet, len(packet)))
+		})
+	}
+
+	t.Run("Allow ICMPv6 packet too big without rule", func(t *testing.T) {
+		manager.mutex.Lock()
+		manager.incomingRules = make(map[netip.Addr]RuleSet)
+		manager.mutex.Unlock()
+
+		packet := createTestPacketV6(t, "fd00:1234::3", localIPv6.String(), fw.ProtocolICMP, 0, 0, layers.ICMPv6TypePacketTooBig)
+		require.False(t, manager.dropFilter(packet, len(packet)))
+	})
+}
+
+func createTestPacketV6(t *testing.T, srcIP, dstIP string, proto fw.Protocol, srcPort, dstPort uint16, icmpType uint8) []byte {
+	t.Helper()
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{
+		ComputeChecksums: true,
+		FixLengths:       true,
+	}
+
+	ipLayer := &layers.IPv6{
+		Version:  6,
+		HopLimit: 64,
+		SrcIP:    net.ParseIP(srcIP),
+		DstIP:    net.ParseIP(dstIP),
+	}
+
+	var err error
+	switch proto {
+	case fw.ProtocolTCP:
+		ipLayer.NextHeader = layers.IPProtocolTCP
+		tcp := &layers.TCP{
+			SrcPort: layers.TCPPort(srcPort),
+			DstPort: layers.TCPPort(dstPort),
+			SYN:     true,
+		}
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ipLayer))
+		err = gopacket.SerializeLayers(buf, opts, ipLayer, tcp)
+
+	case fw.ProtocolICMP:
+		ipLayer.NextHeader = layers.IPProtocolICMPv6
+		icmp := &layers.ICMPv6{
+			TypeCode: layers.CreateICMPv6TypeCode(icmpType, 0),
+		}
+		require.NoError(t, icmp.SetNetworkLayerForChecksum(ipLayer))
+		err = gopacket.SerializeLayers(buf, opts, ipLayer, icmp)
+
+	default:
+		err = gopacket.SerializeLayers(buf, opts, ipLayer)
+	}
+
+	require.NoError(t, err)
+	return buf.Bytes()
+}
@@ -711,3 +711,55 @@ func TestStatefulFirewall_UDPTracking(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConntrackLimits(t *testing.T) {
+	limits := parseConntrackLimits()
+	require.Equal(t, conntrack.DefaultMaxEntries, limits.MaxEntries)
+	require.Equal(t, conntrack.DefaultMaxEntriesPerSource, limits.MaxEntriesPerSource)
+	require.Zero(t, limits.NewConnRate)
+
+	t.Setenv(EnvConntrackMaxEntries, "100")
+	t.Setenv(EnvConntrackMaxEntriesPerSource, "invalid")
+	t.Setenv(EnvConntrackNewConnRate, "2.5")
+
+	limits = parseConntrackLimits()
+	require.Equal(t, 100, limits.MaxEntries)
+	require.Equal(t, conntrack.DefaultMaxEntriesPerSource, limits.MaxEntriesPerSource, "invalid values should keep the default")
+	require.Equal(t, 2.5, float64(limits.NewConnRate))
+}
+
+func TestConntrackLimitsDropNewInboundConnections(t *testing.T) {
+	t.Setenv(EnvConntrackMaxEntriesPerSource, "2")
+
+	wgNet := &net.IPNet{
+		IP:   net.ParseIP("100.10.0.0"),
+		Mask: net.CIDRMask(16, 32),
+	}
+	manager, err := Create(&IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+		AddressFunc: func() wgaddr.Address {
+			return wgaddr.Address{
+				IP:      net.ParseIP("100.10.0.100"),
+				Network: wgNet,
+			}
+		},
+	}, false, flowLogger)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, manager.Close(nil))
+	})
+	manager.wgNetwork = wgNet
+	require.NoError(t, manager.UpdateLocalIPs())
+
+	_, err = manager.AddPeerFiltering(nil, net.ParseIP("0.0.0.0"), fw.ProtocolTCP, nil, &fw.Port{Values: []uint16{443}}, fw.ActionAccept, "")
+	require.NoError(t, err)
+
+	require.False(t, manager.DropIncoming(createTestPacket(t, "100.10.0.1", "100.10.0.100", fw.ProtocolTCP, 40000, 443), 0))
+	require.False(t, manager.DropIncoming(createTestPacket(t, "100.10.0.1", "100.10.0.100", fw.ProtocolTCP, 40001, 443), 0))
+	require.True(t, manager.DropIncoming(createTestPacket(t, "100.10.0.1", "100.10.0.100", fw.ProtocolTCP, 40002, 443), 0),
+		"connection above the per-source cap should be dropped")
+
+	// packets of tracked connections and other sources are not affected
+	require.False(t, manager.DropIncoming(createTestPacket(t, "100.10.0.1", "100.10.0.100", fw.ProtocolTCP, 40000, 443), 0))
+	require.False(t, manager.DropIncoming(createTestPacket(t, "100.10.0.2", "100.10.0.100", fw.ProtocolTCP, 40000, 443), 0))
+}
@@ -4,10 +4,12 @@ package device
 
 import (
 	"fmt"
+	"net"
 	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 
 	"github.com/netbirdio/netbird/client/iface/wgaddr"
 )
@@ -126,6 +128,12 @@ func (l *wgLink) assignAddr(address wgaddr.Address) error {
 		return fmt.Errorf("add addr: %w", err)
 	}
 
+	if address.HasIPv6() {
+		if err := l.assignAddrV6(address); err != nil {
+			return err
+		}
+	}
+
 	// On linux, the link must be brought up
 	if err := netlink.LinkSetUp(l); err != nil {
 		return fmt.Errorf("link setup: %w", err)
@@ -133,3 +141,21 @@ func (l *wgLink) assignAddr(address wgaddr.Address) error {
 
 	return nil
 }
+
+// assignAddrV6 adds the IPv6 overlay address, duplicate address detection is skipped as the prefix is managed by the management server
+func (l *wgLink) assignAddrV6(address wgaddr.Address) error {
+	addr := &netlink.Addr{
+		IPNet: &net.IPNet{IP: address.IPv6, Mask: address.IPv6Net.Mask},
+		Flags: unix.IFA_F_NODAD,
+	}
+
+	log.Debugf("adding address %s to interface: %s", address.IPv6String(), l.attrs.Name)
+
+	err := netlink.AddrAdd(l, addr)
+	if os.IsExist(err) {
+		log.Infof("interface %s already has the address: %s", l.attrs.Name, address.IPv6String())
+	} else if err != nil {
+		return fmt.Errorf("add IPv6 addr: %w", err)
+	}
+	return nil
+}
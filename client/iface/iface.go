@@ -107,6 +107,31 @@ func (w *WGIface) UpdateAddr(newAddr string) error {
 		return err
 	}
 
+	current := w.tun.WgAddress()
+	addr.IPv6, addr.IPv6Net = current.IPv6, current.IPv6Net
+
+	return w.tun.UpdateAddr(addr)
+}
+
+// UpdateAddrV6 updates the IPv6 overlay address of the interface, an empty address removes it
+func (w *WGIface) UpdateAddrV6(newAddr string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	addr := w.tun.WgAddress()
+	addr.IPv6, addr.IPv6Net = nil, nil
+
+	if newAddr != "" {
+		ip, network, err := net.ParseCIDR(newAddr)
+		if err != nil {
+			return err
+		}
+		if ip.To4() != nil {
+			return fmt.Errorf("not an IPv6 address: %s", newAddr)
+		}
+		addr.IPv6, addr.IPv6Net = ip, network
+	}
+
 	return w.tun.UpdateAddr(addr)
 }
 
@@ -9,6 +9,10 @@ import (
 type Address struct {
 	IP      net.IP
 	Network *net.IPNet
+
+	// IPv6 is the optional IPv6 overlay address of the interface
+	IPv6    net.IP     `json:",omitempty"`
+	IPv6Net *net.IPNet `json:",omitempty"`
 }
 
 // ParseWGAddress parse a string ("1.2.3.4/24") address to WG Address
@@ -27,3 +31,17 @@ func (addr Address) String() string {
 	maskSize, _ := addr.Network.Mask.Size()
 	return fmt.Sprintf("%s/%d", addr.IP.String(), maskSize)
 }
+
+// HasIPv6 returns true if the address includes an IPv6 overlay address
+func (addr Address) HasIPv6() bool {
+	return addr.IPv6 != nil && addr.IPv6Net != nil
+}
+
+// IPv6String returns the IPv6 overlay address in CIDR notation or an empty string if there is none
+func (addr Address) IPv6String() string {
+	if !addr.HasIPv6() {
+		return ""
+	}
+	maskSize, _ := addr.IPv6Net.Mask.Size()
+	return fmt.Sprintf("%s/%d", addr.IPv6.String(), maskSize)
+}
@@ -71,6 +71,8 @@ type ConfigInput struct {
 
 	BlockLANAccess *bool
 
+	OfflineStart *bool
+
 	DisableNotifications *bool
 
 	DNSLabels domain.List
@@ -99,6 +101,9 @@ type Config struct {
 
 	BlockLANAccess bool
 
+	// OfflineStart persists the last network map and uses it to start when management is unreachable
+	OfflineStart bool
+
 	DisableNotifications *bool
 
 	DNSLabels domain.List
@@ -479,6 +484,16 @@ func (config *Config) apply(input ConfigInput) (updated bool, err error) {
 		updated = true
 	}
 
+	if input.OfflineStart != nil && *input.OfflineStart != config.OfflineStart {
+		if *input.OfflineStart {
+			log.Infof("enabling offline start with the persisted network map")
+		} else {
+			log.Infof("disabling offline start with the persisted network map")
+		}
+		config.OfflineStart = *input.OfflineStart
+		updated = true
+	}
+
 	if input.DisableNotifications != nil && input.DisableNotifications != config.DisableNotifications {
 		if *input.DisableNotifications {
 			log.Infof("disabling notifications")
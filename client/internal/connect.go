@@ -44,6 +44,7 @@ type ConnectClient struct {
 	engineMutex    sync.Mutex
 
 	persistNetworkMap bool
+	statePath         string
 }
 
 func NewConnectClient(
@@ -171,14 +172,27 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 		defer func() {
 			_, err := state.Status()
 			c.statusRecorder.MarkManagementDisconnected(err)
+			c.statusRecorder.MarkNetworkMapStale(false)
 			c.statusRecorder.CleanLocalPeerState()
 			cancel()
 		}()
 
+		// offlineSync is the persisted network map the engine starts with when management is unreachable
+		var offlineSync *mgmProto.SyncResponse
+
 		log.Debugf("connecting to the Management service %s", c.config.ManagementURL.Host)
 		mgmClient, err := mgm.NewClient(engineCtx, c.config.ManagementURL.Host, myPrivateKey, mgmTlsEnabled)
 		if err != nil {
-			return wrapErr(gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err))
+			if offlineSync = c.loadOfflineSync(myPrivateKey); offlineSync == nil {
+				return wrapErr(gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err))
+			}
+			log.Warnf("failed connecting to the Management service, starting with the persisted network map: %v", err)
+
+			// the engine's management stream keeps retrying on this client until the service is back
+			mgmClient, err = mgm.NewLazyClient(engineCtx, c.config.ManagementURL.Host, myPrivateKey, mgmTlsEnabled)
+			if err != nil {
+				return wrapErr(gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err))
+			}
 		}
 		mgmNotifier := statusRecorderToMgmConnStateNotifier(c.statusRecorder)
 		mgmClient.SetConnStateListener(mgmNotifier)
@@ -190,18 +204,29 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 			}
 		}()
 
-		// connect (just a connection, no stream yet) and login to Management Service to get an initial global Netbird config
-		loginResp, err := loginToManagement(engineCtx, mgmClient, publicSSHKey, c.config)
-		if err != nil {
-			log.Debug(err)
-			if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.PermissionDenied) {
-				state.Set(StatusNeedsLogin)
-				_ = c.Stop()
-				return backoff.Permanent(wrapErr(err)) // unrecoverable error
+		var loginResp *mgmProto.LoginResponse
+		if offlineSync == nil {
+			// connect (just a connection, no stream yet) and login to Management Service to get an initial global Netbird config
+			loginResp, err = loginToManagement(engineCtx, mgmClient, publicSSHKey, c.config)
+			if err != nil {
+				log.Debug(err)
+				if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.PermissionDenied) {
+					state.Set(StatusNeedsLogin)
+					_ = c.Stop()
+					return backoff.Permanent(wrapErr(err)) // unrecoverable error
+				}
+				if offlineSync = c.loadOfflineSync(myPrivateKey); offlineSync == nil {
+					return wrapErr(err)
+				}
+				log.Warnf("failed logging in to the Management service, starting with the persisted network map: %v", err)
 			}
-			return wrapErr(err)
 		}
-		c.statusRecorder.MarkManagementConnected()
+
+		if offlineSync != nil {
+			loginResp = offlineLoginResponse(offlineSync)
+		} else {
+			c.statusRecorder.MarkManagementConnected()
+		}
 
 		localPeerState := peer.LocalPeerState{
 			IP:              loginResp.GetPeerConfig().GetAddress(),
@@ -266,11 +291,15 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 			return wrapErr(err)
 		}
 
+		engineConfig.StateFilePath = c.statePath
+		engineConfig.ManagementURL = c.config.ManagementURL.String()
+
 		checks := loginResp.GetChecks()
 
 		c.engineMutex.Lock()
 		c.engine = NewEngine(engineCtx, cancel, signalClient, mgmClient, relayManager, engineConfig, mobileDependency, c.statusRecorder, checks)
 		c.engine.SetNetworkMapPersistence(c.persistNetworkMap)
+		c.engine.offlineSync = offlineSync
 		c.engineMutex.Unlock()
 
 		if err := c.engine.Start(); err != nil {
@@ -394,6 +423,14 @@ func (c *ConnectClient) SetNetworkMapPersistence(enabled bool) {
 	}
 }
 
+// SetStatePath sets the state file used by the engine, it has to be called before Run.
+// The default state file is used when the path is empty.
+func (c *ConnectClient) SetStatePath(path string) {
+	c.engineMutex.Lock()
+	c.statePath = path
+	c.engineMutex.Unlock()
+}
+
 // createEngineConfig converts configuration received from Management Service to EngineConfig
 func createEngineConfig(key wgtypes.Key, config *Config, peerConfig *mgmProto.PeerConfig) (*EngineConfig, error) {
 	nm := false
@@ -422,6 +459,8 @@ func createEngineConfig(key wgtypes.Key, config *Config, peerConfig *mgmProto.Pe
 		DisableFirewall:     config.DisableFirewall,
 
 		BlockLANAccess: config.BlockLANAccess,
+
+		OfflineStart: config.OfflineStart,
 	}
 
 	if config.PreSharedKey != "" {
@@ -488,6 +527,14 @@ func loginToManagement(ctx context.Context, client mgm.Client, pubSSHKey []byte,
 	return loginResp, nil
 }
 
+// loadOfflineSync returns the persisted network map if offline start is enabled and the map is usable
+func (c *ConnectClient) loadOfflineSync(key wgtypes.Key) *mgmProto.SyncResponse {
+	if !c.config.OfflineStart {
+		return nil
+	}
+	return loadOfflineSyncResponse(c.statePath, c.config.ManagementURL.String(), key)
+}
+
 func statusRecorderToMgmConnStateNotifier(statusRecorder *peer.Status) mgm.ConnStateNotifier {
 	var sri interface{} = statusRecorder
 	mgmNotifier, _ := sri.(mgm.ConnStateNotifier)
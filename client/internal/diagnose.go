@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/relay"
+)
+
+// PeerDiagnostics is the result of diagnosing the connection to a remote peer
+type PeerDiagnostics struct {
+	State peer.State
+	Conn  peer.ConnDiagnostics
+	// NAT is nil when the classification failed, NATErr contains the reason
+	NAT    *relay.NATResult
+	NATErr error
+	Relays []relay.ProbeResult
+}
+
+// DiagnosePeer collects the connection details of the remote peer, classifies the local NAT
+// and probes the STUN, TURN and relay servers
+func (e *Engine) DiagnosePeer(ctx context.Context, pubKey string) (*PeerDiagnostics, error) {
+	state, err := e.statusRecorder.GetPeer(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("get peer state: %w", err)
+	}
+
+	conn, ok := e.peerStore.PeerConn(pubKey)
+	if !ok {
+		return nil, fmt.Errorf("peer %s has no connection", pubKey)
+	}
+
+	diag := &PeerDiagnostics{
+		State: state,
+		Conn:  conn.Diagnostics(),
+	}
+
+	e.syncMsgMux.Lock()
+	stuns := slices.Clone(e.STUNs)
+	e.syncMsgMux.Unlock()
+
+	diag.NAT, diag.NATErr = relay.ClassifyNAT(ctx, stuns)
+
+	results := append(e.probeSTUNs(), e.probeTURNs()...)
+	e.statusRecorder.UpdateRelayStates(results)
+	diag.Relays = e.statusRecorder.GetRelayStates()
+
+	return diag, nil
+}
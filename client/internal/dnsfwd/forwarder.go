@@ -5,12 +5,16 @@ import (
 	"errors"
 	"net"
 	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/miekg/dns"
 	log "github.com/sirupsen/logrus"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	nbdomain "github.com/netbirdio/netbird/management/domain"
 )
 
 const errResolveFailed = "failed to resolve query for domain=%s: %v"
@@ -21,6 +25,13 @@ type DNSForwarder struct {
 	ttl           uint32
 	domains       []string
 
+	// mu guards the patterns the queried names are checked against
+	mu sync.RWMutex
+	// zones are the zones of the plain and wildcard domains, all their names are forwarded
+	zones []string
+	// regexes are the regex domains, only the matching names of their zones are forwarded
+	regexes []*regexp.Regexp
+
 	dnsServer *dns.Server
 	mux       *dns.ServeMux
 }
@@ -57,11 +68,16 @@ func (f *DNSForwarder) UpdateDomains(domains []string) {
 		f.mux.HandleRemove(d)
 	}
 
-	newDomains := filterDomains(domains)
+	newDomains, zones, regexes := filterDomains(domains)
 	for _, d := range newDomains {
 		f.mux.HandleFunc(d, f.handleDNSQuery)
 	}
 	f.domains = newDomains
+
+	f.mu.Lock()
+	f.zones = zones
+	f.regexes = regexes
+	f.mu.Unlock()
 }
 
 func (f *DNSForwarder) Close(ctx context.Context) error {
@@ -82,6 +98,16 @@ func (f *DNSForwarder) handleDNSQuery(w dns.ResponseWriter, query *dns.Msg) {
 	domain := question.Name
 
 	resp := query.SetReply(query)
+
+	if !f.isAllowed(domain) {
+		log.Tracef("refusing DNS query for domain=%s not matching a regex domain", domain)
+		resp.Rcode = dns.RcodeRefused
+		if err := w.WriteMsg(resp); err != nil {
+			log.Errorf("failed to write DNS response: %v", err)
+		}
+		return
+	}
+
 	var network string
 	switch question.Qtype {
 	case dns.TypeA:
@@ -173,15 +199,56 @@ func (f *DNSForwarder) addIPsToResponse(resp *dns.Msg, domain string, ips []neti
 	}
 }
 
-// filterDomains returns a list of normalized domains
-func filterDomains(domains []string) []string {
+// isAllowed returns true if the name is covered by a plain or wildcard domain or matches a regex domain
+func (f *DNSForwarder) isAllowed(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if len(f.regexes) == 0 {
+		return true
+	}
+
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, zone := range f.zones {
+		if name == zone || strings.HasSuffix(name, "."+zone) {
+			return true
+		}
+	}
+
+	for _, re := range f.regexes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterDomains returns a list of normalized domains to serve, with the zones of the plain domains and the regex domains.
+// Regex domains are served for their zone.
+func filterDomains(domains []string) ([]string, []string, []*regexp.Regexp) {
 	newDomains := make([]string, 0, len(domains))
+	var zones []string
+	var regexes []*regexp.Regexp
 	for _, d := range domains {
 		if d == "" {
 			log.Warn("empty domain in DNS forwarder")
 			continue
 		}
-		newDomains = append(newDomains, nbdns.NormalizeZone(d))
+
+		if nbdomain.Domain(d).IsRegex() {
+			re, zone, err := nbdomain.ParseRegex(nbdomain.Domain(d))
+			if err != nil {
+				log.Warnf("skipping invalid regex domain in DNS forwarder: %v", err)
+				continue
+			}
+			regexes = append(regexes, re)
+			newDomains = append(newDomains, string(zone))
+			continue
+		}
+
+		zone := nbdns.NormalizeZone(d)
+		zones = append(zones, strings.ToLower(strings.TrimSuffix(zone, ".")))
+		newDomains = append(newDomains, zone)
 	}
-	return newDomains
+	return newDomains, zones, regexes
 }
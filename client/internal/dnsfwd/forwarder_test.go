@@ -0,0 +1,24 @@
+package dnsfwd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSForwarder_IsAllowed(t *testing.T) {
+	f := NewDNSForwarder("127.0.0.1:0", 60)
+
+	domains, _, _ := filterDomains([]string{"example.org", "*.example.net", `~^api-[0-9]+\.internal\.example\.com$`})
+	assert.Equal(t, []string{"example.org", "example.net", "internal.example.com"}, domains, "regex domains should be served for their zone")
+
+	f.zones, f.regexes = nil, nil
+	assert.True(t, f.isAllowed("anything.example.org."), "all names are allowed without regex domains")
+
+	_, f.zones, f.regexes = filterDomains([]string{"example.org", "*.example.net", `~^api-[0-9]+\.internal\.example\.com$`})
+
+	assert.True(t, f.isAllowed("example.org."))
+	assert.True(t, f.isAllowed("www.example.net."))
+	assert.True(t, f.isAllowed("API-1.internal.example.com."))
+	assert.False(t, f.isAllowed("db.internal.example.com."), "names of a regex zone not matching the regex should be refused")
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
 	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
+	"github.com/netbirdio/netbird/client/internal/peer/probe"
 	"github.com/netbirdio/netbird/client/internal/peerstore"
 	"github.com/netbirdio/netbird/client/internal/relay"
 	"github.com/netbirdio/netbird/client/internal/rosenpass"
@@ -122,6 +123,14 @@ type EngineConfig struct {
 	DisableFirewall     bool
 
 	BlockLANAccess bool
+
+	// OfflineStart persists the network map to be used when management is unreachable at startup
+	OfflineStart bool
+	// ManagementURL binds the persisted network map to the management server it was received from
+	ManagementURL string
+
+	// StateFilePath is the state file of the active profile, the default state file is used when empty
+	StateFilePath string
 }
 
 // Engine is a mechanism responsible for reacting on Signal and Management stream events and managing connections to the remote peers.
@@ -139,6 +148,8 @@ type Engine struct {
 
 	// rpManager is a Rosenpass manager
 	rpManager *rosenpass.Manager
+	// rosenpassRequired is set by the management when the peer has to refuse connections without Rosenpass
+	rosenpassRequired bool
 
 	// syncMsgMux is used to guarantee sequential Management Service message processing
 	syncMsgMux *sync.Mutex
@@ -186,12 +197,25 @@ type Engine struct {
 	relayManager *relayClient.Manager
 	stateManager *statemanager.Manager
 	srWatcher    *guard.SRWatcher
+	prober       *probe.Prober
+
+	// networkMapDeltaBase is the last network map received from management, deltas are applied to it
+	networkMapDeltaBase *mgmProto.NetworkMap
 
 	// Network map persistence
 	persistNetworkMap bool
 	latestNetworkMap  *mgmProto.NetworkMap
 	connSemaphore     *semaphoregroup.SemaphoreGroup
 	flowManager       nftypes.FlowManager
+
+	// latestNetbirdConfig is the last global config received from management, stored with the offline network map
+	latestNetbirdConfig *mgmProto.NetbirdConfig
+	// offlineSync is the persisted sync response applied on start when management is unreachable
+	offlineSync *mgmProto.SyncResponse
+	// networkMapStale is set until the first network map from management replaces the persisted one
+	networkMapStale bool
+	// storedNetworkMap is the network map last persisted for an offline start
+	storedNetworkMap *mgmProto.NetworkMap
 }
 
 // Peer is an instance of the Connection Peer
@@ -246,8 +270,12 @@ func NewEngine(
 
 		engine.stateManager = statemanager.New(mobileDep.StateFilePath)
 	}
-	if path := statemanager.GetDefaultStatePath(); path != "" {
-		engine.stateManager = statemanager.New(path)
+	statePath := config.StateFilePath
+	if statePath == "" {
+		statePath = statemanager.GetDefaultStatePath()
+	}
+	if statePath != "" {
+		engine.stateManager = statemanager.New(statePath)
 	}
 
 	return engine
@@ -293,6 +321,11 @@ func (e *Engine) Stop() error {
 		e.srWatcher.Close()
 	}
 
+	if e.prober != nil {
+		e.prober.Stop()
+		e.prober = nil
+	}
+
 	e.statusRecorder.ReplaceOfflinePeers([]peer.State{})
 	e.statusRecorder.UpdateDNSStates([]peer.NSGroupState{})
 	e.statusRecorder.UpdateRelayStates([]relay.ProbeResult{})
@@ -373,6 +406,7 @@ func (e *Engine) Start() error {
 	}
 
 	e.stateManager.Start()
+	e.initNetworkMapState()
 
 	initialRoutes, dnsServer, err := e.newDnsServer()
 	if err != nil {
@@ -445,6 +479,15 @@ func (e *Engine) Start() error {
 	e.srWatcher = guard.NewSRWatcher(e.signal, e.relayManager, e.mobileDep.IFaceDiscover, iceCfg)
 	e.srWatcher.Start()
 
+	e.startProber()
+
+	if e.offlineSync != nil {
+		if err := e.applyOfflineSync(e.offlineSync); err != nil {
+			log.Errorf("failed to apply the persisted network map: %v", err)
+		}
+		e.offlineSync = nil
+	}
+
 	e.receiveSignalEvents()
 	e.receiveManagementEvents()
 
@@ -454,6 +497,23 @@ func (e *Engine) Start() error {
 	return nil
 }
 
+// startProber starts measuring the packet loss, jitter and path MTU to the connected peers.
+// The probes need a raw socket, which isn't available in netstack mode and on mobile.
+func (e *Engine) startProber() {
+	if nbnetstack.IsEnabled() || runtime.GOOS == "android" || runtime.GOOS == "ios" {
+		return
+	}
+
+	pinger, err := probe.NewICMPPinger()
+	if err != nil {
+		log.Warnf("connection quality probes are disabled: %v", err)
+		return
+	}
+
+	e.prober = probe.NewProber(e.statusRecorder, pinger, iface.DefaultMTU)
+	e.prober.Start(e.ctx)
+}
+
 func (e *Engine) createFirewall() error {
 	if e.config.DisableFirewall {
 		log.Infof("firewall is disabled")
@@ -639,16 +699,77 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 	e.syncMsgMux.Lock()
 	defer e.syncMsgMux.Unlock()
 
+	// the persisted network map is replaced by whatever management sends, regardless of its serial
+	if e.networkMapStale {
+		e.networkSerial = 0
+	}
+
+	nm, err := e.applySync(update)
+	if err != nil {
+		return err
+	}
+	if nm == nil {
+		// keep the refreshed relay and TURN credentials for an offline start
+		if update.GetNetbirdConfig() != nil && !e.networkMapStale && e.storedNetworkMap != nil {
+			e.storeNetworkMapState(e.storedNetworkMap)
+		}
+		return nil
+	}
+
+	if e.networkMapStale {
+		log.Infof("received the network map from management, replacing the persisted one")
+		e.networkMapStale = false
+		e.statusRecorder.MarkNetworkMapStale(false)
+	}
+	e.storeNetworkMapState(nm)
+
+	e.statusRecorder.PublishEvent(cProto.SystemEvent_INFO, cProto.SystemEvent_SYSTEM, "Network map updated", "", nil)
+
+	return nil
+}
+
+// applyOfflineSync configures the engine from a persisted sync response and marks the network map as stale.
+// The caller must hold syncMsgMux.
+func (e *Engine) applyOfflineSync(update *mgmProto.SyncResponse) error {
+	nm, err := e.applySync(update)
+	if err != nil {
+		return err
+	}
+	// management doesn't know about this network map, deltas must not be applied to it
+	e.networkMapDeltaBase = nil
+
+	log.Warnf("management is unreachable, running with the persisted network map with serial %d", nm.GetSerial())
+	e.networkMapStale = true
+	e.statusRecorder.MarkNetworkMapStale(true)
+
+	expiry := credentialsExpiry(update.GetNetbirdConfig())
+	e.statusRecorder.SetStaleCredentialsExpiry(expiry)
+	if !expiry.IsZero() && time.Now().After(expiry) {
+		log.Warnf("the relay and TURN credentials of the persisted network map expired at %s, peers are only reachable directly", expiry)
+	}
+	e.statusRecorder.PublishEvent(
+		cProto.SystemEvent_WARNING, cProto.SystemEvent_SYSTEM,
+		"Using persisted network map",
+		"Management is unreachable, the last known network map is in use until it reconnects.",
+		nil,
+	)
+
+	return nil
+}
+
+// applySync applies the configuration and network map of a sync response.
+// It returns the network map that was applied, or nil if the response didn't contain one.
+func (e *Engine) applySync(update *mgmProto.SyncResponse) (*mgmProto.NetworkMap, error) {
 	if update.GetNetbirdConfig() != nil {
 		wCfg := update.GetNetbirdConfig()
 		err := e.updateTURNs(wCfg.GetTurns())
 		if err != nil {
-			return fmt.Errorf("update TURNs: %w", err)
+			return nil, fmt.Errorf("update TURNs: %w", err)
 		}
 
 		err = e.updateSTUNs(wCfg.GetStuns())
 		if err != nil {
-			return fmt.Errorf("update STUNs: %w", err)
+			return nil, fmt.Errorf("update STUNs: %w", err)
 		}
 
 		var stunTurn []*stun.URI
@@ -658,26 +779,37 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 
 		err = e.handleRelayUpdate(wCfg.GetRelay())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		err = e.handleFlowUpdate(wCfg.GetFlow())
 		if err != nil {
-			return fmt.Errorf("handle the flow configuration: %w", err)
+			return nil, fmt.Errorf("handle the flow configuration: %w", err)
 		}
 
+		e.latestNetbirdConfig = wCfg
+
 		// todo update signal
 	}
 
 	if err := e.updateChecksIfNew(update.Checks); err != nil {
-		return err
+		return nil, err
 	}
 
 	nm := update.GetNetworkMap()
+	if delta := update.GetNetworkMapDelta(); delta != nil {
+		var err error
+		if nm, err = e.applyNetworkMapDelta(delta); err != nil {
+			return nil, err
+		}
+	}
 	if nm == nil {
-		return nil
+		return nil, nil //nolint:nilnil
 	}
 
+	// management diffs against the last network map it sent, even an outdated one
+	e.networkMapDeltaBase = nm
+
 	// Store network map if persistence is enabled
 	if e.persistNetworkMap {
 		e.latestNetworkMap = nm
@@ -686,12 +818,22 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 
 	// only apply new changes and ignore old ones
 	if err := e.updateNetworkMap(nm); err != nil {
-		return err
+		return nil, err
 	}
 
-	e.statusRecorder.PublishEvent(cProto.SystemEvent_INFO, cProto.SystemEvent_SYSTEM, "Network map updated", "", nil)
+	return nm, nil
+}
 
-	return nil
+// applyNetworkMapDelta returns the network map resulting from the delta.
+// On a mismatch the error closes the management stream, reconnecting receives a full network map.
+func (e *Engine) applyNetworkMapDelta(delta *mgmProto.NetworkMapDelta) (*mgmProto.NetworkMap, error) {
+	nm, err := mgmProto.ApplyNetworkMapDelta(e.networkMapDeltaBase, delta)
+	if err != nil {
+		e.networkMapDeltaBase = nil
+		return nil, fmt.Errorf("apply network map delta, resyncing: %w", err)
+	}
+	log.Debugf("applied network map delta from serial %d to %d", delta.GetBaseSerial(), delta.GetSerial())
+	return nm, nil
 }
 
 func (e *Engine) handleRelayUpdate(update *mgmProto.RelayConfig) error {
@@ -848,6 +990,10 @@ func (e *Engine) updateConfig(conf *mgmProto.PeerConfig) error {
 		log.Infof("updated peer address from %s to %s", oldAddr, conf.Address)
 	}
 
+	if err := e.updateAddrV6(conf.GetAddressV6()); err != nil {
+		log.Warnf("failed to update IPv6 overlay address: %v", err)
+	}
+
 	if conf.GetSshConfig() != nil {
 		err := e.updateSSH(conf.GetSshConfig())
 		if err != nil {
@@ -855,6 +1001,8 @@ func (e *Engine) updateConfig(conf *mgmProto.PeerConfig) error {
 		}
 	}
 
+	e.updateRosenpassRequired(conf.GetRosenpassRequired())
+
 	state := e.statusRecorder.GetLocalPeerState()
 	state.IP = e.config.WgAddr
 	state.PubKey = e.config.WgPrivateKey.PublicKey().String()
@@ -866,6 +1014,67 @@ func (e *Engine) updateConfig(conf *mgmProto.PeerConfig) error {
 	return nil
 }
 
+// updateRosenpassRequired applies the post-quantum requirement of the management. If the requirement can't be met
+// because Rosenpass is disabled, every connection is refused until Rosenpass is enabled.
+func (e *Engine) updateRosenpassRequired(required bool) {
+	if e.rosenpassRequired == required {
+		return
+	}
+	e.rosenpassRequired = required
+
+	switch {
+	case !required:
+		log.Infof("post-quantum key exchange is no longer required")
+	case e.rpManager == nil:
+		log.Errorf("post-quantum key exchange is required by the management but Rosenpass is disabled, refusing all peer connections")
+	default:
+		log.Infof("post-quantum key exchange is required, refusing peer connections without Rosenpass")
+	}
+}
+
+// refuseNonRosenpassPeer returns true if the post-quantum key exchange is required and the remote offer or answer
+// can't be used for it
+func (e *Engine) refuseNonRosenpassPeer(remoteKey string, remoteRosenpassPubKey []byte) bool {
+	if !e.rosenpassRequired {
+		return false
+	}
+
+	if e.rpManager == nil {
+		log.Debugf("refusing connection with peer %s, Rosenpass is required but disabled", remoteKey)
+		return true
+	}
+
+	if len(remoteRosenpassPubKey) == 0 {
+		log.Warnf("refusing connection with peer %s, the peer doesn't support Rosenpass", remoteKey)
+		return true
+	}
+	return false
+}
+
+// removeNonRosenpassPeers removes the connected peers without the post-quantum key exchange when it is required,
+// they were connected before the requirement was applied
+func (e *Engine) removeNonRosenpassPeers() error {
+	if !e.rosenpassRequired {
+		return nil
+	}
+
+	for _, p := range e.peerStore.PeersPubKey() {
+		state, err := e.statusRecorder.GetPeer(p)
+		if err != nil || state.ConnStatus != peer.StatusConnected {
+			continue
+		}
+		if e.rpManager != nil && state.RosenpassEnabled {
+			continue
+		}
+
+		if err := e.removePeer(p); err != nil {
+			return err
+		}
+		log.Infof("removed peer %s connected without Rosenpass", p)
+	}
+	return nil
+}
+
 // receiveManagementEvents connects to the Management Service event stream to receive updates from the management service
 // E.g. when a new peer has been registered and we are allowed to connect to it.
 func (e *Engine) receiveManagementEvents() {
@@ -937,6 +1146,50 @@ func (e *Engine) updateTURNs(turns []*mgmProto.ProtectedHostConfig) error {
 	return nil
 }
 
+// ipv6Enabled returns true if the IPv6 overlay traffic can be filtered like the IPv4 traffic
+func (e *Engine) ipv6Enabled() bool {
+	return e.firewall == nil || e.firewall.SupportsIPv6()
+}
+
+// updateAddrV6 assigns the IPv6 overlay address to the interface if the firewall is able to filter it
+func (e *Engine) updateAddrV6(addr string) error {
+	if addr != "" && !e.ipv6Enabled() {
+		log.Debugf("firewall doesn't support IPv6, skipping IPv6 overlay address %s", addr)
+		addr = ""
+	}
+
+	if e.wgInterface.Address().IPv6String() == addr {
+		return nil
+	}
+
+	if err := e.wgInterface.UpdateAddrV6(addr); err != nil {
+		return err
+	}
+	log.Infof("updated IPv6 overlay address to %q", addr)
+	return nil
+}
+
+// stripIPv6 returns a copy of the network map without the IPv6 overlay addresses and rules.
+// Without them WireGuard drops the IPv6 traffic of the peers instead of passing it unfiltered.
+// The received map is kept intact as network map deltas are applied to it.
+func stripIPv6(networkMap *mgmProto.NetworkMap) *mgmProto.NetworkMap {
+	stripped := proto.Clone(networkMap).(*mgmProto.NetworkMap)
+	for _, peerConfig := range append(stripped.GetRemotePeers(), stripped.GetOfflinePeers()...) {
+		peerConfig.AllowedIps = slices.DeleteFunc(peerConfig.AllowedIps, isIPv6Prefix)
+	}
+
+	stripped.FirewallRules = slices.DeleteFunc(stripped.FirewallRules, func(rule *mgmProto.FirewallRule) bool {
+		ip := net.ParseIP(rule.GetPeerIP())
+		return ip != nil && ip.To4() == nil
+	})
+	return stripped
+}
+
+func isIPv6Prefix(s string) bool {
+	prefix, err := netip.ParsePrefix(s)
+	return err == nil && prefix.Addr().Is6() && !prefix.Addr().Is4In6()
+}
+
 func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 	// intentionally leave it before checking serial because for now it can happen that peer IP changed but serial didn't
 	if networkMap.GetPeerConfig() != nil {
@@ -952,6 +1205,10 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		return nil
 	}
 
+	if !e.ipv6Enabled() {
+		networkMap = stripIPv6(networkMap)
+	}
+
 	// Apply ACLs in the beginning to avoid security leaks
 	if e.acl != nil {
 		e.acl.ApplyFiltering(networkMap)
@@ -997,6 +1254,12 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 			return err
 		}
 
+		// the peers are added again below and have to connect with Rosenpass
+		err = e.removeNonRosenpassPeers()
+		if err != nil {
+			return err
+		}
+
 		err = e.modifyPeers(networkMap.GetRemotePeers())
 		if err != nil {
 			return err
@@ -1302,6 +1565,9 @@ func (e *Engine) receiveSignalEvents() {
 					rosenpassPubKey = msg.GetBody().GetRosenpassConfig().GetRosenpassPubKey()
 					rosenpassAddr = msg.GetBody().GetRosenpassConfig().GetRosenpassServerAddr()
 				}
+				if e.refuseNonRosenpassPeer(msg.Key, rosenpassPubKey) {
+					return nil
+				}
 				conn.OnRemoteOffer(peer.OfferAnswer{
 					IceCredentials: peer.IceCredentials{
 						UFrag: remoteCred.UFrag,
@@ -1325,6 +1591,9 @@ func (e *Engine) receiveSignalEvents() {
 					rosenpassPubKey = msg.GetBody().GetRosenpassConfig().GetRosenpassPubKey()
 					rosenpassAddr = msg.GetBody().GetRosenpassConfig().GetRosenpassServerAddr()
 				}
+				if e.refuseNonRosenpassPeer(msg.Key, rosenpassPubKey) {
+					return nil
+				}
 				conn.OnRemoteAnswer(peer.OfferAnswer{
 					IceCredentials: peer.IceCredentials{
 						UFrag: remoteCred.UFrag,
@@ -1751,6 +2020,44 @@ func (e *Engine) GetLatestNetworkMap() (*mgmProto.NetworkMap, error) {
 	return nm, nil
 }
 
+// initNetworkMapState keeps the persisted network map when offline start is enabled and removes it otherwise
+func (e *Engine) initNetworkMapState() {
+	e.stateManager.RegisterState(&NetworkMapState{})
+	if err := e.stateManager.LoadState(&NetworkMapState{}); err != nil {
+		log.Warnf("failed to load the persisted network map: %v", err)
+	}
+
+	if e.config.OfflineStart || e.stateManager.GetState(&NetworkMapState{}) == nil {
+		return
+	}
+	if err := e.stateManager.DeleteState(&NetworkMapState{}); err != nil {
+		log.Errorf("failed to delete the persisted network map: %v", err)
+	}
+}
+
+// storeNetworkMapState persists the applied network map to start from when management is unreachable
+func (e *Engine) storeNetworkMapState(nm *mgmProto.NetworkMap) {
+	if !e.config.OfflineStart {
+		return
+	}
+
+	update := &mgmProto.SyncResponse{
+		NetbirdConfig: e.latestNetbirdConfig,
+		NetworkMap:    nm,
+		Checks:        e.checks,
+	}
+	state, err := newNetworkMapState(e.config.ManagementURL, e.config.WgPrivateKey, update)
+	if err != nil {
+		log.Errorf("failed to persist the network map: %v", err)
+		return
+	}
+	if err := e.stateManager.UpdateState(state); err != nil {
+		log.Errorf("failed to persist the network map: %v", err)
+		return
+	}
+	e.storedNetworkMap = nm
+}
+
 // GetWgAddr returns the wireguard address
 func (e *Engine) GetWgAddr() net.IP {
 	if e.wgInterface == nil {
@@ -6,7 +6,9 @@ import (
 	"net"
 	"net/netip"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -40,6 +42,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
 	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
+	"github.com/netbirdio/netbird/client/internal/rosenpass"
 	"github.com/netbirdio/netbird/client/internal/routemanager"
 	"github.com/netbirdio/netbird/client/ssh"
 	"github.com/netbirdio/netbird/client/system"
@@ -84,6 +87,7 @@ type MockWGIface struct {
 	ToInterfaceFunc            func() *net.Interface
 	UpFunc                     func() (*bind.UniversalUDPMuxDefault, error)
 	UpdateAddrFunc             func(newAddr string) error
+	UpdateAddrV6Func           func(newAddr string) error
 	UpdatePeerFunc             func(peerKey string, allowedIps []netip.Prefix, keepAlive time.Duration, endpoint *net.UDPAddr, preSharedKey *wgtypes.Key) error
 	RemovePeerFunc             func(peerKey string) error
 	AddAllowedIPFunc           func(peerKey string, allowedIP string) error
@@ -135,6 +139,10 @@ func (m *MockWGIface) UpdateAddr(newAddr string) error {
 	return m.UpdateAddrFunc(newAddr)
 }
 
+func (m *MockWGIface) UpdateAddrV6(newAddr string) error {
+	return m.UpdateAddrV6Func(newAddr)
+}
+
 func (m *MockWGIface) UpdatePeer(peerKey string, allowedIps []netip.Prefix, keepAlive time.Duration, endpoint *net.UDPAddr, preSharedKey *wgtypes.Key) error {
 	return m.UpdatePeerFunc(peerKey, allowedIps, keepAlive, endpoint, preSharedKey)
 }
@@ -550,6 +558,40 @@ func TestEngine_UpdateNetworkMap(t *testing.T) {
 	}
 }
 
+func TestEngine_ApplyNetworkMapDelta(t *testing.T) {
+	base := &mgmtProto.NetworkMap{
+		Serial: 10,
+		RemotePeers: []*mgmtProto.RemotePeerConfig{
+			{WgPubKey: "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=", AllowedIps: []string{"100.64.0.10/32"}},
+			{WgPubKey: "LLHf3Ma6z6mdLbriAJbqhX9+nM/B71lgw2+91q3LlhU=", AllowedIps: []string{"100.64.0.11/32"}},
+		},
+		FirewallRulesIsEmpty:       true,
+		RoutesFirewallRulesIsEmpty: true,
+	}
+	target := &mgmtProto.NetworkMap{
+		Serial: 11,
+		RemotePeers: []*mgmtProto.RemotePeerConfig{
+			{WgPubKey: "LLHf3Ma6z6mdLbriAJbqhX9+nM/B71lgw2+91q3LlhU=", AllowedIps: []string{"100.64.0.11/32"}},
+			{WgPubKey: "GGHf3Ma6z6mdLbriAJbqhX9+nM/B71lgw2+91q3LlhU=", AllowedIps: []string{"100.64.0.12/32"}},
+		},
+		FirewallRulesIsEmpty:       true,
+		RoutesFirewallRulesIsEmpty: true,
+	}
+	delta := mgmtProto.ComputeNetworkMapDelta(base, target)
+
+	engine := &Engine{networkMapDeltaBase: base}
+	nm, err := engine.applyNetworkMapDelta(delta)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), nm.GetSerial())
+	assert.Len(t, nm.GetRemotePeers(), 2)
+
+	// the delta doesn't apply to the newer map, the engine has to resync
+	engine.networkMapDeltaBase = nm
+	_, err = engine.applyNetworkMapDelta(delta)
+	require.ErrorIs(t, err, mgmtProto.ErrNetworkMapDeltaMismatch)
+	assert.Nil(t, engine.networkMapDeltaBase)
+}
+
 func TestEngine_Sync(t *testing.T) {
 	key, err := wgtypes.GeneratePrivateKey()
 	if err != nil {
@@ -635,6 +677,119 @@ func TestEngine_Sync(t *testing.T) {
 	}
 }
 
+func TestEngine_OfflineSync(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan *mgmtProto.SyncResponse)
+	defer close(updates)
+	syncFunc := func(ctx context.Context, info *system.Info, msgHandler func(msg *mgmtProto.SyncResponse) error) error {
+		for msg := range updates {
+			if err := msgHandler(msg); err != nil {
+				t.Error(err)
+			}
+		}
+		return nil
+	}
+	statusRecorder := peer.NewRecorder("https://mgm")
+	relayMgr := relayClient.NewManager(ctx, nil, key.PublicKey().String())
+	engine := NewEngine(ctx, cancel, &signal.MockClient{}, &mgmt.MockClient{SyncFunc: syncFunc}, relayMgr, &EngineConfig{
+		WgIfaceName:   "utun104",
+		WgAddr:        "100.64.0.1/24",
+		WgPrivateKey:  key,
+		WgPort:        33101,
+		OfflineStart:  true,
+		ManagementURL: "https://mgm:443",
+		StateFilePath: filepath.Join(t.TempDir(), "state.json"),
+	}, MobileDependency{}, statusRecorder, nil)
+	engine.ctx = ctx
+	engine.dnsServer = &dns.MockServer{
+		UpdateDNSServerFunc: func(serial uint64, update nbdns.Config) error { return nil },
+	}
+	// the persisted TURN credentials expired while the client was offline
+	expiredUser := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	engine.offlineSync = &mgmtProto.SyncResponse{
+		NetbirdConfig: &mgmtProto.NetbirdConfig{
+			Turns: []*mgmtProto.ProtectedHostConfig{
+				{HostConfig: &mgmtProto.HostConfig{Uri: "turn:turn.netbird.io:3478"}, User: expiredUser, Password: "secret"},
+			},
+		},
+		NetworkMap: &mgmtProto.NetworkMap{
+			Serial:     20,
+			PeerConfig: &mgmtProto.PeerConfig{Address: "100.64.0.1/24"},
+			RemotePeers: []*mgmtProto.RemotePeerConfig{
+				{WgPubKey: "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=", AllowedIps: []string{"100.64.0.10/24"}},
+			},
+		},
+	}
+
+	defer func() {
+		_ = engine.Stop()
+	}()
+
+	require.NoError(t, engine.Start())
+
+	engine.syncMsgMux.Lock()
+	assert.True(t, engine.networkMapStale, "persisted network map should be marked stale")
+	assert.Equal(t, uint64(20), engine.networkSerial)
+	assert.Equal(t, 1, len(engine.peerStore.PeersPubKey()))
+	engine.syncMsgMux.Unlock()
+	assert.True(t, statusRecorder.GetManagementState().NetworkMapStale)
+	assert.True(t, statusRecorder.GetManagementState().RelayCredentialsExpired, "expired TURN credentials should be reported")
+
+	// the first network map from management replaces the persisted one even with a lower serial
+	updates <- &mgmtProto.SyncResponse{
+		NetworkMap: &mgmtProto.NetworkMap{
+			Serial:     5,
+			PeerConfig: &mgmtProto.PeerConfig{Address: "100.64.0.1/24"},
+			RemotePeers: []*mgmtProto.RemotePeerConfig{
+				{WgPubKey: "LLHf3Ma6z6mdLbriAJbqhX9+nM/B71lgw2+91q3LlhU=", AllowedIps: []string{"100.64.0.11/24"}},
+				{WgPubKey: "GGHf3Ma6z6mdLbriAJbqhX9+nM/B71lgw2+91q3LlhU=", AllowedIps: []string{"100.64.0.12/24"}},
+			},
+		},
+	}
+
+	require.Eventually(t, func() bool {
+		engine.syncMsgMux.Lock()
+		defer engine.syncMsgMux.Unlock()
+		return !engine.networkMapStale && engine.networkSerial == 5
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, statusRecorder.GetManagementState().NetworkMapStale)
+	assert.False(t, statusRecorder.GetManagementState().RelayCredentialsExpired)
+	assert.Equal(t, 2, getPeers(engine))
+
+	state, ok := engine.stateManager.GetState(&NetworkMapState{}).(*NetworkMapState)
+	require.True(t, ok, "network map from management should be persisted")
+	update, err := state.syncResponse("https://mgm:443", key)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), update.GetNetworkMap().GetSerial())
+
+	// refreshed credentials are persisted with the current network map
+	refreshedUser := strconv.FormatInt(time.Now().Add(12*time.Hour).Unix(), 10)
+	updates <- &mgmtProto.SyncResponse{
+		NetbirdConfig: &mgmtProto.NetbirdConfig{
+			Turns: []*mgmtProto.ProtectedHostConfig{
+				{HostConfig: &mgmtProto.HostConfig{Uri: "turn:turn.netbird.io:3478"}, User: refreshedUser, Password: "secret"},
+			},
+		},
+	}
+
+	require.Eventually(t, func() bool {
+		state, ok := engine.stateManager.GetState(&NetworkMapState{}).(*NetworkMapState)
+		if !ok {
+			return false
+		}
+		update, err := state.syncResponse("https://mgm:443", key)
+		return err == nil && update.GetNetbirdConfig().GetTurns()[0].GetUser() == refreshedUser
+	}, 2*time.Second, 10*time.Millisecond)
+	update, err = engine.stateManager.GetState(&NetworkMapState{}).(*NetworkMapState).syncResponse("https://mgm:443", key)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), update.GetNetworkMap().GetSerial(), "network map should be kept with the refreshed credentials")
+}
+
 func TestEngine_UpdateNetworkMapWithRoutes(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -1151,6 +1306,23 @@ func Test_ParseNATExternalIPMappings(t *testing.T) {
 	}
 }
 
+func TestEngine_RefuseNonRosenpassPeer(t *testing.T) {
+	rosenpassPubKey := []byte("rosenpass-pub-key")
+
+	engine := &Engine{}
+	assert.False(t, engine.refuseNonRosenpassPeer("peer", nil), "peers without Rosenpass should be accepted when not required")
+
+	engine.updateRosenpassRequired(true)
+	assert.True(t, engine.refuseNonRosenpassPeer("peer", rosenpassPubKey), "all peers should be refused when Rosenpass is required but disabled")
+
+	engine.rpManager = &rosenpass.Manager{}
+	assert.True(t, engine.refuseNonRosenpassPeer("peer", nil), "peers without Rosenpass should be refused when required")
+	assert.False(t, engine.refuseNonRosenpassPeer("peer", rosenpassPubKey), "peers with Rosenpass should be accepted when required")
+
+	engine.updateRosenpassRequired(false)
+	assert.False(t, engine.refuseNonRosenpassPeer("peer", nil), "peers without Rosenpass should be accepted when no longer required")
+}
+
 func Test_CheckFilesEqual(t *testing.T) {
 	testCases := []struct {
 		name         string
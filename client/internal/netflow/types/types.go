@@ -44,6 +44,36 @@ const (
 	TypeDrop
 )
 
+// DropReason describes why a flow was dropped
+type DropReason int
+
+const (
+	DropReasonUnknown = DropReason(iota)
+	// DropReasonPolicy is used when no policy allowed the flow
+	DropReasonPolicy
+	// DropReasonConntrackLimit is used when the global or per-source connection tracking cap is reached
+	DropReasonConntrackLimit
+	// DropReasonRateLimit is used when a source exceeds its new-connection rate
+	DropReasonRateLimit
+	// DropReasonSynFlood is used when a source exceeds its half-open TCP handshakes
+	DropReasonSynFlood
+)
+
+func (r DropReason) String() string {
+	switch r {
+	case DropReasonPolicy:
+		return "policy"
+	case DropReasonConntrackLimit:
+		return "conntrack limit"
+	case DropReasonRateLimit:
+		return "rate limit"
+	case DropReasonSynFlood:
+		return "syn flood"
+	default:
+		return "unknown"
+	}
+}
+
 type Direction int
 
 func (d Direction) String() string {
@@ -87,6 +117,8 @@ type EventFields struct {
 	TxPackets        uint64
 	RxBytes          uint64
 	TxBytes          uint64
+	// DropReason is only set for TypeDrop events
+	DropReason DropReason
 }
 
 type FlowConfig struct {
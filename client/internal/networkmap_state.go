@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+	"github.com/netbirdio/netbird/encryption"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+// offlineNetworkMapMaxAge limits how long a persisted network map can be used to start without management.
+// The relay and TURN credentials it holds expire much earlier, peers are then only reachable directly,
+// see credentialsExpiry.
+const offlineNetworkMapMaxAge = 7 * 24 * time.Hour
+
+// NetworkMapState holds the last network map applied from management, encrypted with the peer's WireGuard key.
+// It is used to bring up the tunnel when management is unreachable at startup.
+type NetworkMapState struct {
+	ManagementURL string    `json:"management_url"`
+	PeerKey       string    `json:"peer_key"`
+	Serial        uint64    `json:"serial"`
+	SavedAt       time.Time `json:"saved_at"`
+	Data          []byte    `json:"data"`
+}
+
+func (s *NetworkMapState) Name() string {
+	return "network_map_state"
+}
+
+// newNetworkMapState encrypts the sync response to the peer's own key
+func newNetworkMapState(managementURL string, key wgtypes.Key, update *mgmProto.SyncResponse) (*NetworkMapState, error) {
+	data, err := encryption.EncryptMessage(key.PublicKey(), key, update)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt network map: %w", err)
+	}
+
+	return &NetworkMapState{
+		ManagementURL: managementURL,
+		PeerKey:       key.PublicKey().String(),
+		Serial:        update.GetNetworkMap().GetSerial(),
+		SavedAt:       time.Now().UTC(),
+		Data:          data,
+	}, nil
+}
+
+// syncResponse decrypts the persisted sync response after checking it belongs to this peer and management server
+func (s *NetworkMapState) syncResponse(managementURL string, key wgtypes.Key) (*mgmProto.SyncResponse, error) {
+	if s.ManagementURL != managementURL {
+		return nil, fmt.Errorf("persisted network map belongs to management %s", s.ManagementURL)
+	}
+	if s.PeerKey != key.PublicKey().String() {
+		return nil, errors.New("persisted network map belongs to a different peer key")
+	}
+	if age := time.Since(s.SavedAt); age > offlineNetworkMapMaxAge {
+		return nil, fmt.Errorf("persisted network map is too old: %s", age.Round(time.Second))
+	}
+
+	update := &mgmProto.SyncResponse{}
+	if err := encryption.DecryptMessage(key.PublicKey(), key, s.Data, update); err != nil {
+		return nil, fmt.Errorf("decrypt network map: %w", err)
+	}
+	if update.GetNetworkMap() == nil || update.GetNetworkMap().GetPeerConfig() == nil {
+		return nil, errors.New("persisted network map is incomplete")
+	}
+
+	return update, nil
+}
+
+// loadOfflineSyncResponse reads the persisted network map from the state file.
+// It returns nil if there is no usable network map.
+func loadOfflineSyncResponse(statePath, managementURL string, key wgtypes.Key) *mgmProto.SyncResponse {
+	if statePath == "" {
+		statePath = statemanager.GetDefaultStatePath()
+	}
+	if statePath == "" {
+		return nil
+	}
+
+	stateManager := statemanager.New(statePath)
+	stateManager.RegisterState(&NetworkMapState{})
+	if err := stateManager.LoadState(&NetworkMapState{}); err != nil {
+		log.Warnf("failed to load the persisted network map: %v", err)
+		return nil
+	}
+
+	state, ok := stateManager.GetState(&NetworkMapState{}).(*NetworkMapState)
+	if !ok || state == nil {
+		log.Debugf("no persisted network map found")
+		return nil
+	}
+
+	update, err := state.syncResponse(managementURL, key)
+	if err != nil {
+		log.Warnf("not using the persisted network map: %v", err)
+		return nil
+	}
+
+	return update
+}
+
+// offlineLoginResponse builds the login values the client needs to start from a persisted sync response
+func offlineLoginResponse(update *mgmProto.SyncResponse) *mgmProto.LoginResponse {
+	return &mgmProto.LoginResponse{
+		NetbirdConfig: update.GetNetbirdConfig(),
+		PeerConfig:    update.GetNetworkMap().GetPeerConfig(),
+		Checks:        update.GetChecks(),
+	}
+}
+
+// credentialsExpiry returns when the first of the relay and TURN credentials of the config expires.
+// Management issues time based credentials with the expiry unix timestamp as payload, a zero time is
+// returned when the config holds no credentials.
+func credentialsExpiry(config *mgmProto.NetbirdConfig) time.Time {
+	payloads := []string{config.GetRelay().GetTokenPayload()}
+	for _, turn := range config.GetTurns() {
+		payloads = append(payloads, turn.GetUser())
+	}
+
+	var expiry time.Time
+	for _, payload := range payloads {
+		timestamp, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		if t := time.Unix(timestamp, 0); expiry.IsZero() || t.Before(expiry) {
+			expiry = t
+		}
+	}
+	return expiry
+}
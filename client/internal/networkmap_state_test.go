@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+const testManagementURL = "https://api.netbird.io:443"
+
+func testSyncResponse() *mgmProto.SyncResponse {
+	return &mgmProto.SyncResponse{
+		NetbirdConfig: &mgmProto.NetbirdConfig{
+			Signal: &mgmProto.HostConfig{Uri: "signal.netbird.io:443", Protocol: mgmProto.HostConfig_HTTPS},
+		},
+		NetworkMap: &mgmProto.NetworkMap{
+			Serial:     10,
+			PeerConfig: &mgmProto.PeerConfig{Address: "100.64.0.1/16", Fqdn: "peer.netbird.cloud"},
+			RemotePeers: []*mgmProto.RemotePeerConfig{
+				{WgPubKey: "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=", AllowedIps: []string{"100.64.0.2/32"}},
+			},
+		},
+	}
+}
+
+func TestNetworkMapState_SyncResponse(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	otherKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	update := testSyncResponse()
+	state, err := newNetworkMapState(testManagementURL, key, update)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), state.Serial)
+	assert.NotContains(t, string(state.Data), "peer.netbird.cloud", "network map should be encrypted")
+
+	decrypted, err := state.syncResponse(testManagementURL, key)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(update, decrypted), "decrypted sync response should match the persisted one")
+
+	_, err = state.syncResponse("https://other.example.com:443", key)
+	assert.Error(t, err, "network map of a different management server should be rejected")
+
+	_, err = state.syncResponse(testManagementURL, otherKey)
+	assert.Error(t, err, "network map of a different peer key should be rejected")
+
+	tampered := *state
+	tampered.Data = append([]byte{}, state.Data...)
+	tampered.Data[len(tampered.Data)-1] ^= 0xff
+	_, err = tampered.syncResponse(testManagementURL, key)
+	assert.Error(t, err, "tampered network map should be rejected")
+
+	expired := *state
+	expired.SavedAt = time.Now().Add(-offlineNetworkMapMaxAge - time.Minute)
+	_, err = expired.syncResponse(testManagementURL, key)
+	assert.Error(t, err, "expired network map should be rejected")
+}
+
+func TestLoadOfflineSyncResponse(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	assert.Nil(t, loadOfflineSyncResponse(statePath, testManagementURL, key), "missing state file should not return a network map")
+
+	update := testSyncResponse()
+	state, err := newNetworkMapState(testManagementURL, key, update)
+	require.NoError(t, err)
+
+	stateManager := statemanager.New(statePath)
+	stateManager.RegisterState(&NetworkMapState{})
+	require.NoError(t, stateManager.UpdateState(state))
+	require.NoError(t, stateManager.PersistState(context.Background()))
+
+	loaded := loadOfflineSyncResponse(statePath, testManagementURL, key)
+	require.NotNil(t, loaded)
+	assert.True(t, proto.Equal(update, loaded), "loaded sync response should match the persisted one")
+
+	loginResp := offlineLoginResponse(loaded)
+	assert.Equal(t, "100.64.0.1/16", loginResp.GetPeerConfig().GetAddress())
+	assert.Equal(t, "signal.netbird.io:443", loginResp.GetNetbirdConfig().GetSignal().GetUri())
+
+	assert.Nil(t, loadOfflineSyncResponse(statePath, "https://other.example.com:443", key), "network map of a different management server should not be used")
+}
+
+func TestCredentialsExpiry(t *testing.T) {
+	relayExpiry := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+	turnExpiry := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	assert.True(t, credentialsExpiry(nil).IsZero(), "config without credentials should not expire")
+
+	config := &mgmProto.NetbirdConfig{
+		Relay: &mgmProto.RelayConfig{
+			Urls:           []string{"rels://relay.netbird.io:443"},
+			TokenPayload:   strconv.FormatInt(relayExpiry.Unix(), 10),
+			TokenSignature: "signature",
+		},
+	}
+	assert.True(t, relayExpiry.Equal(credentialsExpiry(config)))
+
+	config.Turns = []*mgmProto.ProtectedHostConfig{
+		{HostConfig: &mgmProto.HostConfig{Uri: "turn:turn.netbird.io:3478"}, User: strconv.FormatInt(turnExpiry.Unix(), 10)},
+		{HostConfig: &mgmProto.HostConfig{Uri: "turn:static.netbird.io:3478"}, User: "static-user"},
+	}
+	assert.True(t, turnExpiry.Equal(credentialsExpiry(config)), "the first expiring credentials should be returned")
+}
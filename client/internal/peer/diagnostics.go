@@ -0,0 +1,185 @@
+package peer
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/pion/ice/v3"
+
+	"github.com/netbirdio/netbird/client/internal/peer/guard"
+)
+
+// pairsSnapshotInterval is how often the candidate pair states are recorded during the connectivity checks
+const pairsSnapshotInterval = 2 * time.Second
+
+// ICECandidateInfo describes a local or remote ICE candidate
+type ICECandidateInfo struct {
+	ID             string
+	Type           string
+	Network        string
+	Address        string
+	Port           int
+	RelatedAddress string
+	// IgnoredReason is set for remote candidates that were not added to the ICE agent
+	IgnoredReason string
+}
+
+// ICECandidatePairInfo describes the connectivity check state of a candidate pair
+type ICECandidatePairInfo struct {
+	Local     ICECandidateInfo
+	Remote    ICECandidateInfo
+	State     string
+	Nominated bool
+}
+
+// ICEDiagnostics contains the details of the latest ICE connection attempt
+type ICEDiagnostics struct {
+	State            string
+	LastAttempt      time.Time
+	LastError        string
+	LocalCandidates  []ICECandidateInfo
+	RemoteCandidates []ICECandidateInfo
+	CandidatePairs   []ICECandidatePairInfo
+}
+
+// ConnDiagnostics contains the details of the connection to a remote peer
+type ConnDiagnostics struct {
+	ICE                    ICEDiagnostics
+	ICEStatus              ConnStatus
+	RelayStatus            ConnStatus
+	RelaySupportedLocally  bool
+	RelaySupportedByRemote bool
+	ReconnectHistory       []guard.ReconnectEvent
+}
+
+// Diagnostics returns the state of the ICE and relay workers and the reconnect history of the guard
+func (conn *Conn) Diagnostics() ConnDiagnostics {
+	return ConnDiagnostics{
+		ICE:                    conn.workerICE.Diagnostics(),
+		ICEStatus:              conn.statusICE.Get(),
+		RelayStatus:            conn.statusRelay.Get(),
+		RelaySupportedLocally:  conn.workerRelay.RelayIsSupportedLocally(),
+		RelaySupportedByRemote: conn.workerRelay.relaySupportedOnRemotePeer.Load(),
+		ReconnectHistory:       conn.guard.History(),
+	}
+}
+
+// Diagnostics returns the details of the latest ICE connection attempt. The candidate pairs are refreshed
+// from the agent while it is running, otherwise the pairs of the last closed agent are returned.
+func (w *WorkerICE) Diagnostics() ICEDiagnostics {
+	w.muxAgent.Lock()
+	if w.agent != nil {
+		w.recordCandidatePairs(w.agent)
+	}
+	w.muxAgent.Unlock()
+
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+
+	diag := w.diag
+	diag.LocalCandidates = slices.Clone(w.diag.LocalCandidates)
+	diag.RemoteCandidates = slices.Clone(w.diag.RemoteCandidates)
+	diag.CandidatePairs = slices.Clone(w.diag.CandidatePairs)
+	return diag
+}
+
+// resetDiagnostics starts recording a new connection attempt
+func (w *WorkerICE) resetDiagnostics() {
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+
+	w.diag = ICEDiagnostics{
+		State:       ice.ConnectionStateNew.String(),
+		LastAttempt: time.Now(),
+	}
+}
+
+func (w *WorkerICE) recordState(state ice.ConnectionState) {
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+	w.diag.State = state.String()
+}
+
+func (w *WorkerICE) recordError(err error) {
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+	w.diag.LastError = err.Error()
+}
+
+func (w *WorkerICE) recordLocalCandidate(candidate ice.Candidate) {
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+	w.diag.LocalCandidates = append(w.diag.LocalCandidates, toCandidateInfo(candidate))
+}
+
+func (w *WorkerICE) recordRemoteCandidate(candidate ice.Candidate, ignoredReason string) {
+	info := toCandidateInfo(candidate)
+	info.IgnoredReason = ignoredReason
+
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+	w.diag.RemoteCandidates = append(w.diag.RemoteCandidates, info)
+}
+
+// snapshotCandidatePairs records the candidate pair states while the connectivity checks are running.
+// The agent drops the pairs when the checks fail, so they can't be read after the failure.
+func (w *WorkerICE) snapshotCandidatePairs(ctx context.Context, agent *ice.Agent) {
+	ticker := time.NewTicker(pairsSnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.recordCandidatePairs(agent)
+		}
+	}
+}
+
+// recordCandidatePairs stores the candidate pair states of the agent. An empty list doesn't replace
+// the recorded pairs, to keep the pairs of a failed attempt.
+func (w *WorkerICE) recordCandidatePairs(agent *ice.Agent) {
+	stats := agent.GetCandidatePairsStats()
+	if len(stats) == 0 {
+		return
+	}
+
+	w.diagMu.Lock()
+	defer w.diagMu.Unlock()
+
+	pairs := make([]ICECandidatePairInfo, 0, len(stats))
+	for _, stat := range stats {
+		pairs = append(pairs, ICECandidatePairInfo{
+			Local:     findCandidate(w.diag.LocalCandidates, stat.LocalCandidateID),
+			Remote:    findCandidate(w.diag.RemoteCandidates, stat.RemoteCandidateID),
+			State:     stat.State.String(),
+			Nominated: stat.Nominated,
+		})
+	}
+	w.diag.CandidatePairs = pairs
+}
+
+func findCandidate(candidates []ICECandidateInfo, id string) ICECandidateInfo {
+	for _, c := range candidates {
+		if c.ID == id {
+			return c
+		}
+	}
+	return ICECandidateInfo{ID: id}
+}
+
+func toCandidateInfo(candidate ice.Candidate) ICECandidateInfo {
+	info := ICECandidateInfo{
+		ID:      candidate.ID(),
+		Type:    candidate.Type().String(),
+		Network: candidate.NetworkType().String(),
+		Address: candidate.Address(),
+		Port:    candidate.Port(),
+	}
+	if related := candidate.RelatedAddress(); related != nil {
+		info.RelatedAddress = related.String()
+	}
+	return info
+}
@@ -2,6 +2,8 @@ package guard
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -10,8 +12,18 @@ import (
 
 const (
 	reconnectMaxElapsedTime = 30 * time.Minute
+
+	// maxHistorySize is the number of reconnect events kept for diagnostics
+	maxHistorySize = 20
 )
 
+// ReconnectEvent is an event the guard reacted on, OfferSent is true when it triggered a new offer
+type ReconnectEvent struct {
+	Time      time.Time
+	Reason    string
+	OfferSent bool
+}
+
 type isConnectedFunc func() bool
 
 // Guard is responsible for the reconnection logic.
@@ -31,6 +43,9 @@ type Guard struct {
 	srWatcher               *SRWatcher
 	relayedConnDisconnected chan struct{}
 	iCEConnDisconnected     chan struct{}
+
+	historyMu sync.Mutex
+	history   []ReconnectEvent
 }
 
 func NewGuard(log *log.Entry, isController bool, isConnectedFn isConnectedFunc, timeout time.Duration, srWatcher *SRWatcher) *Guard {
@@ -68,6 +83,13 @@ func (g *Guard) SetICEConnDisconnected() {
 	}
 }
 
+// History returns the latest reconnect events, the oldest first
+func (g *Guard) History() []ReconnectEvent {
+	g.historyMu.Lock()
+	defer g.historyMu.Unlock()
+	return slices.Clone(g.history)
+}
+
 // reconnectLoopWithRetry periodically check (max 30 min) the connection status.
 // Try to send offer while the P2P is not established or while the Relay is not connected if is it supported
 func (g *Guard) reconnectLoopWithRetry(ctx context.Context) {
@@ -93,23 +115,26 @@ func (g *Guard) reconnectLoopWithRetry(ctx context.Context) {
 			}
 
 			if !g.isConnectedOnAllWay() {
-				g.triggerOfferSending()
+				g.triggerOfferSending("connection is not established on all ways")
 			}
 
 		case <-g.relayedConnDisconnected:
 			g.log.Debugf("Relay connection changed, reset reconnection ticker")
+			g.recordEvent("relay connection changed, reset reconnection ticker", false)
 			ticker.Stop()
 			ticker = g.prepareExponentTicker(ctx)
 			tickerChannel = ticker.C
 
 		case <-g.iCEConnDisconnected:
 			g.log.Debugf("ICE connection changed, reset reconnection ticker")
+			g.recordEvent("ICE connection changed, reset reconnection ticker", false)
 			ticker.Stop()
 			ticker = g.prepareExponentTicker(ctx)
 			tickerChannel = ticker.C
 
 		case <-srReconnectedChan:
 			g.log.Debugf("has network changes, reset reconnection ticker")
+			g.recordEvent("signal or relay reconnected, reset reconnection ticker", false)
 			ticker.Stop()
 			ticker = g.prepareExponentTicker(ctx)
 			tickerChannel = ticker.C
@@ -134,12 +159,12 @@ func (g *Guard) listenForDisconnectEvents(ctx context.Context) {
 		select {
 		case <-g.relayedConnDisconnected:
 			g.log.Debugf("Relay connection changed, triggering reconnect")
-			g.triggerOfferSending()
+			g.triggerOfferSending("relay connection changed")
 		case <-g.iCEConnDisconnected:
 			g.log.Debugf("ICE state changed, try to send new offer")
-			g.triggerOfferSending()
+			g.triggerOfferSending("ICE connection changed")
 		case <-srReconnectedChan:
-			g.triggerOfferSending()
+			g.triggerOfferSending("signal or relay reconnected")
 		case <-ctx.Done():
 			g.log.Debugf("context is done, stop reconnect loop")
 			return
@@ -164,13 +189,29 @@ func (g *Guard) prepareExponentTicker(ctx context.Context) *backoff.Ticker {
 	return ticker
 }
 
-func (g *Guard) triggerOfferSending() {
+func (g *Guard) triggerOfferSending(reason string) {
+	g.recordEvent(reason, true)
+
 	select {
 	case g.Reconnect <- struct{}{}:
 	default:
 	}
 }
 
+func (g *Guard) recordEvent(reason string, offerSent bool) {
+	g.historyMu.Lock()
+	defer g.historyMu.Unlock()
+
+	g.history = append(g.history, ReconnectEvent{
+		Time:      time.Now(),
+		Reason:    reason,
+		OfferSent: offerSent,
+	})
+	if len(g.history) > maxHistorySize {
+		g.history = g.history[len(g.history)-maxHistorySize:]
+	}
+}
+
 // Give chance to the peer to establish the initial connection.
 // With it, we can decrease to send necessary offer
 func waitForInitialConnectionTry(ctx context.Context) {
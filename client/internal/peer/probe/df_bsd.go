@@ -0,0 +1,12 @@
+//go:build darwin || freebsd
+
+package probe
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// setDontFragment sets the don't fragment bit on outgoing packets
+func setDontFragment(fd uintptr) error {
+	return unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_DONTFRAG, 1)
+}
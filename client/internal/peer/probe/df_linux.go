@@ -0,0 +1,11 @@
+package probe
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// setDontFragment sets the don't fragment bit on outgoing packets and ignores the cached path MTU,
+// so probes larger than the path MTU are dropped on the path instead of failing locally
+func setDontFragment(fd uintptr) error {
+	return unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_PROBE)
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package probe
+
+// setDontFragment is a no-op, path MTU discovery reports the interface MTU on these platforms
+func setDontFragment(uintptr) error {
+	return nil
+}
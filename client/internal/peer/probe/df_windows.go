@@ -0,0 +1,13 @@
+package probe
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// ipDontFragment is the IP_DONTFRAGMENT socket option from ws2ipdef.h
+const ipDontFragment = 14
+
+// setDontFragment sets the don't fragment bit on outgoing packets
+func setDontFragment(fd uintptr) error {
+	return windows.SetsockoptInt(windows.Handle(fd), windows.IPPROTO_IP, ipDontFragment, 1)
+}
@@ -0,0 +1,188 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	// ipv4HeaderLen and icmpHeaderLen are subtracted from the probe size to get the echo payload size
+	ipv4HeaderLen = 20
+	icmpHeaderLen = 8
+
+	protocolICMP = 1
+)
+
+// ErrPacketTooBig is returned when the probe doesn't fit the MTU of the outgoing interface
+var ErrPacketTooBig = errors.New("packet too big")
+
+// Pinger sends echo requests to peers and waits for their replies
+type Pinger interface {
+	// Ping sends an echo request of size bytes, IP header included, and returns the round trip time
+	Ping(ctx context.Context, addr netip.Addr, size int) (time.Duration, error)
+	Close() error
+}
+
+type pendingEcho struct {
+	addr  netip.Addr
+	reply chan struct{}
+}
+
+// ICMPPinger sends ICMP echo requests with the don't fragment bit set, so oversized probes are dropped
+// on the path instead of being fragmented. It requires a raw socket, which is available to the daemon.
+type ICMPPinger struct {
+	conn net.PacketConn
+	id   int
+	seq  atomic.Uint32
+
+	mu      sync.Mutex
+	pending map[int]pendingEcho
+
+	done chan struct{}
+}
+
+// NewICMPPinger opens the raw ICMP socket and starts reading the replies
+func NewICMPPinger() (*ICMPPinger, error) {
+	lc := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = setDontFragment(fd)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+
+	conn, err := lc.ListenPacket(context.Background(), "ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil, fmt.Errorf("listen icmp: %w", err)
+	}
+
+	p := &ICMPPinger{
+		conn:    conn,
+		id:      rand.Intn(0xffff),
+		pending: make(map[int]pendingEcho),
+		done:    make(chan struct{}),
+	}
+	go p.readReplies()
+
+	return p, nil
+}
+
+// Ping sends an echo request of size bytes to addr and waits for the reply until ctx is done
+func (p *ICMPPinger) Ping(ctx context.Context, addr netip.Addr, size int) (time.Duration, error) {
+	if !addr.Is4() {
+		return 0, fmt.Errorf("unsupported address %s", addr)
+	}
+
+	payloadSize := size - ipv4HeaderLen - icmpHeaderLen
+	if payloadSize < 0 {
+		payloadSize = 0
+	}
+
+	seq := int(p.seq.Add(1) & 0xffff)
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{
+			ID:   p.id,
+			Seq:  seq,
+			Data: make([]byte, payloadSize),
+		},
+	}
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, fmt.Errorf("marshal echo request: %w", err)
+	}
+
+	reply := make(chan struct{})
+	p.mu.Lock()
+	p.pending[seq] = pendingEcho{addr: addr, reply: reply}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, seq)
+		p.mu.Unlock()
+	}()
+
+	start := time.Now()
+	if _, err := p.conn.WriteTo(b, &net.IPAddr{IP: addr.AsSlice()}); err != nil {
+		if errors.Is(err, syscall.EMSGSIZE) {
+			return 0, ErrPacketTooBig
+		}
+		return 0, fmt.Errorf("send echo request: %w", err)
+	}
+
+	select {
+	case <-reply:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-p.done:
+		return 0, net.ErrClosed
+	}
+}
+
+// Close stops reading replies and closes the socket
+func (p *ICMPPinger) Close() error {
+	select {
+	case <-p.done:
+		return nil
+	default:
+		close(p.done)
+	}
+	return p.conn.Close()
+}
+
+func (p *ICMPPinger) readReplies() {
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := p.conn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-p.done:
+			default:
+				log.Debugf("stopped reading icmp replies: %v", err)
+			}
+			return
+		}
+
+		msg, err := icmp.ParseMessage(protocolICMP, buf[:n])
+		if err != nil || msg.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+
+		echo, ok := msg.Body.(*icmp.Echo)
+		if !ok || echo.ID != p.id {
+			continue
+		}
+
+		ipAddr, ok := from.(*net.IPAddr)
+		if !ok {
+			continue
+		}
+		addr, _ := netip.AddrFromSlice(ipAddr.IP)
+
+		p.mu.Lock()
+		pending, ok := p.pending[echo.Seq]
+		if ok && pending.addr == addr.Unmap() {
+			close(pending.reply)
+			delete(p.pending, echo.Seq)
+		}
+		p.mu.Unlock()
+	}
+}
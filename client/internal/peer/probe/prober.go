@@ -0,0 +1,295 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/netip"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+const (
+	defaultInterval    = 5 * time.Second
+	defaultTimeout     = 2 * time.Second
+	defaultMTUInterval = 10 * time.Minute
+
+	// windowSize is the number of probes the loss and jitter statistics are calculated from
+	windowSize = 20
+	// minProbeSize is the size of the periodic probes, small enough to not be affected by the path MTU
+	minProbeSize = 64
+	// minPathMTU is the minimum IPv4 datagram size every host has to accept
+	minPathMTU = 576
+)
+
+// Prober periodically sends probes over the tunnel to the connected peers and records
+// the packet loss, jitter and path MTU in the status recorder
+type Prober struct {
+	recorder *peer.Status
+	pinger   Pinger
+	maxMTU   int
+
+	interval    time.Duration
+	timeout     time.Duration
+	mtuInterval time.Duration
+
+	mu    sync.Mutex
+	peers map[string]*peerStats
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type peerStats struct {
+	// results is a ring buffer of the last probe results
+	results [windowSize]bool
+	next    int
+	count   int
+
+	lastRTT  time.Duration
+	hasRTT   bool
+	jitter   float64
+	answered bool
+
+	pathMTU      int
+	mtuCheckedAt time.Time
+	mtuRunning   bool
+}
+
+// NewProber creates a prober, maxMTU is the MTU of the tunnel interface and the upper bound of the path MTU discovery
+func NewProber(recorder *peer.Status, pinger Pinger, maxMTU int) *Prober {
+	return &Prober{
+		recorder:    recorder,
+		pinger:      pinger,
+		maxMTU:      maxMTU,
+		interval:    defaultInterval,
+		timeout:     defaultTimeout,
+		mtuInterval: defaultMTUInterval,
+		peers:       make(map[string]*peerStats),
+	}
+}
+
+// Start runs the probe rounds until Stop is called
+func (p *Prober) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.probeRound(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops probing, waits for the running probes and closes the pinger
+func (p *Prober) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+
+	if err := p.pinger.Close(); err != nil {
+		log.Debugf("failed to close pinger: %v", err)
+	}
+}
+
+// probeRound probes all connected peers concurrently and forgets the statistics of disconnected peers
+func (p *Prober) probeRound(ctx context.Context) {
+	connected := make(map[string]netip.Addr)
+	for _, state := range p.recorder.GetFullStatus().Peers {
+		if state.ConnStatus != peer.StatusConnected {
+			continue
+		}
+		addr, err := netip.ParseAddr(state.IP)
+		if err != nil {
+			continue
+		}
+		connected[state.PubKey] = addr
+	}
+
+	p.mu.Lock()
+	for pubKey := range p.peers {
+		if _, ok := connected[pubKey]; !ok {
+			delete(p.peers, pubKey)
+			// the peer might have been removed in the meantime, nothing to reset then
+			_ = p.recorder.UpdatePeerQuality(pubKey, peer.ConnQuality{})
+		}
+	}
+	for pubKey := range connected {
+		if _, ok := p.peers[pubKey]; !ok {
+			p.peers[pubKey] = &peerStats{}
+		}
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for pubKey, addr := range connected {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.probePeer(ctx, pubKey, addr)
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Prober) probePeer(ctx context.Context, pubKey string, addr netip.Addr) {
+	pingCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	rtt, err := p.pinger.Ping(pingCtx, addr, minProbeSize)
+	cancel()
+
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		log.Tracef("failed to probe peer %s: %v", pubKey, err)
+	}
+
+	p.mu.Lock()
+	stats, ok := p.peers[pubKey]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	stats.record(err == nil, rtt)
+	quality := stats.quality()
+
+	startMTU := stats.answered && !stats.mtuRunning && time.Since(stats.mtuCheckedAt) >= p.mtuInterval
+	if startMTU {
+		stats.mtuRunning = true
+	}
+	p.mu.Unlock()
+
+	if err := p.recorder.UpdatePeerQuality(pubKey, quality); err != nil {
+		log.Debugf("failed to update connection quality of peer %s: %v", pubKey, err)
+	}
+
+	if startMTU {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.discoverMTU(ctx, pubKey, addr)
+		}()
+	}
+}
+
+// discoverMTU searches the largest probe size answered by the peer between the minimum IPv4 MTU and the
+// interface MTU. The probes have the don't fragment bit set, so larger packets are dropped on the path.
+func (p *Prober) discoverMTU(ctx context.Context, pubKey string, addr netip.Addr) {
+	mtu := 0
+	switch {
+	case p.pingSize(ctx, addr, p.maxMTU):
+		mtu = p.maxMTU
+	case p.pingSize(ctx, addr, minPathMTU):
+		low, high := minPathMTU, p.maxMTU-1
+		for low < high && ctx.Err() == nil {
+			mid := (low + high + 1) / 2
+			if p.pingSize(ctx, addr, mid) {
+				low = mid
+			} else {
+				high = mid - 1
+			}
+		}
+		mtu = low
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	p.mu.Lock()
+	stats, ok := p.peers[pubKey]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	stats.mtuRunning = false
+	stats.mtuCheckedAt = time.Now()
+	if mtu > 0 {
+		stats.pathMTU = mtu
+	}
+	quality := stats.quality()
+	p.mu.Unlock()
+
+	log.Debugf("discovered path MTU %d to peer %s", mtu, pubKey)
+
+	if err := p.recorder.UpdatePeerQuality(pubKey, quality); err != nil {
+		log.Debugf("failed to update connection quality of peer %s: %v", pubKey, err)
+	}
+}
+
+// pingSize retries a lost probe once, to not mistake a single lost packet for a too small path MTU
+func (p *Prober) pingSize(ctx context.Context, addr netip.Addr, size int) bool {
+	for i := 0; i < 2; i++ {
+		pingCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		_, err := p.pinger.Ping(pingCtx, addr, size)
+		cancel()
+
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, ErrPacketTooBig) || ctx.Err() != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// record adds a probe result to the window and updates the jitter as defined in RFC 3550
+func (s *peerStats) record(received bool, rtt time.Duration) {
+	s.results[s.next] = received
+	s.next = (s.next + 1) % windowSize
+	if s.count < windowSize {
+		s.count++
+	}
+
+	if !received {
+		return
+	}
+	s.answered = true
+
+	if s.hasRTT {
+		d := math.Abs(float64(rtt - s.lastRTT))
+		s.jitter += (d - s.jitter) / 16
+	}
+	s.lastRTT = rtt
+	s.hasRTT = true
+}
+
+func (s *peerStats) quality() peer.ConnQuality {
+	received := 0
+	for i := 0; i < s.count; i++ {
+		if s.results[i] {
+			received++
+		}
+	}
+
+	var loss float64
+	if s.count > 0 {
+		loss = float64(s.count-received) / float64(s.count)
+	}
+
+	return peer.ConnQuality{
+		ProbesSent:     s.count,
+		ProbesReceived: received,
+		PacketLoss:     loss,
+		Jitter:         time.Duration(s.jitter),
+		PathMTU:        s.pathMTU,
+		Answered:       s.answered,
+		UpdatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,202 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+type fakePinger struct {
+	mu sync.Mutex
+	// pathMTU is the largest probe answered, lost drops the next probes of the given peers
+	pathMTU int
+	lost    map[netip.Addr]int
+	rtts    []time.Duration
+}
+
+func (f *fakePinger) Ping(_ context.Context, addr netip.Addr, size int) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if size > f.pathMTU {
+		return 0, context.DeadlineExceeded
+	}
+	if f.lost[addr] > 0 {
+		f.lost[addr]--
+		return 0, context.DeadlineExceeded
+	}
+	if size > minProbeSize {
+		return time.Millisecond, nil
+	}
+	if len(f.rtts) == 0 {
+		return 10 * time.Millisecond, nil
+	}
+	rtt := f.rtts[0]
+	f.rtts = f.rtts[1:]
+	return rtt, nil
+}
+
+func (f *fakePinger) Close() error {
+	return nil
+}
+
+func newTestRecorder(t *testing.T, peers map[string]string) *peer.Status {
+	t.Helper()
+
+	recorder := peer.NewRecorder("https://mgm")
+	for pubKey, ip := range peers {
+		require.NoError(t, recorder.AddPeer(pubKey, pubKey+".netbird.cloud"))
+		require.NoError(t, recorder.UpdatePeerState(peer.State{
+			PubKey:     pubKey,
+			IP:         ip,
+			ConnStatus: peer.StatusConnected,
+		}))
+	}
+	return recorder
+}
+
+func TestProber_PacketLossAndJitter(t *testing.T) {
+	recorder := newTestRecorder(t, map[string]string{"peerA": "100.64.0.1"})
+	pinger := &fakePinger{
+		pathMTU: 1280,
+		lost:    map[netip.Addr]int{netip.MustParseAddr("100.64.0.1"): 1},
+		rtts:    []time.Duration{10 * time.Millisecond, 26 * time.Millisecond, 10 * time.Millisecond},
+	}
+	prober := NewProber(recorder, pinger, 1280)
+	prober.mtuInterval = time.Hour
+
+	for i := 0; i < 4; i++ {
+		prober.probeRound(context.Background())
+	}
+
+	state, err := recorder.GetPeer("peerA")
+	require.NoError(t, err)
+	assert.Equal(t, 4, state.Quality.ProbesSent)
+	assert.Equal(t, 3, state.Quality.ProbesReceived)
+	assert.InDelta(t, 0.25, state.Quality.PacketLoss, 0.0001)
+	// |26-10|/16 = 1ms, then 1 + (16-1)/16 = 1.9375ms
+	assert.Equal(t, 1937500*time.Nanosecond, state.Quality.Jitter)
+	assert.True(t, state.Quality.Answered)
+}
+
+func TestProber_WindowSize(t *testing.T) {
+	recorder := newTestRecorder(t, map[string]string{"peerA": "100.64.0.1"})
+	pinger := &fakePinger{
+		pathMTU: 1280,
+		lost:    map[netip.Addr]int{netip.MustParseAddr("100.64.0.1"): windowSize},
+	}
+	prober := NewProber(recorder, pinger, 1280)
+
+	for i := 0; i < windowSize+5; i++ {
+		prober.probeRound(context.Background())
+	}
+
+	state, err := recorder.GetPeer("peerA")
+	require.NoError(t, err)
+	assert.Equal(t, windowSize, state.Quality.ProbesSent)
+	assert.Equal(t, 5, state.Quality.ProbesReceived, "lost probes should leave the window")
+}
+
+func TestProber_NeverAnswered(t *testing.T) {
+	recorder := newTestRecorder(t, map[string]string{"peerA": "100.64.0.1"})
+	pinger := &fakePinger{
+		pathMTU: 1280,
+		lost:    map[netip.Addr]int{netip.MustParseAddr("100.64.0.1"): 100},
+	}
+	prober := NewProber(recorder, pinger, 1280)
+
+	prober.probeRound(context.Background())
+	prober.wg.Wait()
+
+	state, err := recorder.GetPeer("peerA")
+	require.NoError(t, err)
+	assert.False(t, state.Quality.Answered)
+	assert.Equal(t, 1.0, state.Quality.PacketLoss)
+	assert.Zero(t, state.Quality.PathMTU, "MTU discovery shouldn't run for peers not answering")
+}
+
+func TestProber_PathMTU(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pathMTU  int
+		expected int
+	}{
+		{name: "interface MTU", pathMTU: 1500, expected: 1280},
+		{name: "lower path MTU", pathMTU: 1232, expected: 1232},
+		{name: "below minimum", pathMTU: 500, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := newTestRecorder(t, map[string]string{"peerA": "100.64.0.1"})
+			prober := NewProber(recorder, &fakePinger{pathMTU: tc.pathMTU}, 1280)
+
+			prober.probeRound(context.Background())
+			prober.wg.Wait()
+
+			state, err := recorder.GetPeer("peerA")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, state.Quality.PathMTU)
+		})
+	}
+}
+
+func TestProber_ForgetsDisconnectedPeers(t *testing.T) {
+	recorder := newTestRecorder(t, map[string]string{"peerA": "100.64.0.1", "peerB": "100.64.0.2"})
+	prober := NewProber(recorder, &fakePinger{pathMTU: 1280}, 1280)
+	prober.mtuInterval = time.Hour
+
+	prober.probeRound(context.Background())
+
+	require.NoError(t, recorder.UpdatePeerState(peer.State{
+		PubKey:     "peerB",
+		IP:         "100.64.0.2",
+		ConnStatus: peer.StatusDisconnected,
+	}))
+	prober.probeRound(context.Background())
+
+	stateA, err := recorder.GetPeer("peerA")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stateA.Quality.ProbesSent)
+
+	stateB, err := recorder.GetPeer("peerB")
+	require.NoError(t, err)
+	assert.Equal(t, peer.ConnQuality{}, stateB.Quality)
+
+	prober.mu.Lock()
+	defer prober.mu.Unlock()
+	assert.Len(t, prober.peers, 1)
+}
+
+func TestProber_PacketTooBig(t *testing.T) {
+	pinger := &tooBigPinger{limit: 1000}
+	prober := NewProber(peer.NewRecorder("https://mgm"), pinger, 1280)
+
+	assert.False(t, prober.pingSize(context.Background(), netip.MustParseAddr("100.64.0.1"), 1200))
+	assert.Equal(t, 1, pinger.calls, "probes failing locally shouldn't be retried")
+}
+
+type tooBigPinger struct {
+	limit int
+	calls int
+}
+
+func (p *tooBigPinger) Ping(_ context.Context, _ netip.Addr, size int) (time.Duration, error) {
+	p.calls++
+	if size > p.limit {
+		return 0, ErrPacketTooBig
+	}
+	return 0, errors.New("unexpected")
+}
+
+func (p *tooBigPinger) Close() error {
+	return nil
+}
@@ -53,9 +53,26 @@ type State struct {
 	BytesRx                    int64
 	Latency                    time.Duration
 	RosenpassEnabled           bool
+	Quality                    ConnQuality
 	routes                     map[string]struct{}
 }
 
+// ConnQuality contains the results of the probes sent over the tunnel to a connected peer
+type ConnQuality struct {
+	// ProbesSent and ProbesReceived count the probes of the rolling window
+	ProbesSent     int
+	ProbesReceived int
+	// PacketLoss is the ratio of lost probes in the rolling window, from 0 to 1
+	PacketLoss float64
+	// Jitter is the smoothed round trip time variation as defined in RFC 3550
+	Jitter time.Duration
+	// PathMTU is the largest packet size answered by the peer, 0 if it wasn't discovered yet
+	PathMTU int
+	// Answered is false until the peer answers a probe, its policies might not allow ICMP
+	Answered  bool
+	UpdatedAt time.Time
+}
+
 // AddRoute add a single route to routes map
 func (s *State) AddRoute(network string) {
 	s.Mux.Lock()
@@ -114,6 +131,10 @@ type ManagementState struct {
 	URL       string
 	Connected bool
 	Error     error
+	// NetworkMapStale is set while the network map in use was loaded from disk instead of received from management
+	NetworkMapStale bool
+	// RelayCredentialsExpired is set while the relay and TURN credentials of the persisted network map are expired
+	RelayCredentialsExpired bool
 }
 
 // RosenpassState contains the latest state of the Rosenpass configuration
@@ -153,6 +174,8 @@ type Status struct {
 	signalError           error
 	managementState       bool
 	managementError       error
+	networkMapStale       bool
+	staleCredsExpiry      time.Time
 	relayStates           []relay.ProbeResult
 	localPeer             LocalPeerState
 	offlinePeers          []State
@@ -647,6 +670,25 @@ func (d *Status) MarkManagementConnected() {
 	d.managementError = nil
 }
 
+// MarkNetworkMapStale sets whether the network map in use is a persisted one waiting for a management sync
+func (d *Status) MarkNetworkMapStale(stale bool) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.networkMapStale = stale
+	if !stale {
+		d.staleCredsExpiry = time.Time{}
+	}
+}
+
+// SetStaleCredentialsExpiry sets when the relay and TURN credentials of the persisted network map expire
+func (d *Status) SetStaleCredentialsExpiry(expiry time.Time) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.staleCredsExpiry = expiry
+}
+
 // UpdateSignalAddress update the address of the signal server
 func (d *Status) UpdateSignalAddress(signalURL string) {
 	d.mux.Lock()
@@ -732,6 +774,32 @@ func (d *Status) DeleteResolvedDomainsStates(domain domain.Domain) {
 	}
 }
 
+// RemoveResolvedDomainPrefixes removes expired prefixes from a resolved domain.
+// The resolved domain is dropped once it has no prefixes left.
+func (d *Status) RemoveResolvedDomainPrefixes(resolvedDomain domain.Domain, prefixes []netip.Prefix) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	info, ok := d.resolvedDomainsStates[resolvedDomain]
+	if !ok {
+		return
+	}
+
+	for _, prefix := range prefixes {
+		d.routeIDLookup.RemoveResolvedIP(prefix)
+	}
+
+	info.Prefixes = slices.DeleteFunc(slices.Clone(info.Prefixes), func(p netip.Prefix) bool {
+		return slices.Contains(prefixes, p)
+	})
+
+	if len(info.Prefixes) == 0 {
+		delete(d.resolvedDomainsStates, resolvedDomain)
+		return
+	}
+	d.resolvedDomainsStates[resolvedDomain] = info
+}
+
 func (d *Status) GetRosenpassState() RosenpassState {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -748,6 +816,8 @@ func (d *Status) GetManagementState() ManagementState {
 		d.mgmAddress,
 		d.managementState,
 		d.managementError,
+		d.networkMapStale,
+		d.networkMapStale && !d.staleCredsExpiry.IsZero() && time.Now().After(d.staleCredsExpiry),
 	}
 }
 
@@ -767,6 +837,20 @@ func (d *Status) UpdateLatency(pubKey string, latency time.Duration) error {
 	return nil
 }
 
+// UpdatePeerQuality stores the probe results of a peer
+func (d *Status) UpdatePeerQuality(pubKey string, quality ConnQuality) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[pubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+	peerState.Quality = quality
+	d.peers[pubKey] = peerState
+	return nil
+}
+
 // IsLoginRequired determines if a peer's login has expired.
 func (d *Status) IsLoginRequired() bool {
 	d.mux.Lock()
@@ -822,7 +906,22 @@ func (d *Status) GetRelayStates() []relay.ProbeResult {
 	relayState := relay.ProbeResult{
 		URI: instanceAddr,
 	}
-	return append(relayStates, relayState)
+
+	// the other relay servers are listed with the result of the latency measurement
+	var others []relay.ProbeResult
+	for _, r := range d.relayMgr.RelayRTTs() {
+		if r.Home {
+			relayState.RTT = r.RTT
+			continue
+		}
+		others = append(others, relay.ProbeResult{
+			URI: r.URL,
+			Err: r.Err,
+			RTT: r.RTT,
+		})
+	}
+	relayStates = append(relayStates, relayState)
+	return append(relayStates, others...)
 }
 
 func (d *Status) ForwardingRules() []firewall.ForwardRule {
@@ -53,6 +53,10 @@ type WorkerICE struct {
 
 	// we record the last known state of the ICE agent to avoid duplicate on disconnected events
 	lastKnownState ice.ConnectionState
+
+	// diag contains the details of the latest connection attempt for the diagnose command
+	diag   ICEDiagnostics
+	diagMu sync.Mutex
 }
 
 func NewWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *Conn, signaler *Signaler, ifaceDiscover stdnet.ExternalIFaceDiscover, statusRecorder *Status, hasRelayOnLocally bool) (*WorkerICE, error) {
@@ -96,9 +100,11 @@ func (w *WorkerICE) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 
 	w.log.Debugf("recreate ICE agent")
 	agentCtx, agentCancel := context.WithCancel(w.ctx)
+	w.resetDiagnostics()
 	agent, err := w.reCreateAgent(agentCancel, preferredCandidateTypes)
 	if err != nil {
 		w.log.Errorf("failed to recreate ICE Agent: %s", err)
+		w.recordError(err)
 		w.muxAgent.Unlock()
 		return
 	}
@@ -109,6 +115,7 @@ func (w *WorkerICE) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 	err = w.agent.GatherCandidates()
 	if err != nil {
 		w.log.Debugf("failed to gather candidates: %s", err)
+		w.recordError(fmt.Errorf("gather candidates: %w", err))
 		return
 	}
 
@@ -116,12 +123,17 @@ func (w *WorkerICE) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 	// but it won't release if ICE Agent went into Disconnected or Failed state,
 	// so we have to cancel it with the provided context once agent detected a broken connection
 	w.log.Debugf("turn agent dial")
+	snapshotCtx, snapshotCancel := context.WithCancel(agentCtx)
+	go w.snapshotCandidatePairs(snapshotCtx, agent)
 	remoteConn, err := w.turnAgentDial(agentCtx, remoteOfferAnswer)
+	snapshotCancel()
 	if err != nil {
 		w.log.Debugf("failed to dial the remote peer: %s", err)
+		w.recordError(fmt.Errorf("dial remote peer: %w", err))
 		return
 	}
 	w.log.Debugf("agent dial succeeded")
+	w.recordCandidatePairs(agent)
 
 	pair, err := w.agent.GetSelectedCandidatePair()
 	if err != nil {
@@ -165,14 +177,17 @@ func (w *WorkerICE) OnRemoteCandidate(candidate ice.Candidate, haRoutes route.HA
 	}
 
 	if candidateViaRoutes(candidate, haRoutes) {
+		w.recordRemoteCandidate(candidate, "address is part of a routed network")
 		return
 	}
 
 	err := w.agent.AddRemoteCandidate(candidate)
 	if err != nil {
 		w.log.Errorf("error while handling remote candidate")
+		w.recordRemoteCandidate(candidate, fmt.Sprintf("failed to add candidate: %v", err))
 		return
 	}
+	w.recordRemoteCandidate(candidate, "")
 }
 
 func (w *WorkerICE) GetLocalUserCredentials() (frag string, pwd string) {
@@ -209,6 +224,7 @@ func (w *WorkerICE) reCreateAgent(agentCancel context.CancelFunc, candidates []i
 
 	err = agent.OnConnectionStateChange(func(state ice.ConnectionState) {
 		w.log.Debugf("ICE ConnectionState has changed to %s", state.String())
+		w.recordState(state)
 		switch state {
 		case ice.ConnectionStateConnected:
 			w.lastKnownState = ice.ConnectionStateConnected
@@ -297,6 +313,7 @@ func (w *WorkerICE) onICECandidate(candidate ice.Candidate) {
 
 	// TODO: reported port is incorrect for CandidateTypeHost, makes understanding ICE use via logs confusing as port is ignored
 	w.log.Debugf("discovered local candidate %s", candidate.String())
+	w.recordLocalCandidate(candidate)
 	go func() {
 		err := w.signaler.SignalICECandidate(candidate, w.config.Key)
 		if err != nil {
@@ -0,0 +1,243 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+)
+
+const (
+	// DefaultProfileName is the name of the profile using the config and state files of the daemon flags,
+	// it always exists and can't be removed
+	DefaultProfileName = "default"
+
+	profilesDirName       = "profiles"
+	activeProfileFileName = "active_profile"
+	configFileName        = "config.json"
+	stateFileName         = "state.json"
+)
+
+var (
+	// ErrProfileNotFound is returned when the requested profile doesn't exist
+	ErrProfileNotFound = errors.New("profile not found")
+	// ErrProfileExists is returned when adding a profile whose name is already taken
+	ErrProfileExists = errors.New("profile already exists")
+	// ErrProfileActive is returned when removing the active profile
+	ErrProfileActive = errors.New("profile is active")
+	// ErrDefaultProfile is returned when removing the default profile
+	ErrDefaultProfile = errors.New("the default profile can't be removed")
+	// ErrInvalidProfileName is returned when the profile name isn't usable as a directory name
+	ErrInvalidProfileName = errors.New("invalid profile name")
+
+	profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,63}$`)
+)
+
+// Profile is a named client configuration with its own config file, WireGuard key and state file
+type Profile struct {
+	Name       string
+	ConfigPath string
+	StatePath  string
+}
+
+// Manager keeps the client profiles. The default profile uses the config path it was created with,
+// other profiles are stored in a profiles directory next to it.
+type Manager struct {
+	mu sync.Mutex
+
+	defaultConfigPath string
+	defaultStatePath  string
+	profilesDir       string
+	activeFile        string
+}
+
+// NewManager creates a profile manager for the daemon using defaultConfigPath as the default profile config
+func NewManager(defaultConfigPath string) *Manager {
+	configDir := filepath.Dir(defaultConfigPath)
+	return &Manager{
+		defaultConfigPath: defaultConfigPath,
+		defaultStatePath:  statemanager.GetDefaultStatePath(),
+		profilesDir:       filepath.Join(configDir, profilesDirName),
+		activeFile:        filepath.Join(configDir, activeProfileFileName),
+	}
+}
+
+// ValidateName checks the name can be used for a profile
+func ValidateName(name string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("%w %q: use up to 64 letters, digits, dashes or underscores", ErrInvalidProfileName, name)
+	}
+	return nil
+}
+
+// List returns the profiles ordered by name, the default profile first
+func (m *Manager) List() ([]*Profile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	profiles := []*Profile{m.profile(DefaultProfileName)}
+
+	entries, err := os.ReadDir(m.profilesDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("read profiles directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfileName && ValidateName(entry.Name()) == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		profiles = append(profiles, m.profile(name))
+	}
+
+	return profiles, nil
+}
+
+// Get returns the profile with the given name
+func (m *Manager) Get(name string) (*Profile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.get(name)
+}
+
+// Add creates a new profile. Its config, and with it the WireGuard key, is generated on the first use.
+func (m *Manager) Add(name string) (*Profile, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.get(name); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+
+	if err := os.MkdirAll(filepath.Join(m.profilesDir, name), 0700); err != nil {
+		return nil, fmt.Errorf("create profile directory: %w", err)
+	}
+
+	return m.profile(name), nil
+}
+
+// Remove deletes a profile with its config and state files. The default and the active profiles can't be removed.
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name == DefaultProfileName {
+		return ErrDefaultProfile
+	}
+
+	if _, err := m.get(name); err != nil {
+		return err
+	}
+
+	if m.activeName() == name {
+		return fmt.Errorf("%w: %s, switch to another profile first", ErrProfileActive, name)
+	}
+
+	if err := os.RemoveAll(filepath.Join(m.profilesDir, name)); err != nil {
+		return fmt.Errorf("remove profile directory: %w", err)
+	}
+
+	return nil
+}
+
+// Active returns the active profile. It falls back to the default profile when the active one was removed.
+func (m *Manager) Active() *Profile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active, err := m.get(m.activeName())
+	if err != nil {
+		return m.profile(DefaultProfileName)
+	}
+	return active
+}
+
+// SetActive persists the profile with the given name as the active one
+func (m *Manager) SetActive(name string) (*Profile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == DefaultProfileName {
+		if err := os.Remove(m.activeFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("remove active profile file: %w", err)
+		}
+		return p, nil
+	}
+
+	if err := os.WriteFile(m.activeFile, []byte(name+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("write active profile file: %w", err)
+	}
+
+	return p, nil
+}
+
+func (m *Manager) get(name string) (*Profile, error) {
+	if name == DefaultProfileName {
+		return m.profile(name), nil
+	}
+
+	if ValidateName(name) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	info, err := os.Stat(filepath.Join(m.profilesDir, name))
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	return m.profile(name), nil
+}
+
+func (m *Manager) activeName() string {
+	data, err := os.ReadFile(m.activeFile)
+	if err != nil {
+		return DefaultProfileName
+	}
+
+	name := strings.TrimSpace(string(data))
+	if name == "" {
+		return DefaultProfileName
+	}
+	return name
+}
+
+func (m *Manager) profile(name string) *Profile {
+	if name == DefaultProfileName {
+		return &Profile{
+			Name:       DefaultProfileName,
+			ConfigPath: m.defaultConfigPath,
+			StatePath:  m.defaultStatePath,
+		}
+	}
+
+	dir := filepath.Join(m.profilesDir, name)
+	return &Profile{
+		Name:       name,
+		ConfigPath: filepath.Join(dir, configFileName),
+		StatePath:  filepath.Join(dir, stateFileName),
+	}
+}
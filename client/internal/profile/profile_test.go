@@ -0,0 +1,77 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+	defaultConfigPath := filepath.Join(dir, "config.json")
+	manager := NewManager(defaultConfigPath)
+
+	profiles, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, DefaultProfileName, profiles[0].Name)
+	assert.Equal(t, defaultConfigPath, profiles[0].ConfigPath)
+	assert.Equal(t, DefaultProfileName, manager.Active().Name)
+
+	_, err = manager.Add("../escape")
+	assert.ErrorIs(t, err, ErrInvalidProfileName)
+
+	_, err = manager.Add(DefaultProfileName)
+	assert.ErrorIs(t, err, ErrProfileExists)
+
+	work, err := manager.Add("work")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "profiles", "work", "config.json"), work.ConfigPath)
+	assert.Equal(t, filepath.Join(dir, "profiles", "work", "state.json"), work.StatePath)
+
+	_, err = manager.Add("customer-a")
+	require.NoError(t, err)
+
+	_, err = manager.Add("work")
+	assert.ErrorIs(t, err, ErrProfileExists)
+
+	profiles, err = manager.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 3)
+	assert.Equal(t, []string{DefaultProfileName, "customer-a", "work"}, []string{profiles[0].Name, profiles[1].Name, profiles[2].Name})
+
+	_, err = manager.SetActive("missing")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+
+	_, err = manager.SetActive("work")
+	require.NoError(t, err)
+	assert.Equal(t, "work", NewManager(defaultConfigPath).Active().Name, "active profile should be persisted")
+
+	assert.ErrorIs(t, manager.Remove("work"), ErrProfileActive)
+	assert.ErrorIs(t, manager.Remove(DefaultProfileName), ErrDefaultProfile)
+	assert.ErrorIs(t, manager.Remove("missing"), ErrProfileNotFound)
+
+	require.NoError(t, os.WriteFile(work.ConfigPath, []byte("{}"), 0600))
+
+	_, err = manager.SetActive(DefaultProfileName)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfileName, manager.Active().Name)
+
+	require.NoError(t, manager.Remove("work"))
+	_, err = os.Stat(filepath.Dir(work.ConfigPath))
+	assert.True(t, os.IsNotExist(err), "profile directory should be removed")
+
+	_, err = manager.Get("work")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func TestManager_ActiveFallsBackToDefault(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(filepath.Join(dir, "config.json"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, activeProfileFileName), []byte("removed\n"), 0600))
+	assert.Equal(t, DefaultProfileName, manager.Active().Name)
+}
@@ -0,0 +1,240 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/pion/stun/v2"
+	log "github.com/sirupsen/logrus"
+
+	nbnet "github.com/netbirdio/netbird/util/net"
+)
+
+// NATType is the NAT behavior classified by binding to multiple STUN servers from the same socket
+type NATType string
+
+const (
+	// NATUnknown means less than two STUN servers answered, so the mapping behavior can't be compared
+	NATUnknown NATType = "unknown"
+	// NATUDPBlocked means none of the STUN servers answered
+	NATUDPBlocked NATType = "UDP blocked"
+	// NATNone means the mapped address is the local address of the socket
+	NATNone NATType = "no NAT"
+	// NATEndpointIndependent means all STUN servers saw the same mapped address, P2P connections should work
+	NATEndpointIndependent NATType = "endpoint-independent mapping"
+	// NATEndpointDependent means the STUN servers saw different mapped addresses (symmetric NAT),
+	// P2P connections only work if the remote side isn't behind a NAT of the same type
+	NATEndpointDependent NATType = "endpoint-dependent mapping (symmetric)"
+
+	// maxNATServers is the number of STUN server addresses used for the classification
+	maxNATServers   = 3
+	natProbeTimeout = 2 * time.Second
+)
+
+// STUNMapping is the result of a binding request to a STUN server address
+type STUNMapping struct {
+	Server        string
+	MappedAddress string
+	Err           error
+}
+
+// NATResult holds the NAT classification and the mappings it is based on
+type NATResult struct {
+	Type      NATType
+	LocalAddr string
+	Mappings  []STUNMapping
+}
+
+// ClassifyNAT sends binding requests from a single socket to the addresses of the given UDP STUN servers and
+// compares the mapped addresses. A single STUN host resolving to multiple addresses is enough for the classification.
+func ClassifyNAT(ctx context.Context, uris []*stun.URI) (*NATResult, error) {
+	servers := resolveSTUNServers(ctx, uris)
+	if len(servers) == 0 {
+		return nil, errors.New("no STUN server address available")
+	}
+
+	conn, err := nbnet.NewListener().ListenPacket(ctx, "udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Debugf("failed to close NAT probe socket: %v", err)
+		}
+	}()
+
+	return classifyNAT(ctx, conn, servers, localAddresses()), nil
+}
+
+func classifyNAT(ctx context.Context, conn net.PacketConn, servers []netip.AddrPort, localAddrs []netip.Addr) *NATResult {
+	result := &NATResult{
+		Type:      NATUnknown,
+		LocalAddr: conn.LocalAddr().String(),
+	}
+
+	var mapped []netip.AddrPort
+	for _, server := range servers {
+		mapping := STUNMapping{Server: server.String()}
+		addr, err := stunBinding(ctx, conn, server)
+		if err != nil {
+			mapping.Err = err
+		} else {
+			mapping.MappedAddress = addr.String()
+			mapped = append(mapped, addr)
+		}
+		result.Mappings = append(result.Mappings, mapping)
+	}
+
+	switch {
+	case len(mapped) == 0:
+		result.Type = NATUDPBlocked
+	case isLocalMapping(mapped, conn.LocalAddr(), localAddrs):
+		result.Type = NATNone
+	case len(mapped) == 1:
+		result.Type = NATUnknown
+	case allEqual(mapped):
+		result.Type = NATEndpointIndependent
+	default:
+		result.Type = NATEndpointDependent
+	}
+
+	return result
+}
+
+func stunBinding(ctx context.Context, conn net.PacketConn, server netip.AddrPort) (netip.AddrPort, error) {
+	request, err := stun.Build(stun.TransactionID, stun.BindingRequest)
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("build request: %w", err)
+	}
+
+	if _, err := conn.WriteTo(request.Raw, net.UDPAddrFromAddrPort(server)); err != nil {
+		return netip.AddrPort{}, fmt.Errorf("send request: %w", err)
+	}
+
+	deadline := time.Now().Add(natProbeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return netip.AddrPort{}, fmt.Errorf("set deadline: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return netip.AddrPort{}, fmt.Errorf("read response: %w", err)
+		}
+
+		response := &stun.Message{Raw: append([]byte{}, buf[:n]...)}
+		if err := response.Decode(); err != nil || response.TransactionID != request.TransactionID {
+			// late answer of a previous server or unrelated packet
+			continue
+		}
+
+		var xorAddr stun.XORMappedAddress
+		if err := xorAddr.GetFrom(response); err != nil {
+			return netip.AddrPort{}, fmt.Errorf("get xor addr: %w", err)
+		}
+
+		addr, ok := netip.AddrFromSlice(xorAddr.IP)
+		if !ok {
+			return netip.AddrPort{}, fmt.Errorf("invalid mapped address %s", xorAddr.IP)
+		}
+		return netip.AddrPortFrom(addr.Unmap(), uint16(xorAddr.Port)), nil
+	}
+}
+
+// resolveSTUNServers returns up to maxNATServers distinct IPv4 addresses of the UDP STUN servers
+func resolveSTUNServers(ctx context.Context, uris []*stun.URI) []netip.AddrPort {
+	var servers []netip.AddrPort
+	seen := make(map[netip.AddrPort]struct{})
+
+	for _, uri := range uris {
+		if uri.Scheme != stun.SchemeTypeSTUN || uri.Proto != stun.ProtoTypeUDP {
+			continue
+		}
+
+		ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip4", uri.Host)
+		if err != nil {
+			log.Debugf("failed to resolve STUN server %s: %v", uri.Host, err)
+			continue
+		}
+
+		for _, ip := range ips {
+			server := netip.AddrPortFrom(ip.Unmap(), uint16(uri.Port))
+			if _, ok := seen[server]; ok {
+				continue
+			}
+			seen[server] = struct{}{}
+			servers = append(servers, server)
+
+			if len(servers) == maxNATServers {
+				return servers
+			}
+		}
+	}
+
+	return servers
+}
+
+func isLocalMapping(mapped []netip.AddrPort, local net.Addr, localAddrs []netip.Addr) bool {
+	_, portStr, err := net.SplitHostPort(local.String())
+	if err != nil {
+		return false
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return false
+	}
+
+	for _, addr := range mapped {
+		if int(addr.Port()) != port {
+			return false
+		}
+
+		isLocal := false
+		for _, localAddr := range localAddrs {
+			if localAddr == addr.Addr() {
+				isLocal = true
+				break
+			}
+		}
+		if !isLocal {
+			return false
+		}
+	}
+	return true
+}
+
+func allEqual(mapped []netip.AddrPort) bool {
+	for _, addr := range mapped[1:] {
+		if addr != mapped[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func localAddresses() []netip.Addr {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		log.Debugf("failed to get interface addresses: %v", err)
+		return nil
+	}
+
+	var result []netip.Addr
+	for _, addr := range addrs {
+		prefix, err := netip.ParsePrefix(addr.String())
+		if err != nil {
+			continue
+		}
+		result = append(result, prefix.Addr().Unmap())
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package relay
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/pion/stun/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSTUNServer answers binding requests with the source address, portOffset simulates a NAT
+// mapping the socket to a different port for this server
+func startSTUNServer(t *testing.T, portOffset int) netip.AddrPort {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			request := &stun.Message{Raw: append([]byte{}, buf[:n]...)}
+			if err := request.Decode(); err != nil {
+				continue
+			}
+
+			udpAddr := from.(*net.UDPAddr)
+			response, err := stun.Build(
+				stun.NewTransactionIDSetter(request.TransactionID),
+				stun.BindingSuccess,
+				&stun.XORMappedAddress{IP: udpAddr.IP, Port: udpAddr.Port + portOffset},
+			)
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(response.Raw, from)
+		}
+	}()
+
+	return netip.MustParseAddrPort(conn.LocalAddr().String())
+}
+
+// unusedServer returns an address nothing is answering on
+func unusedServer(t *testing.T) netip.AddrPort {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := netip.MustParseAddrPort(conn.LocalAddr().String())
+	require.NoError(t, conn.Close())
+	return addr
+}
+
+func TestClassifyNAT(t *testing.T) {
+	loopback := []netip.Addr{netip.MustParseAddr("127.0.0.1")}
+
+	testCases := []struct {
+		name       string
+		servers    func(t *testing.T) []netip.AddrPort
+		localAddrs []netip.Addr
+		expected   NATType
+		answered   int
+	}{
+		{
+			name: "mapped address is local",
+			servers: func(t *testing.T) []netip.AddrPort {
+				return []netip.AddrPort{startSTUNServer(t, 0), startSTUNServer(t, 0)}
+			},
+			localAddrs: loopback,
+			expected:   NATNone,
+			answered:   2,
+		},
+		{
+			name: "same mapping for all servers",
+			servers: func(t *testing.T) []netip.AddrPort {
+				return []netip.AddrPort{startSTUNServer(t, 0), startSTUNServer(t, 0)}
+			},
+			expected: NATEndpointIndependent,
+			answered: 2,
+		},
+		{
+			name: "different mapping per server",
+			servers: func(t *testing.T) []netip.AddrPort {
+				return []netip.AddrPort{startSTUNServer(t, 0), startSTUNServer(t, 1)}
+			},
+			localAddrs: loopback,
+			expected:   NATEndpointDependent,
+			answered:   2,
+		},
+		{
+			name: "single server answered",
+			servers: func(t *testing.T) []netip.AddrPort {
+				return []netip.AddrPort{startSTUNServer(t, 0), unusedServer(t)}
+			},
+			expected: NATUnknown,
+			answered: 1,
+		},
+		{
+			name: "no server answered",
+			servers: func(t *testing.T) []netip.AddrPort {
+				return []netip.AddrPort{unusedServer(t)}
+			},
+			expected: NATUDPBlocked,
+			answered: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			result := classifyNAT(ctx, conn, tc.servers(t), tc.localAddrs)
+			assert.Equal(t, tc.expected, result.Type)
+
+			answered := 0
+			for _, mapping := range result.Mappings {
+				if mapping.Err == nil {
+					answered++
+				}
+			}
+			assert.Equal(t, tc.answered, answered)
+		})
+	}
+}
@@ -20,6 +20,8 @@ type ProbeResult struct {
 	URI  string
 	Err  error
 	Addr string
+	// RTT is the measured round-trip time of the relay server, it is zero if not measured
+	RTT time.Duration
 }
 
 // ProbeSTUN tries binding to the given STUN uri and acquiring an address
@@ -42,6 +42,8 @@ type routerPeerStatus struct {
 	connected bool
 	relayed   bool
 	latency   time.Duration
+	// packetLoss is the loss ratio of the connection quality probes, 0 if the peer never answered them
+	packetLoss float64
 }
 
 type routesUpdate struct {
@@ -118,11 +120,16 @@ func (c *clientNetwork) getRouterPeerStatuses() map[route.ID]routerPeerStatus {
 			log.Debugf("couldn't fetch peer state: %v", err)
 			continue
 		}
-		routePeerStatuses[r.ID] = routerPeerStatus{
+		status := routerPeerStatus{
 			connected: peerStatus.ConnStatus == peer.StatusConnected,
 			relayed:   peerStatus.Relayed,
 			latency:   peerStatus.Latency,
 		}
+		// peers not answering the probes at all are likely blocking ICMP, which says nothing about the loss
+		if peerStatus.Quality.Answered {
+			status.packetLoss = peerStatus.Quality.PacketLoss
+		}
+		routePeerStatuses[r.ID] = status
 	}
 	return routePeerStatuses
 }
@@ -136,6 +143,7 @@ func (c *clientNetwork) getRouterPeerStatuses() map[route.ID]routerPeerStatus {
 // * Metric: Routes with lower metrics (better) are prioritized.
 // * Non-relayed: Routes without relays are preferred.
 // * Latency: Routes with lower latency are prioritized.
+// * Packet loss: Routes with lower packet loss on the probes are prioritized, full loss weighs like a relayed connection.
 // * we compare the current score + 10ms to the chosen score to avoid flapping between routes
 // * Stability: In case of equal scores, the currently active route (if any) is maintained.
 //
@@ -182,6 +190,8 @@ func (c *clientNetwork) getBestRouteFromStatuses(routePeerStatuses map[route.ID]
 			tempScore++
 		}
 
+		tempScore -= peerStatus.packetLoss
+
 		if tempScore > chosenScore || (tempScore == chosenScore && chosen == "") {
 			chosen = r.ID
 			chosenScore = tempScore
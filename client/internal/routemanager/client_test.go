@@ -315,6 +315,36 @@ func TestGetBestrouteFromStatuses(t *testing.T) {
 			currentRoute:    "route1",
 			expectedRouteID: "route2",
 		},
+		{
+			name: "current route with high packet loss should be changed to route with lower latency and no loss",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {
+					connected:  true,
+					relayed:    false,
+					latency:    10 * time.Millisecond,
+					packetLoss: 0.3,
+				},
+				"route2": {
+					connected: true,
+					relayed:   false,
+					latency:   40 * time.Millisecond,
+				},
+			},
+			existingRoutes: map[route.ID]*route.Route{
+				"route1": {
+					ID:     "route1",
+					Metric: route.MaxMetric,
+					Peer:   "peer1",
+				},
+				"route2": {
+					ID:     "route2",
+					Metric: route.MaxMetric,
+					Peer:   "peer2",
+				},
+			},
+			currentRoute:    "route1",
+			expectedRouteID: "route2",
+		},
 		{
 			name: "current chosen route doesn't exist anymore",
 			statuses: map[route.ID]routerPeerStatus{
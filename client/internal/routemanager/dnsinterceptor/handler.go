@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/netip"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -18,12 +20,27 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/peerstore"
 	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
+	"github.com/netbirdio/netbird/client/internal/routemanager/util"
 	"github.com/netbirdio/netbird/management/domain"
 	"github.com/netbirdio/netbird/route"
 )
 
+const (
+	// expiryCheckInterval is how often resolved IPs are checked for expiration
+	expiryCheckInterval = time.Minute
+)
+
 type domainMap map[domain.Domain][]netip.Prefix
 
+// prefixExpiries tracks until when each resolved prefix of a domain is kept
+type prefixExpiries map[domain.Domain]map[netip.Prefix]time.Time
+
+// regexDomain is a regex domain of the route, intercepted for the queries of its zone
+type regexDomain struct {
+	domain domain.Domain
+	re     *regexp.Regexp
+}
+
 type DnsInterceptor struct {
 	mu                   sync.RWMutex
 	route                *route.Route
@@ -33,7 +50,13 @@ type DnsInterceptor struct {
 	dnsServer            nbdns.Server
 	currentPeerKey       string
 	interceptedDomains   domainMap
+	expiries             prefixExpiries
 	peerStore            *peerstore.Store
+	cancel               context.CancelFunc
+	// handlerPatterns are the patterns registered with the DNS server, regex domains are registered for their zone
+	handlerPatterns []string
+	// regexDomains are the regex domains by the handler pattern of their zone, a matched name has to match one of them
+	regexDomains map[string][]regexDomain
 }
 
 func New(
@@ -44,29 +67,93 @@ func New(
 	dnsServer nbdns.Server,
 	peerStore *peerstore.Store,
 ) *DnsInterceptor {
-	return &DnsInterceptor{
+	d := &DnsInterceptor{
 		route:                rt,
 		routeRefCounter:      routeRefCounter,
 		allowedIPsRefcounter: allowedIPsRefCounter,
 		statusRecorder:       statusRecorder,
 		dnsServer:            dnsServer,
 		interceptedDomains:   make(domainMap),
+		expiries:             make(prefixExpiries),
 		peerStore:            peerStore,
+		regexDomains:         make(map[string][]regexDomain),
+	}
+	d.buildHandlerPatterns()
+
+	return d
+}
+
+// buildHandlerPatterns registers regex domains for the wildcard of their zone.
+// A zone also covered by a plain domain of the route is not restricted to the regex.
+func (d *DnsInterceptor) buildHandlerPatterns() {
+	plainPatterns := make(map[string]struct{})
+	for _, dom := range d.route.Domains {
+		if dom.IsRegex() {
+			continue
+		}
+		pattern := handlerKey(string(dom))
+		plainPatterns[pattern] = struct{}{}
+		d.handlerPatterns = append(d.handlerPatterns, string(dom))
+	}
+
+	for _, dom := range d.route.Domains {
+		if !dom.IsRegex() {
+			continue
+		}
+
+		re, zone, err := domain.ParseRegex(dom)
+		if err != nil {
+			log.Errorf("skipping invalid regex domain %s: %v", dom, err)
+			continue
+		}
+
+		pattern := "*." + string(zone)
+		key := handlerKey(pattern)
+		if _, ok := plainPatterns[key]; ok {
+			continue
+		}
+		if _, ok := d.regexDomains[key]; !ok {
+			d.handlerPatterns = append(d.handlerPatterns, pattern)
+		}
+		d.regexDomains[key] = append(d.regexDomains[key], regexDomain{domain: dom, re: re})
 	}
 }
 
+// handlerKey normalizes a pattern like the DNS handler chain
+func handlerKey(pattern string) string {
+	return strings.ToLower(dns.Fqdn(pattern))
+}
+
 func (d *DnsInterceptor) String() string {
 	return d.route.Domains.SafeString()
 }
 
-func (d *DnsInterceptor) AddRoute(context.Context) error {
-	d.dnsServer.RegisterHandler(d.route.Domains.ToPunycodeList(), d, nbdns.PriorityDNSRoute)
+func (d *DnsInterceptor) AddRoute(ctx context.Context) error {
+	d.dnsServer.RegisterHandler(d.handlerPatterns, d, nbdns.PriorityDNSRoute)
+
+	// routes without KeepRoute replace their IPs on every resolution, only accumulating routes need to expire
+	if d.route.KeepRoute {
+		d.mu.Lock()
+		if d.cancel != nil {
+			d.cancel()
+		}
+		ctx, d.cancel = context.WithCancel(ctx)
+		d.mu.Unlock()
+
+		go d.watchExpiries(ctx)
+	}
+
 	return nil
 }
 
 func (d *DnsInterceptor) RemoveRoute() error {
 	d.mu.Lock()
 
+	if d.cancel != nil {
+		d.cancel()
+		d.cancel = nil
+	}
+
 	var merr *multierror.Error
 	for domain, prefixes := range d.interceptedDomains {
 		for _, prefix := range prefixes {
@@ -87,9 +174,10 @@ func (d *DnsInterceptor) RemoveRoute() error {
 	}
 
 	clear(d.interceptedDomains)
+	clear(d.expiries)
 	d.mu.Unlock()
 
-	d.dnsServer.DeregisterHandler(d.route.Domains.ToPunycodeList(), nbdns.PriorityDNSRoute)
+	d.dnsServer.DeregisterHandler(d.handlerPatterns, nbdns.PriorityDNSRoute)
 
 	return nberrors.FormatErrorOrNil(merr)
 }
@@ -142,6 +230,12 @@ func (d *DnsInterceptor) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	log.Tracef("received DNS request for domain=%s type=%v class=%v",
 		r.Question[0].Name, r.Question[0].Qtype, r.Question[0].Qclass)
 
+	pattern, ok := d.matchPattern(w, r.Question[0].Name)
+	if !ok {
+		d.continueToNextHandler(w, r, "no regex domain matched")
+		return
+	}
+
 	d.mu.RLock()
 	peerKey := d.currentPeerKey
 	d.mu.RUnlock()
@@ -188,7 +282,7 @@ func (d *DnsInterceptor) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	}
 
 	reply.Id = r.Id
-	if err := d.writeMsg(w, reply); err != nil {
+	if err := d.writeMsg(w, reply, pattern); err != nil {
 		log.Errorf("failed writing DNS response: %v", err)
 	}
 }
@@ -214,7 +308,36 @@ func (d *DnsInterceptor) getUpstreamIP(peerKey string) (netip.Addr, error) {
 	return peerAllowedIP, nil
 }
 
-func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg) error {
+// matchPattern returns the route domain a query of a regex zone matches.
+// Queries matched by a plain domain of the route return an empty pattern, the pattern of the handler chain is used then.
+func (d *DnsInterceptor) matchPattern(w dns.ResponseWriter, qname string) (domain.Domain, bool) {
+	if len(d.regexDomains) == 0 {
+		return "", true
+	}
+
+	var regexDomains []regexDomain
+	if writer, ok := w.(*nbdns.ResponseWriterChain); ok {
+		var isRegexZone bool
+		regexDomains, isRegexZone = d.regexDomains[handlerKey(writer.GetOrigPattern())]
+		if !isRegexZone {
+			return "", true
+		}
+	} else {
+		for _, domains := range d.regexDomains {
+			regexDomains = append(regexDomains, domains...)
+		}
+	}
+
+	name := strings.TrimSuffix(qname, ".")
+	for _, candidate := range regexDomains {
+		if candidate.re.MatchString(name) {
+			return candidate.domain, true
+		}
+	}
+	return "", false
+}
+
+func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg, pattern domain.Domain) error {
 	if r == nil {
 		return fmt.Errorf("received nil DNS message")
 	}
@@ -229,11 +352,15 @@ func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg) error {
 
 		// already punycode via RegisterHandler()
 		originalDomain := domain.Domain(origPattern)
+		if pattern != "" {
+			originalDomain = pattern
+		}
 		if originalDomain == "" {
 			originalDomain = resolvedDomain
 		}
 
 		var newPrefixes []netip.Prefix
+		var ttl uint32
 		for _, answer := range r.Answer {
 			var ip netip.Addr
 			switch rr := answer.(type) {
@@ -257,10 +384,14 @@ func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg) error {
 
 			prefix := netip.PrefixFrom(ip, ip.BitLen())
 			newPrefixes = append(newPrefixes, prefix)
+
+			if hdrTTL := answer.Header().Ttl; ttl == 0 || hdrTTL < ttl {
+				ttl = hdrTTL
+			}
 		}
 
 		if len(newPrefixes) > 0 {
-			if err := d.updateDomainPrefixes(resolvedDomain, originalDomain, newPrefixes); err != nil {
+			if err := d.updateDomainPrefixes(resolvedDomain, originalDomain, newPrefixes, time.Duration(ttl)*time.Second); err != nil {
 				log.Errorf("failed to update domain prefixes: %v", err)
 			}
 		}
@@ -273,13 +404,20 @@ func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg) error {
 	return nil
 }
 
-func (d *DnsInterceptor) updateDomainPrefixes(resolvedDomain, originalDomain domain.Domain, newPrefixes []netip.Prefix) error {
+func (d *DnsInterceptor) updateDomainPrefixes(resolvedDomain, originalDomain domain.Domain, newPrefixes []netip.Prefix, ttl time.Duration) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	oldPrefixes := d.interceptedDomains[resolvedDomain]
 	toAdd, toRemove := determinePrefixChanges(oldPrefixes, newPrefixes)
 
+	if d.route.KeepRoute {
+		// IPs missing from the answer stay routed until they expire
+		d.refreshExpiries(resolvedDomain, newPrefixes, ttl)
+		toRemove = nil
+		newPrefixes = append(slices.Clone(oldPrefixes), toAdd...)
+	}
+
 	var merr *multierror.Error
 
 	// Add new prefixes
@@ -359,3 +497,82 @@ func determinePrefixChanges(oldPrefixes, newPrefixes []netip.Prefix) (toAdd, toR
 	}
 	return
 }
+
+// refreshExpiries extends the lifetime of the resolved prefixes of a domain based on the answer TTL.
+func (d *DnsInterceptor) refreshExpiries(resolvedDomain domain.Domain, prefixes []netip.Prefix, ttl time.Duration) {
+	expiry := util.PrefixExpiry(time.Now(), ttl)
+
+	domainExpiries, ok := d.expiries[resolvedDomain]
+	if !ok {
+		domainExpiries = make(map[netip.Prefix]time.Time)
+		d.expiries[resolvedDomain] = domainExpiries
+	}
+
+	for _, prefix := range prefixes {
+		domainExpiries[prefix] = expiry
+	}
+}
+
+// watchExpiries periodically removes resolved prefixes that weren't seen in an answer within their TTL and grace period.
+func (d *DnsInterceptor) watchExpiries(ctx context.Context) {
+	ticker := time.NewTicker(expiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := d.removeExpiredPrefixes(now); err != nil {
+				log.Errorf("failed to remove expired dynamic routes for [%s]: %v", d.route.Domains.SafeString(), err)
+			}
+		}
+	}
+}
+
+func (d *DnsInterceptor) removeExpiredPrefixes(now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var merr *multierror.Error
+	for resolvedDomain, domainExpiries := range d.expiries {
+		var expired []netip.Prefix
+		for prefix, expiry := range domainExpiries {
+			if now.After(expiry) {
+				expired = append(expired, prefix)
+				delete(domainExpiries, prefix)
+			}
+		}
+		if len(expired) == 0 {
+			continue
+		}
+
+		for _, prefix := range expired {
+			if _, err := d.routeRefCounter.Decrement(prefix); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("remove route for IP %s: %v", prefix, err))
+			}
+			if d.currentPeerKey != "" {
+				if _, err := d.allowedIPsRefcounter.Decrement(prefix); err != nil {
+					merr = multierror.Append(merr, fmt.Errorf("remove allowed IP %s: %v", prefix, err))
+				}
+			}
+		}
+
+		// the prefixes are shared with the status recorder, so they are not modified in place
+		remaining := slices.DeleteFunc(slices.Clone(d.interceptedDomains[resolvedDomain]), func(p netip.Prefix) bool {
+			return slices.Contains(expired, p)
+		})
+		if len(remaining) == 0 {
+			delete(d.interceptedDomains, resolvedDomain)
+			delete(d.expiries, resolvedDomain)
+		} else {
+			d.interceptedDomains[resolvedDomain] = remaining
+		}
+
+		d.statusRecorder.RemoveResolvedDomainPrefixes(resolvedDomain, expired)
+
+		log.Debugf("expired dynamic route(s) for domain=%s: %s", resolvedDomain.SafeString(), expired)
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
@@ -0,0 +1,178 @@
+package dnsinterceptor
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/peerstore"
+	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
+	"github.com/netbirdio/netbird/client/internal/routemanager/util"
+	"github.com/netbirdio/netbird/management/domain"
+	"github.com/netbirdio/netbird/route"
+)
+
+type testWriter struct {
+	msg *dns.Msg
+}
+
+func (w *testWriter) LocalAddr() net.Addr       { return nil }
+func (w *testWriter) RemoteAddr() net.Addr      { return nil }
+func (w *testWriter) Write([]byte) (int, error) { return 0, nil }
+func (w *testWriter) Close() error              { return nil }
+func (w *testWriter) TsigStatus() error         { return nil }
+func (w *testWriter) TsigTimersOnly(bool)       {}
+func (w *testWriter) Hijack()                   {}
+func (w *testWriter) WriteMsg(m *dns.Msg) error {
+	w.msg = m
+	return nil
+}
+
+func newTestInterceptor(t *testing.T, rt *route.Route, dnsServer nbdns.Server) (*DnsInterceptor, map[netip.Prefix]bool, map[netip.Prefix]string) {
+	t.Helper()
+
+	routes := make(map[netip.Prefix]bool)
+	routeRefCounter := refcounter.New(
+		func(prefix netip.Prefix, _ struct{}) (struct{}, error) {
+			routes[prefix] = true
+			return struct{}{}, nil
+		},
+		func(prefix netip.Prefix, _ struct{}) error {
+			delete(routes, prefix)
+			return nil
+		},
+	)
+
+	allowedIPs := make(map[netip.Prefix]string)
+	allowedIPsRefCounter := refcounter.New(
+		func(prefix netip.Prefix, peerKey string) (string, error) {
+			allowedIPs[prefix] = peerKey
+			return peerKey, nil
+		},
+		func(prefix netip.Prefix, _ string) error {
+			delete(allowedIPs, prefix)
+			return nil
+		},
+	)
+
+	if dnsServer == nil {
+		dnsServer = &nbdns.MockServer{}
+	}
+
+	return New(rt, routeRefCounter, allowedIPsRefCounter, peer.NewRecorder(""), dnsServer, peerstore.NewConnStore()), routes, allowedIPs
+}
+
+func TestDnsInterceptor_RemoveExpiredPrefixes(t *testing.T) {
+	rt := &route.Route{ID: "route", KeepRoute: true, Domains: domain.List{"*.example.com"}}
+	d, routes, allowedIPs := newTestInterceptor(t, rt, nil)
+	require.NoError(t, d.AddAllowedIPs("peer"))
+
+	resolved := domain.Domain("app.example.com.")
+	first := netip.MustParsePrefix("192.0.2.1/32")
+	second := netip.MustParsePrefix("192.0.2.2/32")
+
+	require.NoError(t, d.updateDomainPrefixes(resolved, "*.example.com.", []netip.Prefix{first, second}, 0))
+	// the second IP is missing from the next answer, the first one is seen again with a long TTL
+	require.NoError(t, d.updateDomainPrefixes(resolved, "*.example.com.", []netip.Prefix{first}, 2*time.Hour))
+
+	assert.ElementsMatch(t, []netip.Prefix{first, second}, d.interceptedDomains[resolved], "IPs missing from an answer should stay routed")
+	assert.True(t, routes[second])
+
+	now := time.Now()
+	require.NoError(t, d.removeExpiredPrefixes(now.Add(util.KeepRouteGracePeriod)))
+	assert.Len(t, d.interceptedDomains[resolved], 2, "IPs should stay routed during the grace period")
+
+	require.NoError(t, d.removeExpiredPrefixes(now.Add(util.MinPrefixTTL+util.KeepRouteGracePeriod+time.Second)))
+	assert.Equal(t, []netip.Prefix{first}, d.interceptedDomains[resolved])
+	assert.False(t, routes[second], "expired IP should be removed from the routes")
+	assert.NotContains(t, allowedIPs, second, "expired IP should be removed from the allowed IPs")
+	assert.Equal(t, "peer", allowedIPs[first])
+	assert.Equal(t, []netip.Prefix{first}, d.statusRecorder.GetResolvedDomainsStates()[resolved].Prefixes)
+
+	require.NoError(t, d.removeExpiredPrefixes(now.Add(2*time.Hour+util.KeepRouteGracePeriod+time.Second)))
+	assert.Empty(t, d.interceptedDomains)
+	assert.Empty(t, d.expiries)
+	assert.Empty(t, routes)
+	assert.Empty(t, allowedIPs)
+	assert.NotContains(t, d.statusRecorder.GetResolvedDomainsStates(), resolved)
+}
+
+func TestDnsInterceptor_ReplacesPrefixesWithoutKeepRoute(t *testing.T) {
+	rt := &route.Route{ID: "route", Domains: domain.List{"app.example.com"}}
+	d, routes, _ := newTestInterceptor(t, rt, nil)
+
+	resolved := domain.Domain("app.example.com.")
+	first := netip.MustParsePrefix("192.0.2.1/32")
+	second := netip.MustParsePrefix("192.0.2.2/32")
+
+	require.NoError(t, d.updateDomainPrefixes(resolved, "app.example.com.", []netip.Prefix{first}, time.Minute))
+	require.NoError(t, d.updateDomainPrefixes(resolved, "app.example.com.", []netip.Prefix{second}, time.Minute))
+
+	assert.Equal(t, []netip.Prefix{second}, d.interceptedDomains[resolved])
+	assert.Equal(t, map[netip.Prefix]bool{second: true}, routes)
+	assert.Empty(t, d.expiries, "replaced IPs don't need to expire")
+}
+
+func TestDnsInterceptor_RegexDomains(t *testing.T) {
+	regexDomain := domain.Domain(`~^api-[0-9]+\.internal\.example\.com$`)
+	rt := &route.Route{ID: "route", KeepRoute: true, Domains: domain.List{"app.example.com", regexDomain}}
+
+	var registered []string
+	dnsServer := &nbdns.MockServer{
+		RegisterHandlerFunc: func(domains []string, _ dns.Handler, _ int) {
+			registered = domains
+		},
+	}
+	d, _, _ := newTestInterceptor(t, rt, dnsServer)
+	require.NoError(t, d.AddRoute(context.Background()))
+	t.Cleanup(func() { _ = d.RemoveRoute() })
+
+	assert.Equal(t, []string{"app.example.com", "*.internal.example.com"}, registered, "regex domain should be intercepted for its zone")
+
+	chain := nbdns.NewHandlerChain()
+	var matched domain.Domain
+	var ok bool
+	for _, pattern := range registered {
+		chain.AddHandler(pattern, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			matched, ok = d.matchPattern(w, r.Question[0].Name)
+		}), nbdns.PriorityDNSRoute)
+	}
+
+	query := func(name string) {
+		matched, ok = "", false
+		chain.ServeDNS(&nbdns.ResponseWriterChain{ResponseWriter: &testWriter{}}, new(dns.Msg).SetQuestion(name, dns.TypeA))
+	}
+
+	query("api-1.internal.example.com.")
+	assert.True(t, ok)
+	assert.Equal(t, regexDomain, matched)
+
+	query("db.internal.example.com.")
+	assert.False(t, ok, "names of the zone not matching the regex should be passed to the next handler")
+
+	query("app.example.com.")
+	assert.True(t, ok)
+	assert.Empty(t, matched, "plain domains should keep the pattern of the handler")
+
+	// the resolved IPs are reported for the regex domain
+	answer := new(dns.Msg).SetQuestion("api-1.internal.example.com.", dns.TypeA)
+	answer.Answer = []dns.RR{&dns.A{
+		Hdr: dns.RR_Header{Name: "api-1.internal.example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+		A:   net.ParseIP("192.0.2.10").To4(),
+	}}
+	w := &testWriter{}
+	require.NoError(t, d.writeMsg(w, answer, regexDomain))
+	assert.Equal(t, answer, w.msg)
+
+	state := d.statusRecorder.GetResolvedDomainsStates()[domain.Domain("api-1.internal.example.com.")]
+	assert.Equal(t, regexDomain, state.ParentDomain)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("192.0.2.10/32")}, state.Prefixes)
+}
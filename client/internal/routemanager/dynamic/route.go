@@ -35,7 +35,9 @@ type domainMap map[domain.Domain][]netip.Prefix
 type resolveResult struct {
 	domain domain.Domain
 	prefix netip.Prefix
-	err    error
+	// ttl is the TTL of the answer, zero if the resolver doesn't report it
+	ttl time.Duration
+	err error
 }
 
 type Route struct {
@@ -44,6 +46,7 @@ type Route struct {
 	allowedIPsRefcounter *refcounter.AllowedIPsRefCounter
 	interval             time.Duration
 	dynamicDomains       domainMap
+	expiries             map[domain.Domain]map[netip.Prefix]time.Time
 	mu                   sync.Mutex
 	currentPeerKey       string
 	cancel               context.CancelFunc
@@ -67,6 +70,7 @@ func NewRoute(
 		allowedIPsRefcounter: allowedIPsRefCounter,
 		interval:             interval,
 		dynamicDomains:       domainMap{},
+		expiries:             map[domain.Domain]map[netip.Prefix]time.Time{},
 		statusRecorder:       statusRecorder,
 		wgInterface:          wgInterface,
 		resolverAddr:         resolverAddr,
@@ -115,6 +119,7 @@ func (r *Route) RemoveRoute() error {
 	}
 
 	r.dynamicDomains = domainMap{}
+	r.expiries = map[domain.Domain]map[netip.Prefix]time.Time{}
 
 	return nberrors.FormatErrorOrNil(merr)
 }
@@ -192,47 +197,59 @@ func (r *Route) startResolver(ctx context.Context) {
 }
 
 func (r *Route) update(ctx context.Context) error {
-	resolved, err := r.resolveDomains()
+	resolved, ttls, err := r.resolveDomains()
 	if err != nil {
 		if len(resolved) == 0 {
 			return fmt.Errorf("resolve domains: %w", err)
 		}
 		log.Warnf("Failed to resolve domains: %v", err)
 	}
-	if err := r.updateDynamicRoutes(ctx, resolved); err != nil {
+	if err := r.updateDynamicRoutes(ctx, resolved, ttls); err != nil {
 		return fmt.Errorf("update dynamic routes: %w", err)
 	}
 
 	return nil
 }
 
-func (r *Route) resolveDomains() (domainMap, error) {
+// resolveDomains resolves the domains of the route and returns their prefixes with the lowest TTL of the answers
+func (r *Route) resolveDomains() (domainMap, map[domain.Domain]time.Duration, error) {
 	results := make(chan resolveResult)
 	go r.resolve(results)
 
 	resolved := domainMap{}
+	ttls := map[domain.Domain]time.Duration{}
 	var merr *multierror.Error
 
 	for result := range results {
 		if result.err != nil {
 			merr = multierror.Append(merr, result.err)
-		} else {
-			resolved[result.domain] = append(resolved[result.domain], result.prefix)
+			continue
+		}
+
+		resolved[result.domain] = append(resolved[result.domain], result.prefix)
+		if ttl, ok := ttls[result.domain]; !ok || result.ttl < ttl {
+			ttls[result.domain] = result.ttl
 		}
 	}
 
-	return resolved, nberrors.FormatErrorOrNil(merr)
+	return resolved, ttls, nberrors.FormatErrorOrNil(merr)
 }
 
 func (r *Route) resolve(results chan resolveResult) {
 	var wg sync.WaitGroup
 
 	for _, d := range r.route.Domains {
+		// wildcard patterns can't be resolved directly, their IPs are only learned by intercepting DNS queries
+		if d.IsWildcard() || d.IsRegex() {
+			log.Debugf("Skipping resolution of domain pattern %s", d.SafeString())
+			continue
+		}
+
 		wg.Add(1)
 		go func(domain domain.Domain) {
 			defer wg.Done()
 
-			ips, err := r.getIPsFromResolver(domain)
+			ips, ttl, err := r.getIPsFromResolver(domain)
 			if err != nil {
 				log.Tracef("Failed to resolve domain %s with private resolver: %v", domain.SafeString(), err)
 				ips, err = net.LookupIP(string(domain))
@@ -248,7 +265,7 @@ func (r *Route) resolve(results chan resolveResult) {
 					results <- resolveResult{domain: domain, err: fmt.Errorf("get prefix from IP %s: %w", ip.String(), err)}
 					return
 				}
-				results <- resolveResult{domain: domain, prefix: prefix}
+				results <- resolveResult{domain: domain, prefix: prefix, ttl: ttl}
 			}
 		}(d)
 	}
@@ -257,7 +274,7 @@ func (r *Route) resolve(results chan resolveResult) {
 	close(results)
 }
 
-func (r *Route) updateDynamicRoutes(ctx context.Context, newDomains domainMap) error {
+func (r *Route) updateDynamicRoutes(ctx context.Context, newDomains domainMap, ttls map[domain.Domain]time.Duration) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -270,6 +287,11 @@ func (r *Route) updateDynamicRoutes(ctx context.Context, newDomains domainMap) e
 	for domain, newPrefixes := range newDomains {
 		oldPrefixes := r.dynamicDomains[domain]
 		toAdd, toRemove := determinePrefixChanges(oldPrefixes, newPrefixes)
+		r.refreshExpiries(domain, newPrefixes, ttls[domain])
+
+		if r.route.KeepRoute {
+			toRemove = r.expiredPrefixes(domain, toRemove)
+		}
 
 		addedPrefixes, err := r.addRoutes(domain, toAdd)
 		if err != nil {
@@ -278,7 +300,7 @@ func (r *Route) updateDynamicRoutes(ctx context.Context, newDomains domainMap) e
 			log.Debugf("Added dynamic route(s) for [%s]: %s", domain.SafeString(), strings.ReplaceAll(fmt.Sprintf("%s", addedPrefixes), " ", ", "))
 		}
 
-		removedPrefixes, err := r.removeRoutes(toRemove)
+		removedPrefixes, err := r.removeRoutes(domain, toRemove)
 		if err != nil {
 			merr = multierror.Append(merr, err)
 		} else if len(removedPrefixes) > 0 {
@@ -314,11 +336,7 @@ func (r *Route) addRoutes(domain domain.Domain, prefixes []netip.Prefix) ([]neti
 	return addedPrefixes, merr.ErrorOrNil()
 }
 
-func (r *Route) removeRoutes(prefixes []netip.Prefix) ([]netip.Prefix, error) {
-	if r.route.KeepRoute {
-		return nil, nil
-	}
-
+func (r *Route) removeRoutes(domain domain.Domain, prefixes []netip.Prefix) ([]netip.Prefix, error) {
 	var removedPrefixes []netip.Prefix
 	var merr *multierror.Error
 
@@ -331,12 +349,41 @@ func (r *Route) removeRoutes(prefixes []netip.Prefix) ([]netip.Prefix, error) {
 				merr = multierror.Append(merr, fmt.Errorf("remove allowed IP %s: %w", prefix, err))
 			}
 		}
+		delete(r.expiries[domain], prefix)
 		removedPrefixes = append(removedPrefixes, prefix)
 	}
 
 	return removedPrefixes, merr.ErrorOrNil()
 }
 
+// refreshExpiries extends the lifetime of the resolved prefixes of a domain based on the answer TTL.
+// The prefixes are resolved again every interval, so they don't expire before the next resolution.
+func (r *Route) refreshExpiries(domain domain.Domain, prefixes []netip.Prefix, ttl time.Duration) {
+	domainExpiries, ok := r.expiries[domain]
+	if !ok {
+		domainExpiries = make(map[netip.Prefix]time.Time)
+		r.expiries[domain] = domainExpiries
+	}
+
+	expiry := util.PrefixExpiry(time.Now(), max(ttl, r.interval))
+	for _, prefix := range prefixes {
+		domainExpiries[prefix] = expiry
+	}
+}
+
+// expiredPrefixes returns the prefixes that haven't been resolved within their TTL and grace period.
+func (r *Route) expiredPrefixes(domain domain.Domain, prefixes []netip.Prefix) []netip.Prefix {
+	now := time.Now()
+
+	var expired []netip.Prefix
+	for _, prefix := range prefixes {
+		if now.After(r.expiries[domain][prefix]) {
+			expired = append(expired, prefix)
+		}
+	}
+	return expired
+}
+
 func (r *Route) incrementAllowedIP(domain domain.Domain, prefix netip.Prefix, peerKey string) error {
 	if ref, err := r.allowedIPsRefcounter.Increment(prefix, peerKey); err != nil {
 		return fmt.Errorf(addAllowedIP, prefix, err)
@@ -4,10 +4,13 @@ package dynamic
 
 import (
 	"net"
+	"time"
 
 	"github.com/netbirdio/netbird/management/domain"
 )
 
-func (r *Route) getIPsFromResolver(domain domain.Domain) ([]net.IP, error) {
-	return net.LookupIP(string(domain))
+// getIPsFromResolver resolves the domain with the system resolver, which doesn't report the TTL
+func (r *Route) getIPsFromResolver(domain domain.Domain) ([]net.IP, time.Duration, error) {
+	ips, err := net.LookupIP(string(domain))
+	return ips, 0, err
 }
@@ -16,10 +16,10 @@ import (
 
 const dialTimeout = 10 * time.Second
 
-func (r *Route) getIPsFromResolver(domain domain.Domain) ([]net.IP, error) {
+func (r *Route) getIPsFromResolver(domain domain.Domain) ([]net.IP, time.Duration, error) {
 	privateClient, err := nbdns.GetClientPrivate(r.wgInterface.Address().IP, r.wgInterface.Name(), dialTimeout)
 	if err != nil {
-		return nil, fmt.Errorf("error while creating private client: %s", err)
+		return nil, 0, fmt.Errorf("error while creating private client: %s", err)
 	}
 
 	msg := new(dns.Msg)
@@ -29,27 +29,34 @@ func (r *Route) getIPsFromResolver(domain domain.Domain) ([]net.IP, error) {
 
 	response, _, err := privateClient.Exchange(msg, r.resolverAddr)
 	if err != nil {
-		return nil, fmt.Errorf("DNS query for %s failed after %s: %s ", domain.SafeString(), time.Since(startTime), err)
+		return nil, 0, fmt.Errorf("DNS query for %s failed after %s: %s ", domain.SafeString(), time.Since(startTime), err)
 	}
 
 	if response.Rcode != dns.RcodeSuccess {
-		return nil, fmt.Errorf("dns response code: %s", dns.RcodeToString[response.Rcode])
+		return nil, 0, fmt.Errorf("dns response code: %s", dns.RcodeToString[response.Rcode])
 	}
 
 	ips := make([]net.IP, 0)
+	var ttl uint32
 
 	for _, answ := range response.Answer {
-		if aRecord, ok := answ.(*dns.A); ok {
-			ips = append(ips, aRecord.A)
+		switch record := answ.(type) {
+		case *dns.A:
+			ips = append(ips, record.A)
+		case *dns.AAAA:
+			ips = append(ips, record.AAAA)
+		default:
+			continue
 		}
-		if aaaaRecord, ok := answ.(*dns.AAAA); ok {
-			ips = append(ips, aaaaRecord.AAAA)
+
+		if hdrTTL := answ.Header().Ttl; ttl == 0 || hdrTTL < ttl {
+			ttl = hdrTTL
 		}
 	}
 
 	if len(ips) == 0 {
-		return nil, fmt.Errorf("no A or AAAA records found for %s", domain.SafeString())
+		return nil, 0, fmt.Errorf("no A or AAAA records found for %s", domain.SafeString())
 	}
 
-	return ips, nil
+	return ips, time.Duration(ttl) * time.Second, nil
 }
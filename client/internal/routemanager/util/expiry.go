@@ -0,0 +1,15 @@
+package util
+
+import "time"
+
+const (
+	// MinPrefixTTL is the lowest TTL honored for resolved IPs, to avoid churn on very short DNS TTLs
+	MinPrefixTTL = time.Minute
+	// KeepRouteGracePeriod is how long resolved IPs of routes with KeepRoute stay routed after their TTL expired
+	KeepRouteGracePeriod = 30 * time.Minute
+)
+
+// PrefixExpiry returns until when an IP resolved at the given time with the given TTL stays routed for routes with KeepRoute
+func PrefixExpiry(resolvedAt time.Time, ttl time.Duration) time.Time {
+	return resolvedAt.Add(max(ttl, MinPrefixTTL) + KeepRouteGracePeriod)
+}
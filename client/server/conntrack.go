@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/firewall/uspfilter/conntrack"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+type conntrackManager interface {
+	ConntrackEntries(filter conntrack.Filter) ([]conntrack.Entry, error)
+	FlushConntrack(filter conntrack.Filter) (int, error)
+}
+
+// ListConntrack returns the connections tracked by the userspace firewall
+func (s *Server) ListConntrack(_ context.Context, req *proto.ConntrackFilter) (*proto.ListConntrackResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	manager, err := s.getConntrackManager()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := toConntrackFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := manager.ConntrackEntries(filter)
+	if err != nil {
+		return nil, fmt.Errorf("list conntrack entries: %w", err)
+	}
+
+	resp := &proto.ListConntrackResponse{}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, toProtoConntrackEntry(entry))
+	}
+
+	return resp, nil
+}
+
+// FlushConntrack removes the tracked connections matching the filter
+func (s *Server) FlushConntrack(_ context.Context, req *proto.ConntrackFilter) (*proto.FlushConntrackResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	manager, err := s.getConntrackManager()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := toConntrackFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	flushed, err := manager.FlushConntrack(filter)
+	if err != nil {
+		return nil, fmt.Errorf("flush conntrack entries: %w", err)
+	}
+
+	return &proto.FlushConntrackResponse{Flushed: uint32(flushed)}, nil
+}
+
+func (s *Server) getConntrackManager() (conntrackManager, error) {
+	if s.connectClient == nil {
+		return nil, fmt.Errorf("connect client not initialized")
+	}
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, fmt.Errorf("engine not initialized")
+	}
+
+	fwManager := engine.GetFirewallManager()
+	if fwManager == nil {
+		return nil, fmt.Errorf("firewall manager not initialized")
+	}
+
+	manager, ok := fwManager.(conntrackManager)
+	if !ok {
+		return nil, fmt.Errorf("firewall manager does not support connection tracking inspection")
+	}
+	return manager, nil
+}
+
+func toConntrackFilter(req *proto.ConntrackFilter) (conntrack.Filter, error) {
+	var filter conntrack.Filter
+
+	if req.GetIp() != "" {
+		addr, err := netip.ParseAddr(req.GetIp())
+		if err != nil {
+			return filter, fmt.Errorf("invalid IP address: %s", req.GetIp())
+		}
+		filter.IP = addr
+	}
+
+	if req.GetPort() > 65535 {
+		return filter, fmt.Errorf("invalid port: %d", req.GetPort())
+	}
+	filter.Port = uint16(req.GetPort())
+
+	switch strings.ToLower(req.GetProtocol()) {
+	case "":
+	case "tcp":
+		filter.Protocol = nftypes.TCP
+	case "udp":
+		filter.Protocol = nftypes.UDP
+	case "icmp":
+		filter.Protocol = nftypes.ICMP
+	default:
+		return filter, fmt.Errorf("invalid protocol: %s", req.GetProtocol())
+	}
+
+	return filter, nil
+}
+
+func toProtoConntrackEntry(entry conntrack.Entry) *proto.ConntrackEntry {
+	return &proto.ConntrackEntry{
+		Protocol:        entry.Protocol.String(),
+		Direction:       entry.Direction.String(),
+		SourceIp:        entry.SourceIP.Unmap().String(),
+		DestinationIp:   entry.DestIP.Unmap().String(),
+		SourcePort:      uint32(entry.SourcePort),
+		DestinationPort: uint32(entry.DestPort),
+		IcmpType:        uint32(entry.ICMPType),
+		IcmpCode:        uint32(entry.ICMPCode),
+		IcmpId:          uint32(entry.ICMPID),
+		State:           entry.State,
+		RuleId:          string(entry.RuleID),
+		LastSeen:        timestamppb.New(entry.LastSeen),
+		Timeout:         durationpb.New(entry.Timeout),
+		ExpiresIn:       durationpb.New(entry.ExpiresIn()),
+		PacketsTx:       entry.PacketsTx,
+		PacketsRx:       entry.PacketsRx,
+		BytesTx:         entry.BytesTx,
+		BytesRx:         entry.BytesRx,
+	}
+}
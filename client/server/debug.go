@@ -28,7 +28,6 @@ import (
 	"github.com/netbirdio/netbird/client/anonymize"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
-	"github.com/netbirdio/netbird/client/internal/statemanager"
 	"github.com/netbirdio/netbird/client/proto"
 	mgmProto "github.com/netbirdio/netbird/management/proto"
 )
@@ -37,6 +36,7 @@ const readmeContent = `Netbird debug bundle
 This debug bundle contains the following files:
 
 status.txt: Anonymized status information of the NetBird client.
+diagnostics.txt: Anonymized connection diagnostics of the peers passed with the --diagnose flag.
 client.log: Most recent, anonymized client log file of the NetBird client.
 netbird.err: Most recent, anonymized stderr log file of the NetBird client.
 netbird.out: Most recent, anonymized stdout log file of the NetBird client.
@@ -188,6 +188,10 @@ func (s *Server) createArchive(bundlePath *os.File, req *proto.DebugBundleReques
 		return fmt.Errorf("add status: %w", err)
 	}
 
+	if err := s.addDiagnostics(req, archive); err != nil {
+		return fmt.Errorf("add diagnostics: %w", err)
+	}
+
 	anonymizer := anonymize.NewAnonymizer(anonymize.DefaultAddresses())
 	status := s.statusRecorder.GetFullStatus()
 	seedFromStatus(anonymizer, &status)
@@ -262,6 +266,16 @@ func (s *Server) addStatus(req *proto.DebugBundleRequest, archive *zip.Writer) e
 	return nil
 }
 
+func (s *Server) addDiagnostics(req *proto.DebugBundleRequest, archive *zip.Writer) error {
+	if diagnostics := req.GetDiagnostics(); diagnostics != "" {
+		diagnosticsReader := strings.NewReader(diagnostics)
+		if err := addFileToZip(archive, diagnosticsReader, "diagnostics.txt"); err != nil {
+			return fmt.Errorf("add diagnostics file to zip: %w", err)
+		}
+	}
+	return nil
+}
+
 func (s *Server) addConfig(req *proto.DebugBundleRequest, anonymizer *anonymize.Anonymizer, archive *zip.Writer) error {
 	var configContent strings.Builder
 	s.addCommonConfigFields(&configContent)
@@ -324,6 +338,7 @@ func (s *Server) addCommonConfigFields(configContent *strings.Builder) {
 	configContent.WriteString(fmt.Sprintf("DisableFirewall: %v\n", s.config.DisableFirewall))
 
 	configContent.WriteString(fmt.Sprintf("BlockLANAccess: %v\n", s.config.BlockLANAccess))
+	configContent.WriteString(fmt.Sprintf("OfflineStart: %v\n", s.config.OfflineStart))
 }
 
 func (s *Server) addProf(req *proto.DebugBundleRequest, anonymizer *anonymize.Anonymizer, archive *zip.Writer) error {
@@ -413,7 +428,7 @@ func (s *Server) addNetworkMap(req *proto.DebugBundleRequest, anonymizer *anonym
 }
 
 func (s *Server) addStateFile(req *proto.DebugBundleRequest, anonymizer *anonymize.Anonymizer, archive *zip.Writer) error {
-	path := statemanager.GetDefaultStatePath()
+	path := s.activeProfile.StatePath
 	if path == "" {
 		return nil
 	}
@@ -451,7 +466,7 @@ func (s *Server) addStateFile(req *proto.DebugBundleRequest, anonymizer *anonymi
 }
 
 func (s *Server) addCorruptedStateFiles(archive *zip.Writer) error {
-	pattern := statemanager.GetDefaultStatePath()
+	pattern := s.activeProfile.StatePath
 	if pattern == "" {
 		return nil
 	}
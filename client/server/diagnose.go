@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// DiagnosePeer returns the ICE, NAT, relay and reconnect details of the connection to a remote peer
+func (s *Server) DiagnosePeer(ctx context.Context, req *proto.DiagnosePeerRequest) (*proto.DiagnosePeerResponse, error) {
+	s.mutex.Lock()
+	var engine *internal.Engine
+	if s.connectClient != nil {
+		engine = s.connectClient.Engine()
+	}
+	s.mutex.Unlock()
+
+	// the diagnosis takes a few seconds, the engine is used without holding the server lock
+	if engine == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "engine not initialized, run netbird up first")
+	}
+
+	pubKey, err := findPeerKey(s.statusRecorder.GetFullStatus().Peers, req.GetPeer())
+	if err != nil {
+		return nil, err
+	}
+
+	diag, err := engine.DiagnosePeer(ctx, pubKey)
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "diagnose peer: %v", err)
+	}
+
+	return &proto.DiagnosePeerResponse{Diagnostics: toProtoDiagnostics(diag)}, nil
+}
+
+// findPeerKey returns the public key of the peer matching the public key, NetBird IP, FQDN or
+// the first label of the FQDN
+func findPeerKey(peers []peer.State, query string) (string, error) {
+	query = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(query)), ".")
+	if query == "" {
+		return "", gstatus.Errorf(codes.InvalidArgument, "peer is required")
+	}
+
+	var matches []peer.State
+	for _, p := range peers {
+		fqdn := strings.TrimSuffix(strings.ToLower(p.FQDN), ".")
+		switch {
+		case strings.ToLower(p.PubKey) == query, p.IP == query, fqdn == query:
+			return p.PubKey, nil
+		case strings.SplitN(fqdn, ".", 2)[0] == query:
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", gstatus.Errorf(codes.NotFound, "peer %s not found", query)
+	case 1:
+		return matches[0].PubKey, nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m.FQDN)
+		}
+		return "", gstatus.Errorf(codes.InvalidArgument, "peer %s is ambiguous, use one of: %s", query, strings.Join(names, ", "))
+	}
+}
+
+func toProtoDiagnostics(diag *internal.PeerDiagnostics) *proto.PeerDiagnostics {
+	pbDiag := &proto.PeerDiagnostics{
+		PubKey:                 diag.State.PubKey,
+		Fqdn:                   diag.State.FQDN,
+		IP:                     diag.State.IP,
+		ConnStatus:             diag.State.ConnStatus.String(),
+		Relayed:                diag.State.Relayed,
+		IceStatus:              diag.Conn.ICEStatus.String(),
+		RelayStatus:            diag.Conn.RelayStatus.String(),
+		RelayAddress:           diag.State.RelayServerAddress,
+		RelaySupportedLocally:  diag.Conn.RelaySupportedLocally,
+		RelaySupportedByRemote: diag.Conn.RelaySupportedByRemote,
+		Ice: &proto.ICEDiagnostics{
+			State:     diag.Conn.ICE.State,
+			LastError: diag.Conn.ICE.LastError,
+		},
+		Nat: &proto.NATDiagnostics{},
+	}
+
+	if !diag.Conn.ICE.LastAttempt.IsZero() {
+		pbDiag.Ice.LastAttempt = timestamppb.New(diag.Conn.ICE.LastAttempt)
+	}
+	for _, c := range diag.Conn.ICE.LocalCandidates {
+		pbDiag.Ice.LocalCandidates = append(pbDiag.Ice.LocalCandidates, toProtoCandidate(c))
+	}
+	for _, c := range diag.Conn.ICE.RemoteCandidates {
+		pbDiag.Ice.RemoteCandidates = append(pbDiag.Ice.RemoteCandidates, toProtoCandidate(c))
+	}
+	for _, pair := range diag.Conn.ICE.CandidatePairs {
+		pbDiag.Ice.CandidatePairs = append(pbDiag.Ice.CandidatePairs, &proto.ICECandidatePair{
+			Local:     toProtoCandidate(pair.Local),
+			Remote:    toProtoCandidate(pair.Remote),
+			State:     pair.State,
+			Nominated: pair.Nominated,
+		})
+	}
+
+	if diag.NATErr != nil {
+		pbDiag.Nat.Error = diag.NATErr.Error()
+	}
+	if diag.NAT != nil {
+		pbDiag.Nat.Type = string(diag.NAT.Type)
+		pbDiag.Nat.LocalAddress = diag.NAT.LocalAddr
+		for _, mapping := range diag.NAT.Mappings {
+			pbMapping := &proto.STUNMapping{
+				Server:        mapping.Server,
+				MappedAddress: mapping.MappedAddress,
+			}
+			if mapping.Err != nil {
+				pbMapping.Error = mapping.Err.Error()
+			}
+			pbDiag.Nat.Mappings = append(pbDiag.Nat.Mappings, pbMapping)
+		}
+	}
+
+	for _, relayState := range diag.Relays {
+		pbRelayState := &proto.RelayState{
+			URI:       relayState.URI,
+			Available: relayState.Err == nil,
+		}
+		if err := relayState.Err; err != nil {
+			pbRelayState.Error = err.Error()
+		}
+		pbDiag.Relays = append(pbDiag.Relays, pbRelayState)
+	}
+
+	for _, event := range diag.Conn.ReconnectHistory {
+		pbDiag.ReconnectHistory = append(pbDiag.ReconnectHistory, &proto.ReconnectEvent{
+			Time:      timestamppb.New(event.Time),
+			Reason:    event.Reason,
+			OfferSent: event.OfferSent,
+		})
+	}
+
+	return pbDiag
+}
+
+func toProtoCandidate(c peer.ICECandidateInfo) *proto.ICECandidate {
+	return &proto.ICECandidate{
+		Type:           c.Type,
+		Network:        c.Network,
+		Address:        c.Address,
+		Port:           int32(c.Port),
+		RelatedAddress: c.RelatedAddress,
+		IgnoredReason:  c.IgnoredReason,
+	}
+}
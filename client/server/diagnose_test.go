@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+func TestFindPeerKey(t *testing.T) {
+	peers := []peer.State{
+		{PubKey: "keyA", IP: "100.64.0.1", FQDN: "laptop.netbird.cloud"},
+		{PubKey: "keyB", IP: "100.64.0.2", FQDN: "server.netbird.cloud"},
+		{PubKey: "keyC", IP: "100.64.0.3", FQDN: "server.netbird.selfhosted"},
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedKey  string
+		expectedCode codes.Code
+	}{
+		{name: "public key", query: "keyB", expectedKey: "keyB"},
+		{name: "netbird ip", query: "100.64.0.3", expectedKey: "keyC"},
+		{name: "fqdn", query: "Server.NetBird.Cloud.", expectedKey: "keyB"},
+		{name: "short name", query: "laptop", expectedKey: "keyA"},
+		{name: "ambiguous short name", query: "server", expectedCode: codes.InvalidArgument},
+		{name: "unknown peer", query: "printer", expectedCode: codes.NotFound},
+		{name: "empty", query: " ", expectedCode: codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := findPeerKey(peers, tc.query)
+			if tc.expectedCode != codes.OK {
+				assert.Equal(t, tc.expectedCode, gstatus.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedKey, key)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/profile"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/netbirdio/netbird/util"
+)
+
+// ListProfiles returns the client profiles
+func (s *Server) ListProfiles(_ context.Context, _ *proto.ListProfilesRequest) (*proto.ListProfilesResponse, error) {
+	s.mutex.Lock()
+	activeName := s.activeProfile.Name
+	s.mutex.Unlock()
+
+	profiles, err := s.profileManager.List()
+	if err != nil {
+		return nil, toProfileError(err)
+	}
+
+	resp := &proto.ListProfilesResponse{Profiles: make([]*proto.Profile, 0, len(profiles))}
+	for _, p := range profiles {
+		resp.Profiles = append(resp.Profiles, toProtoProfile(p, p.Name == activeName))
+	}
+
+	return resp, nil
+}
+
+// AddProfile creates a client profile, its config is generated when the profile is used for the first time
+func (s *Server) AddProfile(_ context.Context, req *proto.AddProfileRequest) (*proto.AddProfileResponse, error) {
+	p, err := s.profileManager.Add(req.GetName())
+	if err != nil {
+		return nil, toProfileError(err)
+	}
+
+	log.Infof("added profile %s", p.Name)
+
+	return &proto.AddProfileResponse{Profile: toProtoProfile(p, false)}, nil
+}
+
+// RemoveProfile removes a client profile with its config and state, the active profile can't be removed
+func (s *Server) RemoveProfile(_ context.Context, req *proto.RemoveProfileRequest) (*proto.RemoveProfileResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.profileManager.Remove(req.GetName()); err != nil {
+		return nil, toProfileError(err)
+	}
+
+	log.Infof("removed profile %s", req.GetName())
+
+	return &proto.RemoveProfileResponse{}, nil
+}
+
+// SwitchProfile disconnects the active profile and activates the requested one. The new profile connects right away
+// when it is logged in and auto connect isn't disabled, otherwise the daemon waits for a login or an up command.
+func (s *Server) SwitchProfile(_ context.Context, req *proto.SwitchProfileRequest) (*proto.SwitchProfileResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	p, err := s.profileManager.Get(req.GetName())
+	if err != nil {
+		return nil, toProfileError(err)
+	}
+
+	state := internal.CtxGetState(s.rootCtx)
+
+	if p.Name != s.activeProfile.Name {
+		s.disconnect()
+
+		if _, err := s.profileManager.SetActive(p.Name); err != nil {
+			return nil, toProfileError(err)
+		}
+
+		log.Infof("switched from profile %s to %s", s.activeProfile.Name, p.Name)
+
+		s.activeProfile = p
+		s.latestConfigInput = internal.ConfigInput{ConfigPath: p.ConfigPath}
+		s.oauthAuthFlow = oauthAuthFlow{}
+		state.Set(internal.StatusIdle)
+
+		if err := restoreResidualState(s.rootCtx, p.StatePath); err != nil {
+			log.Warnf(errRestoreResidualState, err)
+		}
+
+		if err := s.loadConfigAndConnect(); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := state.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SwitchProfileResponse{Status: string(status)}, nil
+}
+
+// disconnect stops the connection of the active profile. It has to be called with the mutex held.
+func (s *Server) disconnect() {
+	if s.actCancel != nil {
+		s.actCancel()
+	}
+
+	if s.connectClient != nil {
+		if err := s.connectClient.Stop(); err != nil {
+			log.Errorf("failed to stop the connection of profile %s: %v", s.activeProfile.Name, err)
+		}
+	}
+}
+
+func toProtoProfile(p *profile.Profile, active bool) *proto.Profile {
+	pbProfile := &proto.Profile{
+		Name:       p.Name,
+		Active:     active,
+		ConfigFile: p.ConfigPath,
+	}
+
+	config := &internal.Config{}
+	if _, err := util.ReadJson(p.ConfigPath, config); err == nil && config.ManagementURL != nil {
+		pbProfile.ManagementUrl = config.ManagementURL.String()
+	}
+
+	return pbProfile
+}
+
+func toProfileError(err error) error {
+	switch {
+	case errors.Is(err, profile.ErrInvalidProfileName):
+		return gstatus.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, profile.ErrProfileNotFound):
+		return gstatus.Error(codes.NotFound, err.Error())
+	case errors.Is(err, profile.ErrProfileExists):
+		return gstatus.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, profile.ErrProfileActive), errors.Is(err, profile.ErrDefaultProfile):
+		return gstatus.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return gstatus.Errorf(codes.Internal, "profile operation failed: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/profile"
+	daemonProto "github.com/netbirdio/netbird/client/proto"
+)
+
+func TestServer_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NB_DNS_STATE_FILE", filepath.Join(dir, "state.json"))
+
+	ctx := internal.CtxInitState(context.Background())
+	s := New(ctx, filepath.Join(dir, "config.json"), "console")
+	require.NoError(t, s.Start())
+
+	defaultConfig := s.config
+	require.NotNil(t, defaultConfig)
+
+	_, err := s.AddProfile(ctx, &daemonProto.AddProfileRequest{Name: "bad name"})
+	assert.Equal(t, codes.InvalidArgument, gstatus.Code(err))
+
+	added, err := s.AddProfile(ctx, &daemonProto.AddProfileRequest{Name: "customer"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "profiles", "customer", "config.json"), added.Profile.ConfigFile)
+
+	list, err := s.ListProfiles(ctx, &daemonProto.ListProfilesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Profiles, 2)
+	assert.Equal(t, profile.DefaultProfileName, list.Profiles[0].Name)
+	assert.True(t, list.Profiles[0].Active)
+	assert.Equal(t, internal.DefaultManagementURL, list.Profiles[0].ManagementUrl)
+	assert.False(t, list.Profiles[1].Active)
+	assert.Empty(t, list.Profiles[1].ManagementUrl, "unused profile shouldn't have a config")
+
+	_, err = s.SwitchProfile(ctx, &daemonProto.SwitchProfileRequest{Name: "missing"})
+	assert.Equal(t, codes.NotFound, gstatus.Code(err))
+
+	switched, err := s.SwitchProfile(ctx, &daemonProto.SwitchProfileRequest{Name: "customer"})
+	require.NoError(t, err)
+	assert.Equal(t, string(internal.StatusNeedsLogin), switched.Status)
+	assert.NotEqual(t, defaultConfig.PrivateKey, s.config.PrivateKey, "profiles should have their own WireGuard key")
+
+	config, err := s.GetConfig(ctx, &daemonProto.GetConfigRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "customer", config.ProfileName)
+	assert.Equal(t, added.Profile.ConfigFile, config.ConfigFile)
+
+	_, err = s.RemoveProfile(ctx, &daemonProto.RemoveProfileRequest{Name: "customer"})
+	assert.Equal(t, codes.FailedPrecondition, gstatus.Code(err))
+
+	_, err = s.SwitchProfile(ctx, &daemonProto.SwitchProfileRequest{Name: profile.DefaultProfileName})
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfig.PrivateKey, s.config.PrivateKey, "default profile config should be kept")
+
+	_, err = s.RemoveProfile(ctx, &daemonProto.RemoveProfileRequest{Name: "customer"})
+	require.NoError(t, err)
+
+	list, err = s.ListProfiles(ctx, &daemonProto.ListProfilesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.Profiles, 1)
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/netbirdio/netbird/client/internal"
 	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/profile"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/version"
 )
@@ -66,6 +67,10 @@ type Server struct {
 
 	lastProbe         time.Time
 	persistNetworkMap bool
+
+	profileManager *profile.Manager
+	// activeProfile is the profile whose config the daemon uses
+	activeProfile *profile.Profile
 }
 
 type oauthAuthFlow struct {
@@ -75,16 +80,21 @@ type oauthAuthFlow struct {
 	waitCancel context.CancelFunc
 }
 
-// New server instance constructor.
+// New server instance constructor. The configPath is the config of the default profile.
 func New(ctx context.Context, configPath, logFile string) *Server {
+	profileManager := profile.NewManager(configPath)
+	activeProfile := profileManager.Active()
+
 	return &Server{
 		rootCtx: ctx,
 		latestConfigInput: internal.ConfigInput{
-			ConfigPath: configPath,
+			ConfigPath: activeProfile.ConfigPath,
 		},
 		logFile:           logFile,
 		persistNetworkMap: true,
 		statusRecorder:    peer.NewRecorder(""),
+		profileManager:    profileManager,
+		activeProfile:     activeProfile,
 	}
 }
 
@@ -97,7 +107,7 @@ func (s *Server) Start() error {
 		log.Warnf("failed to redirect stderr: %v", err)
 	}
 
-	if err := restoreResidualState(s.rootCtx); err != nil {
+	if err := restoreResidualState(s.rootCtx, s.activeProfile.StatePath); err != nil {
 		log.Warnf(errRestoreResidualState, err)
 	}
 
@@ -113,6 +123,15 @@ func (s *Server) Start() error {
 		return nil
 	}
 
+	return s.loadConfigAndConnect()
+}
+
+// loadConfigAndConnect loads the config of the active profile and connects unless auto connect is disabled.
+// When the profile has no config yet, a new one is created and the daemon waits for login.
+// It has to be called with the mutex held.
+func (s *Server) loadConfigAndConnect() error {
+	state := internal.CtxGetState(s.rootCtx)
+
 	ctx, cancel := context.WithCancel(s.rootCtx)
 	s.actCancel = cancel
 
@@ -149,7 +168,7 @@ func (s *Server) Start() error {
 		return nil
 	}
 
-	go s.connectWithRetryRuns(ctx, config, s.statusRecorder, nil)
+	go s.connectWithRetryRuns(ctx, config, s.statusRecorder, s.activeProfile.StatePath, nil)
 
 	return nil
 }
@@ -158,7 +177,7 @@ func (s *Server) Start() error {
 // mechanism to keep the client connected even when the connection is lost.
 // we cancel retry if the client receive a stop or down command, or if disable auto connect is configured.
 func (s *Server) connectWithRetryRuns(ctx context.Context, config *internal.Config, statusRecorder *peer.Status,
-	runningChan chan struct{},
+	statePath string, runningChan chan struct{},
 ) {
 	backOff := getConnectWithBackoff(ctx)
 	retryStarted := false
@@ -190,6 +209,7 @@ func (s *Server) connectWithRetryRuns(ctx context.Context, config *internal.Conf
 		log.Tracef("running client connection")
 		s.connectClient = internal.NewConnectClient(ctx, config, statusRecorder)
 		s.connectClient.SetNetworkMapPersistence(s.persistNetworkMap)
+		s.connectClient.SetStatePath(statePath)
 
 		err := s.connectClient.Run(runningChan)
 		if err != nil {
@@ -287,9 +307,10 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 	}
 
 	s.actCancel = cancel
+	statePath := s.activeProfile.StatePath
 	s.mutex.Unlock()
 
-	if err := restoreResidualState(ctx); err != nil {
+	if err := restoreResidualState(ctx, statePath); err != nil {
 		log.Warnf(errRestoreResidualState, err)
 	}
 
@@ -403,6 +424,11 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 		s.latestConfigInput.BlockLANAccess = msg.BlockLanAccess
 	}
 
+	if msg.OfflineStart != nil {
+		inputConfig.OfflineStart = msg.OfflineStart
+		s.latestConfigInput.OfflineStart = msg.OfflineStart
+	}
+
 	if msg.CleanDNSLabels {
 		inputConfig.DNSLabels = domain.List{}
 		s.latestConfigInput.DNSLabels = nil
@@ -586,7 +612,7 @@ func (s *Server) Up(callerCtx context.Context, _ *proto.UpRequest) (*proto.UpRes
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if err := restoreResidualState(callerCtx); err != nil {
+	if err := restoreResidualState(callerCtx, s.activeProfile.StatePath); err != nil {
 		log.Warnf(errRestoreResidualState, err)
 	}
 
@@ -627,7 +653,7 @@ func (s *Server) Up(callerCtx context.Context, _ *proto.UpRequest) (*proto.UpRes
 	defer cancel()
 
 	runningChan := make(chan struct{}, 1) // buffered channel to do not lose the signal
-	go s.connectWithRetryRuns(ctx, s.config, s.statusRecorder, runningChan)
+	go s.connectWithRetryRuns(ctx, s.config, s.statusRecorder, s.activeProfile.StatePath, runningChan)
 	for {
 		select {
 		case <-runningChan:
@@ -762,6 +788,7 @@ func (s *Server) GetConfig(_ context.Context, _ *proto.GetConfigRequest) (*proto
 		RosenpassEnabled:     s.config.RosenpassEnabled,
 		RosenpassPermissive:  s.config.RosenpassPermissive,
 		DisableNotifications: disableNotifications,
+		ProfileName:          s.activeProfile.Name,
 	}, nil
 }
 
@@ -786,6 +813,8 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 
 	pbFullStatus.ManagementState.URL = fullStatus.ManagementState.URL
 	pbFullStatus.ManagementState.Connected = fullStatus.ManagementState.Connected
+	pbFullStatus.ManagementState.NetworkMapStale = fullStatus.ManagementState.NetworkMapStale
+	pbFullStatus.ManagementState.RelayCredentialsExpired = fullStatus.ManagementState.RelayCredentialsExpired
 	if err := fullStatus.ManagementState.Error; err != nil {
 		pbFullStatus.ManagementState.Error = err.Error()
 	}
@@ -824,6 +853,14 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 			RosenpassEnabled:           peerState.RosenpassEnabled,
 			Networks:                   maps.Keys(peerState.GetRoutes()),
 			Latency:                    durationpb.New(peerState.Latency),
+			Quality: &proto.ConnectionQuality{
+				ProbesSent:     int32(peerState.Quality.ProbesSent),
+				ProbesReceived: int32(peerState.Quality.ProbesReceived),
+				PacketLoss:     peerState.Quality.PacketLoss,
+				Jitter:         durationpb.New(peerState.Quality.Jitter),
+				PathMTU:        int32(peerState.Quality.PathMTU),
+				Answered:       peerState.Quality.Answered,
+			},
 		}
 		pbFullStatus.Peers = append(pbFullStatus.Peers, pbPeerState)
 	}
@@ -836,6 +873,9 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 		if err := relayState.Err; err != nil {
 			pbRelayState.Error = err.Error()
 		}
+		if relayState.RTT > 0 {
+			pbRelayState.Rtt = durationpb.New(relayState.RTT)
+		}
 		pbFullStatus.Relays = append(pbFullStatus.Relays, pbRelayState)
 	}
 
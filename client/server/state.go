@@ -16,7 +16,7 @@ import (
 
 // ListStates returns a list of all saved states
 func (s *Server) ListStates(_ context.Context, _ *proto.ListStatesRequest) (*proto.ListStatesResponse, error) {
-	mgr := statemanager.New(statemanager.GetDefaultStatePath())
+	mgr := statemanager.New(s.statePath())
 
 	stateNames, err := mgr.GetSavedStateNames()
 	if err != nil {
@@ -43,12 +43,12 @@ func (s *Server) CleanState(ctx context.Context, req *proto.CleanStateRequest) (
 
 	if req.All {
 		// Reuse existing cleanup logic for all states
-		if err := restoreResidualState(ctx); err != nil {
+		if err := restoreResidualState(ctx, s.statePath()); err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to clean all states: %v", err)
 		}
 
 		// Get count of cleaned states
-		mgr := statemanager.New(statemanager.GetDefaultStatePath())
+		mgr := statemanager.New(s.statePath())
 		stateNames, err := mgr.GetSavedStateNames()
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get state count: %v", err)
@@ -60,7 +60,7 @@ func (s *Server) CleanState(ctx context.Context, req *proto.CleanStateRequest) (
 	}
 
 	// Handle single state cleanup
-	mgr := statemanager.New(statemanager.GetDefaultStatePath())
+	mgr := statemanager.New(s.statePath())
 	registerStates(mgr)
 
 	if err := mgr.CleanupStateByName(req.StateName); err != nil {
@@ -82,7 +82,7 @@ func (s *Server) DeleteState(ctx context.Context, req *proto.DeleteStateRequest)
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot clean state while connecting or connected, run 'netbird down' first.")
 	}
 
-	mgr := statemanager.New(statemanager.GetDefaultStatePath())
+	mgr := statemanager.New(s.statePath())
 
 	var count int
 	var err error
@@ -110,10 +110,17 @@ func (s *Server) DeleteState(ctx context.Context, req *proto.DeleteStateRequest)
 	}, nil
 }
 
+// statePath returns the state file of the active profile
+func (s *Server) statePath() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.activeProfile.StatePath
+}
+
 // restoreResidualState checks if the client was not shut down in a clean way and restores residual if required.
 // Otherwise, we might not be able to connect to the management server to retrieve new config.
-func restoreResidualState(ctx context.Context) error {
-	path := statemanager.GetDefaultStatePath()
+func restoreResidualState(ctx context.Context, path string) error {
 	if path == "" {
 		return nil
 	}
@@ -20,21 +20,31 @@ import (
 )
 
 type PeerStateDetailOutput struct {
-	FQDN                   string           `json:"fqdn" yaml:"fqdn"`
-	IP                     string           `json:"netbirdIp" yaml:"netbirdIp"`
-	PubKey                 string           `json:"publicKey" yaml:"publicKey"`
-	Status                 string           `json:"status" yaml:"status"`
-	LastStatusUpdate       time.Time        `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
-	ConnType               string           `json:"connectionType" yaml:"connectionType"`
-	IceCandidateType       IceCandidateType `json:"iceCandidateType" yaml:"iceCandidateType"`
-	IceCandidateEndpoint   IceCandidateType `json:"iceCandidateEndpoint" yaml:"iceCandidateEndpoint"`
-	RelayAddress           string           `json:"relayAddress" yaml:"relayAddress"`
-	LastWireguardHandshake time.Time        `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
-	TransferReceived       int64            `json:"transferReceived" yaml:"transferReceived"`
-	TransferSent           int64            `json:"transferSent" yaml:"transferSent"`
-	Latency                time.Duration    `json:"latency" yaml:"latency"`
-	RosenpassEnabled       bool             `json:"quantumResistance" yaml:"quantumResistance"`
-	Networks               []string         `json:"networks" yaml:"networks"`
+	FQDN                   string            `json:"fqdn" yaml:"fqdn"`
+	IP                     string            `json:"netbirdIp" yaml:"netbirdIp"`
+	PubKey                 string            `json:"publicKey" yaml:"publicKey"`
+	Status                 string            `json:"status" yaml:"status"`
+	LastStatusUpdate       time.Time         `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
+	ConnType               string            `json:"connectionType" yaml:"connectionType"`
+	IceCandidateType       IceCandidateType  `json:"iceCandidateType" yaml:"iceCandidateType"`
+	IceCandidateEndpoint   IceCandidateType  `json:"iceCandidateEndpoint" yaml:"iceCandidateEndpoint"`
+	RelayAddress           string            `json:"relayAddress" yaml:"relayAddress"`
+	LastWireguardHandshake time.Time         `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
+	TransferReceived       int64             `json:"transferReceived" yaml:"transferReceived"`
+	TransferSent           int64             `json:"transferSent" yaml:"transferSent"`
+	Latency                time.Duration     `json:"latency" yaml:"latency"`
+	RosenpassEnabled       bool              `json:"quantumResistance" yaml:"quantumResistance"`
+	Networks               []string          `json:"networks" yaml:"networks"`
+	Quality                ConnQualityOutput `json:"connectionQuality" yaml:"connectionQuality"`
+}
+
+type ConnQualityOutput struct {
+	ProbesSent     int32         `json:"probesSent" yaml:"probesSent"`
+	ProbesReceived int32         `json:"probesReceived" yaml:"probesReceived"`
+	PacketLoss     float64       `json:"packetLoss" yaml:"packetLoss"`
+	Jitter         time.Duration `json:"jitter" yaml:"jitter"`
+	PathMTU        int32         `json:"pathMtu" yaml:"pathMtu"`
+	Answered       bool          `json:"answered" yaml:"answered"`
 }
 
 type PeersStateOutput struct {
@@ -50,15 +60,18 @@ type SignalStateOutput struct {
 }
 
 type ManagementStateOutput struct {
-	URL       string `json:"url" yaml:"url"`
-	Connected bool   `json:"connected" yaml:"connected"`
-	Error     string `json:"error" yaml:"error"`
+	URL                     string `json:"url" yaml:"url"`
+	Connected               bool   `json:"connected" yaml:"connected"`
+	Error                   string `json:"error" yaml:"error"`
+	NetworkMapStale         bool   `json:"networkMapStale" yaml:"networkMapStale"`
+	RelayCredentialsExpired bool   `json:"relayCredentialsExpired" yaml:"relayCredentialsExpired"`
 }
 
 type RelayStateOutputDetail struct {
-	URI       string `json:"uri" yaml:"uri"`
-	Available bool   `json:"available" yaml:"available"`
-	Error     string `json:"error" yaml:"error"`
+	URI       string        `json:"uri" yaml:"uri"`
+	Available bool          `json:"available" yaml:"available"`
+	Error     string        `json:"error" yaml:"error"`
+	RTT       time.Duration `json:"rtt" yaml:"rtt"`
 }
 
 type RelayStateOutput struct {
@@ -103,9 +116,11 @@ func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, status
 
 	managementState := pbFullStatus.GetManagementState()
 	managementOverview := ManagementStateOutput{
-		URL:       managementState.GetURL(),
-		Connected: managementState.GetConnected(),
-		Error:     managementState.Error,
+		URL:                     managementState.GetURL(),
+		Connected:               managementState.GetConnected(),
+		Error:                   managementState.Error,
+		NetworkMapStale:         managementState.GetNetworkMapStale(),
+		RelayCredentialsExpired: managementState.GetRelayCredentialsExpired(),
 	}
 
 	signalState := pbFullStatus.GetSignalState()
@@ -156,6 +171,7 @@ func mapRelays(relays []*proto.RelayState) RelayStateOutput {
 				URI:       relay.URI,
 				Available: available,
 				Error:     relay.GetError(),
+				RTT:       relay.GetRtt().AsDuration(),
 			},
 		)
 
@@ -248,6 +264,14 @@ func mapPeers(
 			Latency:                pbPeerState.GetLatency().AsDuration(),
 			RosenpassEnabled:       pbPeerState.GetRosenpassEnabled(),
 			Networks:               pbPeerState.GetNetworks(),
+			Quality: ConnQualityOutput{
+				ProbesSent:     pbPeerState.GetQuality().GetProbesSent(),
+				ProbesReceived: pbPeerState.GetQuality().GetProbesReceived(),
+				PacketLoss:     pbPeerState.GetQuality().GetPacketLoss(),
+				Jitter:         pbPeerState.GetQuality().GetJitter().AsDuration(),
+				PathMTU:        pbPeerState.GetQuality().GetPathMTU(),
+				Answered:       pbPeerState.GetQuality().GetAnswered(),
+			},
 		}
 
 		peersStateDetail = append(peersStateDetail, peerState)
@@ -302,6 +326,12 @@ func ParseGeneralSummary(overview OutputOverview, showURL bool, showRelays bool,
 			managementConnString = fmt.Sprintf("%s, reason: %s", managementConnString, overview.ManagementState.Error)
 		}
 	}
+	if overview.ManagementState.NetworkMapStale {
+		managementConnString = fmt.Sprintf("%s (using persisted network map)", managementConnString)
+	}
+	if overview.ManagementState.RelayCredentialsExpired {
+		managementConnString = fmt.Sprintf("%s (relay credentials expired, only direct connections)", managementConnString)
+	}
 
 	var signalConnString string
 	if overview.SignalState.Connected {
@@ -334,6 +364,9 @@ func ParseGeneralSummary(overview OutputOverview, showURL bool, showRelays bool,
 				available = "Unavailable"
 				reason = fmt.Sprintf(", reason: %s", relay.Error)
 			}
+			if relay.RTT > 0 {
+				reason += fmt.Sprintf(", RTT: %s", relay.RTT)
+			}
 			relaysString += fmt.Sprintf("\n  [%s] is %s%s", relay.URI, available, reason)
 		}
 	} else {
@@ -492,6 +525,18 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 			networks = strings.Join(peerState.Networks, ", ")
 		}
 
+		packetLoss := "-"
+		jitter := "-"
+		if peerState.Quality.Answered {
+			packetLoss = fmt.Sprintf("%.1f%% (%d/%d probes answered)", peerState.Quality.PacketLoss*100, peerState.Quality.ProbesReceived, peerState.Quality.ProbesSent)
+			jitter = peerState.Quality.Jitter.String()
+		}
+
+		pathMTU := "-"
+		if peerState.Quality.PathMTU > 0 {
+			pathMTU = fmt.Sprintf("%d", peerState.Quality.PathMTU)
+		}
+
 		peerString := fmt.Sprintf(
 			"\n %s:\n"+
 				"  NetBird IP: %s\n"+
@@ -507,7 +552,10 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 				"  Transfer status (received/sent) %s/%s\n"+
 				"  Quantum resistance: %s\n"+
 				"  Networks: %s\n"+
-				"  Latency: %s\n",
+				"  Latency: %s\n"+
+				"  Packet loss: %s\n"+
+				"  Jitter: %s\n"+
+				"  Path MTU: %s\n",
 			peerState.FQDN,
 			peerState.IP,
 			peerState.PubKey,
@@ -525,6 +573,9 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 			rosenpassEnabledStatus,
 			networks,
 			peerState.Latency.String(),
+			packetLoss,
+			jitter,
+			pathMTU,
 		)
 
 		peersString += peerString
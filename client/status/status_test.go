@@ -48,6 +48,14 @@ var resp = &proto.StatusResponse{
 					"10.1.0.0/24",
 				},
 				Latency: durationpb.New(time.Duration(10000000)),
+				Quality: &proto.ConnectionQuality{
+					ProbesSent:     20,
+					ProbesReceived: 19,
+					PacketLoss:     0.05,
+					Jitter:         durationpb.New(time.Duration(2000000)),
+					PathMTU:        1280,
+					Answered:       true,
+				},
 			},
 			{
 				IP:                         "192.168.178.102",
@@ -87,6 +95,12 @@ var resp = &proto.StatusResponse{
 				Available: false,
 				Error:     "context: deadline exceeded",
 			},
+			{
+				URI:       "rels://my-awesome-relay.com:443",
+				Available: true,
+				Error:     "",
+				Rtt:       durationpb.New(25 * time.Millisecond),
+			},
 		},
 		LocalPeerState: &proto.LocalPeerState{
 			IP:              "192.168.178.100/16",
@@ -150,6 +164,14 @@ var overview = OutputOverview{
 					"10.1.0.0/24",
 				},
 				Latency: time.Duration(10000000),
+				Quality: ConnQualityOutput{
+					ProbesSent:     20,
+					ProbesReceived: 19,
+					PacketLoss:     0.05,
+					Jitter:         time.Duration(2000000),
+					PathMTU:        1280,
+					Answered:       true,
+				},
 			},
 			{
 				IP:               "192.168.178.102",
@@ -187,8 +209,8 @@ var overview = OutputOverview{
 		Error:     "",
 	},
 	Relays: RelayStateOutput{
-		Total:     2,
-		Available: 1,
+		Total:     3,
+		Available: 2,
 		Details: []RelayStateOutputDetail{
 			{
 				URI:       "stun:my-awesome-stun.com:3478",
@@ -200,6 +222,12 @@ var overview = OutputOverview{
 				Available: false,
 				Error:     "context: deadline exceeded",
 			},
+			{
+				URI:       "rels://my-awesome-relay.com:443",
+				Available: true,
+				Error:     "",
+				RTT:       25 * time.Millisecond,
+			},
 		},
 	},
 	IP:              "192.168.178.100/16",
@@ -296,7 +324,15 @@ func TestParsingToJSON(t *testing.T) {
                 "quantumResistance": false,
                 "networks": [
                   "10.1.0.0/24"
-                ]
+                ],
+                "connectionQuality": {
+                  "probesSent": 20,
+                  "probesReceived": 19,
+                  "packetLoss": 0.05,
+                  "jitter": 2000000,
+                  "pathMtu": 1280,
+                  "answered": true
+                }
               },
               {
                 "fqdn": "peer-2.awesome-domain.com",
@@ -319,7 +355,15 @@ func TestParsingToJSON(t *testing.T) {
                 "transferSent": 1000,
 				"latency": 10000000,
                 "quantumResistance": false,
-                "networks": null
+                "networks": null,
+                "connectionQuality": {
+                  "probesSent": 0,
+                  "probesReceived": 0,
+                  "packetLoss": 0,
+                  "jitter": 0,
+                  "pathMtu": 0,
+                  "answered": false
+                }
               }
             ]
           },
@@ -328,7 +372,9 @@ func TestParsingToJSON(t *testing.T) {
           "management": {
             "url": "my-awesome-management.com:443",
             "connected": true,
-            "error": ""
+            "error": "",
+            "networkMapStale": false,
+            "relayCredentialsExpired": false
           },
           "signal": {
             "url": "my-awesome-signal.com:443",
@@ -336,18 +382,26 @@ func TestParsingToJSON(t *testing.T) {
             "error": ""
           },
           "relays": {
-            "total": 2,
-            "available": 1,
+            "total": 3,
+            "available": 2,
             "details": [
               {
                 "uri": "stun:my-awesome-stun.com:3478",
                 "available": true,
-                "error": ""
+                "error": "",
+                "rtt": 0
               },
               {
                 "uri": "turns:my-awesome-turn.com:443?transport=tcp",
                 "available": false,
-                "error": "context: deadline exceeded"
+                "error": "context: deadline exceeded",
+                "rtt": 0
+              },
+              {
+                "uri": "rels://my-awesome-relay.com:443",
+                "available": true,
+                "error": "",
+                "rtt": 25000000
               }
             ]
           },
@@ -421,6 +475,13 @@ func TestParsingToYAML(t *testing.T) {
           quantumResistance: false
           networks:
             - 10.1.0.0/24
+          connectionQuality:
+            probesSent: 20
+            probesReceived: 19
+            packetLoss: 0.05
+            jitter: 2ms
+            pathMtu: 1280
+            answered: true
         - fqdn: peer-2.awesome-domain.com
           netbirdIp: 192.168.178.102
           publicKey: Pubkey2
@@ -440,26 +501,41 @@ func TestParsingToYAML(t *testing.T) {
           latency: 10ms
           quantumResistance: false
           networks: []
+          connectionQuality:
+            probesSent: 0
+            probesReceived: 0
+            packetLoss: 0
+            jitter: 0s
+            pathMtu: 0
+            answered: false
 cliVersion: development
 daemonVersion: 0.14.1
 management:
     url: my-awesome-management.com:443
     connected: true
     error: ""
+    networkMapStale: false
+    relayCredentialsExpired: false
 signal:
     url: my-awesome-signal.com:443
     connected: true
     error: ""
 relays:
-    total: 2
-    available: 1
+    total: 3
+    available: 2
     details:
         - uri: stun:my-awesome-stun.com:3478
           available: true
           error: ""
+          rtt: 0s
         - uri: turns:my-awesome-turn.com:443?transport=tcp
           available: false
           error: 'context: deadline exceeded'
+          rtt: 0s
+        - uri: rels://my-awesome-relay.com:443
+          available: true
+          error: ""
+          rtt: 25ms
 netbirdIp: 192.168.178.100/16
 publicKey: Some-Pub-Key
 usesKernelInterface: true
@@ -515,6 +591,9 @@ func TestParsingToDetail(t *testing.T) {
   Quantum resistance: false
   Networks: 10.1.0.0/24
   Latency: 10ms
+  Packet loss: 5.0%% (19/20 probes answered)
+  Jitter: 2ms
+  Path MTU: 1280
 
  peer-2.awesome-domain.com:
   NetBird IP: 192.168.178.102
@@ -531,6 +610,9 @@ func TestParsingToDetail(t *testing.T) {
   Quantum resistance: false
   Networks: -
   Latency: 10ms
+  Packet loss: -
+  Jitter: -
+  Path MTU: -
 
 Events: No events recorded
 OS: %s/%s
@@ -541,6 +623,7 @@ Signal: Connected to my-awesome-signal.com:443
 Relays: 
   [stun:my-awesome-stun.com:3478] is Available
   [turns:my-awesome-turn.com:443?transport=tcp] is Unavailable, reason: context: deadline exceeded
+  [rels://my-awesome-relay.com:443] is Available, RTT: 25ms
 Nameservers: 
   [8.8.8.8:53] for [.] is Available
   [1.1.1.1:53, 2.2.2.2:53] for [example.com, example.net] is Unavailable, reason: timeout
@@ -564,7 +647,7 @@ Daemon version: 0.14.1
 CLI version: development
 Management: Connected
 Signal: Connected
-Relays: 1/2 Available
+Relays: 2/3 Available
 Nameservers: 1/2 Available
 FQDN: some-localhost.awesome-domain.com
 NetBird IP: 192.168.178.100/16
@@ -39,6 +39,18 @@ type File struct {
 	ProcessIsRunning bool
 }
 
+// Capability is a client feature advertised to the management server
+type Capability int32
+
+const (
+	// CapabilityIPv6Overlay indicates that the client can use an IPv6 overlay address
+	CapabilityIPv6Overlay Capability = 1
+	// CapabilityNetworkMapDelta indicates that the client applies network map deltas
+	CapabilityNetworkMapDelta Capability = 2
+	// CapabilityRosenpass indicates that the client refuses connections without Rosenpass when the network map requires it
+	CapabilityRosenpass Capability = 3
+)
+
 // Info is an object that contains machine information
 // Most of the code is taken from https://github.com/matishsiao/goInfo
 type Info struct {
@@ -66,6 +78,8 @@ type Info struct {
 	DisableServerRoutes bool
 	DisableDNS          bool
 	DisableFirewall     bool
+
+	Capabilities []Capability
 }
 
 func (i *Info) SetFlags(
@@ -42,6 +42,7 @@ func GetInfo(ctx context.Context) *Info {
 		SystemSerialNumber: serial(),
 		SystemProductName:  productModel(),
 		SystemManufacturer: productManufacturer(),
+		Capabilities:       []Capability{CapabilityNetworkMapDelta, CapabilityRosenpass},
 	}
 
 	return gio
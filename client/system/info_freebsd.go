@@ -50,6 +50,7 @@ func GetInfo(ctx context.Context) *Info {
 		UIVersion:      extractUserAgent(ctx),
 		KernelVersion:  osInfo[1],
 		Environment:    env,
+		Capabilities:   []Capability{CapabilityNetworkMapDelta, CapabilityRosenpass},
 	}
 }
 
@@ -15,6 +15,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/zcalusic/sysinfo"
 
+	"github.com/netbirdio/netbird/client/iface/netstack"
 	"github.com/netbirdio/netbird/version"
 )
 
@@ -69,11 +70,21 @@ func GetInfo(ctx context.Context) *Info {
 		SystemProductName:  si.SystemProductName,
 		SystemManufacturer: si.SystemManufacturer,
 		Environment:        si.Environment,
+		Capabilities:       capabilities(),
 	}
 
 	return gio
 }
 
+// capabilities returns the features supported by this client.
+// The IPv6 overlay is only assigned to the kernel interface, netstack mode keeps it IPv4 only.
+func capabilities() []Capability {
+	if netstack.IsEnabled() {
+		return []Capability{CapabilityNetworkMapDelta, CapabilityRosenpass}
+	}
+	return []Capability{CapabilityIPv6Overlay, CapabilityNetworkMapDelta, CapabilityRosenpass}
+}
+
 func _getInfo() string {
 	cmd := exec.Command("uname", "-srio")
 	cmd.Stdin = strings.NewReader("some")
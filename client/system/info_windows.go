@@ -60,6 +60,7 @@ func GetInfo(ctx context.Context) *Info {
 		SystemProductName:  si.SystemProductName,
 		SystemManufacturer: si.SystemManufacturer,
 		Environment:        si.Environment,
+		Capabilities:       []Capability{CapabilityNetworkMapDelta, CapabilityRosenpass},
 	}
 
 	systemHostname, _ := os.Hostname()
@@ -238,6 +238,11 @@ type serviceClient struct {
 
 	exitNodeMu     sync.Mutex
 	mExitNodeItems []menuHandler
+
+	mProfile      *systray.MenuItem
+	profileMu     sync.Mutex
+	profileNames  []string
+	mProfileItems []menuHandler
 }
 
 type menuHandler struct {
@@ -616,6 +621,10 @@ func (s *serviceClient) onTrayReady() {
 	s.mCreateDebugBundle = s.mSettings.AddSubMenuItem("Create Debug Bundle", debugBundleMenuDescr)
 	s.loadSettings()
 
+	s.profileMu.Lock()
+	s.mProfile = systray.AddMenuItem("Profile", profileMenuDescr)
+	s.profileMu.Unlock()
+
 	s.exitNodeMu.Lock()
 	s.mExitNode = systray.AddMenuItem("Exit Node", exitNodeMenuDescr)
 	s.mExitNode.Disable()
@@ -646,6 +655,7 @@ func (s *serviceClient) onTrayReady() {
 
 	// update exit node menu in case service is already connected
 	go s.updateExitNodes()
+	go s.updateProfiles()
 
 	s.update.SetOnUpdateListener(s.onUpdateAvailable)
 	go func() {
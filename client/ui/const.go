@@ -9,6 +9,7 @@ const (
 	advancedSettingsMenuDescr  = "Advanced settings of the application"
 	debugBundleMenuDescr       = "Create and open debug information bundle"
 	exitNodeMenuDescr          = "Select exit node for routing traffic"
+	profileMenuDescr           = "Switch between client profiles"
 	networksMenuDescr          = "Open the networks management window"
 	latestVersionMenuDescr     = "Download latest version"
 	quitMenuDescr              = "Quit the client app"
@@ -0,0 +1,136 @@
+//go:build !(linux && 386)
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/systray"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// updateProfiles refreshes the profile menu, the items are only recreated when profiles were added or removed
+func (s *serviceClient) updateProfiles() {
+	conn, err := s.getSrvClient(defaultFailTimeout)
+	if err != nil {
+		log.Errorf("get client: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, defaultFailTimeout)
+	defer cancel()
+
+	resp, err := conn.ListProfiles(ctx, &proto.ListProfilesRequest{})
+	if err != nil {
+		log.Errorf("list profiles: %v", err)
+		return
+	}
+
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	names := make([]string, 0, len(resp.GetProfiles()))
+	for _, p := range resp.GetProfiles() {
+		names = append(names, p.GetName())
+	}
+
+	if !slices.Equal(names, s.profileNames) {
+		s.recreateProfileMenu(resp.GetProfiles())
+		s.profileNames = names
+	}
+
+	for i, p := range resp.GetProfiles() {
+		item := s.mProfileItems[i]
+		if p.GetActive() {
+			item.Check()
+			s.mProfile.SetTitle(fmt.Sprintf("Profile: %s", p.GetName()))
+		} else {
+			item.Uncheck()
+		}
+	}
+}
+
+func (s *serviceClient) recreateProfileMenu(profiles []*proto.Profile) {
+	for _, item := range s.mProfileItems {
+		item.cancel()
+		item.Remove()
+	}
+	s.mProfileItems = nil
+
+	if runtime.GOOS == "linux" || runtime.GOOS == "freebsd" {
+		s.mProfile.Remove()
+		s.mProfile = systray.AddMenuItem("Profile", profileMenuDescr)
+	}
+
+	for _, p := range profiles {
+		tooltip := fmt.Sprintf("Switch to profile %s", p.GetName())
+		if p.GetManagementUrl() != "" {
+			tooltip = fmt.Sprintf("Switch to profile %s (%s)", p.GetName(), p.GetManagementUrl())
+		}
+
+		menuItem := s.mProfile.AddSubMenuItemCheckbox(p.GetName(), tooltip, p.GetActive())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s.mProfileItems = append(s.mProfileItems, menuHandler{
+			MenuItem: menuItem,
+			cancel:   cancel,
+		})
+		go s.handleProfileClick(ctx, p.GetName(), menuItem)
+	}
+}
+
+func (s *serviceClient) handleProfileClick(ctx context.Context, name string, item *systray.MenuItem) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-item.ClickedCh:
+			if !ok {
+				return
+			}
+			if err := s.switchProfile(name); err != nil {
+				log.Errorf("failed to switch to profile %s: %v", name, err)
+				s.app.SendNotification(fyne.NewNotification("Error", fmt.Sprintf("Failed to switch to profile %s", name)))
+			}
+			s.updateProfiles()
+		}
+	}
+}
+
+// switchProfile activates the profile and reloads the settings of the new profile
+func (s *serviceClient) switchProfile(name string) error {
+	conn, err := s.getSrvClient(defaultFailTimeout)
+	if err != nil {
+		return fmt.Errorf("get client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, defaultFailTimeout)
+	defer cancel()
+
+	resp, err := conn.SwitchProfile(ctx, &proto.SwitchProfileRequest{Name: name})
+	if err != nil {
+		return fmt.Errorf("switch profile: %v", err)
+	}
+
+	log.Infof("switched to profile %s", name)
+
+	s.getSrvConfig()
+	s.loadSettings()
+	go s.updateExitNodes()
+
+	if resp.GetStatus() == string(internal.StatusNeedsLogin) {
+		s.app.SendNotification(fyne.NewNotification(
+			fmt.Sprintf("Switched to profile %s", name),
+			"The profile isn't logged in, click Connect to log in",
+		))
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"io"
 	"net"
 	"os"
 	"sync"
@@ -535,3 +536,54 @@ func Test_GetPKCEAuthorizationFlow(t *testing.T) {
 	assert.Equal(t, expectedFlowInfo.ProviderConfig.ClientID, flowInfo.ProviderConfig.ClientID, "provider configured client ID should match")
 	assert.Equal(t, expectedFlowInfo.ProviderConfig.ClientSecret, flowInfo.ProviderConfig.ClientSecret, "provider configured client secret should match")
 }
+
+type testSyncStream struct {
+	grpc.ClientStream
+	messages []*mgmtProto.EncryptedMessage
+}
+
+func (s *testSyncStream) Recv() (*mgmtProto.EncryptedMessage, error) {
+	if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+func TestClient_ReceiveEventsTracksNetworkMapSerial(t *testing.T) {
+	clientKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+	serverKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+
+	client := &GrpcClient{key: clientKey}
+
+	stream := func(responses ...*mgmtProto.SyncResponse) *testSyncStream {
+		s := &testSyncStream{}
+		for _, resp := range responses {
+			body, err := encryption.EncryptMessage(clientKey.PublicKey(), serverKey, resp)
+			require.NoError(t, err)
+			s.messages = append(s.messages, &mgmtProto.EncryptedMessage{WgPubKey: serverKey.PublicKey().String(), Body: body})
+		}
+		return s
+	}
+
+	handled := func(*mgmtProto.SyncResponse) error { return nil }
+
+	err = client.receiveEvents(stream(
+		&mgmtProto.SyncResponse{NetworkMap: &mgmtProto.NetworkMap{Serial: 3}},
+		&mgmtProto.SyncResponse{NetworkMapDelta: &mgmtProto.NetworkMapDelta{BaseSerial: 3, Serial: 5}},
+		&mgmtProto.SyncResponse{NetbirdConfig: &mgmtProto.NetbirdConfig{}},
+	), serverKey.PublicKey(), handled)
+	require.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, uint64(5), client.networkMapSerial.Load(), "the serial of the last handled network map should be acknowledged")
+
+	err = client.receiveEvents(stream(
+		&mgmtProto.SyncResponse{NetworkMapDelta: &mgmtProto.NetworkMapDelta{BaseSerial: 4, Serial: 6}},
+	), serverKey.PublicKey(), func(*mgmtProto.SyncResponse) error {
+		return mgmtProto.ErrNetworkMapDeltaMismatch
+	})
+	require.ErrorIs(t, err, mgmtProto.ErrNetworkMapDeltaMismatch)
+	assert.Zero(t, client.networkMapSerial.Load(), "a failed update should request a full network map")
+}
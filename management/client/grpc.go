@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -44,6 +45,8 @@ type GrpcClient struct {
 	conn                  *grpc.ClientConn
 	connStateCallback     ConnStateNotifier
 	connStateCallbackLock sync.RWMutex
+	// networkMapSerial is the serial of the last network map handled successfully, management sends deltas against it
+	networkMapSerial atomic.Uint64
 }
 
 // NewClient creates a new client to Management service
@@ -66,15 +69,28 @@ func NewClient(ctx context.Context, addr string, ourPrivateKey wgtypes.Key, tlsE
 		return nil, err
 	}
 
-	realClient := proto.NewManagementServiceClient(conn)
+	return newGrpcClient(ctx, conn, ourPrivateKey), nil
+}
+
+// NewLazyClient creates a new client to Management service without waiting for the service to be reachable.
+// Sync keeps retrying until the connection is established.
+func NewLazyClient(ctx context.Context, addr string, ourPrivateKey wgtypes.Key, tlsEnabled bool) (*GrpcClient, error) {
+	conn, err := nbgrpc.CreateLazyConnection(addr, tlsEnabled)
+	if err != nil {
+		return nil, fmt.Errorf("create connection to Management Service: %w", err)
+	}
+
+	return newGrpcClient(ctx, conn, ourPrivateKey), nil
+}
 
+func newGrpcClient(ctx context.Context, conn *grpc.ClientConn, ourPrivateKey wgtypes.Key) *GrpcClient {
 	return &GrpcClient{
 		key:                   ourPrivateKey,
-		realClient:            realClient,
+		realClient:            proto.NewManagementServiceClient(conn),
 		ctx:                   ctx,
 		conn:                  conn,
 		connStateCallbackLock: sync.RWMutex{},
-	}, nil
+	}
 }
 
 // Close closes connection to the Management Service
@@ -144,7 +160,7 @@ func (c *GrpcClient) handleStream(ctx context.Context, serverPubKey wgtypes.Key,
 	ctx, cancelStream := context.WithCancel(ctx)
 	defer cancelStream()
 
-	stream, err := c.connectToStream(ctx, serverPubKey, sysInfo)
+	stream, err := c.connectToStream(ctx, serverPubKey, sysInfo, c.networkMapSerial.Load())
 	if err != nil {
 		log.Debugf("failed to open Management Service stream: %s", err)
 		if s, ok := gstatus.FromError(err); ok && s.Code() == codes.PermissionDenied {
@@ -186,7 +202,7 @@ func (c *GrpcClient) GetNetworkMap(sysInfo *system.Info) (*proto.NetworkMap, err
 
 	ctx, cancelStream := context.WithCancel(c.ctx)
 	defer cancelStream()
-	stream, err := c.connectToStream(ctx, *serverPubKey, sysInfo)
+	stream, err := c.connectToStream(ctx, *serverPubKey, sysInfo, 0)
 	if err != nil {
 		log.Debugf("failed to open Management Service stream: %s", err)
 		return nil, err
@@ -219,8 +235,10 @@ func (c *GrpcClient) GetNetworkMap(sysInfo *system.Info) (*proto.NetworkMap, err
 	return decryptedResp.GetNetworkMap(), nil
 }
 
-func (c *GrpcClient) connectToStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info) (proto.ManagementService_SyncClient, error) {
-	req := &proto.SyncRequest{Meta: infoToMetaData(sysInfo)}
+// connectToStream opens the Sync stream. networkMapSerial acknowledges the network map the client holds,
+// management answers with a full network map if it doesn't match the last one it sent.
+func (c *GrpcClient) connectToStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info, networkMapSerial uint64) (proto.ManagementService_SyncClient, error) {
+	req := &proto.SyncRequest{Meta: infoToMetaData(sysInfo), NetworkMapSerial: networkMapSerial}
 
 	myPrivateKey := c.key
 	myPublicKey := myPrivateKey.PublicKey()
@@ -260,9 +278,17 @@ func (c *GrpcClient) receiveEvents(stream proto.ManagementService_SyncClient, se
 
 		err = msgHandler(decryptedResp)
 		if err != nil {
+			// the handler may have dropped its network map, ask for a full one
+			c.networkMapSerial.Store(0)
 			log.Errorf("failed handling an update message received from Management Service: %v", err.Error())
 			return err
 		}
+
+		if nm := decryptedResp.GetNetworkMap(); nm != nil {
+			c.networkMapSerial.Store(nm.GetSerial())
+		} else if delta := decryptedResp.GetNetworkMapDelta(); delta != nil {
+			c.networkMapSerial.Store(delta.GetSerial())
+		}
 	}
 }
 
@@ -521,6 +547,11 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 		})
 	}
 
+	capabilities := make([]proto.PeerCapability, 0, len(info.Capabilities))
+	for _, capability := range info.Capabilities {
+		capabilities = append(capabilities, proto.PeerCapability(capability))
+	}
+
 	return &proto.PeerSystemMeta{
 		Hostname:         info.Hostname,
 		GoOS:             info.GoOS,
@@ -551,5 +582,6 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 			DisableDNS:          info.DisableDNS,
 			DisableFirewall:     info.DisableFirewall,
 		},
+		Capabilities: capabilities,
 	}
 }
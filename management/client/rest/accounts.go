@@ -52,3 +52,66 @@ func (a *AccountsAPI) Delete(ctx context.Context, accountID string) error {
 
 	return nil
 }
+
+// ListChildren list the accounts managed by the admins of the account
+func (a *AccountsAPI) ListChildren(ctx context.Context) ([]api.ChildAccount, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/accounts/children", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[[]api.ChildAccount](resp)
+	return ret, err
+}
+
+// SetParent ask to link the account to a parent account, an empty parent account ID unlinks it
+func (a *AccountsAPI) SetParent(ctx context.Context, accountID string, request api.PutApiAccountsAccountIdParentJSONRequestBody) error {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := a.c.newRequest(ctx, "PUT", "/api/accounts/"+accountID+"/parent", bytes.NewReader(requestBytes))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// AcceptChild link an account that asked to be managed by the account
+func (a *AccountsAPI) AcceptChild(ctx context.Context, childAccountID string) error {
+	resp, err := a.c.newRequest(ctx, "POST", "/api/accounts/children/"+childAccountID+"/accept", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// RemoveChild unlink a child account from the account or decline a pending one
+func (a *AccountsAPI) RemoveChild(ctx context.Context, childAccountID string) error {
+	resp, err := a.c.newRequest(ctx, "DELETE", "/api/accounts/children/"+childAccountID, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// PushTemplates copy policies, posture checks and nameserver groups to the child accounts
+func (a *AccountsAPI) PushTemplates(ctx context.Context, request api.PostApiAccountsChildrenTemplatesJSONRequestBody) (*api.BulkOperationResponse, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/accounts/children/templates", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.BulkOperationResponse](resp)
+	return &ret, err
+}
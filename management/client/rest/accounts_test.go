@@ -135,6 +135,89 @@ func TestAccounts_Delete_Err(t *testing.T) {
 	})
 }
 
+func TestAccounts_ListChildren_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/accounts/children", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal([]api.ChildAccount{{Id: "Child", Domain: "child.com"}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Accounts.ListChildren(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, ret, 1)
+		assert.Equal(t, "Child", ret[0].Id)
+	})
+}
+
+func TestAccounts_SetParent_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/accounts/Test/parent", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "PUT", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PutApiAccountsAccountIdParentJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "Parent", req.ParentAccountId)
+			w.WriteHeader(200)
+		})
+		err := c.Accounts.SetParent(context.Background(), "Test", api.PutApiAccountsAccountIdParentJSONRequestBody{
+			ParentAccountId: "Parent",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestAccounts_AcceptChild_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/accounts/children/Child/accept", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			w.WriteHeader(200)
+		})
+		err := c.Accounts.AcceptChild(context.Background(), "Child")
+		require.NoError(t, err)
+	})
+}
+
+func TestAccounts_RemoveChild_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/accounts/children/Child", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "Not found", Code: 404})
+			w.WriteHeader(404)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		err := c.Accounts.RemoveChild(context.Background(), "Child")
+		assert.Error(t, err)
+		assert.Equal(t, "Not found", err.Error())
+	})
+}
+
+func TestAccounts_PushTemplates_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/accounts/children/templates", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiAccountsChildrenTemplatesJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Policy"}, *req.PolicyIds)
+			retBytes, _ := json.Marshal(api.BulkOperationResponse{Results: []api.BulkOperationItemResult{
+				{Id: "Child", Status: api.BulkOperationItemResultStatusSuccess},
+			}})
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Accounts.PushTemplates(context.Background(), api.PostApiAccountsChildrenTemplatesJSONRequestBody{
+			PolicyIds: &[]string{"Policy"},
+		})
+		require.NoError(t, err)
+		require.Len(t, ret.Results, 1)
+		assert.Equal(t, "Child", ret.Results[0].Id)
+	})
+}
+
 func TestAccounts_Integration_List(t *testing.T) {
 	withBlackBoxServer(t, func(c *rest.Client) {
 		accounts, err := c.Accounts.List(context.Background())
@@ -4,8 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/netbirdio/netbird/management/server/http/util"
 )
@@ -35,6 +40,9 @@ type Client struct {
 	// see more: https://docs.netbird.io/api/resources/setup-keys
 	SetupKeys *SetupKeysAPI
 
+	// Inventory NetBird device inventory APIs
+	Inventory *InventoryAPI
+
 	// Groups NetBird groups APIs
 	// see more: https://docs.netbird.io/api/resources/groups
 	Groups *GroupsAPI
@@ -94,6 +102,7 @@ func (c *Client) initialize() {
 	c.Tokens = &TokensAPI{c}
 	c.Peers = &PeersAPI{c}
 	c.SetupKeys = &SetupKeysAPI{c}
+	c.Inventory = &InventoryAPI{c}
 	c.Groups = &GroupsAPI{c}
 	c.Policies = &PoliciesAPI{c}
 	c.PostureChecks = &PostureChecksAPI{c}
@@ -145,3 +154,33 @@ func parseResponse[T any](resp *http.Response) (T, error) {
 
 	return ret, err
 }
+
+// encodeQuery encodes the set fields of generated API params into a query string, using their form tags
+func encodeQuery(params any) string {
+	values := url.Values{}
+
+	v := reflect.ValueOf(params)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("form"), ",")
+		if name == "" {
+			continue
+		}
+
+		switch value := field.Elem().Interface().(type) {
+		case time.Time:
+			values.Set(name, value.Format(time.RFC3339))
+		default:
+			values.Set(name, fmt.Sprint(value))
+		}
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
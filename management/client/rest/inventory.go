@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/netbirdio/netbird/management/server/http/api"
+)
+
+// InventoryAPI APIs for the device inventory, do not use directly
+type InventoryAPI struct {
+	c *Client
+}
+
+// ListDevices list all devices of the inventory
+func (a *InventoryAPI) ListDevices(ctx context.Context) ([]api.InventoryDevice, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/inventory/devices", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[[]api.InventoryDevice](resp)
+	return ret, err
+}
+
+// GetDevice get inventory device info
+func (a *InventoryAPI) GetDevice(ctx context.Context, deviceID string) (*api.InventoryDevice, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/inventory/devices/"+deviceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.InventoryDevice](resp)
+	return &ret, err
+}
+
+// CreateDevice add a device to the inventory
+func (a *InventoryAPI) CreateDevice(ctx context.Context, request api.PostApiInventoryDevicesJSONRequestBody) (*api.InventoryDevice, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/inventory/devices", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.InventoryDevice](resp)
+	return &ret, err
+}
+
+// UpdateDevice update inventory device info
+func (a *InventoryAPI) UpdateDevice(ctx context.Context, deviceID string, request api.PutApiInventoryDevicesDeviceIdJSONRequestBody) (*api.InventoryDevice, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "PUT", "/api/inventory/devices/"+deviceID, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.InventoryDevice](resp)
+	return &ret, err
+}
+
+// DeleteDevice delete a device from the inventory
+func (a *InventoryAPI) DeleteDevice(ctx context.Context, deviceID string) error {
+	resp, err := a.c.newRequest(ctx, "DELETE", "/api/inventory/devices/"+deviceID, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ImportDevices add a list of devices to the inventory, devices already in the inventory are matched by serial number and updated
+func (a *InventoryAPI) ImportDevices(ctx context.Context, request api.PostApiInventoryDevicesImportJSONRequestBody) (*api.InventoryImportResponse, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/inventory/devices/import", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.InventoryImportResponse](resp)
+	return &ret, err
+}
@@ -0,0 +1,168 @@
+//go:build integration
+// +build integration
+
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/client/rest"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
+)
+
+var testInventoryDevice = api.InventoryDevice{
+	Id:           "Test",
+	SerialNumber: "SN-1",
+	Manufacturer: ptr("Lenovo"),
+}
+
+func TestInventory_ListDevices_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal([]api.InventoryDevice{testInventoryDevice})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Inventory.ListDevices(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, ret, 1)
+		assert.Equal(t, testInventoryDevice, ret[0])
+	})
+}
+
+func TestInventory_ListDevices_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Inventory.ListDevices(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Empty(t, ret)
+	})
+}
+
+func TestInventory_GetDevice_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices/Test", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(testInventoryDevice)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Inventory.GetDevice(context.Background(), "Test")
+		require.NoError(t, err)
+		assert.Equal(t, testInventoryDevice, *ret)
+	})
+}
+
+func TestInventory_CreateDevice_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiInventoryDevicesJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "SN-1", req.SerialNumber)
+			retBytes, _ := json.Marshal(testInventoryDevice)
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Inventory.CreateDevice(context.Background(), api.PostApiInventoryDevicesJSONRequestBody{
+			SerialNumber: "SN-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testInventoryDevice, *ret)
+	})
+}
+
+func TestInventory_UpdateDevice_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices/Test", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "PUT", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PutApiInventoryDevicesDeviceIdJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "Lenovo", *req.Manufacturer)
+			retBytes, _ := json.Marshal(testInventoryDevice)
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Inventory.UpdateDevice(context.Background(), "Test", api.PutApiInventoryDevicesDeviceIdJSONRequestBody{
+			SerialNumber: "SN-1",
+			Manufacturer: ptr("Lenovo"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testInventoryDevice, *ret)
+	})
+}
+
+func TestInventory_DeleteDevice_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices/Test", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "DELETE", r.Method)
+			w.WriteHeader(200)
+		})
+		err := c.Inventory.DeleteDevice(context.Background(), "Test")
+		require.NoError(t, err)
+	})
+}
+
+func TestInventory_DeleteDevice_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices/Test", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "Not found", Code: 404})
+			w.WriteHeader(404)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		err := c.Inventory.DeleteDevice(context.Background(), "Test")
+		assert.Error(t, err)
+		assert.Equal(t, "Not found", err.Error())
+	})
+}
+
+func TestInventory_ImportDevices_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/inventory/devices/import", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiInventoryDevicesImportJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			require.Len(t, req.Devices, 2)
+			retBytes, _ := json.Marshal(api.InventoryImportResponse{
+				Created: 1,
+				Failed:  1,
+				Results: []api.BulkOperationItemResult{
+					{Id: "SN-1", Status: api.BulkOperationItemResultStatusSuccess},
+					{Id: "", Status: api.BulkOperationItemResultStatusFailed, Error: ptr("device serial number can't be empty")},
+				},
+			})
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Inventory.ImportDevices(context.Background(), api.PostApiInventoryDevicesImportJSONRequestBody{
+			Devices: []api.InventoryDeviceRequest{{SerialNumber: "SN-1"}, {SerialNumber: ""}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, ret.Created)
+		assert.Equal(t, 1, ret.Failed)
+		require.Len(t, ret.Results, 2)
+	})
+}
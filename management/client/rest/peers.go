@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 
 	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
 )
 
 // PeersAPI APIs for peers, do not use directly
@@ -25,6 +26,19 @@ func (a *PeersAPI) List(ctx context.Context) ([]api.Peer, error) {
 	return ret, err
 }
 
+// ListPage list the peers matching the filters, sorted and paginated by the params.
+// Returns the cursor of the next page, which is empty on the last page.
+// See more: https://docs.netbird.io/api/resources/peers#list-all-peers
+func (a *PeersAPI) ListPage(ctx context.Context, params api.GetApiPeersParams) ([]api.Peer, string, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/peers"+encodeQuery(params), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[[]api.Peer](resp)
+	return ret, resp.Header.Get(util.NextCursorHeader), err
+}
+
 // Get retrieve a peer
 // See more: https://docs.netbird.io/api/resources/peers#retrieve-a-peer
 func (a *PeersAPI) Get(ctx context.Context, peerID string) (*api.Peer, error) {
@@ -53,6 +67,21 @@ func (a *PeersAPI) Update(ctx context.Context, peerID string, request api.PutApi
 	return &ret, err
 }
 
+// Bulk apply a single action to a list of peers, the outcome is reported per object
+func (a *PeersAPI) Bulk(ctx context.Context, request api.PostApiPeersBulkJSONRequestBody) (*api.BulkOperationResponse, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/peers/bulk", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.BulkOperationResponse](resp)
+	return &ret, err
+}
+
 // Delete delete a peer
 // See more: https://docs.netbird.io/api/resources/peers#delete-a-peer
 func (a *PeersAPI) Delete(ctx context.Context, peerID string) error {
@@ -76,3 +105,103 @@ func (a *PeersAPI) ListAccessiblePeers(ctx context.Context, peerID string) ([]ap
 	ret, err := parseResponse[[]api.Peer](resp)
 	return ret, err
 }
+
+// ListApprovalRequests list the approval requests of peers that required approval
+// See more: https://docs.netbird.io/api/resources/peers#list-all-peer-approval-requests
+func (a *PeersAPI) ListApprovalRequests(ctx context.Context, params api.GetApiPeersApprovalRequestsParams) ([]api.PeerApprovalRequest, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/peers/approval-requests"+encodeQuery(params), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[[]api.PeerApprovalRequest](resp)
+	return ret, err
+}
+
+// Approve approve a peer waiting for approval
+// See more: https://docs.netbird.io/api/resources/peers#approve-a-peer
+func (a *PeersAPI) Approve(ctx context.Context, peerID string, request api.PostApiPeersPeerIdApproveJSONRequestBody) (*api.Peer, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/peers/"+peerID+"/approve", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.Peer](resp)
+	return &ret, err
+}
+
+// Reject reject and delete a peer waiting for approval
+// See more: https://docs.netbird.io/api/resources/peers#reject-a-peer
+func (a *PeersAPI) Reject(ctx context.Context, peerID string, request api.PostApiPeersPeerIdRejectJSONRequestBody) error {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/peers/"+peerID+"/reject", bytes.NewReader(requestBytes))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ListApprovalRules list the rules approving new peers automatically
+// See more: https://docs.netbird.io/api/resources/peers#list-all-peer-approval-rules
+func (a *PeersAPI) ListApprovalRules(ctx context.Context) ([]api.PeerApprovalRule, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/peers/approval-rules", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[[]api.PeerApprovalRule](resp)
+	return ret, err
+}
+
+// CreateApprovalRule create a rule approving new peers automatically
+// See more: https://docs.netbird.io/api/resources/peers#create-a-peer-approval-rule
+func (a *PeersAPI) CreateApprovalRule(ctx context.Context, request api.PostApiPeersApprovalRulesJSONRequestBody) (*api.PeerApprovalRule, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/peers/approval-rules", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.PeerApprovalRule](resp)
+	return &ret, err
+}
+
+// UpdateApprovalRule update a peer approval rule
+// See more: https://docs.netbird.io/api/resources/peers#update-a-peer-approval-rule
+func (a *PeersAPI) UpdateApprovalRule(ctx context.Context, ruleID string, request api.PutApiPeersApprovalRulesRuleIdJSONRequestBody) (*api.PeerApprovalRule, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "PUT", "/api/peers/approval-rules/"+ruleID, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.PeerApprovalRule](resp)
+	return &ret, err
+}
+
+// DeleteApprovalRule delete a peer approval rule
+// See more: https://docs.netbird.io/api/resources/peers#delete-a-peer-approval-rule
+func (a *PeersAPI) DeleteApprovalRule(ctx context.Context, ruleID string) error {
+	resp, err := a.c.newRequest(ctx, "DELETE", "/api/peers/approval-rules/"+ruleID, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -57,6 +58,57 @@ func TestPeers_List_Err(t *testing.T) {
 	})
 }
 
+func TestPeers_ListPage_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "web", r.URL.Query().Get("name"))
+			assert.Equal(t, "true", r.URL.Query().Get("connected"))
+			assert.Equal(t, "2025-01-01T00:00:00Z", r.URL.Query().Get("last_seen_after"))
+			assert.Equal(t, "last_seen", r.URL.Query().Get("sort_by"))
+			assert.Equal(t, "desc", r.URL.Query().Get("sort_order"))
+			assert.Equal(t, "10", r.URL.Query().Get("limit"))
+			assert.Equal(t, "abc", r.URL.Query().Get("cursor"))
+			assert.False(t, r.URL.Query().Has("ip"))
+
+			w.Header().Set(util.NextCursorHeader, "def")
+			retBytes, _ := json.Marshal([]api.Peer{testPeer})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		sortBy := api.GetApiPeersParamsSortByLastSeen
+		sortOrder := api.SortOrderDesc
+		ret, next, err := c.Peers.ListPage(context.Background(), api.GetApiPeersParams{
+			Name:          ptr("web"),
+			Connected:     ptr(true),
+			LastSeenAfter: ptr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			SortBy:        &sortBy,
+			SortOrder:     &sortOrder,
+			Limit:         ptr(10),
+			Cursor:        ptr("abc"),
+		})
+		require.NoError(t, err)
+		assert.Len(t, ret, 1)
+		assert.Equal(t, testPeer, ret[0])
+		assert.Equal(t, "def", next)
+	})
+}
+
+func TestPeers_ListPage_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, next, err := c.Peers.ListPage(context.Background(), api.GetApiPeersParams{Limit: ptr(10)})
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Empty(t, ret)
+		assert.Empty(t, next)
+	})
+}
+
 func TestPeers_Get_200(t *testing.T) {
 	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
 		mux.HandleFunc("/api/peers/Test", func(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +159,53 @@ func TestPeers_Update_200(t *testing.T) {
 	})
 }
 
+func TestPeers_Bulk_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/bulk", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiPeersBulkJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, api.PeerBulkRequestActionAssignGroups, req.Action)
+			assert.Equal(t, []string{"Test"}, req.PeerIds)
+			assert.Equal(t, []string{"group"}, *req.GroupIds)
+			retBytes, _ := json.Marshal(api.BulkOperationResponse{Results: []api.BulkOperationItemResult{
+				{Id: "Test", Status: api.BulkOperationItemResultStatusSuccess},
+			}})
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.Bulk(context.Background(), api.PostApiPeersBulkJSONRequestBody{
+			Action:   api.PeerBulkRequestActionAssignGroups,
+			PeerIds:  []string{"Test"},
+			GroupIds: &[]string{"group"},
+		})
+		require.NoError(t, err)
+		require.Len(t, ret.Results, 1)
+		assert.Equal(t, api.BulkOperationItemResultStatusSuccess, ret.Results[0].Status)
+	})
+}
+
+func TestPeers_Bulk_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/bulk", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.Bulk(context.Background(), api.PostApiPeersBulkJSONRequestBody{
+			Action:  api.PeerBulkRequestActionDelete,
+			PeerIds: []string{"Test"},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Nil(t, ret)
+	})
+}
+
 func TestPeers_Update_Err(t *testing.T) {
 	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
 		mux.HandleFunc("/api/peers/Test", func(w http.ResponseWriter, r *http.Request) {
@@ -178,6 +277,94 @@ func TestPeers_ListAccessiblePeers_Err(t *testing.T) {
 	})
 }
 
+func TestPeers_ListApprovalRequests_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/approval-requests", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "pending", r.URL.Query().Get("status"))
+			retBytes, _ := json.Marshal([]api.PeerApprovalRequest{{PeerId: "Test", Status: api.PeerApprovalRequestStatusPending}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		status := api.GetApiPeersApprovalRequestsParamsStatusPending
+		ret, err := c.Peers.ListApprovalRequests(context.Background(), api.GetApiPeersApprovalRequestsParams{Status: &status})
+		require.NoError(t, err)
+		require.Len(t, ret, 1)
+		assert.Equal(t, "Test", ret[0].PeerId)
+	})
+}
+
+func TestPeers_Approve_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/Test/approve", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiPeersPeerIdApproveJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "known device", *req.Reason)
+			retBytes, _ := json.Marshal(testPeer)
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.Approve(context.Background(), "Test", api.PostApiPeersPeerIdApproveJSONRequestBody{
+			Reason: ptr("known device"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testPeer, *ret)
+	})
+}
+
+func TestPeers_Reject_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/Test/reject", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 412})
+			w.WriteHeader(412)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		err := c.Peers.Reject(context.Background(), "Test", api.PostApiPeersPeerIdRejectJSONRequestBody{})
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+	})
+}
+
+func TestPeers_CreateApprovalRule_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/approval-rules", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiPeersApprovalRulesJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "servers", req.Name)
+			retBytes, _ := json.Marshal(api.PeerApprovalRule{Id: "Test", Name: req.Name, Enabled: req.Enabled, SetupKeyIds: req.SetupKeyIds})
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.CreateApprovalRule(context.Background(), api.PostApiPeersApprovalRulesJSONRequestBody{
+			Name:        "servers",
+			Enabled:     true,
+			SetupKeyIds: &[]string{"key"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Test", ret.Id)
+		assert.Equal(t, []string{"key"}, *ret.SetupKeyIds)
+	})
+}
+
+func TestPeers_DeleteApprovalRule_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/approval-rules/Test", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "DELETE", r.Method)
+			w.WriteHeader(200)
+		})
+		err := c.Peers.DeleteApprovalRule(context.Background(), "Test")
+		require.NoError(t, err)
+	})
+}
+
 func TestPeers_Integration(t *testing.T) {
 	withBlackBoxServer(t, func(c *rest.Client) {
 		peers, err := c.Peers.List(context.Background())
@@ -69,6 +69,22 @@ func (a *PoliciesAPI) Update(ctx context.Context, policyID string, request api.P
 	return &ret, err
 }
 
+// Simulate evaluates whether a peer can reach a destination and explains the verdict
+// See more: https://docs.netbird.io/api/resources/policies#simulate-traffic-against-policies
+func (a *PoliciesAPI) Simulate(ctx context.Context, request api.PostApiPoliciesSimulateJSONRequestBody) (*api.PolicySimulationResult, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/policies/simulate", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.PolicySimulationResult](resp)
+	return &ret, err
+}
+
 // Delete delete policy
 // See more: https://docs.netbird.io/api/resources/policies#delete-a-policy
 func (a *PoliciesAPI) Delete(ctx context.Context, policyID string) error {
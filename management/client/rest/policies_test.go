@@ -186,6 +186,59 @@ func TestPolicies_Delete_Err(t *testing.T) {
 	})
 }
 
+func TestPolicies_Simulate_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		result := api.PolicySimulationResult{
+			Allowed: true,
+			MatchedRules: []api.PolicySimulationRuleMatch{
+				{PolicyId: "Test", RuleId: "Test", Action: api.PolicySimulationRuleMatchActionAccept},
+			},
+			FailedPostureChecks: []string{},
+			RoutingPeers:        []string{},
+			Reasons:             []string{},
+		}
+		mux.HandleFunc("/api/policies/simulate", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiPoliciesSimulateJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "src", req.SourcePeerId)
+			assert.Equal(t, 5432, *req.Port)
+			retBytes, _ := json.Marshal(result)
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Policies.Simulate(context.Background(), api.PostApiPoliciesSimulateJSONRequestBody{
+			SourcePeerId:      "src",
+			DestinationPeerId: ptr("dst"),
+			Protocol:          api.PolicySimulationRequestProtocolTcp,
+			Port:              ptr(5432),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, result, *ret)
+	})
+}
+
+func TestPolicies_Simulate_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/policies/simulate", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Policies.Simulate(context.Background(), api.PostApiPoliciesSimulateJSONRequestBody{
+			SourcePeerId: "src",
+			Protocol:     api.PolicySimulationRequestProtocolAll,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Nil(t, ret)
+	})
+}
+
 func TestPolicies_Integration(t *testing.T) {
 	withBlackBoxServer(t, func(c *rest.Client) {
 		policies, err := c.Policies.List(context.Background())
@@ -69,6 +69,21 @@ func (a *SetupKeysAPI) Update(ctx context.Context, setupKeyID string, request ap
 	return &ret, err
 }
 
+// Bulk revoke or delete a list of setup keys, the outcome is reported per object
+func (a *SetupKeysAPI) Bulk(ctx context.Context, request api.PostApiSetupKeysBulkJSONRequestBody) (*api.BulkOperationResponse, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.newRequest(ctx, "POST", "/api/setup-keys/bulk", bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[api.BulkOperationResponse](resp)
+	return &ret, err
+}
+
 // Delete delete setup key
 // See more: https://docs.netbird.io/api/resources/setup-keys#delete-a-setup-key
 func (a *SetupKeysAPI) Delete(ctx context.Context, setupKeyID string) error {
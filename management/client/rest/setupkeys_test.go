@@ -153,6 +153,34 @@ func TestSetupKeys_Update_200(t *testing.T) {
 	})
 }
 
+func TestSetupKeys_Bulk_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/setup-keys/bulk", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiSetupKeysBulkJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, api.SetupKeyBulkRequestActionRevoke, req.Action)
+			assert.Equal(t, []string{"Test", "Other"}, req.KeyIds)
+			retBytes, _ := json.Marshal(api.BulkOperationResponse{Results: []api.BulkOperationItemResult{
+				{Id: "Test", Status: api.BulkOperationItemResultStatusSuccess},
+				{Id: "Other", Status: api.BulkOperationItemResultStatusFailed, Error: ptr("setup key not found")},
+			}})
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.SetupKeys.Bulk(context.Background(), api.PostApiSetupKeysBulkJSONRequestBody{
+			Action: api.SetupKeyBulkRequestActionRevoke,
+			KeyIds: []string{"Test", "Other"},
+		})
+		require.NoError(t, err)
+		require.Len(t, ret.Results, 2)
+		assert.Equal(t, api.BulkOperationItemResultStatusFailed, ret.Results[1].Status)
+	})
+}
+
 func TestSetupKeys_Update_Err(t *testing.T) {
 	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
 		mux.HandleFunc("/api/setup-keys/Test", func(w http.ResponseWriter, r *http.Request) {
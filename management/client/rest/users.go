@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 
 	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
 )
 
 // UsersAPI APIs for users, do not use directly
@@ -25,6 +26,19 @@ func (a *UsersAPI) List(ctx context.Context) ([]api.User, error) {
 	return ret, err
 }
 
+// ListPage list the users matching the filters, sorted and paginated by the params.
+// Returns the cursor of the next page, which is empty on the last page.
+// See more: https://docs.netbird.io/api/resources/users#list-all-users
+func (a *UsersAPI) ListPage(ctx context.Context, params api.GetApiUsersParams) ([]api.User, string, error) {
+	resp, err := a.c.newRequest(ctx, "GET", "/api/users"+encodeQuery(params), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	ret, err := parseResponse[[]api.User](resp)
+	return ret, resp.Header.Get(util.NextCursorHeader), err
+}
+
 // Create create user
 // See more: https://docs.netbird.io/api/resources/users#create-a-user
 func (a *UsersAPI) Create(ctx context.Context, request api.PostApiUsersJSONRequestBody) (*api.User, error) {
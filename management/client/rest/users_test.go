@@ -52,6 +52,29 @@ func TestUsers_List_200(t *testing.T) {
 	})
 }
 
+func TestUsers_ListPage_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "false", r.URL.Query().Get("service_user"))
+			assert.Equal(t, "admin", r.URL.Query().Get("role"))
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+
+			retBytes, _ := json.Marshal([]api.User{testUser})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, next, err := c.Users.ListPage(context.Background(), api.GetApiUsersParams{
+			ServiceUser: ptr(false),
+			Role:        ptr("admin"),
+			Limit:       ptr(5),
+		})
+		require.NoError(t, err)
+		assert.Len(t, ret, 1)
+		assert.Equal(t, testUser, ret[0])
+		assert.Empty(t, next)
+	})
+}
+
 func TestUsers_List_Err(t *testing.T) {
 	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
 		mux.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +46,7 @@ import (
 	nbContext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/geolocation"
 	"github.com/netbirdio/netbird/management/server/groups"
+	"github.com/netbirdio/netbird/management/server/ha"
 	nbhttp "github.com/netbirdio/netbird/management/server/http"
 	"github.com/netbirdio/netbird/management/server/idp"
 	"github.com/netbirdio/netbird/management/server/metrics"
@@ -214,6 +215,11 @@ var (
 				return fmt.Errorf("failed to build default manager: %v", err)
 			}
 
+			instanceBus, err := initInstanceBus(ctx, store, accountManager)
+			if err != nil {
+				return fmt.Errorf("failed creating the management instance bus: %v", err)
+			}
+
 			secretsManager := server.NewTimeBasedAuthSecretsManager(peersUpdateManager, config.TURNConfig, config.Relay, settingsManager)
 
 			trustedPeers := config.ReverseProxy.TrustedPeers
@@ -365,6 +371,9 @@ var (
 				_ = certManager.Listener().Close()
 			}
 			gRPCAPIHandler.Stop()
+			if instanceBus != nil {
+				_ = instanceBus.Close()
+			}
 			_ = store.Close(ctx)
 			_ = eventStore.Close(ctx)
 			log.WithContext(ctx).Infof("stopped Management Service")
@@ -412,6 +421,21 @@ func notifyStop(ctx context.Context, msg string) {
 	}
 }
 
+// initInstanceBus connects the account manager to the other management instances sharing the database
+func initInstanceBus(ctx context.Context, s store.Store, accountManager *server.DefaultAccountManager) (ha.Bus, error) {
+	if !ha.Enabled() {
+		return nil, nil //nolint:nilnil
+	}
+
+	bus, err := store.NewInstanceBus(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	accountManager.SetInstanceBus(bus)
+
+	return bus, nil
+}
+
 func getInstallationID(ctx context.Context, store store.Store) (string, error) {
 	installationID := store.GetInstallationID()
 	if installationID != "" {
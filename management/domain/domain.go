@@ -1,13 +1,19 @@
 package domain
 
 import (
+	"strings"
+
 	"golang.org/x/net/idna"
 )
 
 type Domain string
 
 // String converts the Domain to a non-punycode string.
+// Regex patterns are returned as they are.
 func (d Domain) String() (string, error) {
+	if d.IsRegex() {
+		return string(d), nil
+	}
 	unicode, err := idna.ToUnicode(string(d))
 	if err != nil {
 		return "", err
@@ -24,8 +30,17 @@ func (d Domain) SafeString() string {
 	return str
 }
 
+// IsWildcard returns true if the domain is a wildcard pattern like *.example.com.
+func (d Domain) IsWildcard() bool {
+	return strings.HasPrefix(string(d), "*.")
+}
+
 // FromString creates a Domain from a string, converting it to punycode.
+// Regex patterns are kept as they are.
 func FromString(s string) (Domain, error) {
+	if Domain(s).IsRegex() {
+		return Domain(s), nil
+	}
 	ascii, err := idna.ToASCII(s)
 	if err != nil {
 		return "", err
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// RegexPrefix marks a route domain as a regular expression, e.g. ~^api-[0-9]+\.internal\.example\.com$
+const RegexPrefix = "~"
+
+const maxRegexLength = 255
+
+// IsRegex returns true if the domain is a regular expression pattern.
+func (d Domain) IsRegex() bool {
+	return strings.HasPrefix(string(d), RegexPrefix)
+}
+
+// ParseRegex compiles a regex domain pattern and returns the fixed zone the pattern is scoped to.
+// The expression always matches the whole name case-insensitively, without the trailing dot.
+// It has to end with a literal domain below the zone, e.g. \.internal\.example\.com, so DNS queries can be
+// intercepted for the zone and only the names matching the expression are routed.
+func ParseRegex(d Domain) (*regexp.Regexp, Domain, error) {
+	expr, ok := strings.CutPrefix(string(d), RegexPrefix)
+	if !ok {
+		return nil, "", fmt.Errorf("not a regex domain: %s", d)
+	}
+	if expr == "" {
+		return nil, "", fmt.Errorf("empty regex domain")
+	}
+	if len(expr) > maxRegexLength {
+		return nil, "", fmt.Errorf("regex domain exceeds %d characters: %s", maxRegexLength, expr)
+	}
+
+	re, err := regexp.Compile(`(?i)^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid regex domain %s: %w", expr, err)
+	}
+
+	zone, err := regexZone(expr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return re, zone, nil
+}
+
+// regexZone returns the domain below the literal suffix of the expression
+func regexZone(expr string) (Domain, error) {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex domain %s: %w", expr, err)
+	}
+	parsed = parsed.Simplify()
+
+	subs := []*syntax.Regexp{parsed}
+	if parsed.Op == syntax.OpConcat {
+		subs = parsed.Sub
+	}
+
+	var suffix string
+	for i := len(subs) - 1; i >= 0; i-- {
+		sub := subs[i]
+		if sub.Op == syntax.OpEndText && suffix == "" {
+			continue
+		}
+		if sub.Op != syntax.OpLiteral {
+			break
+		}
+		suffix = string(sub.Rune) + suffix
+	}
+
+	_, zone, ok := strings.Cut(strings.ToLower(suffix), ".")
+	if !ok || zone == "" {
+		return "", fmt.Errorf("regex domain must end with a fixed domain like \\.example\\.com: %s", expr)
+	}
+
+	if _, err := ValidateDomains([]string{zone}); err != nil {
+		return "", fmt.Errorf("invalid zone of regex domain %s: %w", expr, err)
+	}
+	if !strings.Contains(zone, ".") {
+		return "", fmt.Errorf("regex domain must be scoped below a registrable domain, not the top-level domain %s: %s", zone, expr)
+	}
+
+	return Domain(zone), nil
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRegex(t *testing.T) {
+	tests := []struct {
+		name         string
+		domain       Domain
+		expectedZone Domain
+		matches      []string
+		notMatches   []string
+		wantErr      bool
+	}{
+		{
+			name:         "Anchored expression",
+			domain:       `~^api-[0-9]+\.internal\.example\.com$`,
+			expectedZone: "internal.example.com",
+			matches:      []string{"api-1.internal.example.com", "API-42.Internal.Example.com"},
+			notMatches:   []string{"api-x.internal.example.com", "api-1.internal.example.com.evil.com", "internal.example.com"},
+		},
+		{
+			name:         "Expression without anchors matches the whole name",
+			domain:       `~(web|api)\.eu\.example\.com`,
+			expectedZone: "eu.example.com",
+			matches:      []string{"web.eu.example.com"},
+			notMatches:   []string{"x.web.eu.example.com", "db.eu.example.com"},
+		},
+		{
+			name:         "Literal prefix of the zone",
+			domain:       `~[a-z]+-svc\.example\.com`,
+			expectedZone: "example.com",
+			matches:      []string{"auth-svc.example.com"},
+			notMatches:   []string{"auth.example.com"},
+		},
+		{
+			name:    "Top-level domain",
+			domain:  `~.*\.com$`,
+			wantErr: true,
+		},
+		{
+			name:    "No fixed suffix",
+			domain:  `~.*`,
+			wantErr: true,
+		},
+		{
+			name:    "Alternation of zones",
+			domain:  `~api\.example\.com|api\.example\.org`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid expression",
+			domain:  `~[a-z\.example\.com`,
+			wantErr: true,
+		},
+		{
+			name:    "Not a regex",
+			domain:  "example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, zone, err := ParseRegex(tt.domain)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedZone, zone)
+
+			for _, name := range tt.matches {
+				assert.True(t, re.MatchString(name), name)
+			}
+			for _, name := range tt.notMatches {
+				assert.False(t, re.MatchString(name), name)
+			}
+		})
+	}
+}
+
+func TestRegexDomainIsKept(t *testing.T) {
+	d, err := FromString(`~^api-[0-9]+\.example\.com$`)
+	require.NoError(t, err)
+	assert.True(t, d.IsRegex())
+
+	s, err := d.String()
+	require.NoError(t, err)
+	assert.Equal(t, `~^api-[0-9]+\.example\.com$`, s)
+}
@@ -63,3 +63,57 @@ func ValidateDomainsStrSlice(domains []string) ([]string, error) {
 	}
 	return domainList, nil
 }
+
+// ValidateRouteDomains validates the domains of a route like ValidateDomains.
+// Regex patterns starting with ~ are allowed, see ParseRegex.
+// Wildcard patterns are additionally required to be scoped below a registrable domain, as *.com would route a whole TLD.
+func ValidateRouteDomains(domains []string) (List, error) {
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("domains list is empty")
+	}
+	if len(domains) > maxDomains {
+		return nil, fmt.Errorf("domains list exceeds maximum allowed domains: %d", maxDomains)
+	}
+
+	var domainList List
+	for _, d := range domains {
+		validated, err := ValidateRouteDomain(d)
+		if err != nil {
+			return nil, err
+		}
+		domainList = append(domainList, validated)
+	}
+	return domainList, nil
+}
+
+// ValidateRouteDomain validates a single domain, wildcard or regex pattern of a route or domain network resource.
+func ValidateRouteDomain(d string) (Domain, error) {
+	if Domain(d).IsRegex() {
+		if _, _, err := ParseRegex(Domain(d)); err != nil {
+			return "", err
+		}
+		return Domain(d), nil
+	}
+
+	domainList, err := ValidateDomains([]string{d})
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateWildcard(string(domainList[0])); err != nil {
+		return "", err
+	}
+	return domainList[0], nil
+}
+
+func validateWildcard(d string) error {
+	suffix, ok := strings.CutPrefix(d, "*.")
+	if !ok {
+		return nil
+	}
+
+	if !strings.Contains(suffix, ".") {
+		return fmt.Errorf("wildcard domain must have at least two labels after the wildcard: %s", d)
+	}
+	return nil
+}
@@ -204,3 +204,74 @@ func TestValidateDomainsStrSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRouteDomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		domains  []string
+		expected List
+		wantErr  bool
+	}{
+		{
+			name:     "Valid domains",
+			domains:  []string{"example.com", "*.internal.example.com"},
+			expected: List{"example.com", "*.internal.example.com"},
+			wantErr:  false,
+		},
+		{
+			name:     "Wildcard below registrable domain",
+			domains:  []string{"*.example.com"},
+			expected: List{"*.example.com"},
+			wantErr:  false,
+		},
+		{
+			name:     "Wildcard top-level domain",
+			domains:  []string{"*.com"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid domain format",
+			domains:  []string{"-example.com"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Regex domain",
+			domains:  []string{"example.com", `~^api-[0-9]+\.internal\.example\.com$`},
+			expected: List{"example.com", `~^api-[0-9]+\.internal\.example\.com$`},
+			wantErr:  false,
+		},
+		{
+			name:     "Regex domain of a top-level domain",
+			domains:  []string{`~.*\.com`},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid regex domain",
+			domains:  []string{`~(api\.example\.com`},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Empty list",
+			domains:  nil,
+			expected: nil,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateRouteDomains(tt.domains)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
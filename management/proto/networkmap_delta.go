@@ -0,0 +1,340 @@
+package proto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// ErrNetworkMapDeltaMismatch is returned when a delta can't be applied to the network map the client holds.
+// The client is expected to resync and receive a full network map.
+var ErrNetworkMapDeltaMismatch = errors.New("network map delta doesn't match the local network map")
+
+var deterministic = goproto.MarshalOptions{Deterministic: true}
+
+// ComputeNetworkMapDelta returns the changes required to turn the base network map into the target one
+func ComputeNetworkMapDelta(base, target *NetworkMap) *NetworkMapDelta {
+	delta := &NetworkMapDelta{
+		BaseSerial: base.GetSerial(),
+		Serial:     target.GetSerial(),
+		Checksum:   NetworkMapChecksum(target),
+	}
+
+	if !goproto.Equal(base.GetPeerConfig(), target.GetPeerConfig()) {
+		delta.PeerConfig = target.GetPeerConfig()
+	}
+
+	delta.UpsertedRemotePeers, delta.RemovedRemotePeers = diffPeers(base.GetRemotePeers(), target.GetRemotePeers())
+	delta.UpsertedOfflinePeers, delta.RemovedOfflinePeers = diffPeers(base.GetOfflinePeers(), target.GetOfflinePeers())
+	delta.AddedRoutes, delta.RemovedRoutes = diffByValue(base.GetRoutes(), target.GetRoutes())
+	delta.AddedFirewallRules, delta.RemovedFirewallRules = diffByValue(base.GetFirewallRules(), target.GetFirewallRules())
+	delta.AddedRoutesFirewallRules, delta.RemovedRoutesFirewallRules = diffByValue(base.GetRoutesFirewallRules(), target.GetRoutesFirewallRules())
+	delta.AddedForwardingRules, delta.RemovedForwardingRules = diffByValue(base.GetForwardingRules(), target.GetForwardingRules())
+
+	if zones, ok := diffCustomZones(base.GetDNSConfig(), target.GetDNSConfig()); ok {
+		delta.CustomZones = zones
+	} else {
+		delta.DNSConfig = target.GetDNSConfig()
+		if delta.DNSConfig == nil {
+			// an empty config disables DNS the same way a missing one does
+			delta.DNSConfig = &DNSConfig{}
+		}
+	}
+
+	return delta
+}
+
+// ApplyNetworkMapDelta returns the network map resulting from applying the delta to the base network map.
+// The base network map isn't modified. ErrNetworkMapDeltaMismatch is returned if the delta wasn't computed
+// from the base network map.
+func ApplyNetworkMapDelta(base *NetworkMap, delta *NetworkMapDelta) (*NetworkMap, error) {
+	if base == nil || base.GetSerial() != delta.GetBaseSerial() {
+		return nil, fmt.Errorf("%w: base serial %d, expected %d", ErrNetworkMapDeltaMismatch, base.GetSerial(), delta.GetBaseSerial())
+	}
+
+	nm := &NetworkMap{
+		Serial:     delta.GetSerial(),
+		PeerConfig: base.GetPeerConfig(),
+		DNSConfig:  base.GetDNSConfig(),
+	}
+	if delta.GetPeerConfig() != nil {
+		nm.PeerConfig = delta.GetPeerConfig()
+	}
+
+	var err error
+	if nm.RemotePeers, err = applyPeers(base.GetRemotePeers(), delta.GetUpsertedRemotePeers(), delta.GetRemovedRemotePeers()); err != nil {
+		return nil, fmt.Errorf("remote peers: %w", err)
+	}
+	if nm.OfflinePeers, err = applyPeers(base.GetOfflinePeers(), delta.GetUpsertedOfflinePeers(), delta.GetRemovedOfflinePeers()); err != nil {
+		return nil, fmt.Errorf("offline peers: %w", err)
+	}
+	if nm.Routes, err = applyByValue(base.GetRoutes(), delta.GetAddedRoutes(), delta.GetRemovedRoutes()); err != nil {
+		return nil, fmt.Errorf("routes: %w", err)
+	}
+	if nm.FirewallRules, err = applyByValue(base.GetFirewallRules(), delta.GetAddedFirewallRules(), delta.GetRemovedFirewallRules()); err != nil {
+		return nil, fmt.Errorf("firewall rules: %w", err)
+	}
+	if nm.RoutesFirewallRules, err = applyByValue(base.GetRoutesFirewallRules(), delta.GetAddedRoutesFirewallRules(), delta.GetRemovedRoutesFirewallRules()); err != nil {
+		return nil, fmt.Errorf("routes firewall rules: %w", err)
+	}
+	if nm.ForwardingRules, err = applyByValue(base.GetForwardingRules(), delta.GetAddedForwardingRules(), delta.GetRemovedForwardingRules()); err != nil {
+		return nil, fmt.Errorf("forwarding rules: %w", err)
+	}
+
+	if delta.GetDNSConfig() != nil {
+		nm.DNSConfig = delta.GetDNSConfig()
+	} else if len(delta.GetCustomZones()) > 0 {
+		if nm.DNSConfig, err = applyCustomZones(base.GetDNSConfig(), delta.GetCustomZones()); err != nil {
+			return nil, fmt.Errorf("custom zones: %w", err)
+		}
+	}
+
+	nm.RemotePeersIsEmpty = len(nm.RemotePeers) == 0
+	nm.FirewallRulesIsEmpty = len(nm.FirewallRules) == 0
+	nm.RoutesFirewallRulesIsEmpty = len(nm.RoutesFirewallRules) == 0
+
+	if checksum := NetworkMapChecksum(nm); !bytes.Equal(checksum, delta.GetChecksum()) {
+		return nil, fmt.Errorf("%w: checksum of serial %d differs", ErrNetworkMapDeltaMismatch, nm.GetSerial())
+	}
+
+	return nm, nil
+}
+
+// NetworkMapChecksum returns a hash of the network map content.
+// The order of the repeated entries doesn't affect the checksum as applying deltas doesn't preserve it.
+func NetworkMapChecksum(nm *NetworkMap) []byte {
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, nm.GetSerial())
+
+	writeMessages(h, []goproto.Message{nm.GetPeerConfig()}, false)
+	writeMessages(h, toMessages(nm.GetRemotePeers()), true)
+	writeMessages(h, toMessages(nm.GetOfflinePeers()), true)
+	writeMessages(h, toMessages(nm.GetRoutes()), true)
+	writeMessages(h, toMessages(nm.GetFirewallRules()), true)
+	writeMessages(h, toMessages(nm.GetRoutesFirewallRules()), true)
+	writeMessages(h, toMessages(nm.GetForwardingRules()), true)
+
+	dns := nm.GetDNSConfig()
+	writeMessages(h, []goproto.Message{dnsConfigWithoutZones(dns)}, false)
+	zones := slices.Clone(dns.GetCustomZones())
+	slices.SortFunc(zones, func(a, b *CustomZone) int {
+		return strings.Compare(a.GetDomain(), b.GetDomain())
+	})
+	for _, zone := range zones {
+		writeMessages(h, []goproto.Message{&CustomZone{Domain: zone.GetDomain()}}, false)
+		writeMessages(h, toMessages(zone.GetRecords()), true)
+	}
+
+	return h.Sum(nil)
+}
+
+// writeMessages writes the length prefixed encoding of the messages, sorted if their order isn't relevant
+func writeMessages(h hash.Hash, messages []goproto.Message, sorted bool) {
+	encoded := make([][]byte, 0, len(messages))
+	for _, m := range messages {
+		encoded = append(encoded, marshalKey(m))
+	}
+	if sorted {
+		slices.SortFunc(encoded, bytes.Compare)
+	}
+
+	_ = binary.Write(h, binary.BigEndian, uint32(len(encoded)))
+	for _, b := range encoded {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(b)))
+		_, _ = h.Write(b)
+	}
+}
+
+func marshalKey(m goproto.Message) []byte {
+	b, err := deterministic.Marshal(m)
+	if err != nil {
+		// the messages were unmarshaled or built by us, this can't happen for valid messages
+		return nil
+	}
+	return b
+}
+
+func toMessages[T goproto.Message](list []T) []goproto.Message {
+	messages := make([]goproto.Message, 0, len(list))
+	for _, m := range list {
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+func dnsConfigWithoutZones(dns *DNSConfig) *DNSConfig {
+	return &DNSConfig{
+		ServiceEnable:    dns.GetServiceEnable(),
+		NameServerGroups: dns.GetNameServerGroups(),
+	}
+}
+
+func diffPeers(base, target []*RemotePeerConfig) ([]*RemotePeerConfig, []string) {
+	basePeers := make(map[string]*RemotePeerConfig, len(base))
+	for _, peer := range base {
+		basePeers[peer.GetWgPubKey()] = peer
+	}
+
+	var upserted []*RemotePeerConfig
+	for _, peer := range target {
+		existing, ok := basePeers[peer.GetWgPubKey()]
+		delete(basePeers, peer.GetWgPubKey())
+		if ok && goproto.Equal(existing, peer) {
+			continue
+		}
+		upserted = append(upserted, peer)
+	}
+
+	removed := make([]string, 0, len(basePeers))
+	for _, peer := range base {
+		if _, ok := basePeers[peer.GetWgPubKey()]; ok {
+			removed = append(removed, peer.GetWgPubKey())
+			delete(basePeers, peer.GetWgPubKey())
+		}
+	}
+	return upserted, removed
+}
+
+func applyPeers(base, upserted []*RemotePeerConfig, removed []string) ([]*RemotePeerConfig, error) {
+	index := make(map[string]int, len(base))
+	peers := make([]*RemotePeerConfig, 0, len(base)+len(upserted))
+	for _, peer := range base {
+		index[peer.GetWgPubKey()] = len(peers)
+		peers = append(peers, peer)
+	}
+
+	for _, key := range removed {
+		i, ok := index[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: removed peer %s is unknown", ErrNetworkMapDeltaMismatch, key)
+		}
+		peers[i] = nil
+		delete(index, key)
+	}
+
+	for _, peer := range upserted {
+		if i, ok := index[peer.GetWgPubKey()]; ok {
+			peers[i] = peer
+			continue
+		}
+		index[peer.GetWgPubKey()] = len(peers)
+		peers = append(peers, peer)
+	}
+
+	return slices.DeleteFunc(peers, func(peer *RemotePeerConfig) bool { return peer == nil }), nil
+}
+
+// diffByValue returns the entries to add and to remove from base to get target, duplicates are counted
+func diffByValue[T goproto.Message](base, target []T) ([]T, []T) {
+	counts := make(map[string]int, len(base))
+	for _, m := range base {
+		counts[string(marshalKey(m))]++
+	}
+
+	var added []T
+	for _, m := range target {
+		key := string(marshalKey(m))
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		added = append(added, m)
+	}
+
+	var removed []T
+	for _, m := range base {
+		key := string(marshalKey(m))
+		if counts[key] > 0 {
+			counts[key]--
+			removed = append(removed, m)
+		}
+	}
+	return added, removed
+}
+
+func applyByValue[T goproto.Message](base, added, removed []T) ([]T, error) {
+	result := make([]T, 0, len(base)+len(added))
+	toRemove := make(map[string]int, len(removed))
+	for _, m := range removed {
+		toRemove[string(marshalKey(m))]++
+	}
+
+	for _, m := range base {
+		key := string(marshalKey(m))
+		if toRemove[key] > 0 {
+			toRemove[key]--
+			continue
+		}
+		result = append(result, m)
+	}
+
+	for _, count := range toRemove {
+		if count > 0 {
+			return nil, fmt.Errorf("%w: removed entry is unknown", ErrNetworkMapDeltaMismatch)
+		}
+	}
+
+	return append(result, added...), nil
+}
+
+// diffCustomZones returns the record changes of the custom zones.
+// It returns false when the DNS config changed in any other way and has to be replaced.
+func diffCustomZones(base, target *DNSConfig) ([]*CustomZoneDelta, bool) {
+	if !goproto.Equal(dnsConfigWithoutZones(base), dnsConfigWithoutZones(target)) {
+		return nil, false
+	}
+
+	baseZones := make(map[string]*CustomZone, len(base.GetCustomZones()))
+	for _, zone := range base.GetCustomZones() {
+		baseZones[zone.GetDomain()] = zone
+	}
+	if len(baseZones) != len(base.GetCustomZones()) || len(baseZones) != len(target.GetCustomZones()) {
+		return nil, false
+	}
+
+	var zones []*CustomZoneDelta
+	for _, zone := range target.GetCustomZones() {
+		baseZone, ok := baseZones[zone.GetDomain()]
+		if !ok {
+			return nil, false
+		}
+		delete(baseZones, zone.GetDomain())
+
+		added, removed := diffByValue(baseZone.GetRecords(), zone.GetRecords())
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+		zones = append(zones, &CustomZoneDelta{
+			Domain:         zone.GetDomain(),
+			AddedRecords:   added,
+			RemovedRecords: removed,
+		})
+	}
+	return zones, true
+}
+
+func applyCustomZones(base *DNSConfig, zoneDeltas []*CustomZoneDelta) (*DNSConfig, error) {
+	dns := dnsConfigWithoutZones(base)
+	dns.CustomZones = slices.Clone(base.GetCustomZones())
+
+	for _, zoneDelta := range zoneDeltas {
+		i := slices.IndexFunc(dns.CustomZones, func(zone *CustomZone) bool { return zone.GetDomain() == zoneDelta.GetDomain() })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: zone %s is unknown", ErrNetworkMapDeltaMismatch, zoneDelta.GetDomain())
+		}
+
+		records, err := applyByValue(dns.CustomZones[i].GetRecords(), zoneDelta.GetAddedRecords(), zoneDelta.GetRemovedRecords())
+		if err != nil {
+			return nil, fmt.Errorf("zone %s: %w", zoneDelta.GetDomain(), err)
+		}
+		dns.CustomZones[i] = &CustomZone{Domain: zoneDelta.GetDomain(), Records: records}
+	}
+	return dns, nil
+}
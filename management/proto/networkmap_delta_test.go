@@ -0,0 +1,178 @@
+package proto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func testNetworkMap() *NetworkMap {
+	return &NetworkMap{
+		Serial:     1,
+		PeerConfig: &PeerConfig{Address: "100.64.0.1/16", Fqdn: "peer1.netbird.cloud"},
+		RemotePeers: []*RemotePeerConfig{
+			{WgPubKey: "peer2", AllowedIps: []string{"100.64.0.2/32"}, Fqdn: "peer2.netbird.cloud"},
+			{WgPubKey: "peer3", AllowedIps: []string{"100.64.0.3/32"}, Fqdn: "peer3.netbird.cloud"},
+		},
+		OfflinePeers: []*RemotePeerConfig{
+			{WgPubKey: "peer4", AllowedIps: []string{"100.64.0.4/32"}},
+		},
+		Routes: []*Route{
+			{ID: "route1", Network: "10.0.0.0/24", NetID: "net1", Peer: "peer2"},
+		},
+		FirewallRules: []*FirewallRule{
+			{PeerIP: "100.64.0.2", Direction: RuleDirection_IN, Action: RuleAction_ACCEPT, Protocol: RuleProtocol_ALL},
+			{PeerIP: "100.64.0.3", Direction: RuleDirection_IN, Action: RuleAction_ACCEPT, Protocol: RuleProtocol_TCP, Port: "22"},
+		},
+		DNSConfig: &DNSConfig{
+			ServiceEnable: true,
+			CustomZones: []*CustomZone{
+				{
+					Domain: "netbird.cloud.",
+					Records: []*SimpleRecord{
+						{Name: "peer2.netbird.cloud.", Type: 1, Class: "IN", TTL: 300, RData: "100.64.0.2"},
+						{Name: "peer3.netbird.cloud.", Type: 1, Class: "IN", TTL: 300, RData: "100.64.0.3"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNetworkMapDelta(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(nm *NetworkMap)
+		check  func(t *testing.T, delta *NetworkMapDelta)
+	}{
+		{
+			name:   "no changes",
+			modify: func(nm *NetworkMap) { nm.Serial = 2 },
+			check: func(t *testing.T, delta *NetworkMapDelta) {
+				t.Helper()
+				assert.Empty(t, delta.GetUpsertedRemotePeers())
+				assert.Empty(t, delta.GetRemovedRemotePeers())
+				assert.Nil(t, delta.GetPeerConfig())
+				assert.Nil(t, delta.GetDNSConfig())
+			},
+		},
+		{
+			name: "peer added, changed and removed",
+			modify: func(nm *NetworkMap) {
+				nm.Serial = 2
+				nm.RemotePeers[0].Fqdn = "renamed.netbird.cloud"
+				nm.RemotePeers = append(nm.RemotePeers[:1], &RemotePeerConfig{WgPubKey: "peer5", AllowedIps: []string{"100.64.0.5/32"}})
+				nm.DNSConfig.CustomZones[0].Records = nm.DNSConfig.CustomZones[0].Records[:1]
+			},
+			check: func(t *testing.T, delta *NetworkMapDelta) {
+				t.Helper()
+				assert.Len(t, delta.GetUpsertedRemotePeers(), 2)
+				assert.Equal(t, []string{"peer3"}, delta.GetRemovedRemotePeers())
+				assert.Nil(t, delta.GetDNSConfig())
+				require.Len(t, delta.GetCustomZones(), 1)
+				assert.Len(t, delta.GetCustomZones()[0].GetRemovedRecords(), 1)
+			},
+		},
+		{
+			name: "rules and routes changed",
+			modify: func(nm *NetworkMap) {
+				nm.Serial = 3
+				nm.FirewallRules = nm.FirewallRules[1:]
+				nm.Routes = append(nm.Routes, &Route{ID: "route2", Network: "10.0.1.0/24", NetID: "net2", Peer: "peer3"})
+				nm.ForwardingRules = []*ForwardingRule{{Protocol: RuleProtocol_TCP, TranslatedAddress: []byte{100, 64, 0, 2}}}
+			},
+			check: func(t *testing.T, delta *NetworkMapDelta) {
+				t.Helper()
+				assert.Len(t, delta.GetRemovedFirewallRules(), 1)
+				assert.Empty(t, delta.GetAddedFirewallRules())
+				assert.Len(t, delta.GetAddedRoutes(), 1)
+				assert.Len(t, delta.GetAddedForwardingRules(), 1)
+			},
+		},
+		{
+			name: "dns config replaced",
+			modify: func(nm *NetworkMap) {
+				nm.Serial = 2
+				nm.PeerConfig.Fqdn = "other.netbird.cloud"
+				nm.DNSConfig = nil
+			},
+			check: func(t *testing.T, delta *NetworkMapDelta) {
+				t.Helper()
+				assert.NotNil(t, delta.GetPeerConfig())
+				assert.NotNil(t, delta.GetDNSConfig())
+				assert.Empty(t, delta.GetCustomZones())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := testNetworkMap()
+			target := goproto.Clone(base).(*NetworkMap)
+			tt.modify(target)
+			target.RemotePeersIsEmpty = len(target.RemotePeers) == 0
+			target.FirewallRulesIsEmpty = len(target.FirewallRules) == 0
+			target.RoutesFirewallRulesIsEmpty = len(target.RoutesFirewallRules) == 0
+
+			delta := ComputeNetworkMapDelta(base, target)
+			tt.check(t, delta)
+
+			// the delta has to survive the wire
+			encoded, err := goproto.Marshal(delta)
+			require.NoError(t, err)
+			received := &NetworkMapDelta{}
+			require.NoError(t, goproto.Unmarshal(encoded, received))
+
+			applied, err := ApplyNetworkMapDelta(base, received)
+			require.NoError(t, err)
+			assert.Equal(t, NetworkMapChecksum(target), NetworkMapChecksum(applied))
+			assert.Equal(t, target.GetSerial(), applied.GetSerial())
+			assert.ElementsMatch(t, peerKeys(target.GetRemotePeers()), peerKeys(applied.GetRemotePeers()))
+
+			assert.True(t, goproto.Equal(testNetworkMap(), base), "base network map must not be modified")
+		})
+	}
+}
+
+func TestApplyNetworkMapDeltaMismatch(t *testing.T) {
+	base := testNetworkMap()
+	target := goproto.Clone(base).(*NetworkMap)
+	target.Serial = 2
+	target.RemotePeers = target.RemotePeers[1:]
+	delta := ComputeNetworkMapDelta(base, target)
+
+	t.Run("missing base", func(t *testing.T) {
+		_, err := ApplyNetworkMapDelta(nil, delta)
+		assert.True(t, errors.Is(err, ErrNetworkMapDeltaMismatch))
+	})
+
+	t.Run("serial gap", func(t *testing.T) {
+		_, err := ApplyNetworkMapDelta(target, delta)
+		assert.True(t, errors.Is(err, ErrNetworkMapDeltaMismatch))
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		diverged := goproto.Clone(base).(*NetworkMap)
+		diverged.FirewallRules = diverged.FirewallRules[1:]
+		_, err := ApplyNetworkMapDelta(diverged, delta)
+		assert.True(t, errors.Is(err, ErrNetworkMapDeltaMismatch))
+	})
+
+	t.Run("unknown removed peer", func(t *testing.T) {
+		diverged := goproto.Clone(base).(*NetworkMap)
+		diverged.RemotePeers = diverged.RemotePeers[1:]
+		_, err := ApplyNetworkMapDelta(diverged, delta)
+		assert.True(t, errors.Is(err, ErrNetworkMapDeltaMismatch))
+	})
+}
+
+func peerKeys(peers []*RemotePeerConfig) []string {
+	keys := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		keys = append(keys, peer.GetWgPubKey())
+	}
+	return keys
+}
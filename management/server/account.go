@@ -24,7 +24,9 @@ import (
 	"github.com/netbirdio/netbird/management/server/activity"
 	nbcache "github.com/netbirdio/netbird/management/server/cache"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/eventstream"
 	"github.com/netbirdio/netbird/management/server/geolocation"
+	"github.com/netbirdio/netbird/management/server/ha"
 	"github.com/netbirdio/netbird/management/server/idp"
 	"github.com/netbirdio/netbird/management/server/integrations/integrated_validator"
 	"github.com/netbirdio/netbird/management/server/integrations/port_forwarding"
@@ -92,6 +94,12 @@ type DefaultAccountManager struct {
 	metrics telemetry.AppMetrics
 
 	permissionsManager permissions.Manager
+
+	// eventBroker streams the peer status changes and activity events to the API subscribers
+	eventBroker *eventstream.Broker
+
+	// instanceBus notifies the other management instances sharing the database about peer updates, nil with a single instance
+	instanceBus ha.Bus
 }
 
 // getJWTGroupsChanges calculates the changes needed to sync a user's JWT groups.
@@ -185,6 +193,7 @@ func BuildManager(
 		proxyController:          proxyController,
 		settingsManager:          settingsManager,
 		permissionsManager:       permissionsManager,
+		eventBroker:              eventstream.NewBroker(eventstream.DefaultHistorySize),
 	}
 	accountsCounter, err := store.GetAccountsCounter(ctx)
 	if err != nil {
@@ -250,7 +259,10 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		return nil, status.Errorf(status.InvalidArgument, "peer login expiration can't be smaller than one hour")
 	}
 
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	account, err := am.Store.GetAccount(ctx, accountID)
@@ -272,6 +284,12 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		return nil, err
 	}
 
+	for _, groupID := range newSettings.PostQuantumRequiredGroups {
+		if _, ok := account.Groups[groupID]; !ok {
+			return nil, status.Errorf(status.InvalidArgument, "post-quantum required group %s doesn't exist", groupID)
+		}
+	}
+
 	oldSettings := account.Settings
 	if oldSettings.PeerLoginExpirationEnabled != newSettings.PeerLoginExpirationEnabled {
 		event := activity.AccountPeerLoginExpirationEnabled
@@ -300,6 +318,12 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		account.Network.Serial++
 	}
 
+	if !slices.Equal(oldSettings.PostQuantumRequiredGroups, newSettings.PostQuantumRequiredGroups) {
+		am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountPostQuantumRequiredGroupsUpdated, map[string]any{"groups": newSettings.PostQuantumRequiredGroups})
+		updateAccountPeers = true
+		account.Network.Serial++
+	}
+
 	err = am.handleInactivityExpirationSettings(ctx, oldSettings, newSettings, userID, accountID)
 	if err != nil {
 		return nil, err
@@ -368,7 +392,11 @@ func (am *DefaultAccountManager) handleInactivityExpirationSettings(ctx context.
 
 func (am *DefaultAccountManager) peerLoginExpirationJob(ctx context.Context, accountID string) func() (time.Duration, bool) {
 	return func() (time.Duration, bool) {
-		unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+		unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to lock account %s for expiring peers: %v", accountID, err)
+			return peerSchedulerRetryInterval, true
+		}
 		defer unlock()
 
 		expiredPeers, err := am.getExpiredPeers(ctx, accountID)
@@ -402,7 +430,11 @@ func (am *DefaultAccountManager) checkAndSchedulePeerLoginExpiration(ctx context
 // peerInactivityExpirationJob marks login expired for all inactive peers and returns the minimum duration in which the next peer of the account will expire by inactivity if found
 func (am *DefaultAccountManager) peerInactivityExpirationJob(ctx context.Context, accountID string) func() (time.Duration, bool) {
 	return func() (time.Duration, bool) {
-		unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+		unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to lock account %s for expiring inactive peers: %v", accountID, err)
+			return peerSchedulerRetryInterval, true
+		}
 		defer unlock()
 
 		inactivePeers, err := am.getInactivePeers(ctx, accountID)
@@ -501,7 +533,10 @@ func (am *DefaultAccountManager) warmupIDPCache(ctx context.Context) error {
 
 // DeleteAccount deletes an account and all its users from local store and from the remote IDP if the requester is an admin and account owner
 func (am *DefaultAccountManager) DeleteAccount(ctx context.Context, accountID, userID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 	account, err := am.Store.GetAccount(ctx, accountID)
 	if err != nil {
@@ -864,7 +899,10 @@ func (am *DefaultAccountManager) updateAccountDomainAttributesIfNotUpToDate(ctx
 		return nil
 	}
 
-	unlockAccount := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlockAccount, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlockAccount()
 
 	accountDomain, domainCategory, err := am.Store.GetAccountDomainAndCategory(ctx, store.LockingStrengthShare, accountID)
@@ -959,12 +997,15 @@ func (am *DefaultAccountManager) addNewPrivateAccount(ctx context.Context, domai
 }
 
 func (am *DefaultAccountManager) addNewUserToDomainAccount(ctx context.Context, domainAccountID string, userAuth nbcontext.UserAuth) (string, error) {
-	unlockAccount := am.Store.AcquireWriteLockByUID(ctx, domainAccountID)
+	unlockAccount, err := am.Store.AcquireWriteLockByUID(ctx, domainAccountID)
+	if err != nil {
+		return "", err
+	}
 	defer unlockAccount()
 
 	newUser := types.NewRegularUser(userAuth.UserId)
 	newUser.AccountID = domainAccountID
-	err := am.Store.SaveUser(ctx, store.LockingStrengthUpdate, newUser)
+	err = am.Store.SaveUser(ctx, store.LockingStrengthUpdate, newUser)
 	if err != nil {
 		return "", err
 	}
@@ -1056,14 +1097,14 @@ func (am *DefaultAccountManager) GetAccountIDFromUserAuth(ctx context.Context, u
 		return "", "", status.Errorf(status.NotFound, "user %s not found", userAuth.UserId)
 	}
 
-	if userAuth.IsChild {
-		return accountID, user.Id, nil
-	}
-
 	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
 		return "", "", err
 	}
 
+	if userAuth.IsChild {
+		return accountID, user.Id, nil
+	}
+
 	if !user.IsServiceUser && userAuth.Invited {
 		err = am.redeemInvite(ctx, accountID, user.Id)
 		if err != nil {
@@ -1096,7 +1137,10 @@ func (am *DefaultAccountManager) SyncUserJWTGroups(ctx context.Context, userAuth
 		return nil
 	}
 
-	unlockAccount := am.Store.AcquireWriteLockByUID(ctx, userAuth.AccountId)
+	unlockAccount, err := am.Store.AcquireWriteLockByUID(ctx, userAuth.AccountId)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if unlockAccount != nil {
 			unlockAccount()
@@ -1167,6 +1211,11 @@ func (am *DefaultAccountManager) SyncUserJWTGroups(ctx context.Context, userAuth
 				return fmt.Errorf("error saving groups: %w", err)
 			}
 
+			// dynamic groups may select peers by the groups the user peers just joined or left
+			if _, err = refreshDynamicGroups(ctx, transaction, userAuth.AccountId); err != nil {
+				return fmt.Errorf("error refreshing dynamic groups: %w", err)
+			}
+
 			if err = transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, userAuth.AccountId); err != nil {
 				return fmt.Errorf("error incrementing network serial: %w", err)
 			}
@@ -1316,7 +1365,10 @@ func (am *DefaultAccountManager) getPrivateDomainWithGlobalLock(ctx context.Cont
 	}
 
 	log.WithContext(ctx).Debugf("no primary account found for domain %s, acquiring global lock", domain)
-	cancel := am.Store.AcquireGlobalLock(ctx)
+	cancel, err := am.Store.AcquireGlobalLock(ctx)
+	if err != nil {
+		return "", nil, err
+	}
 
 	// check again if the domain has a primary account because of simultaneous requests
 	domainAccountID, err = am.Store.GetAccountIDByPrivateDomain(ctx, store.LockingStrengthShare, domain)
@@ -1387,9 +1439,15 @@ func (am *DefaultAccountManager) SyncAndMarkPeer(ctx context.Context, accountID
 		log.WithContext(ctx).Debugf("SyncAndMarkPeer: took %v", time.Since(start))
 	}()
 
-	accountUnlock := am.Store.AcquireReadLockByUID(ctx, accountID)
+	accountUnlock, err := am.Store.AcquireReadLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	defer accountUnlock()
-	peerUnlock := am.Store.AcquireWriteLockByUID(ctx, peerPubKey)
+	peerUnlock, err := am.Store.AcquireWriteLockByUID(ctx, peerPubKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	defer peerUnlock()
 
 	peer, netMap, postureChecks, err := am.SyncPeer(ctx, types.PeerSync{WireGuardPubKey: peerPubKey, Meta: meta}, accountID)
@@ -1406,12 +1464,18 @@ func (am *DefaultAccountManager) SyncAndMarkPeer(ctx context.Context, accountID
 }
 
 func (am *DefaultAccountManager) OnPeerDisconnected(ctx context.Context, accountID string, peerPubKey string) error {
-	accountUnlock := am.Store.AcquireReadLockByUID(ctx, accountID)
+	accountUnlock, err := am.Store.AcquireReadLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer accountUnlock()
-	peerUnlock := am.Store.AcquireWriteLockByUID(ctx, peerPubKey)
+	peerUnlock, err := am.Store.AcquireWriteLockByUID(ctx, peerPubKey)
+	if err != nil {
+		return err
+	}
 	defer peerUnlock()
 
-	err := am.MarkPeerConnected(ctx, peerPubKey, false, nil, accountID)
+	err = am.MarkPeerConnected(ctx, peerPubKey, false, nil, accountID)
 	if err != nil {
 		log.WithContext(ctx).Warnf("failed marking peer as disconnected %s %v", peerPubKey, err)
 	}
@@ -1426,10 +1490,16 @@ func (am *DefaultAccountManager) SyncPeerMeta(ctx context.Context, peerPubKey st
 		return err
 	}
 
-	unlock := am.Store.AcquireReadLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireReadLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
-	unlockPeer := am.Store.AcquireWriteLockByUID(ctx, peerPubKey)
+	unlockPeer, err := am.Store.AcquireWriteLockByUID(ctx, peerPubKey)
+	if err != nil {
+		return err
+	}
 	defer unlockPeer()
 
 	_, _, _, err = am.SyncPeer(ctx, types.PeerSync{WireGuardPubKey: peerPubKey, Meta: meta, UpdateAccountPeers: true}, accountID)
@@ -1613,7 +1683,10 @@ func (am *DefaultAccountManager) GetStore() store.Store {
 // Creates account by private domain.
 // Expects domain value to be a valid and a private dns domain.
 func (am *DefaultAccountManager) CreateAccountByPrivateDomain(ctx context.Context, initiatorId, domain string) (*types.Account, error) {
-	cancel := am.Store.AcquireGlobalLock(ctx)
+	cancel, err := am.Store.AcquireGlobalLock(ctx)
+	if err != nil {
+		return nil, err
+	}
 	defer cancel()
 
 	domain = strings.ToLower(domain)
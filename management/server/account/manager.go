@@ -11,6 +11,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/activity"
 	nbcache "github.com/netbirdio/netbird/management/server/cache"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/eventstream"
 	"github.com/netbirdio/netbird/management/server/idp"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/posture"
@@ -25,7 +26,7 @@ type Manager interface {
 	GetOrCreateAccountByUser(ctx context.Context, userId, domain string) (*types.Account, error)
 	GetAccount(ctx context.Context, accountID string) (*types.Account, error)
 	CreateSetupKey(ctx context.Context, accountID string, keyName string, keyType types.SetupKeyType, expiresIn time.Duration,
-		autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool) (*types.SetupKey, error)
+		autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool, constraints types.SetupKeyConstraints) (*types.SetupKey, error)
 	SaveSetupKey(ctx context.Context, accountID string, key *types.SetupKey, userID string) (*types.SetupKey, error)
 	CreateUser(ctx context.Context, accountID, initiatorUserID string, key *types.UserInfo) (*types.UserInfo, error)
 	DeleteUser(ctx context.Context, accountID, initiatorUserID string, targetUserID string) error
@@ -45,8 +46,10 @@ type Manager interface {
 	GetUserFromUserAuth(ctx context.Context, userAuth nbcontext.UserAuth) (*types.User, error)
 	ListUsers(ctx context.Context, accountID string) ([]*types.User, error)
 	GetPeers(ctx context.Context, accountID, userID, nameFilter, ipFilter string) ([]*nbpeer.Peer, error)
+	GetPeersPage(ctx context.Context, accountID, userID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error)
 	MarkPeerConnected(ctx context.Context, peerKey string, connected bool, realIP net.IP, accountID string) error
 	DeletePeer(ctx context.Context, accountID, peerID, userID string) error
+	BulkUpdatePeers(ctx context.Context, accountID, userID string, op *types.PeerBulkOperation) ([]*types.BulkItemResult, error)
 	UpdatePeer(ctx context.Context, accountID, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, error)
 	GetNetworkMap(ctx context.Context, peerID string) (*types.NetworkMap, error)
 	GetPeerNetwork(ctx context.Context, peerID string) (*types.Network, error)
@@ -56,8 +59,10 @@ type Manager interface {
 	GetPAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenID string) (*types.PersonalAccessToken, error)
 	GetAllPATs(ctx context.Context, accountID string, initiatorUserID string, targetUserID string) ([]*types.PersonalAccessToken, error)
 	GetUsersFromAccount(ctx context.Context, accountID, userID string) (map[string]*types.UserInfo, error)
+	GetUsersPage(ctx context.Context, accountID, initiatorUserID string, filter types.UserFilter, page types.PageRequest) ([]*types.UserInfo, string, error)
 	GetGroup(ctx context.Context, accountId, groupID, userID string) (*types.Group, error)
 	GetAllGroups(ctx context.Context, accountID, userID string) ([]*types.Group, error)
+	PreviewGroupQuery(ctx context.Context, accountID, userID string, query *types.GroupQuery) ([]*nbpeer.Peer, error)
 	GetGroupByName(ctx context.Context, groupName, accountID string) (*types.Group, error)
 	SaveGroup(ctx context.Context, accountID, userID string, group *types.Group) error
 	SaveGroups(ctx context.Context, accountID, userID string, newGroups []*types.Group) error
@@ -70,6 +75,7 @@ type Manager interface {
 	SavePolicy(ctx context.Context, accountID, userID string, policy *types.Policy) (*types.Policy, error)
 	DeletePolicy(ctx context.Context, accountID, policyID, userID string) error
 	ListPolicies(ctx context.Context, accountID, userID string) ([]*types.Policy, error)
+	SimulatePolicy(ctx context.Context, accountID, userID string, req types.PolicySimulationRequest) (*types.PolicySimulationResult, error)
 	GetRoute(ctx context.Context, accountID string, routeID route.ID, userID string) (*route.Route, error)
 	CreateRoute(ctx context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peerID string, peerGroupIDs []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroupIDs []string, enabled bool, userID string, keepRoute bool) (*route.Route, error)
 	SaveRoute(ctx context.Context, accountID, userID string, route *route.Route) error
@@ -107,6 +113,7 @@ type Manager interface {
 	GetAccountIDForPeerKey(ctx context.Context, peerKey string) (string, error)
 	GetAccountSettings(ctx context.Context, accountID string, userID string) (*types.Settings, error)
 	DeleteSetupKey(ctx context.Context, accountID, userID, keyID string) error
+	BulkUpdateSetupKeys(ctx context.Context, accountID, userID string, op *types.SetupKeyBulkOperation) ([]*types.BulkItemResult, error)
 	UpdateAccountPeers(ctx context.Context, accountID string)
 	BuildUserInfosForAccount(ctx context.Context, accountID, initiatorUserID string, accountUsers []*types.User) (map[string]*types.UserInfo, error)
 	SyncUserJWTGroups(ctx context.Context, userAuth nbcontext.UserAuth) error
@@ -114,4 +121,25 @@ type Manager interface {
 	CreateAccountByPrivateDomain(ctx context.Context, initiatorId, domain string) (*types.Account, error)
 	UpdateToPrimaryAccount(ctx context.Context, accountId string) (*types.Account, error)
 	GetOwnerInfo(ctx context.Context, accountId string) (*types.UserInfo, error)
+	GetChildAccounts(ctx context.Context, accountID, userID string) ([]*types.ChildAccount, error)
+	SetAccountParent(ctx context.Context, accountID, userID, parentAccountID string) error
+	AcceptChildAccount(ctx context.Context, accountID, userID, childAccountID string) error
+	RemoveChildAccount(ctx context.Context, accountID, userID, childAccountID string) error
+	PushTemplatesToChildAccounts(ctx context.Context, accountID, userID string, templates *types.ChildAccountTemplates) ([]*types.BulkItemResult, error)
+	GetChildAccountsEvents(ctx context.Context, accountID, userID string) ([]*activity.Event, error)
+	SubscribeEvents(ctx context.Context, accountID, userID string, filter eventstream.Filter, lastEventID uint64) (*eventstream.Subscription, error)
+	UnsubscribeEvents(subscription *eventstream.Subscription)
+	GetPeerApprovalRequests(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRequest, error)
+	ApprovePeer(ctx context.Context, accountID, userID, peerID, reason string) (*nbpeer.Peer, error)
+	RejectPeer(ctx context.Context, accountID, userID, peerID, reason string) error
+	GetPeerApprovalRules(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRule, error)
+	GetPeerApprovalRule(ctx context.Context, accountID, userID, ruleID string) (*types.PeerApprovalRule, error)
+	SavePeerApprovalRule(ctx context.Context, accountID, userID string, rule *types.PeerApprovalRule) (*types.PeerApprovalRule, error)
+	DeletePeerApprovalRule(ctx context.Context, accountID, userID, ruleID string) error
+	GetInventoryDevices(ctx context.Context, accountID, userID string) ([]*types.InventoryDevice, error)
+	GetInventoryDevice(ctx context.Context, accountID, userID, deviceID string) (*types.InventoryDevice, error)
+	SaveInventoryDevice(ctx context.Context, accountID, userID string, device *types.InventoryDevice) (*types.InventoryDevice, error)
+	DeleteInventoryDevice(ctx context.Context, accountID, userID, deviceID string) error
+	ImportInventoryDevices(ctx context.Context, accountID, userID string, devices []*types.InventoryDevice) (*types.InventoryImportSummary, error)
+	GetInventoriedSerialNumbers(ctx context.Context, accountID string) (map[string]struct{}, error)
 }
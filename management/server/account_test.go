@@ -983,7 +983,7 @@ func TestAccountManager_AddPeer(t *testing.T) {
 
 	serial := account.Network.CurrentSerial() // should be 0
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -1359,7 +1359,7 @@ func TestAccountManager_DeletePeer(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -1679,6 +1679,13 @@ func TestAccount_Copy(t *testing.T) {
 				Address:   "172.12.6.1/24",
 			},
 		},
+		InventoryDevices: []*types.InventoryDevice{
+			{
+				ID:           "device1",
+				SerialNumber: "SN-1",
+				UserID:       "user1",
+			},
+		},
 	}
 	err := hasNilField(account)
 	if err != nil {
@@ -1933,6 +1940,48 @@ func TestDefaultAccountManager_UpdateAccountSettings(t *testing.T) {
 	require.Error(t, err, "expecting to fail when providing PeerLoginExpiration more than 180 days")
 }
 
+func TestDefaultAccountManager_UpdateAccountSettings_PostQuantumRequiredGroups(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err, "unable to create account manager")
+
+	accountID, err := manager.GetAccountIDByUserID(context.Background(), userID, "")
+	require.NoError(t, err, "unable to create an account")
+
+	allGroup, err := manager.Store.GetGroupByName(context.Background(), store.LockingStrengthShare, accountID, "All")
+	require.NoError(t, err, "unable to get the All group")
+
+	_, err = manager.UpdateAccountSettings(context.Background(), accountID, userID, &types.Settings{
+		PeerLoginExpiration:       2 * time.Hour,
+		PostQuantumRequiredGroups: []string{"unknown"},
+	})
+	require.Error(t, err, "expecting to fail when providing a group that doesn't exist")
+
+	_, err = manager.UpdateAccountSettings(context.Background(), accountID, userID, &types.Settings{
+		PeerLoginExpiration:       time.Hour,
+		PostQuantumRequiredGroups: []string{allGroup.ID},
+	})
+	require.NoError(t, err, "expecting to update account settings successfully but got error")
+
+	settings, err := manager.Store.GetAccountSettings(context.Background(), store.LockingStrengthShare, accountID)
+	require.NoError(t, err, "unable to get account settings")
+	assert.Equal(t, []string{allGroup.ID}, settings.PostQuantumRequiredGroups)
+
+	// the rejected update must not store the event of its login expiration change
+	durationUpdates := func() int {
+		events, err := manager.GetEvents(context.Background(), accountID, userID)
+		require.NoError(t, err, "unable to get events")
+		var count int
+		for _, event := range events {
+			if event.Activity == activity.AccountPeerLoginExpirationDurationUpdated {
+				count++
+			}
+		}
+		return count
+	}
+	require.Eventually(t, func() bool { return durationUpdates() > 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, durationUpdates(), "expecting only the accepted update to store an event")
+}
+
 func TestAccount_GetExpiredPeers(t *testing.T) {
 	type test struct {
 		name          string
@@ -2878,7 +2927,7 @@ func setupNetworkMapTest(t *testing.T) (*DefaultAccountManager, *types.Account,
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 	}
@@ -169,6 +169,40 @@ const (
 
 	ResourceAddedToGroup     Activity = 82
 	ResourceRemovedFromGroup Activity = 83
+
+	// AccountParentLinked indicates that a parent account admin accepted the account as a child account
+	AccountParentLinked Activity = 84
+	// AccountParentUnlinked indicates that the account was unlinked from its parent account
+	AccountParentUnlinked Activity = 85
+	// ChildAccountTemplatesPushed indicates that a parent account admin pushed templates to the child account
+	ChildAccountTemplatesPushed Activity = 86
+
+	// PeerRejected indicates that a pending peer was rejected and removed
+	PeerRejected Activity = 87
+	// PeerAutoApproved indicates that the peer was approved by an auto-approve rule
+	PeerAutoApproved Activity = 88
+	// PeerApprovalRuleCreated indicates that a user created a peer auto-approve rule
+	PeerApprovalRuleCreated Activity = 89
+	// PeerApprovalRuleUpdated indicates that a user updated a peer auto-approve rule
+	PeerApprovalRuleUpdated Activity = 90
+	// PeerApprovalRuleDeleted indicates that a user deleted a peer auto-approve rule
+	PeerApprovalRuleDeleted Activity = 91
+	// InventoryDeviceAdded indicates that a user added a device to the account inventory
+	InventoryDeviceAdded Activity = 92
+	// InventoryDeviceUpdated indicates that a user updated a device of the account inventory
+	InventoryDeviceUpdated Activity = 93
+	// InventoryDeviceDeleted indicates that a user deleted a device from the account inventory
+	InventoryDeviceDeleted Activity = 94
+	// InventoryDevicesImported indicates that a user imported devices to the account inventory
+	InventoryDevicesImported Activity = 95
+	// SetupKeyConstraintViolated indicates that a peer was refused registration by the setup key constraints
+	SetupKeyConstraintViolated Activity = 96
+	// AccountPostQuantumRequiredGroupsUpdated indicates that a user updated the groups requiring the post-quantum key exchange
+	AccountPostQuantumRequiredGroupsUpdated Activity = 97
+	// AccountParentLinkRequested indicates that the account owner asked to link the account to a parent account
+	AccountParentLinkRequested Activity = 98
+	// AccountParentLinkRequestCanceled indicates that a request to link the account to a parent account was withdrawn or declined
+	AccountParentLinkRequestCanceled Activity = 99
 )
 
 var activityMap = map[Activity]Code{
@@ -264,6 +298,27 @@ var activityMap = map[Activity]Code{
 
 	ResourceAddedToGroup:     {"Resource added to group", "resource.group.add"},
 	ResourceRemovedFromGroup: {"Resource removed from group", "resource.group.delete"},
+
+	AccountParentLinked:         {"Account linked to parent account", "account.parent.link"},
+	AccountParentUnlinked:       {"Account unlinked from parent account", "account.parent.unlink"},
+	ChildAccountTemplatesPushed: {"Templates pushed from parent account", "account.child.templates.push"},
+
+	PeerRejected:            {"Peer rejected", "peer.reject"},
+	PeerAutoApproved:        {"Peer auto-approved", "peer.auto.approve"},
+	PeerApprovalRuleCreated: {"Peer approval rule created", "peer.approval.rule.create"},
+	PeerApprovalRuleUpdated: {"Peer approval rule updated", "peer.approval.rule.update"},
+	PeerApprovalRuleDeleted: {"Peer approval rule deleted", "peer.approval.rule.delete"},
+
+	InventoryDeviceAdded:     {"Inventory device added", "inventory.device.add"},
+	InventoryDeviceUpdated:   {"Inventory device updated", "inventory.device.update"},
+	InventoryDeviceDeleted:   {"Inventory device deleted", "inventory.device.delete"},
+	InventoryDevicesImported: {"Inventory devices imported", "inventory.device.import"},
+
+	SetupKeyConstraintViolated: {"Peer registration refused by setup key constraints", "setupkey.constraint.violate"},
+
+	AccountPostQuantumRequiredGroupsUpdated: {"Account post-quantum required groups updated", "account.setting.post.quantum.groups.update"},
+	AccountParentLinkRequested:              {"Account parent link requested", "account.parent.link.request"},
+	AccountParentLinkRequestCanceled:        {"Account parent link request canceled", "account.parent.link.cancel"},
 }
 
 // StringCode returns a string code of the activity
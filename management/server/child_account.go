@@ -0,0 +1,650 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rs/xid"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// childAccountTemplateSet holds the parent account objects pushed to the child accounts
+type childAccountTemplateSet struct {
+	policies      []*types.Policy
+	postureChecks []*posture.Checks
+	nsGroups      []*nbdns.NameServerGroup
+	// groups are the parent groups referenced by the templates, they are matched to the child groups by name
+	groups map[string]*types.Group
+}
+
+// GetChildAccounts returns the accounts managed by the admins of the account and the accounts waiting for them to accept the link
+func (am *DefaultAccountManager) GetChildAccounts(ctx context.Context, accountID, userID string) ([]*types.ChildAccount, error) {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, err
+	}
+
+	if !user.HasAdminPower() {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	childAccounts, err := am.Store.GetChildAccounts(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingChildAccounts, err := am.Store.GetPendingChildAccounts(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(childAccounts, pendingChildAccounts...), nil
+}
+
+// SetAccountParent asks to link the account to a parent account whose admins will be able to manage it.
+// The link is made once an admin of the parent account accepts it with AcceptChildAccount.
+// Only the owner of the account can change its parent, an empty parentAccountID unlinks the account and withdraws a pending request.
+func (am *DefaultAccountManager) SetAccountParent(ctx context.Context, accountID, userID, parentAccountID string) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.AccountID != accountID || user.Role != types.UserRoleOwner {
+		return status.Errorf(status.PermissionDenied, "only the account owner can change the parent account")
+	}
+
+	var oldParentAccountID, oldPendingParentAccountID string
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		oldParentAccountID, err = transaction.GetAccountParentID(ctx, store.LockingStrengthUpdate, accountID)
+		if err != nil {
+			return err
+		}
+
+		oldPendingParentAccountID, err = transaction.GetAccountPendingParentID(ctx, store.LockingStrengthUpdate, accountID)
+		if err != nil {
+			return err
+		}
+
+		if parentAccountID == "" {
+			if err = transaction.SaveAccountParent(ctx, store.LockingStrengthUpdate, accountID, ""); err != nil {
+				return err
+			}
+			return transaction.SaveAccountPendingParent(ctx, store.LockingStrengthUpdate, accountID, "")
+		}
+
+		if parentAccountID == oldParentAccountID || parentAccountID == oldPendingParentAccountID {
+			return nil
+		}
+
+		if err = validateAccountParent(ctx, transaction, accountID, parentAccountID); err != nil {
+			return err
+		}
+
+		return transaction.SaveAccountPendingParent(ctx, store.LockingStrengthUpdate, accountID, parentAccountID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if parentAccountID == "" {
+		if oldParentAccountID != "" {
+			am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountParentUnlinked, map[string]any{"parent_account_id": oldParentAccountID})
+		}
+		if oldPendingParentAccountID != "" {
+			am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountParentLinkRequestCanceled, map[string]any{"parent_account_id": oldPendingParentAccountID})
+		}
+		return nil
+	}
+
+	if parentAccountID == oldParentAccountID || parentAccountID == oldPendingParentAccountID {
+		return nil
+	}
+
+	if oldPendingParentAccountID != "" {
+		am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountParentLinkRequestCanceled, map[string]any{"parent_account_id": oldPendingParentAccountID})
+	}
+	am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountParentLinkRequested, map[string]any{"parent_account_id": parentAccountID})
+
+	return nil
+}
+
+// AcceptChildAccount links an account that asked to be managed by the account, its admins will be able to manage the child account
+func (am *DefaultAccountManager) AcceptChildAccount(ctx context.Context, accountID, userID, childAccountID string) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, childAccountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return err
+	}
+
+	if !user.HasAdminPower() {
+		return status.NewAdminPermissionError()
+	}
+
+	var oldParentAccountID string
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		pendingParentAccountID, err := transaction.GetAccountPendingParentID(ctx, store.LockingStrengthUpdate, childAccountID)
+		if err != nil || pendingParentAccountID != accountID {
+			return status.Errorf(status.NotFound, "pending child account %s not found", childAccountID)
+		}
+
+		oldParentAccountID, err = transaction.GetAccountParentID(ctx, store.LockingStrengthUpdate, childAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err = validateAccountParent(ctx, transaction, childAccountID, accountID); err != nil {
+			return err
+		}
+
+		if err = transaction.SaveAccountParent(ctx, store.LockingStrengthUpdate, childAccountID, accountID); err != nil {
+			return err
+		}
+
+		return transaction.SaveAccountPendingParent(ctx, store.LockingStrengthUpdate, childAccountID, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	if oldParentAccountID != "" {
+		am.StoreEvent(ctx, userID, childAccountID, childAccountID, activity.AccountParentUnlinked, map[string]any{"parent_account_id": oldParentAccountID})
+	}
+	am.StoreEvent(ctx, userID, childAccountID, childAccountID, activity.AccountParentLinked, map[string]any{"parent_account_id": accountID})
+
+	return nil
+}
+
+// RemoveChildAccount unlinks a child account from the account, its admins won't be able to manage the child account anymore.
+// A pending request of the account to be linked is declined.
+func (am *DefaultAccountManager) RemoveChildAccount(ctx context.Context, accountID, userID, childAccountID string) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, childAccountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return err
+	}
+
+	if !user.HasAdminPower() {
+		return status.NewAdminPermissionError()
+	}
+
+	var pending bool
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		parentAccountID, err := transaction.GetAccountParentID(ctx, store.LockingStrengthUpdate, childAccountID)
+		if err != nil {
+			return status.Errorf(status.NotFound, "child account %s not found", childAccountID)
+		}
+
+		if parentAccountID == accountID {
+			return transaction.SaveAccountParent(ctx, store.LockingStrengthUpdate, childAccountID, "")
+		}
+
+		pendingParentAccountID, err := transaction.GetAccountPendingParentID(ctx, store.LockingStrengthUpdate, childAccountID)
+		if err != nil || pendingParentAccountID != accountID {
+			return status.Errorf(status.NotFound, "child account %s not found", childAccountID)
+		}
+
+		pending = true
+		return transaction.SaveAccountPendingParent(ctx, store.LockingStrengthUpdate, childAccountID, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	if pending {
+		am.StoreEvent(ctx, userID, childAccountID, childAccountID, activity.AccountParentLinkRequestCanceled, map[string]any{"parent_account_id": accountID})
+		return nil
+	}
+
+	am.StoreEvent(ctx, userID, childAccountID, childAccountID, activity.AccountParentUnlinked, map[string]any{"parent_account_id": accountID})
+
+	return nil
+}
+
+// PushTemplatesToChildAccounts copies the given policies, posture checks and nameserver groups of the account to its child accounts.
+// Each child account is updated in its own transaction, the outcome is reported per child account.
+func (am *DefaultAccountManager) PushTemplatesToChildAccounts(ctx context.Context, accountID, userID string, templates *types.ChildAccountTemplates) ([]*types.BulkItemResult, error) {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, err
+	}
+
+	if !user.HasAdminPower() {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	if len(templates.PolicyIDs)+len(templates.PostureCheckIDs)+len(templates.NameServerGroupIDs) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "no templates provided")
+	}
+
+	childAccounts, err := am.Store.GetChildAccounts(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	childAccountIDs := templates.ChildAccountIDs
+	if len(childAccountIDs) == 0 {
+		for _, childAccount := range childAccounts {
+			childAccountIDs = append(childAccountIDs, childAccount.ID)
+		}
+	}
+
+	templateSet, err := getChildAccountTemplateSet(ctx, am.Store, accountID, templates)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*types.BulkItemResult, 0, len(childAccountIDs))
+	for _, childAccountID := range childAccountIDs {
+		isChild := slices.ContainsFunc(childAccounts, func(childAccount *types.ChildAccount) bool {
+			return childAccount.ID == childAccountID
+		})
+		if !isChild {
+			results = append(results, &types.BulkItemResult{
+				ID:  childAccountID,
+				Err: status.Errorf(status.NotFound, "child account %s not found", childAccountID),
+			})
+			continue
+		}
+
+		err = am.pushTemplatesToChildAccount(ctx, childAccountID, userID, templateSet)
+		results = append(results, &types.BulkItemResult{ID: childAccountID, Err: err})
+	}
+
+	return results, nil
+}
+
+func (am *DefaultAccountManager) pushTemplatesToChildAccount(ctx context.Context, childAccountID, userID string, templateSet *childAccountTemplateSet) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, childAccountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		groupIDs, err := saveChildAccountGroups(ctx, transaction, childAccountID, templateSet.groups)
+		if err != nil {
+			return err
+		}
+
+		if _, err = refreshDynamicGroups(ctx, transaction, childAccountID); err != nil {
+			return err
+		}
+
+		postureCheckIDs, err := saveChildAccountPostureChecks(ctx, transaction, childAccountID, templateSet.postureChecks)
+		if err != nil {
+			return err
+		}
+
+		if err = saveChildAccountPolicies(ctx, transaction, childAccountID, templateSet.policies, groupIDs, postureCheckIDs); err != nil {
+			return err
+		}
+
+		if err = saveChildAccountNameServerGroups(ctx, transaction, childAccountID, templateSet.nsGroups, groupIDs); err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, childAccountID)
+	})
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]any{
+		"policies":          len(templateSet.policies),
+		"posture_checks":    len(templateSet.postureChecks),
+		"nameserver_groups": len(templateSet.nsGroups),
+	}
+	am.StoreEvent(ctx, userID, childAccountID, childAccountID, activity.ChildAccountTemplatesPushed, meta)
+
+	am.UpdateAccountPeers(ctx, childAccountID)
+
+	return nil
+}
+
+// getChildAccountTemplateSet loads the templates from the parent account including the posture checks and groups they reference
+func getChildAccountTemplateSet(ctx context.Context, s store.Store, accountID string, templates *types.ChildAccountTemplates) (*childAccountTemplateSet, error) {
+	templateSet := &childAccountTemplateSet{}
+	postureCheckIDs := slices.Clone(templates.PostureCheckIDs)
+	var groupIDs []string
+
+	for _, policyID := range templates.PolicyIDs {
+		policy, err := s.GetPolicyByID(ctx, store.LockingStrengthShare, accountID, policyID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range policy.Rules {
+			if rule.SourceResource.ID != "" || rule.DestinationResource.ID != "" {
+				return nil, status.Errorf(status.InvalidArgument, "policy %s references network resources and can't be used as a template", policy.Name)
+			}
+		}
+
+		templateSet.policies = append(templateSet.policies, policy)
+		postureCheckIDs = append(postureCheckIDs, policy.SourcePostureChecks...)
+		groupIDs = append(groupIDs, policy.RuleGroups()...)
+	}
+
+	postureChecks, err := s.GetPostureChecksByIDs(ctx, store.LockingStrengthShare, accountID, postureCheckIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, postureCheckID := range templates.PostureCheckIDs {
+		if _, ok := postureChecks[postureCheckID]; !ok {
+			return nil, status.Errorf(status.NotFound, "posture checks %s not found", postureCheckID)
+		}
+	}
+
+	for _, postureCheck := range postureChecks {
+		templateSet.postureChecks = append(templateSet.postureChecks, postureCheck)
+	}
+
+	for _, nsGroupID := range templates.NameServerGroupIDs {
+		nsGroup, err := s.GetNameServerGroupByID(ctx, store.LockingStrengthShare, accountID, nsGroupID)
+		if err != nil {
+			return nil, err
+		}
+
+		templateSet.nsGroups = append(templateSet.nsGroups, nsGroup)
+		groupIDs = append(groupIDs, nsGroup.Groups...)
+	}
+
+	templateSet.groups, err = getChildAccountTemplateGroups(ctx, s, accountID, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateSet, nil
+}
+
+// getChildAccountTemplateGroups loads the groups and the groups their dynamic queries depend on
+func getChildAccountTemplateGroups(ctx context.Context, s store.Store, accountID string, groupIDs []string) (map[string]*types.Group, error) {
+	templateGroups := make(map[string]*types.Group, len(groupIDs))
+	for len(groupIDs) > 0 {
+		groups, err := s.GetGroupsByIDs(ctx, store.LockingStrengthShare, accountID, groupIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		groupIDs = nil
+		for _, group := range groups {
+			templateGroups[group.ID] = group
+			if !group.IsDynamic() {
+				continue
+			}
+
+			// user IDs are specific to the parent account, such a query would match nothing in the child account
+			for _, rule := range group.Query.Rules {
+				if rule.Attribute == types.GroupQueryAttributeUser {
+					return nil, status.Errorf(status.InvalidArgument, "group %s matches peers by user and can't be used as a template", group.Name)
+				}
+			}
+
+			for _, referencedGroupID := range group.Query.ReferencedGroups() {
+				if _, ok := templateGroups[referencedGroupID]; !ok {
+					groupIDs = append(groupIDs, referencedGroupID)
+				}
+			}
+		}
+	}
+
+	return templateGroups, nil
+}
+
+// saveChildAccountGroups creates the template groups missing in the child account and returns the child group IDs by the parent group IDs.
+// The queries of the dynamic groups are copied to the new child groups and to the existing dynamic ones, existing static groups are kept as they are.
+func saveChildAccountGroups(ctx context.Context, transaction store.Store, childAccountID string, groups map[string]*types.Group) (map[string]string, error) {
+	childGroups, err := transaction.GetAccountGroups(ctx, store.LockingStrengthUpdate, childAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs := make(map[string]string, len(groups))
+	// dynamicGroups are the child groups receiving the query of the parent group with the same ID
+	dynamicGroups := make(map[string]*types.Group)
+	var groupsToSave []*types.Group
+	for _, group := range groups {
+		idx := slices.IndexFunc(childGroups, func(childGroup *types.Group) bool { return childGroup.Name == group.Name })
+		if idx >= 0 {
+			childGroup := childGroups[idx]
+			groupIDs[group.ID] = childGroup.ID
+			if group.IsDynamic() && childGroup.IsDynamic() {
+				dynamicGroups[group.ID] = childGroup
+				groupsToSave = append(groupsToSave, childGroup)
+			}
+			continue
+		}
+
+		newGroup := &types.Group{
+			ID:        xid.New().String(),
+			AccountID: childAccountID,
+			Name:      group.Name,
+			Issued:    types.GroupIssuedAPI,
+			Peers:     []string{},
+		}
+		if group.IsDynamic() {
+			dynamicGroups[group.ID] = newGroup
+		}
+		groupsToSave = append(groupsToSave, newGroup)
+		groupIDs[group.ID] = newGroup.ID
+	}
+
+	for groupID, childGroup := range dynamicGroups {
+		childGroup.Query = groups[groupID].Query.Copy()
+		for i, rule := range childGroup.Query.Rules {
+			if rule.Attribute == types.GroupQueryAttributeGroup {
+				childGroup.Query.Rules[i].Values = mapChildAccountIDs(rule.Values, groupIDs)
+			}
+		}
+	}
+
+	if len(groupsToSave) == 0 {
+		return groupIDs, nil
+	}
+
+	return groupIDs, transaction.SaveGroups(ctx, store.LockingStrengthUpdate, groupsToSave)
+}
+
+// saveChildAccountPostureChecks saves the posture checks replacing the child ones with the same name and returns the child IDs by the parent IDs
+func saveChildAccountPostureChecks(ctx context.Context, transaction store.Store, childAccountID string, postureChecks []*posture.Checks) (map[string]string, error) {
+	childPostureChecks, err := transaction.GetAccountPostureChecks(ctx, store.LockingStrengthUpdate, childAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	postureCheckIDs := make(map[string]string, len(postureChecks))
+	for _, postureCheck := range postureChecks {
+		childPostureCheck := postureCheck.Copy()
+		childPostureCheck.AccountID = childAccountID
+		childPostureCheck.ID = xid.New().String()
+
+		idx := slices.IndexFunc(childPostureChecks, func(c *posture.Checks) bool { return c.Name == postureCheck.Name })
+		if idx >= 0 {
+			childPostureCheck.ID = childPostureChecks[idx].ID
+		}
+
+		if err = transaction.SavePostureChecks(ctx, store.LockingStrengthUpdate, childPostureCheck); err != nil {
+			return nil, err
+		}
+		postureCheckIDs[postureCheck.ID] = childPostureCheck.ID
+	}
+
+	return postureCheckIDs, nil
+}
+
+// saveChildAccountPolicies saves the policies replacing the child ones with the same name
+func saveChildAccountPolicies(ctx context.Context, transaction store.Store, childAccountID string, policies []*types.Policy, groupIDs, postureCheckIDs map[string]string) error {
+	childPolicies, err := transaction.GetAccountPolicies(ctx, store.LockingStrengthUpdate, childAccountID)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		childPolicy := policy.Copy()
+		childPolicy.AccountID = childAccountID
+		childPolicy.ID = xid.New().String()
+		saveFunc := transaction.CreatePolicy
+
+		idx := slices.IndexFunc(childPolicies, func(p *types.Policy) bool { return p.Name == policy.Name })
+		if idx >= 0 {
+			childPolicy.ID = childPolicies[idx].ID
+			saveFunc = transaction.SavePolicy
+
+			// saving the policy keeps the rules missing from it, the template rules replace all of them
+			if err = transaction.DeletePolicyRules(ctx, store.LockingStrengthUpdate, childPolicy.ID); err != nil {
+				return err
+			}
+		}
+
+		for i, rule := range childPolicy.Rules {
+			rule.ID = childPolicy.ID
+			if i > 0 {
+				rule.ID = fmt.Sprintf("%s-%d", childPolicy.ID, i)
+			}
+			rule.PolicyID = childPolicy.ID
+			rule.Sources = mapChildAccountIDs(rule.Sources, groupIDs)
+			rule.Destinations = mapChildAccountIDs(rule.Destinations, groupIDs)
+		}
+		childPolicy.SourcePostureChecks = mapChildAccountIDs(childPolicy.SourcePostureChecks, postureCheckIDs)
+
+		if err = saveFunc(ctx, store.LockingStrengthUpdate, childPolicy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveChildAccountNameServerGroups saves the nameserver groups replacing the child ones with the same name
+func saveChildAccountNameServerGroups(ctx context.Context, transaction store.Store, childAccountID string, nsGroups []*nbdns.NameServerGroup, groupIDs map[string]string) error {
+	childNSGroups, err := transaction.GetAccountNameServerGroups(ctx, store.LockingStrengthUpdate, childAccountID)
+	if err != nil {
+		return err
+	}
+
+	for _, nsGroup := range nsGroups {
+		childNSGroup := nsGroup.Copy()
+		childNSGroup.AccountID = childAccountID
+		childNSGroup.ID = xid.New().String()
+		childNSGroup.Groups = mapChildAccountIDs(nsGroup.Groups, groupIDs)
+
+		idx := slices.IndexFunc(childNSGroups, func(g *nbdns.NameServerGroup) bool { return g.Name == nsGroup.Name })
+		if idx >= 0 {
+			childNSGroup.ID = childNSGroups[idx].ID
+		}
+
+		if err = transaction.SaveNameServerGroup(ctx, store.LockingStrengthUpdate, childNSGroup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateAccountParent checks the account hierarchy stays a single level deep
+func validateAccountParent(ctx context.Context, transaction store.Store, accountID, parentAccountID string) error {
+	if parentAccountID == accountID {
+		return status.Errorf(status.InvalidArgument, "an account can't be its own parent")
+	}
+
+	grandParentAccountID, err := transaction.GetAccountParentID(ctx, store.LockingStrengthShare, parentAccountID)
+	if err != nil {
+		return err
+	}
+
+	if grandParentAccountID != "" {
+		return status.Errorf(status.InvalidArgument, "the parent account is a child account itself")
+	}
+
+	childAccounts, err := transaction.GetChildAccounts(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return err
+	}
+
+	if len(childAccounts) > 0 {
+		return status.Errorf(status.InvalidArgument, "an account with child accounts can't have a parent account")
+	}
+
+	return nil
+}
+
+// mapChildAccountIDs maps the parent object IDs to the child object IDs dropping the ones without a match
+func mapChildAccountIDs(ids []string, childIDs map[string]string) []string {
+	mapped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if childID, ok := childIDs[id]; ok {
+			mapped = append(mapped, childID)
+		}
+	}
+	return mapped
+}
+
+// GetChildAccountsEvents returns the activity events of all child accounts of the account, newest first
+func (am *DefaultAccountManager) GetChildAccountsEvents(ctx context.Context, accountID, userID string) ([]*activity.Event, error) {
+	childAccounts, err := am.GetChildAccounts(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*activity.Event
+	for _, childAccount := range childAccounts {
+		if childAccount.Pending {
+			continue
+		}
+
+		childEvents, err := am.GetEvents(ctx, childAccount.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, childEvents...)
+	}
+
+	slices.SortStableFunc(events, func(a, b *activity.Event) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+
+	return events, nil
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func TestDefaultAccountManager_ChildAccounts(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err)
+	// the mock permissions manager isn't aware of the account hierarchy
+	manager.permissionsManager = permissions.NewManager(manager.Store)
+
+	ctx := context.Background()
+	parentUserID := "parentUser"
+	childUserID := "childUser"
+	otherUserID := "otherUser"
+
+	parent, err := manager.GetOrCreateAccountByUser(ctx, parentUserID, "")
+	require.NoError(t, err)
+	child, err := manager.GetOrCreateAccountByUser(ctx, childUserID, "")
+	require.NoError(t, err)
+	other, err := manager.GetOrCreateAccountByUser(ctx, otherUserID, "")
+	require.NoError(t, err)
+
+	// the parent admin has no access to the child account before it is linked
+	_, err = manager.GetAllGroups(ctx, child.Id, parentUserID)
+	require.Error(t, err)
+
+	// only the owner of the child account can link it
+	err = manager.SetAccountParent(ctx, child.Id, parentUserID, parent.Id)
+	require.Error(t, err)
+
+	err = manager.SetAccountParent(ctx, child.Id, childUserID, child.Id)
+	require.Error(t, err, "an account can't be its own parent")
+
+	err = manager.SetAccountParent(ctx, child.Id, childUserID, parent.Id)
+	require.NoError(t, err)
+
+	childAccounts, err := manager.GetChildAccounts(ctx, parent.Id, parentUserID)
+	require.NoError(t, err)
+	require.Len(t, childAccounts, 1)
+	assert.Equal(t, child.Id, childAccounts[0].ID)
+	assert.True(t, childAccounts[0].Pending)
+
+	// the link needs the consent of the parent account admins
+	_, err = manager.GetAllGroups(ctx, child.Id, parentUserID)
+	require.Error(t, err, "the parent admin has no access before accepting the child account")
+
+	err = manager.AcceptChildAccount(ctx, parent.Id, childUserID, child.Id)
+	require.Error(t, err, "only the parent admins can accept a child account")
+
+	err = manager.AcceptChildAccount(ctx, parent.Id, parentUserID, other.Id)
+	require.Error(t, err, "an account that didn't ask to be linked can't be accepted")
+
+	err = manager.AcceptChildAccount(ctx, parent.Id, parentUserID, child.Id)
+	require.NoError(t, err)
+
+	err = manager.SetAccountParent(ctx, other.Id, otherUserID, child.Id)
+	require.Error(t, err, "a child account can't be a parent")
+
+	err = manager.SetAccountParent(ctx, parent.Id, parentUserID, other.Id)
+	require.Error(t, err, "a parent account can't be a child")
+
+	// the parent admins can decline a pending child account
+	err = manager.SetAccountParent(ctx, other.Id, otherUserID, parent.Id)
+	require.NoError(t, err)
+	err = manager.RemoveChildAccount(ctx, parent.Id, parentUserID, other.Id)
+	require.NoError(t, err)
+
+	childAccounts, err = manager.GetChildAccounts(ctx, parent.Id, parentUserID)
+	require.NoError(t, err)
+	require.Len(t, childAccounts, 1)
+	assert.Equal(t, child.Id, childAccounts[0].ID)
+	assert.False(t, childAccounts[0].Pending)
+
+	// the parent admin can act within the child account, but not the other way around
+	_, err = manager.GetAllGroups(ctx, child.Id, parentUserID)
+	require.NoError(t, err)
+	_, err = manager.GetAllGroups(ctx, parent.Id, childUserID)
+	require.Error(t, err)
+	_, err = manager.GetAllGroups(ctx, child.Id, otherUserID)
+	require.Error(t, err)
+
+	err = manager.SaveGroup(ctx, parent.Id, parentUserID, &types.Group{ID: "devs", Name: "Devs", Issued: types.GroupIssuedAPI})
+	require.NoError(t, err)
+
+	// a dynamic group depending on another group, the query is copied to the child account
+	err = manager.SaveGroup(ctx, parent.Id, parentUserID, &types.Group{
+		ID:     "linux",
+		Name:   "Linux",
+		Issued: types.GroupIssuedAPI,
+		Query: &types.GroupQuery{Match: types.GroupQueryMatchAny, Rules: []types.GroupQueryRule{
+			{Attribute: types.GroupQueryAttributeOS, Operator: types.GroupQueryOperatorIs, Values: []string{"linux"}},
+			{Attribute: types.GroupQueryAttributeGroup, Operator: types.GroupQueryOperatorIs, Values: []string{"devs"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	childPeer := &nbpeer.Peer{
+		ID:        "childPeer",
+		AccountID: child.Id,
+		Key:       "childPeerKey",
+		IP:        net.IP{100, 64, 0, 1},
+		Meta:      nbpeer.PeerSystemMeta{Hostname: "childPeer", GoOS: "linux"},
+		Status:    &nbpeer.PeerStatus{},
+	}
+	require.NoError(t, manager.Store.AddPeerToAccount(ctx, store.LockingStrengthUpdate, childPeer))
+
+	postureChecks, err := manager.SavePostureChecks(ctx, parent.Id, parentUserID, &posture.Checks{
+		Name: "Min version",
+		Checks: posture.ChecksDefinition{
+			NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.26.0"},
+		},
+	})
+	require.NoError(t, err)
+
+	policy, err := manager.SavePolicy(ctx, parent.Id, parentUserID, &types.Policy{
+		Name:                "Devs to devs",
+		Enabled:             true,
+		SourcePostureChecks: []string{postureChecks.ID},
+		Rules: []*types.PolicyRule{
+			{
+				ID:            "devs-rule",
+				Enabled:       true,
+				Sources:       []string{"devs"},
+				Destinations:  []string{"devs"},
+				Bidirectional: true,
+				Action:        types.PolicyTrafficActionAccept,
+				Protocol:      types.PolicyRuleProtocolALL,
+			},
+			{
+				ID:           "ssh-rule",
+				Enabled:      true,
+				Sources:      []string{"linux"},
+				Destinations: []string{"devs"},
+				Action:       types.PolicyTrafficActionAccept,
+				Protocol:     types.PolicyRuleProtocolTCP,
+				Ports:        []string{"22"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	nsGroup, err := manager.CreateNameServerGroup(ctx, parent.Id, "Company DNS", "", []nbdns.NameServer{{
+		IP:     netip.MustParseAddr("1.1.1.1"),
+		NSType: nbdns.UDPNameServerType,
+		Port:   nbdns.DefaultDNSPort,
+	}}, []string{"devs"}, true, nil, true, parentUserID, false)
+	require.NoError(t, err)
+
+	templates := &types.ChildAccountTemplates{
+		PolicyIDs:          []string{policy.ID},
+		NameServerGroupIDs: []string{nsGroup.ID},
+		ChildAccountIDs:    []string{child.Id, other.Id},
+	}
+
+	_, err = manager.PushTemplatesToChildAccounts(ctx, parent.Id, childUserID, templates)
+	require.Error(t, err, "only the parent admins can push templates")
+
+	results, err := manager.PushTemplatesToChildAccounts(ctx, parent.Id, parentUserID, templates)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err, "the other account isn't a child account")
+
+	childPolicies, err := manager.ListPolicies(ctx, child.Id, childUserID)
+	require.NoError(t, err)
+	idx := slices.IndexFunc(childPolicies, func(p *types.Policy) bool { return p.Name == "Devs to devs" })
+	require.GreaterOrEqual(t, idx, 0)
+	require.Len(t, childPolicies[idx].Rules, 2)
+
+	// pushing the templates again replaces the policy, rules removed from the template are removed from the child policy
+	policy.Rules = policy.Rules[:1]
+	require.NoError(t, manager.Store.DeletePolicyRules(ctx, store.LockingStrengthUpdate, policy.ID))
+	policy, err = manager.SavePolicy(ctx, parent.Id, parentUserID, policy)
+	require.NoError(t, err)
+
+	results, err = manager.PushTemplatesToChildAccounts(ctx, parent.Id, parentUserID, templates)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+
+	childGroups, err := manager.GetAllGroups(ctx, child.Id, childUserID)
+	require.NoError(t, err)
+	var childDevs *types.Group
+	for _, group := range childGroups {
+		if group.Name == "Devs" {
+			childDevs = group
+		}
+	}
+	require.NotNil(t, childDevs)
+	assert.NotEqual(t, "devs", childDevs.ID)
+
+	var childLinux *types.Group
+	for _, group := range childGroups {
+		if group.Name == "Linux" {
+			childLinux = group
+		}
+	}
+	require.NotNil(t, childLinux, "groups referenced by the rules removed later are still created")
+	require.NotNil(t, childLinux.Query, "the dynamic group query should be copied")
+	require.Len(t, childLinux.Query.Rules, 2)
+	assert.Equal(t, []string{childDevs.ID}, childLinux.Query.Rules[1].Values, "referenced groups should be mapped to the child groups")
+	assert.Equal(t, []string{childPeer.ID}, childLinux.Peers, "the dynamic group should be evaluated in the child account")
+
+	childChecks, err := manager.ListPostureChecks(ctx, child.Id, childUserID)
+	require.NoError(t, err)
+	require.Len(t, childChecks, 1)
+	assert.Equal(t, "Min version", childChecks[0].Name)
+
+	childPolicies, err = manager.ListPolicies(ctx, child.Id, childUserID)
+	require.NoError(t, err)
+	var childPolicy *types.Policy
+	for _, p := range childPolicies {
+		if p.Name == "Devs to devs" {
+			assert.Nil(t, childPolicy, "pushing the templates again should replace the policy")
+			childPolicy = p
+		}
+	}
+	require.NotNil(t, childPolicy)
+	assert.Equal(t, []string{childChecks[0].ID}, childPolicy.SourcePostureChecks)
+	require.Len(t, childPolicy.Rules, 1)
+	assert.Equal(t, childPolicy.ID, childPolicy.Rules[0].ID)
+	assert.Equal(t, []string{childDevs.ID}, childPolicy.Rules[0].Sources)
+	assert.Equal(t, []string{childDevs.ID}, childPolicy.Rules[0].Destinations)
+
+	childNSGroups, err := manager.ListNameServerGroups(ctx, child.Id, childUserID)
+	require.NoError(t, err)
+	require.Len(t, childNSGroups, 1)
+	assert.Equal(t, []string{childDevs.ID}, childNSGroups[0].Groups)
+
+	err = manager.RemoveChildAccount(ctx, parent.Id, parentUserID, other.Id)
+	require.Error(t, err)
+
+	err = manager.RemoveChildAccount(ctx, parent.Id, parentUserID, child.Id)
+	require.NoError(t, err)
+
+	_, err = manager.GetAllGroups(ctx, child.Id, parentUserID)
+	require.Error(t, err)
+
+	childAccounts, err = manager.GetChildAccounts(ctx, parent.Id, parentUserID)
+	require.NoError(t, err)
+	assert.Empty(t, childAccounts)
+}
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// GetInventoryDevices returns the devices of the account inventory ordered by serial number.
+func (am *DefaultAccountManager) GetInventoryDevices(ctx context.Context, accountID, userID string) ([]*types.InventoryDevice, error) {
+	if err := am.validateInventoryAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	return am.Store.GetAccountInventoryDevices(ctx, store.LockingStrengthShare, accountID)
+}
+
+// GetInventoryDevice returns a device of the account inventory.
+func (am *DefaultAccountManager) GetInventoryDevice(ctx context.Context, accountID, userID, deviceID string) (*types.InventoryDevice, error) {
+	if err := am.validateInventoryAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	return am.Store.GetInventoryDeviceByID(ctx, store.LockingStrengthShare, accountID, deviceID)
+}
+
+// SaveInventoryDevice creates a device of the account inventory when the ID is empty, otherwise updates the existing one.
+func (am *DefaultAccountManager) SaveInventoryDevice(ctx context.Context, accountID, userID string, device *types.InventoryDevice) (*types.InventoryDevice, error) {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := am.validateInventoryAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	device.SerialNumber = types.NormalizeSerialNumber(device.SerialNumber)
+	if err := device.Validate(); err != nil {
+		return nil, err
+	}
+
+	var isUpdate = device.ID != ""
+	var action = activity.InventoryDeviceAdded
+	var updateAccountPeers bool
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := validateInventoryDeviceOwner(ctx, transaction, accountID, device.UserID); err != nil {
+			return err
+		}
+
+		existing, err := transaction.GetInventoryDeviceBySerialNumber(ctx, store.LockingStrengthUpdate, accountID, device.SerialNumber)
+		if err != nil && !isNotFoundError(err) {
+			return err
+		}
+		if existing != nil && existing.ID != device.ID {
+			return status.Errorf(status.AlreadyExists, "device with serial number %s already exists in the inventory", device.SerialNumber)
+		}
+
+		if isUpdate {
+			oldDevice, err := transaction.GetInventoryDeviceByID(ctx, store.LockingStrengthUpdate, accountID, device.ID)
+			if err != nil {
+				return err
+			}
+			device.CreatedAt = oldDevice.CreatedAt
+			action = activity.InventoryDeviceUpdated
+		} else {
+			created := types.NewInventoryDevice(accountID, device.SerialNumber, device.Manufacturer, device.ProductName, device.UserID, device.Description)
+			device.ID = created.ID
+			device.CreatedAt = created.CreatedAt
+		}
+		device.AccountID = accountID
+
+		updateAccountPeers, err = anyDeviceInventoryPostureCheck(ctx, transaction, accountID)
+		if err != nil {
+			return err
+		}
+
+		return transaction.SaveInventoryDevice(ctx, store.LockingStrengthUpdate, device)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	am.StoreEvent(ctx, userID, device.ID, accountID, action, device.EventMeta())
+
+	if updateAccountPeers {
+		am.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return device, nil
+}
+
+// DeleteInventoryDevice deletes a device from the account inventory.
+func (am *DefaultAccountManager) DeleteInventoryDevice(ctx context.Context, accountID, userID, deviceID string) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := am.validateInventoryAdmin(ctx, accountID, userID); err != nil {
+		return err
+	}
+
+	var device *types.InventoryDevice
+	var updateAccountPeers bool
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		device, err = transaction.GetInventoryDeviceByID(ctx, store.LockingStrengthUpdate, accountID, deviceID)
+		if err != nil {
+			return err
+		}
+
+		updateAccountPeers, err = anyDeviceInventoryPostureCheck(ctx, transaction, accountID)
+		if err != nil {
+			return err
+		}
+
+		return transaction.DeleteInventoryDevice(ctx, store.LockingStrengthUpdate, accountID, deviceID)
+	})
+	if err != nil {
+		return err
+	}
+
+	am.StoreEvent(ctx, userID, deviceID, accountID, activity.InventoryDeviceDeleted, device.EventMeta())
+
+	if updateAccountPeers {
+		am.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return nil
+}
+
+// ImportInventoryDevices adds the devices to the account inventory. Devices already in the inventory are matched by
+// serial number and updated. A device that fails validation doesn't stop the import, its error is reported in the
+// summary results, keyed by the serial number.
+func (am *DefaultAccountManager) ImportInventoryDevices(ctx context.Context, accountID, userID string, devices []*types.InventoryDevice) (*types.InventoryImportSummary, error) {
+	if len(devices) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "no devices to import")
+	}
+	if len(devices) > types.MaxInventoryImportDevices {
+		return nil, status.Errorf(status.InvalidArgument, "an inventory import can contain at most %d devices", types.MaxInventoryImportDevices)
+	}
+
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := am.validateInventoryAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	summary := &types.InventoryImportSummary{Results: make([]*types.BulkItemResult, 0, len(devices))}
+	var updateAccountPeers bool
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		existing, err := transaction.GetAccountInventoryDevices(ctx, store.LockingStrengthUpdate, accountID)
+		if err != nil {
+			return err
+		}
+
+		bySerial := make(map[string]*types.InventoryDevice, len(existing))
+		for _, device := range existing {
+			bySerial[device.SerialNumber] = device
+		}
+
+		for _, device := range devices {
+			device.SerialNumber = types.NormalizeSerialNumber(device.SerialNumber)
+			result := &types.BulkItemResult{ID: device.SerialNumber}
+			summary.Results = append(summary.Results, result)
+
+			if result.Err = device.Validate(); result.Err != nil {
+				continue
+			}
+			if result.Err = validateInventoryDeviceOwner(ctx, transaction, accountID, device.UserID); result.Err != nil {
+				continue
+			}
+
+			toSave, ok := bySerial[device.SerialNumber]
+			if ok {
+				toSave.Manufacturer = device.Manufacturer
+				toSave.ProductName = device.ProductName
+				toSave.UserID = device.UserID
+				toSave.Description = device.Description
+			} else {
+				toSave = types.NewInventoryDevice(accountID, device.SerialNumber, device.Manufacturer, device.ProductName, device.UserID, device.Description)
+			}
+
+			if err = transaction.SaveInventoryDevice(ctx, store.LockingStrengthUpdate, toSave); err != nil {
+				return err
+			}
+
+			if ok {
+				summary.Updated++
+			} else {
+				summary.Created++
+				bySerial[toSave.SerialNumber] = toSave
+			}
+		}
+
+		updateAccountPeers, err = anyDeviceInventoryPostureCheck(ctx, transaction, accountID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.Created+summary.Updated > 0 {
+		am.StoreEvent(ctx, userID, accountID, accountID, activity.InventoryDevicesImported, summary.EventMeta())
+
+		if updateAccountPeers {
+			am.UpdateAccountPeers(ctx, accountID)
+		}
+	}
+
+	return summary, nil
+}
+
+// GetInventoriedSerialNumbers returns the serial numbers of the account inventory devices.
+// It doesn't check user permissions, callers are expected to have validated access to the account peers.
+func (am *DefaultAccountManager) GetInventoriedSerialNumbers(ctx context.Context, accountID string) (map[string]struct{}, error) {
+	devices, err := am.Store.GetAccountInventoryDevices(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumbers := make(map[string]struct{}, len(devices))
+	for _, device := range devices {
+		serialNumbers[device.SerialNumber] = struct{}{}
+	}
+
+	return serialNumbers, nil
+}
+
+func (am *DefaultAccountManager) validateInventoryAdmin(ctx context.Context, accountID, userID string) error {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return err
+	}
+
+	if !user.HasAdminPower() {
+		return status.NewAdminPermissionError()
+	}
+
+	return nil
+}
+
+// validateInventoryDeviceOwner checks the device owner, when set, is a user of the account.
+func validateInventoryDeviceOwner(ctx context.Context, transaction store.Store, accountID, ownerID string) error {
+	if ownerID == "" {
+		return nil
+	}
+
+	owner, err := transaction.GetUserByUserID(ctx, store.LockingStrengthShare, ownerID)
+	if err != nil {
+		if isNotFoundError(err) {
+			return status.Errorf(status.InvalidArgument, "device owner %s not found", ownerID)
+		}
+		return err
+	}
+
+	if owner.AccountID != accountID {
+		return status.Errorf(status.InvalidArgument, "device owner %s not found", ownerID)
+	}
+
+	return nil
+}
+
+// anyDeviceInventoryPostureCheck returns true when a posture check of the account depends on the inventory,
+// meaning inventory changes can change the account peers network maps.
+func anyDeviceInventoryPostureCheck(ctx context.Context, transaction store.Store, accountID string) (bool, error) {
+	checks, err := transaction.GetAccountPostureChecks(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, check := range checks {
+		if check.Checks.DeviceInventoryCheck != nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func isNotFoundError(err error) bool {
+	var sErr *status.Error
+	return errors.As(err, &sErr) && sErr.Type() == status.NotFound
+}
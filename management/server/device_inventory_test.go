@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func TestDefaultAccountManager_InventoryDevices(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	adminUserID := "account_creator"
+	account, err := createAccount(manager, "test_account", adminUserID, "")
+	require.NoError(t, err)
+
+	account.Users["regular_user"] = types.NewRegularUser("regular_user")
+	require.NoError(t, manager.Store.SaveAccount(ctx, account))
+
+	t.Run("regular users can't manage the inventory", func(t *testing.T) {
+		_, err := manager.GetInventoryDevices(ctx, account.Id, "regular_user")
+		assertErrorType(t, status.PermissionDenied, err)
+
+		_, err = manager.SaveInventoryDevice(ctx, account.Id, "regular_user", &types.InventoryDevice{SerialNumber: "SN-1"})
+		assertErrorType(t, status.PermissionDenied, err)
+	})
+
+	t.Run("device requires a serial number and a known owner", func(t *testing.T) {
+		_, err := manager.SaveInventoryDevice(ctx, account.Id, adminUserID, &types.InventoryDevice{SerialNumber: " "})
+		assertErrorType(t, status.InvalidArgument, err)
+
+		_, err = manager.SaveInventoryDevice(ctx, account.Id, adminUserID, &types.InventoryDevice{SerialNumber: "SN-1", UserID: "missing"})
+		assertErrorType(t, status.InvalidArgument, err)
+	})
+
+	device, err := manager.SaveInventoryDevice(ctx, account.Id, adminUserID, &types.InventoryDevice{
+		SerialNumber: " sn-1 ",
+		Manufacturer: "Lenovo",
+		UserID:       "regular_user",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, device.ID)
+	assert.Equal(t, "SN-1", device.SerialNumber)
+
+	t.Run("serial numbers are unique", func(t *testing.T) {
+		_, err := manager.SaveInventoryDevice(ctx, account.Id, adminUserID, &types.InventoryDevice{SerialNumber: "SN-1"})
+		assertErrorType(t, status.AlreadyExists, err)
+	})
+
+	t.Run("update device", func(t *testing.T) {
+		updated, err := manager.SaveInventoryDevice(ctx, account.Id, adminUserID, &types.InventoryDevice{
+			ID:           device.ID,
+			SerialNumber: "SN-1",
+			Description:  "office laptop",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, device.CreatedAt.Unix(), updated.CreatedAt.Unix())
+
+		got, err := manager.GetInventoryDevice(ctx, account.Id, adminUserID, device.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "office laptop", got.Description)
+		assert.Empty(t, got.UserID)
+	})
+
+	t.Run("import creates and updates devices by serial number", func(t *testing.T) {
+		summary, err := manager.ImportInventoryDevices(ctx, account.Id, adminUserID, []*types.InventoryDevice{
+			{SerialNumber: "sn-1", UserID: adminUserID},
+			{SerialNumber: "SN-2"},
+			{SerialNumber: ""},
+			{SerialNumber: "SN-3", UserID: "missing"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Created)
+		assert.Equal(t, 1, summary.Updated)
+		assert.Equal(t, 2, summary.Failed())
+		require.Len(t, summary.Results, 4)
+		assert.Equal(t, "SN-1", summary.Results[0].ID)
+		assert.NoError(t, summary.Results[1].Err)
+		assert.Error(t, summary.Results[3].Err)
+
+		devices, err := manager.GetInventoryDevices(ctx, account.Id, adminUserID)
+		require.NoError(t, err)
+		require.Len(t, devices, 2)
+		assert.Equal(t, "SN-1", devices[0].SerialNumber)
+		assert.Equal(t, adminUserID, devices[0].UserID)
+		assert.Equal(t, "SN-2", devices[1].SerialNumber)
+
+		serials, err := manager.GetInventoriedSerialNumbers(ctx, account.Id)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]struct{}{"SN-1": {}, "SN-2": {}}, serials)
+	})
+
+	t.Run("delete device", func(t *testing.T) {
+		require.NoError(t, manager.DeleteInventoryDevice(ctx, account.Id, adminUserID, device.ID))
+
+		_, err := manager.GetInventoryDevice(ctx, account.Id, adminUserID, device.ID)
+		assertErrorType(t, status.NotFound, err)
+	})
+}
+
+func TestDefaultAccountManager_SetupKeyInventoryDevicesOnly(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	adminUserID := "account_creator"
+	account, err := createAccount(manager, "test_account", adminUserID, "")
+	require.NoError(t, err)
+
+	setupKey, err := manager.CreateSetupKey(ctx, account.Id, "inventory", types.SetupKeyReusable, time.Hour, nil, 999, adminUserID, false, false,
+		types.SetupKeyConstraints{InventoryDevicesOnly: true})
+	require.NoError(t, err)
+	assert.True(t, setupKey.Constraints.InventoryDevicesOnly)
+
+	_, err = manager.SaveInventoryDevice(ctx, account.Id, adminUserID, &types.InventoryDevice{SerialNumber: "SN-1"})
+	require.NoError(t, err)
+
+	addPeer := func(serialNumber string) error {
+		key, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		_, _, _, err = manager.AddPeer(ctx, setupKey.Key, "", &nbpeer.Peer{
+			Key:  key.PublicKey().String(),
+			Meta: nbpeer.PeerSystemMeta{Hostname: "laptop", SystemSerialNumber: serialNumber},
+		})
+		return err
+	}
+
+	assert.NoError(t, addPeer("sn-1"), "inventoried device should register")
+	assertErrorType(t, status.PermissionDenied, addPeer("SN-2"), "unknown device shouldn't register")
+	assertErrorType(t, status.PermissionDenied, addPeer(""), "device without serial number shouldn't register")
+}
@@ -65,16 +65,20 @@ func (am *DefaultAccountManager) GetEvents(ctx context.Context, accountID, userI
 }
 
 func (am *DefaultAccountManager) StoreEvent(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+	event := &activity.Event{
+		Timestamp:   time.Now().UTC(),
+		Activity:    activityID,
+		InitiatorID: initiatorID,
+		TargetID:    targetID,
+		AccountID:   accountID,
+		Meta:        meta,
+	}
+
+	am.publishActivityEvent(ctx, event)
+
 	if isEnabled() {
 		go func() {
-			_, err := am.eventStore.Save(ctx, &activity.Event{
-				Timestamp:   time.Now().UTC(),
-				Activity:    activityID,
-				InitiatorID: initiatorID,
-				TargetID:    targetID,
-				AccountID:   accountID,
-				Meta:        meta,
-			})
+			_, err := am.eventStore.Save(ctx, event)
 			if err != nil {
 				// todo add metric
 				log.WithContext(ctx).Errorf("received an error while storing an activity event, error: %s", err)
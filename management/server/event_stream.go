@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/eventstream"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+)
+
+// SubscribeEvents subscribes the user to the real-time events of the account.
+// The subscription has to be released with UnsubscribeEvents.
+func (am *DefaultAccountManager) SubscribeEvents(ctx context.Context, accountID, userID string, filter eventstream.Filter, lastEventID uint64) (*eventstream.Subscription, error) {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, err
+	}
+
+	if !(user.HasAdminPower() || user.IsServiceUser) {
+		return nil, status.Errorf(status.PermissionDenied, "only users with admin power can subscribe to events")
+	}
+
+	return am.eventBroker.Subscribe(accountID, filter, lastEventID), nil
+}
+
+// UnsubscribeEvents stops delivering events to the subscription
+func (am *DefaultAccountManager) UnsubscribeEvents(subscription *eventstream.Subscription) {
+	am.eventBroker.Unsubscribe(subscription)
+}
+
+// publishPeerStatusEvent streams the change of the peer connection status
+func (am *DefaultAccountManager) publishPeerStatusEvent(ctx context.Context, accountID string, peer *nbpeer.Peer, connected bool) {
+	eventType := eventstream.PeerDisconnected
+	if connected {
+		eventType = eventstream.PeerConnected
+	}
+
+	am.publishStreamEvent(ctx, &eventstream.Event{
+		Type:      eventType,
+		AccountID: accountID,
+		PeerID:    peer.ID,
+		Data:      peer.EventMeta(am.GetDNSDomain()),
+	}, peer.ID)
+}
+
+// publishActivityEvent streams the activity event, the event type is the activity code.
+// The event gets its ID before returning, so the IDs follow the order of the changes, and is published in the background.
+func (am *DefaultAccountManager) publishActivityEvent(ctx context.Context, event *activity.Event) {
+	if am.eventBroker == nil {
+		return
+	}
+
+	data := map[string]any{
+		"activity":     event.Activity.Message(),
+		"initiator_id": event.InitiatorID,
+		"target_id":    event.TargetID,
+	}
+	for k, v := range event.Meta {
+		data[k] = v
+	}
+
+	streamEvent := &eventstream.Event{
+		Type:      event.Activity.StringCode(),
+		AccountID: event.AccountID,
+		Timestamp: event.Timestamp,
+		Data:      data,
+	}
+	am.eventBroker.AssignID(streamEvent)
+
+	// the request context may be canceled before the event is published
+	go am.publishStreamEvent(context.WithoutCancel(ctx), streamEvent, event.TargetID)
+}
+
+// publishStreamEvent fills the groups of the given peer in the event and publishes it.
+// The event is kept in the history also when the account has no subscribers, so a reconnecting subscriber can resume from it.
+// For events that aren't related to a peer no groups are found and the event only matches subscriptions without a group filter.
+func (am *DefaultAccountManager) publishStreamEvent(ctx context.Context, event *eventstream.Event, peerID string) {
+	if am.eventBroker == nil {
+		return
+	}
+
+	if peerID != "" {
+		groups, err := am.Store.GetPeerGroups(ctx, store.LockingStrengthShare, event.AccountID, peerID)
+		if err != nil {
+			log.WithContext(ctx).Debugf("failed to get groups of peer %s for the event stream: %v", peerID, err)
+		}
+		for _, group := range groups {
+			event.GroupIDs = append(event.GroupIDs, group.ID)
+		}
+	}
+
+	am.eventBroker.Publish(event)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/eventstream"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func TestDefaultAccountManager_SubscribeEvents(t *testing.T) {
+	manager, account, peer1, peer2, _ := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	group := &types.Group{ID: "stream", Name: "stream", Issued: types.GroupIssuedAPI, Peers: []string{peer1.ID}}
+	require.NoError(t, manager.SaveGroup(ctx, account.Id, userID, group))
+
+	_, err := manager.SubscribeEvents(ctx, "otherAccount", userID, eventstream.Filter{}, 0)
+	require.Error(t, err)
+
+	all, err := manager.SubscribeEvents(ctx, account.Id, userID, eventstream.Filter{}, 0)
+	require.NoError(t, err)
+	defer manager.UnsubscribeEvents(all)
+
+	grouped, err := manager.SubscribeEvents(ctx, account.Id, userID, eventstream.Filter{
+		Types:    []string{eventstream.PeerConnected},
+		GroupIDs: []string{group.ID},
+	}, 0)
+	require.NoError(t, err)
+	defer manager.UnsubscribeEvents(grouped)
+
+	require.NoError(t, manager.MarkPeerConnected(ctx, peer2.Key, true, nil, account.Id))
+	require.NoError(t, manager.MarkPeerConnected(ctx, peer1.Key, true, nil, account.Id))
+
+	event := receiveStreamEvent(t, all)
+	assert.Equal(t, eventstream.PeerConnected, event.Type)
+	assert.Equal(t, peer2.ID, event.PeerID)
+
+	event = receiveStreamEvent(t, grouped)
+	assert.Equal(t, peer1.ID, event.PeerID)
+	assert.Contains(t, event.GroupIDs, group.ID)
+
+	manager.StoreEvent(ctx, userID, peer1.ID, account.Id, activity.PeerApproved, map[string]any{"name": peer1.Name})
+
+	// skip the connection event of peer1
+	receiveStreamEvent(t, all)
+	event = receiveStreamEvent(t, all)
+	assert.Equal(t, activity.PeerApproved.StringCode(), event.Type)
+	assert.Equal(t, peer1.Name, event.Data["name"])
+	assert.Contains(t, event.GroupIDs, group.ID)
+}
+
+func receiveStreamEvent(t *testing.T, subscription *eventstream.Subscription) *eventstream.Event {
+	t.Helper()
+
+	select {
+	case event := <-subscription.Events():
+		require.NotNil(t, event)
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for a stream event")
+		return nil
+	}
+}
+
+func TestDefaultAccountManager_SubscribeEventsResumesAfterGap(t *testing.T) {
+	manager, account, peer1, _, _ := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	subscription, err := manager.SubscribeEvents(ctx, account.Id, userID, eventstream.Filter{}, 0)
+	require.NoError(t, err)
+	manager.StoreEvent(ctx, userID, peer1.ID, account.Id, activity.PeerApproved, nil)
+	lastID := receiveStreamEvent(t, subscription).ID
+	manager.UnsubscribeEvents(subscription)
+
+	// the events of the gap are stored while nobody is subscribed, their request is done before they are published
+	reqCtx, cancel := context.WithCancel(ctx)
+	manager.StoreEvent(reqCtx, userID, peer1.ID, account.Id, activity.PeerRenamed, nil)
+	manager.StoreEvent(reqCtx, userID, peer1.ID, account.Id, activity.PeerSSHEnabled, nil)
+	cancel()
+
+	var replay []*eventstream.Event
+	require.Eventually(t, func() bool {
+		subscription, err := manager.SubscribeEvents(ctx, account.Id, userID, eventstream.Filter{}, lastID)
+		require.NoError(t, err)
+		defer manager.UnsubscribeEvents(subscription)
+
+		replay = subscription.Replay()
+		return len(replay) == 2
+	}, time.Second, 10*time.Millisecond, "events stored without subscribers should be replayed")
+
+	assert.Equal(t, activity.PeerRenamed.StringCode(), replay[0].Type)
+	assert.Equal(t, activity.PeerSSHEnabled.StringCode(), replay[1].Type)
+	assert.NotEmpty(t, replay[0].GroupIDs, "groups of the peer should be filled")
+}
@@ -0,0 +1,227 @@
+package eventstream
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// PeerConnected is the type of the event sent when a peer opens its updates stream
+	PeerConnected = "peer.connected"
+	// PeerDisconnected is the type of the event sent when the updates stream of a peer is closed
+	PeerDisconnected = "peer.disconnected"
+
+	// DefaultHistorySize is the number of the latest events kept per account to resume interrupted subscriptions
+	DefaultHistorySize = 1000
+
+	subscriptionBufferSize = 100
+)
+
+// Event is a single change in an account streamed to the subscribers
+type Event struct {
+	// ID increases monotonically, it is used to resume a subscription from the last received event
+	ID        uint64
+	Type      string
+	AccountID string
+	Timestamp time.Time
+	// PeerID is the peer the event is related to, if any
+	PeerID string
+	// GroupIDs are the groups of the peer the event is related to when it was published
+	GroupIDs []string
+	Data     map[string]any
+}
+
+// Filter selects the events delivered to a subscription, an empty filter matches every event
+type Filter struct {
+	Types    []string
+	GroupIDs []string
+}
+
+func (f Filter) matches(event *Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+
+	if len(f.GroupIDs) > 0 && !slices.ContainsFunc(event.GroupIDs, func(groupID string) bool {
+		return slices.Contains(f.GroupIDs, groupID)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// Subscription receives the events of a single account
+type Subscription struct {
+	accountID string
+	filter    Filter
+	events    chan *Event
+	// fromID is the last event ID before the subscription, only later events are delivered
+	fromID uint64
+	// replay holds the events published before the subscription that were missed since the last received event
+	replay []*Event
+	closed bool
+}
+
+// Replay returns the events missed since the last event ID the subscription was created with
+func (s *Subscription) Replay() []*Event {
+	return s.replay
+}
+
+// Events returns the channel the new events are delivered to.
+// The channel is closed when the subscriber can't keep up, the subscriber is expected to resume from the last received event.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Broker fans out the events published by the management service to the subscriptions of every account
+type Broker struct {
+	mu     sync.Mutex
+	lastID uint64
+	// history keeps the latest events of every account ordered by ID, also while the account has no subscribers
+	history       map[string][]*Event
+	historySize   int
+	subscriptions map[string]map[*Subscription]struct{}
+}
+
+// NewBroker returns a broker keeping the given number of the latest events of every account for resuming subscriptions
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		// IDs start from the current time to keep them increasing across restarts, so a stale ID replays the history
+		lastID:        uint64(time.Now().UnixNano()),
+		history:       make(map[string][]*Event),
+		historySize:   historySize,
+		subscriptions: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// HasSubscribers returns true if there is at least one subscription for the account
+func (b *Broker) HasSubscribers(accountID string) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscriptions[accountID]) > 0
+}
+
+// AssignID gives the event the next ID.
+// Publishers preparing the event asynchronously assign the ID first, so the IDs follow the order of the changes.
+func (b *Broker) AssignID(event *Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.assignID(event)
+}
+
+func (b *Broker) assignID(event *Event) {
+	b.lastID++
+	event.ID = b.lastID
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+}
+
+// Publish keeps the event in the history of its account and delivers it to the matching subscriptions.
+// Events without an ID are assigned one.
+func (b *Broker) Publish(event *Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.ID == 0 {
+		b.assignID(event)
+	}
+
+	b.addToHistory(event)
+
+	for sub := range b.subscriptions[event.AccountID] {
+		if event.ID <= sub.fromID || !sub.filter.matches(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			b.unsubscribe(sub)
+		}
+	}
+}
+
+func (b *Broker) addToHistory(event *Event) {
+	history := b.history[event.AccountID]
+
+	// events prepared asynchronously can be published out of order
+	i, _ := slices.BinarySearchFunc(history, event.ID, func(e *Event, id uint64) int {
+		return cmp.Compare(e.ID, id)
+	})
+	history = slices.Insert(history, i, event)
+
+	if len(history) > b.historySize {
+		history = history[len(history)-b.historySize:]
+	}
+	b.history[event.AccountID] = history
+}
+
+// Subscribe creates a subscription for the events of the account matching the filter.
+// When lastEventID is not zero, the kept events published after it are returned by Subscription.Replay.
+func (b *Broker) Subscribe(accountID string, filter Filter, lastEventID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		accountID: accountID,
+		filter:    filter,
+		events:    make(chan *Event, subscriptionBufferSize),
+		fromID:    b.lastID,
+	}
+
+	if lastEventID != 0 {
+		// events of the gap that aren't published yet are delivered when they are
+		sub.fromID = lastEventID
+		for _, event := range b.history[accountID] {
+			if event.ID > lastEventID && filter.matches(event) {
+				sub.replay = append(sub.replay, event)
+			}
+		}
+	}
+
+	if b.subscriptions[accountID] == nil {
+		b.subscriptions[accountID] = make(map[*Subscription]struct{})
+	}
+	b.subscriptions[accountID][sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe stops delivering events to the subscription and closes its channel
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.unsubscribe(sub)
+}
+
+func (b *Broker) unsubscribe(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.events)
+
+	delete(b.subscriptions[sub.accountID], sub)
+	if len(b.subscriptions[sub.accountID]) == 0 {
+		delete(b.subscriptions, sub.accountID)
+	}
+}
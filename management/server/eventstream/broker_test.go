@@ -0,0 +1,121 @@
+package eventstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_PublishFiltersEvents(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+
+	all := broker.Subscribe("account", Filter{}, 0)
+	connected := broker.Subscribe("account", Filter{Types: []string{PeerConnected}}, 0)
+	devs := broker.Subscribe("account", Filter{GroupIDs: []string{"devs"}}, 0)
+	other := broker.Subscribe("other", Filter{}, 0)
+
+	assert.True(t, broker.HasSubscribers("account"))
+	assert.False(t, broker.HasSubscribers("missing"))
+
+	broker.Publish(&Event{Type: PeerConnected, AccountID: "account", PeerID: "peer1", GroupIDs: []string{"all"}})
+	broker.Publish(&Event{Type: PeerDisconnected, AccountID: "account", PeerID: "peer2", GroupIDs: []string{"all", "devs"}})
+
+	require.Len(t, all.Events(), 2)
+	require.Len(t, connected.Events(), 1)
+	assert.Equal(t, "peer1", (<-connected.Events()).PeerID)
+	require.Len(t, devs.Events(), 1)
+	assert.Equal(t, "peer2", (<-devs.Events()).PeerID)
+	assert.Len(t, other.Events(), 0)
+
+	first, second := <-all.Events(), <-all.Events()
+	assert.Greater(t, second.ID, first.ID)
+	assert.False(t, first.Timestamp.IsZero())
+}
+
+func TestBroker_SubscribeReplaysMissedEvents(t *testing.T) {
+	broker := NewBroker(2)
+
+	for i := 0; i < 3; i++ {
+		broker.Publish(&Event{Type: PeerConnected, AccountID: "account"})
+	}
+	broker.Publish(&Event{Type: PeerConnected, AccountID: "other"})
+
+	sub := broker.Subscribe("account", Filter{}, 0)
+	assert.Empty(t, sub.Replay(), "nothing is replayed without a last event ID")
+	broker.Unsubscribe(sub)
+
+	// the history keeps the two latest events of every account
+	sub = broker.Subscribe("account", Filter{}, 1)
+	require.Len(t, sub.Replay(), 2)
+	lastID := sub.Replay()[1].ID
+	broker.Unsubscribe(sub)
+
+	sub = broker.Subscribe("other", Filter{}, 1)
+	assert.Len(t, sub.Replay(), 1, "events of another account shouldn't evict the account's history")
+	broker.Unsubscribe(sub)
+
+	sub = broker.Subscribe("account", Filter{}, lastID)
+	assert.Empty(t, sub.Replay())
+}
+
+func TestBroker_ReplayKeepsAssignedOrder(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+
+	first := &Event{Type: PeerConnected, AccountID: "account"}
+	second := &Event{Type: PeerDisconnected, AccountID: "account"}
+	broker.AssignID(first)
+	broker.AssignID(second)
+
+	// the first event is prepared slower and published last
+	broker.Publish(second)
+	broker.Publish(first)
+
+	sub := broker.Subscribe("account", Filter{}, 1)
+	require.Len(t, sub.Replay(), 2)
+	assert.Equal(t, first.ID, sub.Replay()[0].ID)
+	assert.Equal(t, second.ID, sub.Replay()[1].ID)
+}
+
+func TestBroker_DropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+	sub := broker.Subscribe("account", Filter{}, 0)
+
+	for i := 0; i < subscriptionBufferSize+1; i++ {
+		broker.Publish(&Event{Type: PeerConnected, AccountID: "account"})
+	}
+
+	assert.False(t, broker.HasSubscribers("account"))
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, subscriptionBufferSize, received)
+
+	// unsubscribing a dropped subscription is a no-op
+	broker.Unsubscribe(sub)
+}
+
+func TestBroker_NilBrokerIsNoop(t *testing.T) {
+	var broker *Broker
+	assert.False(t, broker.HasSubscribers("account"))
+	broker.AssignID(&Event{AccountID: "account"})
+	broker.Publish(&Event{AccountID: "account"})
+}
+
+func TestBroker_SubscribeSkipsEarlierEvents(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+
+	pending := &Event{Type: PeerConnected, AccountID: "account"}
+	broker.AssignID(pending)
+
+	fresh := broker.Subscribe("account", Filter{}, 0)
+	resumed := broker.Subscribe("account", Filter{}, pending.ID-1)
+
+	broker.Publish(pending)
+
+	assert.Len(t, fresh.Events(), 0, "events assigned before a new subscription shouldn't be delivered")
+	require.Len(t, resumed.Events(), 1, "events of the gap published late should be delivered")
+	assert.Equal(t, pending.ID, (<-resumed.Events()).ID)
+}
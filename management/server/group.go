@@ -12,6 +12,7 @@ import (
 	nbdns "github.com/netbirdio/netbird/dns"
 	"github.com/netbirdio/netbird/management/server/activity"
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -62,6 +63,29 @@ func (am *DefaultAccountManager) GetAllGroups(ctx context.Context, accountID, us
 	return am.Store.GetAccountGroups(ctx, store.LockingStrengthShare, accountID)
 }
 
+// PreviewGroupQuery returns the peers of the account that would be members of a dynamic group with the given query
+func (am *DefaultAccountManager) PreviewGroupQuery(ctx context.Context, accountID, userID string, query *types.GroupQuery) ([]*nbpeer.Peer, error) {
+	if err := am.CheckGroupPermissions(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	groups, err := am.Store.GetAccountGroups(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := am.Store.GetAccountPeers(ctx, store.LockingStrengthShare, accountID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return query.MatchingPeers(groups, peers), nil
+}
+
 // GetGroupByName filters all groups in an account by name and returns the one with the most peers
 func (am *DefaultAccountManager) GetGroupByName(ctx context.Context, groupName, accountID string) (*types.Group, error) {
 	return am.Store.GetGroupByName(ctx, store.LockingStrengthShare, accountID, groupName)
@@ -69,7 +93,10 @@ func (am *DefaultAccountManager) GetGroupByName(ctx context.Context, groupName,
 
 // SaveGroup object of the peers
 func (am *DefaultAccountManager) SaveGroup(ctx context.Context, accountID, userID string, newGroup *types.Group) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 	return am.SaveGroups(ctx, accountID, userID, []*types.Group{newGroup})
 }
@@ -102,6 +129,13 @@ func (am *DefaultAccountManager) SaveGroups(ctx context.Context, accountID, user
 				return err
 			}
 
+			if newGroup.IsDynamic() {
+				newGroup.Peers, err = getDynamicGroupPeers(ctx, transaction, accountID, newGroup.Query)
+				if err != nil {
+					return err
+				}
+			}
+
 			newGroup.AccountID = accountID
 			groupsToSave = append(groupsToSave, newGroup)
 			groupIDs = append(groupIDs, newGroup.ID)
@@ -110,16 +144,22 @@ func (am *DefaultAccountManager) SaveGroups(ctx context.Context, accountID, user
 			eventsToStore = append(eventsToStore, events...)
 		}
 
-		updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, groupIDs)
+		if err = transaction.SaveGroups(ctx, store.LockingStrengthUpdate, groupsToSave); err != nil {
+			return err
+		}
+
+		refreshedGroupIDs, err := refreshDynamicGroups(ctx, transaction, accountID)
 		if err != nil {
 			return err
 		}
+		groupIDs = append(groupIDs, refreshedGroupIDs...)
 
-		if err = transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID); err != nil {
+		updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, groupIDs)
+		if err != nil {
 			return err
 		}
 
-		return transaction.SaveGroups(ctx, store.LockingStrengthUpdate, groupsToSave)
+		return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
 	})
 	if err != nil {
 		return err
@@ -198,7 +238,10 @@ func (am *DefaultAccountManager) prepareGroupEvents(ctx context.Context, transac
 
 // DeleteGroup object of the peers.
 func (am *DefaultAccountManager) DeleteGroup(ctx context.Context, accountID, userID, groupID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 	return am.DeleteGroups(ctx, accountID, userID, []string{groupID})
 }
@@ -263,12 +306,14 @@ func (am *DefaultAccountManager) DeleteGroups(ctx context.Context, accountID, us
 
 // GroupAddPeer appends peer to the group
 func (am *DefaultAccountManager) GroupAddPeer(ctx context.Context, accountID, groupID, peerID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var group *types.Group
 	var updateAccountPeers bool
-	var err error
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		group, err = transaction.GetGroupByID(context.Background(), store.LockingStrengthUpdate, accountID, groupID)
@@ -276,20 +321,29 @@ func (am *DefaultAccountManager) GroupAddPeer(ctx context.Context, accountID, gr
 			return err
 		}
 
+		if group.IsDynamic() {
+			return status.Errorf(status.InvalidArgument, "peers of dynamic group %s are defined by its query", group.Name)
+		}
+
 		if updated := group.AddPeer(peerID); !updated {
 			return nil
 		}
 
-		updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, []string{groupID})
+		if err = transaction.SaveGroup(ctx, store.LockingStrengthUpdate, group); err != nil {
+			return err
+		}
+
+		refreshedGroupIDs, err := refreshDynamicGroups(ctx, transaction, accountID)
 		if err != nil {
 			return err
 		}
 
-		if err = transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID); err != nil {
+		updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, append([]string{groupID}, refreshedGroupIDs...))
+		if err != nil {
 			return err
 		}
 
-		return transaction.SaveGroup(ctx, store.LockingStrengthUpdate, group)
+		return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
 	})
 	if err != nil {
 		return err
@@ -304,12 +358,14 @@ func (am *DefaultAccountManager) GroupAddPeer(ctx context.Context, accountID, gr
 
 // GroupAddResource appends resource to the group
 func (am *DefaultAccountManager) GroupAddResource(ctx context.Context, accountID, groupID string, resource types.Resource) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var group *types.Group
 	var updateAccountPeers bool
-	var err error
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		group, err = transaction.GetGroupByID(context.Background(), store.LockingStrengthUpdate, accountID, groupID)
@@ -345,12 +401,14 @@ func (am *DefaultAccountManager) GroupAddResource(ctx context.Context, accountID
 
 // GroupDeletePeer removes peer from the group
 func (am *DefaultAccountManager) GroupDeletePeer(ctx context.Context, accountID, groupID, peerID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var group *types.Group
 	var updateAccountPeers bool
-	var err error
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		group, err = transaction.GetGroupByID(context.Background(), store.LockingStrengthUpdate, accountID, groupID)
@@ -358,20 +416,29 @@ func (am *DefaultAccountManager) GroupDeletePeer(ctx context.Context, accountID,
 			return err
 		}
 
+		if group.IsDynamic() {
+			return status.Errorf(status.InvalidArgument, "peers of dynamic group %s are defined by its query", group.Name)
+		}
+
 		if updated := group.RemovePeer(peerID); !updated {
 			return nil
 		}
 
-		updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, []string{groupID})
+		if err = transaction.SaveGroup(ctx, store.LockingStrengthUpdate, group); err != nil {
+			return err
+		}
+
+		refreshedGroupIDs, err := refreshDynamicGroups(ctx, transaction, accountID)
 		if err != nil {
 			return err
 		}
 
-		if err = transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID); err != nil {
+		updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, append([]string{groupID}, refreshedGroupIDs...))
+		if err != nil {
 			return err
 		}
 
-		return transaction.SaveGroup(ctx, store.LockingStrengthUpdate, group)
+		return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
 	})
 	if err != nil {
 		return err
@@ -386,12 +453,14 @@ func (am *DefaultAccountManager) GroupDeletePeer(ctx context.Context, accountID,
 
 // GroupDeleteResource removes resource from the group
 func (am *DefaultAccountManager) GroupDeleteResource(ctx context.Context, accountID, groupID string, resource types.Resource) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var group *types.Group
 	var updateAccountPeers bool
-	var err error
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		group, err = transaction.GetGroupByID(context.Background(), store.LockingStrengthUpdate, accountID, groupID)
@@ -448,6 +517,10 @@ func validateNewGroup(ctx context.Context, transaction store.Store, accountID st
 		newGroup.ID = xid.New().String()
 	}
 
+	if newGroup.IsDynamic() {
+		return validateGroupQuery(ctx, transaction, accountID, newGroup)
+	}
+
 	for _, peerID := range newGroup.Peers {
 		_, err := transaction.GetPeerByID(ctx, store.LockingStrengthShare, accountID, peerID)
 		if err != nil {
@@ -458,6 +531,115 @@ func validateNewGroup(ctx context.Context, transaction store.Store, accountID st
 	return nil
 }
 
+// validateGroupQuery validates the query of a dynamic group and the groups it references.
+// Dynamic groups can only reference static groups, so the evaluation never depends on the order of groups.
+func validateGroupQuery(ctx context.Context, transaction store.Store, accountID string, newGroup *types.Group) error {
+	if newGroup.Issued != types.GroupIssuedAPI {
+		return status.Errorf(status.InvalidArgument, "%s group can't be dynamic", newGroup.Issued)
+	}
+
+	if newGroup.IsGroupAll() {
+		return status.Errorf(status.InvalidArgument, "group All can't be dynamic")
+	}
+
+	if err := newGroup.Query.Validate(); err != nil {
+		return err
+	}
+
+	groups, err := transaction.GetAccountGroups(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return err
+	}
+
+	groupsMap := make(map[string]*types.Group, len(groups))
+	for _, group := range groups {
+		groupsMap[group.ID] = group
+
+		if group.IsDynamic() && group.ID != newGroup.ID && slices.Contains(group.Query.ReferencedGroups(), newGroup.ID) {
+			return status.Errorf(status.InvalidArgument, "group is referenced by the query of dynamic group %s", group.Name)
+		}
+	}
+
+	for _, groupID := range newGroup.Query.ReferencedGroups() {
+		group, ok := groupsMap[groupID]
+		if !ok || groupID == newGroup.ID {
+			return status.Errorf(status.InvalidArgument, "group with ID \"%s\" not found", groupID)
+		}
+		if group.IsDynamic() {
+			return status.Errorf(status.InvalidArgument, "query can't reference dynamic group %s", group.Name)
+		}
+	}
+
+	return nil
+}
+
+// getDynamicGroupPeers returns the IDs of the account peers matching the query.
+func getDynamicGroupPeers(ctx context.Context, transaction store.Store, accountID string, query *types.GroupQuery) ([]string, error) {
+	groups, err := transaction.GetAccountGroups(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := transaction.GetAccountPeers(ctx, store.LockingStrengthShare, accountID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	matchingPeers := query.MatchingPeers(groups, peers)
+	peerIDs := make([]string, 0, len(matchingPeers))
+	for _, peer := range matchingPeers {
+		peerIDs = append(peerIDs, peer.ID)
+	}
+	return peerIDs, nil
+}
+
+// refreshDynamicGroups re-evaluates the peers of all dynamic groups in the account,
+// saves the groups whose membership changed and returns their IDs.
+func refreshDynamicGroups(ctx context.Context, transaction store.Store, accountID string) ([]string, error) {
+	groups, err := transaction.GetAccountGroups(ctx, store.LockingStrengthUpdate, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(groups, (*types.Group).IsDynamic) {
+		return nil, nil
+	}
+
+	peers, err := transaction.GetAccountPeers(ctx, store.LockingStrengthShare, accountID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return saveChangedDynamicGroups(ctx, transaction, types.EvaluateDynamicGroups(groups, peers))
+}
+
+// updatePeerDynamicGroups re-evaluates the dynamic groups membership of a single peer,
+// saves the groups whose membership changed and returns their IDs.
+func updatePeerDynamicGroups(ctx context.Context, transaction store.Store, accountID string, peer *nbpeer.Peer) ([]string, error) {
+	groups, err := transaction.GetAccountGroups(ctx, store.LockingStrengthUpdate, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return saveChangedDynamicGroups(ctx, transaction, types.EvaluatePeerDynamicGroups(groups, peer))
+}
+
+func saveChangedDynamicGroups(ctx context.Context, transaction store.Store, changedGroups []*types.Group) ([]string, error) {
+	if len(changedGroups) == 0 {
+		return nil, nil
+	}
+
+	if err := transaction.SaveGroups(ctx, store.LockingStrengthUpdate, changedGroups); err != nil {
+		return nil, err
+	}
+
+	groupIDs := make([]string, 0, len(changedGroups))
+	for _, group := range changedGroups {
+		groupIDs = append(groupIDs, group.ID)
+	}
+	return groupIDs, nil
+}
+
 func validateDeleteGroup(ctx context.Context, transaction store.Store, group *types.Group, userID string) error {
 	// disable a deleting integration group if the initiator is not an admin service user
 	if group.Issued == types.GroupIssuedIntegration {
@@ -525,6 +707,10 @@ func checkGroupLinkedToSettings(ctx context.Context, transaction store.Store, gr
 		return &GroupLinkError{"integrated validator", group.Name}
 	}
 
+	if slices.Contains(settings.PostQuantumRequiredGroups, group.ID) {
+		return &GroupLinkError{"post-quantum required groups", group.Name}
+	}
+
 	return nil
 }
 
@@ -644,10 +830,18 @@ func areGroupChangesAffectPeers(ctx context.Context, transaction store.Store, ac
 		return false, err
 	}
 
+	settings, err := transaction.GetAccountSettings(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return false, err
+	}
+
 	for _, groupID := range groupIDs {
 		if slices.Contains(dnsSettings.DisabledManagementGroups, groupID) {
 			return true, nil
 		}
+		if slices.Contains(settings.PostQuantumRequiredGroups, groupID) {
+			return true, nil
+		}
 		if linked, _ := isGroupLinkedToDns(ctx, transaction, accountID, groupID); linked {
 			return true, nil
 		}
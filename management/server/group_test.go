@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/netip"
 	"testing"
 	"time"
@@ -12,14 +13,18 @@ import (
 	"github.com/stretchr/testify/require"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/geolocation"
 	"github.com/netbirdio/netbird/management/server/groups"
 	"github.com/netbirdio/netbird/management/server/networks"
 	"github.com/netbirdio/netbird/management/server/networks/resources"
 	"github.com/netbirdio/netbird/management/server/networks/routers"
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
 	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/route"
 )
@@ -733,3 +738,159 @@ func TestGroupAccountPeersUpdate(t *testing.T) {
 		}
 	})
 }
+
+// countryGeo is a geolocation stub locating every IP in a single country
+type countryGeo struct {
+	geolocation.Mock
+	country string
+}
+
+func (g *countryGeo) Lookup(net.IP) (*geolocation.Record, error) {
+	record := &geolocation.Record{}
+	record.Country.ISOCode = g.country
+	return record, nil
+}
+
+func TestDefaultAccountManager_DynamicGroupRefresh(t *testing.T) {
+	manager, account, peer1, peer2, _ := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	account, err := manager.Store.GetAccount(ctx, account.Id)
+	require.NoError(t, err)
+	account.Settings.GroupsPropagationEnabled = true
+	account.Settings.JWTGroupsEnabled = true
+	account.Settings.JWTGroupsClaimName = "groups"
+	require.NoError(t, manager.Store.SaveAccount(ctx, account))
+
+	// peer1 belongs to the user, its groups follow the user auto groups
+	peer1.UserID = userID
+	require.NoError(t, manager.Store.SavePeer(ctx, store.LockingStrengthUpdate, account.Id, peer1))
+
+	require.NoError(t, manager.SaveGroups(ctx, account.Id, userID, []*types.Group{
+		{ID: "devs", Name: "devs", Issued: types.GroupIssuedAPI},
+		{ID: "jwt", Name: "jwt", Issued: types.GroupIssuedJWT},
+	}))
+
+	byGroup := func(id, groupID string) *types.Group {
+		return &types.Group{
+			ID:     id,
+			Name:   id,
+			Issued: types.GroupIssuedAPI,
+			Query: &types.GroupQuery{Match: types.GroupQueryMatchAll, Rules: []types.GroupQueryRule{
+				{Attribute: types.GroupQueryAttributeGroup, Operator: types.GroupQueryOperatorIs, Values: []string{groupID}},
+			}},
+		}
+	}
+	germany := &types.Group{
+		ID:     "germany",
+		Name:   "germany",
+		Issued: types.GroupIssuedAPI,
+		Query: &types.GroupQuery{Match: types.GroupQueryMatchAll, Rules: []types.GroupQueryRule{
+			{Attribute: types.GroupQueryAttributeCountry, Operator: types.GroupQueryOperatorIs, Values: []string{"DE"}},
+		}},
+	}
+	require.NoError(t, manager.SaveGroups(ctx, account.Id, userID, []*types.Group{byGroup("by-devs", "devs"), byGroup("by-jwt", "jwt"), germany}))
+
+	groupPeers := func(groupID string) []string {
+		t.Helper()
+		group, err := manager.GetGroup(ctx, account.Id, groupID, userID)
+		require.NoError(t, err)
+		return group.Peers
+	}
+
+	t.Run("user auto groups change", func(t *testing.T) {
+		user, err := manager.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+		require.NoError(t, err)
+		user.AutoGroups = []string{"devs"}
+		_, err = manager.SaveOrAddUsers(ctx, account.Id, userID, []*types.User{user}, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{peer1.ID}, groupPeers("by-devs"))
+	})
+
+	t.Run("JWT groups sync", func(t *testing.T) {
+		err := manager.SyncUserJWTGroups(ctx, nbcontext.UserAuth{AccountId: account.Id, UserId: userID, Groups: []string{"jwt"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{peer1.ID}, groupPeers("by-jwt"))
+
+		err = manager.SyncUserJWTGroups(ctx, nbcontext.UserAuth{AccountId: account.Id, UserId: userID, Groups: []string{}})
+		require.NoError(t, err)
+		assert.Empty(t, groupPeers("by-jwt"))
+	})
+
+	t.Run("peer location change", func(t *testing.T) {
+		manager.geo = &countryGeo{country: "DE"}
+		require.NoError(t, manager.MarkPeerConnected(ctx, peer2.Key, true, net.IP{192, 0, 2, 1}, account.Id))
+		assert.Equal(t, []string{peer2.ID}, groupPeers("germany"))
+
+		manager.geo = &countryGeo{country: "FR"}
+		require.NoError(t, manager.MarkPeerConnected(ctx, peer2.Key, true, net.IP{192, 0, 2, 2}, account.Id))
+		assert.Empty(t, groupPeers("germany"))
+	})
+}
+
+func TestDefaultAccountManager_DynamicGroup(t *testing.T) {
+	manager, account, peer1, peer2, peer3 := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	static := &types.Group{ID: "static", Name: "static", Issued: types.GroupIssuedAPI, Peers: []string{peer1.ID, peer2.ID}}
+	require.NoError(t, manager.SaveGroup(ctx, account.Id, userID, static))
+
+	dynamic := &types.Group{
+		ID:     "dynamic",
+		Name:   "dynamic",
+		Issued: types.GroupIssuedAPI,
+		Peers:  []string{peer3.ID},
+		Query: &types.GroupQuery{Match: types.GroupQueryMatchAll, Rules: []types.GroupQueryRule{
+			{Attribute: types.GroupQueryAttributeGroup, Operator: types.GroupQueryOperatorIs, Values: []string{static.ID}},
+			{Attribute: types.GroupQueryAttributeOS, Operator: types.GroupQueryOperatorIs, Values: []string{"linux"}},
+		}},
+	}
+	require.NoError(t, manager.SaveGroup(ctx, account.Id, userID, dynamic))
+
+	group, err := manager.GetGroup(ctx, account.Id, dynamic.ID, userID)
+	require.NoError(t, err)
+	assert.Empty(t, group.Peers, "peers of a dynamic group must be evaluated from the query")
+
+	_, _, _, err = manager.SyncPeer(ctx, types.PeerSync{
+		WireGuardPubKey: peer2.Key,
+		Meta:            nbpeer.PeerSystemMeta{Hostname: peer2.Key, GoOS: "linux"},
+	}, account.Id)
+	require.NoError(t, err)
+
+	group, err = manager.GetGroup(ctx, account.Id, dynamic.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{peer2.ID}, group.Peers, "peer should join the group after a meta sync")
+
+	_, _, _, err = manager.SyncPeer(ctx, types.PeerSync{
+		WireGuardPubKey: peer3.Key,
+		Meta:            nbpeer.PeerSystemMeta{Hostname: peer3.Key, GoOS: "linux"},
+	}, account.Id)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.GroupAddPeer(ctx, account.Id, static.ID, peer3.ID))
+	group, err = manager.GetGroup(ctx, account.Id, dynamic.ID, userID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{peer2.ID, peer3.ID}, group.Peers, "peer should join the group after a static group change")
+
+	err = manager.GroupAddPeer(ctx, account.Id, dynamic.ID, peer1.ID)
+	require.Error(t, err, "peers can't be added to a dynamic group manually")
+
+	peers, err := manager.PreviewGroupQuery(ctx, account.Id, userID, &types.GroupQuery{Match: types.GroupQueryMatchAny, Rules: []types.GroupQueryRule{
+		{Attribute: types.GroupQueryAttributeOS, Operator: types.GroupQueryOperatorIsNot, Values: []string{"linux"}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, peer1.ID, peers[0].ID)
+
+	nested := &types.Group{
+		ID:     "nested",
+		Name:   "nested",
+		Issued: types.GroupIssuedAPI,
+		Query: &types.GroupQuery{Match: types.GroupQueryMatchAll, Rules: []types.GroupQueryRule{
+			{Attribute: types.GroupQueryAttributeGroup, Operator: types.GroupQueryOperatorIs, Values: []string{dynamic.ID}},
+		}},
+	}
+	err = manager.SaveGroup(ctx, account.Id, userID, nested)
+	require.Error(t, err, "dynamic groups can't reference other dynamic groups")
+}
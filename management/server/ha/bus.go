@@ -0,0 +1,54 @@
+package ha
+
+import (
+	"context"
+	"os"
+	"strconv"
+)
+
+const (
+	// AccountPeersUpdated asks the instances to send network map updates to the connected peers of an account
+	AccountPeersUpdated = "account.peers.updated"
+	// PeerUpdated asks the instance a peer is connected to for sending the peer a network map update
+	PeerUpdated = "peer.updated"
+	// PeersDisconnected asks the instances to close the updates streams of deleted or expired peers
+	PeersDisconnected = "peers.disconnected"
+
+	// EnabledEnv enables running multiple management instances sharing one database
+	EnabledEnv = "NB_MANAGEMENT_HA_ENABLED"
+)
+
+// Event is a change published by a management instance for the peers connected to the other instances
+type Event struct {
+	Type      string   `json:"type"`
+	AccountID string   `json:"account_id"`
+	PeerIDs   []string `json:"peer_ids,omitempty"`
+	// Origin is the instance that published the event, it is set by the bus
+	Origin string `json:"origin"`
+}
+
+// Handler processes an event received from another management instance
+type Handler func(ctx context.Context, event *Event)
+
+// Bus delivers events between the management instances sharing one database.
+// Every instance keeps the updates streams of its own peers, the bus lets the other instances know when these peers need an update.
+type Bus interface {
+	// Publish sends the event to the other instances, the publishing instance doesn't receive it
+	Publish(ctx context.Context, event *Event) error
+	// Subscribe sets the handler of the events received from the other instances
+	Subscribe(handler Handler)
+	Close() error
+}
+
+// Locker locks resources across the management instances sharing one database
+type Locker interface {
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+	RLock(ctx context.Context, key string) (unlock func(), err error)
+	Close() error
+}
+
+// Enabled returns true if the management runs as one of multiple instances sharing the database
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnabledEnv))
+	return enabled
+}
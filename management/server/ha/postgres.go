@@ -0,0 +1,301 @@
+package ha
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	notifyChannel = "netbird_management_events"
+	// maxPayloadSize keeps the events below the 8000 bytes limit of a Postgres notification payload
+	maxPayloadSize = 7900
+	// listenRetryInterval is the time between the attempts to listen again after losing the connection
+	listenRetryInterval = 5 * time.Second
+
+	// lockMaxConns limits the database connections holding the advisory locks of an instance, one per held lock
+	lockMaxConns     = 32
+	lockMaxIdleConns = 8
+	// lockCallTimeout bounds the release of a lock, the calls never wait for a lock
+	lockCallTimeout      = 10 * time.Second
+	lockRetryMinInterval = 10 * time.Millisecond
+	lockRetryMaxInterval = 500 * time.Millisecond
+)
+
+// PostgresBus delivers the events between the instances with Postgres LISTEN/NOTIFY.
+// Events published while an instance reconnects its listening connection are not delivered to it.
+type PostgresBus struct {
+	dsn        string
+	instanceID string
+	// db publishes the events, listening uses a dedicated connection
+	db *sql.DB
+
+	handlerMu sync.RWMutex
+	handler   Handler
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPostgresBus connects to the database and starts listening to the events of the other instances
+func NewPostgresBus(ctx context.Context, dsn string) (*PostgresBus, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	b := &PostgresBus{
+		dsn:        dsn,
+		instanceID: xid.New().String(),
+		db:         db,
+		done:       make(chan struct{}),
+	}
+
+	conn, err := b.listen(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	go b.run(runCtx, conn)
+
+	log.WithContext(ctx).Infof("listening to the events of the other management instances as instance %s", b.instanceID)
+
+	return b, nil
+}
+
+// Publish notifies the other instances, events with too many peers for a single notification are split
+func (b *PostgresBus) Publish(ctx context.Context, event *Event) error {
+	e := *event
+	e.Origin = b.instanceID
+
+	payloads, err := encodeEvent(e)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	for _, payload := range payloads {
+		if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", notifyChannel, payload); err != nil {
+			return fmt.Errorf("notify: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe sets the handler of the events, each event is handled in its own goroutine
+func (b *PostgresBus) Subscribe(handler Handler) {
+	b.handlerMu.Lock()
+	defer b.handlerMu.Unlock()
+	b.handler = handler
+}
+
+// Close stops listening and closes the database connections
+func (b *PostgresBus) Close() error {
+	b.cancel()
+	<-b.done
+	return b.db.Close()
+}
+
+func (b *PostgresBus) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		_ = conn.Close(ctx)
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	return conn, nil
+}
+
+func (b *PostgresBus) run(ctx context.Context, conn *pgx.Conn) {
+	defer close(b.done)
+
+	for {
+		err := b.receive(ctx, conn)
+		_ = conn.Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		log.WithContext(ctx).Errorf("lost the connection listening to the events of the other management instances: %v", err)
+
+		for conn = nil; conn == nil; {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(listenRetryInterval):
+			}
+
+			if conn, err = b.listen(ctx); err != nil {
+				log.WithContext(ctx).Errorf("failed to listen to the events of the other management instances: %v", err)
+			}
+		}
+		log.WithContext(ctx).Infof("listening to the events of the other management instances again")
+	}
+}
+
+func (b *PostgresBus) receive(ctx context.Context, conn *pgx.Conn) error {
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		event := &Event{}
+		if err := json.Unmarshal([]byte(notification.Payload), event); err != nil {
+			log.WithContext(ctx).Warnf("failed to decode the event of another management instance: %v", err)
+			continue
+		}
+		if event.Origin == b.instanceID {
+			continue
+		}
+
+		b.handlerMu.RLock()
+		handler := b.handler
+		b.handlerMu.RUnlock()
+
+		if handler != nil {
+			go handler(ctx, event)
+		}
+	}
+}
+
+// encodeEvent splits the peers of the event across multiple payloads when it exceeds the payload limit
+func encodeEvent(event Event) ([]string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) <= maxPayloadSize {
+		return []string{string(data)}, nil
+	}
+	if len(event.PeerIDs) < 2 {
+		return nil, fmt.Errorf("event of %d bytes exceeds the payload limit", len(data))
+	}
+
+	half := len(event.PeerIDs) / 2
+	first, second := event, event
+	first.PeerIDs = event.PeerIDs[:half]
+	second.PeerIDs = event.PeerIDs[half:]
+
+	firstPayloads, err := encodeEvent(first)
+	if err != nil {
+		return nil, err
+	}
+	secondPayloads, err := encodeEvent(second)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(firstPayloads, secondPayloads...), nil
+}
+
+// PostgresLocker locks resources with Postgres session level advisory locks.
+// Every held lock has its own database connection, so a failing connection releases only the lock of its holder.
+// Waiting for a lock polls with pg_try_advisory_lock and returns the connection to the pool between the attempts,
+// so the waiting locks don't take the connections of the nested locks held meanwhile.
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+// NewPostgresLocker connects to the database used to hold the locks
+func NewPostgresLocker(ctx context.Context, dsn string) (*PostgresLocker, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	db.SetMaxOpenConns(lockMaxConns)
+	db.SetMaxIdleConns(lockMaxIdleConns)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return &PostgresLocker{db: db}, nil
+}
+
+// Lock acquires an exclusive lock for the key
+func (l *PostgresLocker) Lock(ctx context.Context, key string) (func(), error) {
+	return l.lock(ctx, key, "pg_try_advisory_lock", "pg_advisory_unlock")
+}
+
+// RLock acquires a shared lock for the key
+func (l *PostgresLocker) RLock(ctx context.Context, key string) (func(), error) {
+	return l.lock(ctx, key, "pg_try_advisory_lock_shared", "pg_advisory_unlock_shared")
+}
+
+func (l *PostgresLocker) Close() error {
+	return l.db.Close()
+}
+
+func (l *PostgresLocker) lock(ctx context.Context, key, tryLockFunc, unlockFunc string) (func(), error) {
+	interval := lockRetryMinInterval
+	for {
+		conn, err := l.db.Conn(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get connection: %w", err)
+		}
+
+		acquired, err := callLockFunc(ctx, conn, tryLockFunc, key)
+		if err != nil {
+			dropLockConn(conn)
+			return nil, fmt.Errorf("acquire advisory lock: %w", err)
+		}
+		if acquired {
+			return func() { releaseLock(conn, unlockFunc, key) }, nil
+		}
+		_ = conn.Close()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, lockRetryMaxInterval)
+	}
+}
+
+// releaseLock unlocks the key and returns the connection to the pool.
+// The unlock isn't cancelled with the request, a connection still holding the lock is dropped instead of being reused.
+func releaseLock(conn *sql.Conn, unlockFunc, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockCallTimeout)
+	defer cancel()
+
+	released, err := callLockFunc(ctx, conn, unlockFunc, key)
+	if err != nil {
+		log.Errorf("failed to release the advisory lock of %s, dropping its connection: %v", key, err)
+		dropLockConn(conn)
+		return
+	}
+	if !released {
+		log.Warnf("advisory lock of %s was not held anymore", key)
+	}
+	_ = conn.Close()
+}
+
+func callLockFunc(ctx context.Context, conn *sql.Conn, lockFunc, key string) (bool, error) {
+	var result bool
+	err := conn.QueryRowContext(ctx, fmt.Sprintf("SELECT %s(hashtextextended($1, 0))", lockFunc), key).Scan(&result)
+	return result, err
+}
+
+// dropLockConn closes the connection instead of returning it to the pool, Postgres releases the locks of the session with it
+func dropLockConn(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
@@ -0,0 +1,200 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/testutil"
+)
+
+func TestEncodeEvent(t *testing.T) {
+	event := Event{Type: PeerUpdated, AccountID: "account", Origin: "instance"}
+	for i := 0; i < 1000; i++ {
+		event.PeerIDs = append(event.PeerIDs, fmt.Sprintf("peer-%020d", i))
+	}
+
+	payloads, err := encodeEvent(event)
+	require.NoError(t, err)
+	assert.Greater(t, len(payloads), 1, "event should be split into multiple payloads")
+
+	var peerIDs []string
+	for _, payload := range payloads {
+		assert.LessOrEqual(t, len(payload), maxPayloadSize)
+
+		decoded := Event{}
+		require.NoError(t, json.Unmarshal([]byte(payload), &decoded))
+		assert.Equal(t, event.Type, decoded.Type)
+		assert.Equal(t, event.AccountID, decoded.AccountID)
+		assert.Equal(t, event.Origin, decoded.Origin)
+		peerIDs = append(peerIDs, decoded.PeerIDs...)
+	}
+	assert.Equal(t, event.PeerIDs, peerIDs, "split payloads should contain all peers in order")
+
+	payloads, err = encodeEvent(Event{Type: AccountPeersUpdated, AccountID: "account"})
+	require.NoError(t, err)
+	assert.Len(t, payloads, 1)
+
+	_, err = encodeEvent(Event{Type: PeerUpdated, PeerIDs: []string{string(make([]byte, maxPayloadSize))}})
+	assert.Error(t, err, "event of a single oversized peer cannot be split")
+}
+
+func postgresTestDSN(t *testing.T) string {
+	t.Helper()
+
+	if os.Getenv("NETBIRD_STORE_ENGINE") != "postgres" {
+		t.Skip("skipping, requires NETBIRD_STORE_ENGINE=postgres")
+	}
+
+	if dsn := os.Getenv("NETBIRD_STORE_ENGINE_POSTGRES_DSN"); dsn != "" {
+		return dsn
+	}
+
+	cleanup, err := testutil.CreatePostgresTestContainer()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	return os.Getenv("NETBIRD_STORE_ENGINE_POSTGRES_DSN")
+}
+
+func TestPostgresBus(t *testing.T) {
+	dsn := postgresTestDSN(t)
+	ctx := context.Background()
+
+	first, err := NewPostgresBus(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = first.Close() })
+
+	second, err := NewPostgresBus(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = second.Close() })
+
+	firstEvents := make(chan *Event, 1)
+	first.Subscribe(func(_ context.Context, event *Event) { firstEvents <- event })
+	secondEvents := make(chan *Event, 1)
+	second.Subscribe(func(_ context.Context, event *Event) { secondEvents <- event })
+
+	require.NoError(t, first.Publish(ctx, &Event{Type: PeerUpdated, AccountID: "account", PeerIDs: []string{"peer"}}))
+
+	select {
+	case event := <-secondEvents:
+		assert.Equal(t, &Event{Type: PeerUpdated, AccountID: "account", PeerIDs: []string{"peer"}, Origin: first.instanceID}, event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered to the other instance")
+	}
+
+	select {
+	case event := <-firstEvents:
+		t.Fatalf("event was delivered to the publishing instance: %v", event)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestPostgresLocker(t *testing.T) {
+	dsn := postgresTestDSN(t)
+	ctx := context.Background()
+
+	first, err := NewPostgresLocker(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = first.Close() })
+
+	second, err := NewPostgresLocker(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = second.Close() })
+
+	unlock, err := first.Lock(ctx, "account")
+	require.NoError(t, err)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err = second.RLock(timeoutCtx, "account")
+	assert.Error(t, err, "lock held by another instance should block")
+
+	otherUnlock, err := second.Lock(ctx, "other-account")
+	require.NoError(t, err, "locks of other keys should not block")
+	otherUnlock()
+
+	unlock()
+
+	unlock, err = second.RLock(ctx, "account")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestPostgresLocker_NestedLocks(t *testing.T) {
+	dsn := postgresTestDSN(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	locker, err := NewPostgresLocker(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = locker.Close() })
+
+	// an account lock is held while the lock of a peer is acquired, like on peer login
+	var unlocks []func()
+	for i := 0; i < lockMaxConns/2; i++ {
+		unlock, err := locker.RLock(ctx, "account")
+		require.NoError(t, err, "shared locks should stack")
+		unlocks = append(unlocks, unlock)
+
+		unlock, err = locker.Lock(ctx, fmt.Sprintf("peer-%d", i))
+		require.NoError(t, err)
+		unlocks = append(unlocks, unlock)
+	}
+
+	for _, unlock := range unlocks {
+		unlock()
+	}
+
+	unlock, err := locker.Lock(ctx, "account")
+	require.NoError(t, err, "all shared locks should be released")
+	unlock()
+}
+
+func TestPostgresLocker_LostConnectionReleasesOnlyItsLock(t *testing.T) {
+	dsn := postgresTestDSN(t)
+	ctx := context.Background()
+
+	locker, err := NewPostgresLocker(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = locker.Close() })
+
+	other, err := NewPostgresLocker(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = other.Close() })
+
+	unlockFirst, err := locker.Lock(ctx, "first")
+	require.NoError(t, err)
+	unlockSecond, err := locker.Lock(ctx, "second")
+	require.NoError(t, err)
+	defer unlockSecond()
+
+	// terminate the session holding the first lock
+	_, err = other.db.ExecContext(ctx, `SELECT pg_terminate_backend(pid) FROM pg_locks
+		WHERE locktype = 'advisory' AND ((classid::bigint << 32) | objid::bigint) = hashtextextended($1, 0)`, "first")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		attemptCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		unlock, err := other.Lock(attemptCtx, "first")
+		if err != nil {
+			return false
+		}
+		unlock()
+		return true
+	}, 5*time.Second, 100*time.Millisecond, "lock of the terminated session should be released")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err = other.Lock(timeoutCtx, "second")
+	assert.Error(t, err, "lock of another session should still be held")
+
+	unlockFirst()
+}
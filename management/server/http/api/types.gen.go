@@ -12,6 +12,12 @@ const (
 	TokenAuthScopes  = "TokenAuth.Scopes"
 )
 
+// Defines values for BulkOperationItemResultStatus.
+const (
+	BulkOperationItemResultStatusFailed  BulkOperationItemResultStatus = "failed"
+	BulkOperationItemResultStatusSuccess BulkOperationItemResultStatus = "success"
+)
+
 // Defines values for EventActivityCode.
 const (
 	EventActivityCodeAccountCreate                            EventActivityCode = "account.create"
@@ -83,6 +89,34 @@ const (
 	GroupMinimumIssuedJwt         GroupMinimumIssued = "jwt"
 )
 
+// Defines values for GroupQueryMatch.
+const (
+	GroupQueryMatchAll GroupQueryMatch = "all"
+	GroupQueryMatchAny GroupQueryMatch = "any"
+)
+
+// Defines values for GroupQueryRuleAttribute.
+const (
+	GroupQueryRuleAttributeCloud     GroupQueryRuleAttribute = "cloud"
+	GroupQueryRuleAttributeCountry   GroupQueryRuleAttribute = "country"
+	GroupQueryRuleAttributeGroup     GroupQueryRuleAttribute = "group"
+	GroupQueryRuleAttributeHostname  GroupQueryRuleAttribute = "hostname"
+	GroupQueryRuleAttributeKernel    GroupQueryRuleAttribute = "kernel"
+	GroupQueryRuleAttributeOs        GroupQueryRuleAttribute = "os"
+	GroupQueryRuleAttributeOsVersion GroupQueryRuleAttribute = "os_version"
+	GroupQueryRuleAttributePlatform  GroupQueryRuleAttribute = "platform"
+	GroupQueryRuleAttributeUser      GroupQueryRuleAttribute = "user"
+	GroupQueryRuleAttributeVersion   GroupQueryRuleAttribute = "version"
+)
+
+// Defines values for GroupQueryRuleOperator.
+const (
+	GroupQueryRuleOperatorIs         GroupQueryRuleOperator = "is"
+	GroupQueryRuleOperatorIsNot      GroupQueryRuleOperator = "is_not"
+	GroupQueryRuleOperatorMatches    GroupQueryRuleOperator = "matches"
+	GroupQueryRuleOperatorMinVersion GroupQueryRuleOperator = "min_version"
+)
+
 // Defines values for IngressPortAllocationPortMappingProtocol.
 const (
 	IngressPortAllocationPortMappingProtocolTcp    IngressPortAllocationPortMappingProtocol = "tcp"
@@ -109,6 +143,14 @@ const (
 	NameserverNsTypeUdp NameserverNsType = "udp"
 )
 
+// Defines values for NetworkResourceServiceProtocol.
+const (
+	NetworkResourceServiceProtocolAll  NetworkResourceServiceProtocol = "all"
+	NetworkResourceServiceProtocolIcmp NetworkResourceServiceProtocol = "icmp"
+	NetworkResourceServiceProtocolTcp  NetworkResourceServiceProtocol = "tcp"
+	NetworkResourceServiceProtocolUdp  NetworkResourceServiceProtocol = "udp"
+)
+
 // Defines values for NetworkResourceType.
 const (
 	NetworkResourceTypeDomain NetworkResourceType = "domain"
@@ -116,6 +158,23 @@ const (
 	NetworkResourceTypeSubnet NetworkResourceType = "subnet"
 )
 
+// Defines values for PeerApprovalRequestStatus.
+const (
+	PeerApprovalRequestStatusApproved PeerApprovalRequestStatus = "approved"
+	PeerApprovalRequestStatusPending  PeerApprovalRequestStatus = "pending"
+	PeerApprovalRequestStatusRejected PeerApprovalRequestStatus = "rejected"
+)
+
+// Defines values for PeerBulkRequestAction.
+const (
+	PeerBulkRequestActionApprove            PeerBulkRequestAction = "approve"
+	PeerBulkRequestActionAssignGroups       PeerBulkRequestAction = "assign_groups"
+	PeerBulkRequestActionDelete             PeerBulkRequestAction = "delete"
+	PeerBulkRequestActionSetLoginExpiration PeerBulkRequestAction = "set_login_expiration"
+	PeerBulkRequestActionSetSsh             PeerBulkRequestAction = "set_ssh"
+	PeerBulkRequestActionUnassignGroups     PeerBulkRequestAction = "unassign_groups"
+)
+
 // Defines values for PeerNetworkRangeCheckAction.
 const (
 	PeerNetworkRangeCheckActionAllow PeerNetworkRangeCheckAction = "allow"
@@ -164,6 +223,27 @@ const (
 	PolicyRuleUpdateProtocolUdp  PolicyRuleUpdateProtocol = "udp"
 )
 
+// Defines values for PolicySimulationRequestProtocol.
+const (
+	PolicySimulationRequestProtocolAll  PolicySimulationRequestProtocol = "all"
+	PolicySimulationRequestProtocolIcmp PolicySimulationRequestProtocol = "icmp"
+	PolicySimulationRequestProtocolTcp  PolicySimulationRequestProtocol = "tcp"
+	PolicySimulationRequestProtocolUdp  PolicySimulationRequestProtocol = "udp"
+)
+
+// Defines values for PolicySimulationResultDestinationType.
+const (
+	PolicySimulationResultDestinationTypePeer     PolicySimulationResultDestinationType = "peer"
+	PolicySimulationResultDestinationTypeResource PolicySimulationResultDestinationType = "resource"
+	PolicySimulationResultDestinationTypeRoute    PolicySimulationResultDestinationType = "route"
+)
+
+// Defines values for PolicySimulationRuleMatchAction.
+const (
+	PolicySimulationRuleMatchActionAccept PolicySimulationRuleMatchAction = "accept"
+	PolicySimulationRuleMatchActionDrop   PolicySimulationRuleMatchAction = "drop"
+)
+
 // Defines values for ResourceType.
 const (
 	ResourceTypeDomain ResourceType = "domain"
@@ -171,6 +251,22 @@ const (
 	ResourceTypeSubnet ResourceType = "subnet"
 )
 
+// Defines values for SetupKeyBulkRequestAction.
+const (
+	SetupKeyBulkRequestActionDelete SetupKeyBulkRequestAction = "delete"
+	SetupKeyBulkRequestActionRevoke SetupKeyBulkRequestAction = "revoke"
+)
+
+// Defines values for SetupKeyConstraintsAllowedOs.
+const (
+	SetupKeyConstraintsAllowedOsAndroid SetupKeyConstraintsAllowedOs = "android"
+	SetupKeyConstraintsAllowedOsDarwin  SetupKeyConstraintsAllowedOs = "darwin"
+	SetupKeyConstraintsAllowedOsFreebsd SetupKeyConstraintsAllowedOs = "freebsd"
+	SetupKeyConstraintsAllowedOsIos     SetupKeyConstraintsAllowedOs = "ios"
+	SetupKeyConstraintsAllowedOsLinux   SetupKeyConstraintsAllowedOs = "linux"
+	SetupKeyConstraintsAllowedOsWindows SetupKeyConstraintsAllowedOs = "windows"
+)
+
 // Defines values for UserStatus.
 const (
 	UserStatusActive  UserStatus = "active"
@@ -185,6 +281,36 @@ const (
 	UserPermissionsDashboardViewLimited UserPermissionsDashboardView = "limited"
 )
 
+// Defines values for SortOrder.
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+// Defines values for GetApiPeersApprovalRequestsParamsStatus.
+const (
+	GetApiPeersApprovalRequestsParamsStatusApproved GetApiPeersApprovalRequestsParamsStatus = "approved"
+	GetApiPeersApprovalRequestsParamsStatusPending  GetApiPeersApprovalRequestsParamsStatus = "pending"
+	GetApiPeersApprovalRequestsParamsStatusRejected GetApiPeersApprovalRequestsParamsStatus = "rejected"
+)
+
+// Defines values for GetApiPeersParamsSortBy.
+const (
+	GetApiPeersParamsSortByCreatedAt GetApiPeersParamsSortBy = "created_at"
+	GetApiPeersParamsSortByDnsLabel  GetApiPeersParamsSortBy = "dns_label"
+	GetApiPeersParamsSortByLastSeen  GetApiPeersParamsSortBy = "last_seen"
+	GetApiPeersParamsSortByName      GetApiPeersParamsSortBy = "name"
+	GetApiPeersParamsSortByOs        GetApiPeersParamsSortBy = "os"
+	GetApiPeersParamsSortByVersion   GetApiPeersParamsSortBy = "version"
+)
+
+// Defines values for GetApiUsersParamsSortBy.
+const (
+	GetApiUsersParamsSortByCreatedAt GetApiUsersParamsSortBy = "created_at"
+	GetApiUsersParamsSortById        GetApiUsersParamsSortBy = "id"
+	GetApiUsersParamsSortByRole      GetApiUsersParamsSortBy = "role"
+)
+
 // AccessiblePeer defines model for AccessiblePeer.
 type AccessiblePeer struct {
 	// CityName Commonly used English name of the city
@@ -240,6 +366,12 @@ type AccountExtraSettings struct {
 	PeerApprovalEnabled bool `json:"peer_approval_enabled"`
 }
 
+// AccountParentRequest defines model for AccountParentRequest.
+type AccountParentRequest struct {
+	// ParentAccountId ID of the account whose admins can manage this account. An empty value unlinks the account from its parent.
+	ParentAccountId string `json:"parent_account_id"`
+}
+
 // AccountRequest defines model for AccountRequest.
 type AccountRequest struct {
 	Settings AccountSettings `json:"settings"`
@@ -273,6 +405,9 @@ type AccountSettings struct {
 	// PeerLoginExpirationEnabled Enables or disables peer login expiration globally. After peer's login has expired the user has to log in (authenticate). Applies only to peers that were added by a user (interactive SSO login).
 	PeerLoginExpirationEnabled bool `json:"peer_login_expiration_enabled"`
 
+	// PostQuantumRequiredGroups List of group IDs whose peers refuse connections without the Rosenpass post-quantum key exchange
+	PostQuantumRequiredGroups *[]string `json:"post_quantum_required_groups,omitempty"`
+
 	// RegularUsersViewBlocked Allows blocking regular users from viewing parts of the system.
 	RegularUsersViewBlocked bool `json:"regular_users_view_blocked"`
 
@@ -289,8 +424,32 @@ type AvailablePorts struct {
 	Udp int `json:"udp"`
 }
 
+// BulkOperationItemResult defines model for BulkOperationItemResult.
+type BulkOperationItemResult struct {
+	// Error Reason the action failed for the object
+	Error *string `json:"error,omitempty"`
+
+	// Id Object ID
+	Id string `json:"id"`
+
+	// Status Outcome of the action for the object
+	Status BulkOperationItemResultStatus `json:"status"`
+}
+
+// BulkOperationItemResultStatus Outcome of the action for the object
+type BulkOperationItemResultStatus string
+
+// BulkOperationResponse defines model for BulkOperationResponse.
+type BulkOperationResponse struct {
+	// Results Per object outcome of the operation in the order of the request
+	Results []BulkOperationItemResult `json:"results"`
+}
+
 // Checks List of objects that perform the actual checks
 type Checks struct {
+	// DeviceInventoryCheck Posture check allowing only peers running on devices of the account inventory, matched by the system serial number
+	DeviceInventoryCheck *DeviceInventoryCheck `json:"device_inventory_check,omitempty"`
+
 	// GeoLocationCheck Posture check for geo location
 	GeoLocationCheck *GeoLocationCheck `json:"geo_location_check,omitempty"`
 
@@ -303,10 +462,46 @@ type Checks struct {
 	// PeerNetworkRangeCheck Posture check for allow or deny access based on peer local network addresses
 	PeerNetworkRangeCheck *PeerNetworkRangeCheck `json:"peer_network_range_check,omitempty"`
 
+	// PostQuantumCheck Posture check allowing only peers with the Rosenpass post-quantum key exchange enabled
+	PostQuantumCheck *PostQuantumCheck `json:"post_quantum_check,omitempty"`
+
 	// ProcessCheck Posture Check for binaries exist and are running in the peer’s system
 	ProcessCheck *ProcessCheck `json:"process_check,omitempty"`
 }
 
+// ChildAccount defines model for ChildAccount.
+type ChildAccount struct {
+	// CreatedAt Child account creation date
+	CreatedAt time.Time `json:"created_at"`
+
+	// CreatedBy ID of the user that created the child account
+	CreatedBy string `json:"created_by"`
+
+	// Domain Domain of the child account
+	Domain string `json:"domain"`
+
+	// Id Child account ID
+	Id string `json:"id"`
+
+	// Pending Set when the account asked to be linked and waits for an admin to accept it
+	Pending bool `json:"pending"`
+}
+
+// ChildAccountTemplatesRequest defines model for ChildAccountTemplatesRequest.
+type ChildAccountTemplatesRequest struct {
+	// ChildAccountIds IDs of the child accounts receiving the templates. All child accounts receive them when empty.
+	ChildAccountIds *[]string `json:"child_account_ids,omitempty"`
+
+	// NameserverGroupIds IDs of the nameserver groups to copy to the child accounts
+	NameserverGroupIds *[]string `json:"nameserver_group_ids,omitempty"`
+
+	// PolicyIds IDs of the policies to copy to the child accounts
+	PolicyIds *[]string `json:"policy_ids,omitempty"`
+
+	// PostureCheckIds IDs of the posture checks to copy to the child accounts
+	PostureCheckIds *[]string `json:"posture_check_ids,omitempty"`
+}
+
 // City Describe city geographical location information
 type City struct {
 	// CityName Commonly used English name of the city
@@ -339,6 +534,9 @@ type CreateSetupKeyRequest struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Restrictions on the devices that can register with the setup key
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral *bool `json:"ephemeral,omitempty"`
 
@@ -361,8 +559,17 @@ type DNSSettings struct {
 	DisabledManagementGroups []string `json:"disabled_management_groups"`
 }
 
+// DeviceInventoryCheck Posture check allowing only peers running on devices of the account inventory, matched by the system serial number
+type DeviceInventoryCheck struct {
+	// RequireOwnerMatch Requires the peer to be registered by the owner of the device, when the device has one
+	RequireOwnerMatch *bool `json:"require_owner_match,omitempty"`
+}
+
 // Event defines model for Event.
 type Event struct {
+	// AccountId The ID of the account the event occurred in
+	AccountId *string `json:"account_id,omitempty"`
+
 	// Activity The activity that occurred during the event
 	Activity string `json:"activity"`
 
@@ -421,8 +628,11 @@ type Group struct {
 	Peers []PeerMinimum `json:"peers"`
 
 	// PeersCount Count of peers associated to the group
-	PeersCount int        `json:"peers_count"`
-	Resources  []Resource `json:"resources"`
+	PeersCount int `json:"peers_count"`
+
+	// Query Query over peer attributes defining the peers of a dynamic group. Peers of a dynamic group are evaluated automatically and can't be set manually.
+	Query     *GroupQuery `json:"query,omitempty"`
+	Resources []Resource  `json:"resources"`
 
 	// ResourcesCount Count of resources associated to the group
 	ResourcesCount int `json:"resources_count"`
@@ -452,13 +662,46 @@ type GroupMinimum struct {
 // GroupMinimumIssued How the group was issued (api, integration, jwt)
 type GroupMinimumIssued string
 
+// GroupQuery Query over peer attributes defining the peers of a dynamic group. Peers of a dynamic group are evaluated automatically and can't be set manually.
+type GroupQuery struct {
+	// Match Whether all or any of the rules have to match a peer
+	Match GroupQueryMatch `json:"match"`
+
+	// Rules List of rules evaluated on each peer
+	Rules []GroupQueryRule `json:"rules"`
+}
+
+// GroupQueryMatch Whether all or any of the rules have to match a peer
+type GroupQueryMatch string
+
+// GroupQueryRule defines model for GroupQueryRule.
+type GroupQueryRule struct {
+	// Attribute Peer attribute the rule is evaluated on. The group attribute refers to the static groups of the peer.
+	Attribute GroupQueryRuleAttribute `json:"attribute"`
+
+	// Operator Comparison of the attribute to the values. Matches accepts glob patterns and min_version a single version.
+	Operator GroupQueryRuleOperator `json:"operator"`
+
+	// Values Values compared to the attribute, case-insensitive
+	Values []string `json:"values"`
+}
+
+// GroupQueryRuleAttribute Peer attribute the rule is evaluated on. The group attribute refers to the static groups of the peer.
+type GroupQueryRuleAttribute string
+
+// GroupQueryRuleOperator Comparison of the attribute to the values. Matches accepts glob patterns and min_version a single version.
+type GroupQueryRuleOperator string
+
 // GroupRequest defines model for GroupRequest.
 type GroupRequest struct {
 	// Name Group name identifier
 	Name string `json:"name"`
 
 	// Peers List of peers ids
-	Peers     *[]string   `json:"peers,omitempty"`
+	Peers *[]string `json:"peers,omitempty"`
+
+	// Query Query over peer attributes defining the peers of a dynamic group. Peers of a dynamic group are evaluated automatically and can't be set manually.
+	Query     *GroupQuery `json:"query,omitempty"`
 	Resources *[]Resource `json:"resources,omitempty"`
 }
 
@@ -595,6 +838,69 @@ type IngressPortAllocationRequestPortRange struct {
 // IngressPortAllocationRequestPortRangeProtocol The protocol accepted by the port range
 type IngressPortAllocationRequestPortRangeProtocol string
 
+// InventoryDevice defines model for InventoryDevice.
+type InventoryDevice struct {
+	// CreatedAt Date the device was added to the inventory
+	CreatedAt time.Time `json:"created_at"`
+
+	// Description Device description
+	Description *string `json:"description,omitempty"`
+
+	// Id Inventory device ID
+	Id string `json:"id"`
+
+	// Manufacturer Device manufacturer
+	Manufacturer *string `json:"manufacturer,omitempty"`
+
+	// ProductName Device product name
+	ProductName *string `json:"product_name,omitempty"`
+
+	// SerialNumber System serial number of the device, as reported by the peer
+	SerialNumber string `json:"serial_number"`
+
+	// UserId ID of the user owning the device
+	UserId *string `json:"user_id,omitempty"`
+}
+
+// InventoryDeviceRequest defines model for InventoryDeviceRequest.
+type InventoryDeviceRequest struct {
+	// Description Device description
+	Description *string `json:"description,omitempty"`
+
+	// Manufacturer Device manufacturer
+	Manufacturer *string `json:"manufacturer,omitempty"`
+
+	// ProductName Device product name
+	ProductName *string `json:"product_name,omitempty"`
+
+	// SerialNumber System serial number of the device, as reported by the peer
+	SerialNumber string `json:"serial_number"`
+
+	// UserId ID of the user owning the device
+	UserId *string `json:"user_id,omitempty"`
+}
+
+// InventoryImportRequest defines model for InventoryImportRequest.
+type InventoryImportRequest struct {
+	// Devices Devices to add to the inventory, devices already in the inventory are matched by serial number and updated
+	Devices []InventoryDeviceRequest `json:"devices"`
+}
+
+// InventoryImportResponse defines model for InventoryImportResponse.
+type InventoryImportResponse struct {
+	// Created Number of devices added to the inventory
+	Created int `json:"created"`
+
+	// Failed Number of devices that couldn't be imported
+	Failed int `json:"failed"`
+
+	// Results Per device outcome of the import in the order of the request, identified by the serial number
+	Results []BulkOperationItemResult `json:"results"`
+
+	// Updated Number of inventory devices updated
+	Updated int `json:"updated"`
+}
+
 // Location Describe geographical location information
 type Location struct {
 	// CityName Commonly used English name of the city
@@ -726,7 +1032,7 @@ type NetworkRequest struct {
 
 // NetworkResource defines model for NetworkResource.
 type NetworkResource struct {
-	// Address Network resource address (either a direct host like 1.1.1.1 or 1.1.1.1/32, or a subnet like 192.168.178.0/24, or domains like example.com and *.example.com)
+	// Address Network resource address (either a direct host like 1.1.1.1 or 1.1.1.1/32, or a subnet like 192.168.178.0/24, or domains like example.com and *.example.com, or regex domains like ~^api-[0-9]+\.example\.com$ scoped below a fixed domain)
 	Address string `json:"address"`
 
 	// Description Network resource description
@@ -744,13 +1050,16 @@ type NetworkResource struct {
 	// Name Network resource name
 	Name string `json:"name"`
 
+	// Services Services exposed by the resource. When set, access to the resource is limited to these protocols and ports regardless of the policies applied to it
+	Services *[]NetworkResourceService `json:"services,omitempty"`
+
 	// Type Network resource type based of the address
 	Type NetworkResourceType `json:"type"`
 }
 
 // NetworkResourceMinimum defines model for NetworkResourceMinimum.
 type NetworkResourceMinimum struct {
-	// Address Network resource address (either a direct host like 1.1.1.1 or 1.1.1.1/32, or a subnet like 192.168.178.0/24, or domains like example.com and *.example.com)
+	// Address Network resource address (either a direct host like 1.1.1.1 or 1.1.1.1/32, or a subnet like 192.168.178.0/24, or domains like example.com and *.example.com, or regex domains like ~^api-[0-9]+\.example\.com$ scoped below a fixed domain)
 	Address string `json:"address"`
 
 	// Description Network resource description
@@ -761,11 +1070,14 @@ type NetworkResourceMinimum struct {
 
 	// Name Network resource name
 	Name string `json:"name"`
+
+	// Services Services exposed by the resource. When set, access to the resource is limited to these protocols and ports regardless of the policies applied to it
+	Services *[]NetworkResourceService `json:"services,omitempty"`
 }
 
 // NetworkResourceRequest defines model for NetworkResourceRequest.
 type NetworkResourceRequest struct {
-	// Address Network resource address (either a direct host like 1.1.1.1 or 1.1.1.1/32, or a subnet like 192.168.178.0/24, or domains like example.com and *.example.com)
+	// Address Network resource address (either a direct host like 1.1.1.1 or 1.1.1.1/32, or a subnet like 192.168.178.0/24, or domains like example.com and *.example.com, or regex domains like ~^api-[0-9]+\.example\.com$ scoped below a fixed domain)
 	Address string `json:"address"`
 
 	// Description Network resource description
@@ -779,8 +1091,23 @@ type NetworkResourceRequest struct {
 
 	// Name Network resource name
 	Name string `json:"name"`
+
+	// Services Services exposed by the resource. When set, access to the resource is limited to these protocols and ports regardless of the policies applied to it
+	Services *[]NetworkResourceService `json:"services,omitempty"`
 }
 
+// NetworkResourceService Protocol and ports exposed by a network resource
+type NetworkResourceService struct {
+	// PortRanges Port ranges of the exposed service. Only applicable to tcp and udp, all ports are exposed when empty
+	PortRanges *[]RulePortRange `json:"port_ranges,omitempty"`
+
+	// Protocol Protocol of the exposed service
+	Protocol NetworkResourceServiceProtocol `json:"protocol"`
+}
+
+// NetworkResourceServiceProtocol Protocol of the exposed service
+type NetworkResourceServiceProtocol string
+
 // NetworkResourceType Network resource type based of the address
 type NetworkResourceType string
 
@@ -976,6 +1303,9 @@ type Peer struct {
 	// Ip Peer's IP address
 	Ip string `json:"ip"`
 
+	// Ipv6 Peer's IPv6 overlay address, only set if the peer's client supports the IPv6 overlay
+	Ipv6 *string `json:"ipv6,omitempty"`
+
 	// KernelVersion Peer's operating system kernel version
 	KernelVersion string `json:"kernel_version"`
 
@@ -997,6 +1327,9 @@ type Peer struct {
 	// Os Peer's operating system and version
 	Os string `json:"os"`
 
+	// PostQuantum Post-quantum key exchange status of the peer
+	PostQuantum PeerPostQuantumStatus `json:"post_quantum"`
+
 	// SerialNumber System serial number
 	SerialNumber string `json:"serial_number"`
 
@@ -1006,6 +1339,9 @@ type Peer struct {
 	// UiVersion Peer's desktop UI version
 	UiVersion string `json:"ui_version"`
 
+	// UnknownDevice Indicates the account keeps a device inventory and the peer device isn't part of it
+	UnknownDevice bool `json:"unknown_device"`
+
 	// UserId User ID of the user that enrolled this peer
 	UserId string `json:"user_id"`
 
@@ -1013,6 +1349,114 @@ type Peer struct {
 	Version string `json:"version"`
 }
 
+// PeerApprovalDecision defines model for PeerApprovalDecision.
+type PeerApprovalDecision struct {
+	// Reason Reason recorded in the activity log
+	Reason *string `json:"reason,omitempty"`
+}
+
+// PeerApprovalRequest defines model for PeerApprovalRequest.
+type PeerApprovalRequest struct {
+	// ApprovalRuleId ID of the auto-approve rule that approved the request
+	ApprovalRuleId *string `json:"approval_rule_id,omitempty"`
+
+	// CityName Commonly used English name of the city
+	CityName CityName `json:"city_name"`
+
+	// ConnectionIp Peer's public connection IP address
+	ConnectionIp string `json:"connection_ip"`
+
+	// CountryCode 2-letter ISO 3166-1 alpha-2 code that represents the country
+	CountryCode CountryCode `json:"country_code"`
+
+	// CreatedAt Request creation date-time
+	CreatedAt time.Time `json:"created_at"`
+
+	// Hostname Hostname of the machine
+	Hostname string `json:"hostname"`
+
+	// KernelVersion Peer's operating system kernel version
+	KernelVersion string `json:"kernel_version"`
+
+	// Os Peer's operating system and version
+	Os string `json:"os"`
+
+	// PeerId ID of the peer waiting for approval
+	PeerId string `json:"peer_id"`
+
+	// PeerName Name of the peer
+	PeerName string `json:"peer_name"`
+
+	// Reason Reason given when the request was approved or rejected
+	Reason *string `json:"reason,omitempty"`
+
+	// ReviewedAt Review date-time
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+
+	// ReviewedBy ID of the user that approved or rejected the request, empty when it was approved by a rule
+	ReviewedBy *string `json:"reviewed_by,omitempty"`
+
+	// SerialNumber System serial number
+	SerialNumber string `json:"serial_number"`
+
+	// SetupKeyId ID of the setup key the peer registered with
+	SetupKeyId *string `json:"setup_key_id,omitempty"`
+
+	// SetupKeyName Name of the setup key the peer registered with
+	SetupKeyName *string `json:"setup_key_name,omitempty"`
+
+	// Status Status of the approval request
+	Status PeerApprovalRequestStatus `json:"status"`
+
+	// UserId ID of the user that registered the peer, empty for peers registered with a setup key
+	UserId *string `json:"user_id,omitempty"`
+
+	// Version Peer's daemon or cli version
+	Version string `json:"version"`
+}
+
+// PeerApprovalRequestStatus Status of the approval request
+type PeerApprovalRequestStatus string
+
+// PeerApprovalRule defines model for PeerApprovalRule.
+type PeerApprovalRule struct {
+	// Description Rule description
+	Description *string `json:"description,omitempty"`
+
+	// Enabled Rule status
+	Enabled bool `json:"enabled"`
+
+	// Id Rule ID
+	Id string `json:"id"`
+
+	// Name Rule name
+	Name string `json:"name"`
+
+	// SerialNumbers Approves peers reporting one of the system serial numbers
+	SerialNumbers *[]string `json:"serial_numbers,omitempty"`
+
+	// SetupKeyIds Approves peers registered with one of the setup keys
+	SetupKeyIds *[]string `json:"setup_key_ids,omitempty"`
+}
+
+// PeerApprovalRuleRequest defines model for PeerApprovalRuleRequest.
+type PeerApprovalRuleRequest struct {
+	// Description Rule description
+	Description *string `json:"description,omitempty"`
+
+	// Enabled Rule status
+	Enabled bool `json:"enabled"`
+
+	// Name Rule name
+	Name string `json:"name"`
+
+	// SerialNumbers Approves peers reporting one of the system serial numbers
+	SerialNumbers *[]string `json:"serial_numbers,omitempty"`
+
+	// SetupKeyIds Approves peers registered with one of the setup keys
+	SetupKeyIds *[]string `json:"setup_key_ids,omitempty"`
+}
+
 // PeerBatch defines model for PeerBatch.
 type PeerBatch struct {
 	// AccessiblePeersCount Number of accessible peers
@@ -1057,6 +1501,9 @@ type PeerBatch struct {
 	// Ip Peer's IP address
 	Ip string `json:"ip"`
 
+	// Ipv6 Peer's IPv6 overlay address, only set if the peer's client supports the IPv6 overlay
+	Ipv6 *string `json:"ipv6,omitempty"`
+
 	// KernelVersion Peer's operating system kernel version
 	KernelVersion string `json:"kernel_version"`
 
@@ -1078,6 +1525,9 @@ type PeerBatch struct {
 	// Os Peer's operating system and version
 	Os string `json:"os"`
 
+	// PostQuantum Post-quantum key exchange status of the peer
+	PostQuantum PeerPostQuantumStatus `json:"post_quantum"`
+
 	// SerialNumber System serial number
 	SerialNumber string `json:"serial_number"`
 
@@ -1087,6 +1537,9 @@ type PeerBatch struct {
 	// UiVersion Peer's desktop UI version
 	UiVersion string `json:"ui_version"`
 
+	// UnknownDevice Indicates the account keeps a device inventory and the peer device isn't part of it
+	UnknownDevice bool `json:"unknown_device"`
+
 	// UserId User ID of the user that enrolled this peer
 	UserId string `json:"user_id"`
 
@@ -1094,6 +1547,27 @@ type PeerBatch struct {
 	Version string `json:"version"`
 }
 
+// PeerBulkRequest defines model for PeerBulkRequest.
+type PeerBulkRequest struct {
+	// Action Action applied to all the peers
+	Action PeerBulkRequestAction `json:"action"`
+
+	// Enabled New value of the setting changed by the set_login_expiration and set_ssh actions
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// GroupIds IDs of the groups to assign or unassign, required by the assign_groups and unassign_groups actions
+	GroupIds *[]string `json:"group_ids,omitempty"`
+
+	// PeerIds IDs of the peers the action is applied to
+	PeerIds []string `json:"peer_ids"`
+
+	// Reason Reason recorded in the activity log of the approve action
+	Reason *string `json:"reason,omitempty"`
+}
+
+// PeerBulkRequestAction Action applied to all the peers
+type PeerBulkRequestAction string
+
 // PeerMinimum defines model for PeerMinimum.
 type PeerMinimum struct {
 	// Id Peer ID
@@ -1115,6 +1589,18 @@ type PeerNetworkRangeCheck struct {
 // PeerNetworkRangeCheckAction Action to take upon policy match
 type PeerNetworkRangeCheckAction string
 
+// PeerPostQuantumStatus Post-quantum key exchange status of the peer
+type PeerPostQuantumStatus struct {
+	// Enabled Indicates whether the peer has the Rosenpass post-quantum key exchange enabled
+	Enabled bool `json:"enabled"`
+
+	// Permissive Indicates whether the peer runs Rosenpass in permissive mode, allowing connections without the post-quantum key exchange to peers that don't support it
+	Permissive bool `json:"permissive"`
+
+	// Supported Indicates whether the peer's client supports enforcing the post-quantum key exchange
+	Supported bool `json:"supported"`
+}
+
 // PeerRequest defines model for PeerRequest.
 type PeerRequest struct {
 	// ApprovalRequired (Cloud only) Indicates whether peer needs approval
@@ -1335,6 +1821,75 @@ type PolicyRuleUpdateAction string
 // PolicyRuleUpdateProtocol Policy rule type of the traffic
 type PolicyRuleUpdateProtocol string
 
+// PolicySimulationRequest Traffic to evaluate against the account policies. Exactly one destination must be set.
+type PolicySimulationRequest struct {
+	// DestinationDomain Destination domain, resolved to a peer, network resource or route
+	DestinationDomain *string `json:"destination_domain,omitempty"`
+
+	// DestinationIp Destination IP address, resolved to a peer, network resource or route
+	DestinationIp *string `json:"destination_ip,omitempty"`
+
+	// DestinationPeerId ID of the destination peer
+	DestinationPeerId *string `json:"destination_peer_id,omitempty"`
+
+	// DestinationResourceId ID of the destination network resource
+	DestinationResourceId *string `json:"destination_resource_id,omitempty"`
+
+	// Port Destination port of the traffic, only valid for tcp and udp
+	Port *int `json:"port,omitempty"`
+
+	// Protocol Protocol of the traffic
+	Protocol PolicySimulationRequestProtocol `json:"protocol"`
+
+	// SourcePeerId ID of the peer initiating the traffic
+	SourcePeerId string `json:"source_peer_id"`
+}
+
+// PolicySimulationRequestProtocol Protocol of the traffic
+type PolicySimulationRequestProtocol string
+
+// PolicySimulationResult defines model for PolicySimulationResult.
+type PolicySimulationResult struct {
+	// Allowed Whether the traffic is allowed
+	Allowed bool `json:"allowed"`
+
+	// DestinationId ID of the destination the request was resolved to
+	DestinationId *string `json:"destination_id,omitempty"`
+
+	// DestinationType Kind of destination the request was resolved to
+	DestinationType *PolicySimulationResultDestinationType `json:"destination_type,omitempty"`
+
+	// FailedPostureChecks IDs of posture checks the source peer fails
+	FailedPostureChecks []string `json:"failed_posture_checks"`
+
+	// MatchedRules Policy rules matching the traffic
+	MatchedRules []PolicySimulationRuleMatch `json:"matched_rules"`
+
+	// Reasons Human readable explanations of the verdict
+	Reasons []string `json:"reasons"`
+
+	// RoutingPeers IDs of the peers routing the traffic to the destination
+	RoutingPeers []string `json:"routing_peers"`
+}
+
+// PolicySimulationResultDestinationType Kind of destination the request was resolved to
+type PolicySimulationResultDestinationType string
+
+// PolicySimulationRuleMatch defines model for PolicySimulationRuleMatch.
+type PolicySimulationRuleMatch struct {
+	// Action Action of the matching policy rule
+	Action PolicySimulationRuleMatchAction `json:"action"`
+
+	// PolicyId ID of the policy containing the rule
+	PolicyId string `json:"policy_id"`
+
+	// RuleId ID of the matching policy rule
+	RuleId string `json:"rule_id"`
+}
+
+// PolicySimulationRuleMatchAction Action of the matching policy rule
+type PolicySimulationRuleMatchAction string
+
 // PolicyUpdate defines model for PolicyUpdate.
 type PolicyUpdate struct {
 	// Description Policy friendly description
@@ -1353,6 +1908,12 @@ type PolicyUpdate struct {
 	SourcePostureChecks *[]string `json:"source_posture_checks,omitempty"`
 }
 
+// PostQuantumCheck Posture check allowing only peers with the Rosenpass post-quantum key exchange enabled
+type PostQuantumCheck struct {
+	// RequireStrict Rejects peers running Rosenpass in permissive mode, which allows connections without the post-quantum key exchange
+	RequireStrict *bool `json:"require_strict,omitempty"`
+}
+
 // PostureCheck defines model for PostureCheck.
 type PostureCheck struct {
 	// Checks List of objects that perform the actual checks
@@ -1415,7 +1976,7 @@ type Route struct {
 	// Description Route description
 	Description string `json:"description"`
 
-	// Domains Domain list to be dynamically resolved. Max of 32 domains can be added per route configuration. Conflicts with network
+	// Domains Domain list to be dynamically resolved, wildcards like *.example.com and regex patterns starting with ~ like ~^api-[0-9]+\.example\.com$ are resolved by intercepting DNS queries. Max of 32 domains can be added per route configuration. Conflicts with network
 	Domains *[]string `json:"domains,omitempty"`
 
 	// Enabled Route status
@@ -1460,7 +2021,7 @@ type RouteRequest struct {
 	// Description Route description
 	Description string `json:"description"`
 
-	// Domains Domain list to be dynamically resolved. Max of 32 domains can be added per route configuration. Conflicts with network
+	// Domains Domain list to be dynamically resolved, wildcards like *.example.com and regex patterns starting with ~ like ~^api-[0-9]+\.example\.com$ are resolved by intercepting DNS queries. Max of 32 domains can be added per route configuration. Conflicts with network
 	Domains *[]string `json:"domains,omitempty"`
 
 	// Enabled Route status
@@ -1508,6 +2069,9 @@ type SetupKey struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Restrictions on the devices that can register with the setup key
+	Constraints SetupKeyConstraints `json:"constraints"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1556,6 +2120,9 @@ type SetupKeyBase struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Restrictions on the devices that can register with the setup key
+	Constraints SetupKeyConstraints `json:"constraints"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1593,6 +2160,18 @@ type SetupKeyBase struct {
 	Valid bool `json:"valid"`
 }
 
+// SetupKeyBulkRequest defines model for SetupKeyBulkRequest.
+type SetupKeyBulkRequest struct {
+	// Action Action applied to all the setup keys
+	Action SetupKeyBulkRequestAction `json:"action"`
+
+	// KeyIds IDs of the setup keys the action is applied to
+	KeyIds []string `json:"key_ids"`
+}
+
+// SetupKeyBulkRequestAction Action applied to all the setup keys
+type SetupKeyBulkRequestAction string
+
 // SetupKeyClear defines model for SetupKeyClear.
 type SetupKeyClear struct {
 	// AllowExtraDnsLabels Allow extra DNS labels to be added to the peer
@@ -1601,6 +2180,9 @@ type SetupKeyClear struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Restrictions on the devices that can register with the setup key
+	Constraints SetupKeyConstraints `json:"constraints"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1641,6 +2223,27 @@ type SetupKeyClear struct {
 	Valid bool `json:"valid"`
 }
 
+// SetupKeyConstraints Restrictions on the devices that can register with the setup key
+type SetupKeyConstraints struct {
+	// AllowedCidrs Networks the peer public connection IP must belong to, any network when empty
+	AllowedCidrs *[]string `json:"allowed_cidrs,omitempty"`
+
+	// AllowedOs Operating systems of the peers allowed to register, any operating system when empty
+	AllowedOs *[]SetupKeyConstraintsAllowedOs `json:"allowed_os,omitempty"`
+
+	// HostnamePattern Regular expression the whole peer hostname must match
+	HostnamePattern *string `json:"hostname_pattern,omitempty"`
+
+	// InventoryDevicesOnly Allow only devices listed in the account inventory to register with the key
+	InventoryDevicesOnly bool `json:"inventory_devices_only"`
+
+	// MinClientVersion Minimum NetBird client version of the peers allowed to register
+	MinClientVersion *string `json:"min_client_version,omitempty"`
+}
+
+// SetupKeyConstraintsAllowedOs defines model for SetupKeyConstraints.AllowedOs.
+type SetupKeyConstraintsAllowedOs string
+
 // SetupKeyRequest defines model for SetupKeyRequest.
 type SetupKeyRequest struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
@@ -1650,6 +2253,30 @@ type SetupKeyRequest struct {
 	Revoked bool `json:"revoked"`
 }
 
+// StreamEvent defines model for StreamEvent.
+type StreamEvent struct {
+	// AccountId The ID of the account the event occurred in
+	AccountId string `json:"account_id"`
+
+	// Data The details of the event
+	Data map[string]interface{} `json:"data"`
+
+	// GroupIds The groups of the peer the event is related to
+	GroupIds *[]string `json:"group_ids,omitempty"`
+
+	// Id Event identifier, send it back in the Last-Event-ID header to resume the stream
+	Id string `json:"id"`
+
+	// PeerId The ID of the peer the event is related to
+	PeerId *string `json:"peer_id,omitempty"`
+
+	// Timestamp The date and time when the event occurred
+	Timestamp time.Time `json:"timestamp"`
+
+	// Type Event type, either peer.connected, peer.disconnected or the code of an activity event
+	Type string `json:"type"`
+}
+
 // User defines model for User.
 type User struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
@@ -1729,6 +2356,42 @@ type UserRequest struct {
 	Role string `json:"role"`
 }
 
+// PageCursor defines model for PageCursor.
+type PageCursor = string
+
+// PageLimit defines model for PageLimit.
+type PageLimit = int
+
+// SortOrder defines model for SortOrder.
+type SortOrder string
+
+// GetApiEventsAuditParams defines parameters for GetApiEventsAudit.
+type GetApiEventsAuditParams struct {
+	// IncludeChildren Includes the events of the child accounts
+	IncludeChildren *bool `form:"include_children,omitempty" json:"include_children,omitempty"`
+}
+
+// GetApiEventsStreamParams defines parameters for GetApiEventsStream.
+type GetApiEventsStreamParams struct {
+	// Type Only streams events of the given types
+	Type *[]string `form:"type,omitempty" json:"type,omitempty"`
+
+	// GroupId Only streams events of peers in the given groups
+	GroupId *[]string `form:"group_id,omitempty" json:"group_id,omitempty"`
+
+	// LastEventID Resumes the stream after the event with the given ID
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// GetApiPeersApprovalRequestsParams defines parameters for GetApiPeersApprovalRequests.
+type GetApiPeersApprovalRequestsParams struct {
+	// Status Filters the requests by status
+	Status *GetApiPeersApprovalRequestsParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetApiPeersApprovalRequestsParamsStatus defines parameters for GetApiPeersApprovalRequests.
+type GetApiPeersApprovalRequestsParamsStatus string
+
 // GetApiPeersParams defines parameters for GetApiPeers.
 type GetApiPeersParams struct {
 	// Name Filter peers by name
@@ -1736,8 +2399,50 @@ type GetApiPeersParams struct {
 
 	// Ip Filter peers by IP address
 	Ip *string `form:"ip,omitempty" json:"ip,omitempty"`
+
+	// DnsLabel Filter peers by a substring of the DNS label
+	DnsLabel *string `form:"dns_label,omitempty" json:"dns_label,omitempty"`
+
+	// GroupId Filter peers by group membership
+	GroupId *string `form:"group_id,omitempty" json:"group_id,omitempty"`
+
+	// Connected Filter peers by connection status
+	Connected *bool `form:"connected,omitempty" json:"connected,omitempty"`
+
+	// Os Filter peers by OS type (e.g. linux) or a substring of the OS name (e.g. Ubuntu), case-insensitive
+	Os *string `form:"os,omitempty" json:"os,omitempty"`
+
+	// Version Filter peers by a prefix of the NetBird version
+	Version *string `form:"version,omitempty" json:"version,omitempty"`
+
+	// LoginExpired Filter peers by login expiration status
+	LoginExpired *bool `form:"login_expired,omitempty" json:"login_expired,omitempty"`
+
+	// ApprovalRequired Filter peers by approval status
+	ApprovalRequired *bool `form:"approval_required,omitempty" json:"approval_required,omitempty"`
+
+	// LastSeenAfter Return peers last seen at or after this time
+	LastSeenAfter *time.Time `form:"last_seen_after,omitempty" json:"last_seen_after,omitempty"`
+
+	// LastSeenBefore Return peers last seen before this time
+	LastSeenBefore *time.Time `form:"last_seen_before,omitempty" json:"last_seen_before,omitempty"`
+
+	// SortBy Field to sort the peers by, defaults to name
+	SortBy *GetApiPeersParamsSortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// SortOrder Sort order, defaults to asc
+	SortOrder *SortOrder `form:"sort_order,omitempty" json:"sort_order,omitempty"`
+
+	// Limit Maximum number of objects to return. Without a limit all the objects are returned.
+	Limit *PageLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor returned in the X-Next-Cursor header of the previous page
+	Cursor *PageCursor `form:"cursor,omitempty" json:"cursor,omitempty"`
 }
 
+// GetApiPeersParamsSortBy defines parameters for GetApiPeers.
+type GetApiPeersParamsSortBy string
+
 // GetApiPeersPeerIdIngressPortsParams defines parameters for GetApiPeersPeerIdIngressPorts.
 type GetApiPeersPeerIdIngressPortsParams struct {
 	// Name Filters ingress port allocations by name
@@ -1748,11 +2453,41 @@ type GetApiPeersPeerIdIngressPortsParams struct {
 type GetApiUsersParams struct {
 	// ServiceUser Filters users and returns either regular users or service users
 	ServiceUser *bool `form:"service_user,omitempty" json:"service_user,omitempty"`
+
+	// Role Filter users by role
+	Role *string `form:"role,omitempty" json:"role,omitempty"`
+
+	// Blocked Filter users by blocked status
+	Blocked *bool `form:"blocked,omitempty" json:"blocked,omitempty"`
+
+	// GroupId Filter users by auto-assigned group
+	GroupId *string `form:"group_id,omitempty" json:"group_id,omitempty"`
+
+	// SortBy Field to sort the users by, defaults to id
+	SortBy *GetApiUsersParamsSortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// SortOrder Sort order, defaults to asc
+	SortOrder *SortOrder `form:"sort_order,omitempty" json:"sort_order,omitempty"`
+
+	// Limit Maximum number of objects to return. Without a limit all the objects are returned.
+	Limit *PageLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor returned in the X-Next-Cursor header of the previous page
+	Cursor *PageCursor `form:"cursor,omitempty" json:"cursor,omitempty"`
 }
 
+// GetApiUsersParamsSortBy defines parameters for GetApiUsers.
+type GetApiUsersParamsSortBy string
+
+// PostApiAccountsChildrenTemplatesJSONRequestBody defines body for PostApiAccountsChildrenTemplates for application/json ContentType.
+type PostApiAccountsChildrenTemplatesJSONRequestBody = ChildAccountTemplatesRequest
+
 // PutApiAccountsAccountIdJSONRequestBody defines body for PutApiAccountsAccountId for application/json ContentType.
 type PutApiAccountsAccountIdJSONRequestBody = AccountRequest
 
+// PutApiAccountsAccountIdParentJSONRequestBody defines body for PutApiAccountsAccountIdParent for application/json ContentType.
+type PutApiAccountsAccountIdParentJSONRequestBody = AccountParentRequest
+
 // PostApiDnsNameserversJSONRequestBody defines body for PostApiDnsNameservers for application/json ContentType.
 type PostApiDnsNameserversJSONRequestBody = NameserverGroupRequest
 
@@ -1765,6 +2500,9 @@ type PutApiDnsSettingsJSONRequestBody = DNSSettings
 // PostApiGroupsJSONRequestBody defines body for PostApiGroups for application/json ContentType.
 type PostApiGroupsJSONRequestBody = GroupRequest
 
+// PostApiGroupsPreviewJSONRequestBody defines body for PostApiGroupsPreview for application/json ContentType.
+type PostApiGroupsPreviewJSONRequestBody = GroupQuery
+
 // PutApiGroupsGroupIdJSONRequestBody defines body for PutApiGroupsGroupId for application/json ContentType.
 type PutApiGroupsGroupIdJSONRequestBody = GroupRequest
 
@@ -1774,6 +2512,15 @@ type PostApiIngressPeersJSONRequestBody = IngressPeerCreateRequest
 // PutApiIngressPeersIngressPeerIdJSONRequestBody defines body for PutApiIngressPeersIngressPeerId for application/json ContentType.
 type PutApiIngressPeersIngressPeerIdJSONRequestBody = IngressPeerUpdateRequest
 
+// PostApiInventoryDevicesJSONRequestBody defines body for PostApiInventoryDevices for application/json ContentType.
+type PostApiInventoryDevicesJSONRequestBody = InventoryDeviceRequest
+
+// PostApiInventoryDevicesImportJSONRequestBody defines body for PostApiInventoryDevicesImport for application/json ContentType.
+type PostApiInventoryDevicesImportJSONRequestBody = InventoryImportRequest
+
+// PutApiInventoryDevicesDeviceIdJSONRequestBody defines body for PutApiInventoryDevicesDeviceId for application/json ContentType.
+type PutApiInventoryDevicesDeviceIdJSONRequestBody = InventoryDeviceRequest
+
 // PostApiNetworksJSONRequestBody defines body for PostApiNetworks for application/json ContentType.
 type PostApiNetworksJSONRequestBody = NetworkRequest
 
@@ -1792,18 +2539,36 @@ type PostApiNetworksNetworkIdRoutersJSONRequestBody = NetworkRouterRequest
 // PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody defines body for PutApiNetworksNetworkIdRoutersRouterId for application/json ContentType.
 type PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody = NetworkRouterRequest
 
+// PostApiPeersApprovalRulesJSONRequestBody defines body for PostApiPeersApprovalRules for application/json ContentType.
+type PostApiPeersApprovalRulesJSONRequestBody = PeerApprovalRuleRequest
+
+// PutApiPeersApprovalRulesRuleIdJSONRequestBody defines body for PutApiPeersApprovalRulesRuleId for application/json ContentType.
+type PutApiPeersApprovalRulesRuleIdJSONRequestBody = PeerApprovalRuleRequest
+
+// PostApiPeersBulkJSONRequestBody defines body for PostApiPeersBulk for application/json ContentType.
+type PostApiPeersBulkJSONRequestBody = PeerBulkRequest
+
 // PutApiPeersPeerIdJSONRequestBody defines body for PutApiPeersPeerId for application/json ContentType.
 type PutApiPeersPeerIdJSONRequestBody = PeerRequest
 
+// PostApiPeersPeerIdApproveJSONRequestBody defines body for PostApiPeersPeerIdApprove for application/json ContentType.
+type PostApiPeersPeerIdApproveJSONRequestBody = PeerApprovalDecision
+
 // PostApiPeersPeerIdIngressPortsJSONRequestBody defines body for PostApiPeersPeerIdIngressPorts for application/json ContentType.
 type PostApiPeersPeerIdIngressPortsJSONRequestBody = IngressPortAllocationRequest
 
 // PutApiPeersPeerIdIngressPortsAllocationIdJSONRequestBody defines body for PutApiPeersPeerIdIngressPortsAllocationId for application/json ContentType.
 type PutApiPeersPeerIdIngressPortsAllocationIdJSONRequestBody = IngressPortAllocationRequest
 
+// PostApiPeersPeerIdRejectJSONRequestBody defines body for PostApiPeersPeerIdReject for application/json ContentType.
+type PostApiPeersPeerIdRejectJSONRequestBody = PeerApprovalDecision
+
 // PostApiPoliciesJSONRequestBody defines body for PostApiPolicies for application/json ContentType.
 type PostApiPoliciesJSONRequestBody = PolicyUpdate
 
+// PostApiPoliciesSimulateJSONRequestBody defines body for PostApiPoliciesSimulate for application/json ContentType.
+type PostApiPoliciesSimulateJSONRequestBody = PolicySimulationRequest
+
 // PutApiPoliciesPolicyIdJSONRequestBody defines body for PutApiPoliciesPolicyId for application/json ContentType.
 type PutApiPoliciesPolicyIdJSONRequestBody = PolicyCreate
 
@@ -1822,6 +2587,9 @@ type PutApiRoutesRouteIdJSONRequestBody = RouteRequest
 // PostApiSetupKeysJSONRequestBody defines body for PostApiSetupKeys for application/json ContentType.
 type PostApiSetupKeysJSONRequestBody = CreateSetupKeyRequest
 
+// PostApiSetupKeysBulkJSONRequestBody defines body for PostApiSetupKeysBulk for application/json ContentType.
+type PostApiSetupKeysBulkJSONRequestBody = SetupKeyBulkRequest
+
 // PutApiSetupKeysKeyIdJSONRequestBody defines body for PutApiSetupKeysKeyId for application/json ContentType.
 type PutApiSetupKeysKeyIdJSONRequestBody = SetupKeyRequest
 
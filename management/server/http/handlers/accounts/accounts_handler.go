@@ -24,6 +24,11 @@ type handler struct {
 
 func AddEndpoints(accountManager account.Manager, settingsManager settings.Manager, router *mux.Router) {
 	accountsHandler := newHandler(accountManager, settingsManager)
+	router.HandleFunc("/accounts/children", accountsHandler.getChildAccounts).Methods("GET", "OPTIONS")
+	router.HandleFunc("/accounts/children/templates", accountsHandler.pushChildAccountTemplates).Methods("POST", "OPTIONS")
+	router.HandleFunc("/accounts/children/{childAccountId}", accountsHandler.removeChildAccount).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/accounts/children/{childAccountId}/accept", accountsHandler.acceptChildAccount).Methods("POST", "OPTIONS")
+	router.HandleFunc("/accounts/{accountId}/parent", accountsHandler.setAccountParent).Methods("PUT", "OPTIONS")
 	router.HandleFunc("/accounts/{accountId}", accountsHandler.updateAccount).Methods("PUT", "OPTIONS")
 	router.HandleFunc("/accounts/{accountId}", accountsHandler.deleteAccount).Methods("DELETE", "OPTIONS")
 	router.HandleFunc("/accounts", accountsHandler.getAllAccounts).Methods("GET", "OPTIONS")
@@ -113,6 +118,9 @@ func (h *handler) updateAccount(w http.ResponseWriter, r *http.Request) {
 	if req.Settings.RoutingPeerDnsResolutionEnabled != nil {
 		settings.RoutingPeerDNSResolutionEnabled = *req.Settings.RoutingPeerDnsResolutionEnabled
 	}
+	if req.Settings.PostQuantumRequiredGroups != nil {
+		settings.PostQuantumRequiredGroups = *req.Settings.PostQuantumRequiredGroups
+	}
 
 	updatedAccount, err := h.accountManager.UpdateAccountSettings(r.Context(), accountID, userID, settings)
 	if err != nil {
@@ -149,12 +157,157 @@ func (h *handler) deleteAccount(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
 }
 
+// getChildAccounts is HTTP GET handler that returns the accounts managed by the admins of the account
+func (h *handler) getChildAccounts(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	childAccounts, err := h.accountManager.GetChildAccounts(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := make([]*api.ChildAccount, 0, len(childAccounts))
+	for _, childAccount := range childAccounts {
+		resp = append(resp, &api.ChildAccount{
+			Id:        childAccount.ID,
+			Domain:    childAccount.Domain,
+			CreatedBy: childAccount.CreatedBy,
+			CreatedAt: childAccount.CreatedAt,
+			Pending:   childAccount.Pending,
+		})
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
+// setAccountParent is HTTP PUT handler that asks to link the account to a parent account
+func (h *handler) setAccountParent(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	accountID := mux.Vars(r)["accountId"]
+	if len(accountID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid account ID"), w)
+		return
+	}
+
+	var req api.PutApiAccountsAccountIdParentJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	err = h.accountManager.SetAccountParent(r.Context(), accountID, userAuth.UserId, req.ParentAccountId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// removeChildAccount is HTTP DELETE handler that unlinks a child account from the account
+func (h *handler) removeChildAccount(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	childAccountID := mux.Vars(r)["childAccountId"]
+	if len(childAccountID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid child account ID"), w)
+		return
+	}
+
+	err = h.accountManager.RemoveChildAccount(r.Context(), userAuth.AccountId, userAuth.UserId, childAccountID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// acceptChildAccount is HTTP POST handler that links an account that asked to be managed by the account
+func (h *handler) acceptChildAccount(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	childAccountID := mux.Vars(r)["childAccountId"]
+	if len(childAccountID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid child account ID"), w)
+		return
+	}
+
+	err = h.accountManager.AcceptChildAccount(r.Context(), userAuth.AccountId, userAuth.UserId, childAccountID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// pushChildAccountTemplates is HTTP POST handler that copies policies, posture checks and nameserver groups to the child accounts
+func (h *handler) pushChildAccountTemplates(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiAccountsChildrenTemplatesJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	templates := &types.ChildAccountTemplates{}
+	if req.PolicyIds != nil {
+		templates.PolicyIDs = *req.PolicyIds
+	}
+	if req.PostureCheckIds != nil {
+		templates.PostureCheckIDs = *req.PostureCheckIds
+	}
+	if req.NameserverGroupIds != nil {
+		templates.NameServerGroupIDs = *req.NameserverGroupIds
+	}
+	if req.ChildAccountIds != nil {
+		templates.ChildAccountIDs = *req.ChildAccountIds
+	}
+
+	results, err := h.accountManager.PushTemplatesToChildAccounts(r.Context(), userAuth.AccountId, userAuth.UserId, templates)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, types.ToBulkOperationResponse(results))
+}
+
 func toAccountResponse(accountID string, settings *types.Settings) *api.Account {
 	jwtAllowGroups := settings.JWTAllowGroups
 	if jwtAllowGroups == nil {
 		jwtAllowGroups = []string{}
 	}
 
+	postQuantumRequiredGroups := settings.PostQuantumRequiredGroups
+	if postQuantumRequiredGroups == nil {
+		postQuantumRequiredGroups = []string{}
+	}
+
 	apiSettings := api.AccountSettings{
 		PeerLoginExpiration:             int(settings.PeerLoginExpiration.Seconds()),
 		PeerLoginExpirationEnabled:      settings.PeerLoginExpirationEnabled,
@@ -166,6 +319,7 @@ func toAccountResponse(accountID string, settings *types.Settings) *api.Account
 		JwtAllowGroups:                  &jwtAllowGroups,
 		RegularUsersViewBlocked:         settings.RegularUsersViewBlocked,
 		RoutingPeerDnsResolutionEnabled: &settings.RoutingPeerDNSResolutionEnabled,
+		PostQuantumRequiredGroups:       &postQuantumRequiredGroups,
 	}
 
 	if settings.Extra != nil {
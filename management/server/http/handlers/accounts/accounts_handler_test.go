@@ -102,6 +102,7 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				JwtAllowGroups:                  &[]string{},
 				RegularUsersViewBlocked:         true,
 				RoutingPeerDnsResolutionEnabled: br(false),
+				PostQuantumRequiredGroups:       &[]string{},
 			},
 			expectedArray: true,
 			expectedID:    accountID,
@@ -122,6 +123,7 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				JwtAllowGroups:                  &[]string{},
 				RegularUsersViewBlocked:         false,
 				RoutingPeerDnsResolutionEnabled: br(false),
+				PostQuantumRequiredGroups:       &[]string{},
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -142,6 +144,7 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				JwtAllowGroups:                  &[]string{"test"},
 				RegularUsersViewBlocked:         true,
 				RoutingPeerDnsResolutionEnabled: br(false),
+				PostQuantumRequiredGroups:       &[]string{},
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -162,6 +165,28 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				JwtAllowGroups:                  &[]string{},
 				RegularUsersViewBlocked:         true,
 				RoutingPeerDnsResolutionEnabled: br(false),
+				PostQuantumRequiredGroups:       &[]string{},
+			},
+			expectedArray: false,
+			expectedID:    accountID,
+		},
+		{
+			name:           "PutAccount OK with post-quantum required groups",
+			expectedBody:   true,
+			requestType:    http.MethodPut,
+			requestPath:    "/api/accounts/" + accountID,
+			requestBody:    bytes.NewBufferString("{\"settings\": {\"peer_login_expiration\": 15552000,\"peer_login_expiration_enabled\": true,\"post_quantum_required_groups\":[\"servers\"]}}"),
+			expectedStatus: http.StatusOK,
+			expectedSettings: api.AccountSettings{
+				PeerLoginExpiration:             15552000,
+				PeerLoginExpirationEnabled:      true,
+				GroupsPropagationEnabled:        br(false),
+				JwtGroupsClaimName:              sr(""),
+				JwtGroupsEnabled:                br(false),
+				JwtAllowGroups:                  &[]string{},
+				RegularUsersViewBlocked:         false,
+				RoutingPeerDnsResolutionEnabled: br(false),
+				PostQuantumRequiredGroups:       &[]string{"servers"},
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -243,3 +268,125 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestAccounts_ChildAccounts(t *testing.T) {
+	accountID := "test_account"
+	userID := "test_user"
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var pushedTemplates *types.ChildAccountTemplates
+	var parentAccountID string
+
+	h := &handler{
+		accountManager: &mock_server.MockAccountManager{
+			GetChildAccountsFunc: func(_ context.Context, _, _ string) ([]*types.ChildAccount, error) {
+				return []*types.ChildAccount{
+					{ID: "child", Domain: "child.com", CreatedBy: userID, CreatedAt: createdAt},
+					{ID: "pending", Domain: "pending.com", CreatedBy: userID, CreatedAt: createdAt, Pending: true},
+				}, nil
+			},
+			SetAccountParentFunc: func(_ context.Context, _, _, parentID string) error {
+				parentAccountID = parentID
+				return nil
+			},
+			AcceptChildAccountFunc: func(_ context.Context, _, _, childAccountID string) error {
+				if childAccountID != "pending" {
+					return status.Errorf(status.NotFound, "pending child account %s not found", childAccountID)
+				}
+				return nil
+			},
+			RemoveChildAccountFunc: func(_ context.Context, _, _, childAccountID string) error {
+				if childAccountID != "child" {
+					return status.Errorf(status.NotFound, "child account %s not found", childAccountID)
+				}
+				return nil
+			},
+			PushTemplatesToChildAccountsFunc: func(_ context.Context, _, _ string, templates *types.ChildAccountTemplates) ([]*types.BulkItemResult, error) {
+				pushedTemplates = templates
+				return []*types.BulkItemResult{
+					{ID: "child"},
+					{ID: "unknown", Err: status.Errorf(status.NotFound, "child account unknown not found")},
+				}, nil
+			},
+		},
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/accounts/children", h.getChildAccounts).Methods("GET")
+	router.HandleFunc("/api/accounts/children/templates", h.pushChildAccountTemplates).Methods("POST")
+	router.HandleFunc("/api/accounts/children/{childAccountId}", h.removeChildAccount).Methods("DELETE")
+	router.HandleFunc("/api/accounts/children/{childAccountId}/accept", h.acceptChildAccount).Methods("POST")
+	router.HandleFunc("/api/accounts/{accountId}/parent", h.setAccountParent).Methods("PUT")
+
+	serve := func(method, path string, body io.Reader) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, body)
+		req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+			UserId:    userID,
+			AccountId: accountID,
+		})
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("list child accounts", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "/api/accounts/children", nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var got []api.ChildAccount
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+		assert.Equal(t, []api.ChildAccount{
+			{Id: "child", Domain: "child.com", CreatedBy: userID, CreatedAt: createdAt},
+			{Id: "pending", Domain: "pending.com", CreatedBy: userID, CreatedAt: createdAt, Pending: true},
+		}, got)
+	})
+
+	t.Run("set parent account", func(t *testing.T) {
+		recorder := serve(http.MethodPut, "/api/accounts/"+accountID+"/parent", bytes.NewBufferString(`{"parent_account_id": "parent"}`))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "parent", parentAccountID)
+	})
+
+	t.Run("set parent account with invalid body", func(t *testing.T) {
+		recorder := serve(http.MethodPut, "/api/accounts/"+accountID+"/parent", bytes.NewBufferString(`{`))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("accept child account", func(t *testing.T) {
+		recorder := serve(http.MethodPost, "/api/accounts/children/pending/accept", nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("accept unknown child account", func(t *testing.T) {
+		recorder := serve(http.MethodPost, "/api/accounts/children/unknown/accept", nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("remove child account", func(t *testing.T) {
+		recorder := serve(http.MethodDelete, "/api/accounts/children/child", nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("remove unknown child account", func(t *testing.T) {
+		recorder := serve(http.MethodDelete, "/api/accounts/children/unknown", nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("push templates", func(t *testing.T) {
+		body := `{"policy_ids": ["policy"], "nameserver_group_ids": ["ns"], "child_account_ids": ["child", "unknown"]}`
+		recorder := serve(http.MethodPost, "/api/accounts/children/templates", bytes.NewBufferString(body))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		assert.Equal(t, &types.ChildAccountTemplates{
+			PolicyIDs:          []string{"policy"},
+			NameServerGroupIDs: []string{"ns"},
+			ChildAccountIDs:    []string{"child", "unknown"},
+		}, pushedTemplates)
+
+		var got api.BulkOperationResponse
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+		assert.Len(t, got.Results, 2)
+		assert.Equal(t, api.BulkOperationItemResultStatusSuccess, got.Results[0].Status)
+		assert.Equal(t, api.BulkOperationItemResultStatusFailed, got.Results[1].Status)
+	})
+}
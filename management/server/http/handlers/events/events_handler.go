@@ -3,6 +3,7 @@ package events
 import (
 	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +24,7 @@ func AddEndpoints(accountManager account.Manager, router *mux.Router) {
 	eventsHandler := newHandler(accountManager)
 	router.HandleFunc("/events", eventsHandler.getAllEvents).Methods("GET", "OPTIONS")
 	router.HandleFunc("/events/audit", eventsHandler.getAllEvents).Methods("GET", "OPTIONS")
+	router.HandleFunc("/events/stream", eventsHandler.streamEvents).Methods("GET", "OPTIONS")
 }
 
 // newHandler creates a new events handler
@@ -41,12 +43,31 @@ func (h *handler) getAllEvents(w http.ResponseWriter, r *http.Request) {
 
 	accountID, userID := userAuth.AccountId, userAuth.UserId
 
+	includeChildren, err := util.ParseBoolQuery(r, "include_children")
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
 	accountEvents, err := h.accountManager.GetEvents(r.Context(), accountID, userID)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
 	}
 
+	if includeChildren != nil && *includeChildren {
+		childEvents, err := h.accountManager.GetChildAccountsEvents(r.Context(), accountID, userID)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		accountEvents = append(accountEvents, childEvents...)
+		slices.SortStableFunc(accountEvents, func(a, b *activity.Event) int {
+			return b.Timestamp.Compare(a.Timestamp)
+		})
+	}
+
 	events := make([]*api.Event, len(accountEvents))
 	for i, e := range accountEvents {
 		events[i] = toEventResponse(e)
@@ -73,5 +94,8 @@ func toEventResponse(event *activity.Event) *api.Event {
 		Timestamp:      event.Timestamp,
 		Meta:           meta,
 	}
+	if event.AccountID != "" {
+		e.AccountId = &event.AccountID
+	}
 	return e
 }
@@ -0,0 +1,153 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/eventstream"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+// streamKeepAliveInterval is how often a comment is sent to keep idle streams open through proxies
+var streamKeepAliveInterval = 30 * time.Second
+
+// streamEvents is HTTP GET handler that streams the account events as Server-Sent Events until the client disconnects
+func (h *handler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	filter := eventstream.Filter{
+		Types:    parseListQuery(r, "type"),
+		GroupIDs: parseListQuery(r, "group_id"),
+	}
+
+	subscription, err := h.accountManager.SubscribeEvents(r.Context(), userAuth.AccountId, userAuth.UserId, filter, lastEventID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	defer h.accountManager.UnsubscribeEvents(subscription)
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range subscription.Replay() {
+		if err = writeStreamEvent(w, event); err != nil {
+			return
+		}
+	}
+
+	if err = rc.Flush(); err != nil {
+		log.WithContext(r.Context()).Errorf("failed to flush the event stream: %v", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// the subscriber fell behind, the client resumes from the last received event when reconnecting
+				return
+			}
+			err = writeStreamEvent(w, event)
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			log.WithContext(r.Context()).Debugf("stopped streaming events: %v", err)
+			return
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, event *eventstream.Event) error {
+	data, err := json.Marshal(toStreamEventResponse(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func toStreamEventResponse(event *eventstream.Event) *api.StreamEvent {
+	data := event.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	resp := &api.StreamEvent{
+		Id:        strconv.FormatUint(event.ID, 10),
+		Type:      event.Type,
+		AccountId: event.AccountID,
+		Timestamp: event.Timestamp,
+		Data:      data,
+	}
+	if event.PeerID != "" {
+		resp.PeerId = &event.PeerID
+	}
+	if len(event.GroupIDs) > 0 {
+		resp.GroupIds = &event.GroupIDs
+	}
+
+	return resp
+}
+
+// parseLastEventID reads the ID of the last received event from the Last-Event-ID header sent by reconnecting clients
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(status.InvalidArgument, "invalid Last-Event-ID header")
+	}
+	return id, nil
+}
+
+// parseListQuery returns the values of a query parameter given either repeated or comma separated
+func parseListQuery(r *http.Request, name string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
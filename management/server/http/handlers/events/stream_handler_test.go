@@ -0,0 +1,153 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/eventstream"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+func TestEvents_StreamEvents(t *testing.T) {
+	broker := eventstream.NewBroker(eventstream.DefaultHistorySize)
+	subscribed := make(chan eventstream.Filter, 1)
+
+	h := &handler{
+		accountManager: &mock_server.MockAccountManager{
+			SubscribeEventsFunc: func(_ context.Context, accountID, userID string, filter eventstream.Filter, lastEventID uint64) (*eventstream.Subscription, error) {
+				if userID != "test_user" {
+					return nil, status.Errorf(status.PermissionDenied, "only users with admin power can subscribe to events")
+				}
+				subscription := broker.Subscribe(accountID, filter, lastEventID)
+				subscribed <- filter
+				return subscription, nil
+			},
+			UnsubscribeEventsFunc: broker.Unsubscribe,
+		},
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/events/stream", h.streamEvents).Methods("GET")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = nbcontext.SetUserAuthInRequest(r, nbcontext.UserAuth{
+			UserId:    r.Header.Get("X-Test-User"),
+			AccountId: "test_account",
+		})
+		router.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	stream := func(t *testing.T, userID, query, lastEventID string) (*http.Response, context.CancelFunc) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/events/stream"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Test-User", userID)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp, cancel
+	}
+
+	t.Run("regular user can't subscribe", func(t *testing.T) {
+		resp, cancel := stream(t, "regular_user", "", "")
+		defer cancel()
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid last event ID", func(t *testing.T) {
+		resp, cancel := stream(t, "test_user", "", "abc")
+		defer cancel()
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	var lastID string
+
+	t.Run("streams matching events", func(t *testing.T) {
+		resp, cancel := stream(t, "test_user", "?type=peer.connected,peer.disconnected&group_id=devs", "")
+		defer cancel()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		filter := <-subscribed
+		assert.Equal(t, []string{"peer.connected", "peer.disconnected"}, filter.Types)
+		assert.Equal(t, []string{"devs"}, filter.GroupIDs)
+
+		broker.Publish(&eventstream.Event{Type: "peer.approve", AccountID: "test_account", PeerID: "peer1", GroupIDs: []string{"devs"}})
+		broker.Publish(&eventstream.Event{Type: eventstream.PeerConnected, AccountID: "test_account", PeerID: "peer2", GroupIDs: []string{"ops"}})
+		broker.Publish(&eventstream.Event{
+			Type:      eventstream.PeerConnected,
+			AccountID: "test_account",
+			PeerID:    "peer3",
+			GroupIDs:  []string{"devs"},
+			Data:      map[string]any{"name": "peer-3"},
+		})
+
+		id, event := readStreamEvent(t, bufio.NewReader(resp.Body))
+		assert.Equal(t, eventstream.PeerConnected, event.Type)
+		assert.Equal(t, id, event.Id)
+		require.NotNil(t, event.PeerId)
+		assert.Equal(t, "peer3", *event.PeerId)
+		assert.Equal(t, "peer-3", event.Data["name"])
+		lastID = id
+	})
+
+	t.Run("resumes from the last event ID", func(t *testing.T) {
+		broker.Publish(&eventstream.Event{Type: eventstream.PeerDisconnected, AccountID: "test_account", PeerID: "peer3"})
+
+		resp, cancel := stream(t, "test_user", "", lastID)
+		defer cancel()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		<-subscribed
+
+		id, event := readStreamEvent(t, bufio.NewReader(resp.Body))
+		assert.Equal(t, eventstream.PeerDisconnected, event.Type)
+		lastIDNum, _ := strconv.ParseUint(lastID, 10, 64)
+		idNum, _ := strconv.ParseUint(id, 10, 64)
+		assert.Greater(t, idNum, lastIDNum)
+	})
+}
+
+func readStreamEvent(t *testing.T, reader *bufio.Reader) (string, *api.StreamEvent) {
+	t.Helper()
+
+	var id, eventType, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "" && data != "":
+			var event api.StreamEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			assert.Equal(t, eventType, event.Type)
+			return id, &event
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
@@ -26,6 +26,7 @@ func AddEndpoints(accountManager account.Manager, router *mux.Router) {
 	groupsHandler := newHandler(accountManager)
 	router.HandleFunc("/groups", groupsHandler.getAllGroups).Methods("GET", "OPTIONS")
 	router.HandleFunc("/groups", groupsHandler.createGroup).Methods("POST", "OPTIONS")
+	router.HandleFunc("/groups/preview", groupsHandler.previewGroupQuery).Methods("POST", "OPTIONS")
 	router.HandleFunc("/groups/{groupId}", groupsHandler.updateGroup).Methods("PUT", "OPTIONS")
 	router.HandleFunc("/groups/{groupId}", groupsHandler.getGroup).Methods("GET", "OPTIONS")
 	router.HandleFunc("/groups/{groupId}", groupsHandler.deleteGroup).Methods("DELETE", "OPTIONS")
@@ -118,8 +119,15 @@ func (h *handler) updateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var query *types.GroupQuery
+	if req.Query != nil {
+		query = &types.GroupQuery{}
+		query.FromAPIRequest(req.Query)
+	}
+
+	// peers of a dynamic group are evaluated from the query
 	var peers []string
-	if req.Peers == nil {
+	if req.Peers == nil || query != nil {
 		peers = make([]string, 0)
 	} else {
 		peers = *req.Peers
@@ -139,6 +147,7 @@ func (h *handler) updateGroup(w http.ResponseWriter, r *http.Request) {
 		Name:                 req.Name,
 		Peers:                peers,
 		Resources:            resources,
+		Query:                query,
 		Issued:               existingGroup.Issued,
 		IntegrationReference: existingGroup.IntegrationReference,
 	}
@@ -180,8 +189,15 @@ func (h *handler) createGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var query *types.GroupQuery
+	if req.Query != nil {
+		query = &types.GroupQuery{}
+		query.FromAPIRequest(req.Query)
+	}
+
+	// peers of a dynamic group are evaluated from the query
 	var peers []string
-	if req.Peers == nil {
+	if req.Peers == nil || query != nil {
 		peers = make([]string, 0)
 	} else {
 		peers = *req.Peers
@@ -200,6 +216,7 @@ func (h *handler) createGroup(w http.ResponseWriter, r *http.Request) {
 		Name:      req.Name,
 		Peers:     peers,
 		Resources: resources,
+		Query:     query,
 		Issued:    types.GroupIssuedAPI,
 	}
 
@@ -218,6 +235,43 @@ func (h *handler) createGroup(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSONObject(r.Context(), w, toGroupResponse(accountPeers, &group))
 }
 
+// previewGroupQuery returns the peers matching a dynamic group query without saving it
+func (h *handler) previewGroupQuery(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	accountID, userID := userAuth.AccountId, userAuth.UserId
+
+	var req api.PostApiGroupsPreviewJSONRequestBody
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	query := &types.GroupQuery{}
+	query.FromAPIRequest(&req)
+
+	peers, err := h.accountManager.PreviewGroupQuery(r.Context(), accountID, userID, query)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peersResponse := make([]api.PeerMinimum, 0, len(peers))
+	for _, peer := range peers {
+		peersResponse = append(peersResponse, api.PeerMinimum{
+			Id:   peer.ID,
+			Name: peer.Name,
+		})
+	}
+
+	util.WriteJSONObject(r.Context(), w, peersResponse)
+}
+
 // deleteGroup handles group deletion request
 func (h *handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
 	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
@@ -291,6 +345,7 @@ func toGroupResponse(peers []*nbpeer.Peer, group *types.Group) *api.Group {
 		Id:     group.ID,
 		Name:   group.Name,
 		Issued: (*api.GroupIssued)(&group.Issued),
+		Query:  group.Query.ToAPIResponse(),
 	}
 
 	for _, pid := range group.Peers {
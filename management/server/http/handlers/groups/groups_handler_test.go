@@ -69,6 +69,12 @@ func initGroupTestData(initGroups ...*types.Group) *handler {
 			GetPeersFunc: func(ctx context.Context, accountID, userID, nameFilter, ipFilter string) ([]*nbpeer.Peer, error) {
 				return maps.Values(TestPeers), nil
 			},
+			PreviewGroupQueryFunc: func(_ context.Context, _, _ string, query *types.GroupQuery) ([]*nbpeer.Peer, error) {
+				if err := query.Validate(); err != nil {
+					return nil, err
+				}
+				return []*nbpeer.Peer{TestPeers["A"]}, nil
+			},
 			DeleteGroupFunc: func(_ context.Context, accountID, userId, groupID string) error {
 				if groupID == "linked-grp" {
 					err := &server.GroupLinkError{
@@ -187,6 +193,26 @@ func TestWriteGroup(t *testing.T) {
 				Issued: (*api.GroupIssued)(&groupIssuedAPI),
 			},
 		},
+		{
+			name:        "Write Group POST with query ignores peers",
+			requestType: http.MethodPost,
+			requestPath: "/api/groups",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{"name":"Linux","peers":["peer-A-ID"],"query":{"match":"all","rules":[{"attribute":"os","operator":"is","values":["linux"]}]}}`)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedGroup: &api.Group{
+				Id:     "id-was-set",
+				Name:   "Linux",
+				Issued: (*api.GroupIssued)(&groupIssuedAPI),
+				Query: &api.GroupQuery{
+					Match: api.GroupQueryMatchAll,
+					Rules: []api.GroupQueryRule{
+						{Attribute: api.GroupQueryRuleAttributeOs, Operator: api.GroupQueryRuleOperatorIs, Values: []string{"linux"}},
+					},
+				},
+			},
+		},
 		{
 			name:        "Write Group POST Invalid Name",
 			requestType: http.MethodPost,
@@ -364,3 +390,70 @@ func TestDeleteGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestPreviewGroupQuery(t *testing.T) {
+	tt := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+		expectedPeers  []api.PeerMinimum
+	}{
+		{
+			name:           "Preview OK",
+			requestBody:    `{"match":"any","rules":[{"attribute":"hostname","operator":"matches","values":["web-*"]}]}`,
+			expectedStatus: http.StatusOK,
+			expectedPeers:  []api.PeerMinimum{{Id: "peer-A-ID"}},
+		},
+		{
+			name:           "Preview invalid query",
+			requestBody:    `{"match":"any","rules":[]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Preview invalid JSON",
+			requestBody:    `{"match":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	p := initGroupTestData()
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/groups/preview", bytes.NewBufferString(tc.requestBody))
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+				UserId:    "test_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/groups/preview", p.previewGroupQuery).Methods("POST")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			content, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("I don't know what I expected; %v", err)
+			}
+
+			if status := recorder.Code; status != tc.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v, content: %s",
+					status, tc.expectedStatus, string(content))
+			}
+
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var got []api.PeerMinimum
+			if err = json.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Sent content is not in correct json format; %v", err)
+			}
+			assert.Equal(t, tc.expectedPeers, got)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package inventory
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/server/account"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// handler is a handler of the account device inventory
+type handler struct {
+	accountManager account.Manager
+}
+
+func AddEndpoints(accountManager account.Manager, router *mux.Router) {
+	inventoryHandler := newHandler(accountManager)
+	router.HandleFunc("/inventory/devices", inventoryHandler.getAllDevices).Methods("GET", "OPTIONS")
+	router.HandleFunc("/inventory/devices", inventoryHandler.createDevice).Methods("POST", "OPTIONS")
+	router.HandleFunc("/inventory/devices/import", inventoryHandler.importDevices).Methods("POST", "OPTIONS")
+	router.HandleFunc("/inventory/devices/{deviceId}", inventoryHandler.getDevice).Methods("GET", "OPTIONS")
+	router.HandleFunc("/inventory/devices/{deviceId}", inventoryHandler.updateDevice).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/inventory/devices/{deviceId}", inventoryHandler.deleteDevice).Methods("DELETE", "OPTIONS")
+}
+
+// newHandler creates a new inventory handler
+func newHandler(accountManager account.Manager) *handler {
+	return &handler{
+		accountManager: accountManager,
+	}
+}
+
+// getAllDevices returns the devices of the account inventory
+func (h *handler) getAllDevices(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	devices, err := h.accountManager.GetInventoryDevices(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := make([]*api.InventoryDevice, 0, len(devices))
+	for _, device := range devices {
+		resp = append(resp, toDeviceResponse(device))
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
+// getDevice returns a device of the account inventory
+func (h *handler) getDevice(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	device, err := h.accountManager.GetInventoryDevice(r.Context(), userAuth.AccountId, userAuth.UserId, mux.Vars(r)["deviceId"])
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toDeviceResponse(device))
+}
+
+// createDevice adds a device to the account inventory
+func (h *handler) createDevice(w http.ResponseWriter, r *http.Request) {
+	h.saveDevice(w, r, "")
+}
+
+// updateDevice updates a device of the account inventory
+func (h *handler) updateDevice(w http.ResponseWriter, r *http.Request) {
+	deviceID := mux.Vars(r)["deviceId"]
+	if len(deviceID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid device ID"), w)
+		return
+	}
+
+	h.saveDevice(w, r, deviceID)
+}
+
+func (h *handler) saveDevice(w http.ResponseWriter, r *http.Request, deviceID string) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiInventoryDevicesJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	device := fromDeviceRequest(req)
+	device.ID = deviceID
+
+	device, err = h.accountManager.SaveInventoryDevice(r.Context(), userAuth.AccountId, userAuth.UserId, device)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toDeviceResponse(device))
+}
+
+// deleteDevice removes a device from the account inventory
+func (h *handler) deleteDevice(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	if err = h.accountManager.DeleteInventoryDevice(r.Context(), userAuth.AccountId, userAuth.UserId, mux.Vars(r)["deviceId"]); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// importDevices adds devices to the account inventory from a JSON list or a CSV file
+func (h *handler) importDevices(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var devices []*types.InventoryDevice
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/csv" {
+		devices, err = types.ParseInventoryCSV(r.Body)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+	} else {
+		var req api.PostApiInventoryDevicesImportJSONRequestBody
+		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+			util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+			return
+		}
+
+		devices = make([]*types.InventoryDevice, 0, len(req.Devices))
+		for _, device := range req.Devices {
+			devices = append(devices, fromDeviceRequest(device))
+		}
+	}
+
+	summary, err := h.accountManager.ImportInventoryDevices(r.Context(), userAuth.AccountId, userAuth.UserId, devices)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, &api.InventoryImportResponse{
+		Created: summary.Created,
+		Updated: summary.Updated,
+		Failed:  summary.Failed(),
+		Results: types.ToBulkOperationResponse(summary.Results).Results,
+	})
+}
+
+func fromDeviceRequest(req api.InventoryDeviceRequest) *types.InventoryDevice {
+	device := &types.InventoryDevice{SerialNumber: req.SerialNumber}
+	if req.Manufacturer != nil {
+		device.Manufacturer = *req.Manufacturer
+	}
+	if req.ProductName != nil {
+		device.ProductName = *req.ProductName
+	}
+	if req.UserId != nil {
+		device.UserID = *req.UserId
+	}
+	if req.Description != nil {
+		device.Description = *req.Description
+	}
+	return device
+}
+
+func toDeviceResponse(device *types.InventoryDevice) *api.InventoryDevice {
+	return &api.InventoryDevice{
+		Id:           device.ID,
+		SerialNumber: device.SerialNumber,
+		Manufacturer: emptyToNil(device.Manufacturer),
+		ProductName:  emptyToNil(device.ProductName),
+		UserId:       emptyToNil(device.UserID),
+		Description:  emptyToNil(device.Description),
+		CreatedAt:    device.CreatedAt,
+	}
+}
+
+func emptyToNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
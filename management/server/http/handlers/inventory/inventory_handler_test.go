@@ -0,0 +1,178 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+const (
+	testAccountID = "test_id"
+	testUserID    = "admin_user"
+)
+
+func TestInventoryEndpoints(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	devices := []*types.InventoryDevice{
+		{ID: "device1", AccountID: testAccountID, SerialNumber: "SN-1", Manufacturer: "Lenovo", UserID: testUserID, CreatedAt: createdAt},
+		{ID: "device2", AccountID: testAccountID, SerialNumber: "SN-2", CreatedAt: createdAt},
+	}
+
+	var savedDevice *types.InventoryDevice
+	var importedDevices []*types.InventoryDevice
+
+	router := mux.NewRouter()
+	AddEndpoints(&mock_server.MockAccountManager{
+		GetInventoryDevicesFunc: func(_ context.Context, _, _ string) ([]*types.InventoryDevice, error) {
+			return devices, nil
+		},
+		GetInventoryDeviceFunc: func(_ context.Context, _, _, deviceID string) (*types.InventoryDevice, error) {
+			for _, device := range devices {
+				if device.ID == deviceID {
+					return device, nil
+				}
+			}
+			return nil, status.NewInventoryDeviceNotFoundError(deviceID)
+		},
+		SaveInventoryDeviceFunc: func(_ context.Context, accountID, _ string, device *types.InventoryDevice) (*types.InventoryDevice, error) {
+			if device.ID == "" {
+				device.ID = "new_device"
+			}
+			device.AccountID = accountID
+			device.CreatedAt = createdAt
+			savedDevice = device
+			return device, nil
+		},
+		DeleteInventoryDeviceFunc: func(_ context.Context, _, _, deviceID string) error {
+			if deviceID != "device1" {
+				return status.NewInventoryDeviceNotFoundError(deviceID)
+			}
+			return nil
+		},
+		ImportInventoryDevicesFunc: func(_ context.Context, _, _ string, devices []*types.InventoryDevice) (*types.InventoryImportSummary, error) {
+			importedDevices = devices
+			summary := &types.InventoryImportSummary{}
+			for _, device := range devices {
+				result := &types.BulkItemResult{ID: device.SerialNumber}
+				if device.SerialNumber == "" {
+					result.Err = errors.New("device serial number can't be empty")
+				} else {
+					summary.Created++
+				}
+				summary.Results = append(summary.Results, result)
+			}
+			return summary, nil
+		},
+	}, router)
+
+	do := func(t *testing.T, method, path, contentType, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+			UserId:    testUserID,
+			AccountId: testAccountID,
+		})
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("list devices", func(t *testing.T) {
+		recorder := do(t, http.MethodGet, "/inventory/devices", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var got []api.InventoryDevice
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+		require.Len(t, got, 2)
+		assert.Equal(t, "SN-1", got[0].SerialNumber)
+		assert.Equal(t, "Lenovo", *got[0].Manufacturer)
+		assert.Equal(t, testUserID, *got[0].UserId)
+		assert.Nil(t, got[1].UserId)
+		assert.Equal(t, createdAt, got[1].CreatedAt)
+	})
+
+	t.Run("get unknown device", func(t *testing.T) {
+		recorder := do(t, http.MethodGet, "/inventory/devices/unknown", "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("create device", func(t *testing.T) {
+		recorder := do(t, http.MethodPost, "/inventory/devices", "application/json", `{"serial_number":"SN-3","product_name":"ThinkPad","user_id":"user"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, &types.InventoryDevice{
+			ID:           "new_device",
+			AccountID:    testAccountID,
+			SerialNumber: "SN-3",
+			ProductName:  "ThinkPad",
+			UserID:       "user",
+			CreatedAt:    createdAt,
+		}, savedDevice)
+
+		var got api.InventoryDevice
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+		assert.Equal(t, "new_device", got.Id)
+		assert.Nil(t, got.Manufacturer)
+	})
+
+	t.Run("update device", func(t *testing.T) {
+		recorder := do(t, http.MethodPut, "/inventory/devices/device2", "application/json", `{"serial_number":"SN-2","description":"spare"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "device2", savedDevice.ID)
+		assert.Equal(t, "spare", savedDevice.Description)
+	})
+
+	t.Run("delete device", func(t *testing.T) {
+		recorder := do(t, http.MethodDelete, "/inventory/devices/device1", "", "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder = do(t, http.MethodDelete, "/inventory/devices/device2", "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("import JSON", func(t *testing.T) {
+		recorder := do(t, http.MethodPost, "/inventory/devices/import", "application/json", `{"devices":[{"serial_number":"SN-4"},{"serial_number":""}]}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Len(t, importedDevices, 2)
+
+		var got api.InventoryImportResponse
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+		assert.Equal(t, 1, got.Created)
+		assert.Equal(t, 1, got.Failed)
+		require.Len(t, got.Results, 2)
+		assert.Equal(t, api.BulkOperationItemResultStatusSuccess, got.Results[0].Status)
+		assert.Equal(t, api.BulkOperationItemResultStatusFailed, got.Results[1].Status)
+	})
+
+	t.Run("import CSV", func(t *testing.T) {
+		csv := "serial_number,manufacturer,user_id\nsn-5,Dell,\nSN-6,,user\n"
+		recorder := do(t, http.MethodPost, "/inventory/devices/import", "text/csv; charset=utf-8", csv)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Len(t, importedDevices, 2)
+		assert.Equal(t, "SN-5", importedDevices[0].SerialNumber)
+		assert.Equal(t, "Dell", importedDevices[0].Manufacturer)
+		assert.Equal(t, "user", importedDevices[1].UserID)
+	})
+
+	t.Run("import CSV without serial number column", func(t *testing.T) {
+		recorder := do(t, http.MethodPost, "/inventory/devices/import", "text/csv", "manufacturer\nDell\n")
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+}
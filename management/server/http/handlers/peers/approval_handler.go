@@ -0,0 +1,286 @@
+package peers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func addApprovalEndpoints(peersHandler *Handler, router *mux.Router) {
+	router.HandleFunc("/peers/approval-requests", peersHandler.getApprovalRequests).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/approval-rules", peersHandler.getApprovalRules).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/approval-rules", peersHandler.createApprovalRule).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peers/approval-rules/{ruleId}", peersHandler.getApprovalRule).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/approval-rules/{ruleId}", peersHandler.updateApprovalRule).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/peers/approval-rules/{ruleId}", peersHandler.deleteApprovalRule).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/approve", peersHandler.approvePeer).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/reject", peersHandler.rejectPeer).Methods("POST", "OPTIONS")
+}
+
+// getApprovalRequests returns the approval requests of the account peers, optionally filtered by status
+func (h *Handler) getApprovalRequests(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	filterStatus := types.PeerApprovalStatus(r.URL.Query().Get("status"))
+	switch filterStatus {
+	case "", types.PeerApprovalStatusPending, types.PeerApprovalStatusApproved, types.PeerApprovalStatusRejected:
+	default:
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid approval request status %q", filterStatus), w)
+		return
+	}
+
+	requests, err := h.accountManager.GetPeerApprovalRequests(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := make([]*api.PeerApprovalRequest, 0, len(requests))
+	for _, request := range requests {
+		if filterStatus != "" && request.Status != filterStatus {
+			continue
+		}
+		resp = append(resp, toApprovalRequestResponse(request))
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
+// approvePeer approves a peer waiting for approval
+func (h *Handler) approvePeer(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	reason, ok := parseApprovalReason(w, r)
+	if !ok {
+		return
+	}
+
+	peer, err := h.accountManager.ApprovePeer(r.Context(), userAuth.AccountId, userAuth.UserId, peerID, reason)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	h.getPeer(r.Context(), userAuth.AccountId, peer.ID, userAuth.UserId, w)
+}
+
+// rejectPeer rejects and deletes a peer waiting for approval
+func (h *Handler) rejectPeer(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	reason, ok := parseApprovalReason(w, r)
+	if !ok {
+		return
+	}
+
+	if err = h.accountManager.RejectPeer(r.Context(), userAuth.AccountId, userAuth.UserId, peerID, reason); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// getApprovalRules returns the auto-approve rules of the account
+func (h *Handler) getApprovalRules(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	rules, err := h.accountManager.GetPeerApprovalRules(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := make([]*api.PeerApprovalRule, 0, len(rules))
+	for _, rule := range rules {
+		resp = append(resp, toApprovalRuleResponse(rule))
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
+// getApprovalRule returns an auto-approve rule of the account
+func (h *Handler) getApprovalRule(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	rule, err := h.accountManager.GetPeerApprovalRule(r.Context(), userAuth.AccountId, userAuth.UserId, mux.Vars(r)["ruleId"])
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toApprovalRuleResponse(rule))
+}
+
+// createApprovalRule creates an auto-approve rule
+func (h *Handler) createApprovalRule(w http.ResponseWriter, r *http.Request) {
+	h.saveApprovalRule(w, r, "")
+}
+
+// updateApprovalRule updates an auto-approve rule
+func (h *Handler) updateApprovalRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["ruleId"]
+	if len(ruleID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid approval rule ID"), w)
+		return
+	}
+
+	h.saveApprovalRule(w, r, ruleID)
+}
+
+func (h *Handler) saveApprovalRule(w http.ResponseWriter, r *http.Request, ruleID string) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiPeersApprovalRulesJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	rule := &types.PeerApprovalRule{
+		ID:      ruleID,
+		Name:    req.Name,
+		Enabled: req.Enabled,
+	}
+	if req.Description != nil {
+		rule.Description = *req.Description
+	}
+	if req.SetupKeyIds != nil {
+		rule.SetupKeyIDs = *req.SetupKeyIds
+	}
+	if req.SerialNumbers != nil {
+		rule.SerialNumbers = *req.SerialNumbers
+	}
+
+	rule, err = h.accountManager.SavePeerApprovalRule(r.Context(), userAuth.AccountId, userAuth.UserId, rule)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toApprovalRuleResponse(rule))
+}
+
+// deleteApprovalRule deletes an auto-approve rule
+func (h *Handler) deleteApprovalRule(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	if err = h.accountManager.DeletePeerApprovalRule(r.Context(), userAuth.AccountId, userAuth.UserId, mux.Vars(r)["ruleId"]); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// parseApprovalReason reads the optional approval decision body, an empty body is accepted
+func parseApprovalReason(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.ContentLength == 0 {
+		return "", true
+	}
+
+	var req api.PostApiPeersPeerIdApproveJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return "", false
+	}
+
+	if req.Reason == nil {
+		return "", true
+	}
+	return *req.Reason, true
+}
+
+func toApprovalRequestResponse(request *types.PeerApprovalRequest) *api.PeerApprovalRequest {
+	osVersion := request.Meta.OSVersion
+	if osVersion == "" {
+		osVersion = request.Meta.Core
+	}
+
+	return &api.PeerApprovalRequest{
+		PeerId:         request.PeerID,
+		PeerName:       request.PeerName,
+		UserId:         emptyToNil(request.UserID),
+		SetupKeyId:     emptyToNil(request.SetupKeyID),
+		SetupKeyName:   emptyToNil(request.SetupKeyName),
+		Hostname:       request.Meta.Hostname,
+		Os:             fmt.Sprintf("%s %s", request.Meta.OS, osVersion),
+		KernelVersion:  request.Meta.KernelVersion,
+		Version:        request.Meta.WtVersion,
+		SerialNumber:   request.Meta.SystemSerialNumber,
+		ConnectionIp:   request.Location.ConnectionIP.String(),
+		CountryCode:    request.Location.CountryCode,
+		CityName:       request.Location.CityName,
+		CreatedAt:      request.CreatedAt,
+		Status:         api.PeerApprovalRequestStatus(request.Status),
+		Reason:         emptyToNil(request.Reason),
+		ReviewedBy:     emptyToNil(request.ReviewedBy),
+		ReviewedAt:     request.ReviewedAt,
+		ApprovalRuleId: emptyToNil(request.ApprovalRuleID),
+	}
+}
+
+func toApprovalRuleResponse(rule *types.PeerApprovalRule) *api.PeerApprovalRule {
+	setupKeyIDs := rule.SetupKeyIDs
+	if setupKeyIDs == nil {
+		setupKeyIDs = []string{}
+	}
+	serialNumbers := rule.SerialNumbers
+	if serialNumbers == nil {
+		serialNumbers = []string{}
+	}
+
+	return &api.PeerApprovalRule{
+		Id:            rule.ID,
+		Name:          rule.Name,
+		Description:   &rule.Description,
+		Enabled:       rule.Enabled,
+		SetupKeyIds:   &setupKeyIDs,
+		SerialNumbers: &serialNumbers,
+	}
+}
+
+func emptyToNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,140 @@
+package peers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func TestPeerApprovalEndpoints(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	requests := []*types.PeerApprovalRequest{
+		{
+			PeerID:       "pending_peer",
+			AccountID:    "test_id",
+			PeerName:     "laptop",
+			SetupKeyID:   "key",
+			SetupKeyName: "laptops",
+			Meta:         nbpeer.PeerSystemMeta{Hostname: "laptop", OS: "linux", SystemSerialNumber: "SN-1"},
+			CreatedAt:    createdAt,
+			Status:       types.PeerApprovalStatusPending,
+		},
+		{
+			PeerID:     "approved_peer",
+			AccountID:  "test_id",
+			PeerName:   "server",
+			CreatedAt:  createdAt,
+			Status:     types.PeerApprovalStatusApproved,
+			Reason:     "known device",
+			ReviewedBy: adminUser,
+		},
+	}
+
+	var rejectedReason string
+	var savedRule *types.PeerApprovalRule
+
+	router := mux.NewRouter()
+	AddEndpoints(&mock_server.MockAccountManager{
+		GetPeerApprovalRequestsFunc: func(_ context.Context, _, _ string) ([]*types.PeerApprovalRequest, error) {
+			return requests, nil
+		},
+		RejectPeerFunc: func(_ context.Context, _, _, peerID, reason string) error {
+			if peerID != "pending_peer" {
+				return status.Errorf(status.PreconditionFailed, "peer %s doesn't require approval", peerID)
+			}
+			rejectedReason = reason
+			return nil
+		},
+		SavePeerApprovalRuleFunc: func(_ context.Context, accountID, _ string, rule *types.PeerApprovalRule) (*types.PeerApprovalRule, error) {
+			if rule.ID == "" {
+				rule.ID = "new_rule"
+			}
+			rule.AccountID = accountID
+			savedRule = rule
+			return rule, nil
+		},
+	}, router)
+
+	do := func(t *testing.T, method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+			UserId:    adminUser,
+			Domain:    "hotmail.com",
+			AccountId: "test_id",
+		})
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("list pending requests", func(t *testing.T) {
+		recorder := do(t, http.MethodGet, "/peers/approval-requests?status=pending", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var got []api.PeerApprovalRequest
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+		require.Len(t, got, 1)
+		assert.Equal(t, "pending_peer", got[0].PeerId)
+		assert.Equal(t, api.PeerApprovalRequestStatusPending, got[0].Status)
+		assert.Equal(t, "SN-1", got[0].SerialNumber)
+		assert.Equal(t, "laptops", *got[0].SetupKeyName)
+		assert.Nil(t, got[0].UserId)
+		assert.Equal(t, createdAt, got[0].CreatedAt)
+	})
+
+	t.Run("invalid status filter", func(t *testing.T) {
+		recorder := do(t, http.MethodGet, "/peers/approval-requests?status=unknown", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+
+	t.Run("reject with reason", func(t *testing.T) {
+		recorder := do(t, http.MethodPost, "/peers/pending_peer/reject", `{"reason":"unknown device"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "unknown device", rejectedReason)
+
+		recorder = do(t, http.MethodPost, "/peers/approved_peer/reject", "")
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+	})
+
+	t.Run("create rule", func(t *testing.T) {
+		recorder := do(t, http.MethodPost, "/peers/approval-rules", `{"name":"servers","enabled":true,"setup_key_ids":["key"]}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, &types.PeerApprovalRule{
+			ID:          "new_rule",
+			AccountID:   "test_id",
+			Name:        "servers",
+			Enabled:     true,
+			SetupKeyIDs: []string{"key"},
+		}, savedRule)
+
+		var got api.PeerApprovalRule
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+		assert.Equal(t, "new_rule", got.Id)
+		assert.Equal(t, []string{"key"}, *got.SetupKeyIds)
+		assert.Equal(t, []string{}, *got.SerialNumbers)
+	})
+
+	t.Run("update rule", func(t *testing.T) {
+		recorder := do(t, http.MethodPut, "/peers/approval-rules/rule1", `{"name":"inventory","enabled":false,"serial_numbers":["SN-1"]}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "rule1", savedRule.ID)
+		assert.Equal(t, []string{"SN-1"}, savedRule.SerialNumbers)
+		assert.False(t, savedRule.Enabled)
+	})
+}
@@ -27,6 +27,8 @@ type Handler struct {
 func AddEndpoints(accountManager account.Manager, router *mux.Router) {
 	peersHandler := NewHandler(accountManager)
 	router.HandleFunc("/peers", peersHandler.GetAllPeers).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/bulk", peersHandler.BulkUpdatePeers).Methods("POST", "OPTIONS")
+	addApprovalEndpoints(peersHandler, router)
 	router.HandleFunc("/peers/{peerId}", peersHandler.HandlePeer).
 		Methods("GET", "PUT", "DELETE", "OPTIONS")
 	router.HandleFunc("/peers/{peerId}/accessible-peers", peersHandler.GetAccessiblePeers).Methods("GET", "OPTIONS")
@@ -76,8 +78,17 @@ func (h *Handler) getPeer(ctx context.Context, accountID, peerID, userID string,
 		return
 	}
 
+	inventoriedSerials, err := h.accountManager.GetInventoriedSerialNumbers(ctx, accountID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to list inventoried devices: %v", err)
+		util.WriteError(ctx, fmt.Errorf("internal error"), w)
+		return
+	}
+
 	_, valid := validPeers[peer.ID]
-	util.WriteJSONObject(ctx, w, toSinglePeerResponse(peerToReturn, grpsInfoMap[peerID], dnsDomain, valid))
+	resp := toSinglePeerResponse(peerToReturn, grpsInfoMap[peerID], dnsDomain, valid)
+	resp.UnknownDevice = isUnknownDevice(resp.SerialNumber, inventoriedSerials)
+	util.WriteJSONObject(ctx, w, resp)
 }
 
 func (h *Handler) updatePeer(ctx context.Context, accountID, userID, peerID string, w http.ResponseWriter, r *http.Request) {
@@ -126,9 +137,18 @@ func (h *Handler) updatePeer(ctx context.Context, accountID, userID, peerID stri
 		return
 	}
 
+	inventoriedSerials, err := h.accountManager.GetInventoriedSerialNumbers(ctx, accountID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to list inventoried devices: %v", err)
+		util.WriteError(ctx, fmt.Errorf("internal error"), w)
+		return
+	}
+
 	_, valid := validPeers[peer.ID]
 
-	util.WriteJSONObject(r.Context(), w, toSinglePeerResponse(peer, grpsInfoMap[peerID], dnsDomain, valid))
+	resp := toSinglePeerResponse(peer, grpsInfoMap[peerID], dnsDomain, valid)
+	resp.UnknownDevice = isUnknownDevice(resp.SerialNumber, inventoriedSerials)
+	util.WriteJSONObject(r.Context(), w, resp)
 }
 
 func (h *Handler) deletePeer(ctx context.Context, accountID, userID string, peerID string, w http.ResponseWriter) {
@@ -172,6 +192,46 @@ func (h *Handler) HandlePeer(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// BulkUpdatePeers applies a single action to a list of peers and returns the outcome per peer
+func (h *Handler) BulkUpdatePeers(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	req := &api.PostApiPeersBulkJSONRequestBody{}
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	op := &types.PeerBulkOperation{
+		Action:  types.PeerBulkAction(req.Action),
+		PeerIDs: req.PeerIds,
+	}
+	if req.GroupIds != nil {
+		op.GroupIDs = *req.GroupIds
+	}
+	if req.Reason != nil {
+		op.Reason = *req.Reason
+	}
+	if req.Enabled != nil {
+		op.Enabled = *req.Enabled
+	} else if op.Action == types.PeerBulkActionSetLoginExpiration || op.Action == types.PeerBulkActionSetSSH {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "action %s requires the enabled field", op.Action), w)
+		return
+	}
+
+	results, err := h.accountManager.BulkUpdatePeers(r.Context(), userAuth.AccountId, userAuth.UserId, op)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, types.ToBulkOperationResponse(results))
+}
+
 // GetAllPeers returns a list of all peers associated with a provided account
 func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
@@ -180,12 +240,21 @@ func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nameFilter := r.URL.Query().Get("name")
-	ipFilter := r.URL.Query().Get("ip")
+	filter, err := toPeerFilter(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	page, err := util.ParsePageRequest(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
 
 	accountID, userID := userAuth.AccountId, userAuth.UserId
 
-	peers, err := h.accountManager.GetPeers(r.Context(), accountID, userID, nameFilter, ipFilter)
+	peers, nextCursor, err := h.accountManager.GetPeersPage(r.Context(), accountID, userID, filter, page)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -215,9 +284,52 @@ func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 	}
 	h.setApprovalRequiredFlag(respBody, validPeersMap)
 
+	inventoriedSerials, err := h.accountManager.GetInventoriedSerialNumbers(r.Context(), accountID)
+	if err != nil {
+		log.WithContext(r.Context()).Errorf("failed to list inventoried devices: %v", err)
+		util.WriteError(r.Context(), fmt.Errorf("internal error"), w)
+		return
+	}
+	for _, peer := range respBody {
+		peer.UnknownDevice = isUnknownDevice(peer.SerialNumber, inventoriedSerials)
+	}
+
+	util.WriteNextCursor(w, nextCursor)
 	util.WriteJSONObject(r.Context(), w, respBody)
 }
 
+// toPeerFilter parses the peer list filters from the query parameters
+func toPeerFilter(r *http.Request) (types.PeerFilter, error) {
+	query := r.URL.Query()
+	filter := types.PeerFilter{
+		Name:     query.Get("name"),
+		IP:       query.Get("ip"),
+		DNSLabel: query.Get("dns_label"),
+		GroupID:  query.Get("group_id"),
+		OS:       query.Get("os"),
+		Version:  query.Get("version"),
+	}
+
+	var err error
+	if filter.Connected, err = util.ParseBoolQuery(r, "connected"); err != nil {
+		return filter, err
+	}
+	if filter.LoginExpired, err = util.ParseBoolQuery(r, "login_expired"); err != nil {
+		return filter, err
+	}
+	if filter.ApprovalRequired, err = util.ParseBoolQuery(r, "approval_required"); err != nil {
+		return filter, err
+	}
+	if filter.LastSeenAfter, err = util.ParseTimeQuery(r, "last_seen_after"); err != nil {
+		return filter, err
+	}
+	if filter.LastSeenBefore, err = util.ParseTimeQuery(r, "last_seen_before"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
 func (h *Handler) setApprovalRequiredFlag(respBody []*api.PeerBatch, approvedPeersMap map[string]struct{}) {
 	for _, peer := range respBody {
 		_, ok := approvedPeersMap[peer.Id]
@@ -227,6 +339,15 @@ func (h *Handler) setApprovalRequiredFlag(respBody []*api.PeerBatch, approvedPee
 	}
 }
 
+// isUnknownDevice returns true when the account keeps a device inventory and the serial number isn't part of it
+func isUnknownDevice(serialNumber string, inventoriedSerials map[string]struct{}) bool {
+	if len(inventoriedSerials) == 0 {
+		return false
+	}
+	_, ok := inventoriedSerials[types.NormalizeSerialNumber(serialNumber)]
+	return !ok
+}
+
 // GetAccessiblePeers returns a list of all peers that the specified peer can connect to within the network.
 func (h *Handler) GetAccessiblePeers(w http.ResponseWriter, r *http.Request) {
 	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
@@ -325,6 +446,7 @@ func toSinglePeerResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dnsD
 		Id:                          peer.ID,
 		Name:                        peer.Name,
 		Ip:                          peer.IP.String(),
+		Ipv6:                        peerIPv6(peer),
 		ConnectionIp:                peer.Location.ConnectionIP.String(),
 		Connected:                   peer.Status.Connected,
 		LastSeen:                    peer.Status.LastSeen,
@@ -347,6 +469,7 @@ func toSinglePeerResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dnsD
 		CityName:                    peer.Location.CityName,
 		SerialNumber:                peer.Meta.SystemSerialNumber,
 		InactivityExpirationEnabled: peer.InactivityExpirationEnabled,
+		PostQuantum:                 toPostQuantumStatus(peer),
 	}
 }
 
@@ -360,6 +483,7 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 		Id:                     peer.ID,
 		Name:                   peer.Name,
 		Ip:                     peer.IP.String(),
+		Ipv6:                   peerIPv6(peer),
 		ConnectionIp:           peer.Location.ConnectionIP.String(),
 		Connected:              peer.Status.Connected,
 		LastSeen:               peer.Status.LastSeen,
@@ -381,11 +505,30 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 		CountryCode:            peer.Location.CountryCode,
 		CityName:               peer.Location.CityName,
 		SerialNumber:           peer.Meta.SystemSerialNumber,
+		PostQuantum:            toPostQuantumStatus(peer),
 
 		InactivityExpirationEnabled: peer.InactivityExpirationEnabled,
 	}
 }
 
+// toPostQuantumStatus returns the Rosenpass status reported by the peer's client
+func toPostQuantumStatus(peer *nbpeer.Peer) api.PeerPostQuantumStatus {
+	return api.PeerPostQuantumStatus{
+		Supported:  peer.Meta.HasCapability(nbpeer.CapabilityRosenpass),
+		Enabled:    peer.Meta.Flags.RosenpassEnabled,
+		Permissive: peer.Meta.Flags.RosenpassPermissive,
+	}
+}
+
+// peerIPv6 returns the IPv6 overlay address of the peer if its client is able to use it
+func peerIPv6(peer *nbpeer.Peer) *string {
+	if !peer.SupportsIPv6() {
+		return nil
+	}
+	ip := peer.IPv6.String()
+	return &ip
+}
+
 func fqdn(peer *nbpeer.Peer, dnsDomain string) string {
 	fqdn := peer.FQDN(dnsDomain)
 	if fqdn == "" {
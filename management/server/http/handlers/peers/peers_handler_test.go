@@ -17,10 +17,13 @@ import (
 
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/types"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/management/server/mock_server"
 )
@@ -137,6 +140,9 @@ func initTestMetaData(peers ...*nbpeer.Peer) *Handler {
 			GetPeersFunc: func(_ context.Context, accountID, userID, nameFilter, ipFilter string) ([]*nbpeer.Peer, error) {
 				return peers, nil
 			},
+			GetPeersPageFunc: func(_ context.Context, accountID, userID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error) {
+				return peers, "", nil
+			},
 			GetPeerGroupsFunc: func(ctx context.Context, accountID, peerID string) ([]*types.Group, error) {
 				peersID := make([]string, len(peers))
 				for _, peer := range peers {
@@ -329,6 +335,77 @@ func TestGetPeers(t *testing.T) {
 	}
 }
 
+func TestGetPeerUnknownDevice(t *testing.T) {
+	peer := &nbpeer.Peer{
+		ID:     testPeerID,
+		Key:    "key",
+		IP:     net.ParseIP("100.64.0.1"),
+		Status: &nbpeer.PeerStatus{Connected: true},
+		Name:   "PeerName",
+		Meta: nbpeer.PeerSystemMeta{
+			Hostname:           "hostname",
+			OS:                 "OS",
+			SystemSerialNumber: "c02xj0j0jgh7",
+		},
+	}
+
+	tt := []struct {
+		name            string
+		inventory       map[string]struct{}
+		expectedUnknown bool
+	}{
+		{
+			name:            "account without inventory",
+			inventory:       map[string]struct{}{},
+			expectedUnknown: false,
+		},
+		{
+			name:            "device in inventory",
+			inventory:       map[string]struct{}{"C02XJ0J0JGH7": {}},
+			expectedUnknown: false,
+		},
+		{
+			name:            "device not in inventory",
+			inventory:       map[string]struct{}{"OTHER": {}},
+			expectedUnknown: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p := initTestMetaData(peer)
+			p.accountManager.(*mock_server.MockAccountManager).GetInventoriedSerialNumbersFunc = func(_ context.Context, _ string) (map[string]struct{}, error) {
+				return tc.inventory, nil
+			}
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/peers", p.GetAllPeers).Methods("GET")
+			router.HandleFunc("/api/peers/{peerId}", p.HandlePeer).Methods("GET")
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/peers/"+testPeerID, nil)
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{UserId: adminUser, AccountId: "test_id"})
+			router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			got := &api.Peer{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), got))
+			assert.Equal(t, tc.expectedUnknown, got.UnknownDevice)
+
+			recorder = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "/api/peers", nil)
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{UserId: adminUser, AccountId: "test_id"})
+			router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var list []*api.PeerBatch
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &list))
+			require.Len(t, list, 1)
+			assert.Equal(t, tc.expectedUnknown, list[0].UnknownDevice)
+		})
+	}
+}
+
 func TestGetAccessiblePeers(t *testing.T) {
 	peer1 := &nbpeer.Peer{
 		ID:                     "peer1",
@@ -447,3 +524,221 @@ func TestGetAccessiblePeers(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPeersPage(t *testing.T) {
+	peer := &nbpeer.Peer{
+		ID:     "peer1",
+		Name:   "web-01",
+		IP:     net.ParseIP("100.64.0.1"),
+		Status: &nbpeer.PeerStatus{Connected: true, LastSeen: time.Now()},
+		Meta:   nbpeer.PeerSystemMeta{GoOS: "linux"},
+	}
+
+	tt := []struct {
+		name           string
+		requestPath    string
+		expectedStatus int
+		expectedFilter types.PeerFilter
+		expectedPage   types.PageRequest
+	}{
+		{
+			name:           "filters and page",
+			requestPath:    "/api/peers?name=web&dns_label=web-01&group_id=group1&connected=true&os=linux&version=0.36&login_expired=false&approval_required=false&last_seen_after=2025-01-01T00:00:00Z&limit=10&cursor=abc&sort_by=last_seen&sort_order=desc",
+			expectedStatus: http.StatusOK,
+			expectedFilter: types.PeerFilter{
+				Name:             "web",
+				DNSLabel:         "web-01",
+				GroupID:          "group1",
+				OS:               "linux",
+				Version:          "0.36",
+				Connected:        ptr(true),
+				LoginExpired:     ptr(false),
+				ApprovalRequired: ptr(false),
+				LastSeenAfter:    ptr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			expectedPage: types.PageRequest{Limit: 10, Cursor: "abc", SortBy: "last_seen", SortDesc: true},
+		},
+		{
+			name:           "invalid bool",
+			requestPath:    "/api/peers?connected=maybe",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid time",
+			requestPath:    "/api/peers?last_seen_before=yesterday",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid limit",
+			requestPath:    "/api/peers?limit=5000",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid sort order",
+			requestPath:    "/api/peers?sort_order=random",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotFilter types.PeerFilter
+			var gotPage types.PageRequest
+			p := &Handler{
+				accountManager: &mock_server.MockAccountManager{
+					GetPeersPageFunc: func(_ context.Context, _, _ string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error) {
+						gotFilter, gotPage = filter, page
+						return []*nbpeer.Peer{peer}, "next", nil
+					},
+					GetAllGroupsFunc: func(_ context.Context, _, _ string) ([]*types.Group, error) {
+						return nil, nil
+					},
+					GetAccountFunc: func(_ context.Context, accountID string) (*types.Account, error) {
+						return &types.Account{Id: accountID, Peers: map[string]*nbpeer.Peer{peer.ID: peer}}, nil
+					},
+					HasConnectedChannelFunc: func(string) bool { return true },
+					GetDNSDomainFunc:        func() string { return "netbird.selfhosted" },
+				},
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.requestPath, nil)
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+				UserId:    "admin_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			p.GetAllPeers(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tc.expectedFilter, gotFilter)
+			assert.Equal(t, tc.expectedPage, gotPage)
+			assert.Equal(t, "next", res.Header.Get(util.NextCursorHeader))
+
+			var got []api.PeerBatch
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			require.Len(t, got, 1)
+			assert.Equal(t, peer.ID, got[0].Id)
+		})
+	}
+}
+
+func TestBulkUpdatePeers(t *testing.T) {
+	tt := []struct {
+		name            string
+		requestBody     string
+		expectedStatus  int
+		expectedOp      *types.PeerBulkOperation
+		expectedResults []api.BulkOperationItemResult
+	}{
+		{
+			name:           "assign groups",
+			requestBody:    `{"action":"assign_groups","peer_ids":["peer1","peer2"],"group_ids":["group1"]}`,
+			expectedStatus: http.StatusOK,
+			expectedOp: &types.PeerBulkOperation{
+				Action:   types.PeerBulkActionAssignGroups,
+				PeerIDs:  []string{"peer1", "peer2"},
+				GroupIDs: []string{"group1"},
+			},
+			expectedResults: []api.BulkOperationItemResult{
+				{Id: "peer1", Status: api.BulkOperationItemResultStatusSuccess},
+				{Id: "peer2", Status: api.BulkOperationItemResultStatusFailed, Error: ptr("peer not found: peer2")},
+			},
+		},
+		{
+			name:           "set ssh",
+			requestBody:    `{"action":"set_ssh","peer_ids":["peer1"],"enabled":true}`,
+			expectedStatus: http.StatusOK,
+			expectedOp: &types.PeerBulkOperation{
+				Action:  types.PeerBulkActionSetSSH,
+				PeerIDs: []string{"peer1"},
+				Enabled: true,
+			},
+			expectedResults: []api.BulkOperationItemResult{
+				{Id: "peer1", Status: api.BulkOperationItemResultStatusSuccess},
+			},
+		},
+		{
+			name:           "approve with reason",
+			requestBody:    `{"action":"approve","peer_ids":["peer1"],"reason":"known devices"}`,
+			expectedStatus: http.StatusOK,
+			expectedOp: &types.PeerBulkOperation{
+				Action:  types.PeerBulkActionApprove,
+				PeerIDs: []string{"peer1"},
+				Reason:  "known devices",
+			},
+			expectedResults: []api.BulkOperationItemResult{
+				{Id: "peer1", Status: api.BulkOperationItemResultStatusSuccess},
+			},
+		},
+		{
+			name:           "set ssh without enabled",
+			requestBody:    `{"action":"set_ssh","peer_ids":["peer1"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid json",
+			requestBody:    `{"action":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotOp *types.PeerBulkOperation
+			p := &Handler{
+				accountManager: &mock_server.MockAccountManager{
+					BulkUpdatePeersFunc: func(_ context.Context, _, _ string, op *types.PeerBulkOperation) ([]*types.BulkItemResult, error) {
+						gotOp = op
+						var results []*types.BulkItemResult
+						for _, peerID := range op.PeerIDs {
+							result := &types.BulkItemResult{ID: peerID}
+							if peerID != "peer1" {
+								result.Err = status.NewPeerNotFoundError(peerID)
+							}
+							results = append(results, result)
+						}
+						return results, nil
+					},
+				},
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/peers/bulk", bytes.NewBufferString(tc.requestBody))
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+				UserId:    "admin_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			p.BulkUpdatePeers(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus != http.StatusOK {
+				assert.Nil(t, gotOp)
+				return
+			}
+
+			assert.Equal(t, tc.expectedOp, gotOp)
+
+			var got api.BulkOperationResponse
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			assert.Equal(t, tc.expectedResults, got.Results)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -3,6 +3,7 @@ package policies
 import (
 	"encoding/json"
 	"net/http"
+	"net/netip"
 	"strconv"
 
 	"github.com/gorilla/mux"
@@ -25,6 +26,7 @@ func AddEndpoints(accountManager account.Manager, locationManager geolocation.Ge
 	policiesHandler := newHandler(accountManager)
 	router.HandleFunc("/policies", policiesHandler.getAllPolicies).Methods("GET", "OPTIONS")
 	router.HandleFunc("/policies", policiesHandler.createPolicy).Methods("POST", "OPTIONS")
+	router.HandleFunc("/policies/simulate", policiesHandler.simulatePolicy).Methods("POST", "OPTIONS")
 	router.HandleFunc("/policies/{policyId}", policiesHandler.updatePolicy).Methods("PUT", "OPTIONS")
 	router.HandleFunc("/policies/{policyId}", policiesHandler.getPolicy).Methods("GET", "OPTIONS")
 	router.HandleFunc("/policies/{policyId}", policiesHandler.deletePolicy).Methods("DELETE", "OPTIONS")
@@ -363,6 +365,90 @@ func (h *handler) getPolicy(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSONObject(r.Context(), w, resp)
 }
 
+// simulatePolicy evaluates whether the requested traffic is allowed by the account policies
+func (h *handler) simulatePolicy(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	accountID, userID := userAuth.AccountId, userAuth.UserId
+
+	var req api.PostApiPoliciesSimulateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	simulation, err := toPolicySimulationRequest(req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	result, err := h.accountManager.SimulatePolicy(r.Context(), accountID, userID, simulation)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toPolicySimulationResponse(result))
+}
+
+func toPolicySimulationRequest(req api.PolicySimulationRequest) (types.PolicySimulationRequest, error) {
+	simulation := types.PolicySimulationRequest{
+		SourcePeerID:          req.SourcePeerId,
+		DestinationPeerID:     stringValue(req.DestinationPeerId),
+		DestinationDomain:     stringValue(req.DestinationDomain),
+		DestinationResourceID: stringValue(req.DestinationResourceId),
+		Protocol:              types.PolicyRuleProtocolType(req.Protocol),
+	}
+
+	if req.DestinationIp != nil {
+		ip, err := netip.ParseAddr(*req.DestinationIp)
+		if err != nil {
+			return simulation, status.Errorf(status.InvalidArgument, "invalid destination IP %q", *req.DestinationIp)
+		}
+		simulation.DestinationIP = ip.Unmap()
+	}
+
+	if req.Port != nil {
+		if *req.Port < 1 || *req.Port > 65535 {
+			return simulation, status.Errorf(status.InvalidArgument, "port %d is out of range", *req.Port)
+		}
+		simulation.Port = uint16(*req.Port)
+	}
+
+	return simulation, nil
+}
+
+func toPolicySimulationResponse(result *types.PolicySimulationResult) *api.PolicySimulationResult {
+	resp := &api.PolicySimulationResult{
+		Allowed:             result.Allowed,
+		MatchedRules:        make([]api.PolicySimulationRuleMatch, 0, len(result.MatchedRules)),
+		FailedPostureChecks: append([]string{}, result.FailedPostureChecks...),
+		RoutingPeers:        append([]string{}, result.RoutingPeers...),
+		Reasons:             append([]string{}, result.Reasons...),
+	}
+
+	if result.DestinationType != "" {
+		destinationType := api.PolicySimulationResultDestinationType(result.DestinationType)
+		resp.DestinationType = &destinationType
+		resp.DestinationId = &result.DestinationID
+	}
+
+	for _, match := range result.MatchedRules {
+		resp.MatchedRules = append(resp.MatchedRules, api.PolicySimulationRuleMatch{
+			PolicyId: match.PolicyID,
+			RuleId:   match.RuleID,
+			Action:   api.PolicySimulationRuleMatchAction(match.Action),
+		})
+	}
+
+	return resp
+}
+
 func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 	groupsMap := make(map[string]*types.Group)
 	for _, group := range groups {
@@ -450,3 +536,10 @@ func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 	}
 	return ap
 }
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -307,3 +307,99 @@ func TestPoliciesWritePolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestPoliciesSimulatePolicy(t *testing.T) {
+	tt := []struct {
+		name           string
+		expectedStatus int
+		expectedResult *api.PolicySimulationResult
+		requestBody    string
+	}{
+		{
+			name:           "simulate peer OK",
+			requestBody:    `{"source_peer_id":"src","destination_peer_id":"dst","protocol":"tcp","port":22}`,
+			expectedStatus: http.StatusOK,
+			expectedResult: &api.PolicySimulationResult{
+				Allowed:         true,
+				DestinationType: ptr(api.PolicySimulationResultDestinationTypePeer),
+				DestinationId:   ptr("dst"),
+				MatchedRules: []api.PolicySimulationRuleMatch{
+					{PolicyId: "idofthepolicy", RuleId: "idoftherule", Action: api.PolicySimulationRuleMatchActionAccept},
+				},
+				FailedPostureChecks: []string{},
+				RoutingPeers:        []string{},
+				Reasons:             []string{},
+			},
+		},
+		{
+			name:           "simulate invalid IP",
+			requestBody:    `{"source_peer_id":"src","destination_ip":"not-an-ip","protocol":"all"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "simulate port out of range",
+			requestBody:    `{"source_peer_id":"src","destination_peer_id":"dst","protocol":"tcp","port":70000}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "simulate invalid JSON",
+			requestBody:    `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	p := &handler{
+		accountManager: &mock_server.MockAccountManager{
+			SimulatePolicyFunc: func(_ context.Context, _, _ string, req types.PolicySimulationRequest) (*types.PolicySimulationResult, error) {
+				return &types.PolicySimulationResult{
+					Allowed:         true,
+					DestinationType: types.SimulationDestinationPeer,
+					DestinationID:   req.DestinationPeerID,
+					MatchedRules: []types.PolicySimulationRuleMatch{
+						{PolicyID: "idofthepolicy", RuleID: "idoftherule", Action: types.PolicyTrafficActionAccept},
+					},
+				}, nil
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/policies/simulate", strings.NewReader(tc.requestBody))
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+				UserId:    "test_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/policies/simulate", p.simulatePolicy).Methods("POST")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedResult == nil {
+				return
+			}
+
+			content, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("I don't know what I expected; %v", err)
+			}
+
+			var got api.PolicySimulationResult
+			if err = json.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Sent content is not in correct json format; %v", err)
+			}
+
+			assert.Equal(t, *tc.expectedResult, got)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
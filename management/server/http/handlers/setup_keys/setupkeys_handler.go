@@ -25,6 +25,7 @@ func AddEndpoints(accountManager account.Manager, router *mux.Router) {
 	keysHandler := newHandler(accountManager)
 	router.HandleFunc("/setup-keys", keysHandler.getAllSetupKeys).Methods("GET", "OPTIONS")
 	router.HandleFunc("/setup-keys", keysHandler.createSetupKey).Methods("POST", "OPTIONS")
+	router.HandleFunc("/setup-keys/bulk", keysHandler.bulkUpdateSetupKeys).Methods("POST", "OPTIONS")
 	router.HandleFunc("/setup-keys/{keyId}", keysHandler.getSetupKey).Methods("GET", "OPTIONS")
 	router.HandleFunc("/setup-keys/{keyId}", keysHandler.updateSetupKey).Methods("PUT", "OPTIONS")
 	router.HandleFunc("/setup-keys/{keyId}", keysHandler.deleteSetupKey).Methods("DELETE", "OPTIONS")
@@ -86,7 +87,7 @@ func (h *handler) createSetupKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	setupKey, err := h.accountManager.CreateSetupKey(r.Context(), accountID, req.Name, types.SetupKeyType(req.Type), expiresIn,
-		req.AutoGroups, req.UsageLimit, userID, ephemeral, allowExtraDNSLabels)
+		req.AutoGroups, req.UsageLimit, userID, ephemeral, allowExtraDNSLabels, toSetupKeyConstraints(req.Constraints))
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -212,6 +213,34 @@ func (h *handler) deleteSetupKey(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
 }
 
+// bulkUpdateSetupKeys revokes or deletes a list of setup keys and returns the outcome per key
+func (h *handler) bulkUpdateSetupKeys(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	req := &api.PostApiSetupKeysBulkJSONRequestBody{}
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	op := &types.SetupKeyBulkOperation{
+		Action: types.SetupKeyBulkAction(req.Action),
+		KeyIDs: req.KeyIds,
+	}
+
+	results, err := h.accountManager.BulkUpdateSetupKeys(r.Context(), userAuth.AccountId, userAuth.UserId, op)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, types.ToBulkOperationResponse(results))
+}
+
 func writeSuccess(ctx context.Context, w http.ResponseWriter, key *types.SetupKey) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
@@ -251,5 +280,54 @@ func ToResponseBody(key *types.SetupKey) *api.SetupKey {
 		UsageLimit:          key.UsageLimit,
 		Ephemeral:           key.Ephemeral,
 		AllowExtraDnsLabels: key.AllowExtraDNSLabels,
+		Constraints:         toConstraintsResponse(key.Constraints),
+	}
+}
+
+func toConstraintsResponse(constraints types.SetupKeyConstraints) api.SetupKeyConstraints {
+	resp := api.SetupKeyConstraints{
+		InventoryDevicesOnly: constraints.InventoryDevicesOnly,
+	}
+	if len(constraints.AllowedCIDRs) > 0 {
+		resp.AllowedCidrs = &constraints.AllowedCIDRs
+	}
+	if constraints.HostnamePattern != "" {
+		resp.HostnamePattern = &constraints.HostnamePattern
+	}
+	if len(constraints.AllowedOS) > 0 {
+		allowedOS := make([]api.SetupKeyConstraintsAllowedOs, 0, len(constraints.AllowedOS))
+		for _, osType := range constraints.AllowedOS {
+			allowedOS = append(allowedOS, api.SetupKeyConstraintsAllowedOs(osType))
+		}
+		resp.AllowedOs = &allowedOS
+	}
+	if constraints.MinClientVersion != "" {
+		resp.MinClientVersion = &constraints.MinClientVersion
+	}
+	return resp
+}
+
+func toSetupKeyConstraints(req *api.SetupKeyConstraints) types.SetupKeyConstraints {
+	if req == nil {
+		return types.SetupKeyConstraints{}
+	}
+
+	constraints := types.SetupKeyConstraints{
+		InventoryDevicesOnly: req.InventoryDevicesOnly,
+	}
+	if req.AllowedCidrs != nil {
+		constraints.AllowedCIDRs = *req.AllowedCidrs
+	}
+	if req.HostnamePattern != nil {
+		constraints.HostnamePattern = *req.HostnamePattern
+	}
+	if req.AllowedOs != nil {
+		for _, osType := range *req.AllowedOs {
+			constraints.AllowedOS = append(constraints.AllowedOS, string(osType))
+		}
+	}
+	if req.MinClientVersion != nil {
+		constraints.MinClientVersion = *req.MinClientVersion
 	}
+	return constraints
 }
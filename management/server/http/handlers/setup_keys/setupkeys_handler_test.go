@@ -32,12 +32,13 @@ func initSetupKeysTestMetaData(defaultKey *types.SetupKey, newKey *types.SetupKe
 	return &handler{
 		accountManager: &mock_server.MockAccountManager{
 			CreateSetupKeyFunc: func(_ context.Context, _ string, keyName string, typ types.SetupKeyType, _ time.Duration, _ []string,
-				_ int, _ string, ephemeral bool, allowExtraDNSLabels bool,
+				_ int, _ string, ephemeral bool, allowExtraDNSLabels bool, constraints types.SetupKeyConstraints,
 			) (*types.SetupKey, error) {
 				if keyName == newKey.Name || typ != newKey.Type {
 					nk := newKey.Copy()
 					nk.Ephemeral = ephemeral
 					nk.AllowExtraDNSLabels = allowExtraDNSLabels
+					nk.Constraints = constraints
 					return nk, nil
 				}
 				return nil, fmt.Errorf("failed creating setup key")
@@ -90,6 +91,15 @@ func TestSetupKeysHandlers(t *testing.T) {
 
 	expectedNewKey := ToResponseBody(newSetupKey)
 	expectedNewKey.Key = plainKey
+	constrainedKey := newSetupKey.Copy()
+	constrainedKey.Constraints = types.SetupKeyConstraints{
+		AllowedCIDRs:     []string{"203.0.113.0/24"},
+		HostnamePattern:  "ci-runner-[0-9]+",
+		AllowedOS:        []string{"linux"},
+		MinClientVersion: "0.40.0",
+	}
+	expectedConstrainedKey := ToResponseBody(constrainedKey)
+	expectedConstrainedKey.Key = plainKey
 	tt := []struct {
 		name              string
 		requestType       string
@@ -133,6 +143,18 @@ func TestSetupKeysHandlers(t *testing.T) {
 			expectedBody:     true,
 			expectedSetupKey: expectedNewKey,
 		},
+		{
+			name:        "Create Setup Key with constraints",
+			requestType: http.MethodPost,
+			requestPath: "/api/setup-keys",
+			requestBody: bytes.NewBuffer(
+				[]byte(fmt.Sprintf(`{"name":"%s","type":"%s","expires_in":86400,"ephemeral":true,"constraints":{"inventory_devices_only":false,`+
+					`"allowed_cidrs":["203.0.113.0/24"],"hostname_pattern":"ci-runner-[0-9]+","allowed_os":["linux"],"min_client_version":"0.40.0"}}`,
+					newSetupKey.Name, newSetupKey.Type))),
+			expectedStatus:   http.StatusOK,
+			expectedBody:     true,
+			expectedSetupKey: expectedConstrainedKey,
+		},
 		{
 			name:        "Update Setup Key",
 			requestType: http.MethodPut,
@@ -230,4 +252,83 @@ func assertKeys(t *testing.T, got *api.SetupKey, expected *api.SetupKey) {
 	assert.Equal(t, got.Revoked, expected.Revoked)
 	assert.ElementsMatch(t, got.AutoGroups, expected.AutoGroups)
 	assert.Equal(t, got.Ephemeral, expected.Ephemeral)
+	assert.Equal(t, got.Constraints, expected.Constraints)
+}
+
+func TestBulkUpdateSetupKeys(t *testing.T) {
+	h := &handler{
+		accountManager: &mock_server.MockAccountManager{
+			BulkUpdateSetupKeysFunc: func(_ context.Context, _, _ string, op *types.SetupKeyBulkOperation) ([]*types.BulkItemResult, error) {
+				if err := op.Validate(); err != nil {
+					return nil, err
+				}
+				results := make([]*types.BulkItemResult, 0, len(op.KeyIDs))
+				for _, keyID := range op.KeyIDs {
+					result := &types.BulkItemResult{ID: keyID}
+					if keyID != existingSetupKeyID {
+						result.Err = status.NewSetupKeyNotFoundError(keyID)
+					}
+					results = append(results, result)
+				}
+				return results, nil
+			},
+		},
+	}
+
+	notFoundErr := status.NewSetupKeyNotFoundError(notFoundSetupKeyID).Error()
+
+	tt := []struct {
+		name            string
+		requestBody     string
+		expectedStatus  int
+		expectedResults []api.BulkOperationItemResult
+	}{
+		{
+			name:           "revoke keys",
+			requestBody:    fmt.Sprintf(`{"action":"revoke","key_ids":["%s","%s"]}`, existingSetupKeyID, notFoundSetupKeyID),
+			expectedStatus: http.StatusOK,
+			expectedResults: []api.BulkOperationItemResult{
+				{Id: existingSetupKeyID, Status: api.BulkOperationItemResultStatusSuccess},
+				{Id: notFoundSetupKeyID, Status: api.BulkOperationItemResultStatusFailed, Error: &notFoundErr},
+			},
+		},
+		{
+			name:           "unknown action",
+			requestBody:    fmt.Sprintf(`{"action":"rename","key_ids":["%s"]}`, existingSetupKeyID),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "no keys",
+			requestBody:    `{"action":"delete","key_ids":[]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/setup-keys/bulk", bytes.NewBufferString(tc.requestBody))
+			req = nbcontext.SetUserAuthInRequest(req, nbcontext.UserAuth{
+				UserId:    "test_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/setup-keys/bulk", h.bulkUpdateSetupKeys).Methods("POST")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var got api.BulkOperationResponse
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			assert.Equal(t, tc.expectedResults, got.Results)
+		})
+	}
 }
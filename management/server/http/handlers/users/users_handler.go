@@ -3,10 +3,8 @@ package users
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/gorilla/mux"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/http/api"
@@ -195,37 +193,60 @@ func (h *handler) getAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accountID, userID := userAuth.AccountId, userAuth.UserId
-	data, err := h.accountManager.GetUsersFromAccount(r.Context(), accountID, userID)
+	filter, err := toUserFilter(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	page, err := util.ParsePageRequest(r)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
 	}
 
-	serviceUser := r.URL.Query().Get("service_user")
+	accountID, userID := userAuth.AccountId, userAuth.UserId
+	data, nextCursor, err := h.accountManager.GetUsersPage(r.Context(), accountID, userID, filter, page)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
 
 	users := make([]*api.User, 0)
 	for _, d := range data {
 		if d.NonDeletable {
 			continue
 		}
-		if serviceUser == "" {
-			users = append(users, toUserResponse(d, userID))
-			continue
-		}
+		users = append(users, toUserResponse(d, userID))
+	}
 
-		includeServiceUser, err := strconv.ParseBool(serviceUser)
-		log.WithContext(r.Context()).Debugf("Should include service user: %v", includeServiceUser)
-		if err != nil {
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid service_user query parameter"), w)
-			return
-		}
-		if includeServiceUser == d.IsServiceUser {
-			users = append(users, toUserResponse(d, userID))
+	util.WriteNextCursor(w, nextCursor)
+	util.WriteJSONObject(r.Context(), w, users)
+}
+
+// toUserFilter parses the user list filters from the query parameters
+func toUserFilter(r *http.Request) (types.UserFilter, error) {
+	filter := types.UserFilter{
+		GroupID: r.URL.Query().Get("group_id"),
+	}
+
+	if role := r.URL.Query().Get("role"); role != "" {
+		userRole := types.StrRoleToUserRole(role)
+		if userRole == types.UserRoleUnknown {
+			return filter, status.Errorf(status.InvalidArgument, "invalid role query parameter")
 		}
+		filter.Role = userRole
 	}
 
-	util.WriteJSONObject(r.Context(), w, users)
+	var err error
+	if filter.ServiceUser, err = util.ParseBoolQuery(r, "service_user"); err != nil {
+		return filter, err
+	}
+	if filter.Blocked, err = util.ParseBoolQuery(r, "blocked"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
 }
 
 // inviteUser resend invitations to users who haven't activated their accounts,
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -15,6 +16,7 @@ import (
 
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/util"
 	"github.com/netbirdio/netbird/management/server/mock_server"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -82,6 +84,38 @@ func initUsersTestData() *handler {
 				}
 				return usersInfos, nil
 			},
+			GetUsersPageFunc: func(_ context.Context, accountID, userID string, filter types.UserFilter, page types.PageRequest) ([]*types.UserInfo, string, error) {
+				userIDs := make([]string, 0, len(usersTestAccount.Users))
+				for id, v := range usersTestAccount.Users {
+					if filter.ServiceUser != nil && *filter.ServiceUser != v.IsServiceUser {
+						continue
+					}
+					if filter.Role != "" && filter.Role != v.Role {
+						continue
+					}
+					userIDs = append(userIDs, id)
+				}
+				sort.Strings(userIDs)
+
+				var nextCursor string
+				if page.Limit > 0 && len(userIDs) > page.Limit {
+					userIDs = userIDs[:page.Limit]
+					nextCursor = "next-" + userIDs[len(userIDs)-1]
+				}
+
+				usersInfos := make([]*types.UserInfo, 0, len(userIDs))
+				for _, id := range userIDs {
+					v := usersTestAccount.Users[id]
+					usersInfos = append(usersInfos, &types.UserInfo{
+						ID:            v.Id,
+						Role:          string(v.Role),
+						IsServiceUser: v.IsServiceUser,
+						NonDeletable:  v.NonDeletable,
+						Issued:        v.Issued,
+					})
+				}
+				return usersInfos, nextCursor, nil
+			},
 			CreateUserFunc: func(_ context.Context, accountID, userID string, key *types.UserInfo) (*types.UserInfo, error) {
 				if userID != existingUserID {
 					return nil, status.Errorf(status.NotFound, "user with ID %s does not exists", userID)
@@ -134,10 +168,15 @@ func TestGetUsers(t *testing.T) {
 		requestType     string
 		requestPath     string
 		expectedUserIDs []string
+		expectedCursor  string
 	}{
 		{name: "getAllUsers", requestType: http.MethodGet, requestPath: "/api/users", expectedStatus: http.StatusOK, expectedUserIDs: []string{existingUserID, regularUserID, serviceUserID}},
 		{name: "GetOnlyServiceUsers", requestType: http.MethodGet, requestPath: "/api/users?service_user=true", expectedStatus: http.StatusOK, expectedUserIDs: []string{serviceUserID}},
 		{name: "GetOnlyRegularUsers", requestType: http.MethodGet, requestPath: "/api/users?service_user=false", expectedStatus: http.StatusOK, expectedUserIDs: []string{existingUserID, regularUserID}},
+		{name: "GetUsersByRole", requestType: http.MethodGet, requestPath: "/api/users?role=user", expectedStatus: http.StatusOK, expectedUserIDs: []string{regularUserID, serviceUserID}},
+		{name: "GetUsersPage", requestType: http.MethodGet, requestPath: "/api/users?limit=1&service_user=false", expectedStatus: http.StatusOK, expectedUserIDs: []string{existingUserID}, expectedCursor: "next-" + existingUserID},
+		{name: "GetUsersInvalidLimit", requestType: http.MethodGet, requestPath: "/api/users?limit=0", expectedStatus: http.StatusUnprocessableEntity},
+		{name: "GetUsersInvalidRole", requestType: http.MethodGet, requestPath: "/api/users?role=superuser", expectedStatus: http.StatusUnprocessableEntity},
 	}
 
 	userHandler := initUsersTestData()
@@ -168,6 +207,12 @@ func TestGetUsers(t *testing.T) {
 				return
 			}
 
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tc.expectedCursor, res.Header.Get(util.NextCursorHeader))
+
 			respBody := []*types.UserInfo{}
 			err = json.Unmarshal(content, &respBody)
 			if err != nil {
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// NextCursorHeader is the response header holding the cursor of the next page, it is not set on the last page
+const NextCursorHeader = "X-Next-Cursor"
+
+// ParsePageRequest parses the limit, cursor, sort_by and sort_order query parameters.
+// Without a limit all the objects are returned.
+func ParsePageRequest(r *http.Request) (types.PageRequest, error) {
+	query := r.URL.Query()
+
+	page := types.PageRequest{
+		Cursor: query.Get("cursor"),
+		SortBy: query.Get("sort_by"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil || page.Limit < 1 || page.Limit > types.MaxPageLimit {
+			return page, status.Errorf(status.InvalidArgument, "limit must be a number between 1 and %d", types.MaxPageLimit)
+		}
+	}
+
+	switch query.Get("sort_order") {
+	case "", types.SortOrderAsc:
+	case types.SortOrderDesc:
+		page.SortDesc = true
+	default:
+		return page, status.Errorf(status.InvalidArgument, "sort_order must be %s or %s", types.SortOrderAsc, types.SortOrderDesc)
+	}
+
+	return page, nil
+}
+
+// ParseBoolQuery parses an optional boolean query parameter, returning nil if it isn't set
+func ParseBoolQuery(r *http.Request, name string) (*bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid %s query parameter", name)
+	}
+	return &parsed, nil
+}
+
+// ParseTimeQuery parses an optional RFC3339 time query parameter, returning nil if it isn't set
+func ParseTimeQuery(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid %s query parameter, expected RFC3339 time", name)
+	}
+	return &parsed, nil
+}
+
+// WriteNextCursor sets the next page cursor header, it must be called before writing the response body
+func WriteNextCursor(w http.ResponseWriter, cursor string) {
+	if cursor != "" {
+		w.Header().Set(NextCursorHeader, cursor)
+	}
+}
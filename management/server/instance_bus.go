@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/ha"
+)
+
+// SetInstanceBus connects the account manager to the other management instances sharing the database.
+// Updates for peers connected to the other instances are published to the bus and
+// the events of the other instances update the peers connected to this one.
+func (am *DefaultAccountManager) SetInstanceBus(bus ha.Bus) {
+	am.instanceBus = bus
+	bus.Subscribe(am.handleInstanceEvent)
+}
+
+func (am *DefaultAccountManager) publishInstanceEvent(ctx context.Context, event *ha.Event) {
+	if am.instanceBus == nil {
+		return
+	}
+
+	if err := am.instanceBus.Publish(ctx, event); err != nil {
+		log.WithContext(ctx).Errorf("failed to publish the %s event of account %s to the other management instances: %v", event.Type, event.AccountID, err)
+	}
+}
+
+// handleInstanceEvent applies an event of another management instance to the peers connected to this one
+func (am *DefaultAccountManager) handleInstanceEvent(ctx context.Context, event *ha.Event) {
+	log.WithContext(ctx).Tracef("received the %s event of account %s from management instance %s", event.Type, event.AccountID, event.Origin)
+
+	switch event.Type {
+	case ha.AccountPeersUpdated:
+		am.updateLocalAccountPeers(ctx, event.AccountID)
+	case ha.PeerUpdated:
+		for _, peerID := range event.PeerIDs {
+			am.updateLocalAccountPeer(ctx, event.AccountID, peerID)
+		}
+	case ha.PeersDisconnected:
+		am.peersUpdateManager.CloseChannels(ctx, event.PeerIDs)
+	default:
+		log.WithContext(ctx).Warnf("received an unknown event type %s from management instance %s", event.Type, event.Origin)
+	}
+}
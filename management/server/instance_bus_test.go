@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/ha"
+)
+
+type testInstanceBus struct {
+	mu      sync.Mutex
+	events  []*ha.Event
+	handler ha.Handler
+}
+
+func (b *testInstanceBus) Publish(_ context.Context, event *ha.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *testInstanceBus) Subscribe(handler ha.Handler) {
+	b.handler = handler
+}
+
+func (b *testInstanceBus) Close() error {
+	return nil
+}
+
+func (b *testInstanceBus) published() []*ha.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*ha.Event{}, b.events...)
+}
+
+func (b *testInstanceBus) last() *ha.Event {
+	events := b.published()
+	if len(events) == 0 {
+		return nil
+	}
+	return events[len(events)-1]
+}
+
+func TestDefaultAccountManager_InstanceBus(t *testing.T) {
+	manager, account, peer1, peer2, _ := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	bus := &testInstanceBus{}
+	manager.SetInstanceBus(bus)
+	require.NotNil(t, bus.handler, "account manager should subscribe to the bus")
+
+	// peer1 is connected to this instance, peer2 to another one
+	updMsg := manager.peersUpdateManager.CreateChannel(ctx, peer1.ID)
+	t.Cleanup(func() {
+		manager.peersUpdateManager.CloseChannel(ctx, peer1.ID)
+	})
+
+	t.Run("account update is published", func(t *testing.T) {
+		manager.UpdateAccountPeers(ctx, account.Id)
+		peerShouldReceiveUpdate(t, updMsg)
+		assert.Equal(t, &ha.Event{Type: ha.AccountPeersUpdated, AccountID: account.Id}, bus.last())
+	})
+
+	t.Run("update of a peer connected to another instance is published", func(t *testing.T) {
+		manager.UpdateAccountPeer(ctx, account.Id, peer2.ID)
+		assert.Equal(t, &ha.Event{Type: ha.PeerUpdated, AccountID: account.Id, PeerIDs: []string{peer2.ID}}, bus.last())
+	})
+
+	t.Run("update of a local peer is not published", func(t *testing.T) {
+		count := len(bus.published())
+		manager.UpdateAccountPeer(ctx, account.Id, peer1.ID)
+		peerShouldReceiveUpdate(t, updMsg)
+		assert.Len(t, bus.published(), count)
+	})
+
+	t.Run("account update of another instance updates local peers", func(t *testing.T) {
+		count := len(bus.published())
+		bus.handler(ctx, &ha.Event{Type: ha.AccountPeersUpdated, AccountID: account.Id, Origin: "other"})
+		peerShouldReceiveUpdate(t, updMsg)
+		assert.Len(t, bus.published(), count, "events of other instances should not be published again")
+	})
+
+	t.Run("peer update of another instance updates the local peer", func(t *testing.T) {
+		bus.handler(ctx, &ha.Event{Type: ha.PeerUpdated, AccountID: account.Id, PeerIDs: []string{peer1.ID}, Origin: "other"})
+		peerShouldReceiveUpdate(t, updMsg)
+	})
+
+	t.Run("deleted peer is published", func(t *testing.T) {
+		err := manager.DeletePeer(ctx, account.Id, peer2.ID, userID)
+		require.NoError(t, err)
+
+		events := bus.published()
+		assert.Contains(t, events, &ha.Event{Type: ha.PeersDisconnected, AccountID: account.Id, PeerIDs: []string{peer2.ID}})
+	})
+
+	t.Run("disconnect of another instance closes the local stream", func(t *testing.T) {
+		bus.handler(ctx, &ha.Event{Type: ha.PeersDisconnected, AccountID: account.Id, PeerIDs: []string{peer1.ID}, Origin: "other"})
+		assert.False(t, manager.peersUpdateManager.HasChannel(peer1.ID))
+	})
+}
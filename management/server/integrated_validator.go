@@ -34,7 +34,10 @@ func (am *DefaultAccountManager) UpdateIntegratedValidatorGroups(ctx context.Con
 		return errors.New("invalid groups")
 	}
 
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	a, err := am.Store.GetAccountByUser(ctx, userID)
@@ -104,6 +107,7 @@ func (am *DefaultAccountManager) GetValidatedPeers(ctx context.Context, accountI
 
 type MocIntegratedValidator struct {
 	ValidatePeerFunc func(_ context.Context, update *nbpeer.Peer, peer *nbpeer.Peer, userID string, accountID string, dnsDomain string, peersGroup []string, extraSettings *types.ExtraSettings) (*nbpeer.Peer, bool, error)
+	PreparePeerFunc  func(_ context.Context, accountID string, peer *nbpeer.Peer, peersGroup []string, extraSettings *types.ExtraSettings) *nbpeer.Peer
 }
 
 func (a MocIntegratedValidator) ValidateExtraSettings(_ context.Context, newExtraSettings *types.ExtraSettings, oldExtraSettings *types.ExtraSettings, peers map[string]*nbpeer.Peer, userID string, accountID string) error {
@@ -125,7 +129,10 @@ func (a MocIntegratedValidator) GetValidatedPeers(accountID string, groups []*ty
 	return validatedPeers, nil
 }
 
-func (MocIntegratedValidator) PreparePeer(_ context.Context, accountID string, peer *nbpeer.Peer, peersGroup []string, extraSettings *types.ExtraSettings) *nbpeer.Peer {
+func (a MocIntegratedValidator) PreparePeer(_ context.Context, accountID string, peer *nbpeer.Peer, peersGroup []string, extraSettings *types.ExtraSettings) *nbpeer.Peer {
+	if a.PreparePeerFunc != nil {
+		return a.PreparePeerFunc(context.Background(), accountID, peer, peersGroup, extraSettings)
+	}
 	return peer
 }
 
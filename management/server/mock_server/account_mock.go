@@ -14,6 +14,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/activity"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/eventstream"
 	"github.com/netbirdio/netbird/management/server/idp"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/posture"
@@ -28,21 +29,25 @@ type MockAccountManager struct {
 	GetOrCreateAccountByUserFunc func(ctx context.Context, userId, domain string) (*types.Account, error)
 	GetAccountFunc               func(ctx context.Context, accountID string) (*types.Account, error)
 	CreateSetupKeyFunc           func(ctx context.Context, accountId string, keyName string, keyType types.SetupKeyType,
-		expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool) (*types.SetupKey, error)
+		expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool,
+		constraints types.SetupKeyConstraints) (*types.SetupKey, error)
 	GetSetupKeyFunc                     func(ctx context.Context, accountID, userID, keyID string) (*types.SetupKey, error)
 	AccountExistsFunc                   func(ctx context.Context, accountID string) (bool, error)
 	GetAccountIDByUserIdFunc            func(ctx context.Context, userId, domain string) (string, error)
 	GetUserFromUserAuthFunc             func(ctx context.Context, userAuth nbcontext.UserAuth) (*types.User, error)
 	ListUsersFunc                       func(ctx context.Context, accountID string) ([]*types.User, error)
 	GetPeersFunc                        func(ctx context.Context, accountID, userID, nameFilter, ipFilter string) ([]*nbpeer.Peer, error)
+	GetPeersPageFunc                    func(ctx context.Context, accountID, userID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error)
 	MarkPeerConnectedFunc               func(ctx context.Context, peerKey string, connected bool, realIP net.IP) error
 	SyncAndMarkPeerFunc                 func(ctx context.Context, accountID string, peerPubKey string, meta nbpeer.PeerSystemMeta, realIP net.IP) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error)
 	DeletePeerFunc                      func(ctx context.Context, accountID, peerKey, userID string) error
+	BulkUpdatePeersFunc                 func(ctx context.Context, accountID, userID string, op *types.PeerBulkOperation) ([]*types.BulkItemResult, error)
 	GetNetworkMapFunc                   func(ctx context.Context, peerKey string) (*types.NetworkMap, error)
 	GetPeerNetworkFunc                  func(ctx context.Context, peerKey string) (*types.Network, error)
 	AddPeerFunc                         func(ctx context.Context, setupKey string, userId string, peer *nbpeer.Peer) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error)
 	GetGroupFunc                        func(ctx context.Context, accountID, groupID, userID string) (*types.Group, error)
 	GetAllGroupsFunc                    func(ctx context.Context, accountID, userID string) ([]*types.Group, error)
+	PreviewGroupQueryFunc               func(ctx context.Context, accountID, userID string, query *types.GroupQuery) ([]*nbpeer.Peer, error)
 	GetGroupByNameFunc                  func(ctx context.Context, accountID, groupName string) (*types.Group, error)
 	SaveGroupFunc                       func(ctx context.Context, accountID, userID string, group *types.Group) error
 	SaveGroupsFunc                      func(ctx context.Context, accountID, userID string, groups []*types.Group) error
@@ -56,7 +61,9 @@ type MockAccountManager struct {
 	SavePolicyFunc                      func(ctx context.Context, accountID, userID string, policy *types.Policy) (*types.Policy, error)
 	DeletePolicyFunc                    func(ctx context.Context, accountID, policyID, userID string) error
 	ListPoliciesFunc                    func(ctx context.Context, accountID, userID string) ([]*types.Policy, error)
+	SimulatePolicyFunc                  func(ctx context.Context, accountID, userID string, req types.PolicySimulationRequest) (*types.PolicySimulationResult, error)
 	GetUsersFromAccountFunc             func(ctx context.Context, accountID, userID string) (map[string]*types.UserInfo, error)
+	GetUsersPageFunc                    func(ctx context.Context, accountID, initiatorUserID string, filter types.UserFilter, page types.PageRequest) ([]*types.UserInfo, string, error)
 	UpdatePeerMetaFunc                  func(ctx context.Context, peerID string, meta nbpeer.PeerSystemMeta) error
 	UpdatePeerFunc                      func(ctx context.Context, accountID, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, error)
 	CreateRouteFunc                     func(ctx context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peer string, peerGroups []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroupIDs []string, enabled bool, userID string, keepRoute bool) (*route.Route, error)
@@ -110,11 +117,33 @@ type MockAccountManager struct {
 	GetUserByIDFunc                     func(ctx context.Context, id string) (*types.User, error)
 	GetAccountSettingsFunc              func(ctx context.Context, accountID string, userID string) (*types.Settings, error)
 	DeleteSetupKeyFunc                  func(ctx context.Context, accountID, userID, keyID string) error
+	BulkUpdateSetupKeysFunc             func(ctx context.Context, accountID, userID string, op *types.SetupKeyBulkOperation) ([]*types.BulkItemResult, error)
 	BuildUserInfosForAccountFunc        func(ctx context.Context, accountID, initiatorUserID string, accountUsers []*types.User) (map[string]*types.UserInfo, error)
 	GetStoreFunc                        func() store.Store
 	CreateAccountByPrivateDomainFunc    func(ctx context.Context, initiatorId, domain string) (*types.Account, error)
 	UpdateToPrimaryAccountFunc          func(ctx context.Context, accountId string) (*types.Account, error)
 	GetOwnerInfoFunc                    func(ctx context.Context, accountID string) (*types.UserInfo, error)
+	GetChildAccountsFunc                func(ctx context.Context, accountID, userID string) ([]*types.ChildAccount, error)
+	SetAccountParentFunc                func(ctx context.Context, accountID, userID, parentAccountID string) error
+	AcceptChildAccountFunc              func(ctx context.Context, accountID, userID, childAccountID string) error
+	RemoveChildAccountFunc              func(ctx context.Context, accountID, userID, childAccountID string) error
+	PushTemplatesToChildAccountsFunc    func(ctx context.Context, accountID, userID string, templates *types.ChildAccountTemplates) ([]*types.BulkItemResult, error)
+	GetChildAccountsEventsFunc          func(ctx context.Context, accountID, userID string) ([]*activity.Event, error)
+	SubscribeEventsFunc                 func(ctx context.Context, accountID, userID string, filter eventstream.Filter, lastEventID uint64) (*eventstream.Subscription, error)
+	UnsubscribeEventsFunc               func(subscription *eventstream.Subscription)
+	GetPeerApprovalRequestsFunc         func(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRequest, error)
+	ApprovePeerFunc                     func(ctx context.Context, accountID, userID, peerID, reason string) (*nbpeer.Peer, error)
+	RejectPeerFunc                      func(ctx context.Context, accountID, userID, peerID, reason string) error
+	GetPeerApprovalRulesFunc            func(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRule, error)
+	GetPeerApprovalRuleFunc             func(ctx context.Context, accountID, userID, ruleID string) (*types.PeerApprovalRule, error)
+	SavePeerApprovalRuleFunc            func(ctx context.Context, accountID, userID string, rule *types.PeerApprovalRule) (*types.PeerApprovalRule, error)
+	DeletePeerApprovalRuleFunc          func(ctx context.Context, accountID, userID, ruleID string) error
+	GetInventoryDevicesFunc             func(ctx context.Context, accountID, userID string) ([]*types.InventoryDevice, error)
+	GetInventoryDeviceFunc              func(ctx context.Context, accountID, userID, deviceID string) (*types.InventoryDevice, error)
+	SaveInventoryDeviceFunc             func(ctx context.Context, accountID, userID string, device *types.InventoryDevice) (*types.InventoryDevice, error)
+	DeleteInventoryDeviceFunc           func(ctx context.Context, accountID, userID, deviceID string) error
+	ImportInventoryDevicesFunc          func(ctx context.Context, accountID, userID string, devices []*types.InventoryDevice) (*types.InventoryImportSummary, error)
+	GetInventoriedSerialNumbersFunc     func(ctx context.Context, accountID string) (map[string]struct{}, error)
 }
 
 func (am *MockAccountManager) UpdateAccountPeers(ctx context.Context, accountID string) {
@@ -169,6 +198,14 @@ func (am *MockAccountManager) GetAllGroups(ctx context.Context, accountID, userI
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllGroups is not implemented")
 }
 
+// PreviewGroupQuery mock implementation of PreviewGroupQuery from server.AccountManager interface
+func (am *MockAccountManager) PreviewGroupQuery(ctx context.Context, accountID, userID string, query *types.GroupQuery) ([]*nbpeer.Peer, error) {
+	if am.PreviewGroupQueryFunc != nil {
+		return am.PreviewGroupQueryFunc(ctx, accountID, userID, query)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewGroupQuery is not implemented")
+}
+
 // GetUsersFromAccount mock implementation of GetUsersFromAccount from server.AccountManager interface
 func (am *MockAccountManager) GetUsersFromAccount(ctx context.Context, accountID string, userID string) (map[string]*types.UserInfo, error) {
 	if am.GetUsersFromAccountFunc != nil {
@@ -177,6 +214,30 @@ func (am *MockAccountManager) GetUsersFromAccount(ctx context.Context, accountID
 	return nil, status.Errorf(codes.Unimplemented, "method GetUsersFromAccount is not implemented")
 }
 
+// GetUsersPage mock implementation of GetUsersPage from server.AccountManager interface
+func (am *MockAccountManager) GetUsersPage(ctx context.Context, accountID, initiatorUserID string, filter types.UserFilter, page types.PageRequest) ([]*types.UserInfo, string, error) {
+	if am.GetUsersPageFunc != nil {
+		return am.GetUsersPageFunc(ctx, accountID, initiatorUserID, filter, page)
+	}
+	return nil, "", status.Errorf(codes.Unimplemented, "method GetUsersPage is not implemented")
+}
+
+// BulkUpdateSetupKeys mock implementation of BulkUpdateSetupKeys from server.AccountManager interface
+func (am *MockAccountManager) BulkUpdateSetupKeys(ctx context.Context, accountID, userID string, op *types.SetupKeyBulkOperation) ([]*types.BulkItemResult, error) {
+	if am.BulkUpdateSetupKeysFunc != nil {
+		return am.BulkUpdateSetupKeysFunc(ctx, accountID, userID, op)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method BulkUpdateSetupKeys is not implemented")
+}
+
+// BulkUpdatePeers mock implementation of BulkUpdatePeers from server.AccountManager interface
+func (am *MockAccountManager) BulkUpdatePeers(ctx context.Context, accountID, userID string, op *types.PeerBulkOperation) ([]*types.BulkItemResult, error) {
+	if am.BulkUpdatePeersFunc != nil {
+		return am.BulkUpdatePeersFunc(ctx, accountID, userID, op)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method BulkUpdatePeers is not implemented")
+}
+
 // DeletePeer mock implementation of DeletePeer from server.AccountManager interface
 func (am *MockAccountManager) DeletePeer(ctx context.Context, accountID, peerID, userID string) error {
 	if am.DeletePeerFunc != nil {
@@ -210,9 +271,10 @@ func (am *MockAccountManager) CreateSetupKey(
 	userID string,
 	ephemeral bool,
 	allowExtraDNSLabels bool,
+	constraints types.SetupKeyConstraints,
 ) (*types.SetupKey, error) {
 	if am.CreateSetupKeyFunc != nil {
-		return am.CreateSetupKeyFunc(ctx, accountID, keyName, keyType, expiresIn, autoGroups, usageLimit, userID, ephemeral, allowExtraDNSLabels)
+		return am.CreateSetupKeyFunc(ctx, accountID, keyName, keyType, expiresIn, autoGroups, usageLimit, userID, ephemeral, allowExtraDNSLabels, constraints)
 	}
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSetupKey is not implemented")
 }
@@ -401,6 +463,14 @@ func (am *MockAccountManager) DeletePolicy(ctx context.Context, accountID, polic
 	return status.Errorf(codes.Unimplemented, "method DeletePolicy is not implemented")
 }
 
+// SimulatePolicy mock implementation of SimulatePolicy from server.AccountManager interface
+func (am *MockAccountManager) SimulatePolicy(ctx context.Context, accountID, userID string, req types.PolicySimulationRequest) (*types.PolicySimulationResult, error) {
+	if am.SimulatePolicyFunc != nil {
+		return am.SimulatePolicyFunc(ctx, accountID, userID, req)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method SimulatePolicy is not implemented")
+}
+
 // ListPolicies mock implementation of ListPolicies from server.AccountManager interface
 func (am *MockAccountManager) ListPolicies(ctx context.Context, accountID, userID string) ([]*types.Policy, error) {
 	if am.ListPoliciesFunc != nil {
@@ -617,6 +687,14 @@ func (am *MockAccountManager) GetPeers(ctx context.Context, accountID, userID, n
 	return nil, status.Errorf(codes.Unimplemented, "method GetPeers is not implemented")
 }
 
+// GetPeersPage mocks GetPeersPage of the AccountManager interface
+func (am *MockAccountManager) GetPeersPage(ctx context.Context, accountID, userID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error) {
+	if am.GetPeersPageFunc != nil {
+		return am.GetPeersPageFunc(ctx, accountID, userID, filter, page)
+	}
+	return nil, "", status.Errorf(codes.Unimplemented, "method GetPeersPage is not implemented")
+}
+
 // GetDNSDomain mocks GetDNSDomain of the AccountManager interface
 func (am *MockAccountManager) GetDNSDomain() string {
 	if am.GetDNSDomainFunc != nil {
@@ -871,3 +949,183 @@ func (am *MockAccountManager) GetOwnerInfo(ctx context.Context, accountId string
 	}
 	return nil, status.Errorf(codes.Unimplemented, "method GetOwnerInfo is not implemented")
 }
+
+// GetChildAccounts mocks GetChildAccounts of the AccountManager interface
+func (am *MockAccountManager) GetChildAccounts(ctx context.Context, accountID, userID string) ([]*types.ChildAccount, error) {
+	if am.GetChildAccountsFunc != nil {
+		return am.GetChildAccountsFunc(ctx, accountID, userID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetChildAccounts is not implemented")
+}
+
+// SetAccountParent mocks SetAccountParent of the AccountManager interface
+func (am *MockAccountManager) SetAccountParent(ctx context.Context, accountID, userID, parentAccountID string) error {
+	if am.SetAccountParentFunc != nil {
+		return am.SetAccountParentFunc(ctx, accountID, userID, parentAccountID)
+	}
+	return status.Errorf(codes.Unimplemented, "method SetAccountParent is not implemented")
+}
+
+// AcceptChildAccount mocks AcceptChildAccount of the AccountManager interface
+func (am *MockAccountManager) AcceptChildAccount(ctx context.Context, accountID, userID, childAccountID string) error {
+	if am.AcceptChildAccountFunc != nil {
+		return am.AcceptChildAccountFunc(ctx, accountID, userID, childAccountID)
+	}
+	return status.Errorf(codes.Unimplemented, "method AcceptChildAccount is not implemented")
+}
+
+// RemoveChildAccount mocks RemoveChildAccount of the AccountManager interface
+func (am *MockAccountManager) RemoveChildAccount(ctx context.Context, accountID, userID, childAccountID string) error {
+	if am.RemoveChildAccountFunc != nil {
+		return am.RemoveChildAccountFunc(ctx, accountID, userID, childAccountID)
+	}
+	return status.Errorf(codes.Unimplemented, "method RemoveChildAccount is not implemented")
+}
+
+// PushTemplatesToChildAccounts mocks PushTemplatesToChildAccounts of the AccountManager interface
+func (am *MockAccountManager) PushTemplatesToChildAccounts(ctx context.Context, accountID, userID string, templates *types.ChildAccountTemplates) ([]*types.BulkItemResult, error) {
+	if am.PushTemplatesToChildAccountsFunc != nil {
+		return am.PushTemplatesToChildAccountsFunc(ctx, accountID, userID, templates)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method PushTemplatesToChildAccounts is not implemented")
+}
+
+// GetChildAccountsEvents mocks GetChildAccountsEvents of the AccountManager interface
+func (am *MockAccountManager) GetChildAccountsEvents(ctx context.Context, accountID, userID string) ([]*activity.Event, error) {
+	if am.GetChildAccountsEventsFunc != nil {
+		return am.GetChildAccountsEventsFunc(ctx, accountID, userID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetChildAccountsEvents is not implemented")
+}
+
+// SubscribeEvents mocks SubscribeEvents of the AccountManager interface
+func (am *MockAccountManager) SubscribeEvents(ctx context.Context, accountID, userID string, filter eventstream.Filter, lastEventID uint64) (*eventstream.Subscription, error) {
+	if am.SubscribeEventsFunc != nil {
+		return am.SubscribeEventsFunc(ctx, accountID, userID, filter, lastEventID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method SubscribeEvents is not implemented")
+}
+
+// UnsubscribeEvents mocks UnsubscribeEvents of the AccountManager interface
+func (am *MockAccountManager) UnsubscribeEvents(subscription *eventstream.Subscription) {
+	if am.UnsubscribeEventsFunc != nil {
+		am.UnsubscribeEventsFunc(subscription)
+	}
+}
+
+// GetPeerApprovalRequests mocks GetPeerApprovalRequests of the AccountManager interface
+func (am *MockAccountManager) GetPeerApprovalRequests(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRequest, error) {
+	if am.GetPeerApprovalRequestsFunc != nil {
+		return am.GetPeerApprovalRequestsFunc(ctx, accountID, userID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerApprovalRequests is not implemented")
+}
+
+// ApprovePeer mocks ApprovePeer of the AccountManager interface
+func (am *MockAccountManager) ApprovePeer(ctx context.Context, accountID, userID, peerID, reason string) (*nbpeer.Peer, error) {
+	if am.ApprovePeerFunc != nil {
+		return am.ApprovePeerFunc(ctx, accountID, userID, peerID, reason)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method ApprovePeer is not implemented")
+}
+
+// RejectPeer mocks RejectPeer of the AccountManager interface
+func (am *MockAccountManager) RejectPeer(ctx context.Context, accountID, userID, peerID, reason string) error {
+	if am.RejectPeerFunc != nil {
+		return am.RejectPeerFunc(ctx, accountID, userID, peerID, reason)
+	}
+	return status.Errorf(codes.Unimplemented, "method RejectPeer is not implemented")
+}
+
+// GetPeerApprovalRules mocks GetPeerApprovalRules of the AccountManager interface
+func (am *MockAccountManager) GetPeerApprovalRules(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRule, error) {
+	if am.GetPeerApprovalRulesFunc != nil {
+		return am.GetPeerApprovalRulesFunc(ctx, accountID, userID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerApprovalRules is not implemented")
+}
+
+// GetPeerApprovalRule mocks GetPeerApprovalRule of the AccountManager interface
+func (am *MockAccountManager) GetPeerApprovalRule(ctx context.Context, accountID, userID, ruleID string) (*types.PeerApprovalRule, error) {
+	if am.GetPeerApprovalRuleFunc != nil {
+		return am.GetPeerApprovalRuleFunc(ctx, accountID, userID, ruleID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerApprovalRule is not implemented")
+}
+
+// SavePeerApprovalRule mocks SavePeerApprovalRule of the AccountManager interface
+func (am *MockAccountManager) SavePeerApprovalRule(ctx context.Context, accountID, userID string, rule *types.PeerApprovalRule) (*types.PeerApprovalRule, error) {
+	if am.SavePeerApprovalRuleFunc != nil {
+		return am.SavePeerApprovalRuleFunc(ctx, accountID, userID, rule)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method SavePeerApprovalRule is not implemented")
+}
+
+// DeletePeerApprovalRule mocks DeletePeerApprovalRule of the AccountManager interface
+func (am *MockAccountManager) DeletePeerApprovalRule(ctx context.Context, accountID, userID, ruleID string) error {
+	if am.DeletePeerApprovalRuleFunc != nil {
+		return am.DeletePeerApprovalRuleFunc(ctx, accountID, userID, ruleID)
+	}
+	return status.Errorf(codes.Unimplemented, "method DeletePeerApprovalRule is not implemented")
+}
+
+// GetInventoryDevices mocks GetInventoryDevices of the AccountManager interface
+func (am *MockAccountManager) GetInventoryDevices(ctx context.Context, accountID, userID string) ([]*types.InventoryDevice, error) {
+	if am.GetInventoryDevicesFunc != nil {
+		return am.GetInventoryDevicesFunc(ctx, accountID, userID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventoryDevices is not implemented")
+}
+
+// GetInventoryDevice mocks GetInventoryDevice of the AccountManager interface
+func (am *MockAccountManager) GetInventoryDevice(ctx context.Context, accountID, userID, deviceID string) (*types.InventoryDevice, error) {
+	if am.GetInventoryDeviceFunc != nil {
+		return am.GetInventoryDeviceFunc(ctx, accountID, userID, deviceID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventoryDevice is not implemented")
+}
+
+// SaveInventoryDevice mocks SaveInventoryDevice of the AccountManager interface
+func (am *MockAccountManager) SaveInventoryDevice(ctx context.Context, accountID, userID string, device *types.InventoryDevice) (*types.InventoryDevice, error) {
+	if am.SaveInventoryDeviceFunc != nil {
+		return am.SaveInventoryDeviceFunc(ctx, accountID, userID, device)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method SaveInventoryDevice is not implemented")
+}
+
+// DeleteInventoryDevice mocks DeleteInventoryDevice of the AccountManager interface
+func (am *MockAccountManager) DeleteInventoryDevice(ctx context.Context, accountID, userID, deviceID string) error {
+	if am.DeleteInventoryDeviceFunc != nil {
+		return am.DeleteInventoryDeviceFunc(ctx, accountID, userID, deviceID)
+	}
+	return status.Errorf(codes.Unimplemented, "method DeleteInventoryDevice is not implemented")
+}
+
+// ImportInventoryDevices mocks ImportInventoryDevices of the AccountManager interface
+func (am *MockAccountManager) ImportInventoryDevices(ctx context.Context, accountID, userID string, devices []*types.InventoryDevice) (*types.InventoryImportSummary, error) {
+	if am.ImportInventoryDevicesFunc != nil {
+		return am.ImportInventoryDevicesFunc(ctx, accountID, userID, devices)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method ImportInventoryDevices is not implemented")
+}
+
+// GetInventoriedSerialNumbers mocks GetInventoriedSerialNumbers of the AccountManager interface
+func (am *MockAccountManager) GetInventoriedSerialNumbers(ctx context.Context, accountID string) (map[string]struct{}, error) {
+	if am.GetInventoriedSerialNumbersFunc != nil {
+		return am.GetInventoriedSerialNumbersFunc(ctx, accountID)
+	}
+	if am.GetAccountFunc == nil {
+		return nil, status.Errorf(codes.Unimplemented, "method GetInventoriedSerialNumbers is not implemented")
+	}
+
+	account, err := am.GetAccountFunc(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumbers := make(map[string]struct{}, len(account.InventoryDevices))
+	for _, device := range account.InventoryDevices {
+		serialNumbers[device.SerialNumber] = struct{}{}
+	}
+	return serialNumbers, nil
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/netbirdio/netbird/management/proto"
+)
+
+// networkMapCacheTTL is how long the last network map sent to a peer is kept to serve a delta after a reconnect
+const networkMapCacheTTL = 15 * time.Minute
+
+// networkMapEncoder replaces full network maps with deltas for the Sync stream of a peer supporting them.
+// The first delta is computed against the network map the client acknowledged when opening the stream,
+// if management still has it, the following ones against the last network map sent on the stream as the
+// stream delivers updates in order. A client failing to apply a delta reconnects and acknowledges the
+// serial it holds, a mismatch is answered with a full network map.
+type networkMapEncoder struct {
+	deltasSupported bool
+	last            *proto.NetworkMap
+
+	cache   *networkMapCache
+	peerKey string
+}
+
+func newNetworkMapEncoder(cache *networkMapCache, peerKey string, deltasSupported bool, ackedSerial uint64) *networkMapEncoder {
+	e := &networkMapEncoder{
+		deltasSupported: deltasSupported,
+		cache:           cache,
+		peerKey:         peerKey,
+	}
+
+	if !deltasSupported || ackedSerial == 0 {
+		return e
+	}
+
+	if last := cache.get(peerKey); last.GetSerial() == ackedSerial {
+		e.last = last
+	}
+
+	return e
+}
+
+// encode returns the response to send to the peer, with the network map replaced by a delta when possible
+func (e *networkMapEncoder) encode(resp *proto.SyncResponse) *proto.SyncResponse {
+	nm := resp.GetNetworkMap()
+	if nm == nil || !e.deltasSupported {
+		return resp
+	}
+
+	last := e.last
+	e.last = nm
+	e.cache.put(e.peerKey, nm)
+
+	// outdated network maps are sent as they are, the client ignores them
+	if last == nil || nm.GetSerial() < last.GetSerial() {
+		return resp
+	}
+
+	// the update may be shared, don't modify it
+	return &proto.SyncResponse{
+		NetbirdConfig:   resp.GetNetbirdConfig(),
+		Checks:          resp.GetChecks(),
+		NetworkMapDelta: proto.ComputeNetworkMapDelta(last, nm),
+	}
+}
+
+type networkMapCacheEntry struct {
+	networkMap *proto.NetworkMap
+	sentAt     time.Time
+}
+
+// networkMapCache keeps the last network map sent to every peer supporting deltas.
+// Entries expire after networkMapCacheTTL, a peer reconnecting later receives a full network map.
+type networkMapCache struct {
+	mu        sync.Mutex
+	entries   map[string]networkMapCacheEntry
+	lastPrune time.Time
+}
+
+func newNetworkMapCache() *networkMapCache {
+	return &networkMapCache{
+		entries:   make(map[string]networkMapCacheEntry),
+		lastPrune: time.Now(),
+	}
+}
+
+func (c *networkMapCache) get(peerKey string) *proto.NetworkMap {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[peerKey]
+	if !ok || time.Since(entry.sentAt) > networkMapCacheTTL {
+		return nil
+	}
+	return entry.networkMap
+}
+
+func (c *networkMapCache) put(peerKey string, nm *proto.NetworkMap) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.entries[peerKey] = networkMapCacheEntry{networkMap: nm, sentAt: now}
+
+	if now.Sub(c.lastPrune) < networkMapCacheTTL {
+		return
+	}
+	c.lastPrune = now
+
+	for key, entry := range c.entries {
+		if now.Sub(entry.sentAt) > networkMapCacheTTL {
+			delete(c.entries, key)
+		}
+	}
+}
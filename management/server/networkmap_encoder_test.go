@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/proto"
+)
+
+func TestNetworkMapEncoder(t *testing.T) {
+	syncResponse := func(serial uint64, peers ...string) *proto.SyncResponse {
+		nm := &proto.NetworkMap{Serial: serial, PeerConfig: &proto.PeerConfig{Address: "100.64.0.1/16"}}
+		for _, peer := range peers {
+			nm.RemotePeers = append(nm.RemotePeers, &proto.RemotePeerConfig{WgPubKey: peer})
+		}
+		nm.RemotePeersIsEmpty = len(nm.RemotePeers) == 0
+		nm.FirewallRulesIsEmpty = true
+		nm.RoutesFirewallRulesIsEmpty = true
+		return &proto.SyncResponse{NetworkMap: nm}
+	}
+
+	t.Run("deltas not supported", func(t *testing.T) {
+		encoder := newNetworkMapEncoder(nil, "peer1", false, 0)
+		encoder.encode(syncResponse(1, "peer2"))
+
+		resp := encoder.encode(syncResponse(2, "peer2", "peer3"))
+		assert.NotNil(t, resp.GetNetworkMap())
+		assert.Nil(t, resp.GetNetworkMapDelta())
+	})
+
+	t.Run("deltas supported", func(t *testing.T) {
+		encoder := newNetworkMapEncoder(nil, "peer1", true, 0)
+
+		first := encoder.encode(syncResponse(1, "peer2"))
+		require.NotNil(t, first.GetNetworkMap(), "the first network map of a stream must be complete")
+		clientMap := first.GetNetworkMap()
+
+		resp := encoder.encode(syncResponse(2, "peer2", "peer3"))
+		require.Nil(t, resp.GetNetworkMap())
+		require.NotNil(t, resp.GetNetworkMapDelta())
+		assert.Equal(t, uint64(1), resp.GetNetworkMapDelta().GetBaseSerial())
+		assert.Len(t, resp.GetNetworkMapDelta().GetUpsertedRemotePeers(), 1)
+
+		clientMap, err := proto.ApplyNetworkMapDelta(clientMap, resp.GetNetworkMapDelta())
+		require.NoError(t, err)
+
+		outdated := encoder.encode(syncResponse(1))
+		require.NotNil(t, outdated.GetNetworkMap(), "outdated network maps are sent as they are")
+		clientMap = outdated.GetNetworkMap()
+
+		resp = encoder.encode(syncResponse(3, "peer3"))
+		require.NotNil(t, resp.GetNetworkMapDelta())
+		assert.Equal(t, uint64(1), resp.GetNetworkMapDelta().GetBaseSerial())
+		clientMap, err = proto.ApplyNetworkMapDelta(clientMap, resp.GetNetworkMapDelta())
+		require.NoError(t, err)
+		assert.Len(t, clientMap.GetRemotePeers(), 1)
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		cache := newNetworkMapCache()
+		encoder := newNetworkMapEncoder(cache, "peer1", true, 0)
+		clientMap := encoder.encode(syncResponse(1, "peer2")).GetNetworkMap()
+		require.NotNil(t, clientMap)
+
+		// the client acknowledges the network map it holds, the first response of the new stream is a delta
+		encoder = newNetworkMapEncoder(cache, "peer1", true, clientMap.GetSerial())
+		resp := encoder.encode(syncResponse(2, "peer2", "peer3"))
+		require.NotNil(t, resp.GetNetworkMapDelta())
+		clientMap, err := proto.ApplyNetworkMapDelta(clientMap, resp.GetNetworkMapDelta())
+		require.NoError(t, err)
+		assert.Len(t, clientMap.GetRemotePeers(), 2)
+
+		// the client didn't get the last network map sent
+		encoder = newNetworkMapEncoder(cache, "peer1", true, 1)
+		resp = encoder.encode(syncResponse(3, "peer3"))
+		assert.NotNil(t, resp.GetNetworkMap(), "a mismatching serial must get a full network map")
+
+		// the client has no network map
+		encoder = newNetworkMapEncoder(cache, "peer1", true, 0)
+		resp = encoder.encode(syncResponse(3, "peer3"))
+		assert.NotNil(t, resp.GetNetworkMap())
+
+		// another peer never got a network map from this management instance
+		encoder = newNetworkMapEncoder(cache, "peer2", true, 3)
+		resp = encoder.encode(syncResponse(3, "peer1"))
+		assert.NotNil(t, resp.GetNetworkMap())
+	})
+
+	t.Run("expired cache entry", func(t *testing.T) {
+		cache := newNetworkMapCache()
+		cache.put("peer1", syncResponse(1).GetNetworkMap())
+		cache.entries["peer1"] = networkMapCacheEntry{
+			networkMap: cache.entries["peer1"].networkMap,
+			sentAt:     time.Now().Add(-networkMapCacheTTL - time.Second),
+		}
+		assert.Nil(t, cache.get("peer1"))
+
+		cache.lastPrune = time.Now().Add(-networkMapCacheTTL - time.Second)
+		cache.put("peer2", syncResponse(1).GetNetworkMap())
+		assert.NotContains(t, cache.entries, "peer1", "expired entries should be pruned")
+		assert.Contains(t, cache.entries, "peer2")
+	})
+
+	t.Run("updates without network map", func(t *testing.T) {
+		encoder := newNetworkMapEncoder(nil, "peer1", true, 0)
+		update := &proto.SyncResponse{NetbirdConfig: &proto.NetbirdConfig{}}
+		assert.Same(t, update, encoder.encode(update))
+	})
+}
@@ -68,7 +68,10 @@ func (m *managerImpl) CreateNetwork(ctx context.Context, userID string, network
 
 	network.ID = xid.New().String()
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, network.AccountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, network.AccountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	err = m.store.SaveNetwork(ctx, store.LockingStrengthUpdate, network)
@@ -102,7 +105,10 @@ func (m *managerImpl) UpdateNetwork(ctx context.Context, userID string, network
 		return nil, status.NewPermissionDeniedError()
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, network.AccountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, network.AccountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	_, err = m.store.GetNetworkByID(ctx, store.LockingStrengthUpdate, network.AccountID, network.ID)
@@ -129,7 +135,10 @@ func (m *managerImpl) DeleteNetwork(ctx context.Context, accountID, userID, netw
 		return fmt.Errorf("failed to get network: %w", err)
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var eventsToStore []func()
@@ -101,12 +101,19 @@ func (m *managerImpl) CreateResource(ctx context.Context, userID string, resourc
 		return nil, status.NewPermissionDeniedError()
 	}
 
-	resource, err = types.NewNetworkResource(resource.AccountID, resource.NetworkID, resource.Name, resource.Description, resource.Address, resource.GroupIDs, resource.Enabled)
+	if err = types.ValidateServices(resource.Services); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid services: %s", err)
+	}
+
+	resource, err = types.NewNetworkResource(resource.AccountID, resource.NetworkID, resource.Name, resource.Description, resource.Address, resource.GroupIDs, resource.Enabled, resource.Services)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new network resource: %w", err)
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, resource.AccountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, resource.AccountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	var eventsToStore []func()
@@ -202,7 +209,14 @@ func (m *managerImpl) UpdateResource(ctx context.Context, userID string, resourc
 	resource.Domain = domain
 	resource.Prefix = prefix
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, resource.AccountID)
+	if err = types.ValidateServices(resource.Services); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid services: %s", err)
+	}
+
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, resource.AccountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	var eventsToStore []func()
@@ -313,7 +327,10 @@ func (m *managerImpl) DeleteResource(ctx context.Context, accountID, userID, net
 		return status.NewPermissionDeniedError()
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var events []func()
@@ -3,8 +3,10 @@ package types
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/netip"
 	"regexp"
+	"slices"
 
 	"github.com/rs/xid"
 
@@ -29,6 +31,59 @@ func (p NetworkResourceType) String() string {
 	return string(p)
 }
 
+type ServiceProtocol string
+
+const (
+	ServiceProtocolAll  ServiceProtocol = "all"
+	ServiceProtocolTCP  ServiceProtocol = "tcp"
+	ServiceProtocolUDP  ServiceProtocol = "udp"
+	ServiceProtocolICMP ServiceProtocol = "icmp"
+)
+
+// ServicePortRange is an inclusive range of ports exposed by a service.
+type ServicePortRange struct {
+	Start uint16
+	End   uint16
+}
+
+// NetworkResourceService describes a protocol and the ports a network resource exposes.
+// An empty PortRanges list exposes all ports of the protocol.
+type NetworkResourceService struct {
+	Protocol   ServiceProtocol
+	PortRanges []ServicePortRange
+}
+
+// Validate checks that the service has a known protocol and sane port ranges.
+func (s NetworkResourceService) Validate() error {
+	switch s.Protocol {
+	case ServiceProtocolTCP, ServiceProtocolUDP:
+	case ServiceProtocolAll, ServiceProtocolICMP:
+		if len(s.PortRanges) > 0 {
+			return fmt.Errorf("port ranges are not supported for protocol %s", s.Protocol)
+		}
+	default:
+		return fmt.Errorf("invalid protocol %q", s.Protocol)
+	}
+
+	for _, portRange := range s.PortRanges {
+		if portRange.Start == 0 || portRange.End == 0 || portRange.Start > portRange.End {
+			return fmt.Errorf("invalid port range %d-%d", portRange.Start, portRange.End)
+		}
+	}
+
+	return nil
+}
+
+// ValidateServices validates the services of a network resource.
+func ValidateServices(services []NetworkResourceService) error {
+	for _, service := range services {
+		if err := service.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type NetworkResource struct {
 	ID          string `gorm:"index"`
 	NetworkID   string `gorm:"index"`
@@ -41,14 +96,19 @@ type NetworkResource struct {
 	Domain      string
 	Prefix      netip.Prefix `gorm:"serializer:json"`
 	Enabled     bool
+	Services    []NetworkResourceService `gorm:"serializer:json"`
 }
 
-func NewNetworkResource(accountID, networkID, name, description, address string, groupIDs []string, enabled bool) (*NetworkResource, error) {
+func NewNetworkResource(accountID, networkID, name, description, address string, groupIDs []string, enabled bool, services []NetworkResourceService) (*NetworkResource, error) {
 	resourceType, domain, prefix, err := GetResourceType(address)
 	if err != nil {
 		return nil, fmt.Errorf("invalid address: %w", err)
 	}
 
+	if err = ValidateServices(services); err != nil {
+		return nil, fmt.Errorf("invalid services: %w", err)
+	}
+
 	return &NetworkResource{
 		ID:          xid.New().String(),
 		AccountID:   accountID,
@@ -61,6 +121,7 @@ func NewNetworkResource(accountID, networkID, name, description, address string,
 		Prefix:      prefix,
 		GroupIDs:    groupIDs,
 		Enabled:     enabled,
+		Services:    services,
 	}, nil
 }
 
@@ -70,7 +131,7 @@ func (n *NetworkResource) ToAPIResponse(groups []api.GroupMinimum) *api.NetworkR
 		addr = n.Domain
 	}
 
-	return &api.NetworkResource{
+	resp := &api.NetworkResource{
 		Id:          n.ID,
 		Name:        n.Name,
 		Description: &n.Description,
@@ -79,6 +140,16 @@ func (n *NetworkResource) ToAPIResponse(groups []api.GroupMinimum) *api.NetworkR
 		Groups:      groups,
 		Enabled:     n.Enabled,
 	}
+
+	if len(n.Services) > 0 {
+		services := make([]api.NetworkResourceService, 0, len(n.Services))
+		for _, service := range n.Services {
+			services = append(services, service.toAPIResponse())
+		}
+		resp.Services = &services
+	}
+
+	return resp
 }
 
 func (n *NetworkResource) FromAPIRequest(req *api.NetworkResourceRequest) {
@@ -90,6 +161,13 @@ func (n *NetworkResource) FromAPIRequest(req *api.NetworkResourceRequest) {
 	n.Address = req.Address
 	n.GroupIDs = req.Groups
 	n.Enabled = req.Enabled
+
+	n.Services = nil
+	if req.Services != nil {
+		for _, service := range *req.Services {
+			n.Services = append(n.Services, serviceFromAPIRequest(service))
+		}
+	}
 }
 
 func (n *NetworkResource) Copy() *NetworkResource {
@@ -105,6 +183,7 @@ func (n *NetworkResource) Copy() *NetworkResource {
 		Prefix:      n.Prefix,
 		GroupIDs:    n.GroupIDs,
 		Enabled:     n.Enabled,
+		Services:    copyServices(n.Services),
 	}
 }
 
@@ -149,6 +228,65 @@ func (n *NetworkResource) ToRoute(peer *nbpeer.Peer, router *routerTypes.Network
 	return r
 }
 
+func (s NetworkResourceService) toAPIResponse() api.NetworkResourceService {
+	service := api.NetworkResourceService{
+		Protocol: api.NetworkResourceServiceProtocol(s.Protocol),
+	}
+
+	if len(s.PortRanges) > 0 {
+		portRanges := make([]api.RulePortRange, 0, len(s.PortRanges))
+		for _, portRange := range s.PortRanges {
+			portRanges = append(portRanges, api.RulePortRange{
+				Start: int(portRange.Start),
+				End:   int(portRange.End),
+			})
+		}
+		service.PortRanges = &portRanges
+	}
+
+	return service
+}
+
+func serviceFromAPIRequest(req api.NetworkResourceService) NetworkResourceService {
+	service := NetworkResourceService{
+		Protocol: ServiceProtocol(req.Protocol),
+	}
+
+	if req.PortRanges != nil {
+		for _, portRange := range *req.PortRanges {
+			service.PortRanges = append(service.PortRanges, ServicePortRange{
+				Start: toPort(portRange.Start),
+				End:   toPort(portRange.End),
+			})
+		}
+	}
+
+	return service
+}
+
+// toPort converts an API port to uint16, mapping out of range values to 0 so they fail validation.
+func toPort(port int) uint16 {
+	if port < 0 || port > math.MaxUint16 {
+		return 0
+	}
+	return uint16(port)
+}
+
+func copyServices(services []NetworkResourceService) []NetworkResourceService {
+	if services == nil {
+		return nil
+	}
+
+	servicesCopy := make([]NetworkResourceService, 0, len(services))
+	for _, service := range services {
+		servicesCopy = append(servicesCopy, NetworkResourceService{
+			Protocol:   service.Protocol,
+			PortRanges: slices.Clone(service.PortRanges),
+		})
+	}
+	return servicesCopy
+}
+
 func (n *NetworkResource) EventMeta(network *networkTypes.Network) map[string]any {
 	return map[string]any{"name": n.Name, "type": n.Type, "network_name": network.Name, "network_id": network.ID}
 }
@@ -167,7 +305,10 @@ func GetResourceType(address string) (NetworkResourceType, string, netip.Prefix,
 	}
 
 	domainRegex := regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}$`)
-	if domainRegex.MatchString(address) {
+	if nbDomain.Domain(address).IsRegex() || domainRegex.MatchString(address) {
+		if _, err := nbDomain.ValidateRouteDomain(address); err != nil {
+			return "", "", netip.Prefix{}, err
+		}
 		return Domain, address, netip.Prefix{}, nil
 	}
 
@@ -23,7 +23,10 @@ func TestGetResourceType(t *testing.T) {
 		{"example.com", Domain, false, "example.com", netip.Prefix{}},
 		{"*.example.com", Domain, false, "*.example.com", netip.Prefix{}},
 		{"sub.example.com", Domain, false, "sub.example.com", netip.Prefix{}},
+		{`~^api-[0-9]+\.example\.com$`, Domain, false, `~^api-[0-9]+\.example\.com$`, netip.Prefix{}},
 		// Invalid inputs
+		{"*.com", "", true, "", netip.Prefix{}},
+		{`~.*\.com`, "", true, "", netip.Prefix{}},
 		{"invalid", "", true, "", netip.Prefix{}},
 		{"1.1.1.1/abc", "", true, "", netip.Prefix{}},
 		{"1234", "", true, "", netip.Prefix{}},
@@ -51,3 +54,32 @@ func TestGetResourceType(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkResourceServiceValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		service     NetworkResourceService
+		expectedErr bool
+	}{
+		{"tcp all ports", NetworkResourceService{Protocol: ServiceProtocolTCP}, false},
+		{"udp port range", NetworkResourceService{Protocol: ServiceProtocolUDP, PortRanges: []ServicePortRange{{Start: 53, End: 53}}}, false},
+		{"icmp", NetworkResourceService{Protocol: ServiceProtocolICMP}, false},
+		{"icmp with ports", NetworkResourceService{Protocol: ServiceProtocolICMP, PortRanges: []ServicePortRange{{Start: 1, End: 2}}}, true},
+		{"all with ports", NetworkResourceService{Protocol: ServiceProtocolAll, PortRanges: []ServicePortRange{{Start: 1, End: 2}}}, true},
+		{"unknown protocol", NetworkResourceService{Protocol: "sctp"}, true},
+		{"zero port", NetworkResourceService{Protocol: ServiceProtocolTCP, PortRanges: []ServicePortRange{{Start: 0, End: 80}}}, true},
+		{"inverted range", NetworkResourceService{Protocol: ServiceProtocolTCP, PortRanges: []ServicePortRange{{Start: 443, End: 80}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.service.Validate()
+			if tt.expectedErr && err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+			if !tt.expectedErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
@@ -86,7 +86,10 @@ func (m *managerImpl) CreateRouter(ctx context.Context, userID string, router *t
 		return nil, status.NewPermissionDeniedError()
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, router.AccountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, router.AccountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	var network *networkTypes.Network
@@ -155,7 +158,10 @@ func (m *managerImpl) UpdateRouter(ctx context.Context, userID string, router *t
 		return nil, status.NewPermissionDeniedError()
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, router.AccountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, router.AccountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	var network *networkTypes.Network
@@ -201,7 +207,10 @@ func (m *managerImpl) DeleteRouter(ctx context.Context, accountID, userID, netwo
 		return status.NewPermissionDeniedError()
 	}
 
-	unlock := m.store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := m.store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	var event func()
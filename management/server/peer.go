@@ -18,6 +18,7 @@ import (
 	"github.com/netbirdio/netbird/management/domain"
 	"github.com/netbirdio/netbird/management/server/geolocation"
 
+	"github.com/netbirdio/netbird/management/server/ha"
 	"github.com/netbirdio/netbird/management/server/idp"
 	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -92,6 +93,46 @@ func (am *DefaultAccountManager) GetPeers(ctx context.Context, accountID, userID
 	return maps.Values(peersMap), nil
 }
 
+// GetPeersPage returns a page of the peers visible to the user that match the filter and the cursor of the next page
+func (am *DefaultAccountManager) GetPeersPage(ctx context.Context, accountID, userID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error) {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, "", err
+	}
+
+	if user.IsRegularUser() {
+		// regular users see their own peers and the peers those can connect to, which is resolved from the account policies
+		visiblePeers, err := am.GetPeers(ctx, accountID, userID, "", "")
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(visiblePeers) == 0 {
+			return []*nbpeer.Peer{}, "", nil
+		}
+
+		filter.IDs = make([]string, 0, len(visiblePeers))
+		for _, peer := range visiblePeers {
+			filter.IDs = append(filter.IDs, peer.ID)
+		}
+	}
+
+	if filter.ApprovalRequired != nil {
+		// the approval required flag of the listed peers is set by the integrated validator, so is the filter
+		validatedPeers, err := am.GetValidatedPeers(ctx, accountID)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.ApprovedIDs = maps.Keys(validatedPeers)
+	}
+
+	return am.Store.GetAccountPeersPage(ctx, store.LockingStrengthShare, accountID, filter, page)
+}
+
 // MarkPeerConnected marks peer as connected (true) or disconnected (false)
 func (am *DefaultAccountManager) MarkPeerConnected(ctx context.Context, peerPubKey string, connected bool, realIP net.IP, accountID string) error {
 	start := time.Now()
@@ -102,6 +143,7 @@ func (am *DefaultAccountManager) MarkPeerConnected(ctx context.Context, peerPubK
 	var peer *nbpeer.Peer
 	var settings *types.Settings
 	var expired bool
+	var dynamicGroupsChanged bool
 	var err error
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
@@ -110,13 +152,15 @@ func (am *DefaultAccountManager) MarkPeerConnected(ctx context.Context, peerPubK
 			return err
 		}
 
-		expired, err = updatePeerStatusAndLocation(ctx, am.geo, transaction, peer, connected, realIP, accountID)
+		expired, dynamicGroupsChanged, err = updatePeerStatusAndLocation(ctx, am.geo, transaction, peer, connected, realIP, accountID)
 		return err
 	})
 	if err != nil {
 		return err
 	}
 
+	am.publishPeerStatusEvent(ctx, accountID, peer, connected)
+
 	if peer.AddedWithSSOLogin() {
 		settings, err = am.Store.GetAccountSettings(ctx, store.LockingStrengthShare, accountID)
 		if err != nil {
@@ -132,7 +176,7 @@ func (am *DefaultAccountManager) MarkPeerConnected(ctx context.Context, peerPubK
 		}
 	}
 
-	if expired {
+	if expired || dynamicGroupsChanged {
 		// we need to update other peers because when peer login expires all other peers are notified to disconnect from
 		// the expired one. Here we notify them that connection is now allowed again.
 		am.UpdateAccountPeers(ctx, accountID)
@@ -141,7 +185,9 @@ func (am *DefaultAccountManager) MarkPeerConnected(ctx context.Context, peerPubK
 	return nil
 }
 
-func updatePeerStatusAndLocation(ctx context.Context, geo geolocation.Geolocation, transaction store.Store, peer *nbpeer.Peer, connected bool, realIP net.IP, accountID string) (bool, error) {
+// updatePeerStatusAndLocation saves the peer status and location. It returns whether the peer login was expired
+// and whether a location change moved the peer between dynamic groups affecting the account peers.
+func updatePeerStatusAndLocation(ctx context.Context, geo geolocation.Geolocation, transaction store.Store, peer *nbpeer.Peer, connected bool, realIP net.IP, accountID string) (bool, bool, error) {
 	oldStatus := peer.Status.Copy()
 	newStatus := oldStatus
 	newStatus.LastSeen = time.Now().UTC()
@@ -152,11 +198,13 @@ func updatePeerStatusAndLocation(ctx context.Context, geo geolocation.Geolocatio
 	}
 	peer.Status = newStatus
 
+	var dynamicGroupsChanged bool
 	if geo != nil && realIP != nil {
 		location, err := geo.Lookup(realIP)
 		if err != nil {
 			log.WithContext(ctx).Warnf("failed to get location for peer %s realip: [%s]: %v", peer.ID, realIP.String(), err)
 		} else {
+			oldCountryCode := peer.Location.CountryCode
 			peer.Location.ConnectionIP = realIP
 			peer.Location.CountryCode = location.Country.ISOCode
 			peer.Location.CityName = location.City.Names.En
@@ -164,6 +212,11 @@ func updatePeerStatusAndLocation(ctx context.Context, geo geolocation.Geolocatio
 			err = transaction.SavePeerLocation(ctx, store.LockingStrengthUpdate, accountID, peer)
 			if err != nil {
 				log.WithContext(ctx).Warnf("could not store location for peer %s: %s", peer.ID, err)
+			} else if oldCountryCode != peer.Location.CountryCode {
+				dynamicGroupsChanged, err = updatePeerDynamicGroupsAndSerial(ctx, transaction, accountID, peer)
+				if err != nil {
+					return false, false, err
+				}
 			}
 		}
 	}
@@ -172,15 +225,18 @@ func updatePeerStatusAndLocation(ctx context.Context, geo geolocation.Geolocatio
 
 	err := transaction.SavePeerStatus(ctx, store.LockingStrengthUpdate, accountID, peer.ID, *newStatus)
 	if err != nil {
-		return false, err
+		return false, false, err
 	}
 
-	return oldStatus.LoginExpired, nil
+	return oldStatus.LoginExpired, dynamicGroupsChanged, nil
 }
 
 // UpdatePeer updates peer. Only Peer.Name, Peer.SSHEnabled, Peer.LoginExpirationEnabled and Peer.InactivityExpirationEnabled can be updated.
 func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, userID string, update *nbpeer.Peer) (*nbpeer.Peer, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
@@ -312,7 +368,10 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 
 // DeletePeer removes peer from the account by its IP
 func (am *DefaultAccountManager) DeletePeer(ctx context.Context, accountID, peerID, userID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	if userID != activity.SystemInitiator {
@@ -389,6 +448,329 @@ func (am *DefaultAccountManager) DeletePeer(ctx context.Context, accountID, peer
 	return nil
 }
 
+// bulkPeerChange collects the outcome of a bulk peer operation applied in a transaction
+type bulkPeerChange struct {
+	failed                  map[string]error
+	eventsToStore           []func()
+	updateAccountPeers      bool
+	incrementNetworkSerial  bool
+	scheduleLoginExpiration bool
+}
+
+// BulkUpdatePeers applies the operation to all the given peers in a single transaction followed by a single network map update.
+// Peers the action can't be applied to are reported in the results without failing the rest of the operation.
+func (am *DefaultAccountManager) BulkUpdatePeers(ctx context.Context, accountID, userID string, op *types.PeerBulkOperation) ([]*types.BulkItemResult, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, err
+	}
+
+	if user.IsRegularUser() {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	change := &bulkPeerChange{failed: make(map[string]error)}
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		peersMap, err := transaction.GetPeersByIDs(ctx, store.LockingStrengthUpdate, accountID, op.PeerIDs)
+		if err != nil {
+			return err
+		}
+
+		peers := make([]*nbpeer.Peer, 0, len(peersMap))
+		for _, peerID := range op.PeerIDs {
+			peer, ok := peersMap[peerID]
+			if !ok {
+				change.failed[peerID] = status.NewPeerNotFoundError(peerID)
+				continue
+			}
+			peers = append(peers, peer)
+		}
+
+		switch op.Action {
+		case types.PeerBulkActionDelete:
+			err = am.bulkDeletePeers(ctx, transaction, accountID, userID, peers, change)
+		case types.PeerBulkActionApprove:
+			err = am.bulkApprovePeers(ctx, transaction, accountID, userID, op.Reason, peers, change)
+		case types.PeerBulkActionAssignGroups, types.PeerBulkActionUnassignGroups:
+			err = am.bulkUpdatePeersGroups(ctx, transaction, accountID, userID, op, peers, change)
+		case types.PeerBulkActionSetLoginExpiration:
+			err = am.bulkSetPeersLoginExpiration(ctx, transaction, accountID, userID, op.Enabled, peers, change)
+		case types.PeerBulkActionSetSSH:
+			err = am.bulkSetPeersSSH(ctx, transaction, accountID, userID, op.Enabled, peers, change)
+		}
+		if err != nil {
+			return err
+		}
+
+		if change.incrementNetworkSerial {
+			return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storeEvent := range change.eventsToStore {
+		storeEvent()
+	}
+
+	if change.scheduleLoginExpiration {
+		am.checkAndSchedulePeerLoginExpiration(ctx, accountID)
+	}
+
+	if change.updateAccountPeers {
+		am.UpdateAccountPeers(ctx, accountID)
+	}
+
+	results := make([]*types.BulkItemResult, 0, len(op.PeerIDs))
+	for _, peerID := range op.PeerIDs {
+		results = append(results, &types.BulkItemResult{ID: peerID, Err: change.failed[peerID]})
+	}
+
+	return results, nil
+}
+
+func (am *DefaultAccountManager) bulkDeletePeers(ctx context.Context, transaction store.Store, accountID, userID string, peers []*nbpeer.Peer, change *bulkPeerChange) error {
+	deletedPeers := make([]*nbpeer.Peer, 0, len(peers))
+	deletedPeerIDs := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if err := am.validatePeerDelete(ctx, accountID, peer.ID); err != nil {
+			change.failed[peer.ID] = err
+			continue
+		}
+
+		inActiveGroup, err := isPeerInActiveGroup(ctx, transaction, accountID, peer.ID)
+		if err != nil {
+			return err
+		}
+		change.updateAccountPeers = change.updateAccountPeers || inActiveGroup
+
+		deletedPeers = append(deletedPeers, peer)
+		deletedPeerIDs = append(deletedPeerIDs, peer.ID)
+	}
+
+	if len(deletedPeers) == 0 {
+		return nil
+	}
+
+	groups, err := transaction.GetAccountGroups(ctx, store.LockingStrengthUpdate, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account groups: %w", err)
+	}
+
+	var changedGroups []*types.Group
+	for _, group := range groups {
+		if !slices.ContainsFunc(group.Peers, func(peerID string) bool { return slices.Contains(deletedPeerIDs, peerID) }) {
+			continue
+		}
+		group.Peers = slices.DeleteFunc(group.Peers, func(peerID string) bool { return slices.Contains(deletedPeerIDs, peerID) })
+		changedGroups = append(changedGroups, group)
+	}
+
+	if len(changedGroups) > 0 {
+		if err = transaction.SaveGroups(ctx, store.LockingStrengthUpdate, changedGroups); err != nil {
+			return fmt.Errorf("failed to save groups: %w", err)
+		}
+	}
+
+	events, err := deletePeers(ctx, am, transaction, accountID, userID, deletedPeers)
+	if err != nil {
+		return err
+	}
+
+	change.eventsToStore = append(change.eventsToStore, events...)
+	change.incrementNetworkSerial = true
+
+	return nil
+}
+
+func (am *DefaultAccountManager) bulkApprovePeers(ctx context.Context, transaction store.Store, accountID, userID, reason string, peers []*nbpeer.Peer, change *bulkPeerChange) error {
+	settings, err := transaction.GetAccountSettings(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if !peer.Status.RequiresApproval {
+			continue
+		}
+
+		if err = am.approvePendingPeer(ctx, transaction, accountID, userID, peer, settings); err != nil {
+			change.failed[peer.ID] = err
+			continue
+		}
+
+		eventMeta, err := reviewPeerApprovalRequest(ctx, transaction, accountID, userID, peer, types.PeerApprovalStatusApproved, reason)
+		if err != nil {
+			return err
+		}
+
+		change.eventsToStore = append(change.eventsToStore, func() {
+			am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerApproved, eventMeta)
+		})
+		change.updateAccountPeers = true
+		change.incrementNetworkSerial = true
+	}
+
+	return nil
+}
+
+func (am *DefaultAccountManager) bulkUpdatePeersGroups(ctx context.Context, transaction store.Store, accountID, userID string, op *types.PeerBulkOperation, peers []*nbpeer.Peer, change *bulkPeerChange) error {
+	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthUpdate, accountID, op.GroupIDs)
+	if err != nil {
+		return err
+	}
+
+	eventType := activity.GroupAddedToPeer
+	if op.Action == types.PeerBulkActionUnassignGroups {
+		eventType = activity.GroupRemovedFromPeer
+	}
+
+	var changedGroups []*types.Group
+	var changedGroupIDs []string
+	for _, groupID := range op.GroupIDs {
+		group, ok := groups[groupID]
+		if !ok {
+			return status.Errorf(status.NotFound, "group not found: %s", groupID)
+		}
+
+		if group.IsGroupAll() {
+			return status.Errorf(status.InvalidArgument, "peers of the All group can't be changed")
+		}
+
+		if group.IsDynamic() {
+			return status.Errorf(status.InvalidArgument, "peers of dynamic group %s are defined by its query", group.Name)
+		}
+
+		groupChanged := false
+		for _, peer := range peers {
+			var updated bool
+			if op.Action == types.PeerBulkActionAssignGroups {
+				updated = group.AddPeer(peer.ID)
+			} else {
+				updated = group.RemovePeer(peer.ID)
+			}
+			if !updated {
+				continue
+			}
+
+			groupChanged = true
+			change.eventsToStore = append(change.eventsToStore, func() {
+				meta := map[string]any{
+					"group": group.Name, "group_id": group.ID,
+					"peer_ip": peer.IP.String(), "peer_fqdn": peer.FQDN(am.GetDNSDomain()),
+				}
+				am.StoreEvent(ctx, userID, peer.ID, accountID, eventType, meta)
+			})
+		}
+
+		if groupChanged {
+			changedGroups = append(changedGroups, group)
+			changedGroupIDs = append(changedGroupIDs, group.ID)
+		}
+	}
+
+	if len(changedGroups) == 0 {
+		return nil
+	}
+
+	if err = transaction.SaveGroups(ctx, store.LockingStrengthUpdate, changedGroups); err != nil {
+		return err
+	}
+
+	refreshedGroupIDs, err := refreshDynamicGroups(ctx, transaction, accountID)
+	if err != nil {
+		return err
+	}
+
+	change.updateAccountPeers, err = areGroupChangesAffectPeers(ctx, transaction, accountID, append(changedGroupIDs, refreshedGroupIDs...))
+	if err != nil {
+		return err
+	}
+	change.incrementNetworkSerial = true
+
+	return nil
+}
+
+func (am *DefaultAccountManager) bulkSetPeersLoginExpiration(ctx context.Context, transaction store.Store, accountID, userID string, enabled bool, peers []*nbpeer.Peer, change *bulkPeerChange) error {
+	settings, err := transaction.GetAccountSettings(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return err
+	}
+
+	event := activity.PeerLoginExpirationEnabled
+	if !enabled {
+		event = activity.PeerLoginExpirationDisabled
+	}
+
+	for _, peer := range peers {
+		if !peer.AddedWithSSOLogin() {
+			change.failed[peer.ID] = status.Errorf(status.PreconditionFailed, "this peer hasn't been added with the SSO login, therefore the login expiration can't be updated")
+			continue
+		}
+
+		if peer.LoginExpirationEnabled == enabled {
+			continue
+		}
+
+		peer.LoginExpirationEnabled = enabled
+		if err = transaction.SavePeer(ctx, store.LockingStrengthUpdate, accountID, peer); err != nil {
+			return err
+		}
+
+		change.eventsToStore = append(change.eventsToStore, func() {
+			am.StoreEvent(ctx, userID, peer.IP.String(), accountID, event, peer.EventMeta(am.GetDNSDomain()))
+		})
+		if enabled && settings.PeerLoginExpirationEnabled {
+			change.scheduleLoginExpiration = true
+		}
+	}
+
+	return nil
+}
+
+func (am *DefaultAccountManager) bulkSetPeersSSH(ctx context.Context, transaction store.Store, accountID, userID string, enabled bool, peers []*nbpeer.Peer, change *bulkPeerChange) error {
+	event := activity.PeerSSHEnabled
+	if !enabled {
+		event = activity.PeerSSHDisabled
+	}
+
+	for _, peer := range peers {
+		if peer.SSHEnabled == enabled {
+			continue
+		}
+
+		peer.SSHEnabled = enabled
+		if err := transaction.SavePeer(ctx, store.LockingStrengthUpdate, accountID, peer); err != nil {
+			return err
+		}
+
+		change.eventsToStore = append(change.eventsToStore, func() {
+			am.StoreEvent(ctx, userID, peer.IP.String(), accountID, event, peer.EventMeta(am.GetDNSDomain()))
+		})
+		change.updateAccountPeers = true
+		change.incrementNetworkSerial = true
+	}
+
+	return nil
+}
+
 // GetNetworkMap returns Network map for a given peer (omits original peer from the Peers result)
 func (am *DefaultAccountManager) GetNetworkMap(ctx context.Context, peerID string) (*types.NetworkMap, error) {
 	account, err := am.Store.GetAccountByPeerID(ctx, peerID)
@@ -467,7 +849,10 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 		return nil, nil, nil, status.Errorf(status.NotFound, "failed adding new peer: account not found")
 	}
 
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	defer func() {
 		if unlock != nil {
 			unlock()
@@ -491,6 +876,10 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 
 	var newPeer *nbpeer.Peer
 	var updateAccountPeers bool
+	var approvalRequest *types.PeerApprovalRequest
+	var approvalRule *types.PeerApprovalRule
+	var violatedKey *types.SetupKey
+	var violationMeta map[string]any
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		var setupKeyID string
@@ -528,6 +917,16 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 			if !sk.AllowExtraDNSLabels && len(peer.ExtraDNSLabels) > 0 {
 				return status.Errorf(status.PreconditionFailed, "couldn't add peer: setup key doesn't allow extra DNS labels")
 			}
+
+			reason, err := validateSetupKeyConstraints(ctx, transaction, sk, peer)
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				violatedKey = sk
+				violationMeta = setupKeyViolationMeta(sk, peer, reason)
+				return status.Errorf(status.PermissionDenied, "couldn't add peer: setup key %s doesn't allow this peer: %s", sk.Name, reason)
+			}
 		}
 
 		if (strings.ToLower(peer.Meta.Hostname) == "iphone" || strings.ToLower(peer.Meta.Hostname) == "ipad") && userID != "" {
@@ -544,7 +943,7 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 			return fmt.Errorf("failed to get free DNS label: %w", err)
 		}
 
-		freeIP, err := getFreeIP(ctx, transaction, accountID)
+		freeIP, freeIPv6, err := getFreeIP(ctx, transaction, accountID)
 		if err != nil {
 			return fmt.Errorf("failed to get free IP: %w", err)
 		}
@@ -555,6 +954,7 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 			AccountID:                   accountID,
 			Key:                         peer.Key,
 			IP:                          freeIP,
+			IPv6:                        freeIPv6,
 			Meta:                        peer.Meta,
 			Name:                        peer.Meta.Hostname,
 			DNSLabel:                    freeLabel,
@@ -594,6 +994,11 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 		}
 		newPeer = am.integratedPeerValidator.PreparePeer(ctx, accountID, newPeer, groupsToAdd, settings.Extra)
 
+		approvalRequest, approvalRule, err = am.preparePeerApproval(ctx, transaction, accountID, newPeer, groupsToAdd, settings, setupKeyID, setupKeyName)
+		if err != nil {
+			return fmt.Errorf("failed to prepare peer approval: %w", err)
+		}
+
 		err = transaction.AddPeerToAllGroup(ctx, store.LockingStrengthUpdate, accountID, newPeer.ID)
 		if err != nil {
 			return fmt.Errorf("failed adding peer to All group: %w", err)
@@ -613,6 +1018,10 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 			return fmt.Errorf("failed to add peer to account: %w", err)
 		}
 
+		if _, err = updatePeerDynamicGroups(ctx, transaction, accountID, newPeer); err != nil {
+			return fmt.Errorf("failed to update dynamic groups: %w", err)
+		}
+
 		err = transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
 		if err != nil {
 			return fmt.Errorf("failed to increment network serial: %w", err)
@@ -640,6 +1049,9 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 	})
 
 	if err != nil {
+		if violatedKey != nil {
+			am.StoreEvent(ctx, violatedKey.Id, violatedKey.Id, accountID, activity.SetupKeyConstraintViolated, violationMeta)
+		}
 		return nil, nil, nil, fmt.Errorf("failed to add peer to database: %w", err)
 	}
 
@@ -648,6 +1060,12 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 	}
 
 	am.StoreEvent(ctx, opEvent.InitiatorID, opEvent.TargetID, opEvent.AccountID, opEvent.Activity, opEvent.Meta)
+	if approvalRule != nil {
+		meta := approvalRequest.EventMeta()
+		meta["rule_id"] = approvalRule.ID
+		meta["rule_name"] = approvalRule.Name
+		am.StoreEvent(ctx, activity.SystemInitiator, newPeer.ID, accountID, activity.PeerAutoApproved, meta)
+	}
 
 	unlock()
 	unlock = nil
@@ -659,23 +1077,38 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 	return am.getValidatedPeerWithMap(ctx, false, accountID, newPeer)
 }
 
-func getFreeIP(ctx context.Context, transaction store.Store, accountID string) (net.IP, error) {
+// getFreeIP allocates an IPv4 address and, if the account has an IPv6 overlay network, an IPv6 address for a new peer
+func getFreeIP(ctx context.Context, transaction store.Store, accountID string) (net.IP, net.IP, error) {
 	takenIps, err := transaction.GetTakenIPs(ctx, store.LockingStrengthShare, accountID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get taken IPs: %w", err)
+		return nil, nil, fmt.Errorf("failed to get taken IPs: %w", err)
 	}
 
 	network, err := transaction.GetAccountNetwork(ctx, store.LockingStrengthUpdate, accountID)
 	if err != nil {
-		return nil, fmt.Errorf("failed getting network: %w", err)
+		return nil, nil, fmt.Errorf("failed getting network: %w", err)
 	}
 
 	nextIp, err := types.AllocatePeerIP(network.Net, takenIps)
 	if err != nil {
-		return nil, fmt.Errorf("failed to allocate new peer ip: %w", err)
+		return nil, nil, fmt.Errorf("failed to allocate new peer ip: %w", err)
+	}
+
+	if !network.HasIPv6() {
+		return nextIp, nil, nil
+	}
+
+	takenIPv6s, err := transaction.GetTakenIPv6s(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get taken IPv6s: %w", err)
 	}
 
-	return nextIp, nil
+	nextIPv6, err := types.AllocatePeerIPv6(network.NetV6, takenIPv6s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to allocate new peer ipv6: %w", err)
+	}
+
+	return nextIp, nextIPv6, nil
 }
 
 // SyncPeer checks whether peer is eligible for receiving NetworkMap (authenticated) and returns its NetworkMap if eligible
@@ -689,6 +1122,8 @@ func (am *DefaultAccountManager) SyncPeer(ctx context.Context, sync types.PeerSy
 	var peerNotValid bool
 	var isStatusChanged bool
 	var updated bool
+	var dynamicGroupsChanged bool
+	var rosenpassChanged bool
 	var err error
 	var postureChecks []*posture.Checks
 
@@ -728,6 +1163,9 @@ func (am *DefaultAccountManager) SyncPeer(ctx context.Context, sync types.PeerSy
 			return err
 		}
 
+		// the peers connecting to peers requiring the post-quantum key exchange depend on the Rosenpass flag
+		rosenpassChanged = len(settings.PostQuantumRequiredGroups) > 0 && peer.Meta.Flags.RosenpassEnabled != sync.Meta.Flags.RosenpassEnabled
+
 		updated = peer.UpdateMetaIfNew(sync.Meta)
 		if updated {
 			am.metrics.AccountManagerMetrics().CountPeerMetUpdate()
@@ -740,6 +1178,11 @@ func (am *DefaultAccountManager) SyncPeer(ctx context.Context, sync types.PeerSy
 			if err != nil {
 				return err
 			}
+
+			dynamicGroupsChanged, err = updatePeerDynamicGroupsAndSerial(ctx, transaction, accountID, peer)
+			if err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -747,7 +1190,7 @@ func (am *DefaultAccountManager) SyncPeer(ctx context.Context, sync types.PeerSy
 		return nil, nil, nil, err
 	}
 
-	if isStatusChanged || sync.UpdateAccountPeers || (updated && len(postureChecks) > 0) {
+	if isStatusChanged || sync.UpdateAccountPeers || dynamicGroupsChanged || (updated && (len(postureChecks) > 0 || rosenpassChanged)) {
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
@@ -790,9 +1233,15 @@ func (am *DefaultAccountManager) LoginPeer(ctx context.Context, login types.Peer
 		}
 	}
 
-	unlockAccount := am.Store.AcquireReadLockByUID(ctx, accountID)
+	unlockAccount, err := am.Store.AcquireReadLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	defer unlockAccount()
-	unlockPeer := am.Store.AcquireWriteLockByUID(ctx, login.WireGuardPubKey)
+	unlockPeer, err := am.Store.AcquireWriteLockByUID(ctx, login.WireGuardPubKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	defer func() {
 		if unlockPeer != nil {
 			unlockPeer()
@@ -804,6 +1253,8 @@ func (am *DefaultAccountManager) LoginPeer(ctx context.Context, login types.Peer
 	var isRequiresApproval bool
 	var isStatusChanged bool
 	var isPeerUpdated bool
+	var dynamicGroupsChanged bool
+	var rosenpassChanged bool
 	var postureChecks []*posture.Checks
 
 	settings, err := am.Store.GetAccountSettings(ctx, store.LockingStrengthShare, accountID)
@@ -847,6 +1298,8 @@ func (am *DefaultAccountManager) LoginPeer(ctx context.Context, login types.Peer
 			return err
 		}
 
+		rosenpassChanged = len(settings.PostQuantumRequiredGroups) > 0 && peer.Meta.Flags.RosenpassEnabled != login.Meta.Flags.RosenpassEnabled
+
 		isPeerUpdated = peer.UpdateMetaIfNew(login.Meta)
 		if isPeerUpdated {
 			am.metrics.AccountManagerMetrics().CountPeerMetUpdate()
@@ -856,6 +1309,11 @@ func (am *DefaultAccountManager) LoginPeer(ctx context.Context, login types.Peer
 			if err != nil {
 				return err
 			}
+
+			dynamicGroupsChanged, err = updatePeerDynamicGroupsAndSerial(ctx, transaction, accountID, peer)
+			if err != nil {
+				return err
+			}
 		}
 
 		if peer.SSHKey != login.SSHKey {
@@ -892,13 +1350,30 @@ func (am *DefaultAccountManager) LoginPeer(ctx context.Context, login types.Peer
 	unlockPeer()
 	unlockPeer = nil
 
-	if updateRemotePeers || isStatusChanged || (isPeerUpdated && len(postureChecks) > 0) {
+	if updateRemotePeers || isStatusChanged || dynamicGroupsChanged || (isPeerUpdated && (len(postureChecks) > 0 || rosenpassChanged)) {
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
 	return am.getValidatedPeerWithMap(ctx, isRequiresApproval, accountID, peer)
 }
 
+// updatePeerDynamicGroupsAndSerial re-evaluates the dynamic groups of the peer after its metadata changed.
+// It returns true if the changed groups affect the network map of the account peers.
+func updatePeerDynamicGroupsAndSerial(ctx context.Context, transaction store.Store, accountID string, peer *nbpeer.Peer) (bool, error) {
+	changedGroupIDs, err := updatePeerDynamicGroups(ctx, transaction, accountID, peer)
+	if err != nil || len(changedGroupIDs) == 0 {
+		return false, err
+	}
+
+	log.WithContext(ctx).Debugf("peer %s dynamic groups membership changed: %v", peer.ID, changedGroupIDs)
+
+	if err = transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID); err != nil {
+		return false, err
+	}
+
+	return areGroupChangesAffectPeers(ctx, transaction, accountID, changedGroupIDs)
+}
+
 // getPeerPostureChecks returns the posture checks for the peer.
 func getPeerPostureChecks(ctx context.Context, transaction store.Store, accountID, peerID string) ([]*posture.Checks, error) {
 	policies, err := transaction.GetAccountPolicies(ctx, store.LockingStrengthShare, accountID)
@@ -1151,9 +1626,15 @@ func (am *DefaultAccountManager) GetPeer(ctx context.Context, accountID, peerID,
 	return nil, status.Errorf(status.Internal, "user %s has no access to peer %s under account %s", userID, peerID, accountID)
 }
 
-// UpdateAccountPeers updates all peers that belong to an account.
+// UpdateAccountPeers updates all peers that belong to an account, including the peers connected to the other management instances.
 // Should be called when changes have to be synced to peers.
 func (am *DefaultAccountManager) UpdateAccountPeers(ctx context.Context, accountID string) {
+	am.updateLocalAccountPeers(ctx, accountID)
+	am.publishInstanceEvent(ctx, &ha.Event{Type: ha.AccountPeersUpdated, AccountID: accountID})
+}
+
+// updateLocalAccountPeers updates the peers of an account connected to this management instance
+func (am *DefaultAccountManager) updateLocalAccountPeers(ctx context.Context, accountID string) {
 	account, err := am.requestBuffer.GetAccountWithBackpressure(ctx, accountID)
 	if err != nil {
 		log.WithContext(ctx).Errorf("failed to send out updates to peers. failed to get account: %v", err)
@@ -1228,7 +1709,19 @@ func (am *DefaultAccountManager) UpdateAccountPeers(ctx context.Context, account
 
 // UpdateAccountPeer updates a single peer that belongs to an account.
 // Should be called when changes need to be synced to a specific peer only.
+// Peers connected to another management instance are updated by that instance.
 func (am *DefaultAccountManager) UpdateAccountPeer(ctx context.Context, accountId string, peerId string) {
+	if !am.peersUpdateManager.HasChannel(peerId) {
+		log.WithContext(ctx).Tracef("peer %s doesn't have a channel, asking the other management instances to update it", peerId)
+		am.publishInstanceEvent(ctx, &ha.Event{Type: ha.PeerUpdated, AccountID: accountId, PeerIDs: []string{peerId}})
+		return
+	}
+
+	am.updateLocalAccountPeer(ctx, accountId, peerId)
+}
+
+// updateLocalAccountPeer updates a single peer connected to this management instance
+func (am *DefaultAccountManager) updateLocalAccountPeer(ctx context.Context, accountId string, peerId string) {
 	if !am.peersUpdateManager.HasChannel(peerId) {
 		log.WithContext(ctx).Tracef("peer %s doesn't have a channel, skipping network map update", peerId)
 		return
@@ -1463,9 +1956,10 @@ func isPeerInActiveGroup(ctx context.Context, transaction store.Store, accountID
 }
 
 // deletePeers deletes all specified peers and sends updates to the remote peers.
-// Returns a slice of functions to save events after successful peer deletion.
+// Returns a slice of functions to save events and disconnect the peers from the other management instances after successful peer deletion.
 func deletePeers(ctx context.Context, am *DefaultAccountManager, transaction store.Store, accountID, userID string, peers []*nbpeer.Peer) ([]func(), error) {
 	var peerDeletedEvents []func()
+	peerIDs := make([]string, 0, len(peers))
 
 	for _, peer := range peers {
 		if err := am.integratedPeerValidator.PeerDeleted(ctx, accountID, peer.ID); err != nil {
@@ -1499,6 +1993,18 @@ func deletePeers(ctx context.Context, am *DefaultAccountManager, transaction sto
 		peerDeletedEvents = append(peerDeletedEvents, func() {
 			am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerRemovedByUser, peer.EventMeta(am.GetDNSDomain()))
 		})
+		peerIDs = append(peerIDs, peer.ID)
+	}
+
+	if len(peerIDs) > 0 {
+		if err := transaction.DeletePendingPeerApprovalRequests(ctx, store.LockingStrengthUpdate, accountID, peerIDs); err != nil {
+			return nil, err
+		}
+
+		// peers connected to the other management instances are disconnected once the deletion is committed
+		peerDeletedEvents = append(peerDeletedEvents, func() {
+			am.publishInstanceEvent(ctx, &ha.Event{Type: ha.PeersDisconnected, AccountID: accountID, PeerIDs: peerIDs})
+		})
 	}
 
 	return peerDeletedEvents, nil
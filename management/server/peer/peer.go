@@ -21,6 +21,8 @@ type Peer struct {
 	Key string `gorm:"index"`
 	// IP address of the Peer
 	IP net.IP `gorm:"serializer:json"`
+	// IPv6 is the address of the Peer in the account's IPv6 overlay network, empty if the account has none
+	IPv6 net.IP `gorm:"serializer:json"`
 	// Meta is a Peer system meta data
 	Meta PeerSystemMeta `gorm:"embedded;embeddedPrefix:meta_"`
 	// Name is peer's name (machine name)
@@ -94,6 +96,30 @@ type File struct {
 	ProcessIsRunning bool
 }
 
+// Capability is a feature supported by the peer's client, the values match proto.PeerCapability
+type Capability int32
+
+const (
+	// CapabilityIPv6Overlay indicates that the client configures the IPv6 overlay address and filters IPv6 traffic
+	CapabilityIPv6Overlay Capability = 1
+	// CapabilityNetworkMapDelta indicates that the client applies network map deltas instead of full network maps
+	CapabilityNetworkMapDelta Capability = 2
+	// CapabilityRosenpass indicates that the client supports the Rosenpass post-quantum key exchange and refuses
+	// connections without it when the network map requires it
+	CapabilityRosenpass Capability = 3
+)
+
+// Flags are the client settings reported by the peer
+type Flags struct {
+	RosenpassEnabled    bool
+	RosenpassPermissive bool
+	ServerSSHAllowed    bool
+	DisableClientRoutes bool
+	DisableServerRoutes bool
+	DisableDNS          bool
+	DisableFirewall     bool
+}
+
 // PeerSystemMeta is a metadata of a Peer machine system
 type PeerSystemMeta struct { //nolint:revive
 	Hostname           string
@@ -110,8 +136,15 @@ type PeerSystemMeta struct { //nolint:revive
 	SystemSerialNumber string
 	SystemProductName  string
 	SystemManufacturer string
-	Environment        Environment `gorm:"serializer:json"`
-	Files              []File      `gorm:"serializer:json"`
+	Environment        Environment  `gorm:"serializer:json"`
+	Files              []File       `gorm:"serializer:json"`
+	Capabilities       []Capability `gorm:"serializer:json"`
+	Flags              Flags        `gorm:"serializer:json"`
+}
+
+// HasCapability returns true if the peer's client reported the given capability
+func (p PeerSystemMeta) HasCapability(capability Capability) bool {
+	return slices.Contains(p.Capabilities, capability)
 }
 
 func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
@@ -141,6 +174,14 @@ func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
 		return false
 	}
 
+	if !slices.Equal(p.Capabilities, other.Capabilities) {
+		return false
+	}
+
+	if p.Flags != other.Flags {
+		return false
+	}
+
 	return p.Hostname == other.Hostname &&
 		p.GoOS == other.GoOS &&
 		p.Kernel == other.Kernel &&
@@ -178,6 +219,11 @@ func (p PeerSystemMeta) isEmpty() bool {
 		len(p.Files) == 0
 }
 
+// SupportsIPv6 returns true if the peer has an IPv6 overlay address and its client is able to use it
+func (p *Peer) SupportsIPv6() bool {
+	return len(p.IPv6) > 0 && p.Meta.HasCapability(CapabilityIPv6Overlay)
+}
+
 // AddedWithSSOLogin indicates whether this peer has been added with an SSO login by a user.
 func (p *Peer) AddedWithSSOLogin() bool {
 	return p.UserID != ""
@@ -194,6 +240,7 @@ func (p *Peer) Copy() *Peer {
 		AccountID:                   p.AccountID,
 		Key:                         p.Key,
 		IP:                          p.IP,
+		IPv6:                        p.IPv6,
 		Meta:                        p.Meta,
 		Name:                        p.Name,
 		DNSLabel:                    p.DNSLabel,
@@ -83,3 +83,17 @@ func TestIsEqual(t *testing.T) {
 		t.Error("meta1 should be equal to meta2")
 	}
 }
+
+func TestFlagsChangeUpdatesMeta(t *testing.T) {
+	p := &Peer{Meta: PeerSystemMeta{Hostname: "peer", Flags: Flags{RosenpassEnabled: true, RosenpassPermissive: true}}}
+
+	if p.UpdateMetaIfNew(PeerSystemMeta{Hostname: "peer", Flags: Flags{RosenpassEnabled: true, RosenpassPermissive: true}}) {
+		t.Error("meta with the same flags shouldn't be updated")
+	}
+	if !p.UpdateMetaIfNew(PeerSystemMeta{Hostname: "peer", Flags: Flags{RosenpassEnabled: true}}) {
+		t.Error("meta with changed flags should be updated")
+	}
+	if p.Meta.Flags.RosenpassPermissive {
+		t.Error("expected the updated flags to be stored")
+	}
+}
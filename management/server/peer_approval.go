@@ -0,0 +1,360 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/ha"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// GetPeerApprovalRequests returns the approval requests of the account peers, newest first.
+func (am *DefaultAccountManager) GetPeerApprovalRequests(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRequest, error) {
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	return am.Store.GetAccountPeerApprovalRequests(ctx, store.LockingStrengthShare, accountID)
+}
+
+// ApprovePeer approves a peer pending approval and records the reason in the activity log.
+func (am *DefaultAccountManager) ApprovePeer(ctx context.Context, accountID, userID, peerID, reason string) (*nbpeer.Peer, error) {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	var peer *nbpeer.Peer
+	var eventMeta map[string]any
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		peer, err = transaction.GetPeerByID(ctx, store.LockingStrengthUpdate, accountID, peerID)
+		if err != nil {
+			return err
+		}
+
+		if !peer.Status.RequiresApproval {
+			return status.Errorf(status.PreconditionFailed, "peer %s doesn't require approval", peerID)
+		}
+
+		settings, err := transaction.GetAccountSettings(ctx, store.LockingStrengthShare, accountID)
+		if err != nil {
+			return err
+		}
+
+		if err = am.approvePendingPeer(ctx, transaction, accountID, userID, peer, settings); err != nil {
+			return err
+		}
+
+		eventMeta, err = reviewPeerApprovalRequest(ctx, transaction, accountID, userID, peer, types.PeerApprovalStatusApproved, reason)
+		if err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerApproved, eventMeta)
+	am.UpdateAccountPeers(ctx, accountID)
+
+	return peer, nil
+}
+
+// RejectPeer rejects a peer pending approval, the peer is removed from the account and the reason is recorded in the activity log.
+func (am *DefaultAccountManager) RejectPeer(ctx context.Context, accountID, userID, peerID, reason string) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return err
+	}
+
+	var peer *nbpeer.Peer
+	var eventMeta map[string]any
+	var updateAccountPeers bool
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		peer, err = transaction.GetPeerByID(ctx, store.LockingStrengthUpdate, accountID, peerID)
+		if err != nil {
+			return err
+		}
+
+		if !peer.Status.RequiresApproval {
+			return status.Errorf(status.PreconditionFailed, "peer %s doesn't require approval", peerID)
+		}
+
+		if err = am.validatePeerDelete(ctx, accountID, peerID); err != nil {
+			return err
+		}
+
+		eventMeta, err = reviewPeerApprovalRequest(ctx, transaction, accountID, userID, peer, types.PeerApprovalStatusRejected, reason)
+		if err != nil {
+			return err
+		}
+
+		updateAccountPeers, err = isPeerInActiveGroup(ctx, transaction, accountID, peerID)
+		if err != nil {
+			return err
+		}
+
+		groups, err := transaction.GetPeerGroups(ctx, store.LockingStrengthUpdate, accountID, peerID)
+		if err != nil {
+			return fmt.Errorf("failed to get peer groups: %w", err)
+		}
+
+		for _, group := range groups {
+			group.RemovePeer(peerID)
+			if err = transaction.SaveGroup(ctx, store.LockingStrengthUpdate, group); err != nil {
+				return fmt.Errorf("failed to save group: %w", err)
+			}
+		}
+
+		// the rejection event replaces the regular peer deleted event
+		if _, err = deletePeers(ctx, am, transaction, accountID, userID, []*nbpeer.Peer{peer}); err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
+	})
+	if err != nil {
+		return err
+	}
+
+	am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerRejected, eventMeta)
+	am.publishInstanceEvent(ctx, &ha.Event{Type: ha.PeersDisconnected, AccountID: accountID, PeerIDs: []string{peer.ID}})
+
+	if updateAccountPeers {
+		am.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return nil
+}
+
+// GetPeerApprovalRules returns the auto-approve rules of the account.
+func (am *DefaultAccountManager) GetPeerApprovalRules(ctx context.Context, accountID, userID string) ([]*types.PeerApprovalRule, error) {
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	return am.Store.GetAccountPeerApprovalRules(ctx, store.LockingStrengthShare, accountID)
+}
+
+// GetPeerApprovalRule returns an auto-approve rule of the account.
+func (am *DefaultAccountManager) GetPeerApprovalRule(ctx context.Context, accountID, userID, ruleID string) (*types.PeerApprovalRule, error) {
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	return am.Store.GetPeerApprovalRuleByID(ctx, store.LockingStrengthShare, accountID, ruleID)
+}
+
+// SavePeerApprovalRule creates a new auto-approve rule when the rule has no ID, otherwise updates the existing one.
+func (am *DefaultAccountManager) SavePeerApprovalRule(ctx context.Context, accountID, userID string, rule *types.PeerApprovalRule) (*types.PeerApprovalRule, error) {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	var isUpdate = rule.ID != ""
+	var action = activity.PeerApprovalRuleCreated
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if isUpdate {
+			if _, err := transaction.GetPeerApprovalRuleByID(ctx, store.LockingStrengthUpdate, accountID, rule.ID); err != nil {
+				return err
+			}
+			action = activity.PeerApprovalRuleUpdated
+		} else {
+			rule.ID = xid.New().String()
+		}
+
+		for _, setupKeyID := range rule.SetupKeyIDs {
+			if _, err := transaction.GetSetupKeyByID(ctx, store.LockingStrengthShare, accountID, setupKeyID); err != nil {
+				var sErr *status.Error
+				if errors.As(err, &sErr) && sErr.Type() == status.NotFound {
+					return status.Errorf(status.InvalidArgument, "setup key %s not found", setupKeyID)
+				}
+				return err
+			}
+		}
+
+		rule.AccountID = accountID
+		return transaction.SavePeerApprovalRule(ctx, store.LockingStrengthUpdate, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	am.StoreEvent(ctx, userID, rule.ID, accountID, action, rule.EventMeta())
+
+	return rule, nil
+}
+
+// DeletePeerApprovalRule deletes an auto-approve rule of the account.
+func (am *DefaultAccountManager) DeletePeerApprovalRule(ctx context.Context, accountID, userID, ruleID string) error {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := am.validatePeerApprovalAdmin(ctx, accountID, userID); err != nil {
+		return err
+	}
+
+	var rule *types.PeerApprovalRule
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		rule, err = transaction.GetPeerApprovalRuleByID(ctx, store.LockingStrengthUpdate, accountID, ruleID)
+		if err != nil {
+			return err
+		}
+
+		return transaction.DeletePeerApprovalRule(ctx, store.LockingStrengthUpdate, accountID, ruleID)
+	})
+	if err != nil {
+		return err
+	}
+
+	am.StoreEvent(ctx, userID, ruleID, accountID, activity.PeerApprovalRuleDeleted, rule.EventMeta())
+
+	return nil
+}
+
+func (am *DefaultAccountManager) validatePeerApprovalAdmin(ctx context.Context, accountID, userID string) error {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return err
+	}
+
+	if !user.HasAdminPower() {
+		return status.NewAdminPermissionError()
+	}
+
+	return nil
+}
+
+// approvePendingPeer clears the approval requirement of the peer through the integrated validator, the same way
+// as an administrator updating the peer does.
+func (am *DefaultAccountManager) approvePendingPeer(ctx context.Context, transaction store.Store, accountID, userID string, peer *nbpeer.Peer, settings *types.Settings) error {
+	peerGroupIDs, err := getPeerGroupIDs(ctx, transaction, accountID, peer.ID)
+	if err != nil {
+		return err
+	}
+
+	update := peer.Copy()
+	update.Status.RequiresApproval = false
+
+	update, _, err = am.integratedPeerValidator.ValidatePeer(ctx, update, peer, userID, accountID, am.GetDNSDomain(), peerGroupIDs, settings.Extra)
+	if err != nil {
+		return err
+	}
+
+	if update.Status.RequiresApproval {
+		return status.Errorf(status.PreconditionFailed, "peer %s can't be approved", peer.ID)
+	}
+
+	peer.Status.RequiresApproval = false
+	return transaction.SavePeer(ctx, store.LockingStrengthUpdate, accountID, peer)
+}
+
+// reviewPeerApprovalRequest closes the approval request of the peer and returns the activity meta of the review.
+// Peers waiting for approval since before approval requests were recorded have no request to close.
+func reviewPeerApprovalRequest(ctx context.Context, transaction store.Store, accountID, userID string, peer *nbpeer.Peer, approvalStatus types.PeerApprovalStatus, reason string) (map[string]any, error) {
+	request, err := transaction.GetPeerApprovalRequest(ctx, store.LockingStrengthUpdate, accountID, peer.ID)
+	if err != nil {
+		var sErr *status.Error
+		if !errors.As(err, &sErr) || sErr.Type() != status.NotFound {
+			return nil, err
+		}
+		request = &types.PeerApprovalRequest{PeerID: peer.ID, AccountID: accountID, PeerName: peer.Name}
+	}
+
+	request.Review(approvalStatus, userID, reason)
+	if err = transaction.SavePeerApprovalRequest(ctx, store.LockingStrengthUpdate, request); err != nil {
+		return nil, err
+	}
+
+	return request.EventMeta(), nil
+}
+
+// preparePeerApproval records the approval request of a new peer requiring approval and approves it
+// when one of the account auto-approve rules matches the request.
+// Returns the matched rule and the approval request, the request is nil when the peer doesn't require approval.
+func (am *DefaultAccountManager) preparePeerApproval(ctx context.Context, transaction store.Store, accountID string, peer *nbpeer.Peer,
+	peerGroupIDs []string, settings *types.Settings, setupKeyID, setupKeyName string) (*types.PeerApprovalRequest, *types.PeerApprovalRule, error) {
+	if !peer.Status.RequiresApproval {
+		return nil, nil, nil
+	}
+
+	request := types.NewPeerApprovalRequest(peer, setupKeyID, setupKeyName)
+
+	rules, err := transaction.GetAccountPeerApprovalRules(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matched *types.PeerApprovalRule
+	for _, rule := range rules {
+		if !rule.Matches(request) {
+			continue
+		}
+
+		update := peer.Copy()
+		update.Status.RequiresApproval = false
+		update, _, err = am.integratedPeerValidator.ValidatePeer(ctx, update, peer, peer.UserID, accountID, am.GetDNSDomain(), peerGroupIDs, settings.Extra)
+		if err != nil {
+			log.WithContext(ctx).Warnf("failed to auto-approve peer %s with rule %s: %v", peer.ID, rule.ID, err)
+			break
+		}
+
+		if !update.Status.RequiresApproval {
+			matched = rule
+			peer.Status.RequiresApproval = false
+			request.Review(types.PeerApprovalStatusApproved, "", fmt.Sprintf("auto-approved by rule %s", rule.Name))
+			request.ApprovalRuleID = rule.ID
+		}
+		break
+	}
+
+	if err = transaction.SavePeerApprovalRequest(ctx, store.LockingStrengthUpdate, request); err != nil {
+		return nil, nil, err
+	}
+
+	return request, matched, nil
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func TestDefaultAccountManager_PeerApproval(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err)
+	manager.integratedPeerValidator = MocIntegratedValidator{
+		PreparePeerFunc: func(_ context.Context, _ string, peer *nbpeer.Peer, _ []string, _ *types.ExtraSettings) *nbpeer.Peer {
+			peer.Status.RequiresApproval = true
+			return peer
+		},
+	}
+
+	ctx := context.Background()
+	adminUserID := "account_creator"
+	account, err := createAccount(manager, "test_account", adminUserID, "")
+	require.NoError(t, err)
+
+	account.Users["regular_user"] = types.NewRegularUser("regular_user")
+	require.NoError(t, manager.Store.SaveAccount(ctx, account))
+
+	serversKey, err := manager.CreateSetupKey(ctx, account.Id, "servers", types.SetupKeyReusable, time.Hour, nil, 999, adminUserID, false, false, types.SetupKeyConstraints{})
+	require.NoError(t, err)
+	laptopsKey, err := manager.CreateSetupKey(ctx, account.Id, "laptops", types.SetupKeyReusable, time.Hour, nil, 999, adminUserID, false, false, types.SetupKeyConstraints{})
+	require.NoError(t, err)
+
+	addPeer := func(t *testing.T, setupKey, hostname, serialNumber string) *nbpeer.Peer {
+		t.Helper()
+		key, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		peer, _, _, err := manager.AddPeer(ctx, setupKey, "", &nbpeer.Peer{
+			Key:  key.PublicKey().String(),
+			Meta: nbpeer.PeerSystemMeta{Hostname: hostname, SystemSerialNumber: serialNumber},
+		})
+		require.NoError(t, err)
+		return peer
+	}
+
+	t.Run("rules require a name and a condition", func(t *testing.T) {
+		_, err := manager.SavePeerApprovalRule(ctx, account.Id, adminUserID, &types.PeerApprovalRule{Name: "empty", Enabled: true})
+		assertErrorType(t, status.InvalidArgument, err)
+
+		_, err = manager.SavePeerApprovalRule(ctx, account.Id, adminUserID, &types.PeerApprovalRule{Name: "missing", Enabled: true, SetupKeyIDs: []string{"missing"}})
+		assertErrorType(t, status.InvalidArgument, err)
+
+		_, err = manager.SavePeerApprovalRule(ctx, account.Id, "regular_user", &types.PeerApprovalRule{Name: "servers", Enabled: true, SetupKeyIDs: []string{serversKey.Id}})
+		assertErrorType(t, status.PermissionDenied, err)
+	})
+
+	serversRule, err := manager.SavePeerApprovalRule(ctx, account.Id, adminUserID, &types.PeerApprovalRule{
+		Name:        "servers",
+		Enabled:     true,
+		SetupKeyIDs: []string{serversKey.Id},
+	})
+	require.NoError(t, err)
+	inventoryRule, err := manager.SavePeerApprovalRule(ctx, account.Id, adminUserID, &types.PeerApprovalRule{
+		Name:          "inventory",
+		Enabled:       false,
+		SerialNumbers: []string{"SN-1"},
+	})
+	require.NoError(t, err)
+
+	t.Run("peer matching a rule is auto-approved", func(t *testing.T) {
+		peer := addPeer(t, serversKey.Key, "server-1", "")
+		assert.False(t, peer.Status.RequiresApproval)
+
+		request, err := manager.Store.GetPeerApprovalRequest(ctx, "", account.Id, peer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types.PeerApprovalStatusApproved, request.Status)
+		assert.Equal(t, serversRule.ID, request.ApprovalRuleID)
+		assert.Equal(t, "servers", request.SetupKeyName)
+		assert.NotNil(t, request.ReviewedAt)
+	})
+
+	t.Run("peer matching a disabled rule waits for approval", func(t *testing.T) {
+		peer := addPeer(t, laptopsKey.Key, "laptop-1", "SN-1")
+		assert.True(t, peer.Status.RequiresApproval)
+
+		request, err := manager.Store.GetPeerApprovalRequest(ctx, "", account.Id, peer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types.PeerApprovalStatusPending, request.Status)
+		assert.Equal(t, "SN-1", request.Meta.SystemSerialNumber)
+		assert.Equal(t, laptopsKey.Id, request.SetupKeyID)
+	})
+
+	inventoryRule.Enabled = true
+	_, err = manager.SavePeerApprovalRule(ctx, account.Id, adminUserID, inventoryRule)
+	require.NoError(t, err)
+
+	t.Run("peer with a serial number in the inventory is auto-approved", func(t *testing.T) {
+		peer := addPeer(t, laptopsKey.Key, "laptop-2", "SN-1")
+		assert.False(t, peer.Status.RequiresApproval)
+	})
+
+	pending := addPeer(t, laptopsKey.Key, "laptop-3", "SN-3")
+	rejected := addPeer(t, laptopsKey.Key, "laptop-4", "SN-4")
+
+	t.Run("approve with a reason", func(t *testing.T) {
+		_, err := manager.ApprovePeer(ctx, account.Id, "regular_user", pending.ID, "")
+		assertErrorType(t, status.PermissionDenied, err)
+
+		peer, err := manager.ApprovePeer(ctx, account.Id, adminUserID, pending.ID, "known laptop")
+		require.NoError(t, err)
+		assert.False(t, peer.Status.RequiresApproval)
+
+		request, err := manager.Store.GetPeerApprovalRequest(ctx, "", account.Id, pending.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types.PeerApprovalStatusApproved, request.Status)
+		assert.Equal(t, adminUserID, request.ReviewedBy)
+		assert.Equal(t, "known laptop", request.Reason)
+
+		_, err = manager.ApprovePeer(ctx, account.Id, adminUserID, pending.ID, "")
+		assertErrorType(t, status.PreconditionFailed, err, "approved peers can't be approved again")
+	})
+
+	t.Run("reject with a reason", func(t *testing.T) {
+		err := manager.RejectPeer(ctx, account.Id, adminUserID, rejected.ID, "unknown device")
+		require.NoError(t, err)
+
+		_, err = manager.Store.GetPeerByID(ctx, "", account.Id, rejected.ID)
+		assertErrorType(t, status.NotFound, err, "rejected peers are deleted")
+
+		request, err := manager.Store.GetPeerApprovalRequest(ctx, "", account.Id, rejected.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types.PeerApprovalStatusRejected, request.Status)
+		assert.Equal(t, "unknown device", request.Reason)
+
+		events, err := manager.GetEvents(ctx, account.Id, adminUserID)
+		require.NoError(t, err)
+		var found bool
+		for _, event := range events {
+			if event.Activity == activity.PeerRejected && event.TargetID == rejected.ID {
+				found = true
+				assert.Equal(t, "unknown device", event.Meta["reason"])
+			}
+		}
+		assert.True(t, found, "rejection must be recorded in the activity log")
+	})
+
+	t.Run("bulk approve records the reason", func(t *testing.T) {
+		peer := addPeer(t, laptopsKey.Key, "laptop-5", "SN-5")
+
+		results, err := manager.BulkUpdatePeers(ctx, account.Id, adminUserID, &types.PeerBulkOperation{
+			Action:  types.PeerBulkActionApprove,
+			PeerIDs: []string{peer.ID},
+			Reason:  "onboarding batch",
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NoError(t, results[0].Err)
+
+		request, err := manager.Store.GetPeerApprovalRequest(ctx, "", account.Id, peer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, types.PeerApprovalStatusApproved, request.Status)
+		assert.Equal(t, "onboarding batch", request.Reason)
+	})
+
+	t.Run("pending requests are removed with the peer", func(t *testing.T) {
+		peer := addPeer(t, laptopsKey.Key, "laptop-6", "SN-6")
+		require.NoError(t, manager.DeletePeer(ctx, account.Id, peer.ID, adminUserID))
+
+		_, err := manager.Store.GetPeerApprovalRequest(ctx, "", account.Id, peer.ID)
+		assertErrorType(t, status.NotFound, err)
+	})
+
+	t.Run("delete rule", func(t *testing.T) {
+		require.NoError(t, manager.DeletePeerApprovalRule(ctx, account.Id, adminUserID, serversRule.ID))
+
+		rules, err := manager.GetPeerApprovalRules(ctx, account.Id, adminUserID)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		assert.Equal(t, inventoryRule.ID, rules[0].ID)
+
+		peer := addPeer(t, serversKey.Key, "server-2", "")
+		assert.True(t, peer.Status.RequiresApproval)
+	})
+}
+
+func assertErrorType(t *testing.T, expected status.Type, err error, msgAndArgs ...any) {
+	t.Helper()
+	sErr, ok := status.FromError(err)
+	require.True(t, ok, "expected a status error, got %v", err)
+	assert.Equal(t, expected, sErr.Type(), msgAndArgs...)
+}
@@ -172,7 +172,7 @@ func TestAccountManager_GetNetworkMap(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -421,7 +421,7 @@ func TestAccountManager_GetPeerNetwork(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -493,7 +493,7 @@ func TestDefaultAccountManager_GetPeer(t *testing.T) {
 	}
 
 	// two peers one added by a regular user and one with a setup key
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, adminUser, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, adminUser, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -1080,9 +1080,10 @@ func TestToSyncResponse(t *testing.T) {
 		Signature: "turn-pass",
 	}
 	networkMap := &types.NetworkMap{
-		Network:      &types.Network{Net: *ipnet, Serial: 1000},
-		Peers:        []*nbpeer.Peer{{IP: net.ParseIP("192.168.1.2"), Key: "peer2-key", DNSLabel: "peer2", SSHEnabled: true, SSHKey: "peer2-ssh-key"}},
-		OfflinePeers: []*nbpeer.Peer{{IP: net.ParseIP("192.168.1.3"), Key: "peer3-key", DNSLabel: "peer3", SSHEnabled: true, SSHKey: "peer3-ssh-key"}},
+		Network:           &types.Network{Net: *ipnet, Serial: 1000},
+		Peers:             []*nbpeer.Peer{{IP: net.ParseIP("192.168.1.2"), Key: "peer2-key", DNSLabel: "peer2", SSHEnabled: true, SSHKey: "peer2-ssh-key"}},
+		OfflinePeers:      []*nbpeer.Peer{{IP: net.ParseIP("192.168.1.3"), Key: "peer3-key", DNSLabel: "peer3", SSHEnabled: true, SSHKey: "peer3-ssh-key"}},
+		RosenpassRequired: true,
 		Routes: []*nbroute.Route{
 			{
 				ID:          "route1",
@@ -1164,6 +1165,7 @@ func TestToSyncResponse(t *testing.T) {
 	assert.Equal(t, "192.168.1.1/24", response.PeerConfig.Address)
 	assert.Equal(t, "peer1.example.com", response.PeerConfig.Fqdn)
 	assert.Equal(t, true, response.PeerConfig.SshConfig.SshEnabled)
+	assert.True(t, response.PeerConfig.RosenpassRequired)
 	// assert netbird config
 	assert.Equal(t, "signal.uri", response.NetbirdConfig.Signal.Uri)
 	assert.Equal(t, proto.HostConfig_HTTPS, response.NetbirdConfig.Signal.GetProtocol())
@@ -1866,3 +1868,129 @@ func Test_DeletePeer(t *testing.T) {
 	assert.NotContains(t, group.Peers, "peer1")
 
 }
+
+func TestDefaultAccountManager_BulkUpdatePeers(t *testing.T) {
+	manager, account, peer1, peer2, peer3 := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	group := &types.Group{ID: "bulk", Name: "bulk", Issued: types.GroupIssuedAPI}
+	require.NoError(t, manager.SaveGroup(ctx, account.Id, userID, group))
+
+	policy := &types.Policy{
+		Enabled: true,
+		Rules: []*types.PolicyRule{{
+			Enabled:       true,
+			Sources:       []string{group.ID},
+			Destinations:  []string{group.ID},
+			Bidirectional: true,
+			Action:        types.PolicyTrafficActionAccept,
+		}},
+	}
+	_, err := manager.SavePolicy(ctx, account.Id, userID, policy)
+	require.NoError(t, err)
+
+	updMsg := manager.peersUpdateManager.CreateChannel(ctx, peer1.ID)
+	t.Cleanup(func() {
+		manager.peersUpdateManager.CloseChannel(ctx, peer1.ID)
+	})
+
+	t.Run("assign groups", func(t *testing.T) {
+		results, err := manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:   types.PeerBulkActionAssignGroups,
+			PeerIDs:  []string{peer1.ID, "missing", peer2.ID, peer1.ID},
+			GroupIDs: []string{group.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3, "duplicated peers must be applied once")
+		assert.NoError(t, results[0].Err)
+		assert.Error(t, results[1].Err)
+		assert.NoError(t, results[2].Err)
+
+		peerShouldReceiveUpdate(t, updMsg)
+		peerShouldNotReceiveUpdate(t, updMsg)
+
+		group, err := manager.GetGroup(ctx, account.Id, group.ID, userID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{peer1.ID, peer2.ID}, group.Peers)
+	})
+
+	t.Run("assign to a missing group", func(t *testing.T) {
+		_, err := manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:   types.PeerBulkActionAssignGroups,
+			PeerIDs:  []string{peer3.ID},
+			GroupIDs: []string{group.ID, "missing"},
+		})
+		require.Error(t, err)
+
+		group, err := manager.GetGroup(ctx, account.Id, group.ID, userID)
+		require.NoError(t, err)
+		assert.NotContains(t, group.Peers, peer3.ID, "the operation must be rolled back")
+	})
+
+	t.Run("set ssh", func(t *testing.T) {
+		results, err := manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:  types.PeerBulkActionSetSSH,
+			PeerIDs: []string{peer1.ID, peer2.ID, peer3.ID},
+			Enabled: true,
+		})
+		require.NoError(t, err)
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+
+		peerShouldReceiveUpdate(t, updMsg)
+		peerShouldNotReceiveUpdate(t, updMsg)
+
+		peer, err := manager.GetPeer(ctx, account.Id, peer3.ID, userID)
+		require.NoError(t, err)
+		assert.True(t, peer.SSHEnabled)
+	})
+
+	t.Run("set login expiration of setup key peers", func(t *testing.T) {
+		results, err := manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:  types.PeerBulkActionSetLoginExpiration,
+			PeerIDs: []string{peer1.ID},
+			Enabled: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Error(t, results[0].Err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		results, err := manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:  types.PeerBulkActionDelete,
+			PeerIDs: []string{peer2.ID, peer3.ID},
+		})
+		require.NoError(t, err)
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+
+		peerShouldReceiveUpdate(t, updMsg)
+		peerShouldNotReceiveUpdate(t, updMsg)
+
+		peers, err := manager.GetPeers(ctx, account.Id, userID, "", "")
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+		assert.Equal(t, peer1.ID, peers[0].ID)
+
+		group, err := manager.GetGroup(ctx, account.Id, group.ID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{peer1.ID}, group.Peers)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		_, err := manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:  "rename",
+			PeerIDs: []string{peer1.ID},
+		})
+		require.Error(t, err)
+
+		_, err = manager.BulkUpdatePeers(ctx, account.Id, userID, &types.PeerBulkOperation{
+			Action:  types.PeerBulkActionUnassignGroups,
+			PeerIDs: []string{peer1.ID},
+		})
+		require.Error(t, err, "unassign groups requires groups")
+	})
+}
@@ -100,10 +100,21 @@ func (m *managerImpl) validateRegularUserPermissions(ctx context.Context, accoun
 	return false, nil
 }
 
+// ValidateAccountAccess checks the user belongs to the account or is an admin of its parent account
 func (m *managerImpl) ValidateAccountAccess(ctx context.Context, accountID string, user *types.User, allowOwnerAndAdmin bool) error {
-	if user.AccountID != accountID {
+	if user.AccountID == accountID {
+		return nil
+	}
+
+	if !user.HasAdminPower() || user.IsBlocked() {
 		return status.NewUserNotPartOfAccountError()
 	}
+
+	parentAccountID, err := m.store.GetAccountParentID(ctx, store.LockingStrengthShare, accountID)
+	if err != nil || parentAccountID == "" || parentAccountID != user.AccountID {
+		return status.NewUserNotPartOfAccountError()
+	}
+
 	return nil
 }
 
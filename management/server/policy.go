@@ -3,8 +3,11 @@ package server
 import (
 	"context"
 	_ "embed"
+	"fmt"
+	"strings"
 
 	"github.com/rs/xid"
+	"golang.org/x/exp/maps"
 
 	"github.com/netbirdio/netbird/management/proto"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -35,7 +38,10 @@ func (am *DefaultAccountManager) GetPolicy(ctx context.Context, accountID, polic
 
 // SavePolicy in the store
 func (am *DefaultAccountManager) SavePolicy(ctx context.Context, accountID, userID string, policy *types.Policy) (*types.Policy, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
@@ -92,7 +98,10 @@ func (am *DefaultAccountManager) SavePolicy(ctx context.Context, accountID, user
 
 // DeletePolicy from the store
 func (am *DefaultAccountManager) DeletePolicy(ctx context.Context, accountID, policyID, userID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
@@ -159,6 +168,44 @@ func (am *DefaultAccountManager) ListPolicies(ctx context.Context, accountID, us
 	return am.Store.GetAccountPolicies(ctx, store.LockingStrengthShare, accountID)
 }
 
+// SimulatePolicy evaluates whether a peer can reach a destination with the current policies of the account
+func (am *DefaultAccountManager) SimulatePolicy(ctx context.Context, accountID, userID string, req types.PolicySimulationRequest) (*types.PolicySimulationResult, error) {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, err
+	}
+
+	if user.IsRegularUser() {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	account, err := am.requestBuffer.GetAccountWithBackpressure(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	validatedPeers, err := am.integratedPeerValidator.GetValidatedPeers(account.Id, maps.Values(account.Groups), maps.Values(account.Peers), account.Settings.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validated peers: %w", err)
+	}
+
+	// peer FQDNs aren't routed, resolve them to the peer
+	if label, ok := strings.CutSuffix(strings.TrimSuffix(req.DestinationDomain, "."), "."+am.GetDNSDomain()); ok && req.DestinationDomain != "" {
+		for _, peer := range account.Peers {
+			if peer.DNSLabel == label {
+				req.DestinationDomain, req.DestinationPeerID = "", peer.ID
+				break
+			}
+		}
+	}
+
+	return account.SimulatePolicy(ctx, req, validatedPeers)
+}
+
 // arePolicyChangesAffectPeers checks if changes to a policy will affect any associated peers.
 func arePolicyChangesAffectPeers(ctx context.Context, transaction store.Store, accountID string, policy *types.Policy, isUpdate bool) (bool, error) {
 	if isUpdate {
@@ -18,6 +18,8 @@ const (
 	GeoLocationCheckName      = "GeoLocationCheck"
 	PeerNetworkRangeCheckName = "PeerNetworkRangeCheck"
 	ProcessCheckName          = "ProcessCheck"
+	DeviceInventoryCheckName  = "DeviceInventoryCheck"
+	PostQuantumCheckName      = "PostQuantumCheck"
 
 	CheckActionAllow string = "allow"
 	CheckActionDeny  string = "deny"
@@ -58,6 +60,8 @@ type ChecksDefinition struct {
 	GeoLocationCheck      *GeoLocationCheck      `json:",omitempty"`
 	PeerNetworkRangeCheck *PeerNetworkRangeCheck `json:",omitempty"`
 	ProcessCheck          *ProcessCheck          `json:",omitempty"`
+	DeviceInventoryCheck  *DeviceInventoryCheck  `json:",omitempty"`
+	PostQuantumCheck      *PostQuantumCheck      `json:",omitempty"`
 }
 
 // Copy returns a copy of a checks definition.
@@ -110,6 +114,16 @@ func (cd ChecksDefinition) Copy() ChecksDefinition {
 		}
 		copy(cdCopy.ProcessCheck.Processes, processCheck.Processes)
 	}
+	if cd.DeviceInventoryCheck != nil {
+		cdCopy.DeviceInventoryCheck = &DeviceInventoryCheck{
+			RequireOwnerMatch: cd.DeviceInventoryCheck.RequireOwnerMatch,
+		}
+	}
+	if cd.PostQuantumCheck != nil {
+		cdCopy.PostQuantumCheck = &PostQuantumCheck{
+			RequireStrict: cd.PostQuantumCheck.RequireStrict,
+		}
+	}
 	return cdCopy
 }
 
@@ -153,6 +167,12 @@ func (pc *Checks) GetChecks() []Check {
 	if pc.Checks.ProcessCheck != nil {
 		checks = append(checks, pc.Checks.ProcessCheck)
 	}
+	if pc.Checks.DeviceInventoryCheck != nil {
+		checks = append(checks, pc.Checks.DeviceInventoryCheck)
+	}
+	if pc.Checks.PostQuantumCheck != nil {
+		checks = append(checks, pc.Checks.PostQuantumCheck)
+	}
 	return checks
 }
 
@@ -208,6 +228,18 @@ func buildPostureCheck(postureChecksID string, name string, description string,
 		postureChecks.Checks.ProcessCheck = toProcessCheck(processCheck)
 	}
 
+	if deviceInventoryCheck := checks.DeviceInventoryCheck; deviceInventoryCheck != nil {
+		postureChecks.Checks.DeviceInventoryCheck = &DeviceInventoryCheck{
+			RequireOwnerMatch: deviceInventoryCheck.RequireOwnerMatch != nil && *deviceInventoryCheck.RequireOwnerMatch,
+		}
+	}
+
+	if postQuantumCheck := checks.PostQuantumCheck; postQuantumCheck != nil {
+		postureChecks.Checks.PostQuantumCheck = &PostQuantumCheck{
+			RequireStrict: postQuantumCheck.RequireStrict != nil && *postQuantumCheck.RequireStrict,
+		}
+	}
+
 	return &postureChecks, nil
 }
 
@@ -242,6 +274,18 @@ func (pc *Checks) ToAPIResponse() *api.PostureCheck {
 		checks.ProcessCheck = toProcessCheckResponse(pc.Checks.ProcessCheck)
 	}
 
+	if pc.Checks.DeviceInventoryCheck != nil {
+		checks.DeviceInventoryCheck = &api.DeviceInventoryCheck{
+			RequireOwnerMatch: &pc.Checks.DeviceInventoryCheck.RequireOwnerMatch,
+		}
+	}
+
+	if pc.Checks.PostQuantumCheck != nil {
+		checks.PostQuantumCheck = &api.PostQuantumCheck{
+			RequireStrict: &pc.Checks.PostQuantumCheck.RequireStrict,
+		}
+	}
+
 	return &api.PostureCheck{
 		Id:          pc.ID,
 		Name:        pc.Name,
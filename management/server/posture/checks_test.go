@@ -283,6 +283,9 @@ func TestChecks_Copy(t *testing.T) {
 					},
 				},
 			},
+			PostQuantumCheck: &PostQuantumCheck{
+				RequireStrict: true,
+			},
 		},
 	}
 	checkCopy := check.Copy()
@@ -0,0 +1,53 @@
+package posture
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+// DeviceInventory provides the account device inventory the DeviceInventoryCheck validates peers against
+type DeviceInventory interface {
+	// DeviceOwner returns the owner of the device with the serial number and whether the device is inventoried
+	DeviceOwner(serialNumber string) (string, bool)
+}
+
+// DeviceInventoryCheck allows access only to peers running on devices of the account inventory
+type DeviceInventoryCheck struct {
+	// RequireOwnerMatch requires the peer to be registered by the owner of the device, when the device has one
+	RequireOwnerMatch bool
+}
+
+var _ Check = (*DeviceInventoryCheck)(nil)
+
+// Check fails as the device inventory check can only be evaluated against the account inventory with CheckInventory
+func (d *DeviceInventoryCheck) Check(_ context.Context, _ nbpeer.Peer) (bool, error) {
+	return false, errors.New("device inventory isn't available")
+}
+
+// CheckInventory checks that the peer serial number is in the inventory and, if required, that the peer belongs to the device owner
+func (d *DeviceInventoryCheck) CheckInventory(ctx context.Context, peer nbpeer.Peer, inventory DeviceInventory) (bool, error) {
+	owner, ok := inventory.DeviceOwner(peer.Meta.SystemSerialNumber)
+	if !ok {
+		log.WithContext(ctx).Debugf("peer %s serial number %q isn't in the device inventory", peer.ID, peer.Meta.SystemSerialNumber)
+		return false, nil
+	}
+
+	if d.RequireOwnerMatch && owner != "" && owner != peer.UserID {
+		log.WithContext(ctx).Debugf("peer %s isn't registered by the owner of device %s", peer.ID, peer.Meta.SystemSerialNumber)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (d *DeviceInventoryCheck) Name() string {
+	return DeviceInventoryCheckName
+}
+
+func (d *DeviceInventoryCheck) Validate() error {
+	return nil
+}
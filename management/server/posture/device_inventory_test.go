@@ -0,0 +1,77 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+type testInventory map[string]string
+
+func (i testInventory) DeviceOwner(serialNumber string) (string, bool) {
+	owner, ok := i[serialNumber]
+	return owner, ok
+}
+
+func TestDeviceInventoryCheck_CheckInventory(t *testing.T) {
+	inventory := testInventory{
+		"SN-1": "",
+		"SN-2": "user1",
+	}
+
+	tests := []struct {
+		name    string
+		input   peer.Peer
+		check   DeviceInventoryCheck
+		isValid bool
+	}{
+		{
+			name:    "device in inventory",
+			input:   peer.Peer{UserID: "user2", Meta: peer.PeerSystemMeta{SystemSerialNumber: "SN-1"}},
+			check:   DeviceInventoryCheck{RequireOwnerMatch: true},
+			isValid: true,
+		},
+		{
+			name:    "device not in inventory",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{SystemSerialNumber: "SN-3"}},
+			check:   DeviceInventoryCheck{},
+			isValid: false,
+		},
+		{
+			name:    "device of another owner without owner match",
+			input:   peer.Peer{UserID: "user2", Meta: peer.PeerSystemMeta{SystemSerialNumber: "SN-2"}},
+			check:   DeviceInventoryCheck{},
+			isValid: true,
+		},
+		{
+			name:    "device of another owner with owner match",
+			input:   peer.Peer{UserID: "user2", Meta: peer.PeerSystemMeta{SystemSerialNumber: "SN-2"}},
+			check:   DeviceInventoryCheck{RequireOwnerMatch: true},
+			isValid: false,
+		},
+		{
+			name:    "device of the owner with owner match",
+			input:   peer.Peer{UserID: "user1", Meta: peer.PeerSystemMeta{SystemSerialNumber: "SN-2"}},
+			check:   DeviceInventoryCheck{RequireOwnerMatch: true},
+			isValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := tt.check.CheckInventory(context.Background(), tt.input, inventory)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.isValid, isValid)
+		})
+	}
+}
+
+func TestDeviceInventoryCheck_Check(t *testing.T) {
+	check := DeviceInventoryCheck{}
+	isValid, err := check.Check(context.Background(), peer.Peer{Meta: peer.PeerSystemMeta{SystemSerialNumber: "SN-1"}})
+	assert.Error(t, err)
+	assert.False(t, isValid)
+}
@@ -0,0 +1,40 @@
+package posture
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+// PostQuantumCheck allows access only to peers with the Rosenpass post-quantum key exchange enabled
+type PostQuantumCheck struct {
+	// RequireStrict rejects peers running Rosenpass in permissive mode, which accepts connections without the
+	// post-quantum key exchange to peers that don't support it
+	RequireStrict bool
+}
+
+var _ Check = (*PostQuantumCheck)(nil)
+
+func (p *PostQuantumCheck) Check(ctx context.Context, peer nbpeer.Peer) (bool, error) {
+	if !peer.Meta.Flags.RosenpassEnabled {
+		log.WithContext(ctx).Debugf("peer %s doesn't have Rosenpass enabled", peer.ID)
+		return false, nil
+	}
+
+	if p.RequireStrict && peer.Meta.Flags.RosenpassPermissive {
+		log.WithContext(ctx).Debugf("peer %s runs Rosenpass in permissive mode", peer.ID)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *PostQuantumCheck) Name() string {
+	return PostQuantumCheckName
+}
+
+func (p *PostQuantumCheck) Validate() error {
+	return nil
+}
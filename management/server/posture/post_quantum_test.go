@@ -0,0 +1,52 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestPostQuantumCheck_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   peer.Peer
+		check   PostQuantumCheck
+		isValid bool
+	}{
+		{
+			name:    "rosenpass disabled",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{}},
+			check:   PostQuantumCheck{},
+			isValid: false,
+		},
+		{
+			name:    "rosenpass enabled",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{Flags: peer.Flags{RosenpassEnabled: true}}},
+			check:   PostQuantumCheck{RequireStrict: true},
+			isValid: true,
+		},
+		{
+			name:    "rosenpass permissive",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{Flags: peer.Flags{RosenpassEnabled: true, RosenpassPermissive: true}}},
+			check:   PostQuantumCheck{},
+			isValid: true,
+		},
+		{
+			name:    "rosenpass permissive with strict mode required",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{Flags: peer.Flags{RosenpassEnabled: true, RosenpassPermissive: true}}},
+			check:   PostQuantumCheck{RequireStrict: true},
+			isValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := tt.check.Check(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.isValid, isValid)
+		})
+	}
+}
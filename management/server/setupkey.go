@@ -2,12 +2,14 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -52,8 +54,12 @@ type SetupKeyUpdateOperation struct {
 // CreateSetupKey generates a new setup key with a given name, type, list of groups IDs to auto-assign to peers registered with this key,
 // and adds it to the specified account. A list of autoGroups IDs can be empty.
 func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID string, keyName string, keyType types.SetupKeyType,
-	expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool) (*types.SetupKey, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool,
+	constraints types.SetupKeyConstraints) (*types.SetupKey, error) {
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
@@ -69,6 +75,10 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 		return nil, status.NewAdminPermissionError()
 	}
 
+	if err = constraints.Validate(); err != nil {
+		return nil, err
+	}
+
 	var setupKey *types.SetupKey
 	var plainKey string
 	var eventsToStore []func()
@@ -80,6 +90,7 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 
 		setupKey, plainKey = types.GenerateSetupKey(keyName, keyType, expiresIn, autoGroups, usageLimit, ephemeral, allowExtraDNSLabels)
 		setupKey.AccountID = accountID
+		setupKey.Constraints = constraints
 
 		events := am.prepareSetupKeyEvents(ctx, transaction, accountID, userID, autoGroups, nil, setupKey)
 		eventsToStore = append(eventsToStore, events...)
@@ -110,7 +121,10 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 		return nil, status.Errorf(status.InvalidArgument, "provided setup key to update is nil")
 	}
 
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
@@ -253,6 +267,86 @@ func (am *DefaultAccountManager) DeleteSetupKey(ctx context.Context, accountID,
 	return nil
 }
 
+// BulkUpdateSetupKeys revokes or deletes all the given setup keys in a single transaction.
+// Keys that don't exist are reported in the results without failing the rest of the operation.
+func (am *DefaultAccountManager) BulkUpdateSetupKeys(ctx context.Context, accountID, userID string, op *types.SetupKeyBulkOperation) ([]*types.BulkItemResult, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, user, false); err != nil {
+		return nil, err
+	}
+
+	if user.IsRegularUser() {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	var results []*types.BulkItemResult
+	var eventsToStore []func()
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		results = make([]*types.BulkItemResult, 0, len(op.KeyIDs))
+		eventsToStore = nil
+
+		for _, keyID := range op.KeyIDs {
+			key, err := transaction.GetSetupKeyByID(ctx, store.LockingStrengthUpdate, accountID, keyID)
+			if err != nil {
+				if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+					results = append(results, &types.BulkItemResult{ID: keyID, Err: err})
+					continue
+				}
+				return err
+			}
+
+			switch op.Action {
+			case types.SetupKeyBulkActionRevoke:
+				if !key.Revoked {
+					key.Revoked = true
+					key.UpdatedAt = time.Now().UTC()
+					if err = transaction.SaveSetupKey(ctx, store.LockingStrengthUpdate, key); err != nil {
+						return err
+					}
+					eventsToStore = append(eventsToStore, func() {
+						am.StoreEvent(ctx, userID, key.Id, accountID, activity.SetupKeyRevoked, key.EventMeta())
+					})
+				}
+			case types.SetupKeyBulkActionDelete:
+				if err = transaction.DeleteSetupKey(ctx, store.LockingStrengthUpdate, accountID, keyID); err != nil {
+					return err
+				}
+				eventsToStore = append(eventsToStore, func() {
+					am.StoreEvent(ctx, userID, key.Id, accountID, activity.SetupKeyDeleted, key.EventMeta())
+				})
+			}
+
+			results = append(results, &types.BulkItemResult{ID: keyID})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storeEvent := range eventsToStore {
+		storeEvent()
+	}
+
+	return results, nil
+}
+
 func validateSetupKeyAutoGroups(ctx context.Context, transaction store.Store, accountID string, autoGroupIDs []string) error {
 	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthShare, accountID, autoGroupIDs)
 	if err != nil {
@@ -312,3 +406,41 @@ func (am *DefaultAccountManager) prepareSetupKeyEvents(ctx context.Context, tran
 
 	return eventsToStore
 }
+
+// validateSetupKeyConstraints checks the peer registering with the setup key satisfies the key constraints.
+// It returns the reason the peer is rejected or an empty string when the peer is allowed to register.
+func validateSetupKeyConstraints(ctx context.Context, transaction store.Store, key *types.SetupKey, peer *nbpeer.Peer) (string, error) {
+	if reason := key.Constraints.CheckPeer(peer); reason != "" {
+		return reason, nil
+	}
+
+	if key.Constraints.InventoryDevicesOnly {
+		serialNumber := types.NormalizeSerialNumber(peer.Meta.SystemSerialNumber)
+		if serialNumber == "" {
+			return "device serial number is unknown", nil
+		}
+
+		_, err := transaction.GetInventoryDeviceBySerialNumber(ctx, store.LockingStrengthShare, key.AccountID, serialNumber)
+		if err != nil {
+			if isNotFoundError(err) {
+				return fmt.Sprintf("device %s isn't in the account inventory", serialNumber), nil
+			}
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// setupKeyViolationMeta returns activity event meta naming the setup key that refused the peer and why
+func setupKeyViolationMeta(key *types.SetupKey, peer *nbpeer.Peer, reason string) map[string]any {
+	meta := key.EventMeta()
+	meta["reason"] = reason
+	meta["hostname"] = peer.Meta.Hostname
+	meta["os"] = peer.Meta.GoOS
+	meta["version"] = peer.Meta.WtVersion
+	if peer.Location.ConnectionIP != nil {
+		meta["connection_ip"] = peer.Location.ConnectionIP.String()
+	}
+	return meta
+}
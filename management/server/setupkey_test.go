@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"testing"
@@ -13,8 +14,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/types"
 )
 
@@ -50,7 +54,7 @@ func TestDefaultAccountManager_SaveSetupKey(t *testing.T) {
 	keyName := "my-test-key"
 
 	key, err := manager.CreateSetupKey(context.Background(), account.Id, keyName, types.SetupKeyReusable, expiresIn, []string{},
-		types.SetupKeyUnlimitedUsage, userID, false, false)
+		types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -168,7 +172,7 @@ func TestDefaultAccountManager_CreateSetupKey(t *testing.T) {
 	for _, tCase := range []testCase{testCase1, testCase2, testCase3} {
 		t.Run(tCase.name, func(t *testing.T) {
 			key, err := manager.CreateSetupKey(context.Background(), account.Id, tCase.expectedKeyName, types.SetupKeyReusable, expiresIn,
-				tCase.expectedGroups, types.SetupKeyUnlimitedUsage, userID, false, false)
+				tCase.expectedGroups, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 
 			if tCase.expectedFailure {
 				if err == nil {
@@ -210,7 +214,7 @@ func TestGetSetupKeys(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	plainKey, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false)
+	plainKey, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -436,7 +440,7 @@ func TestSetupKeyAccountPeersUpdate(t *testing.T) {
 			close(done)
 		}()
 
-		setupKey, err = manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+		setupKey, err = manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 		assert.NoError(t, err)
 
 		select {
@@ -477,7 +481,7 @@ func TestDefaultAccountManager_CreateSetupKey_ShouldNotAllowToUpdateRevokedKey(t
 		t.Fatal(err)
 	}
 
-	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false)
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 	assert.NoError(t, err)
 
 	// revoke the key
@@ -492,3 +496,108 @@ func TestDefaultAccountManager_CreateSetupKey_ShouldNotAllowToUpdateRevokedKey(t
 	assert.Error(t, err, "should not allow to update revoked key")
 
 }
+
+func TestDefaultAccountManager_BulkUpdateSetupKeys(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err)
+
+	userID := "testingUser"
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), userID, "")
+	require.NoError(t, err)
+
+	key1, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
+	require.NoError(t, err)
+	key2, err := manager.CreateSetupKey(context.Background(), account.Id, "key2", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
+	require.NoError(t, err)
+
+	results, err := manager.BulkUpdateSetupKeys(context.Background(), account.Id, userID, &types.SetupKeyBulkOperation{
+		Action: types.SetupKeyBulkActionRevoke,
+		KeyIDs: []string{key1.Id, "missing", key2.Id},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+
+	key, err := manager.GetSetupKey(context.Background(), account.Id, userID, key2.Id)
+	require.NoError(t, err)
+	assert.True(t, key.Revoked)
+
+	results, err = manager.BulkUpdateSetupKeys(context.Background(), account.Id, userID, &types.SetupKeyBulkOperation{
+		Action: types.SetupKeyBulkActionDelete,
+		KeyIDs: []string{key1.Id},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Err)
+
+	keys, err := manager.ListSetupKeys(context.Background(), account.Id, userID)
+	require.NoError(t, err)
+	for _, key := range keys {
+		assert.NotEqual(t, key1.Id, key.Id)
+	}
+}
+
+func TestDefaultAccountManager_SetupKeyConstraints(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	adminUserID := "account_creator"
+	account, err := createAccount(manager, "test_account", adminUserID, "")
+	require.NoError(t, err)
+
+	_, err = manager.CreateSetupKey(ctx, account.Id, "invalid", types.SetupKeyReusable, time.Hour, nil, 999, adminUserID, false, false,
+		types.SetupKeyConstraints{AllowedCIDRs: []string{"10.0.0.0/33"}})
+	assertErrorType(t, status.InvalidArgument, err)
+
+	setupKey, err := manager.CreateSetupKey(ctx, account.Id, "ci runners", types.SetupKeyReusable, time.Hour, nil, 999, adminUserID, false, false,
+		types.SetupKeyConstraints{
+			AllowedCIDRs:     []string{"203.0.113.0/24"},
+			HostnamePattern:  "ci-runner-[0-9]+",
+			AllowedOS:        []string{"linux"},
+			MinClientVersion: "0.40.0",
+		})
+	require.NoError(t, err)
+
+	addPeer := func(hostname, goos, version, connectionIP string) error {
+		key, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		_, _, _, err = manager.AddPeer(ctx, setupKey.Key, "", &nbpeer.Peer{
+			Key:      key.PublicKey().String(),
+			Meta:     nbpeer.PeerSystemMeta{Hostname: hostname, GoOS: goos, WtVersion: version},
+			Location: nbpeer.Location{ConnectionIP: net.ParseIP(connectionIP)},
+		})
+		return err
+	}
+
+	assert.NoError(t, addPeer("ci-runner-1", "linux", "0.41.2", "203.0.113.10"))
+
+	err = addPeer("ci-runner-2", "linux", "0.41.2", "198.51.100.10")
+	assertErrorType(t, status.PermissionDenied, err)
+	assert.Contains(t, err.Error(), "ci runners")
+	assert.Contains(t, err.Error(), "connection IP 198.51.100.10 isn't in the allowed networks")
+
+	assertErrorType(t, status.PermissionDenied, addPeer("laptop", "linux", "0.41.2", "203.0.113.11"))
+	assertErrorType(t, status.PermissionDenied, addPeer("ci-runner-3", "windows", "0.41.2", "203.0.113.12"))
+	assertErrorType(t, status.PermissionDenied, addPeer("ci-runner-4", "linux", "0.39.0", "203.0.113.13"))
+
+	key, err := manager.GetSetupKey(ctx, account.Id, adminUserID, setupKey.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, key.UsedTimes, "refused peers shouldn't use the key")
+
+	assert.Eventually(t, func() bool {
+		events, err := manager.GetEvents(ctx, account.Id, adminUserID)
+		if err != nil {
+			return false
+		}
+		var violations int
+		for _, event := range events {
+			if event.Activity == activity.SetupKeyConstraintViolated && event.TargetID == setupKey.Id && event.Meta["name"] == "ci runners" {
+				violations++
+			}
+		}
+		return violations == 4
+	}, time.Second, 10*time.Millisecond)
+}
@@ -177,6 +177,21 @@ func NewNetworkResourceNotFoundError(resourceID string) error {
 	return Errorf(NotFound, "network resource: %s not found", resourceID)
 }
 
+// NewInventoryDeviceNotFoundError creates a new Error with NotFound type for a missing inventory device.
+func NewInventoryDeviceNotFoundError(deviceID string) error {
+	return Errorf(NotFound, "inventory device: %s not found", deviceID)
+}
+
+// NewPeerApprovalRequestNotFoundError creates a new Error with NotFound type for a missing peer approval request.
+func NewPeerApprovalRequestNotFoundError(peerID string) error {
+	return Errorf(NotFound, "approval request for peer: %s not found", peerID)
+}
+
+// NewPeerApprovalRuleNotFoundError creates a new Error with NotFound type for a missing peer approval rule.
+func NewPeerApprovalRuleNotFoundError(ruleID string) error {
+	return Errorf(NotFound, "peer approval rule: %s not found", ruleID)
+}
+
 // NewPermissionDeniedError creates a new Error with PermissionDenied type for a permission denied error.
 func NewPermissionDeniedError() error {
 	return Errorf(PermissionDenied, "permission denied")
@@ -23,6 +23,7 @@ import (
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 
+	"github.com/netbirdio/netbird/management/server/ha"
 	"github.com/netbirdio/netbird/management/server/util"
 
 	nbdns "github.com/netbirdio/netbird/dns"
@@ -46,6 +47,14 @@ const (
 	accountAndIDsQueryCondition = "account_id = ? AND id IN ?"
 	accountIDCondition          = "account_id = ?"
 	peerNotFoundFMT             = "peer %s not found"
+
+	// distributedLockPrefix namespaces the advisory locks of management in the shared database
+	distributedLockPrefix = "netbird:"
+	globalLockKey         = "global"
+	// distributedLockRetryInterval is the time between the attempts to acquire a distributed lock after an error
+	distributedLockRetryInterval = time.Second
+	// distributedLockTimeout bounds the wait for a distributed lock, including the retries after errors
+	distributedLockTimeout = time.Minute
 )
 
 // SqlStore represents an account storage backed by a Sql DB persisted to disk
@@ -56,6 +65,8 @@ type SqlStore struct {
 	metrics           telemetry.AppMetrics
 	installationPK    int
 	storeEngine       types.Engine
+	// locker extends the resource locks to the other management instances sharing the database, nil with a single instance
+	locker ha.Locker
 }
 
 type installation struct {
@@ -96,6 +107,7 @@ func NewSqlStore(ctx context.Context, db *gorm.DB, storeEngine types.Engine, met
 		&types.Account{}, &types.Policy{}, &types.PolicyRule{}, &route.Route{}, &nbdns.NameServerGroup{},
 		&installation{}, &types.ExtraSettings{}, &posture.Checks{}, &nbpeer.NetworkAddress{},
 		&networkTypes.Network{}, &routerTypes.NetworkRouter{}, &resourceTypes.NetworkResource{},
+		&types.PeerApprovalRequest{}, &types.PeerApprovalRule{}, &types.InventoryDevice{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("auto migrate: %w", err)
@@ -112,12 +124,18 @@ func GetKeyQueryCondition(s *SqlStore) string {
 }
 
 // AcquireGlobalLock acquires global lock across all the accounts and returns a function that releases the lock
-func (s *SqlStore) AcquireGlobalLock(ctx context.Context) (unlock func()) {
+func (s *SqlStore) AcquireGlobalLock(ctx context.Context) (unlock func(), err error) {
 	log.WithContext(ctx).Tracef("acquiring global lock")
 	start := time.Now()
 	s.globalAccountLock.Lock()
+	unlockDistributed, err := s.acquireDistributedLock(ctx, globalLockKey, false)
+	if err != nil {
+		s.globalAccountLock.Unlock()
+		return nil, err
+	}
 
 	unlock = func() {
+		unlockDistributed()
 		s.globalAccountLock.Unlock()
 		log.WithContext(ctx).Tracef("released global lock in %v", time.Since(start))
 	}
@@ -128,41 +146,85 @@ func (s *SqlStore) AcquireGlobalLock(ctx context.Context) (unlock func()) {
 		s.metrics.StoreMetrics().CountGlobalLockAcquisitionDuration(took)
 	}
 
-	return unlock
+	return unlock, nil
 }
 
 // AcquireWriteLockByUID acquires an ID lock for writing to a resource and returns a function that releases the lock
-func (s *SqlStore) AcquireWriteLockByUID(ctx context.Context, uniqueID string) (unlock func()) {
+func (s *SqlStore) AcquireWriteLockByUID(ctx context.Context, uniqueID string) (unlock func(), err error) {
 	log.WithContext(ctx).Tracef("acquiring write lock for ID %s", uniqueID)
 
 	start := time.Now()
 	value, _ := s.resourceLocks.LoadOrStore(uniqueID, &sync.RWMutex{})
 	mtx := value.(*sync.RWMutex)
 	mtx.Lock()
+	unlockDistributed, err := s.acquireDistributedLock(ctx, uniqueID, false)
+	if err != nil {
+		mtx.Unlock()
+		return nil, err
+	}
 
 	unlock = func() {
+		unlockDistributed()
 		mtx.Unlock()
 		log.WithContext(ctx).Tracef("released write lock for ID %s in %v", uniqueID, time.Since(start))
 	}
 
-	return unlock
+	return unlock, nil
 }
 
 // AcquireReadLockByUID acquires an ID lock for writing to a resource and returns a function that releases the lock
-func (s *SqlStore) AcquireReadLockByUID(ctx context.Context, uniqueID string) (unlock func()) {
+func (s *SqlStore) AcquireReadLockByUID(ctx context.Context, uniqueID string) (unlock func(), err error) {
 	log.WithContext(ctx).Tracef("acquiring read lock for ID %s", uniqueID)
 
 	start := time.Now()
 	value, _ := s.resourceLocks.LoadOrStore(uniqueID, &sync.RWMutex{})
 	mtx := value.(*sync.RWMutex)
 	mtx.RLock()
+	unlockDistributed, err := s.acquireDistributedLock(ctx, uniqueID, true)
+	if err != nil {
+		mtx.RUnlock()
+		return nil, err
+	}
 
 	unlock = func() {
+		unlockDistributed()
 		mtx.RUnlock()
 		log.WithContext(ctx).Tracef("released read lock for ID %s in %v", uniqueID, time.Since(start))
 	}
 
-	return unlock
+	return unlock, nil
+}
+
+// acquireDistributedLock locks the key on the other management instances sharing the database.
+// The callers hold the local lock meanwhile, so the attempts are bounded by distributedLockTimeout and the request
+// fails instead of blocking the other requests of the key while the database is unreachable.
+func (s *SqlStore) acquireDistributedLock(ctx context.Context, key string, shared bool) (unlock func(), err error) {
+	if s.locker == nil {
+		return func() {}, nil
+	}
+
+	lock := s.locker.Lock
+	if shared {
+		lock = s.locker.RLock
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, distributedLockTimeout)
+	defer cancel()
+
+	for {
+		unlock, err := lock(lockCtx, distributedLockPrefix+key)
+		if err == nil {
+			return unlock, nil
+		}
+
+		log.WithContext(ctx).Errorf("failed to acquire the distributed lock for %s: %v", key, err)
+
+		select {
+		case <-lockCtx.Done():
+			return nil, status.Errorf(status.Internal, "failed to acquire the lock of %s", key)
+		case <-time.After(distributedLockRetryInterval):
+		}
+	}
 }
 
 func (s *SqlStore) SaveAccount(ctx context.Context, account *types.Account) error {
@@ -289,6 +351,26 @@ func (s *SqlStore) DeleteAccount(ctx context.Context, account *types.Account) er
 			return result.Error
 		}
 
+		result = tx.Model(&types.Account{}).Where("parent_account_id = ?", account.Id).Update("parent_account_id", "")
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Model(&types.Account{}).Where("pending_parent_account_id = ?", account.Id).Update("pending_parent_account_id", "")
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&types.PeerApprovalRequest{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&types.PeerApprovalRule{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
 		return nil
 	})
 
@@ -586,6 +668,46 @@ func (s *SqlStore) GetAccountUsers(ctx context.Context, lockStrength LockingStre
 	return users, nil
 }
 
+// userSortColumns maps the user sort fields to their columns and cursor values
+var userSortColumns = map[string]sortColumn[*types.User]{
+	types.UserSortByID:        {column: "id", value: func(u *types.User) any { return u.Id }},
+	types.UserSortByRole:      {column: "role", value: func(u *types.User) any { return string(u.Role) }},
+	types.UserSortByCreatedAt: {column: "created_at", value: func(u *types.User) any { return u.CreatedAt }, isTime: true},
+}
+
+// GetAccountUsersPage returns a page of the account users matching the filter and the cursor of the next page.
+// The cursor is empty if there are no more users.
+func (s *SqlStore) GetAccountUsersPage(ctx context.Context, lockStrength LockingStrength, accountID string, filter types.UserFilter, page types.PageRequest) ([]*types.User, string, error) {
+	query := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Where(accountIDCondition, accountID)
+
+	if filter.IDs != nil {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.ServiceUser != nil {
+		query = query.Where("is_service_user = ?", *filter.ServiceUser)
+	}
+	if filter.Blocked != nil {
+		query = query.Where("blocked = ?", *filter.Blocked)
+	}
+	if filter.GroupID != "" {
+		query = query.Where("auto_groups LIKE ?", fmt.Sprintf(`%%"%s"%%`, filter.GroupID))
+	}
+
+	users, cursor, err := findPage(query, page, types.UserSortByID, userSortColumns, func(u *types.User) string { return u.Id })
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, "", err
+		}
+		log.WithContext(ctx).Errorf("failed to get users page from the store: %s", err)
+		return nil, "", status.Errorf(status.Internal, "issue getting users from store")
+	}
+
+	return users, cursor, nil
+}
+
 func (s *SqlStore) GetAccountOwner(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.User, error) {
 	var user types.User
 	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).First(&user, "account_id = ? AND role = ?", accountID, types.UserRoleOwner)
@@ -846,12 +968,21 @@ func (s *SqlStore) GetAccountIDBySetupKey(ctx context.Context, setupKey string)
 }
 
 func (s *SqlStore) GetTakenIPs(ctx context.Context, lockStrength LockingStrength, accountID string) ([]net.IP, error) {
+	return s.getTakenIPs(ctx, lockStrength, accountID, "ip")
+}
+
+// GetTakenIPv6s returns the IPv6 overlay addresses of all peers of the account
+func (s *SqlStore) GetTakenIPv6s(ctx context.Context, lockStrength LockingStrength, accountID string) ([]net.IP, error) {
+	return s.getTakenIPs(ctx, lockStrength, accountID, "ipv6")
+}
+
+func (s *SqlStore) getTakenIPs(ctx context.Context, lockStrength LockingStrength, accountID string, column string) ([]net.IP, error) {
 	var ipJSONStrings []string
 
 	// Fetch the IP addresses as JSON strings
 	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Model(&nbpeer.Peer{}).
-		Where("account_id = ?", accountID).
-		Pluck("ip", &ipJSONStrings)
+		Where("account_id = ? AND "+column+" IS NOT NULL", accountID).
+		Pluck(column, &ipJSONStrings)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, status.Errorf(status.NotFound, "no peers found for the account")
@@ -939,6 +1070,100 @@ func (s *SqlStore) GetAccountCreatedBy(ctx context.Context, lockStrength Locking
 	return createdBy, nil
 }
 
+// GetAccountParentID returns the ID of the parent account, empty if the account has no parent.
+func (s *SqlStore) GetAccountParentID(ctx context.Context, lockStrength LockingStrength, accountID string) (string, error) {
+	var parentAccountID string
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Model(&types.Account{}).
+		Select("parent_account_id").First(&parentAccountID, idQueryCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return "", status.NewAccountNotFoundError(accountID)
+		}
+		return "", status.NewGetAccountFromStoreError(result.Error)
+	}
+
+	return parentAccountID, nil
+}
+
+// GetAccountPendingParentID returns the ID of the account the account asked to be linked to, empty if there is no pending request.
+func (s *SqlStore) GetAccountPendingParentID(ctx context.Context, lockStrength LockingStrength, accountID string) (string, error) {
+	var pendingParentAccountID string
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Model(&types.Account{}).
+		Select("pending_parent_account_id").First(&pendingParentAccountID, idQueryCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return "", status.NewAccountNotFoundError(accountID)
+		}
+		return "", status.NewGetAccountFromStoreError(result.Error)
+	}
+
+	return pendingParentAccountID, nil
+}
+
+// GetChildAccounts returns the accounts managed by the given parent account.
+func (s *SqlStore) GetChildAccounts(ctx context.Context, lockStrength LockingStrength, parentAccountID string) ([]*types.ChildAccount, error) {
+	return s.getChildAccounts(ctx, lockStrength, "parent_account_id = ?", parentAccountID)
+}
+
+// GetPendingChildAccounts returns the accounts waiting for the given parent account to accept them as child accounts.
+func (s *SqlStore) GetPendingChildAccounts(ctx context.Context, lockStrength LockingStrength, parentAccountID string) ([]*types.ChildAccount, error) {
+	childAccounts, err := s.getChildAccounts(ctx, lockStrength, "pending_parent_account_id = ?", parentAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, childAccount := range childAccounts {
+		childAccount.Pending = true
+	}
+
+	return childAccounts, nil
+}
+
+func (s *SqlStore) getChildAccounts(ctx context.Context, lockStrength LockingStrength, query string, parentAccountID string) ([]*types.ChildAccount, error) {
+	var childAccounts []*types.ChildAccount
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Model(&types.Account{}).
+		Select("id", "domain", "created_by", "created_at").Order("created_at").
+		Find(&childAccounts, query, parentAccountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get child accounts from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get child accounts from store")
+	}
+
+	return childAccounts, nil
+}
+
+// SaveAccountParent sets the parent of the account, an empty parentAccountID unlinks the account from its parent.
+func (s *SqlStore) SaveAccountParent(ctx context.Context, lockStrength LockingStrength, accountID, parentAccountID string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Model(&types.Account{}).
+		Where(idQueryCondition, accountID).Update("parent_account_id", parentAccountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save account parent to the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save account parent to store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewAccountNotFoundError(accountID)
+	}
+
+	return nil
+}
+
+// SaveAccountPendingParent records the account asking to be linked to a parent account, an empty parentAccountID withdraws the request.
+func (s *SqlStore) SaveAccountPendingParent(ctx context.Context, lockStrength LockingStrength, accountID, parentAccountID string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Model(&types.Account{}).
+		Where(idQueryCondition, accountID).Update("pending_parent_account_id", parentAccountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save account pending parent to the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save account pending parent to store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewAccountNotFoundError(accountID)
+	}
+
+	return nil
+}
+
 // SaveUserLastLogin stores the last login time for a user in DB.
 func (s *SqlStore) SaveUserLastLogin(ctx context.Context, accountID, userID string, lastLogin time.Time) error {
 	var user types.User
@@ -975,6 +1200,12 @@ func (s *SqlStore) GetPostureCheckByChecksDefinition(accountID string, checks *p
 
 // Close closes the underlying DB connection
 func (s *SqlStore) Close(_ context.Context) error {
+	if s.locker != nil {
+		if err := s.locker.Close(); err != nil {
+			log.Warnf("failed to close the distributed locker: %v", err)
+		}
+	}
+
 	sql, err := s.db.DB()
 	if err != nil {
 		return fmt.Errorf("get db: %w", err)
@@ -1011,7 +1242,21 @@ func NewPostgresqlStore(ctx context.Context, dsn string, metrics telemetry.AppMe
 		return nil, err
 	}
 
-	return NewSqlStore(ctx, db, types.PostgresStoreEngine, metrics)
+	store, err := NewSqlStore(ctx, db, types.PostgresStoreEngine, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	if ha.Enabled() {
+		store.locker, err = ha.NewPostgresLocker(ctx, dsn)
+		if err != nil {
+			_ = store.Close(ctx)
+			return nil, fmt.Errorf("create distributed locker: %w", err)
+		}
+		log.WithContext(ctx).Infof("distributed locks are enabled for multiple management instances")
+	}
+
+	return store, nil
 }
 
 // NewMysqlStore creates a new MySQL store.
@@ -1040,6 +1285,19 @@ func newPostgresStore(ctx context.Context, metrics telemetry.AppMetrics) (Store,
 	return NewPostgresqlStore(ctx, dsn, metrics)
 }
 
+// NewInstanceBus returns the bus notifying the other management instances that share the Postgres database
+func NewInstanceBus(ctx context.Context, s Store) (ha.Bus, error) {
+	if s.GetStoreEngine() != types.PostgresStoreEngine {
+		return nil, fmt.Errorf("multiple management instances require the %s store engine, got %s", types.PostgresStoreEngine, s.GetStoreEngine())
+	}
+
+	dsn, ok := os.LookupEnv(postgresDsnEnv)
+	if !ok {
+		return nil, fmt.Errorf("%s is not set", postgresDsnEnv)
+	}
+	return ha.NewPostgresBus(ctx, dsn)
+}
+
 // newMysqlStore initializes a new MySQL store.
 func newMysqlStore(ctx context.Context, metrics telemetry.AppMetrics) (Store, error) {
 	dsn, ok := os.LookupEnv(mysqlDsnEnv)
@@ -1297,6 +1555,79 @@ func (s *SqlStore) GetAccountPeers(ctx context.Context, lockStrength LockingStre
 	return peers, nil
 }
 
+// peerSortColumns maps the peer sort fields to their columns and cursor values
+var peerSortColumns = map[string]sortColumn[*nbpeer.Peer]{
+	types.PeerSortByName:      {column: "name", value: func(p *nbpeer.Peer) any { return p.Name }},
+	types.PeerSortByDNSLabel:  {column: "dns_label", value: func(p *nbpeer.Peer) any { return p.DNSLabel }},
+	types.PeerSortByOS:        {column: "meta_os", value: func(p *nbpeer.Peer) any { return p.Meta.OS }},
+	types.PeerSortByVersion:   {column: "meta_wt_version", value: func(p *nbpeer.Peer) any { return p.Meta.WtVersion }},
+	types.PeerSortByLastSeen:  {column: "peer_status_last_seen", value: func(p *nbpeer.Peer) any { return p.Status.LastSeen }, isTime: true},
+	types.PeerSortByCreatedAt: {column: "created_at", value: func(p *nbpeer.Peer) any { return p.CreatedAt }, isTime: true},
+}
+
+// GetAccountPeersPage returns a page of the account peers matching the filter and the cursor of the next page.
+// The cursor is empty if there are no more peers.
+func (s *SqlStore) GetAccountPeersPage(ctx context.Context, lockStrength LockingStrength, accountID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error) {
+	query := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Where(accountIDCondition, accountID)
+
+	if filter.IDs != nil {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.Name != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.IP != "" {
+		query = query.Where("ip LIKE ?", "%"+filter.IP+"%")
+	}
+	if filter.DNSLabel != "" {
+		query = query.Where("dns_label LIKE ?", "%"+filter.DNSLabel+"%")
+	}
+	if filter.GroupID != "" {
+		group, err := s.GetGroupByID(ctx, LockingStrengthShare, accountID, filter.GroupID)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("id IN ?", append([]string{}, group.Peers...))
+	}
+	if filter.OS != "" {
+		osFilter := strings.ToLower(filter.OS)
+		query = query.Where("(LOWER(meta_go_os) = ? OR LOWER(meta_os) LIKE ?)", osFilter, "%"+osFilter+"%")
+	}
+	if filter.Version != "" {
+		query = query.Where("meta_wt_version LIKE ?", filter.Version+"%")
+	}
+	if filter.Connected != nil {
+		query = query.Where("peer_status_connected = ?", *filter.Connected)
+	}
+	if filter.LoginExpired != nil {
+		query = query.Where("peer_status_login_expired = ?", *filter.LoginExpired)
+	}
+	if filter.ApprovalRequired != nil {
+		if !*filter.ApprovalRequired {
+			query = query.Where("id IN ?", append([]string{}, filter.ApprovedIDs...))
+		} else if len(filter.ApprovedIDs) > 0 {
+			query = query.Where("id NOT IN ?", filter.ApprovedIDs)
+		}
+	}
+	if filter.LastSeenAfter != nil {
+		query = query.Where("peer_status_last_seen >= ?", filter.LastSeenAfter.UTC())
+	}
+	if filter.LastSeenBefore != nil {
+		query = query.Where("peer_status_last_seen < ?", filter.LastSeenBefore.UTC())
+	}
+
+	peers, cursor, err := findPage(query, page, types.PeerSortByName, peerSortColumns, func(p *nbpeer.Peer) string { return p.ID })
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, "", err
+		}
+		log.WithContext(ctx).Errorf("failed to get peers page from the store: %s", err)
+		return nil, "", status.Errorf(status.Internal, "failed to get peers from store")
+	}
+
+	return peers, cursor, nil
+}
+
 // GetUserPeers retrieves peers for a user.
 func (s *SqlStore) GetUserPeers(ctx context.Context, lockStrength LockingStrength, accountID, userID string) ([]*nbpeer.Peer, error) {
 	var peers []*nbpeer.Peer
@@ -1669,6 +2000,18 @@ func (s *SqlStore) DeletePolicy(ctx context.Context, lockStrength LockingStrengt
 	return nil
 }
 
+// DeletePolicyRules deletes all rules of a policy, saving a policy doesn't remove the rules missing from it.
+func (s *SqlStore) DeletePolicyRules(ctx context.Context, lockStrength LockingStrength, policyID string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Delete(&types.PolicyRule{}, "policy_id = ?", policyID)
+	if err := result.Error; err != nil {
+		log.WithContext(ctx).Errorf("failed to delete policy rules from store: %s", err)
+		return status.Errorf(status.Internal, "failed to delete policy rules from store")
+	}
+
+	return nil
+}
+
 // GetAccountPostureChecks retrieves posture checks for an account.
 func (s *SqlStore) GetAccountPostureChecks(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*posture.Checks, error) {
 	var postureChecks []*posture.Checks
@@ -2208,6 +2551,179 @@ func (s *SqlStore) GetPeerByIP(ctx context.Context, lockStrength LockingStrength
 	return &peer, nil
 }
 
+func (s *SqlStore) GetAccountInventoryDevices(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.InventoryDevice, error) {
+	var devices []*types.InventoryDevice
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Order("serial_number").Find(&devices, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get inventory devices from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get inventory devices from store")
+	}
+
+	return devices, nil
+}
+
+func (s *SqlStore) GetInventoryDeviceByID(ctx context.Context, lockStrength LockingStrength, accountID, deviceID string) (*types.InventoryDevice, error) {
+	var device *types.InventoryDevice
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		First(&device, accountAndIDQueryCondition, accountID, deviceID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewInventoryDeviceNotFoundError(deviceID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get inventory device from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get inventory device from store")
+	}
+
+	return device, nil
+}
+
+// GetInventoryDeviceBySerialNumber returns the inventory device with the normalized serial number.
+func (s *SqlStore) GetInventoryDeviceBySerialNumber(ctx context.Context, lockStrength LockingStrength, accountID, serialNumber string) (*types.InventoryDevice, error) {
+	var device *types.InventoryDevice
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		First(&device, "account_id = ? AND serial_number = ?", accountID, serialNumber)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewInventoryDeviceNotFoundError(serialNumber)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get inventory device by serial number from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get inventory device from store")
+	}
+
+	return device, nil
+}
+
+func (s *SqlStore) SaveInventoryDevice(ctx context.Context, lockStrength LockingStrength, device *types.InventoryDevice) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Save(device)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save inventory device to the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save inventory device to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteInventoryDevice(ctx context.Context, lockStrength LockingStrength, accountID, deviceID string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Delete(&types.InventoryDevice{}, accountAndIDQueryCondition, accountID, deviceID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete inventory device from the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to delete inventory device from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewInventoryDeviceNotFoundError(deviceID)
+	}
+
+	return nil
+}
+
+// GetAccountPeerApprovalRequests returns the approval requests of the account peers, newest first.
+func (s *SqlStore) GetAccountPeerApprovalRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.PeerApprovalRequest, error) {
+	var requests []*types.PeerApprovalRequest
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Order("created_at DESC").Find(&requests, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get peer approval requests from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval requests from store")
+	}
+
+	return requests, nil
+}
+
+func (s *SqlStore) GetPeerApprovalRequest(ctx context.Context, lockStrength LockingStrength, accountID, peerID string) (*types.PeerApprovalRequest, error) {
+	var request *types.PeerApprovalRequest
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		First(&request, "account_id = ? AND peer_id = ?", accountID, peerID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewPeerApprovalRequestNotFoundError(peerID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get peer approval request from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval request from store")
+	}
+
+	return request, nil
+}
+
+func (s *SqlStore) SavePeerApprovalRequest(ctx context.Context, lockStrength LockingStrength, request *types.PeerApprovalRequest) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Save(request)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save peer approval request to the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save peer approval request to store")
+	}
+
+	return nil
+}
+
+// DeletePendingPeerApprovalRequests deletes the pending approval requests of the peers, reviewed requests are kept as history.
+func (s *SqlStore) DeletePendingPeerApprovalRequests(ctx context.Context, lockStrength LockingStrength, accountID string, peerIDs []string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Delete(&types.PeerApprovalRequest{}, "account_id = ? AND peer_id IN ? AND status = ?", accountID, peerIDs, types.PeerApprovalStatusPending)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete peer approval requests from the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to delete peer approval requests from store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccountPeerApprovalRules(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.PeerApprovalRule, error) {
+	var rules []*types.PeerApprovalRule
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Find(&rules, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get peer approval rules from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval rules from store")
+	}
+
+	return rules, nil
+}
+
+func (s *SqlStore) GetPeerApprovalRuleByID(ctx context.Context, lockStrength LockingStrength, accountID, ruleID string) (*types.PeerApprovalRule, error) {
+	var rule *types.PeerApprovalRule
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		First(&rule, accountAndIDQueryCondition, accountID, ruleID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewPeerApprovalRuleNotFoundError(ruleID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get peer approval rule from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval rule from store")
+	}
+
+	return rule, nil
+}
+
+func (s *SqlStore) SavePeerApprovalRule(ctx context.Context, lockStrength LockingStrength, rule *types.PeerApprovalRule) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Save(rule)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save peer approval rule to the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save peer approval rule to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeletePeerApprovalRule(ctx context.Context, lockStrength LockingStrength, accountID, ruleID string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Delete(&types.PeerApprovalRule{}, accountAndIDQueryCondition, accountID, ruleID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete peer approval rule from the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to delete peer approval rule from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewPeerApprovalRuleNotFoundError(ruleID)
+	}
+
+	return nil
+}
+
 func (s *SqlStore) CountAccountsByPrivateDomain(ctx context.Context, domain string) (int64, error) {
 	var count int64
 	result := s.db.Model(&types.Account{}).
@@ -2221,3 +2737,103 @@ func (s *SqlStore) CountAccountsByPrivateDomain(ctx context.Context, domain stri
 
 	return count, nil
 }
+
+// sortColumn is a column a list can be sorted and paginated by
+type sortColumn[T any] struct {
+	column string
+	// value returns the value of the column of an object, either a string or a time.Time
+	value  func(T) any
+	isTime bool
+}
+
+// findPage runs the query sorted by the requested column with the ID as a tie-breaker and returns the objects
+// after the page cursor. One extra object is fetched to know if there is a next page.
+func findPage[T any](query *gorm.DB, page types.PageRequest, defaultSortBy string, columns map[string]sortColumn[T], id func(T) string) ([]T, string, error) {
+	if err := page.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	sortBy := page.SortBy
+	if sortBy == "" {
+		sortBy = defaultSortBy
+	}
+
+	col, ok := columns[sortBy]
+	if !ok {
+		return nil, "", status.Errorf(status.InvalidArgument, "unsupported sort field %s", sortBy)
+	}
+
+	order, compare := "ASC", ">"
+	if page.SortDesc {
+		order, compare = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		cursor, err := types.DecodePageCursor(page.Cursor, sortBy)
+		if err != nil {
+			return nil, "", err
+		}
+
+		value, err := cursorValue(cursor.Value, col.isTime)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if col.column == "id" {
+			query = query.Where(fmt.Sprintf("id %s ?", compare), cursor.ID)
+		} else {
+			query = query.Where(
+				fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", col.column, compare),
+				value, value, cursor.ID,
+			)
+		}
+	}
+
+	query = query.Order(fmt.Sprintf("%s %s", col.column, order))
+	if col.column != "id" {
+		query = query.Order(fmt.Sprintf("id %s", order))
+	}
+
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit + 1)
+	}
+
+	var items []T
+	if err := query.Find(&items).Error; err != nil {
+		return nil, "", err
+	}
+
+	if page.Limit == 0 || len(items) <= page.Limit {
+		return items, "", nil
+	}
+
+	items = items[:page.Limit]
+	last := items[len(items)-1]
+	next := types.PageCursor{
+		SortBy: sortBy,
+		Value:  encodeCursorValue(col.value(last)),
+		ID:     id(last),
+	}
+
+	return items, next.Encode(), nil
+}
+
+func encodeCursorValue(value any) string {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+// cursorValue parses the cursor value to the type of the column
+func cursorValue(value string, isTime bool) (any, error) {
+	if !isTime {
+		return value, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid cursor")
+	}
+	return t, nil
+}
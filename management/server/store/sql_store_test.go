@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	b64 "encoding/base64"
 	"fmt"
+	"math"
 	"math/rand"
 	"net"
 	"net/netip"
@@ -979,6 +980,41 @@ func TestSqlite_GetTakenIPs(t *testing.T) {
 
 }
 
+func TestSqlite_MigrateIPv6Overlay(t *testing.T) {
+	t.Setenv("NETBIRD_STORE_ENGINE", string(types.SqliteStoreEngine))
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	existingAccountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	account, err := store.GetAccount(context.Background(), existingAccountID)
+	require.NoError(t, err)
+	require.True(t, account.Network.HasIPv6(), "existing account should get an IPv6 network")
+	require.NotEmpty(t, account.Peers)
+
+	for _, peer := range account.Peers {
+		require.NotNil(t, peer.IPv6, "existing peer %s should get an IPv6 address", peer.ID)
+		assert.True(t, account.Network.NetV6.Contains(peer.IPv6))
+	}
+
+	takenIPv6s, err := store.GetTakenIPv6s(context.Background(), LockingStrengthShare, existingAccountID)
+	require.NoError(t, err)
+	assert.Len(t, takenIPv6s, len(account.Peers))
+
+	// running the migration again must not change the assigned addresses
+	sqlStore, ok := store.(*SqlStore)
+	require.True(t, ok)
+	require.NoError(t, migrateIPv6Overlay(context.Background(), sqlStore.db))
+
+	migratedAgain, err := store.GetAccount(context.Background(), existingAccountID)
+	require.NoError(t, err)
+	assert.Equal(t, account.Network.NetV6.String(), migratedAgain.Network.NetV6.String())
+	for id, peer := range account.Peers {
+		assert.Equal(t, peer.IPv6, migratedAgain.Peers[id].IPv6)
+	}
+}
+
 func TestSqlite_GetPeerLabelsInAccount(t *testing.T) {
 	t.Setenv("NETBIRD_STORE_ENGINE", string(types.SqliteStoreEngine))
 	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
@@ -2375,7 +2411,7 @@ func TestSqlStore_SaveNetworkResource(t *testing.T) {
 	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
 	networkID := "ct286bi7qv930dsrrug0"
 
-	netResource, err := resourceTypes.NewNetworkResource(accountID, networkID, "resource-name", "", "example.com", []string{}, true)
+	netResource, err := resourceTypes.NewNetworkResource(accountID, networkID, "resource-name", "", "example.com", []string{}, true, nil)
 	require.NoError(t, err)
 
 	err = store.SaveNetworkResource(context.Background(), LockingStrengthUpdate, netResource)
@@ -2638,6 +2674,111 @@ func TestSqlStore_GetAccountPeers(t *testing.T) {
 
 }
 
+func TestSqlStore_GetAccountPeersPage(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store_with_expired_peers.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	var peerIDs []string
+	page := types.PageRequest{Limit: 3, SortBy: types.PeerSortByName}
+	for {
+		peers, next, err := store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID, types.PeerFilter{}, page)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(peers), page.Limit)
+		for _, peer := range peers {
+			peerIDs = append(peerIDs, peer.ID)
+		}
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+	assert.Equal(t, []string{"cg05lnblo1hkg2j514p0", "cfvprsrlo1hqoo49ohog", "cg3161rlo1hs9cq94gdg", "csrnkiq7qv9d8aitqd50"}, peerIDs)
+
+	peers, next, err := store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID, types.PeerFilter{},
+		types.PageRequest{Limit: 1, SortBy: types.PeerSortByName, SortDesc: true})
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, "csrnkiq7qv9d8aitqd50", peers[0].ID)
+	assert.NotEmpty(t, next)
+
+	_, _, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID, types.PeerFilter{},
+		types.PageRequest{Limit: 1, SortBy: types.PeerSortByLastSeen, Cursor: next})
+	require.Error(t, err, "cursor issued for another sort field must be rejected")
+
+	loginExpired := true
+	peers, next, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID,
+		types.PeerFilter{LoginExpired: &loginExpired, OS: "ubuntu"}, types.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, "cg05lnblo1hkg2j514p0", peers[0].ID)
+	assert.Empty(t, next)
+
+	peers, _, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID,
+		types.PeerFilter{Version: "0.12"}, types.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, "cfvprsrlo1hqoo49ohog", peers[0].ID)
+
+	approvalRequired, approved := true, false
+	peerIDsOf := func(peers []*nbpeer.Peer) []string {
+		var ids []string
+		for _, peer := range peers {
+			ids = append(ids, peer.ID)
+		}
+		return ids
+	}
+	peers, _, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID,
+		types.PeerFilter{ApprovalRequired: &approvalRequired, ApprovedIDs: []string{"cfvprsrlo1hqoo49ohog", "cg3161rlo1hs9cq94gdg"}}, types.PageRequest{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cg05lnblo1hkg2j514p0", "csrnkiq7qv9d8aitqd50"}, peerIDsOf(peers))
+
+	peers, _, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID,
+		types.PeerFilter{ApprovalRequired: &approved, ApprovedIDs: []string{"cfvprsrlo1hqoo49ohog", "cg3161rlo1hs9cq94gdg"}}, types.PageRequest{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cfvprsrlo1hqoo49ohog", "cg3161rlo1hs9cq94gdg"}, peerIDsOf(peers))
+
+	peers, _, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID,
+		types.PeerFilter{ApprovalRequired: &approvalRequired}, types.PageRequest{})
+	require.NoError(t, err)
+	assert.Len(t, peers, 4, "all peers require approval without approved peers")
+
+	peers, _, err = store.GetAccountPeersPage(context.Background(), LockingStrengthShare, accountID,
+		types.PeerFilter{ApprovalRequired: &approved}, types.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, peers, "no peer is approved without approved peers")
+}
+
+func TestSqlStore_GetAccountUsersPage(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store_with_expired_peers.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	users, next, err := store.GetAccountUsersPage(context.Background(), LockingStrengthShare, accountID, types.UserFilter{},
+		types.PageRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "edafee4e-63fb-11ec-90d6-0242ac120003", users[0].Id)
+	require.NotEmpty(t, next)
+
+	users, next, err = store.GetAccountUsersPage(context.Background(), LockingStrengthShare, accountID, types.UserFilter{},
+		types.PageRequest{Limit: 1, Cursor: next})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "f4f6d672-63fb-11ec-90d6-0242ac120003", users[0].Id)
+	assert.Empty(t, next)
+
+	users, _, err = store.GetAccountUsersPage(context.Background(), LockingStrengthShare, accountID,
+		types.UserFilter{Role: types.UserRoleAdmin}, types.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "edafee4e-63fb-11ec-90d6-0242ac120003", users[0].Id)
+}
+
 func TestSqlStore_GetAccountPeersWithExpiration(t *testing.T) {
 	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store_with_expired_peers.sql", t.TempDir())
 	t.Cleanup(cleanup)
@@ -3247,3 +3388,251 @@ func TestSqlStore_SaveGroups_LargeBatch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 8003, len(accountGroups))
 }
+
+func TestSqlStore_ChildAccounts(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	parentID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+	child := newAccountWithId(context.Background(), "child_account_id", "child_user", "child.com")
+	require.NoError(t, store.SaveAccount(context.Background(), child))
+
+	err = store.SaveAccountParent(context.Background(), LockingStrengthUpdate, "missing", parentID)
+	require.Error(t, err)
+
+	err = store.SaveAccountParent(context.Background(), LockingStrengthUpdate, child.Id, parentID)
+	require.NoError(t, err)
+
+	parentAccountID, err := store.GetAccountParentID(context.Background(), LockingStrengthShare, child.Id)
+	require.NoError(t, err)
+	assert.Equal(t, parentID, parentAccountID)
+
+	childAccounts, err := store.GetChildAccounts(context.Background(), LockingStrengthShare, parentID)
+	require.NoError(t, err)
+	require.Len(t, childAccounts, 1)
+	assert.Equal(t, child.Id, childAccounts[0].ID)
+	assert.Equal(t, "child.com", childAccounts[0].Domain)
+
+	pending := newAccountWithId(context.Background(), "pending_account_id", "pending_user", "pending.com")
+	require.NoError(t, store.SaveAccount(context.Background(), pending))
+
+	err = store.SaveAccountPendingParent(context.Background(), LockingStrengthUpdate, pending.Id, parentID)
+	require.NoError(t, err)
+
+	pendingParentAccountID, err := store.GetAccountPendingParentID(context.Background(), LockingStrengthShare, pending.Id)
+	require.NoError(t, err)
+	assert.Equal(t, parentID, pendingParentAccountID)
+
+	childAccounts, err = store.GetChildAccounts(context.Background(), LockingStrengthShare, parentID)
+	require.NoError(t, err)
+	require.Len(t, childAccounts, 1, "pending child accounts aren't linked")
+
+	pendingChildAccounts, err := store.GetPendingChildAccounts(context.Background(), LockingStrengthShare, parentID)
+	require.NoError(t, err)
+	require.Len(t, pendingChildAccounts, 1)
+	assert.Equal(t, pending.Id, pendingChildAccounts[0].ID)
+	assert.True(t, pendingChildAccounts[0].Pending)
+
+	err = store.DeleteAccount(context.Background(), &types.Account{Id: parentID})
+	require.NoError(t, err)
+
+	parentAccountID, err = store.GetAccountParentID(context.Background(), LockingStrengthShare, child.Id)
+	require.NoError(t, err)
+	assert.Empty(t, parentAccountID)
+
+	pendingParentAccountID, err = store.GetAccountPendingParentID(context.Background(), LockingStrengthShare, pending.Id)
+	require.NoError(t, err)
+	assert.Empty(t, pendingParentAccountID)
+}
+
+func TestSqlStore_PeerApproval(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	for _, peerID := range []string{"peer1", "peer2"} {
+		request := types.NewPeerApprovalRequest(&nbpeer.Peer{
+			ID:        peerID,
+			AccountID: accountID,
+			Name:      peerID,
+			Meta:      nbpeer.PeerSystemMeta{Hostname: peerID, SystemSerialNumber: "SN-" + peerID},
+		}, "setupKey", "servers")
+		require.NoError(t, store.SavePeerApprovalRequest(ctx, LockingStrengthUpdate, request))
+	}
+
+	request, err := store.GetPeerApprovalRequest(ctx, LockingStrengthShare, accountID, "peer1")
+	require.NoError(t, err)
+	assert.Equal(t, types.PeerApprovalStatusPending, request.Status)
+	assert.Equal(t, "SN-peer1", request.Meta.SystemSerialNumber)
+	assert.Equal(t, "servers", request.SetupKeyName)
+
+	request.Review(types.PeerApprovalStatusApproved, "user", "known device")
+	require.NoError(t, store.SavePeerApprovalRequest(ctx, LockingStrengthUpdate, request))
+
+	// only pending requests are deleted, reviewed requests are kept as history
+	err = store.DeletePendingPeerApprovalRequests(ctx, LockingStrengthUpdate, accountID, []string{"peer1", "peer2"})
+	require.NoError(t, err)
+
+	requests, err := store.GetAccountPeerApprovalRequests(ctx, LockingStrengthShare, accountID)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "peer1", requests[0].PeerID)
+	assert.Equal(t, "known device", requests[0].Reason)
+	require.NotNil(t, requests[0].ReviewedAt)
+
+	_, err = store.GetPeerApprovalRequest(ctx, LockingStrengthShare, accountID, "peer2")
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	rule := types.NewPeerApprovalRule(accountID, "inventory", "", true, nil, []string{"SN-1", "SN-2"})
+	require.NoError(t, store.SavePeerApprovalRule(ctx, LockingStrengthUpdate, rule))
+
+	rules, err := store.GetAccountPeerApprovalRules(ctx, LockingStrengthShare, accountID)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, []string{"SN-1", "SN-2"}, rules[0].SerialNumbers)
+
+	savedRule, err := store.GetPeerApprovalRuleByID(ctx, LockingStrengthShare, accountID, rule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rule, savedRule)
+
+	require.NoError(t, store.DeletePeerApprovalRule(ctx, LockingStrengthUpdate, accountID, rule.ID))
+	err = store.DeletePeerApprovalRule(ctx, LockingStrengthUpdate, accountID, rule.ID)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	require.NoError(t, store.DeleteAccount(ctx, &types.Account{Id: accountID}))
+	requests, err = store.GetAccountPeerApprovalRequests(ctx, LockingStrengthShare, accountID)
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestSqlStore_InventoryDevices(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	laptop := types.NewInventoryDevice(accountID, "sn-2", "Lenovo", "ThinkPad", "", "")
+	server := types.NewInventoryDevice(accountID, "SN-1", "Dell", "PowerEdge", "edafee4e-63fb-11ec-90d6-0242ac120003", "rack 1")
+	require.NoError(t, store.SaveInventoryDevice(ctx, LockingStrengthUpdate, laptop))
+	require.NoError(t, store.SaveInventoryDevice(ctx, LockingStrengthUpdate, server))
+
+	devices, err := store.GetAccountInventoryDevices(ctx, LockingStrengthShare, accountID)
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, "SN-1", devices[0].SerialNumber)
+	assert.Equal(t, "SN-2", devices[1].SerialNumber)
+
+	device, err := store.GetInventoryDeviceBySerialNumber(ctx, LockingStrengthShare, accountID, "SN-2")
+	require.NoError(t, err)
+	assert.Equal(t, laptop.ID, device.ID)
+
+	device, err = store.GetInventoryDeviceByID(ctx, LockingStrengthShare, accountID, server.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "rack 1", device.Description)
+
+	account, err := store.GetAccount(ctx, accountID)
+	require.NoError(t, err)
+	owner, ok := account.GetDeviceInventory().DeviceOwner("sn-1")
+	assert.True(t, ok)
+	assert.Equal(t, "edafee4e-63fb-11ec-90d6-0242ac120003", owner)
+
+	duplicate := types.NewInventoryDevice(accountID, "SN-1", "", "", "", "")
+	assert.Error(t, store.SaveInventoryDevice(ctx, LockingStrengthUpdate, duplicate))
+
+	require.NoError(t, store.DeleteInventoryDevice(ctx, LockingStrengthUpdate, accountID, laptop.ID))
+	_, err = store.GetInventoryDeviceBySerialNumber(ctx, LockingStrengthShare, accountID, "SN-2")
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	err = store.DeleteInventoryDevice(ctx, LockingStrengthUpdate, accountID, laptop.ID)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
+
+func TestSqlStore_SetupKeyConstraints(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	setupKey, _ := types.GenerateSetupKey("constrained", types.SetupKeyReusable, time.Hour, []string{}, 0, false, false)
+	setupKey.AccountID = accountID
+	setupKey.Constraints = types.SetupKeyConstraints{
+		InventoryDevicesOnly: true,
+		AllowedCIDRs:         []string{"203.0.113.0/24", "2001:db8::/32"},
+		HostnamePattern:      "ci-runner-[0-9]+",
+		AllowedOS:            []string{"linux"},
+		MinClientVersion:     "0.40.0",
+	}
+	require.NoError(t, store.SaveSetupKey(ctx, LockingStrengthUpdate, setupKey))
+
+	saved, err := store.GetSetupKeyByID(ctx, LockingStrengthShare, accountID, setupKey.Id)
+	require.NoError(t, err)
+	assert.Equal(t, setupKey.Constraints, saved.Constraints)
+}
+
+type failingLocker struct {
+	failures int
+	calls    int
+	unlocked int
+}
+
+func (l *failingLocker) Lock(_ context.Context, _ string) (func(), error) {
+	l.calls++
+	if l.calls <= l.failures {
+		return nil, fmt.Errorf("connection lost")
+	}
+	return func() { l.unlocked++ }, nil
+}
+
+func (l *failingLocker) RLock(ctx context.Context, key string) (func(), error) {
+	return l.Lock(ctx, key)
+}
+
+func (l *failingLocker) Close() error {
+	return nil
+}
+
+func TestSqlStore_AcquireDistributedLockRetries(t *testing.T) {
+	locker := &failingLocker{failures: 1}
+	store := &SqlStore{locker: locker}
+
+	unlock, err := store.AcquireWriteLockByUID(context.Background(), "account")
+	require.NoError(t, err)
+	assert.Equal(t, 2, locker.calls, "failed lock should be retried")
+
+	unlock()
+	assert.Equal(t, 1, locker.unlocked)
+}
+
+func TestSqlStore_AcquireDistributedLockFails(t *testing.T) {
+	locker := &failingLocker{failures: math.MaxInt}
+	store := &SqlStore{locker: locker}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := store.AcquireWriteLockByUID(ctx, "account")
+	require.Error(t, err, "lock should fail instead of retrying after the request is done")
+	assert.Equal(t, 1, locker.calls)
+
+	// the local lock is released with the failure
+	locker.failures = 0
+	unlock, err := store.AcquireWriteLockByUID(context.Background(), "account")
+	require.NoError(t, err)
+	unlock()
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -65,7 +66,13 @@ type Store interface {
 	GetAccountSettings(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.Settings, error)
 	GetAccountDNSSettings(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.DNSSettings, error)
 	GetAccountCreatedBy(ctx context.Context, lockStrength LockingStrength, accountID string) (string, error)
+	GetAccountParentID(ctx context.Context, lockStrength LockingStrength, accountID string) (string, error)
+	GetAccountPendingParentID(ctx context.Context, lockStrength LockingStrength, accountID string) (string, error)
+	GetChildAccounts(ctx context.Context, lockStrength LockingStrength, parentAccountID string) ([]*types.ChildAccount, error)
+	GetPendingChildAccounts(ctx context.Context, lockStrength LockingStrength, parentAccountID string) ([]*types.ChildAccount, error)
 	SaveAccount(ctx context.Context, account *types.Account) error
+	SaveAccountParent(ctx context.Context, lockStrength LockingStrength, accountID, parentAccountID string) error
+	SaveAccountPendingParent(ctx context.Context, lockStrength LockingStrength, accountID, parentAccountID string) error
 	DeleteAccount(ctx context.Context, account *types.Account) error
 	UpdateAccountDomainAttributes(ctx context.Context, accountID string, domain string, category string, isPrimaryDomain bool) error
 	SaveDNSSettings(ctx context.Context, lockStrength LockingStrength, accountID string, settings *types.DNSSettings) error
@@ -74,6 +81,7 @@ type Store interface {
 	GetUserByPATID(ctx context.Context, lockStrength LockingStrength, patID string) (*types.User, error)
 	GetUserByUserID(ctx context.Context, lockStrength LockingStrength, userID string) (*types.User, error)
 	GetAccountUsers(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.User, error)
+	GetAccountUsersPage(ctx context.Context, lockStrength LockingStrength, accountID string, filter types.UserFilter, page types.PageRequest) ([]*types.User, string, error)
 	GetAccountOwner(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.User, error)
 	SaveUsers(ctx context.Context, lockStrength LockingStrength, users []*types.User) error
 	SaveUser(ctx context.Context, lockStrength LockingStrength, user *types.User) error
@@ -105,6 +113,7 @@ type Store interface {
 	CreatePolicy(ctx context.Context, lockStrength LockingStrength, policy *types.Policy) error
 	SavePolicy(ctx context.Context, lockStrength LockingStrength, policy *types.Policy) error
 	DeletePolicy(ctx context.Context, lockStrength LockingStrength, accountID, policyID string) error
+	DeletePolicyRules(ctx context.Context, lockStrength LockingStrength, policyID string) error
 
 	GetPostureCheckByChecksDefinition(accountID string, checks *posture.ChecksDefinition) (*posture.Checks, error)
 	GetAccountPostureChecks(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*posture.Checks, error)
@@ -122,6 +131,7 @@ type Store interface {
 	AddPeerToAccount(ctx context.Context, lockStrength LockingStrength, peer *nbpeer.Peer) error
 	GetPeerByPeerPubKey(ctx context.Context, lockStrength LockingStrength, peerKey string) (*nbpeer.Peer, error)
 	GetAccountPeers(ctx context.Context, lockStrength LockingStrength, accountID, nameFilter, ipFilter string) ([]*nbpeer.Peer, error)
+	GetAccountPeersPage(ctx context.Context, lockStrength LockingStrength, accountID string, filter types.PeerFilter, page types.PageRequest) ([]*nbpeer.Peer, string, error)
 	GetUserPeers(ctx context.Context, lockStrength LockingStrength, accountID, userID string) ([]*nbpeer.Peer, error)
 	GetPeerByID(ctx context.Context, lockStrength LockingStrength, accountID string, peerID string) (*nbpeer.Peer, error)
 	GetPeersByIDs(ctx context.Context, lockStrength LockingStrength, accountID string, peerIDs []string) (map[string]*nbpeer.Peer, error)
@@ -149,6 +159,7 @@ type Store interface {
 	DeleteNameServerGroup(ctx context.Context, lockStrength LockingStrength, accountID, nameServerGroupID string) error
 
 	GetTakenIPs(ctx context.Context, lockStrength LockingStrength, accountId string) ([]net.IP, error)
+	GetTakenIPv6s(ctx context.Context, lockStrength LockingStrength, accountId string) ([]net.IP, error)
 	IncrementNetworkSerial(ctx context.Context, lockStrength LockingStrength, accountId string) error
 	GetAccountNetwork(ctx context.Context, lockStrength LockingStrength, accountId string) (*types.Network, error)
 
@@ -156,11 +167,11 @@ type Store interface {
 	SaveInstallationID(ctx context.Context, ID string) error
 
 	// AcquireWriteLockByUID should attempt to acquire a lock for write purposes and return a function that releases the lock
-	AcquireWriteLockByUID(ctx context.Context, uniqueID string) func()
+	AcquireWriteLockByUID(ctx context.Context, uniqueID string) (func(), error)
 	// AcquireReadLockByUID should attempt to acquire lock for read purposes and return a function that releases the lock
-	AcquireReadLockByUID(ctx context.Context, uniqueID string) func()
+	AcquireReadLockByUID(ctx context.Context, uniqueID string) (func(), error)
 	// AcquireGlobalLock should attempt to acquire a global lock and return a function that releases the lock
-	AcquireGlobalLock(ctx context.Context) func()
+	AcquireGlobalLock(ctx context.Context) (func(), error)
 
 	// Close should close the store persisting all unsaved data.
 	Close(ctx context.Context) error
@@ -187,6 +198,22 @@ type Store interface {
 	SaveNetworkResource(ctx context.Context, lockStrength LockingStrength, resource *resourceTypes.NetworkResource) error
 	DeleteNetworkResource(ctx context.Context, lockStrength LockingStrength, accountID, resourceID string) error
 	GetPeerByIP(ctx context.Context, lockStrength LockingStrength, accountID string, ip net.IP) (*nbpeer.Peer, error)
+
+	GetAccountPeerApprovalRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.PeerApprovalRequest, error)
+	GetPeerApprovalRequest(ctx context.Context, lockStrength LockingStrength, accountID, peerID string) (*types.PeerApprovalRequest, error)
+	SavePeerApprovalRequest(ctx context.Context, lockStrength LockingStrength, request *types.PeerApprovalRequest) error
+	DeletePendingPeerApprovalRequests(ctx context.Context, lockStrength LockingStrength, accountID string, peerIDs []string) error
+
+	GetAccountPeerApprovalRules(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.PeerApprovalRule, error)
+	GetPeerApprovalRuleByID(ctx context.Context, lockStrength LockingStrength, accountID, ruleID string) (*types.PeerApprovalRule, error)
+	SavePeerApprovalRule(ctx context.Context, lockStrength LockingStrength, rule *types.PeerApprovalRule) error
+	DeletePeerApprovalRule(ctx context.Context, lockStrength LockingStrength, accountID, ruleID string) error
+
+	GetAccountInventoryDevices(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.InventoryDevice, error)
+	GetInventoryDeviceByID(ctx context.Context, lockStrength LockingStrength, accountID, deviceID string) (*types.InventoryDevice, error)
+	GetInventoryDeviceBySerialNumber(ctx context.Context, lockStrength LockingStrength, accountID, serialNumber string) (*types.InventoryDevice, error)
+	SaveInventoryDevice(ctx context.Context, lockStrength LockingStrength, device *types.InventoryDevice) error
+	DeleteInventoryDevice(ctx context.Context, lockStrength LockingStrength, accountID, deviceID string) error
 }
 
 const (
@@ -313,7 +340,122 @@ func getMigrations(ctx context.Context) []migrationFunc {
 		func(db *gorm.DB) error {
 			return migration.MigrateNewField[routerTypes.NetworkRouter](ctx, db, "enabled", true)
 		},
+		func(db *gorm.DB) error {
+			return migrateIPv6Overlay(ctx, db)
+		},
+	}
+}
+
+// migrateIPv6Overlay assigns an IPv6 overlay network to the accounts that don't have one yet
+// and allocates an address within it for every peer without an IPv6 address.
+func migrateIPv6Overlay(ctx context.Context, db *gorm.DB) error {
+	account := &types.Account{}
+	peer := &nbpeer.Peer{}
+
+	if !db.Migrator().HasTable(account) || !db.Migrator().HasTable(peer) {
+		log.WithContext(ctx).Debugf("Account or peer table does not exist, no IPv6 overlay migration needed")
+		return nil
+	}
+
+	accountsTable, err := getTableName(db, account)
+	if err != nil {
+		return err
+	}
+	peersTable, err := getTableName(db, peer)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if !tx.Migrator().HasColumn(account, "network_net_v6") {
+			if err := tx.Migrator().AddColumn(account, "network_net_v6"); err != nil {
+				return fmt.Errorf("add column network_net_v6: %w", err)
+			}
+		}
+		if !tx.Migrator().HasColumn(peer, "ipv6") {
+			if err := tx.Migrator().AddColumn(peer, "ipv6"); err != nil {
+				return fmt.Errorf("add column ipv6: %w", err)
+			}
+		}
+
+		var accountIDs []string
+		if err := tx.Table(accountsTable).Where("network_net_v6 IS NULL OR network_net_v6 = ''").Pluck("id", &accountIDs).Error; err != nil {
+			return fmt.Errorf("find accounts without IPv6 network: %w", err)
+		}
+
+		for _, accountID := range accountIDs {
+			netV6, err := json.Marshal(types.NewNetV6())
+			if err != nil {
+				return fmt.Errorf("marshal IPv6 network: %w", err)
+			}
+			if err := tx.Table(accountsTable).Where(idQueryCondition, accountID).Update("network_net_v6", string(netV6)).Error; err != nil {
+				return fmt.Errorf("update IPv6 network of account %s: %w", accountID, err)
+			}
+		}
+
+		var peers []struct {
+			ID        string
+			AccountID string
+		}
+		if err := tx.Table(peersTable).Select("id", "account_id").Where("ipv6 IS NULL OR ipv6 = ''").Find(&peers).Error; err != nil {
+			return fmt.Errorf("find peers without IPv6 address: %w", err)
+		}
+
+		networks := make(map[string]net.IPNet)
+		taken := make(map[string][]net.IP)
+		for _, p := range peers {
+			netV6, ok := networks[p.AccountID]
+			if !ok {
+				var netV6JSON string
+				if err := tx.Table(accountsTable).Where(idQueryCondition, p.AccountID).Pluck("network_net_v6", &netV6JSON).Error; err != nil {
+					return fmt.Errorf("get IPv6 network of account %s: %w", p.AccountID, err)
+				}
+				if err := json.Unmarshal([]byte(netV6JSON), &netV6); err != nil {
+					return fmt.Errorf("parse IPv6 network of account %s: %w", p.AccountID, err)
+				}
+				networks[p.AccountID] = netV6
+
+				var takenJSON []string
+				if err := tx.Table(peersTable).Where("account_id = ? AND ipv6 IS NOT NULL AND ipv6 <> ''", p.AccountID).Pluck("ipv6", &takenJSON).Error; err != nil {
+					return fmt.Errorf("get taken IPv6s of account %s: %w", p.AccountID, err)
+				}
+				for _, ipJSON := range takenJSON {
+					var ip net.IP
+					if err := json.Unmarshal([]byte(ipJSON), &ip); err == nil {
+						taken[p.AccountID] = append(taken[p.AccountID], ip)
+					}
+				}
+			}
+
+			ip, err := types.AllocatePeerIPv6(netV6, taken[p.AccountID])
+			if err != nil {
+				return fmt.Errorf("allocate IPv6 for peer %s: %w", p.ID, err)
+			}
+			taken[p.AccountID] = append(taken[p.AccountID], ip)
+
+			ipJSON, err := json.Marshal(ip)
+			if err != nil {
+				return fmt.Errorf("marshal IPv6 of peer %s: %w", p.ID, err)
+			}
+			if err := tx.Table(peersTable).Where(idQueryCondition, p.ID).Update("ipv6", string(ipJSON)).Error; err != nil {
+				return fmt.Errorf("update IPv6 of peer %s: %w", p.ID, err)
+			}
+		}
+
+		if len(accountIDs) > 0 || len(peers) > 0 {
+			log.WithContext(ctx).Infof("Migration of IPv6 overlay completed: %d accounts and %d peers updated", len(accountIDs), len(peers))
+		}
+
+		return nil
+	})
+}
+
+func getTableName(db *gorm.DB, model any) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", fmt.Errorf("parse model: %w", err)
 	}
+	return stmt.Schema.Table, nil
 }
 
 // NewTestStoreFromSQL is only used in tests. It will create a test database base of the store engine set in env.
@@ -73,6 +73,13 @@ type Account struct {
 	Networks         []*networkTypes.Network          `gorm:"foreignKey:AccountID;references:id"`
 	NetworkRouters   []*routerTypes.NetworkRouter     `gorm:"foreignKey:AccountID;references:id"`
 	NetworkResources []*resourceTypes.NetworkResource `gorm:"foreignKey:AccountID;references:id"`
+
+	// ParentAccountID is the account whose admins can manage this account, empty for standalone accounts
+	ParentAccountID string `gorm:"index"`
+	// PendingParentAccountID is the account the owner asked to be linked to, the link is made once its admins accept it
+	PendingParentAccountID string `gorm:"index"`
+
+	InventoryDevices []*InventoryDevice `gorm:"foreignKey:AccountID;references:id"`
 }
 
 // Subclass used in gorm to only load network and not whole account
@@ -237,7 +244,13 @@ func (a *Account) GetPeerNetworkMap(
 		}
 	}
 
-	aclPeers, firewallRules := a.GetPeerConnectionResources(ctx, peerID, validatedPeersMap)
+	inventory := a.GetDeviceInventory()
+	aclPeers, firewallRules := a.getPeerConnectionResources(ctx, peerID, validatedPeersMap, inventory)
+	postQuantumPeers := a.getPostQuantumRequiredPeers()
+	aclPeers = filterPostQuantumPeers(peer, aclPeers, postQuantumPeers)
+	if peer.SupportsIPv6() {
+		firewallRules = append(firewallRules, ipv6FirewallRules(firewallRules, aclPeers)...)
+	}
 	// exclude expired peers
 	var peersToConnect []*nbpeer.Peer
 	var expiredPeers []*nbpeer.Peer
@@ -251,13 +264,14 @@ func (a *Account) GetPeerNetworkMap(
 	}
 
 	routesUpdate := a.GetRoutesToSync(ctx, peerID, peersToConnect)
-	routesFirewallRules := a.GetPeerRoutesFirewallRules(ctx, peerID, validatedPeersMap)
-	isRouter, networkResourcesRoutes, sourcePeers := a.GetNetworkResourcesRoutesToSync(ctx, peerID, resourcePolicies, routers)
+	routesFirewallRules := a.getPeerRoutesFirewallRules(ctx, peerID, validatedPeersMap, inventory)
+	isRouter, networkResourcesRoutes, sourcePeers := a.getNetworkResourcesRoutesToSync(ctx, peerID, resourcePolicies, routers, inventory)
 	var networkResourcesFirewallRules []*RouteFirewallRule
 	if isRouter {
-		networkResourcesFirewallRules = a.GetPeerNetworkResourceFirewallRules(ctx, peer, validatedPeersMap, networkResourcesRoutes, resourcePolicies)
+		networkResourcesFirewallRules = a.getPeerNetworkResourceFirewallRules(ctx, peer, validatedPeersMap, networkResourcesRoutes, resourcePolicies, inventory)
 	}
 	peersToConnectIncludingRouters := a.addNetworksRoutingPeers(networkResourcesRoutes, peer, peersToConnect, expiredPeers, isRouter, sourcePeers)
+	peersToConnectIncludingRouters = filterPostQuantumPeers(peer, peersToConnectIncludingRouters, postQuantumPeers)
 
 	dnsManagementStatus := a.getPeerDNSManagementStatus(peerID)
 	dnsUpdate := nbdns.Config{
@@ -268,6 +282,9 @@ func (a *Account) GetPeerNetworkMap(
 		var zones []nbdns.CustomZone
 
 		if peersCustomZone.Domain != "" {
+			if !peer.SupportsIPv6() {
+				peersCustomZone = withoutAAAARecords(peersCustomZone)
+			}
 			zones = append(zones, peersCustomZone)
 		}
 		dnsUpdate.CustomZones = zones
@@ -282,6 +299,7 @@ func (a *Account) GetPeerNetworkMap(
 		OfflinePeers:        expiredPeers,
 		FirewallRules:       firewallRules,
 		RoutesFirewallRules: slices.Concat(networkResourcesFirewallRules, routesFirewallRules),
+		RosenpassRequired:   isPostQuantumRequired(peerID, postQuantumPeers),
 	}
 
 	if metrics != nil {
@@ -448,13 +466,7 @@ func (a *Account) GetPeersCustomZone(ctx context.Context, dnsDomain string) nbdn
 		sb.WriteString(peer.DNSLabel)
 		sb.WriteString(domainSuffix)
 
-		customZone.Records = append(customZone.Records, nbdns.SimpleRecord{
-			Name:  sb.String(),
-			Type:  int(dns.TypeA),
-			Class: nbdns.DefaultClass,
-			TTL:   defaultTTL,
-			RData: peer.IP.String(),
-		})
+		customZone.Records = appendPeerRecords(customZone.Records, sb.String(), peer)
 		sb.Reset()
 
 		for _, extraLabel := range peer.ExtraDNSLabels {
@@ -462,13 +474,7 @@ func (a *Account) GetPeersCustomZone(ctx context.Context, dnsDomain string) nbdn
 			sb.WriteString(extraLabel)
 			sb.WriteString(domainSuffix)
 
-			customZone.Records = append(customZone.Records, nbdns.SimpleRecord{
-				Name:  sb.String(),
-				Type:  int(dns.TypeA),
-				Class: nbdns.DefaultClass,
-				TTL:   defaultTTL,
-				RData: peer.IP.String(),
-			})
+			customZone.Records = appendPeerRecords(customZone.Records, sb.String(), peer)
 			sb.Reset()
 		}
 
@@ -483,6 +489,72 @@ func (a *Account) GetPeersCustomZone(ctx context.Context, dnsDomain string) nbdn
 	return customZone
 }
 
+// appendPeerRecords appends the A record and, if the peer supports the IPv6 overlay, the AAAA record of the peer
+func appendPeerRecords(records []nbdns.SimpleRecord, name string, peer *nbpeer.Peer) []nbdns.SimpleRecord {
+	records = append(records, nbdns.SimpleRecord{
+		Name:  name,
+		Type:  int(dns.TypeA),
+		Class: nbdns.DefaultClass,
+		TTL:   defaultTTL,
+		RData: peer.IP.String(),
+	})
+
+	if peer.SupportsIPv6() {
+		records = append(records, nbdns.SimpleRecord{
+			Name:  name,
+			Type:  int(dns.TypeAAAA),
+			Class: nbdns.DefaultClass,
+			TTL:   defaultTTL,
+			RData: peer.IPv6.String(),
+		})
+	}
+
+	return records
+}
+
+// withoutAAAARecords returns a copy of the zone without AAAA records for peers that can't use the IPv6 overlay
+func withoutAAAARecords(zone nbdns.CustomZone) nbdns.CustomZone {
+	hasAAAA := slices.ContainsFunc(zone.Records, func(record nbdns.SimpleRecord) bool {
+		return record.Type == int(dns.TypeAAAA)
+	})
+	if !hasAAAA {
+		return zone
+	}
+
+	records := make([]nbdns.SimpleRecord, 0, len(zone.Records))
+	for _, record := range zone.Records {
+		if record.Type == int(dns.TypeAAAA) {
+			continue
+		}
+		records = append(records, record)
+	}
+	zone.Records = records
+	return zone
+}
+
+// ipv6FirewallRules returns a copy of every rule targeting a peer with an IPv6 overlay address, with the peer's IPv6 address.
+// Rules matching all peers are applied to both address families by the clients and aren't copied.
+func ipv6FirewallRules(rules []*FirewallRule, peers []*nbpeer.Peer) []*FirewallRule {
+	ipv6ByIP := make(map[string]string, len(peers))
+	for _, p := range peers {
+		if p.SupportsIPv6() {
+			ipv6ByIP[p.IP.String()] = p.IPv6.String()
+		}
+	}
+
+	var v6Rules []*FirewallRule
+	for _, rule := range rules {
+		ipv6, ok := ipv6ByIP[rule.PeerIP]
+		if !ok {
+			continue
+		}
+		v6Rule := *rule
+		v6Rule.PeerIP = ipv6
+		v6Rules = append(v6Rules, &v6Rule)
+	}
+	return v6Rules
+}
+
 // GetExpiredPeers returns peers that have been expired
 func (a *Account) GetExpiredPeers() []*nbpeer.Peer {
 	var peers []*nbpeer.Peer
@@ -736,6 +808,53 @@ func (a *Account) getPeerDNSManagementStatus(peerID string) bool {
 	return enabled
 }
 
+// getPostQuantumRequiredPeers returns the IDs of the peers in the groups requiring the post-quantum key exchange
+func (a *Account) getPostQuantumRequiredPeers() map[string]struct{} {
+	if a.Settings == nil || len(a.Settings.PostQuantumRequiredGroups) == 0 {
+		return nil
+	}
+
+	peers := make(map[string]struct{})
+	for _, groupID := range a.Settings.PostQuantumRequiredGroups {
+		group, ok := a.Groups[groupID]
+		if !ok {
+			continue
+		}
+		for _, peerID := range group.Peers {
+			peers[peerID] = struct{}{}
+		}
+	}
+	return peers
+}
+
+// isPostQuantumRequired returns true if the peer has to refuse connections without the post-quantum key exchange
+func isPostQuantumRequired(peerID string, postQuantumPeers map[string]struct{}) bool {
+	_, ok := postQuantumPeers[peerID]
+	return ok
+}
+
+// filterPostQuantumPeers removes the peers the given peer can't connect to because one of them requires the
+// post-quantum key exchange and one of them doesn't have Rosenpass enabled
+func filterPostQuantumPeers(peer *nbpeer.Peer, peers []*nbpeer.Peer, postQuantumPeers map[string]struct{}) []*nbpeer.Peer {
+	if len(postQuantumPeers) == 0 {
+		return peers
+	}
+
+	required := isPostQuantumRequired(peer.ID, postQuantumPeers)
+	enabled := peer.Meta.Flags.RosenpassEnabled
+
+	filtered := make([]*nbpeer.Peer, 0, len(peers))
+	for _, p := range peers {
+		if required || isPostQuantumRequired(p.ID, postQuantumPeers) {
+			if !enabled || !p.Meta.Flags.RosenpassEnabled {
+				continue
+			}
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
 func (a *Account) GetPeerGroups(peerID string) LookupMap {
 	groupList := make(LookupMap)
 	for groupID, group := range a.Groups {
@@ -831,6 +950,11 @@ func (a *Account) Copy() *Account {
 		networkResources = append(networkResources, resource.Copy())
 	}
 
+	inventoryDevices := []*InventoryDevice{}
+	for _, device := range a.InventoryDevices {
+		inventoryDevices = append(inventoryDevices, device.Copy())
+	}
+
 	return &Account{
 		Id:                     a.Id,
 		CreatedBy:              a.CreatedBy,
@@ -852,6 +976,9 @@ func (a *Account) Copy() *Account {
 		Networks:               nets,
 		NetworkRouters:         networkRouters,
 		NetworkResources:       networkResources,
+		ParentAccountID:        a.ParentAccountID,
+		PendingParentAccountID: a.PendingParentAccountID,
+		InventoryDevices:       inventoryDevices,
 	}
 }
 
@@ -941,6 +1068,10 @@ func (a *Account) UserGroupsRemoveFromPeers(userID string, groups ...string) map
 //
 // This function returns the list of peers and firewall rules that are applicable to a given peer.
 func (a *Account) GetPeerConnectionResources(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}) ([]*nbpeer.Peer, []*FirewallRule) {
+	return a.getPeerConnectionResources(ctx, peerID, validatedPeersMap, a.GetDeviceInventory())
+}
+
+func (a *Account) getPeerConnectionResources(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}, inventory DeviceInventory) ([]*nbpeer.Peer, []*FirewallRule) {
 	generateResources, getAccumulatedResources := a.connResourcesGenerator(ctx)
 	for _, policy := range a.Policies {
 		if !policy.Enabled {
@@ -952,8 +1083,8 @@ func (a *Account) GetPeerConnectionResources(ctx context.Context, peerID string,
 				continue
 			}
 
-			sourcePeers, peerInSources := a.getAllPeersFromGroups(ctx, rule.Sources, peerID, policy.SourcePostureChecks, validatedPeersMap)
-			destinationPeers, peerInDestinations := a.getAllPeersFromGroups(ctx, rule.Destinations, peerID, nil, validatedPeersMap)
+			sourcePeers, peerInSources := a.getAllPeersFromGroups(ctx, rule.Sources, peerID, policy.SourcePostureChecks, validatedPeersMap, inventory)
+			destinationPeers, peerInDestinations := a.getAllPeersFromGroups(ctx, rule.Destinations, peerID, nil, validatedPeersMap, inventory)
 
 			if rule.Bidirectional {
 				if peerInSources {
@@ -1048,7 +1179,7 @@ func (a *Account) connResourcesGenerator(ctx context.Context) (func(*PolicyRule,
 //
 // Important: Posture checks are applicable only to source group peers,
 // for destination group peers, call this method with an empty list of sourcePostureChecksIDs
-func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, peerID string, sourcePostureChecksIDs []string, validatedPeersMap map[string]struct{}) ([]*nbpeer.Peer, bool) {
+func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, peerID string, sourcePostureChecksIDs []string, validatedPeersMap map[string]struct{}, inventory DeviceInventory) ([]*nbpeer.Peer, bool) {
 	peerInGroups := false
 	uniquePeerIDs := a.getUniquePeerIDsFromGroupsIDs(ctx, groups)
 	filteredPeers := make([]*nbpeer.Peer, 0, len(uniquePeerIDs))
@@ -1059,7 +1190,7 @@ func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, pe
 		}
 
 		// validate the peer based on policy posture checks applied
-		isValid := a.validatePostureChecksOnPeer(ctx, sourcePostureChecksIDs, peer.ID)
+		isValid := a.validatePostureChecksOnPeer(ctx, sourcePostureChecksIDs, peer.ID, inventory)
 		if !isValid {
 			continue
 		}
@@ -1079,8 +1210,8 @@ func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, pe
 	return filteredPeers, peerInGroups
 }
 
-// validatePostureChecksOnPeer validates the posture checks on a peer
-func (a *Account) validatePostureChecksOnPeer(ctx context.Context, sourcePostureChecksID []string, peerID string) bool {
+// validatePostureChecksOnPeer validates the posture checks on a peer, the device inventory checks against the given inventory
+func (a *Account) validatePostureChecksOnPeer(ctx context.Context, sourcePostureChecksID []string, peerID string, inventory DeviceInventory) bool {
 	peer, ok := a.Peers[peerID]
 	if !ok && peer == nil {
 		return false
@@ -1093,7 +1224,13 @@ func (a *Account) validatePostureChecksOnPeer(ctx context.Context, sourcePosture
 		}
 
 		for _, check := range postureChecks.GetChecks() {
-			isValid, err := check.Check(ctx, *peer)
+			var isValid bool
+			var err error
+			if inventoryCheck, ok := check.(*posture.DeviceInventoryCheck); ok {
+				isValid, err = inventoryCheck.CheckInventory(ctx, *peer, inventory)
+			} else {
+				isValid, err = check.Check(ctx, *peer)
+			}
 			if err != nil {
 				log.WithContext(ctx).Debugf("an error occurred check %s: on peer: %s :%s", check.Name(), peer.ID, err.Error())
 			}
@@ -1116,6 +1253,10 @@ func (a *Account) GetPostureChecks(postureChecksID string) *posture.Checks {
 
 // GetPeerRoutesFirewallRules gets the routes firewall rules associated with a routing peer ID for the account.
 func (a *Account) GetPeerRoutesFirewallRules(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}) []*RouteFirewallRule {
+	return a.getPeerRoutesFirewallRules(ctx, peerID, validatedPeersMap, a.GetDeviceInventory())
+}
+
+func (a *Account) getPeerRoutesFirewallRules(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}, inventory DeviceInventory) []*RouteFirewallRule {
 	routesFirewallRules := make([]*RouteFirewallRule, 0, len(a.Routes))
 
 	enabledRoutes, _ := a.getRoutingPeerRoutes(ctx, peerID)
@@ -1131,7 +1272,7 @@ func (a *Account) GetPeerRoutesFirewallRules(ctx context.Context, peerID string,
 
 		for _, accessGroup := range route.AccessControlGroups {
 			policies := GetAllRoutePoliciesFromGroups(a, []string{accessGroup})
-			rules := a.getRouteFirewallRules(ctx, peerID, policies, route, validatedPeersMap, distributionPeers)
+			rules := a.getRouteFirewallRules(ctx, peerID, policies, route, validatedPeersMap, distributionPeers, inventory)
 			routesFirewallRules = append(routesFirewallRules, rules...)
 		}
 	}
@@ -1139,7 +1280,7 @@ func (a *Account) GetPeerRoutesFirewallRules(ctx context.Context, peerID string,
 	return routesFirewallRules
 }
 
-func (a *Account) getRouteFirewallRules(ctx context.Context, peerID string, policies []*Policy, route *route.Route, validatedPeersMap map[string]struct{}, distributionPeers map[string]struct{}) []*RouteFirewallRule {
+func (a *Account) getRouteFirewallRules(ctx context.Context, peerID string, policies []*Policy, route *route.Route, validatedPeersMap map[string]struct{}, distributionPeers map[string]struct{}, inventory DeviceInventory) []*RouteFirewallRule {
 	var fwRules []*RouteFirewallRule
 	for _, policy := range policies {
 		if !policy.Enabled {
@@ -1151,7 +1292,7 @@ func (a *Account) getRouteFirewallRules(ctx context.Context, peerID string, poli
 				continue
 			}
 
-			rulePeers := a.getRulePeers(rule, policy.SourcePostureChecks, peerID, distributionPeers, validatedPeersMap)
+			rulePeers := a.getRulePeers(rule, policy.SourcePostureChecks, peerID, distributionPeers, validatedPeersMap, inventory)
 			rules := generateRouteFirewallRules(ctx, route, rule, rulePeers, FirewallRuleDirectionIN)
 			fwRules = append(fwRules, rules...)
 		}
@@ -1159,7 +1300,7 @@ func (a *Account) getRouteFirewallRules(ctx context.Context, peerID string, poli
 	return fwRules
 }
 
-func (a *Account) getRulePeers(rule *PolicyRule, postureChecks []string, peerID string, distributionPeers map[string]struct{}, validatedPeersMap map[string]struct{}) []*nbpeer.Peer {
+func (a *Account) getRulePeers(rule *PolicyRule, postureChecks []string, peerID string, distributionPeers map[string]struct{}, validatedPeersMap map[string]struct{}, inventory DeviceInventory) []*nbpeer.Peer {
 	distPeersWithPolicy := make(map[string]struct{})
 	for _, id := range rule.Sources {
 		group := a.Groups[id]
@@ -1173,7 +1314,7 @@ func (a *Account) getRulePeers(rule *PolicyRule, postureChecks []string, peerID
 			}
 			_, distPeer := distributionPeers[pID]
 			_, valid := validatedPeersMap[pID]
-			if distPeer && valid && a.validatePostureChecksOnPeer(context.Background(), postureChecks, pID) {
+			if distPeer && valid && a.validatePostureChecksOnPeer(context.Background(), postureChecks, pID, inventory) {
 				distPeersWithPolicy[pID] = struct{}{}
 			}
 		}
@@ -1261,6 +1402,10 @@ func GetAllRoutePoliciesFromGroups(account *Account, accessControlGroups []strin
 
 // GetPeerNetworkResourceFirewallRules gets the network resources firewall rules associated with a routing peer ID for the account.
 func (a *Account) GetPeerNetworkResourceFirewallRules(ctx context.Context, peer *nbpeer.Peer, validatedPeersMap map[string]struct{}, routes []*route.Route, resourcePolicies map[string][]*Policy) []*RouteFirewallRule {
+	return a.getPeerNetworkResourceFirewallRules(ctx, peer, validatedPeersMap, routes, resourcePolicies, a.GetDeviceInventory())
+}
+
+func (a *Account) getPeerNetworkResourceFirewallRules(ctx context.Context, peer *nbpeer.Peer, validatedPeersMap map[string]struct{}, routes []*route.Route, resourcePolicies map[string][]*Policy, inventory DeviceInventory) []*RouteFirewallRule {
 	routesFirewallRules := make([]*RouteFirewallRule, 0)
 
 	for _, route := range routes {
@@ -1270,7 +1415,11 @@ func (a *Account) GetPeerNetworkResourceFirewallRules(ctx context.Context, peer
 		resourceAppliedPolicies := resourcePolicies[route.GetResourceID()]
 		distributionPeers := getPoliciesSourcePeers(resourceAppliedPolicies, a.Groups)
 
-		rules := a.getRouteFirewallRules(ctx, peer.ID, resourceAppliedPolicies, route, validatedPeersMap, distributionPeers)
+		rules := a.getRouteFirewallRules(ctx, peer.ID, resourceAppliedPolicies, route, validatedPeersMap, distributionPeers, inventory)
+		if resource := a.getNetworkResource(route.GetResourceID()); resource != nil {
+			rules = restrictRulesToServices(rules, resource.Services)
+		}
+
 		for _, rule := range rules {
 			if len(rule.SourceRanges) > 0 {
 				routesFirewallRules = append(routesFirewallRules, rule)
@@ -1312,6 +1461,10 @@ func (a *Account) GetResourcePoliciesMap() map[string][]*Policy {
 
 // GetNetworkResourcesRoutesToSync returns network routes for syncing with a specific peer and its ACL peers.
 func (a *Account) GetNetworkResourcesRoutesToSync(ctx context.Context, peerID string, resourcePolicies map[string][]*Policy, routers map[string]map[string]*routerTypes.NetworkRouter) (bool, []*route.Route, map[string]struct{}) {
+	return a.getNetworkResourcesRoutesToSync(ctx, peerID, resourcePolicies, routers, a.GetDeviceInventory())
+}
+
+func (a *Account) getNetworkResourcesRoutesToSync(ctx context.Context, peerID string, resourcePolicies map[string][]*Policy, routers map[string]map[string]*routerTypes.NetworkRouter, inventory DeviceInventory) (bool, []*route.Route, map[string]struct{}) {
 	var isRoutingPeer bool
 	var routes []*route.Route
 	allSourcePeers := make(map[string]struct{}, len(a.Peers))
@@ -1335,10 +1488,10 @@ func (a *Account) GetNetworkResourcesRoutesToSync(ctx context.Context, peerID st
 		for _, policy := range resourcePolicies[resource.ID] {
 			peers := a.getUniquePeerIDsFromGroupsIDs(ctx, policy.SourceGroups())
 			if addSourcePeers {
-				for _, pID := range a.getPostureValidPeers(peers, policy.SourcePostureChecks) {
+				for _, pID := range a.getPostureValidPeers(peers, policy.SourcePostureChecks, inventory) {
 					allSourcePeers[pID] = struct{}{}
 				}
-			} else if slices.Contains(peers, peerID) && a.validatePostureChecksOnPeer(ctx, policy.SourcePostureChecks, peerID) {
+			} else if slices.Contains(peers, peerID) && a.validatePostureChecksOnPeer(ctx, policy.SourcePostureChecks, peerID, inventory) {
 				// add routes for the resource if the peer is in the distribution group
 				for peerId, router := range networkRoutingPeers {
 					routes = append(routes, a.getNetworkResourcesRoutes(resource, peerId, router, resourcePolicies)...)
@@ -1354,10 +1507,10 @@ func (a *Account) GetNetworkResourcesRoutesToSync(ctx context.Context, peerID st
 	return isRoutingPeer, routes, allSourcePeers
 }
 
-func (a *Account) getPostureValidPeers(inputPeers []string, postureChecksIDs []string) []string {
+func (a *Account) getPostureValidPeers(inputPeers []string, postureChecksIDs []string, inventory DeviceInventory) []string {
 	var dest []string
 	for _, peerID := range inputPeers {
-		if a.validatePostureChecksOnPeer(context.Background(), postureChecksIDs, peerID) {
+		if a.validatePostureChecksOnPeer(context.Background(), postureChecksIDs, peerID, inventory) {
 			dest = append(dest, peerID)
 		}
 	}
@@ -1390,6 +1543,16 @@ func (a *Account) getUniquePeerIDsFromGroupsIDs(ctx context.Context, groups []st
 	return ids
 }
 
+// getNetworkResource returns the network resource with the given ID or nil if it doesn't exist.
+func (a *Account) getNetworkResource(resourceID string) *resourceTypes.NetworkResource {
+	for _, resource := range a.NetworkResources {
+		if resource.ID == resourceID {
+			return resource
+		}
+	}
+	return nil
+}
+
 // getNetworkResources filters and returns a list of network resources associated with the given network ID.
 func (a *Account) getNetworkResources(networkID string) []*resourceTypes.NetworkResource {
 	var resources []*resourceTypes.NetworkResource
@@ -7,9 +7,11 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	nbdns "github.com/netbirdio/netbird/dns"
 	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
 	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
@@ -542,9 +544,10 @@ func getBasicAccountsWithResource() *Account {
 				Enabled:   true,
 				Rules: []*PolicyRule{
 					{
-						ID:      "rule1ID",
-						Enabled: true,
-						Sources: []string{group1ID},
+						ID:       "rule1ID",
+						PolicyID: "policy1ID",
+						Enabled:  true,
+						Sources:  []string{group1ID},
 						DestinationResource: Resource{
 							ID:   accNetResource1ID,
 							Type: "Host",
@@ -835,3 +838,380 @@ func Test_NetworksNetMapGenShouldExcludeOtherRouters(t *testing.T) {
 	assert.Len(t, networkResourcesRoutes, 1, "expected network resource route don't match")
 	assert.Len(t, sourcePeers, 2, "expected source peers don't match")
 }
+
+func Test_NetworksNetMapGenWithResourceServices(t *testing.T) {
+	account := getBasicAccountsWithResource()
+	account.Policies[0].Rules[0].Protocol = PolicyRuleProtocolALL
+	account.Policies[0].Rules[0].Ports = nil
+	account.Policies[0].Rules[0].Bidirectional = true
+	account.NetworkResources[0].Services = []resourceTypes.NetworkResourceService{
+		{
+			Protocol:   resourceTypes.ServiceProtocolTCP,
+			PortRanges: []resourceTypes.ServicePortRange{{Start: 5432, End: 5432}, {Start: 8000, End: 8080}},
+		},
+		{
+			Protocol: resourceTypes.ServiceProtocolICMP,
+		},
+	}
+
+	_, networkResourcesRoutes, _ := account.GetNetworkResourcesRoutesToSync(context.Background(), accNetResourceRouter1ID, account.GetResourcePoliciesMap(), account.GetResourceRoutersMap())
+	rules := account.GetPeerNetworkResourceFirewallRules(context.Background(), account.Peers[accNetResourceRouter1ID], accNetResourceValidPeers, networkResourcesRoutes, account.GetResourcePoliciesMap())
+	require.Len(t, rules, 3, "expected a rule per exposed service port range")
+
+	assert.Equal(t, "tcp", rules[0].Protocol)
+	assert.Equal(t, uint16(5432), rules[0].Port)
+	assert.Equal(t, "tcp", rules[1].Protocol)
+	assert.Equal(t, RulePortRange{Start: 8000, End: 8080}, rules[1].PortRange)
+	assert.Equal(t, "icmp", rules[2].Protocol)
+	assert.Equal(t, uint16(0), rules[2].Port)
+}
+
+func Test_NetworksNetMapGenResourceServicesLimitPolicyPorts(t *testing.T) {
+	account := getBasicAccountsWithResource()
+	account.Policies[0].Rules[0].Ports = nil
+	account.Policies[0].Rules[0].PortRanges = []RulePortRange{{Start: 1, End: 6000}}
+	account.NetworkResources[0].Services = []resourceTypes.NetworkResourceService{
+		{
+			Protocol:   resourceTypes.ServiceProtocolTCP,
+			PortRanges: []resourceTypes.ServicePortRange{{Start: 5432, End: 5432}, {Start: 8000, End: 8080}},
+		},
+		{
+			Protocol: resourceTypes.ServiceProtocolUDP,
+		},
+	}
+
+	_, networkResourcesRoutes, _ := account.GetNetworkResourcesRoutesToSync(context.Background(), accNetResourceRouter1ID, account.GetResourcePoliciesMap(), account.GetResourceRoutersMap())
+	rules := account.GetPeerNetworkResourceFirewallRules(context.Background(), account.Peers[accNetResourceRouter1ID], accNetResourceValidPeers, networkResourcesRoutes, account.GetResourcePoliciesMap())
+	require.Len(t, rules, 1, "only the tcp port shared by the policy and the resource should be allowed")
+	assert.Equal(t, "tcp", rules[0].Protocol)
+	assert.Equal(t, uint16(5432), rules[0].Port)
+	assert.Equal(t, RulePortRange{}, rules[0].PortRange)
+}
+
+func Test_GetPeerNetworkMapIPv6Overlay(t *testing.T) {
+	ipv6Capable := nbpeer.PeerSystemMeta{Capabilities: []nbpeer.Capability{nbpeer.CapabilityIPv6Overlay}}
+	network := NewNetwork()
+
+	newPeer := func(id string, ip string, ipv6 string, meta nbpeer.PeerSystemMeta) *nbpeer.Peer {
+		return &nbpeer.Peer{
+			ID:        id,
+			AccountID: "accountID",
+			Key:       id + "Key",
+			DNSLabel:  id,
+			IP:        net.ParseIP(ip),
+			IPv6:      net.ParseIP(ipv6),
+			Meta:      meta,
+			Status:    &nbpeer.PeerStatus{},
+		}
+	}
+
+	account := &Account{
+		Id:      "accountID",
+		Network: network,
+		Peers: map[string]*nbpeer.Peer{
+			"peerA": newPeer("peerA", "100.64.0.1", "fd00:1234::1", ipv6Capable),
+			"peerB": newPeer("peerB", "100.64.0.2", "fd00:1234::2", ipv6Capable),
+			"peerC": newPeer("peerC", "100.64.0.3", "fd00:1234::3", nbpeer.PeerSystemMeta{}),
+			"peerD": newPeer("peerD", "100.64.0.4", "fd00:1234::4", ipv6Capable),
+		},
+		Groups: map[string]*Group{
+			"groupAll": {ID: "groupAll", Name: "All", Peers: []string{"peerA", "peerB", "peerC", "peerD"}},
+			"group1":   {ID: "group1", Name: "group1", Peers: []string{"peerA", "peerB", "peerC"}},
+		},
+		Policies: []*Policy{
+			{
+				ID:      "policy1",
+				Enabled: true,
+				Rules: []*PolicyRule{
+					{
+						ID:            "rule1",
+						Enabled:       true,
+						Sources:       []string{"group1"},
+						Destinations:  []string{"group1"},
+						Bidirectional: true,
+						Protocol:      PolicyRuleProtocolTCP,
+						Ports:         []string{"22"},
+						Action:        PolicyTrafficActionAccept,
+					},
+				},
+			},
+		},
+		Settings: &Settings{},
+	}
+
+	validatedPeers := map[string]struct{}{"peerA": {}, "peerB": {}, "peerC": {}, "peerD": {}}
+	zone := account.GetPeersCustomZone(context.Background(), "netbird.cloud")
+
+	var aaaaRecords []string
+	for _, record := range zone.Records {
+		if record.Type == int(dns.TypeAAAA) {
+			aaaaRecords = append(aaaaRecords, record.RData)
+		}
+	}
+	assert.ElementsMatch(t, []string{"fd00:1234::1", "fd00:1234::2", "fd00:1234::4"}, aaaaRecords, "only IPv6 capable peers should have AAAA records")
+
+	ruleIPs := func(nm *NetworkMap) []string {
+		var ips []string
+		for _, rule := range nm.FirewallRules {
+			if !slices.Contains(ips, rule.PeerIP) {
+				ips = append(ips, rule.PeerIP)
+			}
+		}
+		return ips
+	}
+
+	nmA := account.GetPeerNetworkMap(context.Background(), "peerA", zone, validatedPeers, nil, nil, nil)
+	assert.ElementsMatch(t, []string{"100.64.0.2", "fd00:1234::2", "100.64.0.3"}, ruleIPs(nmA), "IPv6 rules should only be added for IPv6 capable peers")
+	require.Len(t, nmA.DNSConfig.CustomZones, 1)
+	assert.Len(t, nmA.DNSConfig.CustomZones[0].Records, 7)
+
+	nmC := account.GetPeerNetworkMap(context.Background(), "peerC", zone, validatedPeers, nil, nil, nil)
+	assert.ElementsMatch(t, []string{"100.64.0.1", "100.64.0.2"}, ruleIPs(nmC), "peers without IPv6 support should not get IPv6 rules")
+	require.Len(t, nmC.DNSConfig.CustomZones, 1)
+	for _, record := range nmC.DNSConfig.CustomZones[0].Records {
+		assert.NotEqual(t, int(dns.TypeAAAA), record.Type, "peers without IPv6 support should not get AAAA records")
+	}
+}
+
+func Test_GetPeerNetworkMapPostQuantumRequired(t *testing.T) {
+	account := &Account{
+		Id:      "accountID",
+		Network: NewNetwork(),
+		Peers: map[string]*nbpeer.Peer{
+			"peerA": {ID: "peerA", AccountID: "accountID", Key: "peerAKey", IP: net.ParseIP("100.64.0.1"), Status: &nbpeer.PeerStatus{}},
+			"peerB": {ID: "peerB", AccountID: "accountID", Key: "peerBKey", IP: net.ParseIP("100.64.0.2"), Status: &nbpeer.PeerStatus{}},
+		},
+		Groups: map[string]*Group{
+			"groupAll": {ID: "groupAll", Name: "All", Peers: []string{"peerA", "peerB"}},
+			"servers":  {ID: "servers", Name: "servers", Peers: []string{"peerA"}},
+		},
+		Settings: &Settings{},
+	}
+	validatedPeers := map[string]struct{}{"peerA": {}, "peerB": {}}
+
+	nm := account.GetPeerNetworkMap(context.Background(), "peerA", nbdns.CustomZone{}, validatedPeers, nil, nil, nil)
+	assert.False(t, nm.RosenpassRequired, "post-quantum shouldn't be required without required groups")
+
+	account.Settings.PostQuantumRequiredGroups = []string{"servers"}
+
+	nm = account.GetPeerNetworkMap(context.Background(), "peerA", nbdns.CustomZone{}, validatedPeers, nil, nil, nil)
+	assert.True(t, nm.RosenpassRequired, "post-quantum should be required for peers in the required groups")
+
+	nm = account.GetPeerNetworkMap(context.Background(), "peerB", nbdns.CustomZone{}, validatedPeers, nil, nil, nil)
+	assert.False(t, nm.RosenpassRequired, "post-quantum shouldn't be required for peers outside the required groups")
+}
+
+func Test_GetPeerNetworkMapPostQuantumPeers(t *testing.T) {
+	rosenpassMeta := nbpeer.PeerSystemMeta{Flags: nbpeer.Flags{RosenpassEnabled: true}}
+	account := &Account{
+		Id:      "accountID",
+		Network: NewNetwork(),
+		Peers: map[string]*nbpeer.Peer{
+			"server":  {ID: "server", AccountID: "accountID", Key: "serverKey", IP: net.ParseIP("100.64.0.1"), Meta: rosenpassMeta, Status: &nbpeer.PeerStatus{}},
+			"pqPeer":  {ID: "pqPeer", AccountID: "accountID", Key: "pqPeerKey", IP: net.ParseIP("100.64.0.2"), Meta: rosenpassMeta, Status: &nbpeer.PeerStatus{}},
+			"plain":   {ID: "plain", AccountID: "accountID", Key: "plainKey", IP: net.ParseIP("100.64.0.3"), Status: &nbpeer.PeerStatus{}},
+			"plainPQ": {ID: "plainPQ", AccountID: "accountID", Key: "plainPQKey", IP: net.ParseIP("100.64.0.4"), Status: &nbpeer.PeerStatus{}},
+		},
+		Groups: map[string]*Group{
+			"groupAll": {ID: "groupAll", Name: "All", Peers: []string{"server", "pqPeer", "plain", "plainPQ"}},
+		},
+		Policies: []*Policy{
+			{
+				ID:        "policyAll",
+				AccountID: "accountID",
+				Enabled:   true,
+				Rules: []*PolicyRule{
+					{
+						ID:            "ruleAll",
+						Enabled:       true,
+						Action:        PolicyTrafficActionAccept,
+						Bidirectional: true,
+						Protocol:      PolicyRuleProtocolALL,
+						Sources:       []string{"groupAll"},
+						Destinations:  []string{"groupAll"},
+					},
+				},
+			},
+		},
+		Settings: &Settings{},
+	}
+	validatedPeers := map[string]struct{}{"server": {}, "pqPeer": {}, "plain": {}, "plainPQ": {}}
+
+	peerIDs := func(peerID string) []string {
+		nm := account.GetPeerNetworkMap(context.Background(), peerID, nbdns.CustomZone{}, validatedPeers, nil, nil, nil)
+		var ids []string
+		for _, p := range nm.Peers {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"pqPeer", "plain", "plainPQ"}, peerIDs("server"), "all peers should connect without required groups")
+
+	account.Groups["servers"] = &Group{ID: "servers", Name: "servers", Peers: []string{"server", "plainPQ"}}
+	account.Settings.PostQuantumRequiredGroups = []string{"servers"}
+
+	assert.ElementsMatch(t, []string{"pqPeer"}, peerIDs("server"), "peers without Rosenpass should be filtered for a peer requiring post-quantum")
+	assert.ElementsMatch(t, []string{"server", "plain"}, peerIDs("pqPeer"), "a Rosenpass peer should connect to Rosenpass peers requiring post-quantum")
+	assert.ElementsMatch(t, []string{"pqPeer"}, peerIDs("plain"), "peers requiring post-quantum should be filtered for a peer without Rosenpass")
+	assert.Empty(t, peerIDs("plainPQ"), "a peer requiring post-quantum without Rosenpass can't connect to any peer")
+}
+
+func Test_GetPeerNetworkMapDeviceInventoryCheck(t *testing.T) {
+	account := &Account{
+		Id:      "accountID",
+		Network: NewNetwork(),
+		Peers: map[string]*nbpeer.Peer{
+			"server":    {ID: "server", AccountID: "accountID", Key: "serverKey", IP: net.ParseIP("100.64.0.1"), Status: &nbpeer.PeerStatus{}},
+			"inventory": {ID: "inventory", AccountID: "accountID", Key: "inventoryKey", UserID: "user1", IP: net.ParseIP("100.64.0.2"), Meta: nbpeer.PeerSystemMeta{SystemSerialNumber: "sn-1"}, Status: &nbpeer.PeerStatus{}},
+			"unknown":   {ID: "unknown", AccountID: "accountID", Key: "unknownKey", IP: net.ParseIP("100.64.0.3"), Meta: nbpeer.PeerSystemMeta{SystemSerialNumber: "SN-2"}, Status: &nbpeer.PeerStatus{}},
+		},
+		Groups: map[string]*Group{
+			"clients": {ID: "clients", Name: "clients", Peers: []string{"inventory", "unknown"}},
+			"servers": {ID: "servers", Name: "servers", Peers: []string{"server"}},
+		},
+		Policies: []*Policy{
+			{
+				ID:                  "policy",
+				AccountID:           "accountID",
+				Enabled:             true,
+				SourcePostureChecks: []string{"inventoryCheck"},
+				Rules: []*PolicyRule{
+					{
+						ID:           "rule",
+						Enabled:      true,
+						Action:       PolicyTrafficActionAccept,
+						Protocol:     PolicyRuleProtocolALL,
+						Sources:      []string{"clients"},
+						Destinations: []string{"servers"},
+					},
+				},
+			},
+		},
+		PostureChecks: []*posture.Checks{
+			{ID: "inventoryCheck", Checks: posture.ChecksDefinition{DeviceInventoryCheck: &posture.DeviceInventoryCheck{RequireOwnerMatch: true}}},
+		},
+		InventoryDevices: []*InventoryDevice{{SerialNumber: "SN-1", UserID: "user1"}},
+		Settings:         &Settings{},
+	}
+	validatedPeers := map[string]struct{}{"server": {}, "inventory": {}, "unknown": {}}
+
+	nm := account.GetPeerNetworkMap(context.Background(), "server", nbdns.CustomZone{}, validatedPeers, nil, nil, nil)
+	var ids []string
+	for _, p := range nm.Peers {
+		ids = append(ids, p.ID)
+	}
+	assert.ElementsMatch(t, []string{"inventory"}, ids, "only the peers on inventoried devices should pass the posture check")
+}
+
+func Test_SimulatePolicyNetworkResource(t *testing.T) {
+	validatedPeers := map[string]struct{}{accNetResourcePeer1ID: {}, accNetResourcePeer2ID: {}, accNetResourceRouter1ID: {}}
+
+	tests := []struct {
+		name          string
+		modify        func(account *Account)
+		request       PolicySimulationRequest
+		allowed       bool
+		matchedRules  []PolicySimulationRuleMatch
+		routingPeers  []string
+		failedChecks  []string
+		expectReasons bool
+	}{
+		{
+			name:         "allowed port",
+			request:      PolicySimulationRequest{SourcePeerID: accNetResourcePeer1ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 80},
+			allowed:      true,
+			matchedRules: []PolicySimulationRuleMatch{{PolicyID: "policy1ID", RuleID: "rule1ID", Action: PolicyTrafficActionAccept}},
+			routingPeers: []string{accNetResourceRouter1ID},
+		},
+		{
+			name:         "resolved by IP",
+			request:      PolicySimulationRequest{SourcePeerID: accNetResourcePeer2ID, DestinationIP: netip.MustParseAddr("10.10.10.20"), Protocol: PolicyRuleProtocolTCP, Port: 80},
+			allowed:      true,
+			matchedRules: []PolicySimulationRuleMatch{{PolicyID: "policy1ID", RuleID: "rule1ID", Action: PolicyTrafficActionAccept}},
+			routingPeers: []string{accNetResourceRouter1ID},
+		},
+		{
+			name:          "port not allowed",
+			request:       PolicySimulationRequest{SourcePeerID: accNetResourcePeer1ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 443},
+			expectReasons: true,
+		},
+		{
+			name: "failed posture check",
+			modify: func(account *Account) {
+				account.Policies[0].SourcePostureChecks = []string{accNetResourceLockedPostureCheckID}
+			},
+			request:       PolicySimulationRequest{SourcePeerID: accNetResourcePeer1ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 80},
+			failedChecks:  []string{accNetResourceLockedPostureCheckID},
+			expectReasons: true,
+		},
+		{
+			name: "disabled resource",
+			modify: func(account *Account) {
+				account.NetworkResources[0].Enabled = false
+			},
+			request:       PolicySimulationRequest{SourcePeerID: accNetResourcePeer1ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 80},
+			expectReasons: true,
+		},
+		{
+			name: "disabled router",
+			modify: func(account *Account) {
+				account.NetworkRouters[0].Enabled = false
+			},
+			request:       PolicySimulationRequest{SourcePeerID: accNetResourcePeer1ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 80},
+			expectReasons: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := getBasicAccountsWithResource()
+			if tt.modify != nil {
+				tt.modify(account)
+			}
+
+			result, err := account.SimulatePolicy(context.Background(), tt.request, validatedPeers)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.allowed, result.Allowed)
+			assert.Equal(t, SimulationDestinationResource, result.DestinationType)
+			assert.Equal(t, accNetResource1ID, result.DestinationID)
+			assert.Equal(t, tt.matchedRules, result.MatchedRules)
+			assert.Equal(t, tt.routingPeers, result.RoutingPeers)
+			assert.Equal(t, tt.failedChecks, result.FailedPostureChecks)
+			assert.Equal(t, tt.expectReasons, len(result.Reasons) > 0, "reasons: %v", result.Reasons)
+		})
+	}
+}
+
+func Test_SimulatePolicyPeer(t *testing.T) {
+	account := setupTestAccount()
+	validatedPeers := make(map[string]struct{})
+	for peerID := range account.Peers {
+		validatedPeers[peerID] = struct{}{}
+	}
+
+	result, err := account.SimulatePolicy(context.Background(), PolicySimulationRequest{
+		SourcePeerID:      "peer1",
+		DestinationPeerID: "peer1",
+		Protocol:          PolicyRuleProtocolALL,
+	}, validatedPeers)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, SimulationDestinationPeer, result.DestinationType)
+
+	_, err = account.SimulatePolicy(context.Background(), PolicySimulationRequest{
+		SourcePeerID:      "unknown",
+		DestinationPeerID: "peer1",
+		Protocol:          PolicyRuleProtocolALL,
+	}, validatedPeers)
+	require.Error(t, err)
+
+	_, err = account.SimulatePolicy(context.Background(), PolicySimulationRequest{
+		SourcePeerID:      "peer1",
+		DestinationPeerID: "peer2",
+		Protocol:          PolicyRuleProtocolICMP,
+		Port:              80,
+	}, validatedPeers)
+	require.Error(t, err, "port should be rejected for icmp")
+}
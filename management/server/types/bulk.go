@@ -0,0 +1,121 @@
+package types
+
+import (
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+// MaxBulkItems is the maximum number of objects a single bulk operation can change
+const MaxBulkItems = 1000
+
+type PeerBulkAction string
+
+const (
+	PeerBulkActionDelete             PeerBulkAction = "delete"
+	PeerBulkActionApprove            PeerBulkAction = "approve"
+	PeerBulkActionAssignGroups       PeerBulkAction = "assign_groups"
+	PeerBulkActionUnassignGroups     PeerBulkAction = "unassign_groups"
+	PeerBulkActionSetLoginExpiration PeerBulkAction = "set_login_expiration"
+	PeerBulkActionSetSSH             PeerBulkAction = "set_ssh"
+)
+
+// PeerBulkOperation is a single action applied to a list of peers
+type PeerBulkOperation struct {
+	Action  PeerBulkAction
+	PeerIDs []string
+	// GroupIDs are the groups the peers are assigned to or unassigned from
+	GroupIDs []string
+	// Enabled is the new value of the setting changed by the set_login_expiration and set_ssh actions
+	Enabled bool
+	// Reason is recorded in the activity log of the approve action
+	Reason string
+}
+
+// Validate checks the action and the number of peers and deduplicates the peer IDs keeping their order
+func (o *PeerBulkOperation) Validate() error {
+	switch o.Action {
+	case PeerBulkActionDelete, PeerBulkActionApprove, PeerBulkActionSetLoginExpiration, PeerBulkActionSetSSH:
+	case PeerBulkActionAssignGroups, PeerBulkActionUnassignGroups:
+		if len(o.GroupIDs) == 0 {
+			return status.Errorf(status.InvalidArgument, "action %s requires at least one group", o.Action)
+		}
+	default:
+		return status.Errorf(status.InvalidArgument, "unknown bulk action %q", o.Action)
+	}
+
+	var err error
+	o.PeerIDs, err = validateBulkIDs(o.PeerIDs)
+	return err
+}
+
+type SetupKeyBulkAction string
+
+const (
+	SetupKeyBulkActionRevoke SetupKeyBulkAction = "revoke"
+	SetupKeyBulkActionDelete SetupKeyBulkAction = "delete"
+)
+
+// SetupKeyBulkOperation is a single action applied to a list of setup keys
+type SetupKeyBulkOperation struct {
+	Action SetupKeyBulkAction
+	KeyIDs []string
+}
+
+// Validate checks the action and the number of keys and deduplicates the key IDs keeping their order
+func (o *SetupKeyBulkOperation) Validate() error {
+	switch o.Action {
+	case SetupKeyBulkActionRevoke, SetupKeyBulkActionDelete:
+	default:
+		return status.Errorf(status.InvalidArgument, "unknown bulk action %q", o.Action)
+	}
+
+	var err error
+	o.KeyIDs, err = validateBulkIDs(o.KeyIDs)
+	return err
+}
+
+// BulkItemResult is the outcome of a bulk operation for a single object, Err is nil when it succeeded
+type BulkItemResult struct {
+	ID  string
+	Err error
+}
+
+// ToBulkOperationResponse converts the results of a bulk operation to the API response
+func ToBulkOperationResponse(results []*BulkItemResult) *api.BulkOperationResponse {
+	response := &api.BulkOperationResponse{Results: make([]api.BulkOperationItemResult, 0, len(results))}
+	for _, result := range results {
+		item := api.BulkOperationItemResult{Id: result.ID, Status: api.BulkOperationItemResultStatusSuccess}
+		if result.Err != nil {
+			item.Status = api.BulkOperationItemResultStatusFailed
+			errMsg := result.Err.Error()
+			item.Error = &errMsg
+		}
+		response.Results = append(response.Results, item)
+	}
+	return response
+}
+
+func validateBulkIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "no objects provided")
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return nil, status.Errorf(status.InvalidArgument, "object ID can't be empty")
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	if len(unique) > MaxBulkItems {
+		return nil, status.Errorf(status.InvalidArgument, "a bulk operation can change at most %d objects", MaxBulkItems)
+	}
+
+	return unique, nil
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+)
+
+// ChildAccount is an account managed by the admins of its parent account
+type ChildAccount struct {
+	ID        string
+	Domain    string
+	CreatedBy string
+	CreatedAt time.Time
+	// Pending is set when the account asked to be linked and the parent account admins haven't accepted it yet
+	Pending bool `gorm:"-"`
+}
+
+// ChildAccountTemplates are the objects of a parent account copied to its child accounts.
+// Objects are matched to the child objects by name, existing objects with the same name are replaced.
+type ChildAccountTemplates struct {
+	PolicyIDs          []string
+	PostureCheckIDs    []string
+	NameServerGroupIDs []string
+	// ChildAccountIDs limits the child accounts the templates are pushed to, empty means all of them
+	ChildAccountIDs []string
+}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+// MaxInventoryImportDevices is the maximum number of devices a single inventory import can contain
+const MaxInventoryImportDevices = 10000
+
+// inventoryCSVColumns are the columns of an inventory CSV import, serial_number is the only required one
+var inventoryCSVColumns = []string{"serial_number", "manufacturer", "product_name", "user_id", "description"}
+
+// InventoryDevice is a device of the account inventory, identified by the system serial number reported by the peer
+type InventoryDevice struct {
+	ID           string `gorm:"primaryKey"`
+	AccountID    string `json:"-" gorm:"uniqueIndex:idx_inventory_devices_account_serial"`
+	SerialNumber string `gorm:"uniqueIndex:idx_inventory_devices_account_serial"`
+	Manufacturer string
+	ProductName  string
+	// UserID is the owner of the device, empty for devices without an owner
+	UserID      string
+	Description string
+	CreatedAt   time.Time
+}
+
+// NewInventoryDevice creates a new inventory device with a generated ID
+func NewInventoryDevice(accountID, serialNumber, manufacturer, productName, userID, description string) *InventoryDevice {
+	return &InventoryDevice{
+		ID:           xid.New().String(),
+		AccountID:    accountID,
+		SerialNumber: NormalizeSerialNumber(serialNumber),
+		Manufacturer: manufacturer,
+		ProductName:  productName,
+		UserID:       userID,
+		Description:  description,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+// NormalizeSerialNumber returns the serial number in the form it is stored and matched in the inventory
+func NormalizeSerialNumber(serialNumber string) string {
+	return strings.ToUpper(strings.TrimSpace(serialNumber))
+}
+
+// Validate checks the device has a serial number
+func (d *InventoryDevice) Validate() error {
+	if d.SerialNumber == "" {
+		return status.Errorf(status.InvalidArgument, "device serial number can't be empty")
+	}
+	return nil
+}
+
+// Copy returns a copy of the inventory device
+func (d *InventoryDevice) Copy() *InventoryDevice {
+	device := *d
+	return &device
+}
+
+// EventMeta returns activity event meta related to the inventory device
+func (d *InventoryDevice) EventMeta() map[string]any {
+	return map[string]any{"serial_number": d.SerialNumber, "manufacturer": d.Manufacturer, "product_name": d.ProductName}
+}
+
+// ParseInventoryCSV parses an inventory import in CSV format. The first row is the header naming the columns,
+// serial_number is required and manufacturer, product_name, user_id and description are optional.
+func ParseInventoryCSV(r io.Reader) ([]*InventoryDevice, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, status.Errorf(status.InvalidArgument, "inventory CSV is empty")
+	}
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid inventory CSV: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(inventoryCSVColumns, name) {
+			return nil, status.Errorf(status.InvalidArgument, "unknown inventory CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["serial_number"]; !ok {
+		return nil, status.Errorf(status.InvalidArgument, "inventory CSV requires a serial_number column")
+	}
+
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var devices []*InventoryDevice
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid inventory CSV: %v", err)
+		}
+
+		if len(devices) == MaxInventoryImportDevices {
+			return nil, status.Errorf(status.InvalidArgument, "an inventory import can contain at most %d devices", MaxInventoryImportDevices)
+		}
+
+		devices = append(devices, &InventoryDevice{
+			SerialNumber: NormalizeSerialNumber(column(record, "serial_number")),
+			Manufacturer: column(record, "manufacturer"),
+			ProductName:  column(record, "product_name"),
+			UserID:       column(record, "user_id"),
+			Description:  column(record, "description"),
+		})
+	}
+
+	if len(devices) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "inventory CSV has no devices")
+	}
+
+	return devices, nil
+}
+
+// InventoryImportSummary counts the outcome of an inventory import
+type InventoryImportSummary struct {
+	Created int
+	Updated int
+	Results []*BulkItemResult
+}
+
+// EventMeta returns activity event meta related to the inventory import
+func (s *InventoryImportSummary) EventMeta() map[string]any {
+	return map[string]any{"created": s.Created, "updated": s.Updated, "failed": s.Failed()}
+}
+
+// Failed returns the number of devices that couldn't be imported
+func (s *InventoryImportSummary) Failed() int {
+	var failed int
+	for _, result := range s.Results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// DeviceInventory maps the serial numbers of the account inventory to the device owners. It is built once per
+// network map calculation, so the device inventory posture checks don't scan the inventory for every peer.
+type DeviceInventory map[string]string
+
+// GetDeviceInventory returns the device inventory of the account indexed by serial number
+func (a *Account) GetDeviceInventory() DeviceInventory {
+	inventory := make(DeviceInventory, len(a.InventoryDevices))
+	for _, device := range a.InventoryDevices {
+		inventory[device.SerialNumber] = device.UserID
+	}
+	return inventory
+}
+
+// DeviceOwner returns the owner of the inventoried device with the serial number and whether the device is inventoried
+func (i DeviceInventory) DeviceOwner(serialNumber string) (string, bool) {
+	serialNumber = NormalizeSerialNumber(serialNumber)
+	if serialNumber == "" {
+		return "", false
+	}
+
+	owner, ok := i[serialNumber]
+	return owner, ok
+}
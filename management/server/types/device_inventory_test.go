@@ -0,0 +1,55 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInventoryCSV(t *testing.T) {
+	csv := "Serial_Number, manufacturer,user_id\n sn-1 ,Lenovo,user1\nSN-2,,\n"
+	devices, err := ParseInventoryCSV(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, &InventoryDevice{SerialNumber: "SN-1", Manufacturer: "Lenovo", UserID: "user1"}, devices[0])
+	assert.Equal(t, &InventoryDevice{SerialNumber: "SN-2"}, devices[1])
+
+	invalid := []string{
+		"",
+		"serial_number\n",
+		"manufacturer\nLenovo\n",
+		"serial_number,color\nSN-1,red\n",
+		"serial_number,manufacturer\nSN-1\n",
+	}
+	for _, csv := range invalid {
+		_, err = ParseInventoryCSV(strings.NewReader(csv))
+		assert.Error(t, err, "csv %q should be rejected", csv)
+	}
+}
+
+func TestDeviceInventory_DeviceOwner(t *testing.T) {
+	account := &Account{
+		InventoryDevices: []*InventoryDevice{
+			{SerialNumber: "SN-1", UserID: "user1"},
+			{SerialNumber: "SN-2"},
+		},
+	}
+
+	inventory := account.GetDeviceInventory()
+
+	owner, ok := inventory.DeviceOwner(" sn-1")
+	assert.True(t, ok)
+	assert.Equal(t, "user1", owner)
+
+	owner, ok = inventory.DeviceOwner("SN-2")
+	assert.True(t, ok)
+	assert.Empty(t, owner)
+
+	_, ok = inventory.DeviceOwner("SN-3")
+	assert.False(t, ok)
+
+	_, ok = inventory.DeviceOwner("")
+	assert.False(t, ok)
+}
@@ -9,6 +9,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	nbroute "github.com/netbirdio/netbird/route"
 )
@@ -141,3 +142,82 @@ func generateRulesWithPorts(ctx context.Context, baseRule RouteFirewallRule, rul
 func generateRuleIDBase(rule *PolicyRule, baseRule RouteFirewallRule) string {
 	return rule.ID + strings.Join(baseRule.SourceRanges, ",") + strconv.Itoa(FirewallRuleDirectionIN) + baseRule.Protocol + baseRule.Action
 }
+
+// restrictRulesToServices intersects the route firewall rules with the services exposed by a network resource,
+// so that a policy can't grant access to protocols or ports the resource doesn't expose.
+// Rules are returned unchanged if the resource doesn't declare any services.
+func restrictRulesToServices(rules []*RouteFirewallRule, services []resourceTypes.NetworkResourceService) []*RouteFirewallRule {
+	if len(services) == 0 {
+		return rules
+	}
+
+	restricted := make([]*RouteFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		for _, service := range services {
+			restricted = append(restricted, intersectRuleWithService(rule, service)...)
+		}
+	}
+
+	return restricted
+}
+
+// intersectRuleWithService returns the part of the rule matching the given service, split per service port range.
+func intersectRuleWithService(rule *RouteFirewallRule, service resourceTypes.NetworkResourceService) []*RouteFirewallRule {
+	protocol, ok := intersectProtocols(PolicyRuleProtocolType(rule.Protocol), PolicyRuleProtocolType(service.Protocol))
+	if !ok {
+		return nil
+	}
+
+	if len(service.PortRanges) == 0 {
+		r := *rule
+		r.Protocol = string(protocol)
+		return []*RouteFirewallRule{&r}
+	}
+
+	var ruleStart, ruleEnd uint16
+	switch {
+	case rule.Port != 0:
+		ruleStart, ruleEnd = rule.Port, rule.Port
+	case rule.PortRange.Start != 0:
+		ruleStart, ruleEnd = rule.PortRange.Start, rule.PortRange.End
+	}
+
+	rules := make([]*RouteFirewallRule, 0, len(service.PortRanges))
+	for _, portRange := range service.PortRanges {
+		start, end := portRange.Start, portRange.End
+		if ruleStart != 0 {
+			start = max(start, ruleStart)
+			end = min(end, ruleEnd)
+		}
+		if start > end {
+			continue
+		}
+
+		r := *rule
+		r.Protocol = string(protocol)
+		r.Port = 0
+		r.PortRange = RulePortRange{}
+		if start == end {
+			r.Port = start
+		} else {
+			r.PortRange = RulePortRange{Start: start, End: end}
+		}
+		rules = append(rules, &r)
+	}
+
+	return rules
+}
+
+// intersectProtocols returns the narrower of two protocols, or false if they don't overlap.
+func intersectProtocols(a, b PolicyRuleProtocolType) (PolicyRuleProtocolType, bool) {
+	switch {
+	case a == b:
+		return a, true
+	case a == PolicyRuleProtocolALL:
+		return b, true
+	case b == PolicyRuleProtocolALL:
+		return a, true
+	default:
+		return "", false
+	}
+}
@@ -31,6 +31,9 @@ type Group struct {
 	// Resources contains a list of resources in that group
 	Resources []Resource `gorm:"serializer:json"`
 
+	// Query defines the peers of a dynamic group, Peers is evaluated from it and can't be edited
+	Query *GroupQuery `gorm:"serializer:json"`
+
 	IntegrationReference integration_reference.IntegrationReference `gorm:"embedded;embeddedPrefix:integration_ref_"`
 }
 
@@ -50,6 +53,7 @@ func (g *Group) Copy() *Group {
 		Issued:               g.Issued,
 		Peers:                make([]string, len(g.Peers)),
 		Resources:            make([]Resource, len(g.Resources)),
+		Query:                g.Query.Copy(),
 		IntegrationReference: g.IntegrationReference,
 	}
 	copy(group.Peers, g.Peers)
@@ -62,6 +66,11 @@ func (g *Group) HasPeers() bool {
 	return len(g.Peers) > 0
 }
 
+// IsDynamic checks if the group membership is defined by a query
+func (g *Group) IsDynamic() bool {
+	return g.Query != nil
+}
+
 // IsGroupAll checks if the group is a default "All" group.
 func (g *Group) IsGroupAll() bool {
 	return g.Name == "All"
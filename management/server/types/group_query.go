@@ -0,0 +1,349 @@
+package types
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/netbirdio/netbird/management/server/http/api"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+const (
+	// GroupQueryMatchAll requires every rule of the query to match
+	GroupQueryMatchAll = "all"
+	// GroupQueryMatchAny requires at least one rule of the query to match
+	GroupQueryMatchAny = "any"
+)
+
+// GroupQueryAttribute is a peer attribute a dynamic group query rule is evaluated on
+type GroupQueryAttribute string
+
+const (
+	GroupQueryAttributeOS        GroupQueryAttribute = "os"
+	GroupQueryAttributeOSVersion GroupQueryAttribute = "os_version"
+	GroupQueryAttributeKernel    GroupQueryAttribute = "kernel"
+	GroupQueryAttributeVersion   GroupQueryAttribute = "version"
+	GroupQueryAttributeHostname  GroupQueryAttribute = "hostname"
+	GroupQueryAttributeCountry   GroupQueryAttribute = "country"
+	GroupQueryAttributeCloud     GroupQueryAttribute = "cloud"
+	GroupQueryAttributePlatform  GroupQueryAttribute = "platform"
+	GroupQueryAttributeUser      GroupQueryAttribute = "user"
+	GroupQueryAttributeGroup     GroupQueryAttribute = "group"
+)
+
+// GroupQueryOperator defines how the values of a dynamic group query rule are compared to the peer attribute
+type GroupQueryOperator string
+
+const (
+	// GroupQueryOperatorIs matches if the attribute equals any of the values, case-insensitive
+	GroupQueryOperatorIs GroupQueryOperator = "is"
+	// GroupQueryOperatorIsNot matches if the attribute equals none of the values, case-insensitive
+	GroupQueryOperatorIsNot GroupQueryOperator = "is_not"
+	// GroupQueryOperatorMatches matches if the attribute matches any of the glob patterns, case-insensitive
+	GroupQueryOperatorMatches GroupQueryOperator = "matches"
+	// GroupQueryOperatorMinVersion matches if the attribute is a version greater or equal to the value
+	GroupQueryOperatorMinVersion GroupQueryOperator = "min_version"
+)
+
+// GroupQueryRule is a single condition of a dynamic group query
+type GroupQueryRule struct {
+	Attribute GroupQueryAttribute
+	Operator  GroupQueryOperator
+	Values    []string
+}
+
+// GroupQuery defines the membership of a dynamic group as a query over peer attributes
+type GroupQuery struct {
+	// Match is either GroupQueryMatchAll or GroupQueryMatchAny
+	Match string
+	Rules []GroupQueryRule
+}
+
+// Copy returns a deep copy of the query
+func (q *GroupQuery) Copy() *GroupQuery {
+	if q == nil {
+		return nil
+	}
+
+	query := &GroupQuery{
+		Match: q.Match,
+		Rules: make([]GroupQueryRule, 0, len(q.Rules)),
+	}
+	for _, rule := range q.Rules {
+		rule.Values = slices.Clone(rule.Values)
+		query.Rules = append(query.Rules, rule)
+	}
+	return query
+}
+
+// ToAPIResponse converts the query to its API representation
+func (q *GroupQuery) ToAPIResponse() *api.GroupQuery {
+	if q == nil {
+		return nil
+	}
+
+	query := &api.GroupQuery{
+		Match: api.GroupQueryMatch(q.Match),
+		Rules: make([]api.GroupQueryRule, 0, len(q.Rules)),
+	}
+	for _, rule := range q.Rules {
+		query.Rules = append(query.Rules, api.GroupQueryRule{
+			Attribute: api.GroupQueryRuleAttribute(rule.Attribute),
+			Operator:  api.GroupQueryRuleOperator(rule.Operator),
+			Values:    slices.Clone(rule.Values),
+		})
+	}
+	return query
+}
+
+// FromAPIRequest fills the query from its API representation
+func (q *GroupQuery) FromAPIRequest(req *api.GroupQuery) {
+	q.Match = string(req.Match)
+	q.Rules = make([]GroupQueryRule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		q.Rules = append(q.Rules, GroupQueryRule{
+			Attribute: GroupQueryAttribute(rule.Attribute),
+			Operator:  GroupQueryOperator(rule.Operator),
+			Values:    slices.Clone(rule.Values),
+		})
+	}
+}
+
+// Validate checks the query is well-formed
+func (q *GroupQuery) Validate() error {
+	if q.Match != GroupQueryMatchAll && q.Match != GroupQueryMatchAny {
+		return status.Errorf(status.InvalidArgument, "invalid query match %q, expected %s or %s", q.Match, GroupQueryMatchAll, GroupQueryMatchAny)
+	}
+
+	if len(q.Rules) == 0 {
+		return status.Errorf(status.InvalidArgument, "query must have at least one rule")
+	}
+
+	for _, rule := range q.Rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GroupQueryRule) validate() error {
+	switch r.Attribute {
+	case GroupQueryAttributeOS, GroupQueryAttributeOSVersion, GroupQueryAttributeKernel, GroupQueryAttributeVersion,
+		GroupQueryAttributeHostname, GroupQueryAttributeCountry, GroupQueryAttributeCloud, GroupQueryAttributePlatform,
+		GroupQueryAttributeUser, GroupQueryAttributeGroup:
+	default:
+		return status.Errorf(status.InvalidArgument, "invalid query attribute %q", r.Attribute)
+	}
+
+	if len(r.Values) == 0 {
+		return status.Errorf(status.InvalidArgument, "query rule on %s must have at least one value", r.Attribute)
+	}
+
+	switch r.Operator {
+	case GroupQueryOperatorIs, GroupQueryOperatorIsNot:
+	case GroupQueryOperatorMatches:
+		if r.Attribute == GroupQueryAttributeGroup {
+			return status.Errorf(status.InvalidArgument, "operator %s isn't supported for attribute %s", r.Operator, r.Attribute)
+		}
+		for _, pattern := range r.Values {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return status.Errorf(status.InvalidArgument, "invalid glob pattern %q", pattern)
+			}
+		}
+	case GroupQueryOperatorMinVersion:
+		if r.Attribute != GroupQueryAttributeVersion && r.Attribute != GroupQueryAttributeKernel && r.Attribute != GroupQueryAttributeOSVersion {
+			return status.Errorf(status.InvalidArgument, "operator %s isn't supported for attribute %s", r.Operator, r.Attribute)
+		}
+		if len(r.Values) != 1 {
+			return status.Errorf(status.InvalidArgument, "operator %s requires a single value", r.Operator)
+		}
+		if _, err := version.NewVersion(r.Values[0]); err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid version %q", r.Values[0])
+		}
+	default:
+		return status.Errorf(status.InvalidArgument, "invalid query operator %q", r.Operator)
+	}
+
+	return nil
+}
+
+// ReferencedGroups returns the IDs of the groups the query depends on
+func (q *GroupQuery) ReferencedGroups() []string {
+	var groupIDs []string
+	for _, rule := range q.Rules {
+		if rule.Attribute == GroupQueryAttributeGroup {
+			groupIDs = append(groupIDs, rule.Values...)
+		}
+	}
+	return groupIDs
+}
+
+// Matches checks if the peer satisfies the query. peerGroups holds the IDs of the static groups of the peer.
+func (q *GroupQuery) Matches(peer *nbpeer.Peer, peerGroups map[string]struct{}) bool {
+	for _, rule := range q.Rules {
+		matched := rule.matches(peer, peerGroups)
+		if matched && q.Match == GroupQueryMatchAny {
+			return true
+		}
+		if !matched && q.Match == GroupQueryMatchAll {
+			return false
+		}
+	}
+	return q.Match == GroupQueryMatchAll
+}
+
+func (r *GroupQueryRule) matches(peer *nbpeer.Peer, peerGroups map[string]struct{}) bool {
+	if r.Attribute == GroupQueryAttributeGroup {
+		inAny := slices.ContainsFunc(r.Values, func(groupID string) bool {
+			_, ok := peerGroups[groupID]
+			return ok
+		})
+		return inAny == (r.Operator == GroupQueryOperatorIs)
+	}
+
+	value := r.attributeValue(peer)
+	switch r.Operator {
+	case GroupQueryOperatorIs:
+		return slices.ContainsFunc(r.Values, func(v string) bool { return strings.EqualFold(v, value) })
+	case GroupQueryOperatorIsNot:
+		return !slices.ContainsFunc(r.Values, func(v string) bool { return strings.EqualFold(v, value) })
+	case GroupQueryOperatorMatches:
+		return slices.ContainsFunc(r.Values, func(pattern string) bool {
+			matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+			return err == nil && matched
+		})
+	case GroupQueryOperatorMinVersion:
+		return versionAtLeast(value, r.Values[0])
+	default:
+		return false
+	}
+}
+
+func (r *GroupQueryRule) attributeValue(peer *nbpeer.Peer) string {
+	switch r.Attribute {
+	case GroupQueryAttributeOS:
+		return peer.Meta.GoOS
+	case GroupQueryAttributeOSVersion:
+		return peer.Meta.OSVersion
+	case GroupQueryAttributeKernel:
+		return peer.Meta.KernelVersion
+	case GroupQueryAttributeVersion:
+		return peer.Meta.WtVersion
+	case GroupQueryAttributeHostname:
+		return peer.Meta.Hostname
+	case GroupQueryAttributeCountry:
+		return peer.Location.CountryCode
+	case GroupQueryAttributeCloud:
+		return peer.Meta.Environment.Cloud
+	case GroupQueryAttributePlatform:
+		return peer.Meta.Environment.Platform
+	case GroupQueryAttributeUser:
+		return peer.UserID
+	default:
+		return ""
+	}
+}
+
+// versionAtLeast compares the release part of the versions, pre-release and build suffixes like "-dev" are ignored
+func versionAtLeast(value, minimum string) bool {
+	current, err := version.NewVersion(value)
+	if err != nil {
+		return false
+	}
+	required, err := version.NewVersion(minimum)
+	if err != nil {
+		return false
+	}
+	return current.Core().GreaterThanOrEqual(required.Core())
+}
+
+// EvaluateDynamicGroups recomputes the peers of the dynamic groups and returns the groups whose membership changed.
+// Queries are evaluated against the static groups only, dynamic groups can't reference each other.
+func EvaluateDynamicGroups(groups []*Group, peers []*nbpeer.Peer) []*Group {
+	memberships := staticGroupMemberships(groups)
+
+	var changed []*Group
+	for _, group := range groups {
+		if !group.IsDynamic() {
+			continue
+		}
+
+		members := make([]string, 0, len(group.Peers))
+		for _, peer := range peers {
+			if group.Query.Matches(peer, memberships[peer.ID]) {
+				members = append(members, peer.ID)
+			}
+		}
+
+		if !sameMembers(group.Peers, members) {
+			group.Peers = members
+			changed = append(changed, group)
+		}
+	}
+	return changed
+}
+
+// EvaluatePeerDynamicGroups adds the peer to or removes it from the dynamic groups and returns the changed groups
+func EvaluatePeerDynamicGroups(groups []*Group, peer *nbpeer.Peer) []*Group {
+	memberships := staticGroupMemberships(groups)
+
+	var changed []*Group
+	for _, group := range groups {
+		if !group.IsDynamic() {
+			continue
+		}
+
+		if group.Query.Matches(peer, memberships[peer.ID]) {
+			if group.AddPeer(peer.ID) {
+				changed = append(changed, group)
+			}
+		} else if group.RemovePeer(peer.ID) {
+			changed = append(changed, group)
+		}
+	}
+	return changed
+}
+
+// MatchingPeers returns the peers matching the query given the groups of the account
+func (q *GroupQuery) MatchingPeers(groups []*Group, peers []*nbpeer.Peer) []*nbpeer.Peer {
+	memberships := staticGroupMemberships(groups)
+
+	var matching []*nbpeer.Peer
+	for _, peer := range peers {
+		if q.Matches(peer, memberships[peer.ID]) {
+			matching = append(matching, peer)
+		}
+	}
+	return matching
+}
+
+func staticGroupMemberships(groups []*Group) map[string]map[string]struct{} {
+	memberships := make(map[string]map[string]struct{})
+	for _, group := range groups {
+		if group.IsDynamic() {
+			continue
+		}
+		for _, peerID := range group.Peers {
+			if memberships[peerID] == nil {
+				memberships[peerID] = make(map[string]struct{})
+			}
+			memberships[peerID][group.ID] = struct{}{}
+		}
+	}
+	return memberships
+}
+
+func sameMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
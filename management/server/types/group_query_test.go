@@ -0,0 +1,188 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+func testQueryPeers() []*nbpeer.Peer {
+	return []*nbpeer.Peer{
+		{
+			ID:     "peer1",
+			UserID: "user1",
+			Meta: nbpeer.PeerSystemMeta{
+				GoOS:          "linux",
+				KernelVersion: "6.8.0",
+				WtVersion:     "0.36.5",
+				Hostname:      "web-01",
+				Environment:   nbpeer.Environment{Cloud: "AWS", Platform: "EC2"},
+			},
+			Location: nbpeer.Location{CountryCode: "DE"},
+		},
+		{
+			ID:     "peer2",
+			UserID: "user2",
+			Meta: nbpeer.PeerSystemMeta{
+				GoOS:      "darwin",
+				OSVersion: "14.5",
+				WtVersion: "0.30.0-dev",
+				Hostname:  "laptop",
+			},
+			Location: nbpeer.Location{CountryCode: "US"},
+		},
+		{
+			ID: "peer3",
+			Meta: nbpeer.PeerSystemMeta{
+				GoOS:      "linux",
+				WtVersion: "development",
+				Hostname:  "web-02",
+			},
+		},
+	}
+}
+
+func TestGroupQuery_MatchingPeers(t *testing.T) {
+	groups := []*Group{
+		{ID: "static", Peers: []string{"peer2", "peer3"}},
+		{ID: "dynamic", Peers: []string{"peer1"}, Query: &GroupQuery{Match: GroupQueryMatchAll}},
+	}
+
+	tests := []struct {
+		name     string
+		query    GroupQuery
+		expected []string
+	}{
+		{
+			name: "os is",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{
+				{Attribute: GroupQueryAttributeOS, Operator: GroupQueryOperatorIs, Values: []string{"Linux"}},
+			}},
+			expected: []string{"peer1", "peer3"},
+		},
+		{
+			name: "hostname glob and country",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{
+				{Attribute: GroupQueryAttributeHostname, Operator: GroupQueryOperatorMatches, Values: []string{"web-*"}},
+				{Attribute: GroupQueryAttributeCountry, Operator: GroupQueryOperatorIsNot, Values: []string{"US"}},
+			}},
+			expected: []string{"peer1", "peer3"},
+		},
+		{
+			name: "min version ignores unparsable versions",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{
+				{Attribute: GroupQueryAttributeVersion, Operator: GroupQueryOperatorMinVersion, Values: []string{"0.30.0"}},
+			}},
+			expected: []string{"peer1", "peer2"},
+		},
+		{
+			name: "any of cloud or user",
+			query: GroupQuery{Match: GroupQueryMatchAny, Rules: []GroupQueryRule{
+				{Attribute: GroupQueryAttributeCloud, Operator: GroupQueryOperatorIs, Values: []string{"aws"}},
+				{Attribute: GroupQueryAttributeUser, Operator: GroupQueryOperatorIs, Values: []string{"user2"}},
+			}},
+			expected: []string{"peer1", "peer2"},
+		},
+		{
+			name: "group membership ignores dynamic groups",
+			query: GroupQuery{Match: GroupQueryMatchAny, Rules: []GroupQueryRule{
+				{Attribute: GroupQueryAttributeGroup, Operator: GroupQueryOperatorIs, Values: []string{"dynamic"}},
+				{Attribute: GroupQueryAttributeGroup, Operator: GroupQueryOperatorIs, Values: []string{"static"}},
+			}},
+			expected: []string{"peer2", "peer3"},
+		},
+		{
+			name: "not in group",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{
+				{Attribute: GroupQueryAttributeGroup, Operator: GroupQueryOperatorIsNot, Values: []string{"static"}},
+			}},
+			expected: []string{"peer1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.query.Validate())
+
+			var peerIDs []string
+			for _, peer := range tt.query.MatchingPeers(groups, testQueryPeers()) {
+				peerIDs = append(peerIDs, peer.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, peerIDs)
+		})
+	}
+}
+
+func TestGroupQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		query GroupQuery
+	}{
+		{
+			name:  "invalid match",
+			query: GroupQuery{Match: "some", Rules: []GroupQueryRule{{Attribute: GroupQueryAttributeOS, Operator: GroupQueryOperatorIs, Values: []string{"linux"}}}},
+		},
+		{
+			name:  "no rules",
+			query: GroupQuery{Match: GroupQueryMatchAll},
+		},
+		{
+			name:  "unknown attribute",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{{Attribute: "serial", Operator: GroupQueryOperatorIs, Values: []string{"1"}}}},
+		},
+		{
+			name:  "no values",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{{Attribute: GroupQueryAttributeOS, Operator: GroupQueryOperatorIs}}},
+		},
+		{
+			name:  "invalid glob",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{{Attribute: GroupQueryAttributeHostname, Operator: GroupQueryOperatorMatches, Values: []string{"web-["}}}},
+		},
+		{
+			name:  "min version on hostname",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{{Attribute: GroupQueryAttributeHostname, Operator: GroupQueryOperatorMinVersion, Values: []string{"1.0.0"}}}},
+		},
+		{
+			name:  "invalid min version",
+			query: GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{{Attribute: GroupQueryAttributeVersion, Operator: GroupQueryOperatorMinVersion, Values: []string{"latest"}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.query.Validate())
+		})
+	}
+}
+
+func TestEvaluateDynamicGroups(t *testing.T) {
+	linuxQuery := &GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{
+		{Attribute: GroupQueryAttributeOS, Operator: GroupQueryOperatorIs, Values: []string{"linux"}},
+	}}
+	groups := []*Group{
+		{ID: "static", Peers: []string{"peer2"}},
+		{ID: "linux", Peers: []string{"peer3", "peer1"}, Query: linuxQuery},
+		{ID: "darwin", Peers: []string{"peer1"}, Query: &GroupQuery{Match: GroupQueryMatchAll, Rules: []GroupQueryRule{
+			{Attribute: GroupQueryAttributeOS, Operator: GroupQueryOperatorIs, Values: []string{"darwin"}},
+		}}},
+	}
+
+	changed := EvaluateDynamicGroups(groups, testQueryPeers())
+	require.Len(t, changed, 1)
+	assert.Equal(t, "darwin", changed[0].ID)
+	assert.Equal(t, []string{"peer2"}, changed[0].Peers)
+	assert.Equal(t, []string{"peer3", "peer1"}, groups[1].Peers, "unchanged membership must keep the order")
+
+	peer := testQueryPeers()[0]
+	peer.Meta.GoOS = "darwin"
+	changed = EvaluatePeerDynamicGroups(groups, peer)
+	require.Len(t, changed, 2)
+	assert.Equal(t, []string{"peer3"}, groups[1].Peers)
+	assert.ElementsMatch(t, []string{"peer1", "peer2"}, groups[2].Peers)
+	assert.Equal(t, []string{"peer2"}, groups[0].Peers, "static groups must not be changed")
+
+	assert.Empty(t, EvaluatePeerDynamicGroups(groups, peer))
+}
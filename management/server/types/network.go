@@ -1,6 +1,7 @@
 package types
 
 import (
+	crand "crypto/rand"
 	"math/rand"
 	"net"
 	"sync"
@@ -26,6 +27,11 @@ const (
 
 	// AllowedIPsFormat generates Wireguard AllowedIPs format (e.g. 100.64.30.1/32)
 	AllowedIPsFormat = "%s/32"
+	// AllowedIPsV6Format generates Wireguard AllowedIPs format for IPv6 (e.g. fd12:3456:789a:1::1/128)
+	AllowedIPsV6Format = "%s/128"
+
+	// NetV6Size is the size of the IPv6 overlay network of an account, e.g. fd12:3456:789a:1::/64
+	NetV6Size = 64
 )
 
 type NetworkMap struct {
@@ -37,6 +43,8 @@ type NetworkMap struct {
 	FirewallRules       []*FirewallRule
 	RoutesFirewallRules []*RouteFirewallRule
 	ForwardingRules     []*ForwardingRule
+	// RosenpassRequired requires the peer to refuse connections without the post-quantum key exchange
+	RosenpassRequired bool
 }
 
 func (nm *NetworkMap) Merge(other *NetworkMap) {
@@ -46,6 +54,7 @@ func (nm *NetworkMap) Merge(other *NetworkMap) {
 	nm.FirewallRules = util.MergeUnique(nm.FirewallRules, other.FirewallRules)
 	nm.RoutesFirewallRules = util.MergeUnique(nm.RoutesFirewallRules, other.RoutesFirewallRules)
 	nm.ForwardingRules = util.MergeUnique(nm.ForwardingRules, other.ForwardingRules)
+	nm.RosenpassRequired = nm.RosenpassRequired || other.RosenpassRequired
 }
 
 func mergeUniquePeersByID(peers1, peers2 []*nbpeer.Peer) []*nbpeer.Peer {
@@ -108,7 +117,9 @@ func ipToBytes(ip net.IP) []byte {
 type Network struct {
 	Identifier string    `json:"id"`
 	Net        net.IPNet `gorm:"serializer:json"`
-	Dns        string
+	// NetV6 is the IPv6 ULA overlay network of the account, empty if the account has no IPv6 overlay
+	NetV6 net.IPNet `gorm:"serializer:json"`
+	Dns   string
 	// Serial is an ID that increments by 1 when any change to the network happened (e.g. new peer has been added).
 	// Used to synchronize state to the client apps.
 	Serial uint64
@@ -130,10 +141,29 @@ func NewNetwork() *Network {
 	return &Network{
 		Identifier: xid.New().String(),
 		Net:        sub[intn].IPNet,
+		NetV6:      NewNetV6(),
 		Dns:        "",
 		Serial:     0}
 }
 
+// NewNetV6 generates a random /64 from the IPv6 unique local address range fd00::/8 as described in RFC 4193
+func NewNetV6() net.IPNet {
+	ip := make(net.IP, net.IPv6len)
+	ip[0] = 0xfd
+	// global ID and subnet ID, the interface ID stays zeroed
+	_, _ = crand.Read(ip[1:8])
+
+	return net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(NetV6Size, 128),
+	}
+}
+
+// HasIPv6 returns true if the network has an IPv6 overlay network
+func (n *Network) HasIPv6() bool {
+	return len(n.NetV6.IP) == net.IPv6len && n.NetV6.IP.To4() == nil
+}
+
 // IncSerial increments Serial by 1 reflecting that the network state has been changed
 func (n *Network) IncSerial() {
 	n.Mu.Lock()
@@ -152,6 +182,7 @@ func (n *Network) Copy() *Network {
 	return &Network{
 		Identifier: n.Identifier,
 		Net:        n.Net,
+		NetV6:      n.NetV6,
 		Dns:        n.Dns,
 		Serial:     n.Serial,
 	}
@@ -181,6 +212,45 @@ func AllocatePeerIP(ipNet net.IPNet, takenIps []net.IP) (net.IP, error) {
 	return ips[intn], nil
 }
 
+// AllocatePeerIPv6 picks a random available IPv6 address from the given network.
+// The /64 is far too large to enumerate, so random interface IDs are drawn until one doesn't collide with takenIps.
+func AllocatePeerIPv6(ipNet net.IPNet, takenIps []net.IP) (net.IP, error) {
+	ones, bits := ipNet.Mask.Size()
+	if bits != 128 || ones > 120 {
+		return nil, status.Errorf(status.PreconditionFailed, "failed allocating new IPv6 for the ipNet %s - unsupported network size", ipNet.String())
+	}
+
+	takenIPMap := make(map[string]struct{}, len(takenIps))
+	for _, ip := range takenIps {
+		takenIPMap[ip.String()] = struct{}{}
+	}
+
+	network := ipNet.IP.Mask(ipNet.Mask)
+	for i := 0; i < 100; i++ {
+		ip := make(net.IP, net.IPv6len)
+		if _, err := crand.Read(ip); err != nil {
+			return nil, status.Errorf(status.Internal, "failed allocating new IPv6: %v", err)
+		}
+
+		for j := range ip {
+			ip[j] = network[j] | (ip[j] &^ ipNet.Mask[j])
+		}
+
+		// skip the subnet-router anycast address
+		if ip.Equal(network) {
+			continue
+		}
+
+		if _, ok := takenIPMap[ip.String()]; ok {
+			continue
+		}
+
+		return ip, nil
+	}
+
+	return nil, status.Errorf(status.PreconditionFailed, "failed allocating new IPv6 for the ipNet %s - no free address found", ipNet.String())
+}
+
 // generateIPs generates a list of all possible IPs of the given network excluding IPs specified in the exclusion list
 func generateIPs(ipNet *net.IPNet, exclusions map[string]struct{}) ([]net.IP, int) {
 
@@ -13,6 +13,40 @@ func TestNewNetwork(t *testing.T) {
 	// generated net should be a subnet of a larger 100.64.0.0/10 net
 	ipNet := net.IPNet{IP: net.ParseIP("100.64.0.0"), Mask: net.IPMask{255, 192, 0, 0}}
 	assert.Equal(t, ipNet.Contains(network.Net.IP), true)
+
+	// generated IPv6 net should be a /64 of the unique local range fd00::/8
+	ulaNet := net.IPNet{IP: net.ParseIP("fd00::"), Mask: net.CIDRMask(8, 128)}
+	assert.True(t, network.HasIPv6())
+	assert.True(t, ulaNet.Contains(network.NetV6.IP))
+	ones, bits := network.NetV6.Mask.Size()
+	assert.Equal(t, NetV6Size, ones)
+	assert.Equal(t, 128, bits)
+}
+
+func TestAllocatePeerIPv6(t *testing.T) {
+	ipNet := NewNetV6()
+
+	var ips []net.IP
+	for i := 0; i < 100; i++ {
+		ip, err := AllocatePeerIPv6(ipNet, ips)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, ipNet.Contains(ip), "ip %s not in %s", ip, ipNet.String())
+		assert.Nil(t, ip.To4())
+		ips = append(ips, ip)
+	}
+
+	uniq := make(map[string]struct{})
+	for _, ip := range ips {
+		if _, ok := uniq[ip.String()]; ok {
+			t.Errorf("found duplicate IP %s", ip.String())
+		}
+		uniq[ip.String()] = struct{}{}
+	}
+
+	_, err := AllocatePeerIPv6(net.IPNet{IP: net.ParseIP("100.64.0.0"), Mask: net.CIDRMask(16, 32)}, nil)
+	assert.Error(t, err, "IPv4 networks are not supported")
 }
 
 func TestAllocatePeerIP(t *testing.T) {
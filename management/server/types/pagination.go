@@ -0,0 +1,115 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+const (
+	// MaxPageLimit is the maximum number of objects returned in a single page
+	MaxPageLimit = 1000
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// PageRequest defines a page of a list sorted by the SortBy field.
+// A zero Limit returns all the objects after the cursor.
+type PageRequest struct {
+	Limit int
+	// Cursor is the opaque cursor returned with the previous page, empty for the first page
+	Cursor   string
+	SortBy   string
+	SortDesc bool
+}
+
+// PageCursor points to the last object of a page by its sort value and ID, so pages stay stable while objects are added or removed
+type PageCursor struct {
+	SortBy string `json:"s"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+// Encode returns the opaque representation of the cursor
+func (c PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor parses a cursor returned by Encode and checks it was issued for the same sort field
+func DecodePageCursor(cursor, sortBy string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid cursor")
+	}
+
+	var pageCursor PageCursor
+	if err := json.Unmarshal(data, &pageCursor); err != nil || pageCursor.ID == "" {
+		return nil, status.Errorf(status.InvalidArgument, "invalid cursor")
+	}
+
+	if pageCursor.SortBy != sortBy {
+		return nil, status.Errorf(status.InvalidArgument, "cursor was issued for sorting by %s", pageCursor.SortBy)
+	}
+
+	return &pageCursor, nil
+}
+
+// Validate checks the page limit
+func (p PageRequest) Validate() error {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return status.Errorf(status.InvalidArgument, "limit can't be negative or greater than %d", MaxPageLimit)
+	}
+	return nil
+}
+
+const (
+	PeerSortByName      = "name"
+	PeerSortByDNSLabel  = "dns_label"
+	PeerSortByOS        = "os"
+	PeerSortByVersion   = "version"
+	PeerSortByLastSeen  = "last_seen"
+	PeerSortByCreatedAt = "created_at"
+)
+
+// PeerFilter defines the conditions the listed peers have to match, empty fields are ignored
+type PeerFilter struct {
+	// IDs limits the peers to the given IDs, nil means no limit
+	IDs []string
+	// Name, IP and DNSLabel match a substring
+	Name     string
+	IP       string
+	DNSLabel string
+	GroupID  string
+	// OS matches the OS type (e.g. linux) or a substring of the OS name (e.g. Ubuntu), case-insensitive
+	OS string
+	// Version matches a prefix of the NetBird version
+	Version      string
+	Connected    *bool
+	LoginExpired *bool
+	// ApprovalRequired matches the peers missing from ApprovedIDs, the peers approved by the integrated validator
+	ApprovalRequired *bool
+	ApprovedIDs      []string
+	LastSeenAfter    *time.Time
+	LastSeenBefore   *time.Time
+}
+
+const (
+	UserSortByID        = "id"
+	UserSortByRole      = "role"
+	UserSortByCreatedAt = "created_at"
+)
+
+// UserFilter defines the conditions the listed users have to match, empty fields are ignored
+type UserFilter struct {
+	// IDs limits the users to the given IDs, nil means no limit
+	IDs         []string
+	Role        UserRole
+	ServiceUser *bool
+	Blocked     *bool
+	// GroupID matches users with the group in their auto groups
+	GroupID string
+}
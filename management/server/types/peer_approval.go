@@ -0,0 +1,134 @@
+package types
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rs/xid"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+type PeerApprovalStatus string
+
+const (
+	PeerApprovalStatusPending  PeerApprovalStatus = "pending"
+	PeerApprovalStatusApproved PeerApprovalStatus = "approved"
+	PeerApprovalStatusRejected PeerApprovalStatus = "rejected"
+)
+
+// PeerApprovalRequest is created when a new peer requires approval.
+// It keeps the context of the registration for the administrators reviewing it.
+type PeerApprovalRequest struct {
+	PeerID    string `gorm:"primaryKey"`
+	AccountID string `gorm:"index"`
+	PeerName  string
+	// UserID is the user that registered the peer, empty for peers registered with a setup key
+	UserID       string
+	SetupKeyID   string
+	SetupKeyName string
+	Meta         nbpeer.PeerSystemMeta `gorm:"embedded;embeddedPrefix:meta_"`
+	Location     nbpeer.Location       `gorm:"embedded;embeddedPrefix:location_"`
+	CreatedAt    time.Time
+
+	Status PeerApprovalStatus
+	// Reason is the reason given when the request was approved or rejected
+	Reason string
+	// ReviewedBy is the user that approved or rejected the request, empty when it was approved by a rule
+	ReviewedBy string
+	// ApprovalRuleID is the auto-approve rule that approved the request
+	ApprovalRuleID string
+	ReviewedAt     *time.Time
+}
+
+// NewPeerApprovalRequest creates a pending approval request for the peer
+func NewPeerApprovalRequest(peer *nbpeer.Peer, setupKeyID, setupKeyName string) *PeerApprovalRequest {
+	return &PeerApprovalRequest{
+		PeerID:       peer.ID,
+		AccountID:    peer.AccountID,
+		PeerName:     peer.Name,
+		UserID:       peer.UserID,
+		SetupKeyID:   setupKeyID,
+		SetupKeyName: setupKeyName,
+		Meta:         peer.Meta,
+		Location:     peer.Location,
+		CreatedAt:    time.Now().UTC(),
+		Status:       PeerApprovalStatusPending,
+	}
+}
+
+// Review closes the request with the given status
+func (r *PeerApprovalRequest) Review(status PeerApprovalStatus, reviewedBy, reason string) {
+	now := time.Now().UTC()
+	r.Status = status
+	r.ReviewedBy = reviewedBy
+	r.Reason = reason
+	r.ReviewedAt = &now
+}
+
+// EventMeta returns activity event meta related to the approval request
+func (r *PeerApprovalRequest) EventMeta() map[string]any {
+	meta := map[string]any{"name": r.PeerName, "reason": r.Reason}
+	if r.SetupKeyName != "" {
+		meta["setup_key_name"] = r.SetupKeyName
+	}
+	return meta
+}
+
+// PeerApprovalRule approves new peers automatically when they match any of its conditions
+type PeerApprovalRule struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"index"`
+	Name        string
+	Description string
+	Enabled     bool
+	// SetupKeyIDs approves the peers registered with one of the setup keys
+	SetupKeyIDs []string `gorm:"serializer:json"`
+	// SerialNumbers approves the peers reporting one of the system serial numbers
+	SerialNumbers []string `gorm:"serializer:json"`
+}
+
+// NewPeerApprovalRule creates a new auto-approve rule with a generated ID
+func NewPeerApprovalRule(accountID, name, description string, enabled bool, setupKeyIDs, serialNumbers []string) *PeerApprovalRule {
+	return &PeerApprovalRule{
+		ID:            xid.New().String(),
+		AccountID:     accountID,
+		Name:          name,
+		Description:   description,
+		Enabled:       enabled,
+		SetupKeyIDs:   setupKeyIDs,
+		SerialNumbers: serialNumbers,
+	}
+}
+
+// Validate checks the rule has a name and at least one condition
+func (r *PeerApprovalRule) Validate() error {
+	if r.Name == "" {
+		return status.Errorf(status.InvalidArgument, "approval rule name can't be empty")
+	}
+
+	if len(r.SetupKeyIDs) == 0 && len(r.SerialNumbers) == 0 {
+		return status.Errorf(status.InvalidArgument, "approval rule requires at least one setup key or serial number")
+	}
+
+	return nil
+}
+
+// Matches returns true if the enabled rule approves the peer of the request
+func (r *PeerApprovalRule) Matches(request *PeerApprovalRequest) bool {
+	if !r.Enabled {
+		return false
+	}
+
+	if request.SetupKeyID != "" && slices.Contains(r.SetupKeyIDs, request.SetupKeyID) {
+		return true
+	}
+
+	return request.Meta.SystemSerialNumber != "" && slices.Contains(r.SerialNumbers, request.Meta.SystemSerialNumber)
+}
+
+// EventMeta returns activity event meta related to the approval rule
+func (r *PeerApprovalRule) EventMeta() map[string]any {
+	return map[string]any{"name": r.Name}
+}
@@ -0,0 +1,559 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/netbirdio/netbird/management/domain"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	// SimulationDestinationPeer is a destination resolved to a peer
+	SimulationDestinationPeer = "peer"
+	// SimulationDestinationResource is a destination resolved to a network resource
+	SimulationDestinationResource = "resource"
+	// SimulationDestinationRoute is a destination resolved to a network route
+	SimulationDestinationRoute = "route"
+)
+
+// PolicySimulationRequest describes the traffic to evaluate.
+// Exactly one of the destination fields must be set.
+type PolicySimulationRequest struct {
+	SourcePeerID string
+
+	DestinationPeerID     string
+	DestinationIP         netip.Addr
+	DestinationDomain     string
+	DestinationResourceID string
+
+	Protocol PolicyRuleProtocolType
+	// Port of the traffic, 0 matches only rules without port restrictions
+	Port uint16
+}
+
+// PolicySimulationRuleMatch is a policy rule that matched the simulated traffic
+type PolicySimulationRuleMatch struct {
+	PolicyID string
+	RuleID   string
+	Action   PolicyTrafficActionType
+}
+
+// PolicySimulationResult is the verdict of a simulation and the facts that led to it
+type PolicySimulationResult struct {
+	Allowed bool
+
+	DestinationType string
+	DestinationID   string
+
+	MatchedRules []PolicySimulationRuleMatch
+	// FailedPostureChecks of the source peer on policies that would otherwise apply
+	FailedPostureChecks []string
+	// RoutingPeers able to forward the traffic to a routed destination
+	RoutingPeers []string
+	// Reasons explain the verdict in evaluation order
+	Reasons []string
+}
+
+func (r *PolicySimulationResult) addMatch(match PolicySimulationRuleMatch) {
+	if !slices.Contains(r.MatchedRules, match) {
+		r.MatchedRules = append(r.MatchedRules, match)
+	}
+}
+
+func (r *PolicySimulationResult) addReason(format string, args ...any) {
+	r.Reasons = append(r.Reasons, fmt.Sprintf(format, args...))
+}
+
+// Validate checks that the request has a source, a single destination and a known protocol
+func (r *PolicySimulationRequest) Validate() error {
+	if r.SourcePeerID == "" {
+		return status.Errorf(status.InvalidArgument, "source peer is required")
+	}
+
+	destinations := 0
+	for _, set := range []bool{r.DestinationPeerID != "", r.DestinationIP.IsValid(), r.DestinationDomain != "", r.DestinationResourceID != ""} {
+		if set {
+			destinations++
+		}
+	}
+	if destinations != 1 {
+		return status.Errorf(status.InvalidArgument, "exactly one destination peer, IP, domain or resource is required")
+	}
+
+	if r.DestinationDomain != "" {
+		// domains are stored in punycode
+		d, err := domain.FromString(r.DestinationDomain)
+		if err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid domain %q: %v", r.DestinationDomain, err)
+		}
+		r.DestinationDomain = string(d)
+	}
+
+	switch r.Protocol {
+	case PolicyRuleProtocolALL, PolicyRuleProtocolICMP:
+		if r.Port != 0 {
+			return status.Errorf(status.InvalidArgument, "port is not supported for protocol %s", r.Protocol)
+		}
+	case PolicyRuleProtocolTCP, PolicyRuleProtocolUDP:
+	default:
+		return status.Errorf(status.InvalidArgument, "invalid protocol %q", r.Protocol)
+	}
+
+	return nil
+}
+
+// SimulatePolicy evaluates whether the source peer of the request can reach its destination.
+// The verdict is based on the firewall rules the peers would receive in their network maps.
+func (a *Account) SimulatePolicy(ctx context.Context, req PolicySimulationRequest, validatedPeersMap map[string]struct{}) (*PolicySimulationResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	source := a.GetPeer(req.SourcePeerID)
+	if source == nil {
+		return nil, status.NewPeerNotFoundError(req.SourcePeerID)
+	}
+
+	result := &PolicySimulationResult{}
+	if _, ok := validatedPeersMap[source.ID]; !ok {
+		result.addReason("source peer %s is not approved", source.ID)
+		return result, nil
+	}
+
+	switch {
+	case req.DestinationPeerID != "":
+		destination := a.GetPeer(req.DestinationPeerID)
+		if destination == nil {
+			return nil, status.NewPeerNotFoundError(req.DestinationPeerID)
+		}
+		a.simulatePeerAccess(ctx, req, source, destination, validatedPeersMap, result)
+
+	case req.DestinationResourceID != "":
+		resource := a.getNetworkResource(req.DestinationResourceID)
+		if resource == nil {
+			return nil, status.NewNetworkResourceNotFoundError(req.DestinationResourceID)
+		}
+		a.simulateResourceAccess(ctx, req, source, resource, validatedPeersMap, result)
+
+	default:
+		a.simulateAddressAccess(ctx, req, source, validatedPeersMap, result)
+	}
+
+	return result, nil
+}
+
+// simulateAddressAccess resolves a destination IP or domain to a peer, a network resource or a route.
+// Network resources take precedence over routes as they are the newer way to expose networks.
+func (a *Account) simulateAddressAccess(ctx context.Context, req PolicySimulationRequest, source *nbpeer.Peer, validatedPeersMap map[string]struct{}, result *PolicySimulationResult) {
+	if req.DestinationIP.IsValid() {
+		for _, peer := range a.Peers {
+			if peerHasIP(peer, req.DestinationIP) {
+				a.simulatePeerAccess(ctx, req, source, peer, validatedPeersMap, result)
+				return
+			}
+		}
+	}
+
+	for _, resource := range a.NetworkResources {
+		if resourceMatches(resource, req) {
+			a.simulateResourceAccess(ctx, req, source, resource, validatedPeersMap, result)
+			return
+		}
+	}
+
+	for _, r := range a.Routes {
+		if routeMatches(r, req) {
+			a.simulateRouteAccess(ctx, req, source, r, validatedPeersMap, result)
+			return
+		}
+	}
+
+	result.addReason("no peer, network resource or route matches the destination")
+}
+
+func (a *Account) simulatePeerAccess(ctx context.Context, req PolicySimulationRequest, source, destination *nbpeer.Peer, validatedPeersMap map[string]struct{}, result *PolicySimulationResult) {
+	result.DestinationType = SimulationDestinationPeer
+	result.DestinationID = destination.ID
+
+	if source.ID == destination.ID {
+		result.Allowed = true
+		result.addReason("source and destination are the same peer")
+		return
+	}
+
+	if _, ok := validatedPeersMap[destination.ID]; !ok {
+		result.addReason("destination peer %s is not approved", destination.ID)
+		return
+	}
+
+	result.FailedPostureChecks = a.failedPeerPostureChecks(ctx, source, destination)
+	for _, checkID := range result.FailedPostureChecks {
+		result.addReason("source peer fails posture check %s", checkID)
+	}
+
+	// the destination enforces the inbound rules, evaluate them the way it receives them
+	_, rules := a.GetPeerConnectionResources(ctx, destination.ID, validatedPeersMap)
+	for _, rule := range rules {
+		if rule.Direction != FirewallRuleDirectionIN || !peerRuleMatches(rule, source, req) {
+			continue
+		}
+		result.addMatch(a.ruleMatchByRuleID(rule.PolicyID, PolicyTrafficActionType(rule.Action)))
+	}
+
+	result.Allowed = verdict(result)
+}
+
+func (a *Account) simulateResourceAccess(ctx context.Context, req PolicySimulationRequest, source *nbpeer.Peer, resource *resourceTypes.NetworkResource, validatedPeersMap map[string]struct{}, result *PolicySimulationResult) {
+	result.DestinationType = SimulationDestinationResource
+	result.DestinationID = resource.ID
+
+	if !resource.Enabled {
+		result.addReason("network resource %s is disabled", resource.ID)
+		return
+	}
+
+	resourcePolicies := a.GetResourcePoliciesMap()
+	if len(resourcePolicies[resource.ID]) == 0 {
+		result.addReason("no enabled policy applies to network resource %s", resource.ID)
+		return
+	}
+
+	routers := a.availableRouters(a.GetResourceRoutersMap()[resource.NetworkID], source.ID, validatedPeersMap)
+	if len(routers) == 0 {
+		result.addReason("network %s has no enabled and approved routing peers", resource.NetworkID)
+		return
+	}
+
+	for _, policy := range resourcePolicies[resource.ID] {
+		if !slices.Contains(a.getUniquePeerIDsFromGroupsIDs(ctx, policy.SourceGroups()), source.ID) {
+			continue
+		}
+		for _, checkID := range a.failedPostureChecks(ctx, policy.SourcePostureChecks, source) {
+			result.FailedPostureChecks = appendUnique(result.FailedPostureChecks, checkID)
+			result.addReason("source peer fails posture check %s of policy %s", checkID, policy.ID)
+		}
+	}
+
+	for peerID, router := range routers {
+		routingPeer := a.GetPeer(peerID)
+		routes := a.getNetworkResourcesRoutes(resource, peerID, router, resourcePolicies)
+		rules := a.GetPeerNetworkResourceFirewallRules(ctx, routingPeer, validatedPeersMap, routes, resourcePolicies)
+		if a.collectRouteRuleMatches(rules, source, req, result) {
+			result.RoutingPeers = append(result.RoutingPeers, peerID)
+		}
+	}
+
+	if len(resource.Services) > 0 && len(result.MatchedRules) == 0 {
+		result.addReason("network resource %s doesn't expose the requested protocol and port", resource.ID)
+	}
+
+	result.Allowed = verdict(result)
+}
+
+func (a *Account) simulateRouteAccess(ctx context.Context, req PolicySimulationRequest, source *nbpeer.Peer, r *route.Route, validatedPeersMap map[string]struct{}, result *PolicySimulationResult) {
+	result.DestinationType = SimulationDestinationRoute
+	result.DestinationID = string(r.ID)
+
+	if !r.Enabled {
+		result.addReason("route %s is disabled", r.ID)
+		return
+	}
+
+	peerGroups := a.GetPeerGroups(source.ID)
+	if !slices.ContainsFunc(r.Groups, func(groupID string) bool {
+		_, ok := peerGroups[groupID]
+		return ok
+	}) {
+		result.addReason("source peer is not in the distribution groups of route %s", r.ID)
+		return
+	}
+
+	var routingPeers []string
+	if r.Peer != "" {
+		routingPeers = append(routingPeers, r.Peer)
+	}
+	routingPeers = append(routingPeers, a.getUniquePeerIDsFromGroupsIDs(ctx, r.PeerGroups)...)
+
+	for _, peerID := range routingPeers {
+		if _, ok := validatedPeersMap[peerID]; !ok || peerID == source.ID || a.GetPeer(peerID) == nil {
+			continue
+		}
+
+		rules := a.GetPeerRoutesFirewallRules(ctx, peerID, validatedPeersMap)
+		rules = slices.DeleteFunc(rules, func(rule *RouteFirewallRule) bool {
+			return !routeRuleBelongsTo(rule, r)
+		})
+		if a.collectRouteRuleMatches(rules, source, req, result) {
+			result.RoutingPeers = appendUnique(result.RoutingPeers, peerID)
+		}
+	}
+
+	if len(result.RoutingPeers) == 0 && len(result.MatchedRules) == 0 {
+		result.addReason("route %s has no approved routing peer with a matching access control rule", r.ID)
+	}
+
+	result.Allowed = verdict(result)
+}
+
+// collectRouteRuleMatches adds the rules matching the traffic to the result and returns true if any matched
+func (a *Account) collectRouteRuleMatches(rules []*RouteFirewallRule, source *nbpeer.Peer, req PolicySimulationRequest, result *PolicySimulationResult) bool {
+	matched := false
+	for _, rule := range rules {
+		if !routeRuleMatches(rule, source, req) {
+			continue
+		}
+		matched = true
+
+		if rule.PolicyID == "" {
+			result.addReason("route without access control groups permits all traffic")
+		}
+		result.addMatch(a.ruleMatchByPolicyID(rule.PolicyID, req, PolicyTrafficActionType(rule.Action)))
+	}
+	return matched
+}
+
+// availableRouters returns the approved routing peers of a network, a peer can't route its own traffic
+func (a *Account) availableRouters(routers map[string]*routerTypes.NetworkRouter, sourceID string, validatedPeersMap map[string]struct{}) map[string]*routerTypes.NetworkRouter {
+	available := make(map[string]*routerTypes.NetworkRouter, len(routers))
+	for peerID, router := range routers {
+		if _, ok := validatedPeersMap[peerID]; !ok || peerID == sourceID || a.GetPeer(peerID) == nil {
+			continue
+		}
+		available[peerID] = router
+	}
+	return available
+}
+
+// failedPeerPostureChecks returns the posture checks failed by the source on enabled policies connecting it to the destination
+func (a *Account) failedPeerPostureChecks(ctx context.Context, source, destination *nbpeer.Peer) []string {
+	sourceGroups := a.GetPeerGroups(source.ID)
+	destinationGroups := a.GetPeerGroups(destination.ID)
+	inGroups := func(groups []string, memberships LookupMap) bool {
+		return slices.ContainsFunc(groups, func(groupID string) bool {
+			_, ok := memberships[groupID]
+			return ok
+		})
+	}
+
+	var failed []string
+	for _, policy := range a.Policies {
+		if !policy.Enabled || len(policy.SourcePostureChecks) == 0 {
+			continue
+		}
+
+		for _, rule := range policy.Rules {
+			if !rule.Enabled || !inGroups(rule.Sources, sourceGroups) || !inGroups(rule.Destinations, destinationGroups) {
+				continue
+			}
+			for _, checkID := range a.failedPostureChecks(ctx, policy.SourcePostureChecks, source) {
+				failed = appendUnique(failed, checkID)
+			}
+		}
+	}
+	return failed
+}
+
+// failedPostureChecks returns the IDs of the posture checks the peer doesn't pass
+func (a *Account) failedPostureChecks(ctx context.Context, postureChecksIDs []string, peer *nbpeer.Peer) []string {
+	var failed []string
+	inventory := a.GetDeviceInventory()
+	for _, checkID := range postureChecksIDs {
+		if !a.validatePostureChecksOnPeer(ctx, []string{checkID}, peer.ID, inventory) {
+			failed = append(failed, checkID)
+		}
+	}
+	return failed
+}
+
+func (a *Account) ruleMatchByRuleID(ruleID string, action PolicyTrafficActionType) PolicySimulationRuleMatch {
+	for _, policy := range a.Policies {
+		for _, rule := range policy.Rules {
+			if rule.ID == ruleID {
+				return PolicySimulationRuleMatch{PolicyID: policy.ID, RuleID: rule.ID, Action: action}
+			}
+		}
+	}
+	return PolicySimulationRuleMatch{RuleID: ruleID, Action: action}
+}
+
+// ruleMatchByPolicyID resolves the policy rule a route firewall rule was generated from.
+// Route firewall rules only carry the policy ID, the rule is found by its action, protocol and ports.
+func (a *Account) ruleMatchByPolicyID(policyID string, req PolicySimulationRequest, action PolicyTrafficActionType) PolicySimulationRuleMatch {
+	match := PolicySimulationRuleMatch{PolicyID: policyID, Action: action}
+
+	for _, policy := range a.Policies {
+		if policy.ID != policyID {
+			continue
+		}
+		for _, rule := range policy.Rules {
+			if rule.Enabled && rule.Action == action && protocolMatches(rule.Protocol, req.Protocol) && policyRulePortMatches(rule, req.Port) {
+				match.RuleID = rule.ID
+				break
+			}
+		}
+	}
+	return match
+}
+
+// verdict denies the traffic if any matching rule drops it, otherwise allows it if any rule accepts it
+func verdict(result *PolicySimulationResult) bool {
+	accepted := false
+	for _, match := range result.MatchedRules {
+		if match.Action == PolicyTrafficActionDrop {
+			result.addReason("traffic is dropped by policy %s", match.PolicyID)
+			return false
+		}
+		accepted = true
+	}
+
+	if !accepted {
+		result.addReason("no policy rule allows the traffic")
+	}
+	return accepted
+}
+
+func peerHasIP(peer *nbpeer.Peer, ip netip.Addr) bool {
+	if peerIP, ok := netip.AddrFromSlice(peer.IP); ok && peerIP.Unmap() == ip.Unmap() {
+		return true
+	}
+	if peerIPv6, ok := netip.AddrFromSlice(peer.IPv6); ok && len(peer.IPv6) > 0 && peerIPv6 == ip {
+		return true
+	}
+	return false
+}
+
+func peerRuleMatches(rule *FirewallRule, source *nbpeer.Peer, req PolicySimulationRequest) bool {
+	if rule.PeerIP != "0.0.0.0" {
+		ip, err := netip.ParseAddr(rule.PeerIP)
+		if err != nil || !peerHasIP(source, ip) {
+			return false
+		}
+	}
+
+	if !protocolMatches(PolicyRuleProtocolType(rule.Protocol), req.Protocol) {
+		return false
+	}
+
+	switch {
+	case rule.Port != "":
+		return req.Port != 0 && rule.Port == strconv.Itoa(int(req.Port))
+	case rule.PortRange.Start != 0:
+		return req.Port >= rule.PortRange.Start && req.Port <= rule.PortRange.End
+	default:
+		return true
+	}
+}
+
+func routeRuleMatches(rule *RouteFirewallRule, source *nbpeer.Peer, req PolicySimulationRequest) bool {
+	sourceIP, ok := netip.AddrFromSlice(source.IP)
+	if !ok {
+		return false
+	}
+	if !slices.ContainsFunc(rule.SourceRanges, func(sourceRange string) bool {
+		prefix, err := netip.ParsePrefix(sourceRange)
+		return err == nil && prefix.Contains(sourceIP.Unmap())
+	}) {
+		return false
+	}
+
+	switch {
+	case req.DestinationIP.IsValid() && !rule.IsDynamic:
+		prefix, err := netip.ParsePrefix(rule.Destination)
+		if err != nil || !prefix.Contains(req.DestinationIP) {
+			return false
+		}
+	case req.DestinationDomain != "":
+		if !slices.ContainsFunc(rule.Domains, func(d domain.Domain) bool { return domainMatches(string(d), req.DestinationDomain) }) {
+			return false
+		}
+	}
+
+	if !protocolMatches(PolicyRuleProtocolType(rule.Protocol), req.Protocol) {
+		return false
+	}
+
+	switch {
+	case rule.Port != 0:
+		return req.Port == rule.Port
+	case rule.PortRange.Start != 0:
+		return req.Port >= rule.PortRange.Start && req.Port <= rule.PortRange.End
+	default:
+		return true
+	}
+}
+
+func policyRulePortMatches(rule *PolicyRule, port uint16) bool {
+	if len(rule.Ports) == 0 && len(rule.PortRanges) == 0 {
+		return true
+	}
+	if slices.Contains(rule.Ports, strconv.Itoa(int(port))) {
+		return true
+	}
+	return slices.ContainsFunc(rule.PortRanges, func(r RulePortRange) bool {
+		return port >= r.Start && port <= r.End
+	})
+}
+
+// routeRuleBelongsTo checks if the rule was generated for the route
+func routeRuleBelongsTo(rule *RouteFirewallRule, r *route.Route) bool {
+	if r.IsDynamic() {
+		return rule.IsDynamic && rule.Domains.Equal(r.Domains)
+	}
+	return rule.Destination == r.Network.String()
+}
+
+// protocolMatches checks if a rule of the given protocol covers the requested protocol
+func protocolMatches(ruleProtocol, requested PolicyRuleProtocolType) bool {
+	return ruleProtocol == PolicyRuleProtocolALL || ruleProtocol == requested
+}
+
+func resourceMatches(resource *resourceTypes.NetworkResource, req PolicySimulationRequest) bool {
+	switch {
+	case req.DestinationIP.IsValid():
+		return resource.Type != resourceTypes.Domain && resource.Prefix.IsValid() && resource.Prefix.Contains(req.DestinationIP)
+	case req.DestinationDomain != "":
+		return resource.Type == resourceTypes.Domain && domainMatches(resource.Domain, req.DestinationDomain)
+	default:
+		return false
+	}
+}
+
+func routeMatches(r *route.Route, req PolicySimulationRequest) bool {
+	switch {
+	case req.DestinationIP.IsValid():
+		return !r.IsDynamic() && r.Network.Contains(req.DestinationIP)
+	case req.DestinationDomain != "":
+		return slices.ContainsFunc(r.Domains, func(d domain.Domain) bool {
+			return domainMatches(string(d), req.DestinationDomain)
+		})
+	default:
+		return false
+	}
+}
+
+// domainMatches matches a domain against a pattern, a leading "*." matches any subdomain
+func domainMatches(pattern, name string) bool {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(name, suffix)
+	}
+	return pattern == name
+}
+
+func appendUnique(list []string, value string) []string {
+	if slices.Contains(list, value) {
+		return list
+	}
+	return append(list, value)
+}
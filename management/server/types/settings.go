@@ -1,6 +1,7 @@
 package types
 
 import (
+	"slices"
 	"time"
 )
 
@@ -39,6 +40,9 @@ type Settings struct {
 	// RoutingPeerDNSResolutionEnabled enabled the DNS resolution on the routing peers
 	RoutingPeerDNSResolutionEnabled bool
 
+	// PostQuantumRequiredGroups list of groups whose peers refuse connections without the Rosenpass post-quantum key exchange
+	PostQuantumRequiredGroups []string `gorm:"serializer:json"`
+
 	// Extra is a dictionary of Account settings
 	Extra *ExtraSettings `gorm:"embedded;embeddedPrefix:extra_"`
 }
@@ -58,6 +62,7 @@ func (s *Settings) Copy() *Settings {
 		PeerInactivityExpiration:        s.PeerInactivityExpiration,
 
 		RoutingPeerDNSResolutionEnabled: s.RoutingPeerDNSResolutionEnabled,
+		PostQuantumRequiredGroups:       slices.Clone(s.PostQuantumRequiredGroups),
 	}
 	if s.Extra != nil {
 		settings.Extra = s.Extra.Copy()
@@ -57,6 +57,8 @@ type SetupKey struct {
 	Ephemeral bool
 	// AllowExtraDNSLabels indicates if the key allows extra DNS labels
 	AllowExtraDNSLabels bool
+	// Constraints restrict which devices can register with the key
+	Constraints SetupKeyConstraints `gorm:"embedded;embeddedPrefix:constraints_"`
 }
 
 // Copy copies SetupKey to a new object
@@ -83,6 +85,7 @@ func (key *SetupKey) Copy() *SetupKey {
 		UsageLimit:          key.UsageLimit,
 		Ephemeral:           key.Ephemeral,
 		AllowExtraDNSLabels: key.AllowExtraDNSLabels,
+		Constraints:         key.Constraints.Copy(),
 	}
 }
 
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+// SetupKeyConstraintOSTypes are the operating systems a setup key can be restricted to, as reported by the peer GoOS
+var SetupKeyConstraintOSTypes = []string{"linux", "darwin", "windows", "android", "ios", "freebsd"}
+
+// SetupKeyConstraints restrict which devices can register with a setup key, empty constraints allow any device
+type SetupKeyConstraints struct {
+	// InventoryDevicesOnly allows only devices listed in the account inventory to register
+	InventoryDevicesOnly bool
+	// AllowedCIDRs are the networks the peer public connection IP must belong to
+	AllowedCIDRs []string `gorm:"serializer:json"`
+	// HostnamePattern is a regular expression the whole peer hostname must match
+	HostnamePattern string
+	// AllowedOS are the operating systems of the peers allowed to register
+	AllowedOS []string `gorm:"serializer:json"`
+	// MinClientVersion is the minimum NetBird client version of the peers allowed to register
+	MinClientVersion string
+}
+
+// Copy returns a copy of the setup key constraints
+func (c SetupKeyConstraints) Copy() SetupKeyConstraints {
+	c.AllowedCIDRs = slices.Clone(c.AllowedCIDRs)
+	c.AllowedOS = slices.Clone(c.AllowedOS)
+	return c
+}
+
+// Validate checks the constraints are well-formed
+func (c SetupKeyConstraints) Validate() error {
+	for _, cidr := range c.AllowedCIDRs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid allowed CIDR %q", cidr)
+		}
+	}
+
+	if c.HostnamePattern != "" {
+		if _, err := regexp.Compile(c.HostnamePattern); err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid hostname pattern: %v", err)
+		}
+	}
+
+	for _, osType := range c.AllowedOS {
+		if !slices.Contains(SetupKeyConstraintOSTypes, osType) {
+			return status.Errorf(status.InvalidArgument, "invalid allowed OS %q, valid values are %s", osType, strings.Join(SetupKeyConstraintOSTypes, ", "))
+		}
+	}
+
+	if c.MinClientVersion != "" {
+		if _, err := version.NewVersion(c.MinClientVersion); err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid minimum client version %q", c.MinClientVersion)
+		}
+	}
+
+	return nil
+}
+
+// CheckPeer checks the registering peer against the constraints that don't depend on the account state.
+// It returns the reason the peer is rejected or an empty string when the peer satisfies the constraints.
+func (c SetupKeyConstraints) CheckPeer(peer *nbpeer.Peer) string {
+	if len(c.AllowedCIDRs) > 0 {
+		if reason := checkConnectionIP(c.AllowedCIDRs, peer); reason != "" {
+			return reason
+		}
+	}
+
+	if c.HostnamePattern != "" {
+		pattern, err := regexp.Compile("^(?:" + c.HostnamePattern + ")$")
+		if err != nil || !pattern.MatchString(peer.Meta.Hostname) {
+			return fmt.Sprintf("hostname %q doesn't match the allowed pattern", peer.Meta.Hostname)
+		}
+	}
+
+	if len(c.AllowedOS) > 0 && !slices.Contains(c.AllowedOS, strings.ToLower(peer.Meta.GoOS)) {
+		return fmt.Sprintf("operating system %q isn't allowed", peer.Meta.GoOS)
+	}
+
+	if c.MinClientVersion != "" {
+		if !isClientVersionAtLeast(peer.Meta.WtVersion, c.MinClientVersion) {
+			return fmt.Sprintf("client version %q doesn't satisfy the minimum version %s", peer.Meta.WtVersion, c.MinClientVersion)
+		}
+	}
+
+	return ""
+}
+
+func checkConnectionIP(allowedCIDRs []string, peer *nbpeer.Peer) string {
+	ip, ok := netip.AddrFromSlice(peer.Location.ConnectionIP)
+	if !ok {
+		return "connection IP is unknown"
+	}
+	ip = ip.Unmap()
+
+	for _, cidr := range allowedCIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err == nil && prefix.Contains(ip) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("connection IP %s isn't in the allowed networks", ip)
+}
+
+// isClientVersionAtLeast compares versions ignoring pre-release tags, unparsable peer versions are rejected
+func isClientVersionAtLeast(peerVersion, minVersion string) bool {
+	current, err := version.NewVersion(strings.Split(peerVersion, "-")[0])
+	if err != nil {
+		return false
+	}
+	minimum, err := version.NewVersion(strings.Split(minVersion, "-")[0])
+	if err != nil {
+		return false
+	}
+	return current.GreaterThanOrEqual(minimum)
+}
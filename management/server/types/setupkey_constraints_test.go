@@ -0,0 +1,106 @@
+package types
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestSetupKeyConstraints_Validate(t *testing.T) {
+	valid := SetupKeyConstraints{
+		AllowedCIDRs:     []string{"10.0.0.0/8", "2001:db8::/32"},
+		HostnamePattern:  "ci-[a-z]+",
+		AllowedOS:        []string{"linux", "darwin"},
+		MinClientVersion: "0.40.0",
+	}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, SetupKeyConstraints{}.Validate())
+
+	invalid := []SetupKeyConstraints{
+		{AllowedCIDRs: []string{"10.0.0.1"}},
+		{HostnamePattern: "ci-[a-z"},
+		{AllowedOS: []string{"plan9"}},
+		{MinClientVersion: "latest"},
+	}
+	for _, constraints := range invalid {
+		assert.Error(t, constraints.Validate(), "constraints %+v should be invalid", constraints)
+	}
+}
+
+func TestSetupKeyConstraints_CheckPeer(t *testing.T) {
+	constraints := SetupKeyConstraints{
+		AllowedCIDRs:     []string{"203.0.113.0/24"},
+		HostnamePattern:  "ci-runner-[0-9]+",
+		AllowedOS:        []string{"linux"},
+		MinClientVersion: "0.40.0",
+	}
+
+	newPeer := func(hostname, goos, version, connectionIP string) *nbpeer.Peer {
+		return &nbpeer.Peer{
+			Meta:     nbpeer.PeerSystemMeta{Hostname: hostname, GoOS: goos, WtVersion: version},
+			Location: nbpeer.Location{ConnectionIP: net.ParseIP(connectionIP)},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		peer   *nbpeer.Peer
+		reason string
+	}{
+		{
+			name: "allowed peer",
+			peer: newPeer("ci-runner-1", "linux", "0.40.0-dev", "203.0.113.1"),
+		},
+		{
+			name:   "connection IP outside of the allowed networks",
+			peer:   newPeer("ci-runner-1", "linux", "0.41.0", "198.51.100.1"),
+			reason: "connection IP 198.51.100.1 isn't in the allowed networks",
+		},
+		{
+			name:   "unknown connection IP",
+			peer:   newPeer("ci-runner-1", "linux", "0.41.0", ""),
+			reason: "connection IP is unknown",
+		},
+		{
+			name:   "hostname matching only partially",
+			peer:   newPeer("ci-runner-1.example.com", "linux", "0.41.0", "203.0.113.1"),
+			reason: `hostname "ci-runner-1.example.com" doesn't match the allowed pattern`,
+		},
+		{
+			name:   "operating system not allowed",
+			peer:   newPeer("ci-runner-1", "windows", "0.41.0", "203.0.113.1"),
+			reason: `operating system "windows" isn't allowed`,
+		},
+		{
+			name:   "old client version",
+			peer:   newPeer("ci-runner-1", "linux", "0.39.9", "203.0.113.1"),
+			reason: `client version "0.39.9" doesn't satisfy the minimum version 0.40.0`,
+		},
+		{
+			name:   "development client version",
+			peer:   newPeer("ci-runner-1", "linux", "development", "203.0.113.1"),
+			reason: `client version "development" doesn't satisfy the minimum version 0.40.0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.reason, constraints.CheckPeer(tt.peer))
+		})
+	}
+
+	assert.Empty(t, SetupKeyConstraints{}.CheckPeer(newPeer("laptop", "windows", "development", "")))
+}
+
+func TestSetupKeyConstraints_Copy(t *testing.T) {
+	constraints := SetupKeyConstraints{AllowedCIDRs: []string{"10.0.0.0/8"}, AllowedOS: []string{"linux"}}
+	constraintsCopy := constraints.Copy()
+	constraintsCopy.AllowedCIDRs[0] = "192.168.0.0/16"
+	constraintsCopy.AllowedOS[0] = "darwin"
+
+	assert.Equal(t, "10.0.0.0/8", constraints.AllowedCIDRs[0])
+	assert.Equal(t, "linux", constraints.AllowedOS[0])
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/netbirdio/netbird/management/server/activity"
 	nbContext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/ha"
 	"github.com/netbirdio/netbird/management/server/idp"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/status"
@@ -22,7 +23,10 @@ import (
 
 // createServiceUser creates a new service user under the given account.
 func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountID string, initiatorUserID string, role types.UserRole, serviceUserName string, nonDeletable bool, autoGroups []string) (*types.UserInfo, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, initiatorUserID)
@@ -77,7 +81,10 @@ func (am *DefaultAccountManager) CreateUser(ctx context.Context, accountID, user
 
 // inviteNewUser Invites a USer to a given account and creates reference in datastore
 func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, userID string, invite *types.UserInfo) (*types.UserInfo, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	if am.idpManager == nil {
@@ -229,7 +236,10 @@ func (am *DefaultAccountManager) DeleteUser(ctx context.Context, accountID, init
 		return status.Errorf(status.InvalidArgument, "self deletion is not allowed")
 	}
 
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, initiatorUserID)
@@ -287,7 +297,10 @@ func (am *DefaultAccountManager) DeleteUser(ctx context.Context, accountID, init
 
 // InviteUser resend invitations to users who haven't activated their accounts prior to the expiration period.
 func (am *DefaultAccountManager) InviteUser(ctx context.Context, accountID string, initiatorUserID string, targetUserID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	if am.idpManager == nil {
@@ -331,7 +344,10 @@ func (am *DefaultAccountManager) InviteUser(ctx context.Context, accountID strin
 
 // CreatePAT creates a new PAT for the given user
 func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int) (*types.PersonalAccessTokenGenerated, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	if tokenName == "" {
@@ -377,7 +393,10 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 
 // DeletePAT deletes a specific PAT from a user
 func (am *DefaultAccountManager) DeletePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenID string) error {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return err
+	}
 	defer unlock()
 
 	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, initiatorUserID)
@@ -457,7 +476,10 @@ func (am *DefaultAccountManager) SaveUser(ctx context.Context, accountID, initia
 // SaveOrAddUser updates the given user. If addIfNotExists is set to true it will add user when no exist
 // Only User.AutoGroups, User.Role, and User.Blocked fields are allowed to be updated for now.
 func (am *DefaultAccountManager) SaveOrAddUser(ctx context.Context, accountID, initiatorUserID string, update *types.User, addIfNotExists bool) (*types.UserInfo, error) {
-	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	unlock, err := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 
 	updatedUsers, err := am.SaveOrAddUsers(ctx, accountID, initiatorUserID, []*types.User{update}, addIfNotExists)
@@ -533,6 +555,14 @@ func (am *DefaultAccountManager) SaveOrAddUsers(ctx context.Context, accountID,
 				updateAccountPeers = true
 			}
 		}
+
+		// dynamic groups may select peers by the groups the user peers just joined or left
+		if settings.GroupsPropagationEnabled && updateAccountPeers {
+			if _, err = refreshDynamicGroups(ctx, transaction, accountID); err != nil {
+				return fmt.Errorf("failed to refresh dynamic groups: %w", err)
+			}
+		}
+
 		return transaction.SaveUsers(ctx, store.LockingStrengthUpdate, usersToSave)
 	})
 	if err != nil {
@@ -756,7 +786,10 @@ func validateUserUpdate(groupsMap map[string]*types.Group, initiatorUser, oldUse
 // GetOrCreateAccountByUser returns an existing account for a given user id or creates a new one if doesn't exist
 func (am *DefaultAccountManager) GetOrCreateAccountByUser(ctx context.Context, userID, domain string) (*types.Account, error) {
 	start := time.Now()
-	unlock := am.Store.AcquireGlobalLock(ctx)
+	unlock, err := am.Store.AcquireGlobalLock(ctx)
+	if err != nil {
+		return nil, err
+	}
 	defer unlock()
 	log.WithContext(ctx).Debugf("Acquired global lock in %s for user %s", time.Since(start), userID)
 
@@ -812,6 +845,42 @@ func (am *DefaultAccountManager) GetUsersFromAccount(ctx context.Context, accoun
 	return am.BuildUserInfosForAccount(ctx, accountID, initiatorUserID, accountUsers)
 }
 
+// GetUsersPage returns a page of the account users that match the filter and the cursor of the next page
+func (am *DefaultAccountManager) GetUsersPage(ctx context.Context, accountID, initiatorUserID string, filter types.UserFilter, page types.PageRequest) ([]*types.UserInfo, string, error) {
+	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, initiatorUserID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := am.permissionsManager.ValidateAccountAccess(ctx, accountID, initiatorUser, false); err != nil {
+		return nil, "", err
+	}
+
+	if initiatorUser.IsRegularUser() {
+		// regular users can only see themselves
+		filter.IDs = []string{initiatorUser.Id}
+	}
+
+	accountUsers, cursor, err := am.Store.GetAccountUsersPage(ctx, store.LockingStrengthShare, accountID, filter, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	userInfosMap, err := am.BuildUserInfosForAccount(ctx, accountID, initiatorUserID, accountUsers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	userInfos := make([]*types.UserInfo, 0, len(accountUsers))
+	for _, user := range accountUsers {
+		if info, ok := userInfosMap[user.Id]; ok {
+			userInfos = append(userInfos, info)
+		}
+	}
+
+	return userInfos, cursor, nil
+}
+
 // BuildUserInfosForAccount builds user info for the given account.
 func (am *DefaultAccountManager) BuildUserInfosForAccount(ctx context.Context, accountID, initiatorUserID string, accountUsers []*types.User) (map[string]*types.UserInfo, error) {
 	var queriedUsers []*idp.UserData
@@ -945,6 +1014,7 @@ func (am *DefaultAccountManager) expireAndUpdatePeers(ctx context.Context, accou
 	if len(peerIDs) != 0 {
 		// this will trigger peer disconnect from the management service
 		am.peersUpdateManager.CloseChannels(ctx, peerIDs)
+		am.publishInstanceEvent(ctx, &ha.Event{Type: ha.PeersDisconnected, AccountID: accountID, PeerIDs: peerIDs})
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 	return nil
@@ -0,0 +1,241 @@
+// Package admin implements the HTTP admin API of the relay server. It lists the connected peers with their traffic,
+// force-disconnects peers and drains the relay server before the shutdown.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/relay/messages"
+	"github.com/netbirdio/netbird/relay/server"
+)
+
+const (
+	hashedIDPrefix      = "sha-"
+	disconnectTimeout   = 5 * time.Second
+	defaultDrainTimeout = 5 * time.Minute
+)
+
+// Relay is the part of the relay server used by the admin API
+type Relay interface {
+	InstanceURL() string
+	Peers() []server.PeerInfo
+	Peer(id string) (server.PeerInfo, error)
+	DisconnectPeer(ctx context.Context, id string) error
+	Drain(ctx context.Context, period time.Duration)
+	Draining() bool
+}
+
+// Status is the response of the status endpoint
+type Status struct {
+	InstanceURL string `json:"instance_url"`
+	Draining    bool   `json:"draining"`
+	Peers       int    `json:"peers"`
+}
+
+// Peer is a peer connected to the relay server
+type Peer struct {
+	ID          string    `json:"id"`
+	RemoteAddr  string    `json:"remote_addr"`
+	Listener    string    `json:"listener"`
+	ConnectedAt time.Time `json:"connected_at"`
+	BytesIn     uint64    `json:"bytes_in"`
+	BytesOut    uint64    `json:"bytes_out"`
+	OpenStreams int       `json:"open_streams"`
+}
+
+// DrainRequest is the optional body of the drain endpoint. Period is a duration string, e.g. "2m", the peer
+// disconnects are spread over it.
+type DrainRequest struct {
+	Period string `json:"period"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+type handler struct {
+	relay Relay
+	token string
+}
+
+// NewHandler creates the admin API handler. Every request has to be authenticated with the token in the
+// "Authorization: Bearer <token>" header.
+//
+// Endpoints:
+//
+//	GET    /api/status      status of the relay instance
+//	GET    /api/peers       connected peers
+//	GET    /api/peers/{id}  connected peer
+//	DELETE /api/peers/{id}  force-disconnect the peer
+//	POST   /api/drain       stop accepting peers and disconnect the connected ones
+//
+// The {id} is the hashed peer ID (URL encoded) or the WireGuard public key of the peer.
+func NewHandler(relay Relay, token string) (http.Handler, error) {
+	if token == "" {
+		return nil, errors.New("admin token is required")
+	}
+
+	h := &handler{
+		relay: relay,
+		token: token,
+	}
+
+	router := mux.NewRouter().UseEncodedPath()
+	router.Use(h.authMiddleware)
+	router.HandleFunc("/api/status", h.getStatus).Methods(http.MethodGet)
+	router.HandleFunc("/api/peers", h.getPeers).Methods(http.MethodGet)
+	router.HandleFunc("/api/peers/{id}", h.getPeer).Methods(http.MethodGet)
+	router.HandleFunc("/api/peers/{id}", h.deletePeer).Methods(http.MethodDelete)
+	router.HandleFunc("/api/drain", h.drain).Methods(http.MethodPost)
+	return router, nil
+}
+
+func (h *handler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *handler) getStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, Status{
+		InstanceURL: h.relay.InstanceURL(),
+		Draining:    h.relay.Draining(),
+		Peers:       len(h.relay.Peers()),
+	})
+}
+
+func (h *handler) getPeers(w http.ResponseWriter, _ *http.Request) {
+	infos := h.relay.Peers()
+	peers := make([]Peer, 0, len(infos))
+	for _, info := range infos {
+		peers = append(peers, toPeer(info))
+	}
+	writeJSON(w, http.StatusOK, peers)
+}
+
+func (h *handler) getPeer(w http.ResponseWriter, r *http.Request) {
+	id, err := peerID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	info, err := h.relay.Peer(id)
+	if err != nil {
+		writePeerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPeer(info))
+}
+
+func (h *handler) deletePeer(w http.ResponseWriter, r *http.Request) {
+	id, err := peerID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), disconnectTimeout)
+	defer cancel()
+	if err := h.relay.DisconnectPeer(ctx, id); err != nil {
+		writePeerError(w, err)
+		return
+	}
+	log.Infof("peer %s disconnected by admin request", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) drain(w http.ResponseWriter, r *http.Request) {
+	var req DrainRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "couldn't parse JSON request")
+			return
+		}
+	}
+
+	var period time.Duration
+	if req.Period != "" {
+		var err error
+		period, err = time.ParseDuration(req.Period)
+		if err != nil || period < 0 {
+			writeError(w, http.StatusBadRequest, "invalid drain period")
+			return
+		}
+	}
+
+	if h.relay.Draining() {
+		writeError(w, http.StatusConflict, "relay server is already draining")
+		return
+	}
+
+	// the drain continues after the response, its context is not bound to the request
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), period+defaultDrainTimeout)
+		defer cancel()
+		h.relay.Drain(ctx, period)
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// peerID returns the hashed ID of the peer from the path. The path contains the URL encoded hashed ID or the
+// WireGuard public key of the peer.
+func peerID(r *http.Request) (string, error) {
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil || id == "" {
+		return "", errors.New("invalid peer ID")
+	}
+
+	if strings.HasPrefix(id, hashedIDPrefix) {
+		return id, nil
+	}
+	_, hashedID := messages.HashID(id)
+	return hashedID, nil
+}
+
+func toPeer(info server.PeerInfo) Peer {
+	return Peer{
+		ID:          info.ID,
+		RemoteAddr:  info.RemoteAddr,
+		Listener:    info.Listener,
+		ConnectedAt: info.ConnectedAt,
+		BytesIn:     info.BytesIn,
+		BytesOut:    info.BytesOut,
+		OpenStreams: info.OpenStreams,
+	}
+}
+
+func writePeerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, server.ErrPeerNotConnected) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Message: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		log.Errorf("failed to encode admin API response: %s", err)
+	}
+}
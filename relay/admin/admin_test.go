@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"github.com/netbirdio/netbird/relay/auth/allow"
+	"github.com/netbirdio/netbird/relay/auth/hmac"
+	"github.com/netbirdio/netbird/relay/client"
+	"github.com/netbirdio/netbird/relay/messages"
+	"github.com/netbirdio/netbird/relay/server"
+)
+
+const testToken = "secret-token"
+
+func newTestServer(t *testing.T, address string) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(otel.Meter(""), "rel://"+address, false, &allow.Auth{})
+	require.NoError(t, err)
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := srv.Listen(server.ListenerConfig{Address: address}); err != nil {
+			errChan <- err
+		}
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("failed to start server: %s", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+	return srv
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewHandler_RequiresToken(t *testing.T) {
+	_, err := NewHandler(nil, "")
+	assert.Error(t, err)
+}
+
+func TestAdminAPI(t *testing.T) {
+	srv := newTestServer(t, "127.0.0.1:1238")
+	handler, err := NewHandler(srv, testToken)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tokenStore := &hmac.TokenStore{}
+	alice := client.NewClient(ctx, srv.InstanceURL(), tokenStore, "alice")
+	require.NoError(t, alice.Connect())
+	defer alice.Close()
+	bob := client.NewClient(ctx, srv.InstanceURL(), tokenStore, "bob")
+	require.NoError(t, bob.Connect())
+	defer bob.Close()
+
+	conn, err := alice.OpenConn("bob")
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	t.Run("unauthorized", func(t *testing.T) {
+		rec := doRequest(t, handler, http.MethodGet, "/api/peers", "", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec = doRequest(t, handler, http.MethodGet, "/api/peers", "wrong", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	_, aliceID := messages.HashID("alice")
+	_, bobID := messages.HashID("bob")
+
+	require.Eventually(t, func() bool {
+		info, err := srv.Peer(aliceID)
+		return err == nil && info.OpenStreams == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	t.Run("list peers", func(t *testing.T) {
+		rec := doRequest(t, handler, http.MethodGet, "/api/peers", testToken, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var peers []Peer
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &peers))
+		require.Len(t, peers, 2)
+
+		ids := []string{peers[0].ID, peers[1].ID}
+		assert.ElementsMatch(t, []string{aliceID, bobID}, ids)
+		for _, p := range peers {
+			assert.Equal(t, "ws", p.Listener)
+			assert.False(t, p.ConnectedAt.IsZero())
+			if p.ID == aliceID {
+				assert.NotZero(t, p.BytesIn)
+			} else {
+				assert.NotZero(t, p.BytesOut)
+			}
+		}
+	})
+
+	t.Run("get peer by hashed ID and by key", func(t *testing.T) {
+		rec := doRequest(t, handler, http.MethodGet, "/api/peers/"+url.PathEscape(aliceID), testToken, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var peer Peer
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &peer))
+		assert.Equal(t, aliceID, peer.ID)
+		assert.Equal(t, 1, peer.OpenStreams)
+
+		rec = doRequest(t, handler, http.MethodGet, "/api/peers/bob", testToken, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &peer))
+		assert.Equal(t, bobID, peer.ID)
+	})
+
+	t.Run("unknown peer", func(t *testing.T) {
+		rec := doRequest(t, handler, http.MethodGet, "/api/peers/carol", testToken, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = doRequest(t, handler, http.MethodDelete, "/api/peers/carol", testToken, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("disconnect peer", func(t *testing.T) {
+		rec := doRequest(t, handler, http.MethodDelete, "/api/peers/bob", testToken, "")
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		assert.Eventually(t, func() bool {
+			return !bob.Ready() && len(srv.Peers()) == 1
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("drain", func(t *testing.T) {
+		rec := doRequest(t, handler, http.MethodPost, "/api/drain", testToken, `{"period": "invalid"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = doRequest(t, handler, http.MethodPost, "/api/drain", testToken, "")
+		require.Equal(t, http.StatusAccepted, rec.Code)
+
+		assert.Eventually(t, func() bool {
+			return !alice.Ready() && len(srv.Peers()) == 0
+		}, 5*time.Second, 50*time.Millisecond)
+
+		rec = doRequest(t, handler, http.MethodGet, "/api/status", testToken, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var status Status
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		assert.True(t, status.Draining)
+		assert.Equal(t, srv.InstanceURL(), status.InstanceURL)
+
+		rec = doRequest(t, handler, http.MethodPost, "/api/drain", testToken, "")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		carol := client.NewClient(ctx, srv.InstanceURL(), tokenStore, "carol")
+		assert.Error(t, carol.Connect(), "draining server should reject new peers")
+	})
+}
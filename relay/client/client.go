@@ -1,10 +1,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -20,10 +23,14 @@ import (
 const (
 	bufferSize            = 8820
 	serverResponseTimeout = 8 * time.Second
+
+	// clientFeatures are the protocol extensions supported by the client
+	clientFeatures = messages.FeatureClusterInfo
 )
 
 var (
 	ErrConnAlreadyExists = fmt.Errorf("connection already exists")
+	ErrProbeNotSupported = fmt.Errorf("relay server does not support health check probes")
 )
 
 type internalStopFlag struct {
@@ -138,7 +145,15 @@ type Client struct {
 	readLoopMutex    sync.Mutex
 	wgReadLoop       sync.WaitGroup
 	instanceURL      *RelayAddr
+	clusterMembers   []string
 	muInstanceURL    sync.Mutex
+	// serverFeatures are the protocol extensions supported by the connected server, protected by mu
+	serverFeatures messages.Features
+
+	handshakeRTT atomic.Int64
+	probeID      atomic.Uint64
+	probes       map[uint64]chan struct{}
+	probesMu     sync.Mutex
 
 	onDisconnectListener func(string)
 	listenerMutex        sync.Mutex
@@ -159,7 +174,8 @@ func NewClient(ctx context.Context, serverURL string, authTokenStore *auth.Token
 				return &buf
 			},
 		},
-		conns: make(map[string]*connContainer),
+		conns:  make(map[string]*connContainer),
+		probes: make(map[uint64]chan struct{}),
 	}
 	c.log.Infof("create new relay connection: local peerID: %s, local peer hashedID: %s", peerID, hashedStringId)
 	return c
@@ -229,6 +245,61 @@ func (c *Client) ServerInstanceURL() (string, error) {
 	return c.instanceURL.String(), nil
 }
 
+// IsClusterMember returns true if the relay server with the given instance URL is a member of the relay cluster of the
+// connected server. The peers connected to the cluster members are reachable via this client.
+func (c *Client) IsClusterMember(instanceURL string) bool {
+	c.muInstanceURL.Lock()
+	defer c.muInstanceURL.Unlock()
+	return slices.Contains(c.clusterMembers, instanceURL)
+}
+
+// Ping measures the round-trip time to the relay server with a health check probe. It returns ErrProbeNotSupported
+// if the server did not report the probe support in the features exchange.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	c.mu.Lock()
+	if !c.serviceIsRunning {
+		c.mu.Unlock()
+		return 0, fmt.Errorf("relay connection is not established")
+	}
+	relayConn := c.relayConn
+	serverFeatures := c.serverFeatures
+	c.mu.Unlock()
+
+	if !serverFeatures.Has(messages.FeatureHealthcheckProbe) {
+		return 0, ErrProbeNotSupported
+	}
+
+	id := c.probeID.Add(1)
+	echo := make(chan struct{})
+	c.probesMu.Lock()
+	c.probes[id] = echo
+	c.probesMu.Unlock()
+
+	defer func() {
+		c.probesMu.Lock()
+		delete(c.probes, id)
+		c.probesMu.Unlock()
+	}()
+
+	start := time.Now()
+	if _, err := relayConn.Write(messages.MarshalHealthcheckProbe(id)); err != nil {
+		return 0, fmt.Errorf("send health check probe: %w", err)
+	}
+
+	select {
+	case <-echo:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		return 0, fmt.Errorf("wait for health check probe echo: %w", ctx.Err())
+	}
+}
+
+// HandshakeRTT returns the duration of the last authentication handshake with the relay server. It is an
+// approximation of the round-trip time for the servers that do not answer the health check probes.
+func (c *Client) HandshakeRTT() time.Duration {
+	return time.Duration(c.handshakeRTT.Load())
+}
+
 // SetOnDisconnectListener sets a function that will be called when the connection to the relay server is closed.
 func (c *Client) SetOnDisconnectListener(fn func(string)) {
 	c.listenerMutex.Lock()
@@ -280,6 +351,7 @@ func (c *Client) handShake() error {
 		return err
 	}
 
+	start := time.Now()
 	_, err = c.relayConn.Write(msg)
 	if err != nil {
 		c.log.Errorf("failed to send auth message: %s", err)
@@ -291,6 +363,7 @@ func (c *Client) handShake() error {
 		c.log.Errorf("failed to read auth response: %s", err)
 		return err
 	}
+	c.handshakeRTT.Store(int64(time.Since(start)))
 
 	_, err = messages.ValidateVersion(buf[:n])
 	if err != nil {
@@ -316,7 +389,76 @@ func (c *Client) handShake() error {
 	c.muInstanceURL.Lock()
 	c.instanceURL = &RelayAddr{addr: addr}
 	c.muInstanceURL.Unlock()
-	return nil
+
+	return c.exchangeFeatures()
+}
+
+// exchangeFeatures reports the features of the client to the server and learns the features of the server. The
+// servers without features support relay the request back, their feature set is empty. The messages of other peers
+// received in the meantime are dropped.
+func (c *Client) exchangeFeatures() error {
+	msg, err := messages.MarshalFeaturesRequest(c.hashedID, clientFeatures)
+	if err != nil {
+		return fmt.Errorf("marshal features request: %w", err)
+	}
+
+	if _, err := c.relayConn.Write(msg); err != nil {
+		return fmt.Errorf("send features request: %w", err)
+	}
+
+	buf := make([]byte, bufferSize)
+	deadline := time.Now().Add(serverResponseTimeout)
+	for time.Now().Before(deadline) {
+		n, err := c.readWithTimeout(buf)
+		if err != nil {
+			return fmt.Errorf("read features response: %w", err)
+		}
+
+		features, ok := c.parseFeaturesResponse(buf[:n])
+		if !ok {
+			continue
+		}
+
+		c.serverFeatures = features
+		c.log.Debugf("relay server features: %b", features)
+		return nil
+	}
+	return fmt.Errorf("features response timed out")
+}
+
+// parseFeaturesResponse returns the features of the server if the message is the response to the features request
+func (c *Client) parseFeaturesResponse(msg []byte) (messages.Features, bool) {
+	if _, err := messages.ValidateVersion(msg); err != nil {
+		c.log.Debugf("drop message during the features exchange: %s", err)
+		return 0, false
+	}
+
+	msgType, err := messages.DetermineServerMessageType(msg)
+	if err != nil {
+		c.log.Debugf("drop message during the features exchange: %s", err)
+		return 0, false
+	}
+
+	switch msgType {
+	case messages.MsgTypeFeatures:
+		features, err := messages.UnmarshalFeaturesMsg(msg)
+		if err != nil {
+			c.log.Errorf("failed to parse features message: %s", err)
+			return 0, false
+		}
+		return features, true
+	case messages.MsgTypeTransport:
+		peerID, payload, err := messages.UnmarshalTransportMsg(msg)
+		if err != nil {
+			return 0, false
+		}
+		if _, ok := messages.UnmarshalFeaturesRequest(payload); ok && bytes.Equal(peerID, c.hashedID) {
+			return 0, true
+		}
+	}
+
+	c.log.Debugf("drop %s message during the features exchange", msgType)
+	return 0, false
 }
 
 func (c *Client) readLoop(relayConn net.Conn) {
@@ -368,6 +510,7 @@ func (c *Client) readLoop(relayConn net.Conn) {
 
 	c.muInstanceURL.Lock()
 	c.instanceURL = nil
+	c.clusterMembers = nil
 	c.muInstanceURL.Unlock()
 
 	c.wgReadLoop.Done()
@@ -378,10 +521,17 @@ func (c *Client) readLoop(relayConn net.Conn) {
 func (c *Client) handleMsg(msgType messages.MsgType, buf []byte, bufPtr *[]byte, hc *healthcheck.Receiver, internallyStoppedFlag *internalStopFlag) (continueLoop bool) {
 	switch msgType {
 	case messages.MsgTypeHealthCheck:
-		c.handleHealthCheck(hc, internallyStoppedFlag)
+		if id, ok := messages.UnmarshalHealthcheckProbe(buf); ok {
+			c.handleProbeEcho(id)
+		} else {
+			c.handleHealthCheck(hc, internallyStoppedFlag)
+		}
 		c.bufPool.Put(bufPtr)
 	case messages.MsgTypeTransport:
 		return c.handleTransportMsg(buf, bufPtr, internallyStoppedFlag)
+	case messages.MsgTypeClusterInfo:
+		c.handleClusterInfoMsg(buf)
+		c.bufPool.Put(bufPtr)
 	case messages.MsgTypeClose:
 		c.log.Debugf("relay connection close by server")
 		c.bufPool.Put(bufPtr)
@@ -402,6 +552,32 @@ func (c *Client) handleHealthCheck(hc *healthcheck.Receiver, internallyStoppedFl
 	hc.Heartbeat()
 }
 
+func (c *Client) handleProbeEcho(id uint64) {
+	c.probesMu.Lock()
+	defer c.probesMu.Unlock()
+
+	echo, ok := c.probes[id]
+	if !ok {
+		c.log.Debugf("received echo of unknown health check probe: %d", id)
+		return
+	}
+	close(echo)
+	delete(c.probes, id)
+}
+
+func (c *Client) handleClusterInfoMsg(buf []byte) {
+	members, err := messages.UnmarshalClusterInfoMsg(buf)
+	if err != nil {
+		c.log.Errorf("failed to parse cluster info message: %v", err)
+		return
+	}
+
+	c.log.Debugf("relay cluster members: %v", members)
+	c.muInstanceURL.Lock()
+	c.clusterMembers = members
+	c.muInstanceURL.Unlock()
+}
+
 func (c *Client) handleTransportMsg(buf []byte, bufPtr *[]byte, internallyStoppedFlag *internalStopFlag) bool {
 	peerID, payload, err := messages.UnmarshalTransportMsg(buf)
 	if err != nil {
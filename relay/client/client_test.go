@@ -12,6 +12,7 @@ import (
 
 	"github.com/netbirdio/netbird/relay/auth/allow"
 	"github.com/netbirdio/netbird/relay/auth/hmac"
+	"github.com/netbirdio/netbird/relay/messages"
 	"github.com/netbirdio/netbird/util"
 
 	"github.com/netbirdio/netbird/relay/server"
@@ -710,3 +711,35 @@ func waitForServerToStart(errChan chan error) error {
 	}
 	return nil
 }
+
+func TestClient_ParseFeaturesResponse(t *testing.T) {
+	c := NewClient(context.Background(), serverURL, hmacTokenStore, "alice")
+
+	features, ok := c.parseFeaturesResponse(messages.MarshalFeaturesMsg(messages.FeatureClusterInfo))
+	if !ok || features != messages.FeatureClusterInfo {
+		t.Errorf("expected the features of the server, got %b, %t", features, ok)
+	}
+
+	// the servers without features support relay the request back to the client
+	request, err := messages.MarshalFeaturesRequest(c.hashedID, clientFeatures)
+	if err != nil {
+		t.Fatalf("failed to marshal features request: %s", err)
+	}
+	features, ok = c.parseFeaturesResponse(request)
+	if !ok || features != 0 {
+		t.Errorf("expected no features for a relayed request, got %b, %t", features, ok)
+	}
+
+	// the messages of other peers are dropped
+	bobID, _ := messages.HashID("bob")
+	transport, err := messages.MarshalTransportMsg(bobID, []byte("data"))
+	if err != nil {
+		t.Fatalf("failed to marshal transport message: %s", err)
+	}
+	if _, ok := c.parseFeaturesResponse(transport); ok {
+		t.Errorf("transport message of another peer parsed as features response")
+	}
+	if _, ok := c.parseFeaturesResponse(messages.MarshalHealthcheck()); ok {
+		t.Errorf("health check message parsed as features response")
+	}
+}
@@ -0,0 +1,320 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// latencyProbeCount is the number of probes sent to a relay server in a measurement round, the lowest RTT is used
+	latencyProbeCount = 3
+	// probePeerIDSuffix is appended to the peer ID of the temporary measurement connections, so they do not replace
+	// the registration of the peer on the relay server or in the directory of a relay cluster
+	probePeerIDSuffix = "/latency-probe"
+
+	// the home relay is migrated if another relay is faster than migrateRatio of the home RTT and the difference is at
+	// least migrateMinGain in migrateRounds consecutive measurement rounds
+	migrateRatio   = 0.7
+	migrateMinGain = 20 * time.Millisecond
+	migrateRounds  = 2
+)
+
+var (
+	latencyMeasureInterval = 2 * time.Minute
+	// latencyFullMeasureInterval is the interval of the rounds opening temporary connections to all relay servers. In
+	// the other rounds only the connected relay servers and the migration candidate are measured.
+	latencyFullMeasureInterval = 30 * time.Minute
+	latencyProbeTimeout        = 5 * time.Second
+)
+
+// RelayRTT is the result of the latency measurement of a relay server
+type RelayRTT struct {
+	URL        string
+	RTT        time.Duration
+	Err        error
+	Home       bool
+	MeasuredAt time.Time
+}
+
+// homeSelector decides about the migration of the home relay server based on the measured RTTs
+type homeSelector struct {
+	candidate string
+	rounds    int
+}
+
+// evaluate returns the URL of the relay server the home relay should be migrated to
+func (s *homeSelector) evaluate(homeURL string, rtts map[string]RelayRTT) (string, bool) {
+	home, ok := rtts[homeURL]
+	if !ok || home.Err != nil {
+		// the reconnection of an unavailable home relay is the job of the guard
+		s.reset()
+		return "", false
+	}
+
+	var best *RelayRTT
+	for url, r := range rtts {
+		if url == homeURL || r.Err != nil {
+			continue
+		}
+		if best == nil || r.RTT < best.RTT {
+			best = &r
+		}
+	}
+
+	if best == nil || !materiallyBetter(best.RTT, home.RTT) {
+		s.reset()
+		return "", false
+	}
+
+	if best.URL != s.candidate {
+		s.candidate = best.URL
+		s.rounds = 0
+	}
+	s.rounds++
+	if s.rounds < migrateRounds {
+		return "", false
+	}
+
+	s.reset()
+	return best.URL, true
+}
+
+func (s *homeSelector) reset() {
+	s.candidate = ""
+	s.rounds = 0
+}
+
+func materiallyBetter(rtt, homeRTT time.Duration) bool {
+	return float64(rtt) < float64(homeRTT)*migrateRatio && homeRTT-rtt >= migrateMinGain
+}
+
+// RelayRTTs returns the last measured RTTs of the relay servers ordered by URL
+func (m *Manager) RelayRTTs() []RelayRTT {
+	m.relayClientMu.Lock()
+	var homeURL string
+	if m.relayClient != nil {
+		homeURL = m.relayClient.connectionURL
+	}
+	m.relayClientMu.Unlock()
+
+	m.rttsMu.RLock()
+	defer m.rttsMu.RUnlock()
+
+	rtts := make([]RelayRTT, 0, len(m.rtts))
+	for _, r := range m.rtts {
+		r.Home = r.URL == homeURL
+		rtts = append(rtts, r)
+	}
+	sort.Slice(rtts, func(i, j int) bool {
+		return rtts[i].URL < rtts[j].URL
+	})
+	return rtts
+}
+
+func (m *Manager) startLatencyLoop() {
+	ticker := time.NewTicker(latencyMeasureInterval)
+	defer ticker.Stop()
+
+	m.measureLatencies()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.measureLatencies()
+		}
+	}
+}
+
+// measureLatencies measures the RTT of the relay servers and migrates the home relay if a materially better one is
+// available. The relay servers without an existing connection are measured only in the full rounds or while they are
+// the migration candidate, otherwise their previous result is kept.
+func (m *Manager) measureLatencies() {
+	m.relayClientMu.Lock()
+	home := m.relayClient
+	m.relayClientMu.Unlock()
+
+	m.rttsMu.RLock()
+	previous := m.rtts
+	m.rttsMu.RUnlock()
+
+	full := time.Since(m.lastFullMeasurement) >= latencyFullMeasureInterval
+	if full {
+		m.lastFullMeasurement = time.Now()
+	}
+
+	urls := m.ServerURLs()
+	results := make(map[string]RelayRTT, len(urls))
+	var (
+		wg        sync.WaitGroup
+		resultsMu sync.Mutex
+	)
+	concurrentLimiter := make(chan struct{}, maxConcurrentServers)
+	for _, url := range urls {
+		existing := m.connectedClient(url, home)
+		if existing == nil && !full && url != m.homeSelector.candidate {
+			if r, ok := previous[url]; ok {
+				resultsMu.Lock()
+				results[url] = r
+				resultsMu.Unlock()
+				continue
+			}
+		}
+
+		wg.Add(1)
+		concurrentLimiter <- struct{}{}
+		go func(url string) {
+			defer func() {
+				<-concurrentLimiter
+				wg.Done()
+			}()
+
+			rtt, err := m.measure(url, existing)
+			if err != nil {
+				log.Debugf("failed to measure RTT of relay server %s: %s", url, err)
+			}
+
+			resultsMu.Lock()
+			results[url] = RelayRTT{URL: url, RTT: rtt, Err: err, MeasuredAt: time.Now()}
+			resultsMu.Unlock()
+		}(url)
+	}
+	wg.Wait()
+
+	m.rttsMu.Lock()
+	m.rtts = results
+	m.rttsMu.Unlock()
+
+	if home == nil || m.ctx.Err() != nil {
+		return
+	}
+
+	target, ok := m.homeSelector.evaluate(home.connectionURL, results)
+	if !ok {
+		return
+	}
+	log.Infof("relay server %s is faster than the home relay server %s (%s vs %s), migrating",
+		target, home.connectionURL, results[target].RTT, results[home.connectionURL].RTT)
+	m.migrateHome(home, target)
+}
+
+// connectedClient returns the home or foreign relay client connected to the relay server, or nil if there is none
+func (m *Manager) connectedClient(url string, home *Client) *Client {
+	if home != nil && home.connectionURL == url {
+		return home
+	}
+
+	m.relayClientsMutex.RLock()
+	defer m.relayClientsMutex.RUnlock()
+
+	for _, rt := range m.relayClients {
+		// a locked track is still connecting
+		if !rt.TryRLock() {
+			continue
+		}
+		relayClient, err := rt.relayClient, rt.err
+		rt.RUnlock()
+
+		if err == nil && relayClient != nil && relayClient.connectionURL == url {
+			return relayClient
+		}
+	}
+	return nil
+}
+
+// measure returns the RTT of the relay server. The relay servers with an existing connection are measured over it,
+// the others over a temporary connection.
+func (m *Manager) measure(url string, existing *Client) (time.Duration, error) {
+	if existing != nil {
+		if !existing.Ready() {
+			return 0, ErrRelayClientNotConnected
+		}
+		return m.ping(existing)
+	}
+
+	relayClient := NewClient(m.ctx, url, m.tokenStore, m.peerID+probePeerIDSuffix)
+	if err := relayClient.Connect(); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := relayClient.Close(); err != nil {
+			log.Debugf("failed to close latency probe connection to %s: %s", url, err)
+		}
+	}()
+	return m.ping(relayClient)
+}
+
+// ping returns the lowest RTT of the health check probes. The servers without probe support are measured by the
+// duration of the authentication handshake.
+func (m *Manager) ping(relayClient *Client) (time.Duration, error) {
+	var best time.Duration
+	for i := 0; i < latencyProbeCount; i++ {
+		ctx, cancel := context.WithTimeout(m.ctx, latencyProbeTimeout)
+		rtt, err := relayClient.Ping(ctx)
+		cancel()
+		if errors.Is(err, ErrProbeNotSupported) {
+			return relayClient.HandshakeRTT(), nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if best == 0 || rtt < best {
+			best = rtt
+		}
+	}
+	return best, nil
+}
+
+// migrateHome replaces the home relay client with a new one connected to the given relay server. The previous home
+// client is kept as a foreign relay until the peer connections move to the new home relay. The reconnected listener
+// triggers new offers to the peers, so they learn the new relay address and switch their connections over.
+func (m *Manager) migrateHome(previous *Client, url string) {
+	relayClient := NewClient(m.ctx, url, m.tokenStore, m.peerID)
+	if err := relayClient.Connect(); err != nil {
+		log.Errorf("failed to connect to relay server %s, keep the home relay server: %s", url, err)
+		return
+	}
+
+	m.relayClientMu.Lock()
+	if m.relayClient != previous || !previous.Ready() {
+		// the guard replaced or reconnects the home relay in the meantime
+		m.relayClientMu.Unlock()
+		log.Infof("home relay server changed during the migration, drop the connection to %s", url)
+		_ = relayClient.Close()
+		return
+	}
+	m.relayClient = relayClient
+	m.relayClient.SetOnDisconnectListener(m.onServerDisconnected)
+	m.relayClientMu.Unlock()
+
+	m.keepPreviousHome(previous)
+	log.Infof("migrated home relay server from %s to %s", previous.connectionURL, url)
+	m.onServerConnected()
+}
+
+func (m *Manager) keepPreviousHome(previous *Client) {
+	instanceURL, err := previous.ServerInstanceURL()
+	if err != nil {
+		_ = previous.Close()
+		return
+	}
+
+	m.relayClientsMutex.Lock()
+	defer m.relayClientsMutex.Unlock()
+
+	if _, ok := m.relayClients[instanceURL]; ok {
+		go func() {
+			_ = previous.Close()
+		}()
+		return
+	}
+
+	rt := NewRelayTrack()
+	rt.relayClient = previous
+	m.relayClients[instanceURL] = rt
+}
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/netbirdio/netbird/relay/server"
+)
+
+func TestHomeSelector(t *testing.T) {
+	rtts := func(home, other time.Duration, otherErr error) map[string]RelayRTT {
+		return map[string]RelayRTT{
+			"home":  {URL: "home", RTT: home},
+			"other": {URL: "other", RTT: other, Err: otherErr},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		rounds  []map[string]RelayRTT
+		migrate bool
+	}{
+		{
+			name:    "materially better relay in consecutive rounds",
+			rounds:  []map[string]RelayRTT{rtts(200*time.Millisecond, 50*time.Millisecond, nil), rtts(180*time.Millisecond, 60*time.Millisecond, nil)},
+			migrate: true,
+		},
+		{
+			name:   "materially better relay in one round only",
+			rounds: []map[string]RelayRTT{rtts(200*time.Millisecond, 50*time.Millisecond, nil)},
+		},
+		{
+			name:   "interrupted by a round without gain",
+			rounds: []map[string]RelayRTT{rtts(200*time.Millisecond, 50*time.Millisecond, nil), rtts(60*time.Millisecond, 50*time.Millisecond, nil), rtts(200*time.Millisecond, 50*time.Millisecond, nil)},
+		},
+		{
+			name:   "relative gain below the ratio",
+			rounds: []map[string]RelayRTT{rtts(200*time.Millisecond, 150*time.Millisecond, nil), rtts(200*time.Millisecond, 150*time.Millisecond, nil)},
+		},
+		{
+			name:   "absolute gain below the minimum",
+			rounds: []map[string]RelayRTT{rtts(20*time.Millisecond, 5*time.Millisecond, nil), rtts(20*time.Millisecond, 5*time.Millisecond, nil)},
+		},
+		{
+			name:   "unavailable relay",
+			rounds: []map[string]RelayRTT{rtts(200*time.Millisecond, 0, errors.New("unavailable")), rtts(200*time.Millisecond, 0, errors.New("unavailable"))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				s       homeSelector
+				target  string
+				migrate bool
+			)
+			for _, round := range tt.rounds {
+				target, migrate = s.evaluate("home", round)
+			}
+			if migrate != tt.migrate {
+				t.Fatalf("expected migrate %t, got %t", tt.migrate, migrate)
+			}
+			if migrate && target != "other" {
+				t.Errorf("expected migration to other, got %s", target)
+			}
+		})
+	}
+}
+
+func TestMigrateHome(t *testing.T) {
+	ctx := context.Background()
+
+	srvCfgs := []server.ListenerConfig{{Address: "localhost:1237"}, {Address: "localhost:2237"}}
+	var urls []string
+	for _, srvCfg := range srvCfgs {
+		srv, err := server.NewServer(otel.Meter(""), srvCfg.Address, false, av)
+		if err != nil {
+			t.Fatalf("failed to create server: %s", err)
+		}
+		errChan := make(chan error, 1)
+		go func(srvCfg server.ListenerConfig) {
+			if err := srv.Listen(srvCfg); err != nil {
+				errChan <- err
+			}
+		}(srvCfg)
+
+		defer func() {
+			if err := srv.Shutdown(ctx); err != nil {
+				t.Errorf("failed to close server: %s", err)
+			}
+		}()
+
+		if err := waitForServerToStart(errChan); err != nil {
+			t.Fatalf("failed to start server: %s", err)
+		}
+		urls = append(urls, toURL(srvCfg)...)
+	}
+
+	mCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mgr := NewManager(mCtx, urls, "alice")
+	reconnected := make(chan struct{}, 1)
+	mgr.SetOnReconnectedListener(func() {
+		reconnected <- struct{}{}
+	})
+	if err := mgr.Serve(); err != nil {
+		t.Fatalf("failed to serve manager: %s", err)
+	}
+
+	// the manager measures the relays right after the start
+	var rtts []RelayRTT
+	timeout := time.After(10 * time.Second)
+	for len(rtts) != len(urls) {
+		select {
+		case <-timeout:
+			t.Fatalf("expected %d RTTs, got %d", len(urls), len(rtts))
+		case <-time.After(100 * time.Millisecond):
+		}
+		rtts = mgr.RelayRTTs()
+	}
+	var home, other string
+	for _, r := range rtts {
+		if r.Err != nil {
+			t.Fatalf("failed to measure RTT of %s: %s", r.URL, r.Err)
+		}
+		if r.RTT <= 0 {
+			t.Errorf("expected positive RTT of %s, got %s", r.URL, r.RTT)
+		}
+		if r.Home {
+			home = r.URL
+		} else {
+			other = r.URL
+		}
+	}
+	if home == "" || other == "" {
+		t.Fatalf("unexpected home relay in RTTs: %v", rtts)
+	}
+
+	homeAddr, err := mgr.RelayInstanceAddress()
+	if err != nil {
+		t.Fatalf("failed to get relay address: %s", err)
+	}
+	conn, err := mgr.OpenConn(homeAddr, "bob")
+	if err != nil {
+		t.Fatalf("failed to open connection: %s", err)
+	}
+	defer conn.Close()
+
+	mgr.relayClientMu.Lock()
+	previous := mgr.relayClient
+	mgr.relayClientMu.Unlock()
+	mgr.migrateHome(previous, other)
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("reconnected listener was not called")
+	}
+
+	newAddr, err := mgr.RelayInstanceAddress()
+	if err != nil {
+		t.Fatalf("failed to get relay address: %s", err)
+	}
+	if newAddr == homeAddr {
+		t.Fatalf("home relay was not migrated from %s", homeAddr)
+	}
+
+	// the connection opened via the previous home relay keeps working until the peers switch over
+	if !previous.Ready() {
+		t.Fatalf("previous home relay client was closed")
+	}
+	if _, err := mgr.OpenConn(homeAddr, "carol"); err != nil {
+		t.Errorf("failed to open connection via previous home relay: %s", err)
+	}
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Errorf("failed to write via previous home relay: %s", err)
+	}
+}
+
+func TestMeasureLatencies(t *testing.T) {
+	ctx := context.Background()
+
+	srvCfgs := []server.ListenerConfig{{Address: "localhost:1238"}, {Address: "localhost:2238"}}
+	var urls []string
+	for _, srvCfg := range srvCfgs {
+		srv, err := server.NewServer(otel.Meter(""), srvCfg.Address, false, av)
+		if err != nil {
+			t.Fatalf("failed to create server: %s", err)
+		}
+		errChan := make(chan error, 1)
+		go func(srvCfg server.ListenerConfig) {
+			if err := srv.Listen(srvCfg); err != nil {
+				errChan <- err
+			}
+		}(srvCfg)
+
+		defer func() {
+			if err := srv.Shutdown(ctx); err != nil {
+				t.Errorf("failed to close server: %s", err)
+			}
+		}()
+
+		if err := waitForServerToStart(errChan); err != nil {
+			t.Fatalf("failed to start server: %s", err)
+		}
+		urls = append(urls, toURL(srvCfg)...)
+	}
+	homeURL, otherURL := urls[0], urls[1]
+
+	mCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mgr := NewManager(mCtx, urls, "alice")
+	home := NewClient(mCtx, homeURL, mgr.tokenStore, "alice")
+	if err := home.Connect(); err != nil {
+		t.Fatalf("failed to connect to home relay: %s", err)
+	}
+	defer home.Close()
+	mgr.relayClient = home
+
+	measuredAt := func() map[string]time.Time {
+		result := make(map[string]time.Time)
+		for _, r := range mgr.RelayRTTs() {
+			if r.Err != nil {
+				t.Fatalf("failed to measure RTT of %s: %s", r.URL, r.Err)
+			}
+			result[r.URL] = r.MeasuredAt
+		}
+		return result
+	}
+
+	// the first round measures all relay servers
+	mgr.measureLatencies()
+	first := measuredAt()
+	if len(first) != len(urls) {
+		t.Fatalf("expected %d RTTs, got %d", len(urls), len(first))
+	}
+
+	// the relay servers without a connection are not measured until the next full round
+	mgr.measureLatencies()
+	second := measuredAt()
+	if !second[homeURL].After(first[homeURL]) {
+		t.Errorf("expected the home relay to be measured again")
+	}
+	if !second[otherURL].Equal(first[otherURL]) {
+		t.Errorf("expected the previous result of the relay without connection to be kept")
+	}
+
+	// the migration candidate is measured in every round
+	mgr.homeSelector.candidate = otherURL
+	mgr.measureLatencies()
+	third := measuredAt()
+	if !third[otherURL].After(second[otherURL]) {
+		t.Errorf("expected the migration candidate to be measured again")
+	}
+}
+
+func TestClient_PingNotSupported(t *testing.T) {
+	c := &Client{serviceIsRunning: true}
+	if _, err := c.Ping(context.Background()); !errors.Is(err, ErrProbeNotSupported) {
+		t.Errorf("expected ErrProbeNotSupported, got %v", err)
+	}
+}
@@ -74,6 +74,12 @@ type Manager struct {
 	onDisconnectedListeners map[string]*list.List
 	onReconnectedListenerFn func()
 	listenerLock            sync.Mutex
+
+	rtts         map[string]RelayRTT
+	rttsMu       sync.RWMutex
+	homeSelector homeSelector
+	// lastFullMeasurement is used only by the latency loop
+	lastFullMeasurement time.Time
 }
 
 // NewManager creates a new manager instance.
@@ -99,7 +105,8 @@ func NewManager(ctx context.Context, serverURLs []string, peerID string) *Manage
 
 // Serve starts the manager, attempting to establish a connection with the relay server.
 // If the connection fails, it will keep trying to reconnect in the background.
-// Additionally, it starts a cleanup loop to remove unused relay connections.
+// Additionally, it starts a cleanup loop to remove unused relay connections and a latency loop to measure the RTT of
+// the relay servers and migrate the home relay to a materially faster one.
 // The manager will automatically reconnect to the relay server in case of disconnection.
 func (m *Manager) Serve() error {
 	if m.running {
@@ -117,12 +124,14 @@ func (m *Manager) Serve() error {
 
 	go m.listenGuardEvent(m.ctx)
 	go m.startCleanupLoop()
+	go m.startLatencyLoop()
 	return err
 }
 
-// OpenConn opens a connection to the given peer key. If the peer is on the same relay server, the connection will be
-// established via the relay server. If the peer is on a different relay server, the manager will establish a new
-// connection to the relay server. It returns back with a net.Conn what represent the remote peer connection.
+// OpenConn opens a connection to the given peer key. If the peer is on the same relay server or on a member of its relay
+// cluster, the connection will be established via the relay server. If the peer is on a different relay server, the
+// manager will establish a new connection to the relay server. It returns back with a net.Conn what represent the remote
+// peer connection.
 func (m *Manager) OpenConn(serverAddress, peerKey string) (net.Conn, error) {
 	m.relayClientMu.Lock()
 	defer m.relayClientMu.Unlock()
@@ -334,7 +343,11 @@ func (m *Manager) isForeignServer(address string) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("relay client not connected")
 	}
-	return rAddr != address, nil
+	if rAddr == address {
+		return false, nil
+	}
+	// the peers of the other relay cluster members are reachable via the home server
+	return !m.relayClient.IsClusterMember(address), nil
 }
 
 func (m *Manager) startCleanupLoop() {
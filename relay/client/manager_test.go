@@ -431,3 +431,113 @@ func TestNotifierDoubleAdd(t *testing.T) {
 func toURL(address server.ListenerConfig) []string {
 	return []string{"rel://" + address.Address}
 }
+
+func TestClusterConn(t *testing.T) {
+	ctx := context.Background()
+	directory := server.NewMemoryDirectory()
+
+	var srvCfgs []server.ListenerConfig
+	for _, addr := range []string{"localhost:1236", "localhost:2236"} {
+		srvCfg := server.ListenerConfig{Address: addr}
+		srv, err := server.NewServer(otel.Meter(""), srvCfg.Address, false, av)
+		if err != nil {
+			t.Fatalf("failed to create server: %s", err)
+		}
+		if err := srv.EnableCluster(server.ClusterConfig{Directory: directory, Secret: []byte("cluster-secret")}); err != nil {
+			t.Fatalf("failed to enable cluster: %s", err)
+		}
+
+		errChan := make(chan error, 1)
+		go func() {
+			if err := srv.Listen(srvCfg); err != nil {
+				errChan <- err
+			}
+		}()
+
+		defer func() {
+			if err := srv.Shutdown(ctx); err != nil {
+				t.Errorf("failed to close server: %s", err)
+			}
+		}()
+
+		if err := waitForServerToStart(errChan); err != nil {
+			t.Fatalf("failed to start server: %s", err)
+		}
+		srvCfgs = append(srvCfgs, srvCfg)
+	}
+
+	mCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clientAlice := NewManager(mCtx, toURL(srvCfgs[0]), "alice")
+	if err := clientAlice.Serve(); err != nil {
+		t.Fatalf("failed to serve manager: %s", err)
+	}
+	clientBob := NewManager(mCtx, toURL(srvCfgs[1]), "bob")
+	if err := clientBob.Serve(); err != nil {
+		t.Fatalf("failed to serve manager: %s", err)
+	}
+
+	alicesSrvAddr, err := clientAlice.RelayInstanceAddress()
+	if err != nil {
+		t.Fatalf("failed to get relay address: %s", err)
+	}
+	bobsSrvAddr, err := clientBob.RelayInstanceAddress()
+	if err != nil {
+		t.Fatalf("failed to get relay address: %s", err)
+	}
+
+	// wait for the cluster info sent after the authentication
+	deadline := time.Now().Add(5 * time.Second)
+	for !clientAlice.relayClient.IsClusterMember(bobsSrvAddr) || !clientBob.relayClient.IsClusterMember(alicesSrvAddr) {
+		if time.Now().After(deadline) {
+			t.Fatalf("cluster info not received")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	connAliceToBob, err := clientAlice.OpenConn(bobsSrvAddr, "bob")
+	if err != nil {
+		t.Fatalf("failed to bind channel: %s", err)
+	}
+	connBobToAlice, err := clientBob.OpenConn(alicesSrvAddr, "alice")
+	if err != nil {
+		t.Fatalf("failed to bind channel: %s", err)
+	}
+
+	if len(clientAlice.relayClients) != 0 || len(clientBob.relayClients) != 0 {
+		t.Fatalf("expected no foreign relay connections")
+	}
+
+	payload := "hello bob, I am alice"
+	received := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 65535)
+		n, err := connBobToAlice.Read(buf)
+		if err != nil {
+			return
+		}
+		received <- string(buf[:n])
+	}()
+
+	// the first messages are dropped until the link between the relay instances is ready
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(10 * time.Second)
+	for {
+		if _, err := connAliceToBob.Write([]byte(payload)); err != nil {
+			t.Fatalf("failed to write to channel: %s", err)
+		}
+
+		select {
+		case msg := <-received:
+			if msg != payload {
+				t.Fatalf("expected %s, got %s", payload, msg)
+			}
+			return
+		case <-timeout:
+			t.Fatalf("message was not forwarded between the relay instances")
+		case <-ticker.C:
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/netbirdio/netbird/encryption"
+	"github.com/netbirdio/netbird/relay/admin"
 	"github.com/netbirdio/netbird/relay/auth"
 	"github.com/netbirdio/netbird/relay/server"
 	"github.com/netbirdio/netbird/signal/metrics"
@@ -40,6 +41,11 @@ type Config struct {
 	AuthSecret            string
 	LogLevel              string
 	LogFile               string
+	// ClusterDirectory is the Redis URL of the peer directory shared by the relay cluster members
+	ClusterDirectory string
+	// AdminAddress is the listen address of the admin API, the API is disabled if it is empty
+	AdminAddress string
+	AdminToken   string
 }
 
 func (c Config) Validate() error {
@@ -49,6 +55,9 @@ func (c Config) Validate() error {
 	if c.AuthSecret == "" {
 		return fmt.Errorf("auth secret is required")
 	}
+	if c.AdminAddress != "" && c.AdminToken == "" {
+		return fmt.Errorf("admin token is required to enable the admin API")
+	}
 	return nil
 }
 
@@ -87,6 +96,9 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cobraConfig.AuthSecret, "auth-secret", "s", "", "auth secret")
 	rootCmd.PersistentFlags().StringVar(&cobraConfig.LogLevel, "log-level", "info", "log level")
 	rootCmd.PersistentFlags().StringVar(&cobraConfig.LogFile, "log-file", "console", "log file")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.ClusterDirectory, "cluster-directory", "", "Redis URL of the peer directory shared by the relay instances, e.g. redis://redis:6379/0. Enables the relay cluster: the instances forward the traffic of the peers connected to different instances. The instances must use the same auth secret and be reachable on their exposed address")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.AdminAddress, "admin-address", "", "listen address of the admin HTTP API, e.g. 127.0.0.1:9091. The API lists the connected peers, disconnects peers and drains the instance. Disabled if empty")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.AdminToken, "admin-token", "", "bearer token of the admin HTTP API")
 
 	setFlagsFromEnvVars(rootCmd)
 }
@@ -146,6 +158,22 @@ func execute(cmd *cobra.Command, args []string) error {
 		log.Debugf("failed to create relay server: %v", err)
 		return fmt.Errorf("failed to create relay server: %v", err)
 	}
+	if cobraConfig.ClusterDirectory != "" {
+		if err := enableCluster(srv, cobraConfig); err != nil {
+			log.Debugf("failed to enable relay cluster: %v", err)
+			return fmt.Errorf("failed to enable relay cluster: %v", err)
+		}
+	}
+
+	var adminServer *http.Server
+	if cobraConfig.AdminAddress != "" {
+		adminServer, err = startAdminServer(srv, cobraConfig)
+		if err != nil {
+			log.Debugf("failed to start admin server: %v", err)
+			return fmt.Errorf("failed to start admin server: %v", err)
+		}
+	}
+
 	log.Infof("server will be available on: %s", srv.InstanceURL())
 	go func() {
 		if err := srv.Listen(srvListenerCfg); err != nil {
@@ -164,6 +192,13 @@ func execute(cmd *cobra.Command, args []string) error {
 		shutDownErrors = multierror.Append(shutDownErrors, fmt.Errorf("failed to close server: %s", err))
 	}
 
+	if adminServer != nil {
+		log.Infof("shutting down admin server")
+		if err := adminServer.Shutdown(ctx); err != nil {
+			shutDownErrors = multierror.Append(shutDownErrors, fmt.Errorf("failed to close admin server: %v", err))
+		}
+	}
+
 	log.Infof("shutting down metrics server")
 	if err := metricsServer.Shutdown(ctx); err != nil {
 		shutDownErrors = multierror.Append(shutDownErrors, fmt.Errorf("failed to close metrics server: %v", err))
@@ -171,6 +206,42 @@ func execute(cmd *cobra.Command, args []string) error {
 	return shutDownErrors
 }
 
+func enableCluster(srv *server.Server, cfg *Config) error {
+	directory, err := server.NewRedisDirectory(context.Background(), cfg.ClusterDirectory)
+	if err != nil {
+		return fmt.Errorf("connect to cluster directory: %w", err)
+	}
+
+	// the cluster secret is derived from the auth secret, the tokens of the peers are not valid for the cluster
+	// connections
+	clusterSecret := sha256.Sum256([]byte("relay-cluster:" + cfg.AuthSecret))
+	if err := srv.EnableCluster(server.ClusterConfig{Directory: directory, Secret: clusterSecret[:]}); err != nil {
+		_ = directory.Close()
+		return err
+	}
+	return nil
+}
+
+func startAdminServer(srv *server.Server, cfg *Config) (*http.Server, error) {
+	handler, err := admin.NewHandler(srv, cfg.AdminToken)
+	if err != nil {
+		return nil, err
+	}
+
+	adminServer := &http.Server{
+		Addr:              cfg.AdminAddress,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Infof("running admin server: %s", adminServer.Addr)
+		if err := adminServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start admin server: %v", err)
+		}
+	}()
+	return adminServer, nil
+}
+
 func handleTLSConfig(cfg *Config) (*tls.Config, bool, error) {
 	if cfg.LetsencryptAWSRoute53 {
 		log.Debugf("using Let's Encrypt DNS resolver with Route 53 support")
@@ -2,8 +2,10 @@ package messages
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 const (
@@ -22,6 +24,10 @@ const (
 	MsgTypeHealthCheck   MsgType = 5
 	MsgTypeAuth                  = 6
 	MsgTypeAuthResponse          = 7
+	MsgTypeClusterAuth   MsgType = 8
+	MsgTypeForward       MsgType = 9
+	MsgTypeClusterInfo   MsgType = 10
+	MsgTypeFeatures      MsgType = 11
 
 	// base size of the message
 	sizeOfVersionByte = 1
@@ -43,6 +49,24 @@ const (
 	headerSizeTransport      = IDSize
 	offsetTransportID        = sizeOfProtoHeader
 	headerTotalSizeTransport = sizeOfProtoHeader + headerSizeTransport
+
+	// cluster auth
+	headerTotalSizeClusterAuth = sizeOfProtoHeader + sizeOfMagicByte
+
+	// forward
+	offsetForwardDstID     = sizeOfProtoHeader
+	offsetForwardSrcID     = offsetForwardDstID + IDSize
+	headerTotalSizeForward = offsetForwardSrcID + IDSize
+	// the transport message is built in place, it starts right before the source ID
+	offsetForwardTransport = offsetForwardSrcID - sizeOfProtoHeader
+
+	// features
+	sizeOfFeatures          = 4
+	headerTotalSizeFeatures = sizeOfProtoHeader + sizeOfFeatures
+
+	// health check probe
+	sizeOfProbeID              = 8
+	headerTotalSizeHealthProbe = sizeOfProtoHeader + sizeOfProbeID
 )
 
 var (
@@ -50,12 +74,29 @@ var (
 	ErrUnsupportedVersion   = errors.New("unsupported version")
 
 	magicHeader = []byte{0x21, 0x12, 0xA4, 0x42}
+	// featuresMagic identifies the features request in the payload of a transport message
+	featuresMagic = []byte("nb-features")
 
 	healthCheckMsg = []byte{byte(CurrentProtocolVersion), byte(MsgTypeHealthCheck)}
 )
 
 type MsgType byte
 
+// Features is a set of protocol extensions supported by a client or a server
+type Features uint32
+
+const (
+	// FeatureClusterInfo is supported by the clients handling the cluster info messages
+	FeatureClusterInfo Features = 1 << iota
+	// FeatureHealthcheckProbe is supported by the servers echoing the health check probes
+	FeatureHealthcheckProbe
+)
+
+// Has returns true if all the given features are in the set
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
 func (m MsgType) String() string {
 	switch m {
 	case MsgTypeHello:
@@ -72,6 +113,14 @@ func (m MsgType) String() string {
 		return "close"
 	case MsgTypeHealthCheck:
 		return "health check"
+	case MsgTypeClusterAuth:
+		return "cluster auth"
+	case MsgTypeForward:
+		return "forward"
+	case MsgTypeClusterInfo:
+		return "cluster info"
+	case MsgTypeFeatures:
+		return "features"
 	default:
 		return "unknown"
 	}
@@ -100,7 +149,9 @@ func DetermineClientMessageType(msg []byte) (MsgType, error) {
 	case
 		MsgTypeHello,
 		MsgTypeAuth,
+		MsgTypeClusterAuth,
 		MsgTypeTransport,
+		MsgTypeForward,
 		MsgTypeClose,
 		MsgTypeHealthCheck:
 		return msgType, nil
@@ -120,6 +171,8 @@ func DetermineServerMessageType(msg []byte) (MsgType, error) {
 	case
 		MsgTypeHelloResponse,
 		MsgTypeAuthResponse,
+		MsgTypeClusterInfo,
+		MsgTypeFeatures,
 		MsgTypeTransport,
 		MsgTypeClose,
 		MsgTypeHealthCheck:
@@ -255,6 +308,98 @@ func UnmarshalAuthResponse(msg []byte) (string, error) {
 	return string(msg[sizeOfProtoHeader:]), nil
 }
 
+// MarshalClusterAuthMsg creates the authentication message of a relay-to-relay connection.
+// A relay of a cluster opens a connection to the other cluster members to forward the transport messages of the peers
+// they host. The auth payload is signed with the cluster secret, so the peers can not open such connections. In case
+// of success the server responds with an AuthResponse message.
+func MarshalClusterAuthMsg(authPayload []byte) []byte {
+	msg := make([]byte, sizeOfProtoHeader, headerTotalSizeClusterAuth+len(authPayload))
+
+	msg[0] = byte(CurrentProtocolVersion)
+	msg[1] = byte(MsgTypeClusterAuth)
+
+	msg = append(msg, magicHeader...)
+	msg = append(msg, authPayload...)
+
+	return msg
+}
+
+// UnmarshalClusterAuthMsg extracts the auth payload from the cluster auth message
+func UnmarshalClusterAuthMsg(msg []byte) ([]byte, error) {
+	if len(msg) < headerTotalSizeClusterAuth {
+		return nil, ErrInvalidMessageLength
+	}
+	if !bytes.Equal(msg[offsetMagicByte:offsetMagicByte+sizeOfMagicByte], magicHeader) {
+		return nil, errors.New("invalid magic header")
+	}
+
+	return msg[headerTotalSizeClusterAuth:], nil
+}
+
+// MarshalClusterInfoMsg creates a cluster info message.
+// A relay of a cluster sends the instance URLs of the cluster members to its peers after the authentication and every
+// time the members change. The peers can reach the peers of the members via their own relay connection, so they do
+// not have to connect to the members.
+func MarshalClusterInfoMsg(members []string) []byte {
+	payload := []byte(strings.Join(members, "\n"))
+	msg := make([]byte, sizeOfProtoHeader, sizeOfProtoHeader+len(payload))
+
+	msg[0] = byte(CurrentProtocolVersion)
+	msg[1] = byte(MsgTypeClusterInfo)
+
+	return append(msg, payload...)
+}
+
+// UnmarshalClusterInfoMsg extracts the instance URLs of the cluster members from the cluster info message
+func UnmarshalClusterInfoMsg(msg []byte) ([]string, error) {
+	if len(msg) < sizeOfProtoHeader {
+		return nil, ErrInvalidMessageLength
+	}
+	if len(msg) == sizeOfProtoHeader {
+		return nil, nil
+	}
+	return strings.Split(string(msg[sizeOfProtoHeader:]), "\n"), nil
+}
+
+// MarshalFeaturesRequest creates the features request of the client.
+// The request is a transport message addressed to the client itself, with the features of the client in the payload.
+// The servers supporting the features answer with a Features message, the older servers relay the message back to
+// the client. This way the client learns the features of any server in one round trip.
+func MarshalFeaturesRequest(peerID []byte, features Features) ([]byte, error) {
+	payload := make([]byte, len(featuresMagic), len(featuresMagic)+sizeOfFeatures)
+	copy(payload, featuresMagic)
+	payload = binary.BigEndian.AppendUint32(payload, uint32(features))
+
+	return MarshalTransportMsg(peerID, payload)
+}
+
+// UnmarshalFeaturesRequest returns the features of the client if the transport payload is a features request
+func UnmarshalFeaturesRequest(payload []byte) (Features, bool) {
+	if len(payload) != len(featuresMagic)+sizeOfFeatures || !bytes.HasPrefix(payload, featuresMagic) {
+		return 0, false
+	}
+	return Features(binary.BigEndian.Uint32(payload[len(featuresMagic):])), true
+}
+
+// MarshalFeaturesMsg creates a features message.
+// The server sends it in response to the features request of the client, it contains the features of the server.
+func MarshalFeaturesMsg(features Features) []byte {
+	msg := make([]byte, sizeOfProtoHeader, headerTotalSizeFeatures)
+
+	msg[0] = byte(CurrentProtocolVersion)
+	msg[1] = byte(MsgTypeFeatures)
+
+	return binary.BigEndian.AppendUint32(msg, uint32(features))
+}
+
+// UnmarshalFeaturesMsg extracts the features of the server from the features message
+func UnmarshalFeaturesMsg(msg []byte) (Features, error) {
+	if len(msg) < headerTotalSizeFeatures {
+		return 0, ErrInvalidMessageLength
+	}
+	return Features(binary.BigEndian.Uint32(msg[sizeOfProtoHeader:headerTotalSizeFeatures])), nil
+}
+
 // MarshalCloseMsg creates a close message.
 // The close message is used to close the connection gracefully between the client and the server. The server and the
 // client can send this message. After receiving this message, the server or client will close the connection.
@@ -310,9 +455,71 @@ func UpdateTransportMsg(msg []byte, peerID []byte) error {
 	return nil
 }
 
+// MarshalForwardMsg creates a forward message.
+// The forward message carries a transport message between the relays of a cluster. It contains the hashed ID of the
+// destination peer hosted by the receiving relay and the hashed ID of the source peer hosted by the sending relay.
+func MarshalForwardMsg(dstID, srcID, payload []byte) ([]byte, error) {
+	if len(dstID) != IDSize {
+		return nil, fmt.Errorf("invalid dstID length: %d", len(dstID))
+	}
+	if len(srcID) != IDSize {
+		return nil, fmt.Errorf("invalid srcID length: %d", len(srcID))
+	}
+
+	msg := make([]byte, headerTotalSizeForward, headerTotalSizeForward+len(payload))
+	msg[0] = byte(CurrentProtocolVersion)
+	msg[1] = byte(MsgTypeForward)
+	copy(msg[offsetForwardDstID:], dstID)
+	copy(msg[offsetForwardSrcID:], srcID)
+	msg = append(msg, payload...)
+
+	return msg, nil
+}
+
+// UnmarshalForwardMsg extracts the destination ID, the source ID and the payload from the forward message.
+func UnmarshalForwardMsg(msg []byte) ([]byte, []byte, []byte, error) {
+	if len(msg) < headerTotalSizeForward {
+		return nil, nil, nil, ErrInvalidMessageLength
+	}
+	return msg[offsetForwardDstID:offsetForwardSrcID], msg[offsetForwardSrcID:headerTotalSizeForward], msg[headerTotalSizeForward:], nil
+}
+
+// ForwardToTransportMsg converts the forward message to the transport message delivered to the destination peer.
+// The conversion reuses the given byte slice: it overwrites the end of the destination ID with the transport header,
+// so the destination ID has to be consumed before the call.
+func ForwardToTransportMsg(msg []byte) ([]byte, error) {
+	if len(msg) < headerTotalSizeForward {
+		return nil, ErrInvalidMessageLength
+	}
+	transport := msg[offsetForwardTransport:]
+	transport[0] = byte(CurrentProtocolVersion)
+	transport[1] = byte(MsgTypeTransport)
+	return transport, nil
+}
+
 // MarshalHealthcheck creates a health check message.
 // Health check message is sent by the server periodically. The client will respond with a health check response
 // message. If the client does not respond to the health check message, the server will close the connection.
 func MarshalHealthcheck() []byte {
 	return healthCheckMsg
 }
+
+// MarshalHealthcheckProbe creates a health check probe message with the given ID.
+// The probe is sent by the client to measure the round-trip time, the server echoes it back unchanged. The servers
+// without probe support handle it as a plain health check response and do not answer.
+func MarshalHealthcheckProbe(id uint64) []byte {
+	msg := make([]byte, headerTotalSizeHealthProbe)
+	msg[0] = byte(CurrentProtocolVersion)
+	msg[1] = byte(MsgTypeHealthCheck)
+	binary.BigEndian.PutUint64(msg[sizeOfProtoHeader:], id)
+	return msg
+}
+
+// UnmarshalHealthcheckProbe returns the ID of the health check probe. If the message is a plain health check message,
+// it returns false.
+func UnmarshalHealthcheckProbe(msg []byte) (uint64, bool) {
+	if len(msg) != headerTotalSizeHealthProbe {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(msg[sizeOfProtoHeader:]), true
+}
@@ -136,3 +136,179 @@ func TestMarshalHealthcheck(t *testing.T) {
 		t.Errorf("expected %d, got %d", MsgTypeHealthCheck, msgType)
 	}
 }
+
+func TestMarshalClusterAuthMsg(t *testing.T) {
+	payload := []byte("token")
+	msg := MarshalClusterAuthMsg(payload)
+
+	msgType, err := DetermineClientMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if msgType != MsgTypeClusterAuth {
+		t.Errorf("expected %d, got %d", MsgTypeClusterAuth, msgType)
+	}
+
+	receivedPayload, err := UnmarshalClusterAuthMsg(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if string(receivedPayload) != string(payload) {
+		t.Errorf("expected %s, got %s", payload, receivedPayload)
+	}
+}
+
+func TestMarshalClusterInfoMsg(t *testing.T) {
+	members := []string{"rels://relay1.example.com:443", "rels://relay2.example.com:443"}
+	msg := MarshalClusterInfoMsg(members)
+
+	msgType, err := DetermineServerMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if msgType != MsgTypeClusterInfo {
+		t.Errorf("expected %d, got %d", MsgTypeClusterInfo, msgType)
+	}
+
+	receivedMembers, err := UnmarshalClusterInfoMsg(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(receivedMembers) != len(members) || receivedMembers[0] != members[0] || receivedMembers[1] != members[1] {
+		t.Errorf("expected %v, got %v", members, receivedMembers)
+	}
+
+	receivedMembers, err = UnmarshalClusterInfoMsg(MarshalClusterInfoMsg(nil))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(receivedMembers) != 0 {
+		t.Errorf("expected no members, got %v", receivedMembers)
+	}
+}
+
+func TestMarshalForwardMsg(t *testing.T) {
+	dstID := []byte("abdFAaBcawquEiCMzAabYosuUaGLtSNhKxz+")
+	srcID := []byte("sha-AaBcawquEiCMzAabYosuUaGLtSNhKxz+")
+	payload := []byte("payload")
+	msg, err := MarshalForwardMsg(dstID, srcID, payload)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	msgType, err := DetermineClientMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if msgType != MsgTypeForward {
+		t.Errorf("expected %d, got %d", MsgTypeForward, msgType)
+	}
+
+	uDstID, uSrcID, uPayload, err := UnmarshalForwardMsg(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if string(uDstID) != string(dstID) || string(uSrcID) != string(srcID) || string(uPayload) != string(payload) {
+		t.Errorf("unexpected forward message content: %s, %s, %s", uDstID, uSrcID, uPayload)
+	}
+
+	transport, err := ForwardToTransportMsg(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	msgType, err = DetermineServerMessageType(transport)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if msgType != MsgTypeTransport {
+		t.Errorf("expected %d, got %d", MsgTypeTransport, msgType)
+	}
+
+	id, respPayload, err := UnmarshalTransportMsg(transport)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if string(id) != string(srcID) {
+		t.Errorf("expected %s, got %s", srcID, id)
+	}
+	if string(respPayload) != string(payload) {
+		t.Errorf("expected %s, got %s", payload, respPayload)
+	}
+}
+
+func TestMarshalHealthcheckProbe(t *testing.T) {
+	msg := MarshalHealthcheckProbe(42)
+
+	msgType, err := DetermineClientMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if msgType != MsgTypeHealthCheck {
+		t.Errorf("expected %d, got %d", MsgTypeHealthCheck, msgType)
+	}
+
+	id, ok := UnmarshalHealthcheckProbe(msg)
+	if !ok {
+		t.Fatalf("expected health check probe")
+	}
+	if id != 42 {
+		t.Errorf("expected probe id 42, got %d", id)
+	}
+
+	if _, ok := UnmarshalHealthcheckProbe(MarshalHealthcheck()); ok {
+		t.Errorf("plain health check message parsed as probe")
+	}
+}
+
+func TestMarshalFeatures(t *testing.T) {
+	peerID, _ := HashID("alice")
+	msg, err := MarshalFeaturesRequest(peerID, FeatureClusterInfo)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	msgType, err := DetermineClientMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if msgType != MsgTypeTransport {
+		t.Errorf("expected %d, got %d", MsgTypeTransport, msgType)
+	}
+
+	dstID, payload, err := UnmarshalTransportMsg(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if string(dstID) != string(peerID) {
+		t.Errorf("features request must be addressed to the client itself")
+	}
+	features, ok := UnmarshalFeaturesRequest(payload)
+	if !ok || !features.Has(FeatureClusterInfo) {
+		t.Errorf("expected cluster info feature, got %b, %t", features, ok)
+	}
+
+	if _, ok := UnmarshalFeaturesRequest([]byte("wireguard packet")); ok {
+		t.Errorf("transport payload parsed as features request")
+	}
+
+	msg = MarshalFeaturesMsg(FeatureClusterInfo)
+	msgType, err = DetermineServerMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if msgType != MsgTypeFeatures {
+		t.Errorf("expected %d, got %d", MsgTypeFeatures, msgType)
+	}
+
+	features, err = UnmarshalFeaturesMsg(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if features != FeatureClusterInfo {
+		t.Errorf("expected %b, got %b", FeatureClusterInfo, features)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrPeerNotConnected is returned if the peer is not connected to the relay server
+	ErrPeerNotConnected = errors.New("peer is not connected")
+)
+
+// PeerInfo describes a peer connected to the relay server
+type PeerInfo struct {
+	// ID is the hashed peer ID, as it is used in the relay protocol
+	ID         string
+	RemoteAddr string
+	// Listener is the type of the listener the peer connected to: ws or quic
+	Listener    string
+	ConnectedAt time.Time
+	// BytesIn is the traffic received from the peer, BytesOut is the traffic sent to the peer
+	BytesIn  uint64
+	BytesOut uint64
+	// OpenStreams is the number of peers the peer exchanged transport messages with recently
+	OpenStreams int
+}
+
+// Peers returns the peers connected to the relay server ordered by ID
+func (r *Server) Peers() []PeerInfo {
+	return r.relay.peers()
+}
+
+// Peer returns the peer with the given hashed ID
+func (r *Server) Peer(id string) (PeerInfo, error) {
+	peer, ok := r.relay.store.Peer(id)
+	if !ok {
+		return PeerInfo{}, ErrPeerNotConnected
+	}
+	return peer.Info(), nil
+}
+
+// DisconnectPeer closes the connection of the peer with the given hashed ID. The peer is notified about the close, it
+// reconnects to the relay server or to another one.
+func (r *Server) DisconnectPeer(ctx context.Context, id string) error {
+	peer, ok := r.relay.store.Peer(id)
+	if !ok {
+		return ErrPeerNotConnected
+	}
+
+	peer.log.Infof("disconnecting peer on admin request")
+	peer.CloseGracefully(ctx)
+	return nil
+}
+
+// Drain stops accepting new peers and disconnects the connected peers, so they move to other relay servers before the
+// shutdown. The disconnects are spread evenly over the period to avoid the peers reconnecting at the same time. If the
+// context is done, the remaining peers are disconnected at once.
+func (r *Server) Drain(ctx context.Context, period time.Duration) {
+	r.relay.drain(ctx, period)
+}
+
+// Draining returns true if the relay server has been drained
+func (r *Server) Draining() bool {
+	return r.relay.draining.Load()
+}
+
+func (r *Relay) peers() []PeerInfo {
+	peers := r.store.Peers()
+	infos := make([]PeerInfo, 0, len(peers))
+	for _, peer := range peers {
+		infos = append(infos, peer.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+func (r *Relay) drain(ctx context.Context, period time.Duration) {
+	r.drainMu.Lock()
+	swapped := r.draining.CompareAndSwap(false, true)
+	r.drainMu.Unlock()
+	if !swapped {
+		log.Infof("relay server is already draining")
+		return
+	}
+
+	peers := r.store.Peers()
+	log.Infof("draining relay server, disconnecting %d peers in %s", len(peers), period)
+	if len(peers) == 0 {
+		return
+	}
+
+	interval := period / time.Duration(len(peers))
+	for i, peer := range peers {
+		peer.CloseGracefully(ctx)
+		if i == len(peers)-1 || interval == 0 || ctx.Err() != nil {
+			continue
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+		}
+	}
+	log.Infof("relay server drained")
+}
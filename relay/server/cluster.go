@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	authv2 "github.com/netbirdio/netbird/relay/auth/hmac/v2"
+	"github.com/netbirdio/netbird/relay/healthcheck"
+	"github.com/netbirdio/netbird/relay/messages"
+	"github.com/netbirdio/netbird/relay/metrics"
+)
+
+const (
+	// clusterRefreshInterval is the interval of the member announcement and the peer record renewal in the directory
+	clusterRefreshInterval = 30 * time.Second
+)
+
+// ClusterConfig is the configuration of the relay cluster.
+// Directory: the directory shared by the members of the cluster.
+// Secret: the secret used to authenticate the connections between the members. It must differ from the secret of
+// the peer authentication, otherwise a peer could connect as a cluster member.
+type ClusterConfig struct {
+	Directory Directory
+	Secret    []byte
+}
+
+// EnableCluster makes the relay server a member of a relay cluster. The peers connected to different members can
+// communicate with each other: a member receiving a message for a peer it does not host forwards it to the member the
+// peer is connected to. It must be called before Listen.
+func (r *Server) EnableCluster(cfg ClusterConfig) error {
+	return r.relay.enableCluster(cfg)
+}
+
+func (r *Relay) enableCluster(cfg ClusterConfig) error {
+	if cfg.Directory == nil {
+		return errors.New("cluster directory is required")
+	}
+	if len(cfg.Secret) == 0 {
+		return errors.New("cluster secret is required")
+	}
+
+	fwd, err := newForwarder(cfg.Secret)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), directoryTimeout)
+	defer cancel()
+	if err := cfg.Directory.Announce(ctx, r.instanceURL); err != nil {
+		fwd.Close()
+		return fmt.Errorf("announce cluster member: %w", err)
+	}
+
+	r.directory = cfg.Directory
+	r.forwarder = fwd
+	r.clusterValidator = authv2.NewValidator(cfg.Secret)
+	r.store = NewClusterStore(cfg.Directory, r.instanceURL)
+
+	clusterCtx, clusterCancel := context.WithCancel(context.Background())
+	r.clusterCancel = clusterCancel
+	go r.clusterLoop(clusterCtx)
+
+	log.Infof("relay cluster enabled")
+	return nil
+}
+
+// clusterLoop keeps the member and peer records alive in the directory and notifies the peers about the member
+// changes
+func (r *Relay) clusterLoop(ctx context.Context) {
+	ticker := time.NewTicker(clusterRefreshInterval)
+	defer ticker.Stop()
+
+	r.refreshCluster(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			r.refreshCluster(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Relay) refreshCluster(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, directoryTimeout)
+	defer cancel()
+
+	if err := r.directory.Announce(ctx, r.instanceURL); err != nil {
+		log.Errorf("failed to announce cluster member: %s", err)
+	}
+
+	if err := r.store.refreshDirectory(ctx); err != nil {
+		log.Errorf("failed to refresh peers in directory: %s", err)
+	}
+
+	members, err := r.directory.Members(ctx)
+	if err != nil {
+		log.Errorf("failed to get cluster members: %s", err)
+		return
+	}
+
+	r.membersMu.Lock()
+	changed := !slices.Equal(r.members, members)
+	r.members = members
+	r.membersMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	log.Infof("relay cluster members: %v", members)
+	msg := messages.MarshalClusterInfoMsg(members)
+	for _, peer := range r.store.Peers() {
+		if !peer.Supports(messages.FeatureClusterInfo) {
+			continue
+		}
+		if _, err := peer.Write(msg); err != nil {
+			peer.log.Errorf("failed to send cluster info: %s", err)
+		}
+	}
+}
+
+// onPeerFeatures sends the cluster info to the peers supporting it once they reported their features
+func (r *Relay) onPeerFeatures(peer *Peer) {
+	if peer.Supports(messages.FeatureClusterInfo) {
+		go r.sendClusterInfo(peer)
+	}
+}
+
+// sendClusterInfo sends the current members of the cluster to the newly connected peer
+func (r *Relay) sendClusterInfo(peer *Peer) {
+	ctx, cancel := context.WithTimeout(context.Background(), directoryTimeout)
+	defer cancel()
+
+	members, err := r.directory.Members(ctx)
+	if err != nil {
+		peer.log.Errorf("failed to get cluster members: %s", err)
+		r.membersMu.Lock()
+		members = r.members
+		r.membersMu.Unlock()
+	}
+
+	if _, err := peer.Write(messages.MarshalClusterInfoMsg(members)); err != nil {
+		peer.log.Errorf("failed to send cluster info: %s", err)
+	}
+}
+
+func (r *Relay) closeCluster() {
+	if r.directory == nil {
+		return
+	}
+
+	r.clusterCancel()
+	r.forwarder.Close()
+	if err := r.directory.Close(); err != nil {
+		log.Errorf("failed to close cluster directory: %s", err)
+	}
+}
+
+// memberConn is an incoming connection from another member of the relay cluster. The member sends the forward
+// messages of its peers to the peers connected to this relay instance.
+type memberConn struct {
+	metrics *metrics.Metrics
+	log     *log.Entry
+	conn    net.Conn
+	store   *Store
+}
+
+func newMemberConn(metrics *metrics.Metrics, conn net.Conn, store *Store) *memberConn {
+	return &memberConn{
+		metrics: metrics,
+		log:     log.WithField("cluster_member", conn.RemoteAddr().String()),
+		conn:    conn,
+		store:   store,
+	}
+}
+
+// Work reads the forward messages from the connection until it is closed
+func (m *memberConn) Work() {
+	defer func() {
+		if err := m.conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			m.log.Errorf(errCloseConn, err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hc := healthcheck.NewSender(m.log)
+	go hc.StartHealthCheck(ctx)
+	go m.handleHealthcheckEvents(ctx, hc)
+
+	buf := make([]byte, clusterLinkReadLimit)
+	for {
+		n, err := m.conn.Read(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				m.log.Errorf("failed to read message: %s", err)
+			}
+			return
+		}
+
+		msg := buf[:n]
+		if _, err := messages.ValidateVersion(msg); err != nil {
+			m.log.Warnf("failed to validate protocol version: %s", err)
+			return
+		}
+
+		msgType, err := messages.DetermineClientMessageType(msg)
+		if err != nil {
+			m.log.Errorf("failed to determine message type: %s", err)
+			return
+		}
+
+		switch msgType {
+		case messages.MsgTypeForward:
+			m.handleForwardMsg(ctx, msg)
+		case messages.MsgTypeHealthCheck:
+			hc.OnHCResponse()
+		case messages.MsgTypeClose:
+			m.log.Infof("cluster member exited gracefully")
+			return
+		default:
+			m.log.Warnf("received unexpected message type: %s", msgType)
+		}
+	}
+}
+
+func (m *memberConn) handleForwardMsg(ctx context.Context, msg []byte) {
+	dstID, _, _, err := messages.UnmarshalForwardMsg(msg)
+	if err != nil {
+		m.log.Errorf("failed to unmarshal forward message: %s", err)
+		return
+	}
+
+	// the message is not forwarded again if the peer is not here, the members would send it around in a loop
+	stringDstID := messages.HashIDToString(dstID)
+	dp, ok := m.store.Peer(stringDstID)
+	if !ok {
+		m.log.Debugf("forwarded peer not found: %s", stringDstID)
+		return
+	}
+
+	transportMsg, err := messages.ForwardToTransportMsg(msg)
+	if err != nil {
+		m.log.Errorf("failed to convert forward message: %s", err)
+		return
+	}
+
+	if _, err := dp.Write(transportMsg); err != nil {
+		m.log.Errorf("failed to write forwarded message to: %s", dp.String())
+		return
+	}
+	m.metrics.TransferBytesSent.Add(ctx, int64(len(transportMsg)))
+}
+
+func (m *memberConn) handleHealthcheckEvents(ctx context.Context, hc *healthcheck.Sender) {
+	for {
+		select {
+		case <-hc.HealthCheck:
+			if _, err := m.conn.Write(messages.MarshalHealthcheck()); err != nil {
+				m.log.Errorf("failed to send healthcheck message: %s", err)
+				return
+			}
+		case <-hc.Timeout:
+			m.log.Errorf("cluster member healthcheck timeout")
+			if err := m.conn.Close(); err != nil {
+				m.log.Errorf(errCloseConn, err)
+			}
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrPeerNotFound is returned by the Directory if no relay instance hosts the peer
+	ErrPeerNotFound = errors.New("peer not found in directory")
+)
+
+// Directory is the registry shared by the relay instances of a cluster. It records the instance URL of the relay
+// each peer is connected to and the live members of the cluster.
+type Directory interface {
+	// Register records that the peers are connected to the relay instance
+	Register(ctx context.Context, instanceURL string, peerIDs ...string) error
+	// Unregister removes the record of the peer if it still points to the relay instance. The peer could have been
+	// reconnected to another instance in the meantime.
+	Unregister(ctx context.Context, instanceURL string, peerID string) error
+	// Lookup returns the instance URL of the relay the peer is connected to or ErrPeerNotFound
+	Lookup(ctx context.Context, peerID string) (string, error)
+	// Announce records the relay instance as a live member of the cluster
+	Announce(ctx context.Context, instanceURL string) error
+	// Members returns the instance URLs of the live members of the cluster
+	Members(ctx context.Context) ([]string, error)
+	// Close releases the resources of the directory
+	Close() error
+}
+
+// MemoryDirectory is a Directory kept in memory. It can be shared by relay instances running in the same process,
+// mainly for testing purposes.
+type MemoryDirectory struct {
+	mu      sync.RWMutex
+	peers   map[string]string
+	members map[string]struct{}
+}
+
+// NewMemoryDirectory creates a new MemoryDirectory instance
+func NewMemoryDirectory() *MemoryDirectory {
+	return &MemoryDirectory{
+		peers:   make(map[string]string),
+		members: make(map[string]struct{}),
+	}
+}
+
+// Register records that the peers are connected to the relay instance
+func (d *MemoryDirectory) Register(_ context.Context, instanceURL string, peerIDs ...string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, peerID := range peerIDs {
+		d.peers[peerID] = instanceURL
+	}
+	return nil
+}
+
+// Unregister removes the record of the peer if it still points to the relay instance
+func (d *MemoryDirectory) Unregister(_ context.Context, instanceURL string, peerID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.peers[peerID] == instanceURL {
+		delete(d.peers, peerID)
+	}
+	return nil
+}
+
+// Lookup returns the instance URL of the relay the peer is connected to
+func (d *MemoryDirectory) Lookup(_ context.Context, peerID string) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	instanceURL, ok := d.peers[peerID]
+	if !ok {
+		return "", ErrPeerNotFound
+	}
+	return instanceURL, nil
+}
+
+// Announce records the relay instance as a member of the cluster
+func (d *MemoryDirectory) Announce(_ context.Context, instanceURL string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.members[instanceURL] = struct{}{}
+	return nil
+}
+
+// Members returns the instance URLs of the cluster members in sorted order
+func (d *MemoryDirectory) Members(_ context.Context) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	members := make([]string, 0, len(d.members))
+	for member := range d.members {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// Close does nothing, the MemoryDirectory has no resources to release
+func (d *MemoryDirectory) Close() error {
+	return nil
+}
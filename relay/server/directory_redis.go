@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisPeerKeyPrefix = "netbird:relay:peer:"
+	redisMembersKey    = "netbird:relay:members"
+
+	// directoryTTL is the lifetime of the peer and member records. The relay instances refresh their records in
+	// clusterRefreshInterval, so the records of a crashed instance expire.
+	directoryTTL = 3 * clusterRefreshInterval
+)
+
+// unregisterScript deletes the peer record only if it still points to the given instance
+var unregisterScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisDirectory is a Directory stored in Redis, shared by all relay instances of the cluster
+type RedisDirectory struct {
+	client *redis.Client
+}
+
+// NewRedisDirectory connects to the Redis server of the given URL.
+// The value should follow redis URL format. https://github.com/redis/redis-specifications/blob/master/uri/redis.txt
+func NewRedisDirectory(ctx context.Context, redisURL string) (*RedisDirectory, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+
+	return &RedisDirectory{client: client}, nil
+}
+
+// Register records that the peers are connected to the relay instance
+func (d *RedisDirectory) Register(ctx context.Context, instanceURL string, peerIDs ...string) error {
+	if len(peerIDs) == 0 {
+		return nil
+	}
+
+	_, err := d.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, peerID := range peerIDs {
+			pipe.Set(ctx, redisPeerKeyPrefix+peerID, instanceURL, directoryTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("register peers: %w", err)
+	}
+	return nil
+}
+
+// Unregister removes the record of the peer if it still points to the relay instance
+func (d *RedisDirectory) Unregister(ctx context.Context, instanceURL string, peerID string) error {
+	if err := unregisterScript.Run(ctx, d.client, []string{redisPeerKeyPrefix + peerID}, instanceURL).Err(); err != nil {
+		return fmt.Errorf("unregister peer: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the instance URL of the relay the peer is connected to
+func (d *RedisDirectory) Lookup(ctx context.Context, peerID string) (string, error) {
+	instanceURL, err := d.client.Get(ctx, redisPeerKeyPrefix+peerID).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrPeerNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("lookup peer: %w", err)
+	}
+	return instanceURL, nil
+}
+
+// Announce records the relay instance as a live member of the cluster, the members are scored by the time of their
+// last announcement
+func (d *RedisDirectory) Announce(ctx context.Context, instanceURL string) error {
+	now := time.Now()
+	_, err := d.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, redisMembersKey, redis.Z{Score: float64(now.Unix()), Member: instanceURL})
+		pipe.ZRemRangeByScore(ctx, redisMembersKey, "-inf", strconv.FormatInt(now.Add(-directoryTTL).Unix(), 10))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("announce member: %w", err)
+	}
+	return nil
+}
+
+// Members returns the instance URLs of the members announced within the directory TTL
+func (d *RedisDirectory) Members(ctx context.Context) ([]string, error) {
+	members, err := d.client.ZRangeByScore(ctx, redisMembersKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Add(-directoryTTL).Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get members: %w", err)
+	}
+	return members, nil
+}
+
+// Close closes the connection to the Redis server
+func (d *RedisDirectory) Close() error {
+	return d.client.Close()
+}
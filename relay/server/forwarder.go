@@ -0,0 +1,263 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	authv2 "github.com/netbirdio/netbird/relay/auth/hmac/v2"
+	"github.com/netbirdio/netbird/relay/client/dialer"
+	"github.com/netbirdio/netbird/relay/client/dialer/quic"
+	"github.com/netbirdio/netbird/relay/client/dialer/ws"
+	"github.com/netbirdio/netbird/relay/healthcheck"
+	"github.com/netbirdio/netbird/relay/messages"
+)
+
+const (
+	clusterTokenTTL      = time.Hour
+	clusterAuthTimeout   = 10 * time.Second
+	linkRedialBackoff    = 5 * time.Second
+	clusterLinkReadLimit = bufferSize + messages.MaxHandshakeRespSize
+)
+
+var (
+	errLinkNotReady = errors.New("link to cluster member is not ready")
+)
+
+// forwardLink is an outgoing connection to another member of the relay cluster
+type forwardLink struct {
+	instanceURL string
+	conn        net.Conn
+	log         *log.Entry
+}
+
+// forwarder maintains the connections to the other relay cluster members and forwards the transport messages of the
+// local peers to them. The connections are opened on demand. Until a connection is ready the messages are dropped,
+// the peers tolerate the packet loss like in case of an UDP connection.
+type forwarder struct {
+	ctx            context.Context
+	ctxCancel      context.CancelFunc
+	tokenGenerator *authv2.Generator
+
+	links   map[string]*forwardLink
+	linksMu sync.Mutex
+}
+
+func newForwarder(secret []byte) (*forwarder, error) {
+	tokenGenerator, err := authv2.NewGenerator(authv2.AuthAlgoHMACSHA256, secret, clusterTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("create token generator: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &forwarder{
+		ctx:            ctx,
+		ctxCancel:      cancel,
+		tokenGenerator: tokenGenerator,
+		links:          make(map[string]*forwardLink),
+	}, nil
+}
+
+// Forward sends the forward message to the cluster member. If there is no connection to the member yet, it starts to
+// open one in the background and returns errLinkNotReady.
+func (f *forwarder) Forward(instanceURL string, msg []byte) (int, error) {
+	f.linksMu.Lock()
+	link, ok := f.links[instanceURL]
+	if !ok {
+		if f.ctx.Err() != nil {
+			f.linksMu.Unlock()
+			return 0, f.ctx.Err()
+		}
+		link = &forwardLink{
+			instanceURL: instanceURL,
+			log:         log.WithField("cluster_member", instanceURL),
+		}
+		f.links[instanceURL] = link
+		go f.connect(link)
+	}
+	conn := link.conn
+	f.linksMu.Unlock()
+
+	if conn == nil {
+		return 0, errLinkNotReady
+	}
+	return conn.Write(msg)
+}
+
+// Close closes the connections to all cluster members
+func (f *forwarder) Close() {
+	f.ctxCancel()
+
+	f.linksMu.Lock()
+	defer f.linksMu.Unlock()
+	for _, link := range f.links {
+		if link.conn != nil {
+			_ = link.conn.Close()
+		}
+	}
+}
+
+func (f *forwarder) connect(link *forwardLink) {
+	conn, err := f.dial(link)
+	if err != nil {
+		link.log.Errorf("failed to connect to cluster member: %s", err)
+		// keep the link without connection for a while, so the messages do not trigger a new dial immediately
+		time.AfterFunc(linkRedialBackoff, func() {
+			f.removeLink(link)
+		})
+		return
+	}
+
+	f.linksMu.Lock()
+	if f.ctx.Err() != nil {
+		f.linksMu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	link.conn = conn
+	f.linksMu.Unlock()
+
+	link.log.Infof("connected to cluster member")
+	f.readLoop(link, conn)
+	f.removeLink(link)
+	link.log.Infof("connection to cluster member closed")
+}
+
+func (f *forwarder) dial(link *forwardLink) (net.Conn, error) {
+	rd := dialer.NewRaceDial(link.log, link.instanceURL, quic.Dialer{}, ws.Dialer{})
+	conn, err := rd.Dial()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.handshake(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (f *forwarder) handshake(conn net.Conn) error {
+	token, err := f.tokenGenerator.GenerateToken()
+	if err != nil {
+		return fmt.Errorf("generate token: %w", err)
+	}
+
+	if _, err := conn.Write(messages.MarshalClusterAuthMsg(token.Marshal())); err != nil {
+		return fmt.Errorf("send cluster auth message: %w", err)
+	}
+
+	buf := make([]byte, messages.MaxHandshakeRespSize)
+	n, err := readWithTimeout(f.ctx, conn, buf, clusterAuthTimeout)
+	if err != nil {
+		return fmt.Errorf("read cluster auth response: %w", err)
+	}
+
+	if _, err := messages.ValidateVersion(buf[:n]); err != nil {
+		return fmt.Errorf("validate version: %w", err)
+	}
+
+	msgType, err := messages.DetermineServerMessageType(buf[:n])
+	if err != nil {
+		return fmt.Errorf("determine message type: %w", err)
+	}
+	if msgType != messages.MsgTypeAuthResponse {
+		return fmt.Errorf("unexpected message type: %s", msgType)
+	}
+	return nil
+}
+
+// readLoop answers the health checks of the cluster member until the connection is closed
+func (f *forwarder) readLoop(link *forwardLink, conn net.Conn) {
+	ctx, cancel := context.WithCancel(f.ctx)
+	defer cancel()
+
+	hc := healthcheck.NewReceiver(link.log)
+	defer hc.Stop()
+
+	go func() {
+		select {
+		case <-hc.OnTimeout:
+			link.log.Errorf("health check timeout")
+			_ = conn.Close()
+		case <-ctx.Done():
+		}
+	}()
+
+	buf := make([]byte, clusterLinkReadLimit)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) && f.ctx.Err() == nil {
+				link.log.Errorf("failed to read message: %s", err)
+			}
+			return
+		}
+
+		if _, err := messages.ValidateVersion(buf[:n]); err != nil {
+			link.log.Warnf("failed to validate protocol version: %s", err)
+			return
+		}
+
+		msgType, err := messages.DetermineServerMessageType(buf[:n])
+		if err != nil {
+			link.log.Errorf("failed to determine message type: %s", err)
+			continue
+		}
+
+		switch msgType {
+		case messages.MsgTypeHealthCheck:
+			if _, err := conn.Write(messages.MarshalHealthcheck()); err != nil {
+				link.log.Errorf("failed to send health check response: %s", err)
+			}
+			hc.Heartbeat()
+		case messages.MsgTypeClose:
+			_ = conn.Close()
+			return
+		default:
+			link.log.Warnf("received unexpected message type: %s", msgType)
+		}
+	}
+}
+
+func (f *forwarder) removeLink(link *forwardLink) {
+	f.linksMu.Lock()
+	defer f.linksMu.Unlock()
+
+	if f.links[link.instanceURL] == link {
+		delete(f.links, link.instanceURL)
+	}
+	if link.conn != nil {
+		_ = link.conn.Close()
+	}
+}
+
+func readWithTimeout(ctx context.Context, conn net.Conn, buf []byte, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	readDone := make(chan struct{})
+	var (
+		n   int
+		err error
+	)
+
+	go func() {
+		n, err = conn.Read(buf)
+		close(readDone)
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = conn.Close()
+		<-readDone
+		return 0, fmt.Errorf("read operation timed out")
+	case <-readDone:
+		return n, err
+	}
+}
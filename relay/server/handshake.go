@@ -7,6 +7,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/relay/auth"
+	authv2 "github.com/netbirdio/netbird/relay/auth/hmac/v2"
 	"github.com/netbirdio/netbird/relay/messages"
 	//nolint:staticcheck
 	"github.com/netbirdio/netbird/relay/messages/address"
@@ -53,11 +54,13 @@ func marshalResponseHelloMsg(instanceURL string) ([]byte, error) {
 }
 
 type handshake struct {
-	conn        net.Conn
-	validator   auth.Validator
-	preparedMsg *preparedMsg
+	conn             net.Conn
+	validator        auth.Validator
+	clusterValidator *authv2.Validator
+	preparedMsg      *preparedMsg
 
 	handshakeMethodAuth bool
+	clusterMember       bool
 	peerID              string
 }
 
@@ -91,6 +94,10 @@ func (h *handshake) handshakeReceive() ([]byte, error) {
 	case messages.MsgTypeAuth:
 		h.handshakeMethodAuth = true
 		bytePeerID, peerID, err = h.handleAuthMsg(buf)
+	case messages.MsgTypeClusterAuth:
+		h.handshakeMethodAuth = true
+		h.clusterMember = true
+		err = h.handleClusterAuthMsg(buf)
 	default:
 		return nil, fmt.Errorf("invalid message type %d from %s", msgType, h.conn.RemoteAddr())
 	}
@@ -139,6 +146,23 @@ func (h *handshake) handleHelloMsg(buf []byte) ([]byte, string, error) {
 	return rawPeerID, peerID, nil
 }
 
+func (h *handshake) handleClusterAuthMsg(buf []byte) error {
+	if h.clusterValidator == nil {
+		return fmt.Errorf("cluster auth from %s: relay cluster is not enabled", h.conn.RemoteAddr())
+	}
+
+	authPayload, err := messages.UnmarshalClusterAuthMsg(buf)
+	if err != nil {
+		return fmt.Errorf("unmarshal cluster auth message: %w", err)
+	}
+
+	if err := h.clusterValidator.Validate(authPayload); err != nil {
+		return fmt.Errorf("validate cluster member %s: %w", h.conn.RemoteAddr(), err)
+	}
+
+	return nil
+}
+
 func (h *handshake) handleAuthMsg(buf []byte) ([]byte, string, error) {
 	rawPeerID, authPayload, err := messages.UnmarshalAuthMsg(buf)
 	if err != nil {
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -12,12 +14,24 @@ import (
 	"github.com/netbirdio/netbird/relay/healthcheck"
 	"github.com/netbirdio/netbird/relay/messages"
 	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/listener/quic"
+	"github.com/netbirdio/netbird/relay/server/listener/ws"
 )
 
 const (
 	bufferSize = 8820
 
 	errCloseConn = "failed to close connection to peer: %s"
+
+	// streamIdleTimeout is the time after a stream between two peers is not counted as open without traffic
+	streamIdleTimeout = 2 * time.Minute
+
+	listenerWS      = "ws"
+	listenerQUIC    = "quic"
+	listenerUnknown = "unknown"
+
+	// serverFeatures are the protocol extensions supported by the relay server
+	serverFeatures = messages.FeatureHealthcheckProbe
 )
 
 // Peer represents a peer connection
@@ -29,6 +43,19 @@ type Peer struct {
 	conn    net.Conn
 	connMu  sync.RWMutex
 	store   *Store
+	// forwarder is set if the relay is a member of a cluster
+	forwarder *forwarder
+	// onFeatures is called when the client reported its features
+	onFeatures func(*Peer)
+	// features are the protocol extensions supported by the client
+	features atomic.Uint32
+
+	connectedAt time.Time
+	listener    string
+	bytesIn     atomic.Uint64
+	bytesOut    atomic.Uint64
+	// streams holds the last activity of the streams to the destination peers in unix nanoseconds
+	streams sync.Map
 }
 
 // NewPeer creates a new Peer instance and prepare custom logging
@@ -41,6 +68,9 @@ func NewPeer(metrics *metrics.Metrics, id []byte, conn net.Conn, store *Store) *
 		idB:     id,
 		conn:    conn,
 		store:   store,
+
+		connectedAt: time.Now(),
+		listener:    listenerType(conn),
 	}
 }
 
@@ -75,6 +105,7 @@ func (p *Peer) Work() {
 			p.log.Errorf("received empty message")
 			return
 		}
+		p.bytesIn.Add(uint64(n))
 
 		msg := buf[:n]
 
@@ -94,9 +125,20 @@ func (p *Peer) Work() {
 	}
 }
 
+// handleHealthcheckProbe echoes the probe back to the client, so it can measure the round-trip time
+func (p *Peer) handleHealthcheckProbe(msg []byte) {
+	if _, err := p.Write(msg); err != nil {
+		p.log.Errorf("failed to echo health check probe: %s", err)
+	}
+}
+
 func (p *Peer) handleMsgType(ctx context.Context, msgType messages.MsgType, hc *healthcheck.Sender, n int, msg []byte) {
 	switch msgType {
 	case messages.MsgTypeHealthCheck:
+		if _, ok := messages.UnmarshalHealthcheckProbe(msg); ok {
+			p.handleHealthcheckProbe(msg)
+			return
+		}
 		hc.OnHCResponse()
 	case messages.MsgTypeTransport:
 		p.metrics.TransferBytesRecv.Add(ctx, int64(n))
@@ -116,7 +158,12 @@ func (p *Peer) handleMsgType(ctx context.Context, msgType messages.MsgType, hc *
 func (p *Peer) Write(b []byte) (int, error) {
 	p.connMu.RLock()
 	defer p.connMu.RUnlock()
-	return p.conn.Write(b)
+	n, err := p.conn.Write(b)
+	if err == nil {
+		// the websocket connection does not report the written size
+		p.bytesOut.Add(uint64(len(b)))
+	}
+	return n, err
 }
 
 // CloseGracefully closes the connection with the peer gracefully. Send a close message to the client and close the
@@ -197,9 +244,18 @@ func (p *Peer) handleTransportMsg(msg []byte) {
 		return
 	}
 
+	if bytes.Equal(peerID, p.idB) && p.handleFeaturesRequest(msg) {
+		return
+	}
+
 	stringPeerID := messages.HashIDToString(peerID)
+	p.trackStream(stringPeerID)
 	dp, ok := p.store.Peer(stringPeerID)
 	if !ok {
+		if p.forwarder != nil {
+			p.forwardTransportMsg(peerID, stringPeerID, msg)
+			return
+		}
 		p.log.Debugf("peer not found: %s", stringPeerID)
 		return
 	}
@@ -217,3 +273,115 @@ func (p *Peer) handleTransportMsg(msg []byte) {
 	}
 	p.metrics.TransferBytesSent.Add(context.Background(), int64(n))
 }
+
+// handleFeaturesRequest answers the features request of the client with the features of the server. It returns false
+// if the message addressed to the peer itself is not a features request.
+func (p *Peer) handleFeaturesRequest(msg []byte) bool {
+	_, payload, err := messages.UnmarshalTransportMsg(msg)
+	if err != nil {
+		return false
+	}
+
+	features, ok := messages.UnmarshalFeaturesRequest(payload)
+	if !ok {
+		return false
+	}
+
+	p.features.Store(uint32(features))
+	if _, err := p.Write(messages.MarshalFeaturesMsg(serverFeatures)); err != nil {
+		p.log.Errorf("failed to send features: %s", err)
+		return true
+	}
+
+	if p.onFeatures != nil {
+		p.onFeatures(p)
+	}
+	return true
+}
+
+// Supports returns true if the client reported support for the given features
+func (p *Peer) Supports(features messages.Features) bool {
+	return messages.Features(p.features.Load()).Has(features)
+}
+
+// forwardTransportMsg sends the transport message to the cluster member the destination peer is connected to
+func (p *Peer) forwardTransportMsg(dstID []byte, stringDstID string, msg []byte) {
+	instanceURL, ok := p.store.Locate(stringDstID)
+	if !ok {
+		p.log.Debugf("peer not found: %s", stringDstID)
+		return
+	}
+
+	_, payload, err := messages.UnmarshalTransportMsg(msg)
+	if err != nil {
+		p.log.Errorf("failed to unmarshal transport message: %s", err)
+		return
+	}
+
+	fwdMsg, err := messages.MarshalForwardMsg(dstID, p.idB, payload)
+	if err != nil {
+		p.log.Errorf("failed to marshal forward message: %s", err)
+		return
+	}
+
+	if _, err := p.forwarder.Forward(instanceURL, fwdMsg); err != nil {
+		if errors.Is(err, errLinkNotReady) {
+			p.log.Debugf("drop message to %s, the link to %s is not ready", stringDstID, instanceURL)
+			return
+		}
+		p.log.Errorf("failed to forward transport message to %s via %s: %s", stringDstID, instanceURL, err)
+		return
+	}
+	p.metrics.TransferBytesSent.Add(context.Background(), int64(len(fwdMsg)))
+}
+
+// Info returns the connection details and the traffic counters of the peer
+func (p *Peer) Info() PeerInfo {
+	return PeerInfo{
+		ID:          p.idS,
+		RemoteAddr:  p.conn.RemoteAddr().String(),
+		Listener:    p.listener,
+		ConnectedAt: p.connectedAt,
+		BytesIn:     p.bytesIn.Load(),
+		BytesOut:    p.bytesOut.Load(),
+		OpenStreams: p.openStreams(),
+	}
+}
+
+func (p *Peer) trackStream(dstID string) {
+	now := time.Now().UnixNano()
+	if lastActivity, ok := p.streams.Load(dstID); ok {
+		lastActivity.(*atomic.Int64).Store(now)
+		return
+	}
+
+	lastActivity := &atomic.Int64{}
+	lastActivity.Store(now)
+	p.streams.Store(dstID, lastActivity)
+}
+
+// openStreams returns the number of destination peers with traffic in the streamIdleTimeout and drops the idle ones
+func (p *Peer) openStreams() int {
+	idleSince := time.Now().Add(-streamIdleTimeout).UnixNano()
+	var open int
+	p.streams.Range(func(key, value any) bool {
+		if value.(*atomic.Int64).Load() < idleSince {
+			p.streams.Delete(key)
+			return true
+		}
+		open++
+		return true
+	})
+	return open
+}
+
+func listenerType(conn net.Conn) string {
+	switch conn.(type) {
+	case *ws.Conn:
+		return listenerWS
+	case *quic.Conn:
+		return listenerQUIC
+	default:
+		return listenerUnknown
+	}
+}
@@ -7,12 +7,15 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/netbirdio/netbird/relay/auth"
+	authv2 "github.com/netbirdio/netbird/relay/auth/hmac/v2"
+	"github.com/netbirdio/netbird/relay/messages"
 	//nolint:staticcheck
 	"github.com/netbirdio/netbird/relay/metrics"
 )
@@ -27,6 +30,19 @@ type Relay struct {
 	instanceURL string
 	preparedMsg *preparedMsg
 
+	directory        Directory
+	forwarder        *forwarder
+	clusterValidator *authv2.Validator
+	clusterCancel    context.CancelFunc
+	members          []string
+	membersMu        sync.Mutex
+
+	// draining is set if the relay does not accept new peers before the shutdown
+	draining atomic.Bool
+	// drainMu is read locked while a peer is accepted, the drain sets the flag under the write lock to not miss a
+	// peer that passed the check but has not been added to the store yet
+	drainMu sync.RWMutex
+
 	closed  bool
 	closeMu sync.RWMutex
 }
@@ -112,9 +128,10 @@ func (r *Relay) Accept(conn net.Conn) {
 	}
 
 	h := handshake{
-		conn:        conn,
-		validator:   r.validator,
-		preparedMsg: r.preparedMsg,
+		conn:             conn,
+		validator:        r.validator,
+		clusterValidator: r.clusterValidator,
+		preparedMsg:      r.preparedMsg,
 	}
 	peerID, err := h.handshakeReceive()
 	if err != nil {
@@ -125,10 +142,30 @@ func (r *Relay) Accept(conn net.Conn) {
 		return
 	}
 
+	if h.clusterMember {
+		r.acceptMember(conn, &h)
+		return
+	}
+
+	r.drainMu.RLock()
+	if r.draining.Load() {
+		r.drainMu.RUnlock()
+		log.Infof("relay server is draining, reject peer: %s", messages.HashIDToString(peerID))
+		if cErr := conn.Close(); cErr != nil {
+			log.Errorf("failed to close connection, %s: %s", conn.RemoteAddr(), cErr)
+		}
+		return
+	}
+
 	peer := NewPeer(r.metrics, peerID, conn, r.store)
+	peer.forwarder = r.forwarder
+	if r.directory != nil {
+		peer.onFeatures = r.onPeerFeatures
+	}
 	peer.log.Infof("peer connected from: %s", conn.RemoteAddr())
 	storeTime := time.Now()
 	r.store.AddPeer(peer)
+	r.drainMu.RUnlock()
 	r.metrics.RecordPeerStoreTime(time.Since(storeTime))
 	r.metrics.PeerConnected(peer.String())
 	go func() {
@@ -145,6 +182,20 @@ func (r *Relay) Accept(conn net.Conn) {
 	r.metrics.RecordAuthenticationTime(time.Since(acceptTime))
 }
 
+func (r *Relay) acceptMember(conn net.Conn, h *handshake) {
+	if err := h.handshakeResponse(); err != nil {
+		log.Errorf("failed to send handshake response to cluster member: %s", err)
+		if cErr := conn.Close(); cErr != nil {
+			log.Errorf("failed to close connection, %s: %s", conn.RemoteAddr(), cErr)
+		}
+		return
+	}
+
+	m := newMemberConn(r.metrics, conn, r.store)
+	m.log.Infof("cluster member connected")
+	go m.Work()
+}
+
 // Shutdown closes the relay server
 // It closes the connection with all peers in gracefully and stops accepting new connections.
 func (r *Relay) Shutdown(ctx context.Context) {
@@ -162,6 +213,7 @@ func (r *Relay) Shutdown(ctx context.Context) {
 		}(peer)
 	}
 	wg.Wait()
+	r.closeCluster()
 	r.metricsCancel()
 	r.closed = true
 }
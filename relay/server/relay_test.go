@@ -1,6 +1,17 @@
 package server
 
-import "testing"
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/netbirdio/netbird/relay/messages"
+	"github.com/netbirdio/netbird/relay/metrics"
+)
 
 func TestGetInstanceURL(t *testing.T) {
 	tests := []struct {
@@ -34,3 +45,81 @@ func TestGetInstanceURL(t *testing.T) {
 		})
 	}
 }
+
+type recordingConn struct {
+	mockConn
+	mu      sync.Mutex
+	written [][]byte
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.written = append(c.written, append([]byte{}, b...))
+	return len(b), nil
+}
+
+func TestPeer_FeaturesRequest(t *testing.T) {
+	m, _ := metrics.NewMetrics(context.Background(), otel.Meter(""))
+	store := NewStore()
+
+	conn := &recordingConn{}
+	peerID, _ := messages.HashID("alice")
+	p := NewPeer(m, peerID, conn, store)
+	store.AddPeer(p)
+
+	var reported *Peer
+	p.onFeatures = func(peer *Peer) {
+		reported = peer
+	}
+
+	if p.Supports(messages.FeatureClusterInfo) {
+		t.Fatalf("peer must not support cluster info before the features request")
+	}
+
+	msg, err := messages.MarshalFeaturesRequest(peerID, messages.FeatureClusterInfo)
+	if err != nil {
+		t.Fatalf("failed to marshal features request: %s", err)
+	}
+	p.handleTransportMsg(msg)
+
+	if !p.Supports(messages.FeatureClusterInfo) {
+		t.Errorf("peer must support cluster info after the features request")
+	}
+	if reported != p {
+		t.Errorf("features callback not called")
+	}
+	if len(conn.written) != 1 {
+		t.Fatalf("expected the features response only, got %d messages", len(conn.written))
+	}
+	msgType, err := messages.DetermineServerMessageType(conn.written[0])
+	if err != nil || msgType != messages.MsgTypeFeatures {
+		t.Errorf("expected features response, got %s, %v", msgType, err)
+	}
+}
+
+func TestRelay_DrainDisconnectsPeerBeingAccepted(t *testing.T) {
+	m, _ := metrics.NewMetrics(context.Background(), otel.Meter(""))
+	r := &Relay{store: NewStore()}
+
+	// an Accept that passed the draining check but has not added the peer yet
+	r.drainMu.RLock()
+	drained := make(chan struct{})
+	go func() {
+		r.drain(context.Background(), 0)
+		close(drained)
+	}()
+
+	conn, remote := net.Pipe()
+	defer remote.Close()
+	r.store.AddPeer(NewPeer(m, []byte("peer_one"), conn, r.store))
+	r.drainMu.RUnlock()
+
+	if _, err := io.ReadAll(remote); err != nil {
+		t.Fatalf("failed to read from peer connection: %s", err)
+	}
+	<-drained
+	if !r.draining.Load() {
+		t.Errorf("relay is not draining")
+	}
+}
@@ -1,14 +1,43 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	directoryTimeout = 2 * time.Second
+	// locationCacheTTL is the time a peer location is cached, after a peer moved to another relay instance the
+	// messages are dropped until the entry expires
+	locationCacheTTL = 5 * time.Second
+	// missingPeerCacheTTL is the time an unknown peer is cached, to not query the directory for every message
+	missingPeerCacheTTL = 2 * time.Second
+	// maxPendingLookups limits the directory lookups running in the background
+	maxPendingLookups = 64
 )
 
+type location struct {
+	instanceURL string
+	expires     time.Time
+}
+
 // Store is a thread-safe store of peers
-// It is used to store the peers that are connected to the relay server
+// It is used to store the peers that are connected to the relay server. In a relay cluster the store registers the
+// peers in the shared directory and locates the peers connected to the other instances.
 type Store struct {
 	peers     map[string]*Peer // consider to use [32]byte as key. The Peer(id string) would be faster
 	peersLock sync.RWMutex
+
+	directory   Directory
+	instanceURL string
+	locations   map[string]location
+	// lookups are the peers being looked up in the directory, protected by locationsMu
+	lookups     map[string]struct{}
+	locationsMu sync.Mutex
 }
 
 // NewStore creates a new Store instance
@@ -18,32 +47,44 @@ func NewStore() *Store {
 	}
 }
 
+// NewClusterStore creates a new Store instance that registers the peers in the directory with the given instance URL
+func NewClusterStore(directory Directory, instanceURL string) *Store {
+	return &Store{
+		peers:       make(map[string]*Peer),
+		directory:   directory,
+		instanceURL: instanceURL,
+		locations:   make(map[string]location),
+		lookups:     make(map[string]struct{}),
+	}
+}
+
 // AddPeer adds a peer to the store
 func (s *Store) AddPeer(peer *Peer) {
 	s.peersLock.Lock()
-	defer s.peersLock.Unlock()
 	odlPeer, ok := s.peers[peer.String()]
 	if ok {
 		odlPeer.Close()
 	}
 
 	s.peers[peer.String()] = peer
+	s.peersLock.Unlock()
+
+	s.register(peer.String())
 }
 
 // DeletePeer deletes a peer from the store
 func (s *Store) DeletePeer(peer *Peer) {
 	s.peersLock.Lock()
-	defer s.peersLock.Unlock()
-
 	dp, ok := s.peers[peer.String()]
-	if !ok {
-		return
-	}
-	if dp != peer {
+	if !ok || dp != peer {
+		s.peersLock.Unlock()
 		return
 	}
 
 	delete(s.peers, peer.String())
+	s.peersLock.Unlock()
+
+	s.unregister(peer.String())
 }
 
 // Peer returns a peer by its ID
@@ -66,3 +107,121 @@ func (s *Store) Peers() []*Peer {
 	}
 	return peers
 }
+
+// Locate returns the instance URL of the other cluster member the peer is connected to. It is called for every message
+// sent to a peer not connected to this instance, so it never waits for the directory: the locations are cached for a
+// short time and refreshed in the background. Until the first lookup of a peer completes, the peer is not located and
+// its messages are dropped.
+func (s *Store) Locate(id string) (string, bool) {
+	if s.directory == nil {
+		return "", false
+	}
+
+	s.locationsMu.Lock()
+	defer s.locationsMu.Unlock()
+
+	loc, ok := s.locations[id]
+	if !ok || time.Now().After(loc.expires) {
+		// the expired location is used until the lookup completes
+		s.startLookup(id)
+	}
+	return loc.instanceURL, loc.instanceURL != ""
+}
+
+// startLookup looks up the peer in the directory in the background unless a lookup is already running.
+// The caller must hold locationsMu.
+func (s *Store) startLookup(id string) {
+	if _, ok := s.lookups[id]; ok {
+		return
+	}
+	if len(s.lookups) >= maxPendingLookups {
+		log.Debugf("too many pending directory lookups, skip lookup of peer %s", id)
+		return
+	}
+
+	s.lookups[id] = struct{}{}
+	go s.lookup(id)
+}
+
+func (s *Store) lookup(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), directoryTimeout)
+	defer cancel()
+
+	var loc location
+	instanceURL, err := s.directory.Lookup(ctx, id)
+	switch {
+	case errors.Is(err, ErrPeerNotFound):
+		loc = location{expires: time.Now().Add(missingPeerCacheTTL)}
+	case err != nil:
+		log.Errorf("failed to lookup peer %s in directory: %s", id, err)
+		// keep the last known location and retry later
+		s.locationsMu.Lock()
+		loc = s.locations[id]
+		loc.expires = time.Now().Add(missingPeerCacheTTL)
+		s.locations[id] = loc
+		delete(s.lookups, id)
+		s.locationsMu.Unlock()
+		return
+	case instanceURL == s.instanceURL:
+		// the peer is registered here but is not connected (anymore), do not forward the message back to this instance
+		loc = location{expires: time.Now().Add(missingPeerCacheTTL)}
+	default:
+		loc = location{instanceURL: instanceURL, expires: time.Now().Add(locationCacheTTL)}
+	}
+
+	s.locationsMu.Lock()
+	s.locations[id] = loc
+	delete(s.lookups, id)
+	s.locationsMu.Unlock()
+}
+
+// refreshDirectory renews the directory records of all peers and drops the expired locations
+func (s *Store) refreshDirectory(ctx context.Context) error {
+	if s.directory == nil {
+		return nil
+	}
+
+	s.locationsMu.Lock()
+	now := time.Now()
+	for id, loc := range s.locations {
+		if now.After(loc.expires) {
+			delete(s.locations, id)
+		}
+	}
+	s.locationsMu.Unlock()
+
+	s.peersLock.RLock()
+	ids := make([]string, 0, len(s.peers))
+	for id := range s.peers {
+		ids = append(ids, id)
+	}
+	s.peersLock.RUnlock()
+
+	return s.directory.Register(ctx, s.instanceURL, ids...)
+}
+
+func (s *Store) register(id string) {
+	if s.directory == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), directoryTimeout)
+	defer cancel()
+
+	if err := s.directory.Register(ctx, s.instanceURL, id); err != nil {
+		log.Errorf("failed to register peer %s in directory: %s", id, err)
+	}
+}
+
+func (s *Store) unregister(id string) {
+	if s.directory == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), directoryTimeout)
+	defer cancel()
+
+	if err := s.directory.Unregister(ctx, s.instanceURL, id); err != nil {
+		log.Errorf("failed to unregister peer %s from directory: %s", id, err)
+	}
+}
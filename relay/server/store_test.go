@@ -83,3 +83,92 @@ func TestStore_DeleteDeprecatedPeer(t *testing.T) {
 		t.Errorf("second peer was deleted")
 	}
 }
+
+func TestStore_ClusterLocate(t *testing.T) {
+	directory := NewMemoryDirectory()
+	s1 := NewClusterStore(directory, "rel://relay1:80")
+	s2 := NewClusterStore(directory, "rel://relay2:80")
+
+	m, _ := metrics.NewMetrics(context.Background(), otel.Meter(""))
+
+	p := NewPeer(m, []byte("peer_id"), &mockConn{}, nil)
+	s2.AddPeer(p)
+
+	if _, ok := s1.Locate(p.String()); ok {
+		t.Errorf("peer must not be located before the lookup completes")
+	}
+
+	instanceURL, ok := locateEventually(s1, p.String())
+	if !ok || instanceURL != "rel://relay2:80" {
+		t.Errorf("expected peer on relay2, got %s, %t", instanceURL, ok)
+	}
+
+	if _, ok := locateEventually(s2, p.String()); ok {
+		t.Errorf("peer registered on the same instance must not be located")
+	}
+
+	if _, ok := locateEventually(s1, "unknown"); ok {
+		t.Errorf("unknown peer must not be located")
+	}
+
+	// the peer reconnected to relay1, the late unregister of relay2 must keep the new record
+	s1.AddPeer(NewPeer(m, []byte("peer_id"), &mockConn{}, nil))
+	s2.DeletePeer(p)
+	instanceURL, err := directory.Lookup(context.Background(), p.String())
+	if err != nil || instanceURL != "rel://relay1:80" {
+		t.Errorf("expected peer on relay1, got %s, %v", instanceURL, err)
+	}
+}
+
+type blockingDirectory struct {
+	*MemoryDirectory
+	release chan struct{}
+}
+
+func (d *blockingDirectory) Lookup(ctx context.Context, peerID string) (string, error) {
+	<-d.release
+	return d.MemoryDirectory.Lookup(ctx, peerID)
+}
+
+func TestStore_ClusterLocateDoesNotBlock(t *testing.T) {
+	directory := &blockingDirectory{MemoryDirectory: NewMemoryDirectory(), release: make(chan struct{})}
+	if err := directory.Register(context.Background(), "rel://relay2:80", "peer"); err != nil {
+		t.Fatalf("failed to register peer: %s", err)
+	}
+	s := NewClusterStore(directory, "rel://relay1:80")
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			s.Locate("peer")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("locate blocked on the directory")
+	}
+
+	close(directory.release)
+	instanceURL, ok := locateEventually(s, "peer")
+	if !ok || instanceURL != "rel://relay2:80" {
+		t.Errorf("expected peer on relay2, got %s, %t", instanceURL, ok)
+	}
+}
+
+// locateEventually waits for the background lookup of the peer and returns its location
+func locateEventually(s *Store, id string) (string, bool) {
+	s.Locate(id)
+	for i := 0; i < 100; i++ {
+		s.locationsMu.Lock()
+		_, pending := s.lookups[id]
+		s.locationsMu.Unlock()
+		if !pending {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return s.Locate(id)
+}
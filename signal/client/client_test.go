@@ -113,6 +113,47 @@ var _ = Describe("GrpcClient", func() {
 				Expect(featuresSupportedReceivedOnB).To(ContainElements([]uint32{DirectCheck}))
 			})
 		})
+
+		Context("with a peer connecting after the message was sent", func() {
+			It("should deliver the queued message", func() {
+				keyA, _ := wgtypes.GenerateKey()
+				clientA := createSignalClient(addr, keyA)
+				go func() {
+					_ = clientA.Receive(context.Background(), func(msg *sigProto.Message) error {
+						return nil
+					})
+				}()
+				clientA.WaitStreamConnected()
+
+				keyB, _ := wgtypes.GenerateKey()
+				err := clientA.Send(&sigProto.Message{
+					Key:       keyA.PublicKey().String(),
+					RemoteKey: keyB.PublicKey().String(),
+					Body:      &sigProto.Body{Payload: "offer"},
+				})
+				if err != nil {
+					Fail("failed sending a message to PeerB")
+				}
+
+				var msgReceived sync.WaitGroup
+				msgReceived.Add(1)
+				var payloadReceivedOnB string
+
+				clientB := createSignalClient(addr, keyB)
+				go func() {
+					_ = clientB.Receive(context.Background(), func(msg *sigProto.Message) error {
+						payloadReceivedOnB = msg.GetBody().GetPayload()
+						msgReceived.Done()
+						return nil
+					})
+				}()
+
+				if waitTimeout(&msgReceived, 3*time.Second) {
+					Fail("test timed out on waiting for the queued message")
+				}
+				Expect(payloadReceivedOnB).To(BeEquivalentTo("offer"))
+			})
+		})
 	})
 
 	Describe("Connecting to the Signal stream channel", func() {
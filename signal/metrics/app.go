@@ -21,6 +21,10 @@ type AppMetrics struct {
 	MessageForwardFailures metric.Int64Counter
 	MessageForwardLatency  metric.Float64Histogram
 
+	MessagesQueued          metric.Int64Counter
+	QueuedMessagesDelivered metric.Int64Counter
+	QueuedMessagesExpired   metric.Int64Counter
+
 	MessageSize metric.Int64Histogram
 }
 
@@ -99,6 +103,27 @@ func NewAppMetrics(meter metric.Meter) (*AppMetrics, error) {
 		return nil, err
 	}
 
+	messagesQueued, err := meter.Int64Counter("messages_queued_total",
+		metric.WithDescription("Total number of messages queued for peers not connected"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	queuedMessagesDelivered, err := meter.Int64Counter("queued_messages_delivered_total",
+		metric.WithDescription("Total number of queued messages delivered to peers on registration"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	queuedMessagesExpired, err := meter.Int64Counter("queued_messages_expired_total",
+		metric.WithDescription("Total number of queued messages dropped because they expired or the queue was full"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	messageSize, err := meter.Int64Histogram(
 		"message.size.bytes",
 		metric.WithUnit("bytes"),
@@ -125,6 +150,10 @@ func NewAppMetrics(meter metric.Meter) (*AppMetrics, error) {
 		MessageForwardFailures: messageForwardFailures,
 		MessageForwardLatency:  messageForwardLatency,
 
+		MessagesQueued:          messagesQueued,
+		QueuedMessagesDelivered: queuedMessagesDelivered,
+		QueuedMessagesExpired:   queuedMessagesExpired,
+
 		MessageSize: messageSize,
 	}, nil
 }
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/netbirdio/netbird/signal/metrics"
+	"github.com/netbirdio/netbird/signal/proto"
+)
+
+const (
+	// queueTTL is the time a message waits for the destination peer. It covers the reconnection of the peer's stream,
+	// after that the Guard of the sender retries anyway.
+	queueTTL = 10 * time.Second
+	// maxQueuedPerPeer is the number of messages queued for a peer, an offer comes with a couple of candidates
+	maxQueuedPerPeer = 64
+	// maxQueuedTotal limits the memory used by the queues of all peers
+	maxQueuedTotal = 50000
+
+	queueCleanupInterval = 5 * time.Second
+
+	labelReason         = "reason"
+	labelReasonTTL      = "ttl"
+	labelReasonOverflow = "overflow"
+
+	// testMessageRemoteKey is the destination of the test messages sent by netbird status, they are never delivered
+	testMessageRemoteKey = "dummy"
+)
+
+type queuedMessage struct {
+	msg     *proto.EncryptedMessage
+	expires time.Time
+}
+
+// messageQueue holds the messages of the peers that are not connected to the signal server, until they connect or
+// the messages expire. The queue of a peer is bounded, if it is full the oldest message is dropped.
+type messageQueue struct {
+	metrics *metrics.AppMetrics
+
+	mu     sync.Mutex
+	queues map[string][]queuedMessage
+	total  int
+}
+
+func newMessageQueue(metrics *metrics.AppMetrics) *messageQueue {
+	return &messageQueue{
+		metrics: metrics,
+		queues:  make(map[string][]queuedMessage),
+	}
+}
+
+// enqueue adds the message to the queue of the destination peer
+func (q *messageQueue) enqueue(ctx context.Context, msg *proto.EncryptedMessage) {
+	if msg.RemoteKey == testMessageRemoteKey {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.total >= maxQueuedTotal {
+		log.Debugf("message queue is full, drop message from peer [%s] to peer [%s]", msg.Key, msg.RemoteKey)
+		q.metrics.QueuedMessagesExpired.Add(ctx, 1, metric.WithAttributes(attribute.String(labelReason, labelReasonOverflow)))
+		return
+	}
+
+	queue := q.queues[msg.RemoteKey]
+	if len(queue) >= maxQueuedPerPeer {
+		queue = queue[1:]
+		q.total--
+		q.metrics.QueuedMessagesExpired.Add(ctx, 1, metric.WithAttributes(attribute.String(labelReason, labelReasonOverflow)))
+	}
+
+	q.queues[msg.RemoteKey] = append(queue, queuedMessage{msg: msg, expires: time.Now().Add(queueTTL)})
+	q.total++
+	q.metrics.MessagesQueued.Add(ctx, 1)
+	log.Debugf("queued message from peer [%s] to not connected peer [%s]", msg.Key, msg.RemoteKey)
+}
+
+// dequeue removes and returns the not expired messages of the peer in the order they were queued
+func (q *messageQueue) dequeue(ctx context.Context, peerID string) []*proto.EncryptedMessage {
+	q.mu.Lock()
+	queue, ok := q.queues[peerID]
+	if !ok {
+		q.mu.Unlock()
+		return nil
+	}
+	delete(q.queues, peerID)
+	q.total -= len(queue)
+	q.mu.Unlock()
+
+	now := time.Now()
+	msgs := make([]*proto.EncryptedMessage, 0, len(queue))
+	var expired int
+	for _, qm := range queue {
+		if now.After(qm.expires) {
+			expired++
+			continue
+		}
+		msgs = append(msgs, qm.msg)
+	}
+
+	if expired > 0 {
+		q.metrics.QueuedMessagesExpired.Add(ctx, int64(expired), metric.WithAttributes(attribute.String(labelReason, labelReasonTTL)))
+	}
+	return msgs
+}
+
+// startCleanupLoop drops the expired messages periodically until the context is done
+func (q *messageQueue) startCleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(queueCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.removeExpired(ctx)
+		}
+	}
+}
+
+func (q *messageQueue) removeExpired(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var expired int
+	for peerID, queue := range q.queues {
+		// the messages are queued in order, the expired ones are at the front
+		i := 0
+		for i < len(queue) && now.After(queue[i].expires) {
+			i++
+		}
+		if i == 0 {
+			continue
+		}
+
+		expired += i
+		if i == len(queue) {
+			delete(q.queues, peerID)
+		} else {
+			q.queues[peerID] = queue[i:]
+		}
+	}
+
+	if expired > 0 {
+		q.total -= expired
+		q.metrics.QueuedMessagesExpired.Add(ctx, int64(expired), metric.WithAttributes(attribute.String(labelReason, labelReasonTTL)))
+	}
+}
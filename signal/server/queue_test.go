@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/netbirdio/netbird/signal/metrics"
+	"github.com/netbirdio/netbird/signal/proto"
+)
+
+func newTestQueue(t *testing.T) *messageQueue {
+	t.Helper()
+	appMetrics, err := metrics.NewAppMetrics(otel.Meter(""))
+	require.NoError(t, err)
+	return newMessageQueue(appMetrics)
+}
+
+func newTestMessage(remoteKey string, body string) *proto.EncryptedMessage {
+	return &proto.EncryptedMessage{Key: "sender", RemoteKey: remoteKey, Body: []byte(body)}
+}
+
+func TestMessageQueue_Dequeue(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+
+	q.enqueue(ctx, newTestMessage("peer1", "first"))
+	q.enqueue(ctx, newTestMessage("peer1", "second"))
+	q.enqueue(ctx, newTestMessage("peer2", "other"))
+	q.enqueue(ctx, newTestMessage(testMessageRemoteKey, "status"))
+
+	msgs := q.dequeue(ctx, "peer1")
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "first", string(msgs[0].Body))
+	assert.Equal(t, "second", string(msgs[1].Body))
+
+	assert.Empty(t, q.dequeue(ctx, "peer1"), "messages should be delivered once")
+	assert.Empty(t, q.dequeue(ctx, testMessageRemoteKey), "test messages should not be queued")
+	assert.Equal(t, 1, q.total)
+}
+
+func TestMessageQueue_PeerLimit(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+
+	for i := 0; i < maxQueuedPerPeer+2; i++ {
+		q.enqueue(ctx, newTestMessage("peer1", fmt.Sprintf("msg%d", i)))
+	}
+
+	msgs := q.dequeue(ctx, "peer1")
+	require.Len(t, msgs, maxQueuedPerPeer)
+	assert.Equal(t, "msg2", string(msgs[0].Body), "the oldest messages should be dropped")
+	assert.Equal(t, 0, q.total)
+}
+
+func TestMessageQueue_Expiry(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+
+	q.enqueue(ctx, newTestMessage("peer1", "expired"))
+	q.enqueue(ctx, newTestMessage("peer2", "expired"))
+	q.queues["peer1"][0].expires = time.Now().Add(-time.Second)
+	q.queues["peer2"][0].expires = time.Now().Add(-time.Second)
+	q.enqueue(ctx, newTestMessage("peer1", "valid"))
+
+	msgs := q.dequeue(ctx, "peer1")
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "valid", string(msgs[0].Body))
+
+	q.removeExpired(ctx)
+	assert.Empty(t, q.queues)
+	assert.Equal(t, 0, q.total)
+}
+
+type recordingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	mu   sync.Mutex
+	msgs []*proto.EncryptedMessage
+}
+
+func (s *recordingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recordingStream) Send(msg *proto.EncryptedMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+func (s *recordingStream) Recv() (*proto.EncryptedMessage, error) {
+	<-s.ctx.Done()
+	return nil, io.EOF
+}
+
+func (s *recordingStream) received() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.msgs)
+}
+
+func TestServer_QueuesMessageOnce(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewServer(ctx, otel.Meter(""))
+	require.NoError(t, err)
+
+	connect := func() (*recordingStream, context.CancelFunc) {
+		streamCtx, cancel := context.WithCancel(metadata.NewIncomingContext(ctx, metadata.Pairs(proto.HeaderId, "peer")))
+		return &recordingStream{ctx: streamCtx}, cancel
+	}
+
+	// the message to a not connected peer is queued until it registers
+	_, err = s.Send(ctx, newTestMessage("peer", "offer"))
+	require.NoError(t, err)
+
+	stream, cancel := connect()
+	p, err := s.RegisterPeer(stream)
+	require.NoError(t, err)
+	s.deliverQueuedMessages(ctx, p)
+	assert.Equal(t, 1, stream.received(), "queued message should be delivered once")
+
+	// after the deregistration the messages go to the dispatcher listener until the stream is closed
+	s.DeregisterPeer(p)
+	_, err = s.Send(ctx, newTestMessage("peer", "dispatched"))
+	require.NoError(t, err)
+	assert.Empty(t, s.queue.dequeue(ctx, "peer"), "dispatched message should not be queued")
+
+	cancel()
+	require.Eventually(t, func() bool { return !s.hasListener("peer") }, time.Second, 10*time.Millisecond)
+
+	_, err = s.Send(ctx, newTestMessage("peer", "reconnecting"))
+	require.NoError(t, err)
+
+	stream, cancel = connect()
+	defer cancel()
+	p, err = s.RegisterPeer(stream)
+	require.NoError(t, err)
+	s.deliverQueuedMessages(ctx, p)
+	assert.Equal(t, 1, stream.received(), "message should be delivered once after the reconnection")
+}
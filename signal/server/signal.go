@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/netbirdio/signal-dispatcher/dispatcher"
@@ -44,6 +45,12 @@ type Server struct {
 	proto.UnimplementedSignalExchangeServer
 	dispatcher *dispatcher.Dispatcher
 	metrics    *metrics.AppMetrics
+	queue      *messageQueue
+
+	// listeners counts the dispatcher listeners of the peers per peer ID. A listener lives until the stream context of
+	// the peer is done, so it can outlive the registration of the peer for a moment.
+	listeners   map[string]int
+	listenersMu sync.Mutex
 }
 
 // NewServer creates a new Signal server
@@ -62,7 +69,10 @@ func NewServer(ctx context.Context, meter metric.Meter) (*Server, error) {
 		dispatcher: d,
 		registry:   peer.NewRegistry(appMetrics),
 		metrics:    appMetrics,
+		queue:      newMessageQueue(appMetrics),
+		listeners:  make(map[string]int),
 	}
+	go s.queue.startCleanupLoop(ctx)
 
 	return s, nil
 }
@@ -76,7 +86,7 @@ func (s *Server) Send(ctx context.Context, msg *proto.EncryptedMessage) (*proto.
 		return &proto.EncryptedMessage{}, nil
 	}
 
-	return s.dispatcher.SendMessage(ctx, msg)
+	return s.routeMessage(ctx, msg)
 }
 
 // ConnectStream connects to the exchange stream
@@ -97,6 +107,7 @@ func (s *Server) ConnectStream(stream proto.SignalExchange_ConnectStreamServer)
 	}
 
 	log.Debugf("peer connected [%s] [streamID %d] ", p.Id, p.StreamID)
+	s.deliverQueuedMessages(stream.Context(), p)
 
 	for {
 		select {
@@ -114,7 +125,7 @@ func (s *Server) ConnectStream(stream proto.SignalExchange_ConnectStreamServer)
 
 			log.Debugf("Received a response from peer [%s] to peer [%s]", msg.Key, msg.RemoteKey)
 
-			_, err = s.dispatcher.SendMessage(stream.Context(), msg)
+			_, err = s.routeMessage(stream.Context(), msg)
 			if err != nil {
 				log.Debugf("error while sending message from peer [%s] to peer [%s] %v", msg.Key, msg.RemoteKey, err)
 			}
@@ -139,6 +150,7 @@ func (s *Server) RegisterPeer(stream proto.SignalExchange_ConnectStreamServer) (
 	p := peer.NewPeer(id[0], stream)
 	s.registry.Register(p)
 	s.dispatcher.ListenForMessages(stream.Context(), p.Id, s.forwardMessageToPeer)
+	s.trackListener(stream.Context(), p.Id)
 	return p, nil
 }
 
@@ -159,23 +171,89 @@ func (s *Server) forwardMessageToPeer(ctx context.Context, msg *proto.EncryptedM
 		s.metrics.GetRegistrationDelay.Record(ctx, float64(time.Since(getRegistrationStart).Nanoseconds())/1e6, metric.WithAttributes(attribute.String(labelType, labelTypeStream), attribute.String(labelRegistrationStatus, labelRegistrationNotFound)))
 		s.metrics.MessageForwardFailures.Add(ctx, 1, metric.WithAttributes(attribute.String(labelType, labelTypeNotConnected)))
 		log.Debugf("message from peer [%s] can't be forwarded to peer [%s] because destination peer is not connected", msg.Key, msg.RemoteKey)
-		// todo respond to the sender?
 		return
 	}
 
 	s.metrics.GetRegistrationDelay.Record(ctx, float64(time.Since(getRegistrationStart).Nanoseconds())/1e6, metric.WithAttributes(attribute.String(labelType, labelTypeStream), attribute.String(labelRegistrationStatus, labelRegistrationFound)))
+
+	// todo respond to the sender in case of error?
+	_ = s.sendToPeer(ctx, dstPeer, msg)
+}
+
+// routeMessage sends the message through the dispatcher. The messages of the peers without a registration and a
+// dispatcher listener are queued instead, so every message is either dispatched or queued.
+func (s *Server) routeMessage(ctx context.Context, msg *proto.EncryptedMessage) (*proto.EncryptedMessage, error) {
+	if !s.registry.IsPeerRegistered(msg.RemoteKey) && !s.hasListener(msg.RemoteKey) {
+		// the peer could be reconnecting its stream, the message is delivered once it registers here
+		s.queueMessage(ctx, msg)
+		return &proto.EncryptedMessage{}, nil
+	}
+
+	return s.dispatcher.SendMessage(ctx, msg)
+}
+
+// trackListener counts the dispatcher listener of the peer until the stream context is done, like the dispatcher does
+func (s *Server) trackListener(ctx context.Context, peerID string) {
+	s.listenersMu.Lock()
+	s.listeners[peerID]++
+	s.listenersMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.listenersMu.Lock()
+		defer s.listenersMu.Unlock()
+		s.listeners[peerID]--
+		if s.listeners[peerID] <= 0 {
+			delete(s.listeners, peerID)
+		}
+	}()
+}
+
+func (s *Server) hasListener(peerID string) bool {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	return s.listeners[peerID] > 0
+}
+
+// queueMessage queues the message for the not connected destination peer. The message is not dispatched, so if the
+// peer registered in the meantime, the queued messages are delivered right away.
+func (s *Server) queueMessage(ctx context.Context, msg *proto.EncryptedMessage) {
+	s.queue.enqueue(ctx, msg)
+	if p, found := s.registry.Get(msg.RemoteKey); found {
+		s.deliverQueuedMessages(ctx, p)
+	}
+}
+
+// deliverQueuedMessages sends the messages queued while the peer was not connected
+func (s *Server) deliverQueuedMessages(ctx context.Context, p *peer.Peer) {
+	msgs := s.queue.dequeue(ctx, p.Id)
+	if len(msgs) == 0 {
+		return
+	}
+
+	log.Debugf("delivering %d queued messages to peer [%s]", len(msgs), p.Id)
+	for _, msg := range msgs {
+		if err := s.sendToPeer(ctx, p, msg); err != nil {
+			return
+		}
+		s.metrics.QueuedMessagesDelivered.Add(ctx, 1)
+	}
+}
+
+func (s *Server) sendToPeer(ctx context.Context, dstPeer *peer.Peer, msg *proto.EncryptedMessage) error {
 	start := time.Now()
 
 	// forward the message to the target peer
 	if err := dstPeer.Stream.Send(msg); err != nil {
 		log.Warnf("error while forwarding message from peer [%s] to peer [%s] %v", msg.Key, msg.RemoteKey, err)
-		// todo respond to the sender?
 		s.metrics.MessageForwardFailures.Add(ctx, 1, metric.WithAttributes(attribute.String(labelType, labelTypeError)))
-		return
+		return err
 	}
 
 	// in milliseconds
 	s.metrics.MessageForwardLatency.Record(ctx, float64(time.Since(start).Nanoseconds())/1e6, metric.WithAttributes(attribute.String(labelType, labelTypeStream)))
 	s.metrics.MessagesForwarded.Add(ctx, 1)
 	s.metrics.MessageSize.Record(ctx, int64(gproto.Size(msg)), metric.WithAttributes(attribute.String(labelType, labelTypeMessage)))
+	return nil
 }
@@ -58,6 +58,30 @@ func Backoff(ctx context.Context) backoff.BackOff {
 }
 
 func CreateConnection(addr string, tlsEnabled bool) (*grpc.ClientConn, error) {
+	connCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(connCtx, addr, append(dialOptions(tlsEnabled), grpc.WithBlock())...)
+	if err != nil {
+		log.Printf("DialContext error: %v", err)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// CreateLazyConnection returns a connection without waiting for the server to be reachable.
+// gRPC keeps reconnecting in the background, calls fail until the connection is ready.
+func CreateLazyConnection(addr string, tlsEnabled bool) (*grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(context.Background(), addr, dialOptions(tlsEnabled)...)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+
+	return conn, nil
+}
+
+func dialOptions(tlsEnabled bool) []grpc.DialOption {
 	transportOption := grpc.WithTransportCredentials(insecure.NewCredentials())
 	if tlsEnabled {
 		certPool, err := x509.SystemCertPool()
@@ -71,24 +95,12 @@ func CreateConnection(addr string, tlsEnabled bool) (*grpc.ClientConn, error) {
 		}))
 	}
 
-	connCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	conn, err := grpc.DialContext(
-		connCtx,
-		addr,
+	return []grpc.DialOption{
 		transportOption,
 		WithCustomDialer(),
-		grpc.WithBlock(),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:    30 * time.Second,
 			Timeout: 10 * time.Second,
 		}),
-	)
-	if err != nil {
-		log.Printf("DialContext error: %v", err)
-		return nil, err
 	}
-
-	return conn, nil
 }